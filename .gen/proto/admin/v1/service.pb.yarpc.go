@@ -72,6 +72,10 @@ type AdminAPIYARPCClient interface {
 	ListDynamicConfig(context.Context, *ListDynamicConfigRequest, ...yarpc.CallOption) (*ListDynamicConfigResponse, error)
 	DeleteWorkflow(context.Context, *AdminDeleteWorkflowRequest, ...yarpc.CallOption) (*AdminDeleteWorkflowResponse, error)
 	MaintainCorruptWorkflow(context.Context, *AdminMaintainWorkflowRequest, ...yarpc.CallOption) (*AdminMaintainWorkflowResponse, error)
+	ForceUpdateDLQAckLevel(context.Context, *ForceUpdateDLQAckLevelRequest, ...yarpc.CallOption) (*ForceUpdateDLQAckLevelResponse, error)
+	PauseDLQProcessing(context.Context, *PauseDLQProcessingRequest, ...yarpc.CallOption) (*PauseDLQProcessingResponse, error)
+	ResumeDLQProcessing(context.Context, *ResumeDLQProcessingRequest, ...yarpc.CallOption) (*ResumeDLQProcessingResponse, error)
+	ListDLQConsumers(context.Context, *ListDLQConsumersRequest, ...yarpc.CallOption) (*ListDLQConsumersResponse, error)
 }
 
 func newAdminAPIYARPCClient(clientConfig transport.ClientConfig, anyResolver jsonpb.AnyResolver, options ...protobuf.ClientOption) AdminAPIYARPCClient {
@@ -120,6 +124,10 @@ type AdminAPIYARPCServer interface {
 	ListDynamicConfig(context.Context, *ListDynamicConfigRequest) (*ListDynamicConfigResponse, error)
 	DeleteWorkflow(context.Context, *AdminDeleteWorkflowRequest) (*AdminDeleteWorkflowResponse, error)
 	MaintainCorruptWorkflow(context.Context, *AdminMaintainWorkflowRequest) (*AdminMaintainWorkflowResponse, error)
+	ForceUpdateDLQAckLevel(context.Context, *ForceUpdateDLQAckLevelRequest) (*ForceUpdateDLQAckLevelResponse, error)
+	PauseDLQProcessing(context.Context, *PauseDLQProcessingRequest) (*PauseDLQProcessingResponse, error)
+	ResumeDLQProcessing(context.Context, *ResumeDLQProcessingRequest) (*ResumeDLQProcessingResponse, error)
+	ListDLQConsumers(context.Context, *ListDLQConsumersRequest) (*ListDLQConsumersResponse, error)
 }
 
 type buildAdminAPIYARPCProceduresParams struct {
@@ -413,6 +421,46 @@ func buildAdminAPIYARPCProcedures(params buildAdminAPIYARPCProceduresParams) []t
 						},
 					),
 				},
+				{
+					MethodName: "ForceUpdateDLQAckLevel",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:      handler.ForceUpdateDLQAckLevel,
+							NewRequest:  newAdminAPIServiceForceUpdateDLQAckLevelYARPCRequest,
+							AnyResolver: params.AnyResolver,
+						},
+					),
+				},
+				{
+					MethodName: "PauseDLQProcessing",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:      handler.PauseDLQProcessing,
+							NewRequest:  newAdminAPIServicePauseDLQProcessingYARPCRequest,
+							AnyResolver: params.AnyResolver,
+						},
+					),
+				},
+				{
+					MethodName: "ResumeDLQProcessing",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:      handler.ResumeDLQProcessing,
+							NewRequest:  newAdminAPIServiceResumeDLQProcessingYARPCRequest,
+							AnyResolver: params.AnyResolver,
+						},
+					),
+				},
+				{
+					MethodName: "ListDLQConsumers",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:      handler.ListDLQConsumers,
+							NewRequest:  newAdminAPIServiceListDLQConsumersYARPCRequest,
+							AnyResolver: params.AnyResolver,
+						},
+					),
+				},
 			},
 			OnewayHandlerParams: []protobuf.BuildProceduresOnewayHandlerParams{},
 			StreamHandlerParams: []protobuf.BuildProceduresStreamHandlerParams{},
@@ -861,6 +909,54 @@ func (c *_AdminAPIYARPCCaller) MaintainCorruptWorkflow(ctx context.Context, requ
 	return response, err
 }
 
+func (c *_AdminAPIYARPCCaller) ForceUpdateDLQAckLevel(ctx context.Context, request *ForceUpdateDLQAckLevelRequest, options ...yarpc.CallOption) (*ForceUpdateDLQAckLevelResponse, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "ForceUpdateDLQAckLevel", request, newAdminAPIServiceForceUpdateDLQAckLevelYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*ForceUpdateDLQAckLevelResponse)
+	if !ok {
+		return nil, protobuf.CastError(emptyAdminAPIServiceForceUpdateDLQAckLevelYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+func (c *_AdminAPIYARPCCaller) PauseDLQProcessing(ctx context.Context, request *PauseDLQProcessingRequest, options ...yarpc.CallOption) (*PauseDLQProcessingResponse, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "PauseDLQProcessing", request, newAdminAPIServicePauseDLQProcessingYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*PauseDLQProcessingResponse)
+	if !ok {
+		return nil, protobuf.CastError(emptyAdminAPIServicePauseDLQProcessingYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+func (c *_AdminAPIYARPCCaller) ResumeDLQProcessing(ctx context.Context, request *ResumeDLQProcessingRequest, options ...yarpc.CallOption) (*ResumeDLQProcessingResponse, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "ResumeDLQProcessing", request, newAdminAPIServiceResumeDLQProcessingYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*ResumeDLQProcessingResponse)
+	if !ok {
+		return nil, protobuf.CastError(emptyAdminAPIServiceResumeDLQProcessingYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+func (c *_AdminAPIYARPCCaller) ListDLQConsumers(ctx context.Context, request *ListDLQConsumersRequest, options ...yarpc.CallOption) (*ListDLQConsumersResponse, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "ListDLQConsumers", request, newAdminAPIServiceListDLQConsumersYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*ListDLQConsumersResponse)
+	if !ok {
+		return nil, protobuf.CastError(emptyAdminAPIServiceListDLQConsumersYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
 type _AdminAPIYARPCHandler struct {
 	server AdminAPIYARPCServer
 }
@@ -1313,6 +1409,70 @@ func (h *_AdminAPIYARPCHandler) MaintainCorruptWorkflow(ctx context.Context, req
 	return response, err
 }
 
+func (h *_AdminAPIYARPCHandler) ForceUpdateDLQAckLevel(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *ForceUpdateDLQAckLevelRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*ForceUpdateDLQAckLevelRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyAdminAPIServiceForceUpdateDLQAckLevelYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.ForceUpdateDLQAckLevel(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func (h *_AdminAPIYARPCHandler) PauseDLQProcessing(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *PauseDLQProcessingRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*PauseDLQProcessingRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyAdminAPIServicePauseDLQProcessingYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.PauseDLQProcessing(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func (h *_AdminAPIYARPCHandler) ResumeDLQProcessing(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *ResumeDLQProcessingRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*ResumeDLQProcessingRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyAdminAPIServiceResumeDLQProcessingYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.ResumeDLQProcessing(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func (h *_AdminAPIYARPCHandler) ListDLQConsumers(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *ListDLQConsumersRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*ListDLQConsumersRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyAdminAPIServiceListDLQConsumersYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.ListDLQConsumers(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
 func newAdminAPIServiceDescribeWorkflowExecutionYARPCRequest() proto.Message {
 	return &DescribeWorkflowExecutionRequest{}
 }
@@ -1537,6 +1697,38 @@ func newAdminAPIServiceMaintainCorruptWorkflowYARPCResponse() proto.Message {
 	return &AdminMaintainWorkflowResponse{}
 }
 
+func newAdminAPIServiceForceUpdateDLQAckLevelYARPCRequest() proto.Message {
+	return &ForceUpdateDLQAckLevelRequest{}
+}
+
+func newAdminAPIServiceForceUpdateDLQAckLevelYARPCResponse() proto.Message {
+	return &ForceUpdateDLQAckLevelResponse{}
+}
+
+func newAdminAPIServicePauseDLQProcessingYARPCRequest() proto.Message {
+	return &PauseDLQProcessingRequest{}
+}
+
+func newAdminAPIServicePauseDLQProcessingYARPCResponse() proto.Message {
+	return &PauseDLQProcessingResponse{}
+}
+
+func newAdminAPIServiceResumeDLQProcessingYARPCRequest() proto.Message {
+	return &ResumeDLQProcessingRequest{}
+}
+
+func newAdminAPIServiceResumeDLQProcessingYARPCResponse() proto.Message {
+	return &ResumeDLQProcessingResponse{}
+}
+
+func newAdminAPIServiceListDLQConsumersYARPCRequest() proto.Message {
+	return &ListDLQConsumersRequest{}
+}
+
+func newAdminAPIServiceListDLQConsumersYARPCResponse() proto.Message {
+	return &ListDLQConsumersResponse{}
+}
+
 var (
 	emptyAdminAPIServiceDescribeWorkflowExecutionYARPCRequest          = &DescribeWorkflowExecutionRequest{}
 	emptyAdminAPIServiceDescribeWorkflowExecutionYARPCResponse         = &DescribeWorkflowExecutionResponse{}
@@ -1594,194 +1786,219 @@ var (
 	emptyAdminAPIServiceDeleteWorkflowYARPCResponse                    = &AdminDeleteWorkflowResponse{}
 	emptyAdminAPIServiceMaintainCorruptWorkflowYARPCRequest            = &AdminMaintainWorkflowRequest{}
 	emptyAdminAPIServiceMaintainCorruptWorkflowYARPCResponse           = &AdminMaintainWorkflowResponse{}
+	emptyAdminAPIServiceForceUpdateDLQAckLevelYARPCRequest             = &ForceUpdateDLQAckLevelRequest{}
+	emptyAdminAPIServiceForceUpdateDLQAckLevelYARPCResponse            = &ForceUpdateDLQAckLevelResponse{}
+	emptyAdminAPIServicePauseDLQProcessingYARPCRequest                 = &PauseDLQProcessingRequest{}
+	emptyAdminAPIServicePauseDLQProcessingYARPCResponse                = &PauseDLQProcessingResponse{}
+	emptyAdminAPIServiceResumeDLQProcessingYARPCRequest                = &ResumeDLQProcessingRequest{}
+	emptyAdminAPIServiceResumeDLQProcessingYARPCResponse               = &ResumeDLQProcessingResponse{}
+	emptyAdminAPIServiceListDLQConsumersYARPCRequest                   = &ListDLQConsumersRequest{}
+	emptyAdminAPIServiceListDLQConsumersYARPCResponse                  = &ListDLQConsumersResponse{}
 )
 
 var yarpcFileDescriptorClosurec6fc96d64a8b67fd = [][]byte{
 	// uber/cadence/admin/v1/service.proto
 	[]byte{
-		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x1b, 0x4d, 0x6f, 0x1b, 0xc7,
-		0x35, 0x4b, 0x4a, 0xb2, 0xf4, 0x68, 0xc9, 0xd2, 0x58, 0x16, 0xa9, 0x95, 0xed, 0xc8, 0x9b, 0x38,
-		0x56, 0xbe, 0xa8, 0x98, 0x4a, 0xd2, 0x24, 0x46, 0x9a, 0xc8, 0x94, 0x2d, 0x2b, 0xb1, 0x63, 0x7b,
-		0xed, 0x38, 0x45, 0x51, 0x74, 0xbb, 0xe4, 0x0e, 0xa5, 0xad, 0xc8, 0x5d, 0x7a, 0x67, 0x48, 0x87,
-		0x41, 0xd0, 0x06, 0x85, 0x7b, 0xea, 0x37, 0x7a, 0xe8, 0xb1, 0x87, 0x16, 0x39, 0xb4, 0x87, 0xa2,
-		0xf7, 0x9e, 0x7b, 0x6e, 0x7f, 0x42, 0x2f, 0xb9, 0x04, 0x28, 0x50, 0xf4, 0xd2, 0x63, 0x31, 0x1f,
-		0x4b, 0xee, 0xd7, 0xf0, 0x43, 0x75, 0xa1, 0x20, 0x37, 0xee, 0xcc, 0xfb, 0x9e, 0x37, 0xef, 0xbd,
-		0x79, 0x33, 0x84, 0x67, 0x3a, 0x35, 0x1c, 0x6c, 0xd6, 0x6d, 0x07, 0x7b, 0x75, 0xbc, 0x69, 0x3b,
-		0x2d, 0xd7, 0xdb, 0xec, 0x5e, 0xde, 0x24, 0x38, 0xe8, 0xba, 0x75, 0x5c, 0x6e, 0x07, 0x3e, 0xf5,
-		0xd1, 0x19, 0x06, 0x54, 0x96, 0x40, 0x65, 0x0e, 0x54, 0xee, 0x5e, 0xd6, 0x9f, 0xde, 0xf7, 0xfd,
-		0xfd, 0x26, 0xde, 0xe4, 0x40, 0xb5, 0x4e, 0x63, 0x93, 0xba, 0x2d, 0x4c, 0xa8, 0xdd, 0x6a, 0x0b,
-		0x3c, 0xfd, 0x7c, 0x12, 0xe0, 0x51, 0x60, 0xb7, 0xdb, 0x38, 0x20, 0x72, 0x7e, 0x3d, 0xce, 0xbc,
-		0xed, 0x32, 0xd6, 0x75, 0xbf, 0xd5, 0xf2, 0x3d, 0x09, 0xf1, 0x6c, 0x16, 0x44, 0xd7, 0x25, 0x6e,
-		0xcd, 0x6d, 0xba, 0xb4, 0x97, 0x09, 0x45, 0x0e, 0xec, 0x00, 0x3b, 0x9c, 0x54, 0xb3, 0x43, 0x28,
-		0x0e, 0x46, 0x40, 0x1d, 0xb8, 0x84, 0xfa, 0x41, 0x48, 0xcb, 0x50, 0x40, 0x3d, 0xec, 0xe0, 0x8e,
-		0xb4, 0x87, 0xbe, 0xa1, 0x80, 0x09, 0x70, 0xbb, 0xe9, 0xd6, 0x6d, 0xea, 0x86, 0xf2, 0x1b, 0xbf,
-		0xd2, 0x60, 0x7d, 0x07, 0x93, 0x7a, 0xe0, 0xd6, 0xf0, 0x47, 0x7e, 0x70, 0xd8, 0x68, 0xfa, 0x8f,
-		0xae, 0x7d, 0x8c, 0xeb, 0x1d, 0x06, 0x63, 0xe2, 0x87, 0x1d, 0x4c, 0x28, 0x5a, 0x81, 0x19, 0xc7,
-		0x6f, 0xd9, 0xae, 0x57, 0xd2, 0xd6, 0xb5, 0x8d, 0x39, 0x53, 0x7e, 0xa1, 0x0f, 0x01, 0x3d, 0x92,
-		0x38, 0x16, 0x0e, 0x91, 0x4a, 0xb9, 0x75, 0x6d, 0xa3, 0x50, 0x79, 0xae, 0x1c, 0x5f, 0x93, 0xb6,
-		0x5b, 0xee, 0x5e, 0x2e, 0xa7, 0x59, 0x2c, 0x3d, 0x4a, 0x0e, 0x19, 0x7f, 0xd7, 0xe0, 0xc2, 0x10,
-		0x99, 0x48, 0xdb, 0xf7, 0x08, 0x46, 0xab, 0x30, 0xcb, 0x14, 0x73, 0x2c, 0xd7, 0xe1, 0x62, 0x4d,
-		0x9b, 0x27, 0xf8, 0xf7, 0x9e, 0x83, 0x2e, 0xc0, 0x49, 0x69, 0x33, 0xcb, 0x76, 0x9c, 0x80, 0x4b,
-		0x34, 0x67, 0x16, 0xe4, 0xd8, 0xb6, 0xe3, 0x04, 0x68, 0x0b, 0x56, 0x5a, 0x1d, 0x6a, 0xd7, 0x9a,
-		0xd8, 0x22, 0xd4, 0xa6, 0xd8, 0x72, 0x3d, 0xab, 0x6e, 0xd7, 0x0f, 0x70, 0x29, 0xcf, 0x81, 0x4f,
-		0xcb, 0xd9, 0x7b, 0x6c, 0x72, 0xcf, 0xab, 0xb2, 0x29, 0xf4, 0x26, 0xac, 0xa6, 0x90, 0x1c, 0x9b,
-		0xda, 0x35, 0x9b, 0xe0, 0xd2, 0x14, 0xc7, 0x5b, 0x89, 0xe3, 0xed, 0xc8, 0x59, 0xe3, 0xaf, 0x1a,
-		0xe8, 0xa1, 0x4e, 0x37, 0x84, 0x1c, 0x37, 0x7c, 0x42, 0x43, 0x0b, 0x3f, 0x03, 0x27, 0x0f, 0x7c,
-		0x42, 0xb9, 0xb8, 0x98, 0x10, 0x61, 0xe7, 0x1b, 0x4f, 0x99, 0x05, 0x36, 0xba, 0x2d, 0x06, 0xd1,
-		0x5a, 0x44, 0x63, 0xa6, 0xd2, 0xf4, 0x8d, 0xa7, 0x06, 0x3a, 0x7f, 0x94, 0xb9, 0x16, 0xf9, 0x49,
-		0xd6, 0xe2, 0xc6, 0x53, 0x19, 0xab, 0x71, 0x75, 0x1e, 0x0a, 0x8e, 0x14, 0xdc, 0xaa, 0xf5, 0x8c,
-		0x6f, 0x0d, 0xfc, 0xe5, 0x1e, 0x63, 0xbd, 0xe3, 0x12, 0x1a, 0xb8, 0xb5, 0x98, 0xbf, 0xac, 0xc1,
-		0x5c, 0xdb, 0xde, 0xc7, 0x16, 0x71, 0x3f, 0xc1, 0x72, 0x6d, 0x66, 0xd9, 0xc0, 0x3d, 0xf7, 0x13,
-		0x8c, 0x8a, 0x70, 0x82, 0x4f, 0x86, 0x4a, 0x98, 0x33, 0xec, 0x73, 0xcf, 0x31, 0xbe, 0x88, 0x2c,
-		0x7b, 0x06, 0x69, 0xb9, 0xec, 0x1b, 0xb0, 0xe8, 0x75, 0x5a, 0x35, 0x1c, 0x58, 0x7e, 0xc3, 0xe2,
-		0xca, 0x13, 0xc9, 0x62, 0x41, 0x8c, 0xdf, 0x6e, 0x70, 0x64, 0x82, 0xbe, 0x03, 0x33, 0x72, 0x3e,
-		0xb7, 0x9e, 0xdf, 0x28, 0x54, 0x76, 0xca, 0x99, 0x51, 0xa2, 0x3c, 0x92, 0x67, 0x59, 0x10, 0xbc,
-		0xe6, 0xd1, 0xa0, 0x67, 0x4a, 0x9a, 0xfa, 0x9b, 0x50, 0x88, 0x0c, 0xa3, 0x45, 0xc8, 0x1f, 0xe2,
-		0x9e, 0x94, 0x84, 0xfd, 0x44, 0xcb, 0x30, 0xdd, 0xb5, 0x9b, 0x1d, 0x2c, 0xbd, 0x4f, 0x7c, 0xbc,
-		0x95, 0x7b, 0x43, 0x33, 0x7e, 0x94, 0x83, 0xb5, 0x4c, 0x5f, 0x98, 0x58, 0xc5, 0x35, 0x98, 0x0b,
-		0x3d, 0x42, 0x68, 0x39, 0x6d, 0xce, 0x4a, 0x87, 0x20, 0xe8, 0x3d, 0x38, 0x29, 0xf6, 0x69, 0xc4,
-		0xb1, 0x0b, 0x95, 0x4b, 0x71, 0x2b, 0x88, 0xd8, 0xc0, 0xcd, 0xc0, 0x61, 0xb9, 0xa3, 0xef, 0x79,
-		0x0d, 0xdf, 0x2c, 0x38, 0x83, 0x01, 0xf4, 0x3a, 0x14, 0x05, 0xa3, 0xba, 0xef, 0xd1, 0xc0, 0x6f,
-		0x36, 0x71, 0xc0, 0xb7, 0x40, 0x87, 0x48, 0xbf, 0x3f, 0xc3, 0xa7, 0xab, 0xfd, 0xd9, 0x7b, 0x7c,
-		0x12, 0x95, 0xe0, 0x44, 0xe8, 0xd2, 0xd3, 0x1c, 0x2e, 0xfc, 0x34, 0xca, 0xb0, 0x54, 0x6d, 0xfa,
-		0x44, 0x58, 0x3d, 0x74, 0x1c, 0xf5, 0x9e, 0x36, 0x96, 0x01, 0x45, 0xe1, 0x85, 0xa9, 0x8c, 0x7f,
-		0x6a, 0xb0, 0x64, 0xe2, 0x96, 0xdf, 0xc5, 0xf7, 0x6d, 0x72, 0x38, 0x9a, 0x0c, 0x7a, 0x1b, 0xe6,
-		0xa8, 0x4d, 0x0e, 0x2d, 0xda, 0x6b, 0x8b, 0x95, 0x59, 0xa8, 0xac, 0xab, 0x2c, 0xc2, 0x48, 0xde,
-		0xef, 0xb5, 0xb1, 0x39, 0x4b, 0xe5, 0x2f, 0xe6, 0xbc, 0x1c, 0xdd, 0x75, 0xb8, 0x39, 0xf3, 0xe6,
-		0x0c, 0xfb, 0xdc, 0x73, 0x50, 0x15, 0x4e, 0x0d, 0xa2, 0xbe, 0xc5, 0xf2, 0x0c, 0x37, 0x4c, 0xa1,
-		0xa2, 0x97, 0x45, 0x8e, 0x29, 0x87, 0x39, 0xa6, 0x7c, 0x3f, 0x4c, 0x42, 0xe6, 0xc2, 0x00, 0x85,
-		0x0d, 0xb2, 0xb8, 0x25, 0x33, 0x82, 0xe5, 0xd9, 0x2d, 0x2c, 0x4d, 0x56, 0x90, 0x63, 0x1f, 0xd8,
-		0x2d, 0xcc, 0xcc, 0x10, 0xd5, 0x57, 0x9a, 0xe1, 0x97, 0xdc, 0x0c, 0x04, 0xd3, 0xbb, 0x2c, 0x09,
-		0x8c, 0x61, 0x86, 0x24, 0xa7, 0x5c, 0x8a, 0x53, 0xdc, 0x52, 0xf9, 0x49, 0x2d, 0x25, 0x04, 0x1d,
-		0x48, 0x24, 0x05, 0xfd, 0xb5, 0x06, 0xcb, 0xa1, 0xeb, 0x7f, 0x75, 0x64, 0xbd, 0x0d, 0x67, 0x12,
-		0x42, 0xc9, 0x9d, 0xf8, 0x3a, 0x14, 0xdb, 0x81, 0x5f, 0xc7, 0x84, 0xb8, 0xde, 0xbe, 0xc5, 0x33,
-		0xac, 0x88, 0xfc, 0x6c, 0x43, 0xe6, 0x99, 0xdb, 0x0f, 0xa6, 0x39, 0x26, 0x0f, 0xfb, 0xc4, 0xf8,
-		0x77, 0x0e, 0x2e, 0xed, 0x62, 0x9a, 0x4e, 0x5e, 0xf6, 0x23, 0xb9, 0xe1, 0x1f, 0x54, 0x8e, 0x27,
-		0xb9, 0xa2, 0xf7, 0xa1, 0x40, 0xa8, 0x1d, 0x50, 0x0b, 0x77, 0xb1, 0x47, 0x65, 0x50, 0x78, 0x41,
-		0x65, 0xac, 0x07, 0x38, 0x20, 0x2c, 0x33, 0x08, 0xa1, 0xf7, 0x28, 0x6e, 0x99, 0xc0, 0xd1, 0xaf,
-		0x31, 0x6c, 0xb4, 0x0b, 0x73, 0xd8, 0x73, 0x24, 0xa9, 0xa9, 0x89, 0x49, 0xcd, 0x62, 0xcf, 0x11,
-		0x84, 0x62, 0x19, 0x63, 0x3a, 0x91, 0x31, 0x9e, 0x83, 0x53, 0x1e, 0xfe, 0x98, 0x5a, 0x1c, 0x82,
-		0xfa, 0x87, 0xd8, 0x2b, 0xcd, 0xac, 0x6b, 0x1b, 0x27, 0xcd, 0x79, 0x36, 0x7c, 0xc7, 0xde, 0xc7,
-		0xf7, 0xd9, 0xa0, 0xf1, 0xa5, 0x06, 0x1b, 0xa3, 0xad, 0x2e, 0x97, 0x36, 0x83, 0xa8, 0x96, 0x41,
-		0x14, 0x5d, 0x87, 0x53, 0x61, 0x2d, 0x51, 0xb3, 0x69, 0xfd, 0x00, 0x87, 0xe9, 0xe4, 0x5c, 0xe6,
-		0x1a, 0xb0, 0x84, 0x7f, 0xb5, 0xe9, 0xd7, 0xcc, 0x05, 0x89, 0x75, 0x55, 0x20, 0xa1, 0xdb, 0x70,
-		0xaa, 0x2b, 0x2c, 0x60, 0xc9, 0x99, 0xec, 0xe4, 0xac, 0x32, 0x98, 0xb9, 0xd0, 0x8d, 0x7d, 0x1b,
-		0x8f, 0x35, 0x38, 0xb7, 0x8b, 0xa9, 0x39, 0x28, 0xe9, 0x6e, 0x61, 0x42, 0xec, 0x7d, 0x4c, 0x42,
-		0xcf, 0x7a, 0x17, 0x66, 0xb8, 0x62, 0xc2, 0x59, 0x0b, 0x95, 0x0d, 0x15, 0xa7, 0x08, 0x0d, 0xae,
-		0xb4, 0x29, 0xf1, 0xc6, 0xd8, 0x7a, 0xc6, 0x67, 0x39, 0x38, 0xaf, 0x12, 0x43, 0x9a, 0xda, 0x87,
-		0x05, 0xb1, 0xb7, 0x5b, 0x72, 0x46, 0xca, 0x73, 0x43, 0x91, 0x90, 0x87, 0x93, 0x13, 0xd9, 0x38,
-		0x1c, 0x15, 0x49, 0x79, 0x9e, 0x44, 0xc7, 0xf4, 0x16, 0xa0, 0x34, 0x50, 0x46, 0x8a, 0xde, 0x8e,
-		0xa6, 0xe8, 0x42, 0xe5, 0xc5, 0x31, 0xec, 0xd3, 0x97, 0x26, 0x92, 0xcf, 0x3d, 0x58, 0xdf, 0xc5,
-		0x74, 0xe7, 0xe6, 0xdd, 0x21, 0x6b, 0xf1, 0x1e, 0x80, 0x48, 0x1c, 0x5e, 0xc3, 0x0f, 0xf5, 0x1f,
-		0x87, 0x1f, 0x8b, 0x56, 0x3c, 0x1d, 0xf3, 0x00, 0xc7, 0x7e, 0x11, 0xa3, 0x07, 0x17, 0x86, 0xf0,
-		0x93, 0x46, 0xbf, 0x0f, 0x4b, 0x91, 0x6a, 0xdf, 0x62, 0xd8, 0x21, 0xdf, 0x4b, 0x63, 0xf2, 0x35,
-		0x17, 0x83, 0xf8, 0x00, 0x31, 0xfe, 0xa3, 0xc1, 0x33, 0x8c, 0x37, 0x0f, 0x51, 0x43, 0xd4, 0x7d,
-		0x00, 0xab, 0x4d, 0x9b, 0x50, 0x2b, 0xc0, 0x34, 0x70, 0x71, 0x17, 0xf7, 0xd7, 0x3e, 0x8c, 0xef,
-		0x85, 0xca, 0x5a, 0x2a, 0x31, 0xee, 0x79, 0xf4, 0xf5, 0x57, 0x1f, 0x30, 0xb3, 0x9a, 0x2b, 0x0c,
-		0xdb, 0x0c, 0x91, 0x25, 0xf5, 0x3d, 0xa7, 0x4f, 0x57, 0x86, 0xdd, 0x38, 0xdd, 0xdc, 0x98, 0x74,
-		0xef, 0x84, 0xc8, 0x03, 0xba, 0x49, 0x47, 0xcf, 0xa7, 0x1d, 0xdd, 0x87, 0x67, 0x87, 0x6b, 0x2e,
-		0x0d, 0xbf, 0x0b, 0xb3, 0x11, 0x3f, 0x9f, 0xd8, 0xaf, 0xfa, 0xc8, 0xc6, 0x5f, 0x34, 0x58, 0x36,
-		0xb1, 0xdd, 0x6e, 0x37, 0x7b, 0x3c, 0x48, 0x92, 0x63, 0xca, 0x18, 0xaf, 0xc1, 0x0c, 0x0f, 0xf0,
-		0x44, 0x06, 0xac, 0x11, 0x81, 0x4f, 0x02, 0x1b, 0x45, 0x38, 0x93, 0x90, 0x5e, 0xd6, 0x00, 0xbf,
-		0xcd, 0xc1, 0xea, 0xb6, 0xe3, 0xdc, 0xc3, 0x76, 0x50, 0x3f, 0xd8, 0xa6, 0xa2, 0xdc, 0xee, 0x17,
-		0x02, 0x6d, 0x58, 0x24, 0x7c, 0xc6, 0xb2, 0xc3, 0x29, 0xe9, 0xb6, 0xd7, 0x14, 0xe1, 0x42, 0x49,
-		0xab, 0x9c, 0x18, 0x16, 0xb1, 0xe2, 0x14, 0x89, 0x8f, 0xa2, 0x8b, 0xb0, 0x40, 0x70, 0xbd, 0x13,
-		0xf0, 0xc2, 0x8d, 0x27, 0x02, 0x11, 0xe6, 0xe6, 0xc3, 0x51, 0x1e, 0x13, 0x75, 0x17, 0x96, 0xb3,
-		0xe8, 0x45, 0xc3, 0xca, 0x9c, 0x08, 0x2b, 0x57, 0xa2, 0x61, 0x65, 0xa1, 0x72, 0x31, 0xd3, 0x5e,
-		0x7b, 0x9e, 0x83, 0x3f, 0xc6, 0x0e, 0x77, 0x4b, 0x5e, 0x8e, 0x44, 0x02, 0xca, 0x59, 0xd0, 0xb3,
-		0x94, 0x92, 0xf6, 0x2b, 0xc1, 0x4a, 0x58, 0xad, 0x54, 0x85, 0x7f, 0x4a, 0x7d, 0x8d, 0x3f, 0xe7,
-		0xa1, 0x98, 0x9a, 0x92, 0x6e, 0x79, 0x00, 0xab, 0xa4, 0xd3, 0x6e, 0xfb, 0x01, 0xc5, 0x8e, 0x55,
-		0x6f, 0xba, 0xd8, 0xa3, 0x96, 0xcc, 0x28, 0xa1, 0x9f, 0xbe, 0x94, 0x29, 0xe8, 0xbd, 0x10, 0xab,
-		0xca, 0x91, 0x64, 0x56, 0x22, 0x66, 0x91, 0x64, 0x4f, 0xb0, 0x4c, 0xd7, 0xc2, 0xec, 0x98, 0x42,
-		0x0e, 0xdc, 0x36, 0x0f, 0x78, 0xd9, 0x3e, 0x38, 0xd8, 0x07, 0xb7, 0xfa, 0xe0, 0x3c, 0xd4, 0x2d,
-		0xb4, 0x62, 0xdf, 0xc8, 0x83, 0xc5, 0x36, 0x23, 0x4e, 0x28, 0xc3, 0x13, 0x14, 0xf3, 0xdc, 0x25,
-		0xaa, 0x23, 0x8e, 0x74, 0x09, 0x23, 0x94, 0xef, 0x0c, 0xc8, 0x30, 0xca, 0xd2, 0x21, 0xda, 0xf1,
-		0x51, 0xfd, 0x10, 0x96, 0xb3, 0x00, 0x33, 0x56, 0xfa, 0xed, 0x78, 0x02, 0x51, 0x06, 0xd6, 0x04,
-		0xb9, 0xe8, 0x5a, 0xbf, 0x09, 0xc5, 0xaa, 0xdf, 0xf1, 0x58, 0x38, 0x4f, 0x06, 0xd1, 0xf3, 0x00,
-		0x0d, 0x3f, 0xa8, 0xe3, 0xeb, 0x98, 0xd6, 0x0f, 0x38, 0xdb, 0x59, 0x33, 0x32, 0x62, 0x7c, 0x02,
-		0xa5, 0x34, 0xaa, 0x5c, 0xee, 0xeb, 0x70, 0x22, 0x2c, 0x33, 0xc4, 0xee, 0x79, 0x49, 0x25, 0x9b,
-		0xac, 0x27, 0x76, 0x6e, 0xde, 0xe5, 0xc4, 0x84, 0x4d, 0x42, 0xe4, 0x48, 0xac, 0xc9, 0x89, 0xf3,
-		0x8e, 0xf8, 0x32, 0xfe, 0x90, 0x83, 0x15, 0x13, 0xdb, 0x4e, 0x86, 0xd8, 0x5b, 0x30, 0xc5, 0xeb,
-		0x70, 0x8d, 0x7b, 0xff, 0xd3, 0xca, 0xf3, 0xe6, 0xcd, 0xbb, 0xdc, 0xef, 0x39, 0x70, 0xac, 0xfe,
-		0xcf, 0xc5, 0xeb, 0x7f, 0xb6, 0x3f, 0xfd, 0x4e, 0x50, 0xc7, 0x96, 0x0c, 0xc7, 0x32, 0x3a, 0xcf,
-		0x8b, 0x51, 0xb9, 0xc6, 0xe8, 0x3e, 0x94, 0x5c, 0x8f, 0x41, 0xb8, 0x5d, 0x6c, 0xb1, 0xaa, 0x34,
-		0x92, 0x19, 0xa6, 0x46, 0x67, 0x86, 0x33, 0x7d, 0xe4, 0x6b, 0x5e, 0x24, 0x31, 0x3c, 0x91, 0xc2,
-		0xf4, 0x4f, 0x39, 0x28, 0xa6, 0x8c, 0x25, 0x17, 0xea, 0x48, 0xd6, 0xca, 0x4c, 0xee, 0xb9, 0xff,
-		0x31, 0xb9, 0x23, 0x1b, 0x56, 0x52, 0x54, 0xa3, 0xbb, 0x6d, 0xa2, 0x7a, 0x65, 0x39, 0x49, 0x9e,
-		0x6f, 0xe5, 0x0c, 0x8b, 0x4d, 0x65, 0x59, 0xec, 0x0b, 0x0d, 0x8a, 0x77, 0x3a, 0xc1, 0x3e, 0xfe,
-		0x9a, 0xfb, 0x97, 0xa1, 0x43, 0x29, 0xad, 0xa7, 0x0c, 0xf4, 0x7f, 0xcc, 0x41, 0xf1, 0x16, 0xfe,
-		0xfa, 0x1b, 0xe1, 0xc9, 0x6c, 0xb2, 0xab, 0x50, 0x4a, 0x1b, 0x6b, 0xb2, 0xc3, 0x9e, 0xf1, 0x53,
-		0x0d, 0xd6, 0x4c, 0xdc, 0x08, 0x30, 0x39, 0x08, 0x4b, 0x23, 0xee, 0xbb, 0xc7, 0xd4, 0x08, 0x3f,
-		0x0f, 0x67, 0xb3, 0xa5, 0x91, 0x0e, 0xf2, 0xb7, 0x1c, 0x9c, 0x33, 0x31, 0xc1, 0x9e, 0x93, 0xd8,
-		0x81, 0x24, 0xd2, 0x89, 0x95, 0x3d, 0x40, 0x59, 0x77, 0xcf, 0x99, 0xb3, 0x62, 0x60, 0xcf, 0xf9,
-		0x7f, 0xd5, 0x8b, 0x17, 0x61, 0x21, 0xc0, 0x2d, 0x9f, 0xa6, 0x5c, 0x49, 0x8c, 0x86, 0xae, 0x94,
-		0x68, 0x44, 0x4c, 0x3d, 0xb9, 0x46, 0xc4, 0xf4, 0xd1, 0x1b, 0x11, 0xc6, 0x3a, 0x9c, 0x57, 0x59,
-		0x54, 0x1a, 0xdd, 0x86, 0xb5, 0x5d, 0x4c, 0xab, 0x81, 0x4f, 0x88, 0x54, 0x25, 0x69, 0xf1, 0x41,
-		0x4b, 0x56, 0x4b, 0xb4, 0x64, 0x2f, 0xc2, 0x02, 0xb5, 0x83, 0x7d, 0x4c, 0xfb, 0xa6, 0x91, 0xa5,
-		0xa6, 0x18, 0x95, 0xf4, 0x8c, 0x7f, 0xe5, 0xe1, 0x6c, 0x36, 0x0f, 0xe9, 0xcf, 0x87, 0x8c, 0x0e,
-		0x8b, 0xce, 0xb5, 0x9e, 0x68, 0x10, 0x8f, 0x28, 0x91, 0x87, 0x11, 0xe3, 0x0d, 0x31, 0x72, 0xb5,
-		0xc7, 0x4f, 0xcc, 0x22, 0xfb, 0x9f, 0xa4, 0x91, 0x21, 0xf4, 0x03, 0x38, 0xd3, 0xb0, 0xdd, 0x26,
-		0x2b, 0x1b, 0xed, 0x0e, 0xc1, 0x03, 0x9e, 0x22, 0xe1, 0xbc, 0x7f, 0x14, 0x9e, 0xd7, 0x39, 0xc1,
-		0x2a, 0xa3, 0x17, 0xe3, 0x8c, 0x1a, 0xa9, 0x09, 0xfd, 0x21, 0x2c, 0xa5, 0x44, 0xcc, 0x38, 0xcc,
-		0x5f, 0x8f, 0xd7, 0x62, 0xaf, 0xa8, 0x96, 0x3f, 0x29, 0x94, 0x5c, 0xb8, 0xe8, 0x89, 0x5e, 0x7f,
-		0x08, 0x45, 0x85, 0x84, 0x19, 0x8c, 0xdf, 0x8d, 0x97, 0xfb, 0x4a, 0xbf, 0xdb, 0xc5, 0x94, 0xf1,
-		0x8b, 0x10, 0x8e, 0xd6, 0x81, 0x5f, 0x6a, 0xb0, 0x21, 0xcc, 0xe3, 0xa4, 0xcc, 0x56, 0xf5, 0x5b,
-		0xed, 0x26, 0xa6, 0x78, 0x8c, 0x3e, 0xf9, 0x98, 0x2e, 0x86, 0x3e, 0x12, 0x1e, 0x64, 0x05, 0x72,
-		0x45, 0x88, 0xcc, 0xf1, 0x13, 0x98, 0x4d, 0x20, 0x32, 0xc2, 0x83, 0x2f, 0x82, 0x9e, 0x85, 0xf9,
-		0x06, 0xab, 0x4e, 0x3f, 0xc0, 0x22, 0x58, 0xf1, 0x8d, 0x3d, 0x6b, 0xc6, 0x07, 0x0d, 0x02, 0xcf,
-		0x8f, 0xa1, 0x6c, 0xbf, 0x96, 0x9d, 0x0e, 0xdb, 0x17, 0x47, 0x5c, 0x59, 0x8e, 0x6e, 0x7c, 0xa6,
-		0x41, 0x91, 0x1d, 0xe1, 0x7b, 0x9e, 0xdd, 0x72, 0xeb, 0x55, 0xdf, 0x6b, 0xb8, 0xfb, 0xa1, 0x45,
-		0x9f, 0x86, 0x42, 0x9d, 0x0f, 0x88, 0xf3, 0xbf, 0x08, 0x95, 0x20, 0x86, 0x78, 0x8b, 0x79, 0x07,
-		0x4e, 0x34, 0xdc, 0x26, 0xc5, 0x41, 0x58, 0x68, 0xbd, 0xa0, 0x3a, 0x7b, 0x44, 0xc9, 0x5f, 0xe7,
-		0x28, 0x66, 0x88, 0x6a, 0xdc, 0x86, 0x52, 0x5a, 0x82, 0x7e, 0x25, 0x28, 0xfd, 0x48, 0x1b, 0xe7,
-		0x98, 0x2d, 0x60, 0x8d, 0x9f, 0x69, 0xa0, 0x7f, 0xd8, 0x76, 0x6c, 0x8a, 0x8f, 0xa6, 0xd6, 0x07,
-		0x30, 0x2f, 0x01, 0x38, 0xbd, 0x50, 0xb9, 0xe7, 0xc7, 0x51, 0x4e, 0xe4, 0xf4, 0x93, 0xf5, 0xc1,
-		0x07, 0x31, 0xce, 0xc1, 0x5a, 0xa6, 0x38, 0x32, 0x78, 0x3e, 0xe6, 0x09, 0x96, 0x05, 0x5e, 0x7c,
-		0x9c, 0xcb, 0xc0, 0x13, 0x6b, 0x96, 0x14, 0x52, 0xcc, 0x9f, 0x68, 0xec, 0x04, 0xde, 0x72, 0xbd,
-		0x1d, 0xcc, 0x5c, 0x31, 0x4c, 0x7b, 0xc7, 0x54, 0x06, 0xfc, 0x5e, 0x83, 0xb5, 0x4c, 0x69, 0xa4,
-		0xe3, 0x5c, 0x1a, 0xb4, 0xa8, 0x1d, 0x0e, 0xe1, 0xc8, 0xc3, 0x62, 0xd8, 0x83, 0x16, 0x78, 0x0e,
-		0x7a, 0x19, 0x50, 0x5f, 0x2c, 0xd2, 0x87, 0xcd, 0x71, 0xd8, 0xa5, 0xc1, 0x4c, 0x04, 0x3c, 0x72,
-		0xa7, 0x15, 0x82, 0xe7, 0x05, 0xf8, 0x60, 0x46, 0x82, 0x33, 0x57, 0x3c, 0xcb, 0xc5, 0xbc, 0x65,
-		0xbb, 0x1e, 0xb5, 0x5d, 0xef, 0x98, 0xcd, 0xf6, 0xb9, 0x06, 0xe7, 0x14, 0xf2, 0x7c, 0xb5, 0x0c,
-		0x77, 0x05, 0x4a, 0x37, 0x5d, 0x72, 0xb4, 0xb8, 0x64, 0x7c, 0x0f, 0x56, 0x33, 0x90, 0xa5, 0x82,
-		0x55, 0x38, 0x81, 0x3d, 0x1a, 0xb8, 0xfd, 0x96, 0xfb, 0x58, 0xfb, 0x5a, 0xb6, 0x00, 0x24, 0xa6,
-		0x71, 0x08, 0x28, 0x3d, 0x8d, 0x10, 0x4c, 0x45, 0x24, 0xe2, 0xbf, 0xd1, 0x36, 0xcc, 0xc8, 0x28,
-		0x92, 0x9f, 0x34, 0x8a, 0x48, 0x44, 0xe3, 0x17, 0x5a, 0x82, 0x1b, 0x9f, 0x3e, 0x52, 0x6c, 0x7c,
-		0x42, 0xb1, 0xe2, 0xbb, 0x70, 0x3a, 0x63, 0x3e, 0x53, 0xff, 0xad, 0x78, 0x09, 0x32, 0x96, 0x94,
-		0x95, 0x7f, 0xac, 0xc1, 0x2c, 0x77, 0xd3, 0xed, 0x3b, 0x7b, 0xe8, 0xe7, 0x1a, 0xac, 0x2a, 0x9f,
-		0xbe, 0xa0, 0x6f, 0x8c, 0x68, 0x77, 0xa9, 0x1e, 0xf0, 0xe8, 0x6f, 0x4c, 0x8e, 0x28, 0x3d, 0xe8,
-		0x53, 0x38, 0x9d, 0xf1, 0x54, 0x01, 0x5d, 0x1e, 0x41, 0x30, 0xfd, 0xc4, 0x45, 0xaf, 0x4c, 0x82,
-		0x22, 0xb9, 0x47, 0xcd, 0x91, 0x7a, 0x9e, 0x31, 0xd2, 0x1c, 0xaa, 0xf7, 0x29, 0x23, 0xcd, 0xa1,
-		0x7e, 0x7d, 0x62, 0x03, 0x0c, 0x5e, 0x21, 0xa0, 0x0d, 0x05, 0x9d, 0xd4, 0xc3, 0x06, 0xfd, 0xf9,
-		0x31, 0x20, 0x07, 0x2c, 0x06, 0x37, 0xfc, 0x4a, 0x16, 0xa9, 0x47, 0x0f, 0x4a, 0x16, 0xe9, 0xe7,
-		0x02, 0x82, 0x45, 0x78, 0x37, 0x3f, 0x84, 0x45, 0xe2, 0x41, 0xc1, 0x10, 0x16, 0xc9, 0x8b, 0x7e,
-		0xf4, 0x7d, 0x98, 0x8f, 0x5d, 0xa9, 0xa3, 0x17, 0x47, 0xd8, 0x3c, 0xc6, 0xe8, 0xa5, 0xf1, 0x80,
-		0x25, 0xaf, 0xdf, 0x69, 0xfc, 0x02, 0x6e, 0xe8, 0xbd, 0x2f, 0xfa, 0xa6, 0xfa, 0x98, 0x32, 0xce,
-		0x35, 0xbd, 0xfe, 0xce, 0x91, 0xf1, 0xa5, 0x94, 0x3f, 0xd6, 0x60, 0x25, 0xfb, 0x66, 0x13, 0xbd,
-		0x3a, 0xe1, 0x45, 0xa8, 0x90, 0xe8, 0xb5, 0x23, 0x5d, 0x9f, 0xf2, 0x3d, 0xa5, 0xbc, 0x3e, 0x54,
-		0xee, 0xa9, 0x51, 0x17, 0x9c, 0xca, 0x3d, 0x35, 0xfa, 0xa6, 0xf2, 0x37, 0x1a, 0x3f, 0xed, 0x2a,
-		0x6f, 0xd6, 0xd0, 0x5b, 0x43, 0x48, 0x8f, 0xb8, 0x88, 0xd4, 0xaf, 0x1c, 0x09, 0x77, 0xe0, 0xc4,
-		0xb1, 0x2b, 0x2c, 0xa5, 0x13, 0x67, 0x5d, 0xd3, 0x29, 0x9d, 0x38, 0xf3, 0x56, 0x0c, 0xf5, 0x00,
-		0xa5, 0xef, 0x7c, 0xd0, 0x2b, 0x93, 0xde, 0x79, 0xe9, 0x97, 0x27, 0xc0, 0x90, 0xac, 0xdb, 0x70,
-		0x2a, 0x71, 0x61, 0x82, 0x5e, 0x1e, 0xf7, 0x62, 0x45, 0x30, 0x2d, 0x4f, 0x76, 0x0f, 0x83, 0x08,
-		0x2c, 0x26, 0x6f, 0x2e, 0x90, 0x8a, 0x86, 0xe2, 0x76, 0x44, 0xdf, 0x1c, 0x1b, 0x7e, 0xa0, 0x66,
-		0xa2, 0x09, 0xaf, 0x54, 0x33, 0xfb, 0x66, 0x43, 0xa9, 0xa6, 0xaa, 0xb7, 0x4f, 0x60, 0x31, 0xd9,
-		0xdc, 0x55, 0xaa, 0xa9, 0xe8, 0x76, 0x2b, 0xd5, 0x54, 0x75, 0x8d, 0x19, 0xd3, 0x64, 0x1f, 0x54,
-		0xc9, 0x54, 0xd1, 0x5d, 0x56, 0x32, 0x55, 0x36, 0x58, 0x7f, 0x08, 0xcb, 0x59, 0x9d, 0x4a, 0x54,
-		0x51, 0x5a, 0x4c, 0xd9, 0x64, 0xd5, 0xb7, 0x26, 0xc2, 0x89, 0x44, 0xd7, 0xec, 0xc6, 0x9d, 0x32,
-		0xba, 0x0e, 0xed, 0x9c, 0x2a, 0xa3, 0xeb, 0xf0, 0xee, 0x20, 0x33, 0x44, 0x56, 0xe3, 0x4b, 0x69,
-		0x88, 0x21, 0xad, 0x44, 0xa5, 0x21, 0x86, 0xb6, 0x06, 0x3f, 0xd7, 0xe0, 0xc2, 0xc8, 0xd6, 0x0a,
-		0x7a, 0x47, 0xad, 0xdd, 0x58, 0x1d, 0x28, 0xfd, 0xdd, 0xa3, 0x13, 0x18, 0xf8, 0x69, 0xb2, 0x15,
-		0xa2, 0xf4, 0x53, 0x45, 0xd7, 0x46, 0xe9, 0xa7, 0xca, 0x1e, 0xcb, 0xa7, 0x70, 0x3a, 0xa3, 0x3d,
-		0xa1, 0x2c, 0x67, 0xd5, 0x9d, 0x15, 0x65, 0x39, 0x3b, 0xa4, 0xfb, 0x21, 0x76, 0x49, 0xba, 0xed,
-		0x30, 0x64, 0x97, 0x28, 0x3b, 0x25, 0x43, 0x76, 0x89, 0xba, 0xaf, 0x81, 0xba, 0xb0, 0x94, 0x3a,
-		0x2c, 0x22, 0x95, 0x11, 0x55, 0x67, 0x52, 0xfd, 0x95, 0xf1, 0x11, 0x24, 0xdf, 0x47, 0xb0, 0x10,
-		0xef, 0x5d, 0x20, 0x75, 0x9a, 0x52, 0x75, 0x5d, 0x94, 0x16, 0x1f, 0xd6, 0x1a, 0x79, 0xac, 0x41,
-		0x31, 0x3c, 0xfe, 0x57, 0xfd, 0x20, 0xe8, 0xb4, 0xfb, 0xd5, 0x1a, 0xda, 0x1a, 0x46, 0x4f, 0xd1,
-		0xc3, 0xd0, 0x5f, 0x9d, 0x0c, 0x49, 0x88, 0x71, 0xf5, 0xb5, 0x6f, 0x6f, 0xed, 0xbb, 0xf4, 0xa0,
-		0x53, 0x2b, 0xd7, 0xfd, 0xd6, 0x66, 0xec, 0xcf, 0x19, 0xe5, 0x7d, 0xec, 0x89, 0xff, 0x9f, 0xf4,
-		0xff, 0xdc, 0x72, 0x85, 0xff, 0xe8, 0x5e, 0xae, 0xcd, 0xf0, 0xf1, 0xad, 0xff, 0x06, 0x00, 0x00,
-		0xff, 0xff, 0x07, 0xcb, 0x34, 0xdf, 0x04, 0x33, 0x00, 0x00,
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x3b, 0x4d, 0x6c, 0x1b, 0xc7,
+		0xd5, 0x59, 0xd2, 0x92, 0xa5, 0x47, 0x4b, 0xb6, 0x26, 0xb2, 0x48, 0xad, 0x2c, 0x59, 0xde, 0xc4,
+		0xb1, 0x9c, 0x38, 0x54, 0x44, 0xc5, 0xf9, 0x9c, 0x18, 0xf9, 0x62, 0x99, 0xb2, 0x64, 0x25, 0x56,
+		0x2c, 0xaf, 0x1d, 0xa7, 0x28, 0x82, 0x6e, 0x97, 0xdc, 0x91, 0xb4, 0x15, 0xb9, 0x4b, 0xed, 0x2c,
+		0xe9, 0x28, 0x28, 0xda, 0xa2, 0x48, 0x0f, 0x45, 0xff, 0xd1, 0x43, 0x8f, 0x3d, 0x34, 0xc8, 0xa1,
+		0x45, 0x51, 0xf4, 0xde, 0x73, 0xd1, 0x63, 0x7a, 0xea, 0xb5, 0xc8, 0x21, 0x97, 0x02, 0x05, 0x8a,
+		0x5e, 0x7a, 0x2c, 0xe6, 0x67, 0xb9, 0xbb, 0xdc, 0x1d, 0x72, 0x29, 0xbb, 0x70, 0x90, 0x1b, 0xf7,
+		0xcd, 0xfb, 0x9b, 0x37, 0x6f, 0xde, 0x7b, 0xf3, 0x66, 0x08, 0xcf, 0xb5, 0x6b, 0xd8, 0x5b, 0xae,
+		0x9b, 0x16, 0x76, 0xea, 0x78, 0xd9, 0xb4, 0x9a, 0xb6, 0xb3, 0xdc, 0x59, 0x59, 0x26, 0xd8, 0xeb,
+		0xd8, 0x75, 0x5c, 0x6e, 0x79, 0xae, 0xef, 0xa2, 0xb3, 0x14, 0xa9, 0x2c, 0x90, 0xca, 0x0c, 0xa9,
+		0xdc, 0x59, 0x51, 0xcf, 0xef, 0xb9, 0xee, 0x5e, 0x03, 0x2f, 0x33, 0xa4, 0x5a, 0x7b, 0x77, 0xd9,
+		0xb7, 0x9b, 0x98, 0xf8, 0x66, 0xb3, 0xc5, 0xe9, 0xd4, 0x85, 0x5e, 0x84, 0x47, 0x9e, 0xd9, 0x6a,
+		0x61, 0x8f, 0x88, 0xf1, 0xc5, 0xb8, 0xf0, 0x96, 0x4d, 0x45, 0xd7, 0xdd, 0x66, 0xd3, 0x75, 0x04,
+		0xc6, 0xf3, 0x69, 0x18, 0x1d, 0x9b, 0xd8, 0x35, 0xbb, 0x61, 0xfb, 0x47, 0xa9, 0x58, 0x64, 0xdf,
+		0xf4, 0xb0, 0xc5, 0x58, 0x35, 0xda, 0xc4, 0xc7, 0xde, 0x00, 0xac, 0x7d, 0x9b, 0xf8, 0xae, 0x17,
+		0xf0, 0xd2, 0x24, 0x58, 0x87, 0x6d, 0xdc, 0x16, 0xf6, 0x50, 0x97, 0x24, 0x38, 0x1e, 0x6e, 0x35,
+		0xec, 0xba, 0xe9, 0xdb, 0x81, 0xfe, 0xda, 0x2f, 0x14, 0x58, 0x5c, 0xc7, 0xa4, 0xee, 0xd9, 0x35,
+		0xfc, 0xbe, 0xeb, 0x1d, 0xec, 0x36, 0xdc, 0x47, 0xb7, 0x3e, 0xc4, 0xf5, 0x36, 0xc5, 0xd1, 0xf1,
+		0x61, 0x1b, 0x13, 0x1f, 0xcd, 0xc0, 0xa8, 0xe5, 0x36, 0x4d, 0xdb, 0x29, 0x29, 0x8b, 0xca, 0xd2,
+		0xb8, 0x2e, 0xbe, 0xd0, 0x7b, 0x80, 0x1e, 0x09, 0x1a, 0x03, 0x07, 0x44, 0xa5, 0xdc, 0xa2, 0xb2,
+		0x54, 0xa8, 0xbc, 0x50, 0x8e, 0xaf, 0x49, 0xcb, 0x2e, 0x77, 0x56, 0xca, 0x49, 0x11, 0x53, 0x8f,
+		0x7a, 0x41, 0xda, 0x5f, 0x15, 0xb8, 0xd0, 0x47, 0x27, 0xd2, 0x72, 0x1d, 0x82, 0xd1, 0x2c, 0x8c,
+		0xd1, 0x89, 0x59, 0x86, 0x6d, 0x31, 0xb5, 0x46, 0xf4, 0x93, 0xec, 0x7b, 0xcb, 0x42, 0x17, 0xe0,
+		0x94, 0xb0, 0x99, 0x61, 0x5a, 0x96, 0xc7, 0x34, 0x1a, 0xd7, 0x0b, 0x02, 0xb6, 0x66, 0x59, 0x1e,
+		0x5a, 0x85, 0x99, 0x66, 0xdb, 0x37, 0x6b, 0x0d, 0x6c, 0x10, 0xdf, 0xf4, 0xb1, 0x61, 0x3b, 0x46,
+		0xdd, 0xac, 0xef, 0xe3, 0x52, 0x9e, 0x21, 0x3f, 0x2b, 0x46, 0xef, 0xd3, 0xc1, 0x2d, 0xa7, 0x4a,
+		0x87, 0xd0, 0xeb, 0x30, 0x9b, 0x20, 0xb2, 0x4c, 0xdf, 0xac, 0x99, 0x04, 0x97, 0x4e, 0x30, 0xba,
+		0x99, 0x38, 0xdd, 0xba, 0x18, 0xd5, 0xfe, 0xac, 0x80, 0x1a, 0xcc, 0xe9, 0x36, 0xd7, 0xe3, 0xb6,
+		0x4b, 0xfc, 0xc0, 0xc2, 0xcf, 0xc1, 0xa9, 0x7d, 0x97, 0xf8, 0x4c, 0x5d, 0x4c, 0x08, 0xb7, 0xf3,
+		0xed, 0x67, 0xf4, 0x02, 0x85, 0xae, 0x71, 0x20, 0x9a, 0x8b, 0xcc, 0x98, 0x4e, 0x69, 0xe4, 0xf6,
+		0x33, 0xe1, 0x9c, 0xdf, 0x4f, 0x5d, 0x8b, 0xfc, 0x30, 0x6b, 0x71, 0xfb, 0x99, 0x94, 0xd5, 0xb8,
+		0x39, 0x01, 0x05, 0x4b, 0x28, 0x6e, 0xd4, 0x8e, 0xb4, 0xaf, 0x85, 0xfe, 0x72, 0x9f, 0x8a, 0x5e,
+		0xb7, 0x89, 0xef, 0xd9, 0xb5, 0x98, 0xbf, 0xcc, 0xc1, 0x78, 0xcb, 0xdc, 0xc3, 0x06, 0xb1, 0x3f,
+		0xc2, 0x62, 0x6d, 0xc6, 0x28, 0xe0, 0xbe, 0xfd, 0x11, 0x46, 0x45, 0x38, 0xc9, 0x06, 0x83, 0x49,
+		0xe8, 0xa3, 0xf4, 0x73, 0xcb, 0xd2, 0xbe, 0x88, 0x2c, 0x7b, 0x0a, 0x6b, 0xb1, 0xec, 0x4b, 0x70,
+		0xc6, 0x69, 0x37, 0x6b, 0xd8, 0x33, 0xdc, 0x5d, 0x83, 0x4d, 0x9e, 0x08, 0x11, 0x93, 0x1c, 0x7e,
+		0x77, 0x97, 0x11, 0x13, 0xf4, 0x01, 0x8c, 0x8a, 0xf1, 0xdc, 0x62, 0x7e, 0xa9, 0x50, 0x59, 0x2f,
+		0xa7, 0x46, 0x89, 0xf2, 0x40, 0x99, 0x65, 0xce, 0xf0, 0x96, 0xe3, 0x7b, 0x47, 0xba, 0xe0, 0xa9,
+		0xbe, 0x0e, 0x85, 0x08, 0x18, 0x9d, 0x81, 0xfc, 0x01, 0x3e, 0x12, 0x9a, 0xd0, 0x9f, 0x68, 0x1a,
+		0x46, 0x3a, 0x66, 0xa3, 0x8d, 0x85, 0xf7, 0xf1, 0x8f, 0x37, 0x72, 0xd7, 0x14, 0xed, 0xfb, 0x39,
+		0x98, 0x4b, 0xf5, 0x85, 0xa1, 0xa7, 0x38, 0x07, 0xe3, 0x81, 0x47, 0xf0, 0x59, 0x8e, 0xe8, 0x63,
+		0xc2, 0x21, 0x08, 0x7a, 0x1b, 0x4e, 0xf1, 0x7d, 0x1a, 0x71, 0xec, 0x42, 0xe5, 0x52, 0xdc, 0x0a,
+		0x3c, 0x36, 0x30, 0x33, 0x30, 0x5c, 0xe6, 0xe8, 0x5b, 0xce, 0xae, 0xab, 0x17, 0xac, 0x10, 0x80,
+		0x5e, 0x83, 0x22, 0x17, 0x54, 0x77, 0x1d, 0xdf, 0x73, 0x1b, 0x0d, 0xec, 0xb1, 0x2d, 0xd0, 0x26,
+		0xc2, 0xef, 0xcf, 0xb2, 0xe1, 0x6a, 0x77, 0xf4, 0x3e, 0x1b, 0x44, 0x25, 0x38, 0x19, 0xb8, 0xf4,
+		0x08, 0xc3, 0x0b, 0x3e, 0xb5, 0x32, 0x4c, 0x55, 0x1b, 0x2e, 0xe1, 0x56, 0x0f, 0x1c, 0x47, 0xbe,
+		0xa7, 0xb5, 0x69, 0x40, 0x51, 0x7c, 0x6e, 0x2a, 0xed, 0x9f, 0x0a, 0x4c, 0xe9, 0xb8, 0xe9, 0x76,
+		0xf0, 0x03, 0x93, 0x1c, 0x0c, 0x66, 0x83, 0xde, 0x84, 0x71, 0xdf, 0x24, 0x07, 0x86, 0x7f, 0xd4,
+		0xe2, 0x2b, 0x33, 0x59, 0x59, 0x94, 0x59, 0x84, 0xb2, 0x7c, 0x70, 0xd4, 0xc2, 0xfa, 0x98, 0x2f,
+		0x7e, 0x51, 0xe7, 0x65, 0xe4, 0xb6, 0xc5, 0xcc, 0x99, 0xd7, 0x47, 0xe9, 0xe7, 0x96, 0x85, 0xaa,
+		0x70, 0x3a, 0x8c, 0xfa, 0x06, 0xcd, 0x33, 0xcc, 0x30, 0x85, 0x8a, 0x5a, 0xe6, 0x39, 0xa6, 0x1c,
+		0xe4, 0x98, 0xf2, 0x83, 0x20, 0x09, 0xe9, 0x93, 0x21, 0x09, 0x05, 0xd2, 0xb8, 0x25, 0x32, 0x82,
+		0xe1, 0x98, 0x4d, 0x2c, 0x4c, 0x56, 0x10, 0xb0, 0x77, 0xcd, 0x26, 0xa6, 0x66, 0x88, 0xce, 0x57,
+		0x98, 0xe1, 0xe7, 0xcc, 0x0c, 0x04, 0xfb, 0xf7, 0xda, 0xb8, 0x8d, 0x33, 0x98, 0xa1, 0x57, 0x52,
+		0x2e, 0x21, 0x29, 0x6e, 0xa9, 0xfc, 0xb0, 0x96, 0xe2, 0x8a, 0x86, 0x1a, 0x09, 0x45, 0x7f, 0xa9,
+		0xc0, 0x74, 0xe0, 0xfa, 0x5f, 0x1e, 0x5d, 0xef, 0xc2, 0xd9, 0x1e, 0xa5, 0xc4, 0x4e, 0x7c, 0x0d,
+		0x8a, 0x2d, 0xcf, 0xad, 0x63, 0x42, 0x6c, 0x67, 0xcf, 0x60, 0x19, 0x96, 0x47, 0x7e, 0xba, 0x21,
+		0xf3, 0xd4, 0xed, 0xc3, 0x61, 0x46, 0xc9, 0xc2, 0x3e, 0xd1, 0xfe, 0x9d, 0x83, 0x4b, 0x9b, 0xd8,
+		0x4f, 0x26, 0x2f, 0xf3, 0x91, 0xd8, 0xf0, 0x0f, 0x2b, 0x4f, 0x27, 0xb9, 0xa2, 0x77, 0xa0, 0x40,
+		0x7c, 0xd3, 0xf3, 0x0d, 0xdc, 0xc1, 0x8e, 0x2f, 0x82, 0xc2, 0x8b, 0x32, 0x63, 0x3d, 0xc4, 0x1e,
+		0xa1, 0x99, 0x81, 0x2b, 0xbd, 0xe5, 0xe3, 0xa6, 0x0e, 0x8c, 0xfc, 0x16, 0xa5, 0x46, 0x9b, 0x30,
+		0x8e, 0x1d, 0x4b, 0xb0, 0x3a, 0x31, 0x34, 0xab, 0x31, 0xec, 0x58, 0x9c, 0x51, 0x2c, 0x63, 0x8c,
+		0xf4, 0x64, 0x8c, 0x17, 0xe0, 0xb4, 0x83, 0x3f, 0xf4, 0x0d, 0x86, 0xe1, 0xbb, 0x07, 0xd8, 0x29,
+		0x8d, 0x2e, 0x2a, 0x4b, 0xa7, 0xf4, 0x09, 0x0a, 0xde, 0x31, 0xf7, 0xf0, 0x03, 0x0a, 0xd4, 0xfe,
+		0xa1, 0xc0, 0xd2, 0x60, 0xab, 0x8b, 0xa5, 0x4d, 0x61, 0xaa, 0xa4, 0x30, 0x45, 0x1b, 0x70, 0x3a,
+		0xa8, 0x25, 0x6a, 0xa6, 0x5f, 0xdf, 0xc7, 0x41, 0x3a, 0x99, 0x4f, 0x5d, 0x03, 0x9a, 0xf0, 0x6f,
+		0x36, 0xdc, 0x9a, 0x3e, 0x29, 0xa8, 0x6e, 0x72, 0x22, 0x74, 0x17, 0x4e, 0x77, 0xb8, 0x05, 0x0c,
+		0x31, 0x92, 0x9e, 0x9c, 0x65, 0x06, 0xd3, 0x27, 0x3b, 0xb1, 0x6f, 0xed, 0x63, 0x05, 0xe6, 0x37,
+		0xb1, 0xaf, 0x87, 0x25, 0xdd, 0x36, 0x26, 0xc4, 0xdc, 0xc3, 0x24, 0xf0, 0xac, 0x1b, 0x30, 0xca,
+		0x26, 0xc6, 0x9d, 0xb5, 0x50, 0x59, 0x92, 0x49, 0x8a, 0xf0, 0x60, 0x93, 0xd6, 0x05, 0x5d, 0x86,
+		0xad, 0xa7, 0x7d, 0x92, 0x83, 0x05, 0x99, 0x1a, 0xc2, 0xd4, 0x2e, 0x4c, 0xf2, 0xbd, 0xdd, 0x14,
+		0x23, 0x42, 0x9f, 0xdb, 0x92, 0x84, 0xdc, 0x9f, 0x1d, 0xcf, 0xc6, 0x01, 0x94, 0x27, 0xe5, 0x09,
+		0x12, 0x85, 0x21, 0x0d, 0x26, 0xac, 0xc6, 0xa1, 0x61, 0xd6, 0x0f, 0x8c, 0x06, 0xee, 0xe0, 0x06,
+		0xd3, 0x3b, 0xaf, 0x17, 0xac, 0xc6, 0xe1, 0x5a, 0xfd, 0xe0, 0x0e, 0x05, 0xa9, 0x4d, 0x40, 0x49,
+		0x46, 0x29, 0x69, 0x7c, 0x2d, 0x9a, 0xc6, 0x0b, 0x95, 0x97, 0x32, 0xd8, 0xb0, 0xab, 0x71, 0x24,
+		0xe7, 0x3b, 0xb0, 0xb8, 0x89, 0xfd, 0xf5, 0x3b, 0xf7, 0xfa, 0xac, 0xd7, 0xdb, 0x00, 0x3c, 0xb9,
+		0x38, 0xbb, 0x6e, 0x60, 0xa3, 0x2c, 0xf2, 0x68, 0x44, 0x63, 0x29, 0x7b, 0xdc, 0x17, 0xbf, 0x88,
+		0x76, 0x04, 0x17, 0xfa, 0xc8, 0x13, 0x0b, 0xf3, 0x00, 0xa6, 0x22, 0x27, 0x02, 0x83, 0x52, 0x07,
+		0x72, 0x2f, 0x65, 0x94, 0xab, 0x9f, 0xf1, 0xe2, 0x00, 0xa2, 0xfd, 0x47, 0x81, 0xe7, 0xa8, 0x6c,
+		0x16, 0xc6, 0xfa, 0x4c, 0xf7, 0x21, 0xcc, 0x36, 0x4c, 0xe2, 0x1b, 0x1e, 0xf6, 0x3d, 0x1b, 0x77,
+		0x70, 0xd7, 0x3f, 0x82, 0x1c, 0x50, 0xa8, 0xcc, 0x25, 0x92, 0xe7, 0x96, 0xe3, 0xbf, 0xf6, 0xea,
+		0x43, 0x6a, 0x56, 0x7d, 0x86, 0x52, 0xeb, 0x01, 0xb1, 0xe0, 0xbe, 0x65, 0x75, 0xf9, 0x8a, 0xd0,
+		0x1c, 0xe7, 0x9b, 0xcb, 0xc8, 0x77, 0x27, 0x20, 0x0e, 0xf9, 0xf6, 0x6e, 0x86, 0x7c, 0x72, 0x33,
+		0xb8, 0xf0, 0x7c, 0xff, 0x99, 0x0b, 0xc3, 0x6f, 0xc2, 0x58, 0x64, 0x2f, 0x0c, 0xed, 0x57, 0x5d,
+		0x62, 0xed, 0x4f, 0x0a, 0x4c, 0xeb, 0xd8, 0x6c, 0xb5, 0x1a, 0x47, 0x2c, 0x90, 0x92, 0xa7, 0x94,
+		0x55, 0xae, 0xc2, 0x28, 0x4b, 0x02, 0x44, 0x04, 0xb5, 0x01, 0xc1, 0x51, 0x20, 0x6b, 0x45, 0x38,
+		0xdb, 0xa3, 0xbd, 0xa8, 0x13, 0x7e, 0x9d, 0x83, 0xd9, 0x35, 0xcb, 0xba, 0x8f, 0x4d, 0xaf, 0xbe,
+		0xbf, 0xe6, 0xf3, 0x92, 0xbc, 0x5b, 0x2c, 0xb4, 0xe0, 0x0c, 0x61, 0x23, 0x86, 0x19, 0x0c, 0x09,
+		0xb7, 0xbd, 0x25, 0x09, 0x29, 0x52, 0x5e, 0xe5, 0x1e, 0x30, 0x8f, 0x27, 0xa7, 0x49, 0x1c, 0x8a,
+		0x2e, 0xc2, 0x24, 0xc1, 0xf5, 0xb6, 0xc7, 0x8a, 0x3b, 0x96, 0x2c, 0x78, 0x28, 0x9c, 0x08, 0xa0,
+		0x2c, 0x6e, 0xaa, 0x36, 0x4c, 0xa7, 0xf1, 0x8b, 0x86, 0x95, 0x71, 0x1e, 0x56, 0xae, 0x47, 0xc3,
+		0xca, 0x64, 0xe5, 0x62, 0xaa, 0xbd, 0xb6, 0x1c, 0x0b, 0x7f, 0x88, 0x2d, 0xe6, 0x96, 0xac, 0x64,
+		0x89, 0x04, 0x94, 0x73, 0xa0, 0xa6, 0x4d, 0x4a, 0xd8, 0xaf, 0x04, 0x33, 0x41, 0x45, 0x53, 0xe5,
+		0xfe, 0x29, 0xe6, 0xab, 0xfd, 0x31, 0x0f, 0xc5, 0xc4, 0x90, 0x70, 0xcb, 0x7d, 0x98, 0x25, 0xed,
+		0x56, 0xcb, 0xf5, 0x7c, 0x6c, 0x19, 0xf5, 0x86, 0x8d, 0x1d, 0xdf, 0x10, 0x59, 0x27, 0xf0, 0xd3,
+		0x2b, 0xa9, 0x8a, 0xde, 0x0f, 0xa8, 0xaa, 0x8c, 0x48, 0x64, 0x2e, 0xa2, 0x17, 0x49, 0xfa, 0x00,
+		0xcd, 0x86, 0x4d, 0x4c, 0x8f, 0x32, 0x64, 0xdf, 0x6e, 0xb1, 0x80, 0x97, 0xee, 0x83, 0xe1, 0x3e,
+		0xd8, 0xee, 0xa2, 0xb3, 0x50, 0x37, 0xd9, 0x8c, 0x7d, 0x23, 0x07, 0xce, 0xb4, 0x28, 0x73, 0xe2,
+		0x53, 0x3a, 0xce, 0x31, 0xcf, 0x5c, 0xa2, 0x3a, 0xe0, 0xd8, 0xd7, 0x63, 0x84, 0xf2, 0x4e, 0xc8,
+		0x86, 0x72, 0x16, 0x0e, 0xd1, 0x8a, 0x43, 0xd5, 0x03, 0x98, 0x4e, 0x43, 0x4c, 0x59, 0xe9, 0x37,
+		0xe3, 0x09, 0x44, 0x1a, 0x58, 0x7b, 0xd8, 0x45, 0xd7, 0xfa, 0x75, 0x28, 0x56, 0xdd, 0xb6, 0x43,
+		0xc3, 0x79, 0x6f, 0x10, 0x5d, 0x00, 0xd8, 0x75, 0xbd, 0x3a, 0xde, 0xc0, 0x7e, 0x7d, 0x9f, 0x89,
+		0x1d, 0xd3, 0x23, 0x10, 0xed, 0x23, 0x28, 0x25, 0x49, 0xc5, 0x72, 0x6f, 0xc0, 0xc9, 0xa0, 0x14,
+		0xe1, 0xbb, 0xe7, 0x8a, 0x4c, 0x37, 0x51, 0x73, 0xac, 0xdf, 0xb9, 0xc7, 0x98, 0x71, 0x9b, 0x04,
+		0xc4, 0x91, 0x58, 0xc3, 0xf3, 0xac, 0xf8, 0xd2, 0x7e, 0x9b, 0x83, 0x19, 0x1d, 0x9b, 0x56, 0x8a,
+		0xda, 0xab, 0x70, 0x82, 0xd5, 0xea, 0x0a, 0xf3, 0xfe, 0xf3, 0xd2, 0x33, 0xe9, 0x9d, 0x7b, 0xcc,
+		0xef, 0x19, 0x72, 0xec, 0x8c, 0x90, 0x8b, 0x9f, 0x11, 0xe8, 0xfe, 0x74, 0xdb, 0x5e, 0x1d, 0x1b,
+		0x22, 0x1c, 0x8b, 0xe8, 0x3c, 0xc1, 0xa1, 0x62, 0x8d, 0xd1, 0x03, 0x28, 0xd9, 0x0e, 0xc5, 0xb0,
+		0x3b, 0xd8, 0xa0, 0x95, 0x6b, 0x24, 0x33, 0x9c, 0x18, 0x9c, 0x19, 0xce, 0x76, 0x89, 0x6f, 0x39,
+		0x91, 0xc4, 0xf0, 0x44, 0x8a, 0xd7, 0x3f, 0xe4, 0xa0, 0x98, 0x30, 0x96, 0x58, 0xa8, 0x63, 0x59,
+		0x2b, 0x35, 0xb9, 0xe7, 0x1e, 0x33, 0xb9, 0x23, 0x13, 0x66, 0x12, 0x5c, 0xa3, 0xbb, 0x6d, 0xa8,
+		0x7a, 0x65, 0xba, 0x97, 0x3d, 0xdb, 0xca, 0x29, 0x16, 0x3b, 0x91, 0x66, 0xb1, 0x2f, 0x14, 0x28,
+		0xee, 0xb4, 0xbd, 0x3d, 0xfc, 0x15, 0xf7, 0x2f, 0x4d, 0x85, 0x52, 0x72, 0x9e, 0x22, 0xd0, 0xff,
+		0x2e, 0x07, 0xc5, 0x6d, 0xfc, 0xd5, 0x37, 0xc2, 0x93, 0xd9, 0x64, 0x37, 0xa1, 0xb4, 0x8d, 0xd3,
+		0x2d, 0x99, 0xf5, 0x40, 0xa8, 0xfd, 0x58, 0x81, 0x39, 0x1d, 0xef, 0x7a, 0x98, 0xec, 0x07, 0xa5,
+		0x11, 0xf3, 0xdd, 0xa7, 0xd4, 0x2c, 0x5f, 0x80, 0x73, 0xe9, 0xda, 0x08, 0x07, 0xf9, 0x2c, 0x07,
+		0xf3, 0x3a, 0x26, 0xd8, 0xb1, 0x7a, 0x76, 0x20, 0x89, 0x74, 0x6b, 0x45, 0x9f, 0x50, 0xd4, 0xdd,
+		0xe3, 0xfa, 0x18, 0x07, 0x6c, 0x59, 0xff, 0xab, 0x7a, 0xf1, 0x22, 0x4c, 0x7a, 0xb8, 0xe9, 0xfa,
+		0x09, 0x57, 0xe2, 0xd0, 0xc0, 0x95, 0x7a, 0x9a, 0x15, 0x27, 0x9e, 0x5c, 0xb3, 0x62, 0xe4, 0xf8,
+		0xcd, 0x0a, 0x6d, 0x11, 0x16, 0x64, 0x16, 0x15, 0x46, 0x37, 0x61, 0x6e, 0x13, 0xfb, 0x55, 0xcf,
+		0x25, 0x44, 0x4c, 0xa5, 0xd7, 0xe2, 0x61, 0xdb, 0x56, 0xe9, 0x69, 0xdb, 0x5e, 0x84, 0x49, 0xdf,
+		0xf4, 0xf6, 0xb0, 0xdf, 0x35, 0x8d, 0x28, 0x35, 0x39, 0x54, 0xf0, 0xd3, 0xfe, 0x95, 0x87, 0x73,
+		0xe9, 0x32, 0x84, 0x3f, 0x1f, 0xc0, 0x24, 0x8f, 0xce, 0xb5, 0x23, 0xde, 0x44, 0x1e, 0x50, 0x22,
+		0xf7, 0x63, 0xc6, 0x9a, 0x66, 0xe4, 0xe6, 0x11, 0x3b, 0x31, 0xf3, 0xec, 0x7f, 0xca, 0x8f, 0x80,
+		0xd0, 0x77, 0xe0, 0xec, 0xae, 0x69, 0x37, 0x68, 0xd9, 0x68, 0xb6, 0x09, 0x0e, 0x65, 0xf2, 0x84,
+		0xf3, 0xce, 0x71, 0x64, 0x6e, 0x30, 0x86, 0x55, 0xca, 0x2f, 0x26, 0x19, 0xed, 0x26, 0x06, 0xd4,
+		0x43, 0x98, 0x4a, 0xa8, 0x98, 0x72, 0x98, 0xdf, 0x88, 0xd7, 0x62, 0xaf, 0xc8, 0x96, 0xbf, 0x57,
+		0x29, 0xb1, 0x70, 0xd1, 0x13, 0xbd, 0x7a, 0x08, 0x45, 0x89, 0x86, 0x29, 0x82, 0x6f, 0xc4, 0xcb,
+		0x7d, 0xa9, 0xdf, 0x6d, 0x62, 0x9f, 0xca, 0x8b, 0x30, 0x8e, 0xd6, 0x81, 0xb4, 0xc1, 0xc5, 0xcd,
+		0x63, 0x25, 0xcc, 0x56, 0x75, 0x9b, 0xad, 0x06, 0xf6, 0x71, 0x86, 0x5e, 0x7a, 0x46, 0x17, 0x43,
+		0xef, 0x73, 0x0f, 0x32, 0x3c, 0xb1, 0x22, 0x44, 0xe4, 0xf8, 0x21, 0xcc, 0xc6, 0x09, 0x29, 0xe3,
+		0xf0, 0x8b, 0xa0, 0xe7, 0x61, 0x62, 0x97, 0x56, 0xa7, 0xef, 0x62, 0x1e, 0xac, 0xd8, 0xc6, 0x1e,
+		0xd3, 0xe3, 0x40, 0x8d, 0xc0, 0xe5, 0x0c, 0x93, 0xed, 0xd6, 0xb2, 0x23, 0x41, 0xfb, 0xe2, 0x98,
+		0x2b, 0xcb, 0xc8, 0xb5, 0xef, 0x29, 0x50, 0xa4, 0x47, 0xf8, 0x23, 0xc7, 0x6c, 0xda, 0xf5, 0xaa,
+		0xeb, 0xec, 0xda, 0x7b, 0x81, 0x45, 0xcf, 0x43, 0xa1, 0xce, 0x00, 0xfc, 0xfc, 0xcf, 0x43, 0x25,
+		0x70, 0x10, 0x6b, 0x43, 0xaf, 0xc3, 0xc9, 0x5d, 0xbb, 0xe1, 0x63, 0x2f, 0x28, 0xb4, 0x5e, 0x94,
+		0x9d, 0x3d, 0xa2, 0xec, 0x37, 0x18, 0x89, 0x1e, 0x90, 0x6a, 0x77, 0xa1, 0x94, 0xd4, 0xa0, 0x5b,
+		0x09, 0x0a, 0x3f, 0x52, 0xb2, 0x1c, 0xb3, 0x39, 0xae, 0xf6, 0x13, 0x05, 0xd4, 0xf7, 0x5a, 0x96,
+		0xe9, 0xe3, 0xe3, 0x4d, 0xeb, 0x5d, 0x98, 0x10, 0x08, 0x8c, 0x5f, 0x30, 0xb9, 0xcb, 0x59, 0x26,
+		0xc7, 0x73, 0xfa, 0xa9, 0x7a, 0xf8, 0x41, 0xb4, 0x79, 0x98, 0x4b, 0x55, 0x47, 0x04, 0xcf, 0x8f,
+		0x59, 0x82, 0xa5, 0x81, 0x17, 0x3f, 0xcd, 0x65, 0x60, 0x89, 0x35, 0x4d, 0x0b, 0xa1, 0xe6, 0x8f,
+		0x14, 0x7a, 0x02, 0x6f, 0xda, 0xce, 0x3a, 0xa6, 0xae, 0x18, 0xa4, 0xbd, 0xa7, 0x54, 0x06, 0x7c,
+		0xa2, 0xc0, 0x5c, 0xaa, 0x36, 0xc2, 0x71, 0x2e, 0x85, 0x6d, 0x6c, 0x8b, 0x61, 0x58, 0xe2, 0xb0,
+		0x18, 0xf4, 0xa9, 0x39, 0x9d, 0x85, 0x5e, 0x06, 0xd4, 0x55, 0x8b, 0x74, 0x71, 0x73, 0x0c, 0x77,
+		0x2a, 0x1c, 0x89, 0xa0, 0x47, 0xee, 0xbd, 0x02, 0xf4, 0x3c, 0x47, 0x0f, 0x47, 0x04, 0x3a, 0x75,
+		0xc5, 0x73, 0x4c, 0xcd, 0x6d, 0xd3, 0x76, 0x7c, 0xd3, 0x76, 0x9e, 0xb2, 0xd9, 0x3e, 0x55, 0x60,
+		0x5e, 0xa2, 0xcf, 0x97, 0xcb, 0x70, 0xd7, 0xa1, 0x74, 0xc7, 0x26, 0xc7, 0x8b, 0x4b, 0xda, 0x37,
+		0x61, 0x36, 0x85, 0x58, 0x4c, 0xb0, 0x0a, 0x27, 0xb1, 0xe3, 0x7b, 0x76, 0xb7, 0x2d, 0x9f, 0x69,
+		0x5f, 0x8b, 0x16, 0x80, 0xa0, 0xd4, 0x0e, 0x00, 0x25, 0x87, 0x11, 0x82, 0x13, 0x11, 0x8d, 0xd8,
+		0x6f, 0xb4, 0x06, 0xa3, 0x22, 0x8a, 0xe4, 0x87, 0x8d, 0x22, 0x82, 0x50, 0xfb, 0x99, 0x02, 0x28,
+		0x39, 0x7c, 0xac, 0xd8, 0xf8, 0x84, 0x62, 0xc5, 0x37, 0xe0, 0xd9, 0x94, 0xf1, 0xd4, 0xf9, 0xaf,
+		0xc6, 0x4b, 0x90, 0x6c, 0x11, 0xfc, 0x87, 0x0a, 0xcc, 0x6f, 0xb8, 0x5e, 0x1d, 0x8b, 0xb8, 0x79,
+		0xe7, 0x5e, 0x70, 0x8f, 0xf1, 0x58, 0x67, 0xbd, 0x39, 0x18, 0xef, 0xbd, 0x23, 0x19, 0x33, 0x05,
+		0x63, 0xba, 0x13, 0x3d, 0x6c, 0x12, 0xf1, 0x88, 0x64, 0x5c, 0x17, 0x5f, 0xb4, 0xfa, 0x95, 0xa9,
+		0x22, 0x22, 0xe3, 0x0e, 0xcc, 0xee, 0x98, 0x6d, 0x42, 0xc7, 0x76, 0xba, 0xd7, 0xa3, 0x8f, 0xa3,
+		0x28, 0x6d, 0x76, 0xa6, 0x71, 0x14, 0xf2, 0xee, 0x81, 0xaa, 0x63, 0xd2, 0x6e, 0x3e, 0x41, 0x81,
+		0xf3, 0x30, 0x97, 0xca, 0x52, 0x48, 0x3c, 0x84, 0x22, 0xdb, 0x50, 0xb4, 0x1f, 0xe6, 0x50, 0x34,
+		0xef, 0xf1, 0x0e, 0xdd, 0x19, 0xee, 0xd9, 0x3e, 0x80, 0x52, 0x52, 0xa4, 0xd8, 0xc2, 0x37, 0x60,
+		0xbc, 0x1e, 0x00, 0xc5, 0x26, 0xd6, 0x64, 0x6e, 0x1c, 0xd2, 0xeb, 0x21, 0x91, 0xf6, 0x7b, 0x05,
+		0x0a, 0x91, 0x21, 0x5a, 0x21, 0x06, 0x83, 0xc6, 0x9e, 0xe7, 0xb6, 0x5b, 0xc2, 0x87, 0x27, 0x02,
+		0xe8, 0x26, 0x05, 0x52, 0x07, 0x8a, 0xbf, 0xa6, 0x18, 0x8f, 0xbc, 0x95, 0x88, 0x79, 0x57, 0xbe,
+		0xc7, 0xbb, 0xde, 0x84, 0x53, 0x6d, 0xe6, 0x40, 0x56, 0xd6, 0xc7, 0x12, 0x05, 0x81, 0x4f, 0x21,
+		0x95, 0xbf, 0x9d, 0x87, 0x31, 0x16, 0xb7, 0xd7, 0x76, 0xb6, 0xd0, 0x4f, 0x15, 0x98, 0x95, 0xbe,
+		0x17, 0x43, 0xff, 0x37, 0xa0, 0xff, 0x2b, 0x7b, 0xf5, 0xa6, 0x5e, 0x1b, 0x9e, 0x50, 0xac, 0xc7,
+		0xb7, 0xe1, 0xd9, 0x94, 0xf7, 0x3d, 0x68, 0x65, 0x00, 0xc3, 0xe4, 0xbb, 0x30, 0xb5, 0x32, 0x0c,
+		0x89, 0x90, 0x1e, 0x35, 0x47, 0xe2, 0x4d, 0xd3, 0x40, 0x73, 0xc8, 0x1e, 0x75, 0xa9, 0xd7, 0x86,
+		0x27, 0x14, 0x0a, 0x99, 0x00, 0xe1, 0xd3, 0x1d, 0xb4, 0x24, 0xe1, 0x93, 0x78, 0x0d, 0xa4, 0x5e,
+		0xce, 0x80, 0x19, 0x8a, 0x08, 0x9f, 0xc5, 0x48, 0x45, 0x24, 0x5e, 0x0a, 0xa9, 0x97, 0x33, 0x60,
+		0x46, 0x45, 0x04, 0x0f, 0x5a, 0xfa, 0x88, 0xe8, 0x79, 0x85, 0xa3, 0x5e, 0xce, 0x80, 0x29, 0x44,
+		0x7c, 0x0b, 0x26, 0x62, 0xef, 0x50, 0xd0, 0x4b, 0x03, 0x6c, 0x1e, 0x13, 0x74, 0x25, 0x1b, 0xb2,
+		0x90, 0xf5, 0x1b, 0x85, 0xdd, 0x48, 0xf7, 0x7d, 0x2c, 0x81, 0xfe, 0x5f, 0x7e, 0x6e, 0xcf, 0xf2,
+		0xb6, 0x45, 0x7d, 0xeb, 0xd8, 0xf4, 0x42, 0xcb, 0x1f, 0x28, 0x30, 0x93, 0xfe, 0x1c, 0x00, 0xbd,
+		0x3a, 0xe4, 0xeb, 0x01, 0xae, 0xd1, 0xd5, 0x63, 0xbd, 0x39, 0x60, 0x7b, 0x4a, 0x7a, 0x9f, 0x2e,
+		0xdd, 0x53, 0x83, 0x6e, 0xfc, 0xd5, 0x6b, 0xc3, 0x13, 0x0a, 0x85, 0x7e, 0xa5, 0xc0, 0xb9, 0x7e,
+		0x57, 0xcd, 0xe8, 0x8d, 0x3e, 0xac, 0x07, 0xdc, 0xcc, 0xab, 0xd7, 0x8f, 0x45, 0x1b, 0x3a, 0x71,
+		0xec, 0x4e, 0x57, 0xea, 0xc4, 0x69, 0xf7, 0xd6, 0xea, 0x95, 0x6c, 0xc8, 0x42, 0xd6, 0x11, 0xa0,
+		0xe4, 0x25, 0x28, 0x7a, 0x65, 0xd8, 0x4b, 0x60, 0x75, 0x65, 0x08, 0x0a, 0x21, 0xba, 0x05, 0xa7,
+		0x7b, 0x6e, 0x10, 0xd1, 0xcb, 0x59, 0x6f, 0x1a, 0xb9, 0xd0, 0xf2, 0x70, 0x17, 0x93, 0x88, 0xc0,
+		0x99, 0xde, 0xab, 0x3c, 0x24, 0xe3, 0x21, 0xb9, 0x2e, 0x54, 0x97, 0x33, 0xe3, 0x87, 0xd3, 0xec,
+		0xb9, 0x95, 0x92, 0x4e, 0x33, 0xfd, 0xaa, 0x4f, 0x2d, 0x67, 0x45, 0x0f, 0xa7, 0xd9, 0x7b, 0xdb,
+		0x21, 0x9d, 0xa6, 0xe4, 0xfa, 0x47, 0x5d, 0xce, 0x8c, 0x1f, 0x0a, 0xdd, 0xc6, 0x19, 0x85, 0x6e,
+		0xe3, 0xe1, 0x84, 0x4a, 0x6f, 0x1c, 0xbe, 0x0b, 0xd3, 0x69, 0xad, 0x7b, 0x54, 0x91, 0x5a, 0x4c,
+		0x7a, 0xeb, 0xa0, 0xae, 0x0e, 0x45, 0x13, 0x89, 0xae, 0xe9, 0x9d, 0x6c, 0x69, 0x74, 0xed, 0x7b,
+		0x95, 0xa0, 0x5e, 0x1d, 0x92, 0x2a, 0x34, 0x44, 0x5a, 0x27, 0x58, 0x6a, 0x88, 0x3e, 0xbd, 0x75,
+		0x75, 0x75, 0x28, 0x1a, 0xa1, 0xc0, 0xa7, 0x0a, 0x5c, 0x18, 0xd8, 0x6b, 0x44, 0x6f, 0xc9, 0x67,
+		0x97, 0xa9, 0x25, 0xab, 0xde, 0x38, 0x3e, 0x83, 0xd0, 0x4f, 0x7b, 0x7b, 0x83, 0x52, 0x3f, 0x95,
+		0xb4, 0x31, 0xd5, 0xe5, 0xcc, 0xf8, 0x61, 0x39, 0x9b, 0xd2, 0xaf, 0x93, 0x96, 0xb3, 0xf2, 0x56,
+		0xa3, 0x5a, 0x19, 0x86, 0x24, 0xba, 0x4b, 0x92, 0x7d, 0xb8, 0x3e, 0xbb, 0x44, 0xda, 0x3a, 0x54,
+		0x57, 0x87, 0xa2, 0x11, 0x0a, 0x74, 0x60, 0x2a, 0xd1, 0x3d, 0x41, 0x32, 0x23, 0xca, 0x9a, 0x34,
+		0xea, 0x2b, 0xd9, 0x09, 0x84, 0xdc, 0x47, 0x30, 0x19, 0x6f, 0xe6, 0x21, 0x79, 0x9a, 0x92, 0xb5,
+		0x21, 0xd5, 0xca, 0x30, 0x24, 0x42, 0xf0, 0xc7, 0x0a, 0x14, 0x83, 0x7e, 0x58, 0xd5, 0xf5, 0xbc,
+		0x76, 0xab, 0x5b, 0xad, 0xa1, 0xd5, 0x7e, 0xfc, 0x24, 0x4d, 0x3d, 0xf5, 0xd5, 0xe1, 0x88, 0x22,
+		0xd1, 0x29, 0xbd, 0xd3, 0x20, 0x8d, 0x4e, 0x7d, 0x7b, 0x24, 0xea, 0xd5, 0x21, 0xa9, 0xc2, 0x22,
+		0x23, 0xd9, 0x7c, 0x90, 0x16, 0x19, 0xd2, 0xce, 0x87, 0xba, 0x32, 0x04, 0x45, 0xb8, 0xf3, 0x52,
+		0xda, 0x10, 0x52, 0x3f, 0x90, 0x77, 0x41, 0xd4, 0xca, 0x30, 0x24, 0x61, 0xb0, 0xe9, 0x6d, 0x39,
+		0x48, 0x83, 0x8d, 0xa4, 0x1d, 0xa2, 0x2e, 0x67, 0xc6, 0xe7, 0x42, 0x6f, 0xae, 0xfd, 0xe5, 0xf3,
+		0x05, 0xe5, 0xb3, 0xcf, 0x17, 0x94, 0xbf, 0x7f, 0xbe, 0xa0, 0x7c, 0x7d, 0x75, 0xcf, 0xf6, 0xf7,
+		0xdb, 0xb5, 0x72, 0xdd, 0x6d, 0x2e, 0xc7, 0xfe, 0xd3, 0x56, 0xde, 0xc3, 0x0e, 0xff, 0xdb, 0x5e,
+		0xf7, 0x3f, 0x81, 0xd7, 0xd9, 0x8f, 0xce, 0x4a, 0x6d, 0x94, 0xc1, 0x57, 0xff, 0x3b, 0x00, 0x64,
+		0xb0, 0x1c, 0x83, 0x3b, 0x38, 0x00, 0x00,
 	},
 	// google/protobuf/timestamp.proto
 	[]byte{
@@ -239,6 +239,50 @@ func (c *clientImpl) PurgeDLQMessages(
 	return c.client.PurgeDLQMessages(ctx, request, opts...)
 }
 
+func (c *clientImpl) ForceUpdateDLQAckLevel(
+	ctx context.Context,
+	request *types.ForceUpdateDLQAckLevelRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.ForceUpdateDLQAckLevel(ctx, request, opts...)
+}
+
+func (c *clientImpl) PauseDLQProcessing(
+	ctx context.Context,
+	request *types.PauseDLQProcessingRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.PauseDLQProcessing(ctx, request, opts...)
+}
+
+func (c *clientImpl) ResumeDLQProcessing(
+	ctx context.Context,
+	request *types.ResumeDLQProcessingRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.ResumeDLQProcessing(ctx, request, opts...)
+}
+
+func (c *clientImpl) ListDLQConsumers(
+	ctx context.Context,
+	request *types.ListDLQConsumersRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListDLQConsumersResponse, error) {
+
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.ListDLQConsumers(ctx, request, opts...)
+}
+
 func (c *clientImpl) MergeDLQMessages(
 	ctx context.Context,
 	request *types.MergeDLQMessagesRequest,
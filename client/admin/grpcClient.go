@@ -108,6 +108,26 @@ func (g grpcClient) PurgeDLQMessages(ctx context.Context, request *types.PurgeDL
 	return proto.ToError(err)
 }
 
+func (g grpcClient) ForceUpdateDLQAckLevel(ctx context.Context, request *types.ForceUpdateDLQAckLevelRequest, opts ...yarpc.CallOption) error {
+	_, err := g.c.ForceUpdateDLQAckLevel(ctx, proto.FromAdminForceUpdateDLQAckLevelRequest(request), opts...)
+	return proto.ToError(err)
+}
+
+func (g grpcClient) PauseDLQProcessing(ctx context.Context, request *types.PauseDLQProcessingRequest, opts ...yarpc.CallOption) error {
+	_, err := g.c.PauseDLQProcessing(ctx, proto.FromAdminPauseDLQProcessingRequest(request), opts...)
+	return proto.ToError(err)
+}
+
+func (g grpcClient) ResumeDLQProcessing(ctx context.Context, request *types.ResumeDLQProcessingRequest, opts ...yarpc.CallOption) error {
+	_, err := g.c.ResumeDLQProcessing(ctx, proto.FromAdminResumeDLQProcessingRequest(request), opts...)
+	return proto.ToError(err)
+}
+
+func (g grpcClient) ListDLQConsumers(ctx context.Context, request *types.ListDLQConsumersRequest, opts ...yarpc.CallOption) (*types.ListDLQConsumersResponse, error) {
+	response, err := g.c.ListDLQConsumers(ctx, proto.FromAdminListDLQConsumersRequest(request), opts...)
+	return proto.ToAdminListDLQConsumersResponse(response), proto.ToError(err)
+}
+
 func (g grpcClient) ReadDLQMessages(ctx context.Context, request *types.ReadDLQMessagesRequest, opts ...yarpc.CallOption) (*types.ReadDLQMessagesResponse, error) {
 	response, err := g.c.ReadDLQMessages(ctx, proto.FromAdminReadDLQMessagesRequest(request), opts...)
 	return proto.ToAdminReadDLQMessagesResponse(response), proto.ToError(err)
@@ -46,6 +46,10 @@ type Client interface {
 	CountDLQMessages(context.Context, *types.CountDLQMessagesRequest, ...yarpc.CallOption) (*types.CountDLQMessagesResponse, error)
 	MergeDLQMessages(context.Context, *types.MergeDLQMessagesRequest, ...yarpc.CallOption) (*types.MergeDLQMessagesResponse, error)
 	PurgeDLQMessages(context.Context, *types.PurgeDLQMessagesRequest, ...yarpc.CallOption) error
+	ForceUpdateDLQAckLevel(context.Context, *types.ForceUpdateDLQAckLevelRequest, ...yarpc.CallOption) error
+	PauseDLQProcessing(context.Context, *types.PauseDLQProcessingRequest, ...yarpc.CallOption) error
+	ResumeDLQProcessing(context.Context, *types.ResumeDLQProcessingRequest, ...yarpc.CallOption) error
+	ListDLQConsumers(context.Context, *types.ListDLQConsumersRequest, ...yarpc.CallOption) (*types.ListDLQConsumersResponse, error)
 	ReadDLQMessages(context.Context, *types.ReadDLQMessagesRequest, ...yarpc.CallOption) (*types.ReadDLQMessagesResponse, error)
 	ReapplyEvents(context.Context, *types.ReapplyEventsRequest, ...yarpc.CallOption) error
 	RefreshWorkflowTasks(context.Context, *types.RefreshWorkflowTasksRequest, ...yarpc.CallOption) error
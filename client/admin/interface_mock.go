@@ -97,6 +97,26 @@ func (mr *MockClientMockRecorder) CloseShard(arg0, arg1 interface{}, arg2 ...int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseShard", reflect.TypeOf((*MockClient)(nil).CloseShard), varargs...)
 }
 
+// CountDLQMessages mocks base method.
+func (m *MockClient) CountDLQMessages(arg0 context.Context, arg1 *types.CountDLQMessagesRequest, arg2 ...yarpc.CallOption) (*types.CountDLQMessagesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CountDLQMessages", varargs...)
+	ret0, _ := ret[0].(*types.CountDLQMessagesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDLQMessages indicates an expected call of CountDLQMessages.
+func (mr *MockClientMockRecorder) CountDLQMessages(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDLQMessages", reflect.TypeOf((*MockClient)(nil).CountDLQMessages), varargs...)
+}
+
 // DeleteWorkflow mocks base method.
 func (m *MockClient) DeleteWorkflow(arg0 context.Context, arg1 *types.AdminDeleteWorkflowRequest, arg2 ...yarpc.CallOption) (*types.AdminDeleteWorkflowResponse, error) {
 	m.ctrl.T.Helper()
@@ -217,44 +237,43 @@ func (mr *MockClientMockRecorder) DescribeWorkflowExecution(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkflowExecution", reflect.TypeOf((*MockClient)(nil).DescribeWorkflowExecution), varargs...)
 }
 
-// GetCrossClusterTasks mocks base method.
-func (m *MockClient) GetCrossClusterTasks(arg0 context.Context, arg1 *types.GetCrossClusterTasksRequest, arg2 ...yarpc.CallOption) (*types.GetCrossClusterTasksResponse, error) {
+// ForceUpdateDLQAckLevel mocks base method.
+func (m *MockClient) ForceUpdateDLQAckLevel(arg0 context.Context, arg1 *types.ForceUpdateDLQAckLevelRequest, arg2 ...yarpc.CallOption) error {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "GetCrossClusterTasks", varargs...)
-	ret0, _ := ret[0].(*types.GetCrossClusterTasksResponse)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ForceUpdateDLQAckLevel", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetCrossClusterTasks indicates an expected call of GetCrossClusterTasks.
-func (mr *MockClientMockRecorder) GetCrossClusterTasks(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// ForceUpdateDLQAckLevel indicates an expected call of ForceUpdateDLQAckLevel.
+func (mr *MockClientMockRecorder) ForceUpdateDLQAckLevel(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCrossClusterTasks", reflect.TypeOf((*MockClient)(nil).GetCrossClusterTasks), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateDLQAckLevel", reflect.TypeOf((*MockClient)(nil).ForceUpdateDLQAckLevel), varargs...)
 }
 
-// CountDLQMessages mocks base method.
-func (m *MockClient) CountDLQMessages(arg0 context.Context, arg1 *types.CountDLQMessagesRequest, arg2 ...yarpc.CallOption) (*types.CountDLQMessagesResponse, error) {
+// GetCrossClusterTasks mocks base method.
+func (m *MockClient) GetCrossClusterTasks(arg0 context.Context, arg1 *types.GetCrossClusterTasksRequest, arg2 ...yarpc.CallOption) (*types.GetCrossClusterTasksResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "CountDLQMessages", varargs...)
-	ret0, _ := ret[0].(*types.CountDLQMessagesResponse)
+	ret := m.ctrl.Call(m, "GetCrossClusterTasks", varargs...)
+	ret0, _ := ret[0].(*types.GetCrossClusterTasksResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CountDLQMessages indicates an expected call of CountDLQMessages.
-func (mr *MockClientMockRecorder) CountDLQMessages(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// GetCrossClusterTasks indicates an expected call of GetCrossClusterTasks.
+func (mr *MockClientMockRecorder) GetCrossClusterTasks(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDLQMessages", reflect.TypeOf((*MockClient)(nil).CountDLQMessages), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCrossClusterTasks", reflect.TypeOf((*MockClient)(nil).GetCrossClusterTasks), varargs...)
 }
 
 // GetDLQReplicationMessages mocks base method.
@@ -357,6 +376,26 @@ func (mr *MockClientMockRecorder) GetWorkflowExecutionRawHistoryV2(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecutionRawHistoryV2", reflect.TypeOf((*MockClient)(nil).GetWorkflowExecutionRawHistoryV2), varargs...)
 }
 
+// ListDLQConsumers mocks base method.
+func (m *MockClient) ListDLQConsumers(arg0 context.Context, arg1 *types.ListDLQConsumersRequest, arg2 ...yarpc.CallOption) (*types.ListDLQConsumersResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListDLQConsumers", varargs...)
+	ret0, _ := ret[0].(*types.ListDLQConsumersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDLQConsumers indicates an expected call of ListDLQConsumers.
+func (mr *MockClientMockRecorder) ListDLQConsumers(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDLQConsumers", reflect.TypeOf((*MockClient)(nil).ListDLQConsumers), varargs...)
+}
+
 // ListDynamicConfig mocks base method.
 func (m *MockClient) ListDynamicConfig(arg0 context.Context, arg1 *types.ListDynamicConfigRequest, arg2 ...yarpc.CallOption) (*types.ListDynamicConfigResponse, error) {
 	m.ctrl.T.Helper()
@@ -417,6 +456,25 @@ func (mr *MockClientMockRecorder) MergeDLQMessages(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDLQMessages", reflect.TypeOf((*MockClient)(nil).MergeDLQMessages), varargs...)
 }
 
+// PauseDLQProcessing mocks base method.
+func (m *MockClient) PauseDLQProcessing(arg0 context.Context, arg1 *types.PauseDLQProcessingRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PauseDLQProcessing", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseDLQProcessing indicates an expected call of PauseDLQProcessing.
+func (mr *MockClientMockRecorder) PauseDLQProcessing(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseDLQProcessing", reflect.TypeOf((*MockClient)(nil).PauseDLQProcessing), varargs...)
+}
+
 // PurgeDLQMessages mocks base method.
 func (m *MockClient) PurgeDLQMessages(arg0 context.Context, arg1 *types.PurgeDLQMessagesRequest, arg2 ...yarpc.CallOption) error {
 	m.ctrl.T.Helper()
@@ -590,6 +648,25 @@ func (mr *MockClientMockRecorder) RestoreDynamicConfig(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDynamicConfig", reflect.TypeOf((*MockClient)(nil).RestoreDynamicConfig), varargs...)
 }
 
+// ResumeDLQProcessing mocks base method.
+func (m *MockClient) ResumeDLQProcessing(arg0 context.Context, arg1 *types.ResumeDLQProcessingRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeDLQProcessing", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeDLQProcessing indicates an expected call of ResumeDLQProcessing.
+func (mr *MockClientMockRecorder) ResumeDLQProcessing(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeDLQProcessing", reflect.TypeOf((*MockClient)(nil).ResumeDLQProcessing), varargs...)
+}
+
 // UpdateDynamicConfig mocks base method.
 func (m *MockClient) UpdateDynamicConfig(arg0 context.Context, arg1 *types.UpdateDynamicConfigRequest, arg2 ...yarpc.CallOption) error {
 	m.ctrl.T.Helper()
@@ -342,6 +342,74 @@ func (c *metricClient) PurgeDLQMessages(
 	return err
 }
 
+func (c *metricClient) ForceUpdateDLQAckLevel(
+	ctx context.Context,
+	request *types.ForceUpdateDLQAckLevelRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	c.metricsClient.IncCounter(metrics.AdminClientForceUpdateDLQAckLevelScope, metrics.CadenceClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientForceUpdateDLQAckLevelScope, metrics.CadenceClientLatency)
+	err := c.client.ForceUpdateDLQAckLevel(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientForceUpdateDLQAckLevelScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) PauseDLQProcessing(
+	ctx context.Context,
+	request *types.PauseDLQProcessingRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	c.metricsClient.IncCounter(metrics.AdminClientPauseDLQProcessingScope, metrics.CadenceClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientPauseDLQProcessingScope, metrics.CadenceClientLatency)
+	err := c.client.PauseDLQProcessing(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientPauseDLQProcessingScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) ResumeDLQProcessing(
+	ctx context.Context,
+	request *types.ResumeDLQProcessingRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	c.metricsClient.IncCounter(metrics.AdminClientResumeDLQProcessingScope, metrics.CadenceClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientResumeDLQProcessingScope, metrics.CadenceClientLatency)
+	err := c.client.ResumeDLQProcessing(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientResumeDLQProcessingScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) ListDLQConsumers(
+	ctx context.Context,
+	request *types.ListDLQConsumersRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListDLQConsumersResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientListDLQConsumersScope, metrics.CadenceClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientListDLQConsumersScope, metrics.CadenceClientLatency)
+	resp, err := c.client.ListDLQConsumers(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientListDLQConsumersScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
 func (c *metricClient) MergeDLQMessages(
 	ctx context.Context,
 	request *types.MergeDLQMessagesRequest,
@@ -291,6 +291,58 @@ func (c *retryableClient) PurgeDLQMessages(
 	return c.throttleRetry.Do(ctx, op)
 }
 
+func (c *retryableClient) ForceUpdateDLQAckLevel(
+	ctx context.Context,
+	request *types.ForceUpdateDLQAckLevelRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	op := func() error {
+		return c.client.ForceUpdateDLQAckLevel(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) PauseDLQProcessing(
+	ctx context.Context,
+	request *types.PauseDLQProcessingRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	op := func() error {
+		return c.client.PauseDLQProcessing(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) ResumeDLQProcessing(
+	ctx context.Context,
+	request *types.ResumeDLQProcessingRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	op := func() error {
+		return c.client.ResumeDLQProcessing(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) ListDLQConsumers(
+	ctx context.Context,
+	request *types.ListDLQConsumersRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListDLQConsumersResponse, error) {
+
+	var resp *types.ListDLQConsumersResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ListDLQConsumers(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
 func (c *retryableClient) MergeDLQMessages(
 	ctx context.Context,
 	request *types.MergeDLQMessagesRequest,
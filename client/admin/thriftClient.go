@@ -104,6 +104,22 @@ func (t thriftClient) PurgeDLQMessages(ctx context.Context, request *types.Purge
 	return thrift.ToError(err)
 }
 
+func (t thriftClient) ForceUpdateDLQAckLevel(ctx context.Context, request *types.ForceUpdateDLQAckLevelRequest, opts ...yarpc.CallOption) error {
+	return thrift.ToError(&types.BadRequestError{Message: "Feature not supported on TChannel"})
+}
+
+func (t thriftClient) PauseDLQProcessing(ctx context.Context, request *types.PauseDLQProcessingRequest, opts ...yarpc.CallOption) error {
+	return thrift.ToError(&types.BadRequestError{Message: "Feature not supported on TChannel"})
+}
+
+func (t thriftClient) ResumeDLQProcessing(ctx context.Context, request *types.ResumeDLQProcessingRequest, opts ...yarpc.CallOption) error {
+	return thrift.ToError(&types.BadRequestError{Message: "Feature not supported on TChannel"})
+}
+
+func (t thriftClient) ListDLQConsumers(ctx context.Context, request *types.ListDLQConsumersRequest, opts ...yarpc.CallOption) (*types.ListDLQConsumersResponse, error) {
+	return nil, thrift.ToError(&types.BadRequestError{Message: "Feature not supported on TChannel"})
+}
+
 func (t thriftClient) CountDLQMessages(ctx context.Context, request *types.CountDLQMessagesRequest, opts ...yarpc.CallOption) (*types.CountDLQMessagesResponse, error) {
 	return nil, thrift.ToError(&types.BadRequestError{Message: "Feature not supported on TChannel"})
 }
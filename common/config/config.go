@@ -171,6 +171,10 @@ type (
 		// Therefore, the value cannot be changed once set.
 		// TODO This config doesn't belong here, needs refactoring
 		NumHistoryShards int `yaml:"numHistoryShards" validate:"nonzero"`
+		// DomainReplicationDLQNumShards is the number of shards the domain replication DLQ is partitioned into by workflow run ID,
+		// the shard of a message is computed from it. It defaults to 1, must be the same for all the services of the cluster
+		// and cannot be changed once the domain replication DLQ has messages.
+		DomainReplicationDLQNumShards int `yaml:"domainReplicationDLQNumShards"`
 		// DataStores contains the configuration for all datastores
 		DataStores map[string]DataStore `yaml:"datastores"`
 		// TODO: move dynamic config out of static config
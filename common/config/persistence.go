@@ -43,6 +43,10 @@ func (c *Persistence) DefaultStoreType() string {
 
 // FillDefaults populates default values for unspecified fields in persistence config
 func (c *Persistence) FillDefaults() {
+	if c.DomainReplicationDLQNumShards == 0 {
+		c.DomainReplicationDLQNumShards = 1
+	}
+
 	for k, store := range c.DataStores {
 		if store.Cassandra != nil && store.NoSQL == nil {
 			// for backward-compatibility
@@ -73,6 +77,10 @@ func (c *Persistence) FillDefaults() {
 
 // Validate validates the persistence config
 func (c *Persistence) Validate() error {
+	if c.DomainReplicationDLQNumShards < 0 {
+		return fmt.Errorf("persistence config: domainReplicationDLQNumShards must not be negative")
+	}
+
 	dbStoreKeys := []string{c.DefaultStore}
 
 	useAdvancedVisibilityOnly := false
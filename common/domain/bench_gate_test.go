@@ -0,0 +1,194 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+)
+
+const (
+	// benchGateEnv enables the benchmark gate, BENCH_GATE=update updates the baseline instead of checking it
+	benchGateEnv          = "BENCH_GATE"
+	benchGateUpdate       = "update"
+	benchGateBaselineFile = "testdata/bench_baseline.json"
+	// benchGateMaxRegression is how many more allocations than its baseline a gated benchmark may make
+	benchGateMaxRegression = 0.2
+)
+
+// benchmarkResult is the result of a gated benchmark, as stored in the baseline. Only the allocations are gated,
+// the duration of a benchmark depends on the machine it runs on so it can't be compared to a baseline recorded elsewhere.
+type benchmarkResult struct {
+	AllocsPerOp int64 `json:"allocsPerOp"`
+}
+
+// gatedBenchmarks are the benchmarks which fail the tests if they regress from their baseline
+var gatedBenchmarks = map[string]func(*testing.B){
+	"BenchmarkDLQMerge_LargeBatch": BenchmarkDLQMerge_LargeBatch,
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if mode := os.Getenv(benchGateEnv); mode != "" && code == 0 {
+		if err := runBenchmarkGate(mode == benchGateUpdate); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
+// runBenchmarkGate runs the gated benchmarks and compares them to their baseline, or stores them as the baseline
+func runBenchmarkGate(update bool) error {
+	results := make(map[string]benchmarkResult, len(gatedBenchmarks))
+	for name, benchmark := range gatedBenchmarks {
+		result := testing.Benchmark(benchmark)
+		results[name] = benchmarkResult{
+			AllocsPerOp: result.AllocsPerOp(),
+		}
+		fmt.Printf("%v\t%v\t%v\n", name, result.String(), result.MemString())
+	}
+	if update {
+		return writeBenchmarkBaseline(benchGateBaselineFile, results)
+	}
+
+	baseline, err := readBenchmarkBaseline(benchGateBaselineFile)
+	if err != nil {
+		return err
+	}
+	return checkBenchmarkResults(baseline, results)
+}
+
+// checkBenchmarkResults returns an error for each benchmark which regressed from its baseline or has none
+func checkBenchmarkResults(baseline map[string]benchmarkResult, results map[string]benchmarkResult) error {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs error
+	for _, name := range names {
+		expected, ok := baseline[name]
+		if !ok {
+			errs = multierr.Append(errs, fmt.Errorf("%v has no baseline, run make update-bench-baseline", name))
+			continue
+		}
+		result := results[name]
+		if exceedsBaseline(result.AllocsPerOp, expected.AllocsPerOp) {
+			errs = multierr.Append(errs, fmt.Errorf("%v regressed from %v allocs/op to %v allocs/op", name, expected.AllocsPerOp, result.AllocsPerOp))
+		}
+	}
+	return errs
+}
+
+func exceedsBaseline(value int64, baseline int64) bool {
+	return float64(value) > float64(baseline)*(1+benchGateMaxRegression)
+}
+
+func readBenchmarkBaseline(path string) (map[string]benchmarkResult, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark baseline, run make update-bench-baseline: %v", err)
+	}
+	var baseline map[string]benchmarkResult
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to decode benchmark baseline %v: %v", path, err)
+	}
+	return baseline, nil
+}
+
+func writeBenchmarkBaseline(path string, results map[string]benchmarkResult) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func TestCheckBenchmarkResults(t *testing.T) {
+	baseline := map[string]benchmarkResult{
+		"BenchmarkA": {AllocsPerOp: 10},
+	}
+	tests := []struct {
+		name    string
+		results map[string]benchmarkResult
+		wantErr string
+	}{
+		{
+			name:    "fewer allocations",
+			results: map[string]benchmarkResult{"BenchmarkA": {AllocsPerOp: 5}},
+		},
+		{
+			name:    "more allocations within the allowed regression",
+			results: map[string]benchmarkResult{"BenchmarkA": {AllocsPerOp: 12}},
+		},
+		{
+			name:    "more allocations",
+			results: map[string]benchmarkResult{"BenchmarkA": {AllocsPerOp: 13}},
+			wantErr: "BenchmarkA regressed from 10 allocs/op to 13 allocs/op",
+		},
+		{
+			name:    "no baseline",
+			results: map[string]benchmarkResult{"BenchmarkB": {AllocsPerOp: 10}},
+			wantErr: "BenchmarkB has no baseline, run make update-bench-baseline",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBenchmarkResults(baseline, tt.results)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBenchmarkBaseline(t *testing.T) {
+	baseline, err := readBenchmarkBaseline(benchGateBaselineFile)
+	require.NoError(t, err)
+	for name := range gatedBenchmarks {
+		assert.Contains(t, baseline, name)
+	}
+
+	dir, err := ioutil.TempDir("", "bench_gate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "testdata", "bench_baseline.json")
+	require.NoError(t, writeBenchmarkBaseline(path, baseline))
+	written, err := readBenchmarkBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, baseline, written)
+}
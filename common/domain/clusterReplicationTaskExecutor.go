@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	clusterReplicationTaskExecutorImpl struct {
+		defaultExecutor  ReplicationTaskExecutor
+		clusterExecutors map[string]ReplicationTaskExecutor
+	}
+)
+
+var _ ReplicationTaskExecutor = (*clusterReplicationTaskExecutorImpl)(nil)
+
+// NewClusterReplicationTaskExecutor creates a replication task executor which routes every replication task
+// to the executor of its source cluster. Tasks without a source cluster, e.g. persisted before the source cluster
+// was recorded, or from a cluster without an executor are executed by the default executor.
+func NewClusterReplicationTaskExecutor(
+	defaultExecutor ReplicationTaskExecutor,
+	clusterExecutors map[string]ReplicationTaskExecutor,
+) ReplicationTaskExecutor {
+
+	return &clusterReplicationTaskExecutorImpl{
+		defaultExecutor:  defaultExecutor,
+		clusterExecutors: clusterExecutors,
+	}
+}
+
+// ExecuteReplicationTask executes the replication task with the executor of the source cluster
+func (e *clusterReplicationTaskExecutorImpl) ExecuteReplicationTask(task *types.ReplicationTask, sourceCluster string) error {
+	return e.getExecutor(sourceCluster).ExecuteReplicationTask(task, sourceCluster)
+}
+
+// Execute executes the domain replication task with the default executor, as its source cluster is unknown
+func (e *clusterReplicationTaskExecutorImpl) Execute(task *types.DomainTaskAttributes) error {
+	return e.defaultExecutor.Execute(task)
+}
+
+// Overwrite overwrites the domain with the default executor, as its source cluster is unknown
+func (e *clusterReplicationTaskExecutorImpl) Overwrite(task *types.DomainTaskAttributes) error {
+	return e.defaultExecutor.Overwrite(task)
+}
+
+// AddMigration registers the migration with the default executor and the executor of every cluster
+func (e *clusterReplicationTaskExecutorImpl) AddMigration(fromVersion, toVersion int, fn MigrationFunc) error {
+	executors := []ReplicationTaskExecutor{e.defaultExecutor}
+	for _, executor := range e.clusterExecutors {
+		executors = append(executors, executor)
+	}
+	// the default executor may also be the executor of some clusters
+	registered := make(map[ReplicationTaskExecutor]struct{}, len(executors))
+	for _, executor := range executors {
+		if _, ok := registered[executor]; ok {
+			continue
+		}
+		if err := executor.AddMigration(fromVersion, toVersion, fn); err != nil {
+			return err
+		}
+		registered[executor] = struct{}{}
+	}
+	return nil
+}
+
+func (e *clusterReplicationTaskExecutorImpl) getExecutor(sourceCluster string) ReplicationTaskExecutor {
+	if executor, ok := e.clusterExecutors[sourceCluster]; ok && sourceCluster != "" {
+		return executor
+	}
+	return e.defaultExecutor
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestClusterReplicationTaskExecutor_Routing(t *testing.T) {
+	tests := []struct {
+		name            string
+		sourceCluster   string
+		expectedCluster string
+	}{
+		{name: "source cluster B", sourceCluster: "cluster-b", expectedCluster: "cluster-b"},
+		{name: "source cluster C", sourceCluster: "cluster-c", expectedCluster: "cluster-c"},
+		{name: "unknown source cluster", sourceCluster: "cluster-d", expectedCluster: ""},
+		{name: "no source cluster", sourceCluster: "", expectedCluster: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			executors := map[string]*MockReplicationTaskExecutor{
+				"":          NewMockReplicationTaskExecutor(controller),
+				"cluster-b": NewMockReplicationTaskExecutor(controller),
+				"cluster-c": NewMockReplicationTaskExecutor(controller),
+			}
+			task := &types.ReplicationTask{
+				TaskType:      types.ReplicationTaskTypeDomain.Ptr(),
+				SourceTaskID:  11,
+				SourceCluster: tt.sourceCluster,
+			}
+			for cluster, executor := range executors {
+				times := 0
+				if cluster == tt.expectedCluster {
+					times = 1
+				}
+				executor.EXPECT().ExecuteReplicationTask(task, tt.sourceCluster).Return(nil).Times(times)
+			}
+
+			executor := NewClusterReplicationTaskExecutor(
+				executors[""],
+				map[string]ReplicationTaskExecutor{
+					"cluster-b": executors["cluster-b"],
+					"cluster-c": executors["cluster-c"],
+				},
+			)
+			assert.NoError(t, executor.ExecuteReplicationTask(task, tt.sourceCluster))
+		})
+	}
+}
+
+func TestClusterReplicationTaskExecutor_Execute(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defaultExecutor := NewMockReplicationTaskExecutor(controller)
+	clusterExecutor := NewMockReplicationTaskExecutor(controller)
+	attributes := &types.DomainTaskAttributes{ID: "domain-id"}
+	defaultExecutor.EXPECT().Execute(attributes).Return(nil).Times(1)
+	clusterExecutor.EXPECT().Execute(gomock.Any()).Times(0)
+
+	executor := NewClusterReplicationTaskExecutor(
+		defaultExecutor,
+		map[string]ReplicationTaskExecutor{"cluster-b": clusterExecutor},
+	)
+	assert.NoError(t, executor.Execute(attributes))
+}
+
+func TestClusterReplicationTaskExecutor_AddMigration(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defaultExecutor := NewMockReplicationTaskExecutor(controller)
+	clusterExecutor := NewMockReplicationTaskExecutor(controller)
+	executor := NewClusterReplicationTaskExecutor(defaultExecutor, map[string]ReplicationTaskExecutor{
+		"cluster-a": defaultExecutor,
+		"cluster-b": clusterExecutor,
+	})
+	// the migration is registered once with every distinct executor
+	defaultExecutor.EXPECT().AddMigration(0, 1, gomock.Any()).Return(nil).Times(1)
+	clusterExecutor.EXPECT().AddMigration(0, 1, gomock.Any()).Return(nil).Times(1)
+
+	assert.NoError(t, executor.AddMigration(0, 1, func(task *types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		return task, nil
+	}))
+}
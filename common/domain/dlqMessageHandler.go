@@ -24,55 +24,283 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/quotas"
 	"github.com/uber/cadence/common/types"
 )
 
+const (
+	dlqStreamPageSize = 100
+	dlqExpiryInterval = time.Hour
+	// dlqPurgeDefaultBatchSize is large enough for Purge to delete most DLQs in one batch
+	dlqPurgeDefaultBatchSize = 10000
+	// dlqEnqueueBatchSize keeps a batch of domain replication tasks well below the Cassandra batch size limit
+	dlqEnqueueBatchSize = 20
+	// dlqShutdownTimeout bounds how long Stop and Close wait for the background loops to exit
+	dlqShutdownTimeout = time.Minute
+	// dlqWatchdogInterval is how often the watchdog polls the ack level for progress
+	dlqWatchdogInterval = time.Minute
+	// dlqReadRetryInitialInterval and dlqReadRetryMaximumInterval bound the backoff of retried DLQ reads
+	dlqReadRetryInitialInterval = 50 * time.Millisecond
+	dlqReadRetryMaximumInterval = 2 * time.Second
+
+	// CheckpointGranularityPerPage deletes the merged messages and moves the ack level once a page is merged
+	CheckpointGranularityPerPage CheckpointGranularity = "PerPage"
+	// CheckpointGranularityPerTask deletes the merged messages and moves the ack level after every merged message
+	CheckpointGranularityPerTask CheckpointGranularity = "PerTask"
+)
+
 type (
 	// DLQMessageHandler is the interface handles domain DLQ messages
 	DLQMessageHandler interface {
 		common.Daemon
+		io.Closer
+
+		// Shutdown stops the handler and waits for its background loops to finish their current work,
+		// it returns the context error if they are still running once the context is done
+		Shutdown(ctx context.Context) error
 
 		Count(ctx context.Context, forceFetch bool) (int64, error)
-		Read(ctx context.Context, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
-		Purge(ctx context.Context, lastMessageID int64) error
-		Merge(ctx context.Context, lastMessageID int64, pageSize int, pageToken []byte) ([]byte, error)
+		Read(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
+		ReadByDomain(ctx context.Context, domainID string, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
+		ReadByTimeRange(ctx context.Context, startTime time.Time, endTime time.Time, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
+		StreamDLQ(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) (<-chan *types.ReplicationTask, <-chan error)
+		Export(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64, w io.Writer, format ExportFormat) error
+		Import(ctx context.Context, r io.Reader, format ExportFormat) error
+		Purge(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) error
+		PurgeByDomain(ctx context.Context, domainID string, lastMessageID int64) error
+		PurgeDomain(ctx context.Context, domainID string) error
+		PurgeWithBatchSize(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64, batchSize int) (int64, error)
+		DryRunPurge(ctx context.Context, lastMessageID int64) (int64, error)
+		Merge(ctx context.Context, taskType types.ReplicationTaskType, mergeRequestID string, lastMessageID int64, pageSize int, pageToken []byte) ([]byte, error)
+		MergeShard(ctx context.Context, shardID int, shardCount int) error
+		Pause(ctx context.Context) error
+		Resume(ctx context.Context) error
+		DryRunMerge(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) ([]DLQMergePreview, error)
+		SimulateMerge(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) ([]SimulatedOperation, error)
+		Forward(ctx context.Context, destinationCluster string, lastMessageID int64) error
+		Reinject(ctx context.Context, messageID int64) error
+		Replay(ctx context.Context, srcQueue ReplicationQueue, dstQueue ReplicationQueue, lastMessageID int64) error
+		MergeHistory(ctx context.Context, limit int) ([]DLQMergeRecord, error)
+		SetConflictResolutionPolicy(ctx context.Context, domainName string, policy ConflictResolutionPolicy) error
+		GetConflictResolutionPolicies(ctx context.Context) (map[string]ConflictResolutionPolicy, error)
+		GarbageCollectDLQ(ctx context.Context, olderThan time.Duration) error
+		Health() DLQHealth
+		Ready() bool
+	}
+
+	// CheckpointGranularity tells how often a merge persists its progress
+	CheckpointGranularity string
+
+	// DLQHealth is a snapshot of the progress made by the domain DLQ handler
+	DLQHealth struct {
+		LastMergeTime   time.Time `json:"lastMergeTime"`
+		LastMergeCount  int64     `json:"lastMergeCount"`
+		CurrentDLQDepth int64     `json:"currentDLQDepth"`
+		AckLevel        int64     `json:"ackLevel"`
+		// CircuitBreakerState is the state of the circuit breaker protecting the replication task executor
+		CircuitBreakerState DLQCircuitBreakerState `json:"circuitBreakerState"`
+		// State is the state of the merges of the handler
+		State DLQState `json:"state"`
+		// Ready tells whether the handler polled the DLQ and its last poll did not fail persistently
+		Ready bool `json:"ready"`
+		// Paused tells whether the merges of the DLQ shards are paused by an operator
+		Paused bool `json:"paused"`
+	}
+
+	// DLQMergePreview describes a domain DLQ message which would be applied by a merge
+	DLQMergePreview struct {
+		MessageID       int64                     `json:"messageID"`
+		TaskType        types.ReplicationTaskType `json:"taskType"`
+		DomainID        string                    `json:"domainID,omitempty"`
+		DomainName      string                    `json:"domainName,omitempty"`
+		DomainOperation types.DomainOperation     `json:"domainOperation"`
+		FailoverVersion int64                     `json:"failoverVersion"`
+		EnqueuedAt      time.Time                 `json:"enqueuedAt"`
+	}
+
+	// ErrPageSizeExceeded is returned by Read when the requested page size is larger than MaxPageSize
+	ErrPageSizeExceeded struct {
+		MaxPageSize int
+	}
+
+	// ErrMergeTimeout is returned by Merge when merging a page takes longer than the merge timeout.
+	// The messages merged before the timeout are acknowledged up to AckLevel unless the page was merged by priority,
+	// in which case the caller can only rely on MergedCount.
+	ErrMergeTimeout struct {
+		MergedCount int64
+		AckLevel    int64
 	}
 
 	dlqMessageHandlerImpl struct {
-		replicationHandler ReplicationTaskExecutor
-		replicationQueue   ReplicationQueue
-		logger             log.Logger
-		metricsClient      metrics.Client
-		done               chan struct{}
-		status             int32
+		executors        ReplicationTaskExecutorRegistry
+		replicationQueue ReplicationQueue
+		// consumerGroup keeps the ack levels of this handler independent of other consumers of the DLQ
+		consumerGroup         string
+		maxRetryAttempts      dynamicconfig.IntPropertyFn
+		sizeEmitInterval      dynamicconfig.DurationPropertyFn
+		mergeRateLimiter      quotas.Limiter
+		replayRateLimiter     quotas.Limiter
+		deduplicator          *dlqDeduplicator
+		circuitBreaker        *dlqCircuitBreaker
+		backpressure          *dlqBackpressure
+		messageTTL            dynamicconfig.DurationPropertyFn
+		purgeBatchDelay       dynamicconfig.DurationPropertyFn
+		priorityMerge         dynamicconfig.BoolPropertyFn
+		groupedMerge          dynamicconfig.BoolPropertyFn
+		speculativeRetry      dynamicconfig.BoolPropertyFn
+		speculativeBackoff    backoff.RetryPolicy
+		journal               *dlqExecutionJournal
+		journalEnabled        dynamicconfig.BoolPropertyFn
+		versionedAckLevel     dynamicconfig.BoolPropertyFn
+		ackLevelRetries       dynamicconfig.IntPropertyFn
+		maxReadPageSize       dynamicconfig.IntPropertyFn
+		softDelete            dynamicconfig.BoolPropertyFn
+		largeMessageSize      dynamicconfig.IntPropertyFn
+		stalledAckLevel       dynamicconfig.DurationPropertyFn
+		depthAlertThreshold   dynamicconfig.IntPropertyFn
+		mergeTimeout          dynamicconfig.DurationPropertyFn
+		readMaxRetries        dynamicconfig.IntPropertyFn
+		checkpointGranularity dynamicconfig.StringPropertyFn
+		parallelism           dynamicconfig.IntPropertyFn
+		backpressureRetries   dynamicconfig.IntPropertyFn
+		invalidTaskPolicy     dynamicconfig.StringPropertyFn
+		domainFilter          DomainFilterFunc
+		// priorityFunc is nil unless the handler was created WithDomainPriority
+		priorityFunc PriorityFunc
+		// seenTaskIDs is nil unless the handler was created WithSeenTaskIDs
+		seenTaskIDs cache.Cache
+		// locker is nil unless the handler was created WithDistributedLocker
+		locker            DistributedLocker
+		mergeLockTTL      time.Duration
+		notificationHooks []NotificationHook
+		// simulationDomains is nil unless the handler was created WithSimulationMode
+		simulationDomains persistence.DomainManager
+		// dlqArchiver is nil unless the handler was created WithArchival
+		dlqArchiver     DLQArchiver
+		archivalEnabled dynamicconfig.BoolPropertyFn
+		archivalURI     dynamicconfig.StringPropertyFn
+		timeSource      clock.TimeSource
+		logger          log.Logger
+		metricsClient   metrics.Client
+		done            chan struct{}
+		status          int32
+		shutdownWG      sync.WaitGroup
+
+		// metrics scopes of the executed and skipped messages, created once as tagging a scope for every message allocates
+		scopes     map[dlqScopeKey]metrics.Scope
+		scopesLock sync.Mutex
+		// metrics scope of the reads of DLQ pages, created once for the same reason
+		readScope     metrics.Scope
+		readScopeOnce sync.Once
+
+		// growthRateMonitor pauses the merges of the DLQ shards while the DLQ grows faster than maxGrowthRate
+		growthRateMonitor  *dlqGrowthRateMonitor
+		growthRateInterval dynamicconfig.DurationPropertyFn
+		maxGrowthRate      dynamicconfig.FloatPropertyFn
+
+		// stateMachine tracks the state of the merges and notifies the observer of the handler of its transitions
+		stateMachine *dlqStateMachine
 
-		mu        sync.Mutex
-		lastCount int64
+		// progress of the handler, accessed atomically
+		lastCount      int64
+		lastMergeTime  int64
+		lastMergeCount int64
+		ackLevel       int64
+		ready          int32
+		paused         int32
+
+		// last ack level read by the watchdog and since when it has not changed, only accessed by the watchdog
+		watchdogAckLevel      int64
+		watchdogAckLevelSince time.Time
 	}
 )
 
-// NewDLQMessageHandler returns a DLQTaskHandler instance
+// NewDLQMessageHandler returns a DLQTaskHandler instance, the options override the defaults of dlqMessageHandlerConfig
 func NewDLQMessageHandler(
-	replicationHandler ReplicationTaskExecutor,
+	executors ReplicationTaskExecutorRegistry,
 	replicationQueue ReplicationQueue,
 	logger log.Logger,
-	metricsClient metrics.Client,
+	opts ...DLQOption,
 ) DLQMessageHandler {
+
+	config := defaultDLQMessageHandlerConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	circuitBreakerScope := config.metricsClient.Scope(metrics.DomainReplicationQueueScope, metrics.DestinationClusterTag(config.destinationCluster))
 	return &dlqMessageHandlerImpl{
-		replicationHandler: replicationHandler,
-		replicationQueue:   replicationQueue,
-		logger:             logger,
-		metricsClient:      metricsClient,
-		done:               make(chan struct{}),
-		lastCount:          -1,
+		executors:             executors,
+		replicationQueue:      replicationQueue,
+		consumerGroup:         config.consumerGroup,
+		maxRetryAttempts:      config.maxRetryAttempts,
+		sizeEmitInterval:      config.sizeEmitInterval,
+		mergeRateLimiter:      newDLQRateLimiter(config.mergeRPS.AsFloat64()),
+		replayRateLimiter:     newDLQRateLimiter(config.replayRPS.AsFloat64()),
+		deduplicator:          newDLQDeduplicator(config.deduplicationWindowSize, config.deduplicationFalsePositiveRate),
+		seenTaskIDs:           newDLQSeenTaskIDs(config.seenTaskIDsCapacity),
+		circuitBreaker:        newDLQCircuitBreaker(dlqCircuitBreakerFailureThreshold, dlqCircuitBreakerOpenTimeout, config.timeSource, circuitBreakerScope, logger),
+		backpressure:          newDLQBackpressure(dlqBackpressureInitialInterval, dlqBackpressureMaximumInterval),
+		messageTTL:            config.messageTTL,
+		purgeBatchDelay:       config.purgeBatchDelay,
+		priorityMerge:         config.priorityMergeEnabled,
+		groupedMerge:          config.groupedMergeEnabled,
+		speculativeRetry:      config.speculativeRetryEnabled,
+		speculativeBackoff:    newDLQSpeculativeRetryPolicy(),
+		journal:               newDLQExecutionJournal(replicationQueue, config.consumerGroup),
+		journalEnabled:        config.executionJournalEnabled,
+		versionedAckLevel:     config.versionedAckLevelEnabled,
+		ackLevelRetries:       config.ackLevelMaxConflictRetries,
+		maxReadPageSize:       config.maxReadPageSize,
+		softDelete:            config.softDeleteEnabled,
+		largeMessageSize:      config.largeMessageThresholdBytes,
+		stalledAckLevel:       config.stalledAckThreshold,
+		depthAlertThreshold:   config.depthAlertThreshold,
+		mergeTimeout:          config.mergeTimeout,
+		readMaxRetries:        config.readMaxRetries,
+		checkpointGranularity: config.checkpointGranularity,
+		parallelism:           config.parallelism,
+		backpressureRetries:   config.backpressureMaxAttempts,
+		invalidTaskPolicy:     config.invalidTaskPolicy,
+		domainFilter:          config.domainFilter,
+		priorityFunc:          config.priorityFunc,
+		locker:                config.locker,
+		mergeLockTTL:          dlqMergeLockTTL,
+		notificationHooks:     config.notificationHooks,
+		simulationDomains:     config.simulationDomains,
+		dlqArchiver:           config.archiver,
+		archivalEnabled:       config.archivalEnabled,
+		archivalURI:           config.archivalURI,
+		growthRateMonitor:     newDLQGrowthRateMonitor(dlqGrowthRateWindowSize),
+		growthRateInterval:    config.growthRateSampleInterval,
+		maxGrowthRate:         config.maxGrowthRate,
+		stateMachine:          newDLQStateMachine(config.stateChangeObserver, config.metricsClient),
+		timeSource:            config.timeSource,
+		logger:                logger,
+		metricsClient:         config.metricsClient,
+		scopes:                make(map[dlqScopeKey]metrics.Scope),
+		done:                  make(chan struct{}),
+		lastCount:             -1,
+		ackLevel:              common.EmptyMessageID,
 	}
 }
 
@@ -82,165 +310,2144 @@ func (d *dlqMessageHandlerImpl) Start() {
 		return
 	}
 
+	if d.journalEnabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), dlqJournalRecoveryTimeout)
+		if err := d.recoverJournal(ctx); err != nil {
+			// the first journaled execution retries the recovery
+			d.logger.Error("Failed to recover domain DLQ execution journal.", tag.Error(err))
+		}
+		cancel()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dlqPausedStateLoadTimeout)
+	if err := d.loadPausedState(ctx); err != nil {
+		d.logger.Error("Failed to load domain DLQ paused state.", tag.Error(err))
+	}
+	cancel()
+	d.shutdownWG.Add(4)
 	go d.emitDLQSizeMetricsLoop()
+	go d.expireMessagesLoop()
+	go d.watchdog()
+	go d.growthRateMonitorLoop()
 	d.logger.Info("Domain DLQ handler started.")
 }
 
 // Stop stops the DLQ handler
 func (d *dlqMessageHandlerImpl) Stop() {
-	if !atomic.CompareAndSwapInt32(&d.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
-		return
+	if err := d.Close(); err != nil {
+		d.logger.Warn("Domain DLQ handler timed out on shutdown.", tag.LifeCycleStopTimedout)
 	}
+}
 
-	d.logger.Info("Domain DLQ handler shutting down.")
-	close(d.done)
+// Close stops the DLQ handler, waiting up to a minute for the background loops to exit
+func (d *dlqMessageHandlerImpl) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), dlqShutdownTimeout)
+	defer cancel()
+	return d.Shutdown(ctx)
+}
+
+// Shutdown stops the DLQ handler and waits for the background loops to exit
+func (d *dlqMessageHandlerImpl) Shutdown(ctx context.Context) error {
+	if atomic.CompareAndSwapInt32(&d.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		d.logger.Info("Domain DLQ handler shutting down.")
+		close(d.done)
+	}
+	return awaitShutdown(ctx, &d.shutdownWG)
 }
 
 // Count counts domain replication DLQ messages
 func (d *dlqMessageHandlerImpl) Count(ctx context.Context, forceFetch bool) (int64, error) {
-	if forceFetch || d.lastCount == -1 {
+	if forceFetch || atomic.LoadInt64(&d.lastCount) == -1 {
 		if err := d.fetchAndEmitDLQSize(ctx); err != nil {
 			return 0, err
 		}
 	}
-	return d.lastCount, nil
+	return atomic.LoadInt64(&d.lastCount), nil
+}
+
+// Health returns the progress made by the DLQ handler
+func (d *dlqMessageHandlerImpl) Health() DLQHealth {
+	health := DLQHealth{
+		LastMergeCount:  atomic.LoadInt64(&d.lastMergeCount),
+		CurrentDLQDepth: atomic.LoadInt64(&d.lastCount),
+		AckLevel:        atomic.LoadInt64(&d.ackLevel),
+
+		CircuitBreakerState: d.circuitBreaker.currentState(),
+		State:               d.stateMachine.currentState(),
+		Ready:               d.Ready(),
+		Paused:              d.isPaused(),
+	}
+	if lastMergeTime := atomic.LoadInt64(&d.lastMergeTime); lastMergeTime != 0 {
+		health.LastMergeTime = time.Unix(0, lastMergeTime).UTC()
+	}
+	return health
 }
 
-// ReadMessages reads domain replication DLQ messages
+// Ready returns true once the handler polled the DLQ successfully, and false again
+// as long as its last poll failed with an error which is not transient
+func (d *dlqMessageHandlerImpl) Ready() bool {
+	return atomic.LoadInt32(&d.ready) == 1
+}
+
+func (e *ErrPageSizeExceeded) Error() string {
+	return fmt.Sprintf("domain DLQ page size exceeds the maximum of %v", e.MaxPageSize)
+}
+
+func (e *ErrMergeTimeout) Error() string {
+	return fmt.Sprintf("domain DLQ merge timed out after merging %v messages, ack level is %v", e.MergedCount, e.AckLevel)
+}
+
+// ReadMessages reads domain replication DLQ messages, a non-positive page size reads a page of the maximum size
 func (d *dlqMessageHandlerImpl) Read(
 	ctx context.Context,
+	taskType types.ReplicationTaskType,
 	lastMessageID int64,
 	pageSize int,
 	pageToken []byte,
 ) ([]*types.ReplicationTask, []byte, error) {
 
-	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx)
+	pageSize, err := d.clampPageSize(pageSize)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return d.replicationQueue.GetMessagesFromDLQ(
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, taskType, d.consumerGroup)
+	if err != nil {
+		return nil, nil, newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+
+	messages, token, err := d.getMessagesFromDLQ(
 		ctx,
+		d.replicationQueue,
+		taskType,
 		ackLevel,
 		lastMessageID,
 		pageSize,
 		pageToken,
 	)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.emitDLQMessageSizes(ctx, messages)
+	return messages, token, nil
 }
 
-// PurgeMessages purges domain replication DLQ messages
-func (d *dlqMessageHandlerImpl) Purge(
+// ReadByDomain reads the domain replication DLQ messages of a single domain,
+// a non-positive page size reads a page of the maximum size
+func (d *dlqMessageHandlerImpl) ReadByDomain(
 	ctx context.Context,
+	domainID string,
 	lastMessageID int64,
-) error {
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
 
-	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx)
+	pageSize, err := d.clampPageSize(pageSize)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	if err := d.replicationQueue.RangeDeleteMessagesFromDLQ(
-		ctx,
-		ackLevel,
-		lastMessageID,
-	); err != nil {
-		return err
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
+	if err != nil {
+		return nil, nil, newDLQError(ErrDLQAckLevelNotFound, err)
 	}
 
-	if err := d.replicationQueue.UpdateDLQAckLevel(
+	messages, token, err := d.replicationQueue.GetMessagesFromDLQByDomain(
 		ctx,
+		domainID,
+		ackLevel,
 		lastMessageID,
-	); err != nil {
-		d.logger.Error("Failed to update DLQ ack level after purging messages", tag.Error(err))
+		pageSize,
+		pageToken,
+	)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	return nil
+	d.emitDLQMessageSizes(ctx, messages)
+	return messages, token, nil
 }
 
-// MergeMessages merges domain replication DLQ messages
-func (d *dlqMessageHandlerImpl) Merge(
+// ReadByTimeRange reads the domain replication DLQ messages enqueued between startTime, inclusive, and endTime, exclusive,
+// a non-positive page size reads a page of the maximum size. A page may hold fewer messages than the page size
+// while there are more messages in the time range, the messages are read until there is no next page token.
+func (d *dlqMessageHandlerImpl) ReadByTimeRange(
 	ctx context.Context,
-	lastMessageID int64,
+	startTime time.Time,
+	endTime time.Time,
 	pageSize int,
 	pageToken []byte,
-) ([]byte, error) {
+) ([]*types.ReplicationTask, []byte, error) {
 
-	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx)
+	if !startTime.Before(endTime) {
+		return nil, nil, &types.BadRequestError{Message: fmt.Sprintf("invalid domain DLQ time range from %v to %v", startTime, endTime)}
+	}
+	pageSize, err := d.clampPageSize(pageSize)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
+	if err != nil {
+		return nil, nil, newDLQError(ErrDLQAckLevelNotFound, err)
 	}
 
-	messages, token, err := d.replicationQueue.GetMessagesFromDLQ(
+	messages, token, err := d.replicationQueue.GetMessagesFromDLQByTimeRange(
 		ctx,
+		startTime,
+		endTime,
 		ackLevel,
-		lastMessageID,
+		common.EndMessageID,
 		pageSize,
 		pageToken,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	d.emitDLQMessageSizes(ctx, messages)
+	return messages, token, nil
+}
 
-	var ackedMessageID int64
-	for _, message := range messages {
-		domainTask := message.GetDomainTaskAttributes()
-		if domainTask == nil {
-			return nil, &types.InternalServiceError{Message: "Encounter non domain replication task in domain replication queue."}
+func (d *dlqMessageHandlerImpl) clampPageSize(pageSize int) (int, error) {
+	maxPageSize := d.maxReadPageSize()
+	if pageSize <= 0 {
+		return maxPageSize, nil
+	}
+	if pageSize > maxPageSize {
+		return 0, &ErrPageSizeExceeded{MaxPageSize: maxPageSize}
+	}
+	return pageSize, nil
+}
+
+// StreamDLQ reads domain replication DLQ messages page by page in the background.
+// The task channel is closed once all messages are read, the error channel
+// receives at most one error and is closed after the task channel.
+func (d *dlqMessageHandlerImpl) StreamDLQ(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+) (<-chan *types.ReplicationTask, <-chan error) {
+
+	return d.streamDLQ(ctx, taskType, lastMessageID, nil)
+}
+
+// streamDLQ streams the messages like StreamDLQ, the stream stops with the error returned by beforeNextPage
+// if it is not nil and fails before a page other than the first one is read
+func (d *dlqMessageHandlerImpl) streamDLQ(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+	beforeNextPage func() error,
+) (<-chan *types.ReplicationTask, <-chan error) {
+
+	pageSize := dlqStreamPageSize
+	if maxPageSize := d.maxReadPageSize(); maxPageSize < pageSize {
+		pageSize = maxPageSize
+	}
+	taskCh := make(chan *types.ReplicationTask, pageSize)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer close(taskCh)
+
+		var pageToken []byte
+		for {
+			if err := ctx.Err(); err != nil {
+				errCh <- err
+				return
+			}
+
+			tasks, token, err := d.Read(ctx, taskType, lastMessageID, pageSize, pageToken)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			for _, task := range tasks {
+				select {
+				case taskCh <- task:
+				case <-ctx.Done():
+					errCh <- ctx.Err()
+					return
+				}
+			}
+
+			if len(token) == 0 {
+				return
+			}
+			if beforeNextPage != nil {
+				if err := beforeNextPage(); err != nil {
+					errCh <- err
+					return
+				}
+			}
+			pageToken = token
 		}
+	}()
+	return taskCh, errCh
+}
 
-		// TODO:
-		if err := d.replicationHandler.Execute(
-			domainTask,
-		); err != nil {
-			return nil, err
+// Export writes domain replication DLQ messages to the writer without merging them
+func (d *dlqMessageHandlerImpl) Export(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+	w io.Writer,
+	format ExportFormat,
+) error {
+
+	encode, err := newDLQTaskEncoder(w, format)
+	if err != nil {
+		return err
+	}
+
+	// cancel the stream if the export stops early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	taskCh, errCh := d.StreamDLQ(ctx, taskType, lastMessageID)
+	for task := range taskCh {
+		if err := encode(task); err != nil {
+			return err
 		}
-		ackedMessageID = message.SourceTaskID
 	}
+	return <-errCh
+}
 
-	if err := d.replicationQueue.RangeDeleteMessagesFromDLQ(
-		ctx,
-		ackLevel,
-		ackedMessageID,
-	); err != nil {
-		d.logger.Error("failed to delete merged tasks on merging domain DLQ message", tag.Error(err))
-		return nil, err
+// Import re-enqueues exported domain replication DLQ messages from the reader in batches
+func (d *dlqMessageHandlerImpl) Import(
+	ctx context.Context,
+	r io.Reader,
+	format ExportFormat,
+) error {
+
+	decode, err := newDLQTaskDecoder(r, format)
+	if err != nil {
+		return err
 	}
-	if err := d.replicationQueue.UpdateDLQAckLevel(ctx, ackedMessageID); err != nil {
-		d.logger.Error("failed to update ack level on merging domain DLQ message", tag.Error(err))
+
+	batch := make([]*types.ReplicationTask, 0, dlqEnqueueBatchSize)
+	for {
+		task, err := decode()
+		if err == io.EOF {
+			if err := d.replicationQueue.EnqueueBatch(ctx, batch); err != nil {
+				return newDLQError(ErrDLQQueueFull, err)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		batch = append(batch, task)
+		if len(batch) == dlqEnqueueBatchSize {
+			if err := d.replicationQueue.EnqueueBatch(ctx, batch); err != nil {
+				return newDLQError(ErrDLQQueueFull, err)
+			}
+			batch = make([]*types.ReplicationTask, 0, dlqEnqueueBatchSize)
+		}
 	}
+}
 
-	return token, nil
+// PurgeMessages purges domain replication DLQ messages
+func (d *dlqMessageHandlerImpl) Purge(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+) error {
+
+	_, err := d.PurgeWithBatchSize(ctx, taskType, lastMessageID, dlqPurgeDefaultBatchSize)
+	return err
 }
 
-func (d *dlqMessageHandlerImpl) emitDLQSizeMetricsLoop() {
-	ticker := time.NewTicker(queueSizeQueryInterval)
-	defer ticker.Stop()
+// PurgeByDomain purges the domain replication DLQ messages of a single domain.
+// The ack level is not moved since the messages of other domains remain in the DLQ.
+func (d *dlqMessageHandlerImpl) PurgeByDomain(
+	ctx context.Context,
+	domainID string,
+	lastMessageID int64,
+) error {
+
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
+	if err != nil {
+		return newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+
+	if err := d.replicationQueue.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, ackLevel, lastMessageID); err != nil {
+		return newDLQDeleteError(err)
+	}
+
+	d.logger.Info("Purged domain DLQ messages of a domain.",
+		tag.WorkflowDomainID(domainID),
+		tag.DLQAckLevel(ackLevel),
+		tag.DLQLastMessageID(lastMessageID),
+	)
+	return nil
+}
+
+// PurgeDomain purges all the domain replication DLQ messages of a deleted domain, which can no longer be merged.
+// The ack level is not moved since the messages of other domains remain in the DLQ.
+func (d *dlqMessageHandlerImpl) PurgeDomain(
+	ctx context.Context,
+	domainID string,
+) error {
 
+	if err := d.replicationQueue.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, common.EmptyMessageID, math.MaxInt64); err != nil {
+		return newDLQDeleteError(err)
+	}
+
+	d.logger.Info("Purged domain DLQ messages of a deleted domain.", tag.WorkflowDomainID(domainID))
+	return nil
+}
+
+// PurgeWithBatchSize purges domain replication DLQ messages in batches of at most batchSize messages,
+// waiting for the configured batch delay between two batches to keep the load on the persistence low.
+// The ack level is moved after every batch, so an interrupted purge resumes where it stopped.
+// It returns the number of purged messages.
+func (d *dlqMessageHandlerImpl) PurgeWithBatchSize(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+	batchSize int,
+) (int64, error) {
+
+	if batchSize <= 0 {
+		return 0, &types.BadRequestError{Message: fmt.Sprintf("invalid purge batch size %v", batchSize)}
+	}
+
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, taskType, d.consumerGroup)
+	if err != nil {
+		return 0, newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+
+	var purgedCount int64
+	purgedLevel := ackLevel
+	var pageToken []byte
 	for {
-		select {
-		case <-d.done:
-			return
-		case <-ticker.C:
-			err := d.fetchAndEmitDLQSize(context.Background())
-			if err != nil {
-				d.logger.Warn("Failed to get DLQ size.", tag.Error(err))
+		tasks, token, err := d.getMessagesFromDLQ(ctx, d.replicationQueue, taskType, ackLevel, lastMessageID, batchSize, pageToken)
+		if err != nil {
+			return purgedCount, err
+		}
+
+		if len(tasks) > 0 {
+			batchLastMessageID := tasks[len(tasks)-1].SourceTaskID
+			if err := d.archiveMessages(ctx, taskType, purgedLevel, batchLastMessageID, tasks); err != nil {
+				return purgedCount, newDLQError(ErrDLQArchivalFailed, err)
 			}
+			if err := d.rangeDeleteMessages(
+				ctx,
+				taskType,
+				purgedLevel,
+				batchLastMessageID,
+			); err != nil {
+				return purgedCount, newDLQDeleteError(err)
+			}
+			purgedCount += int64(len(tasks))
+			d.updatePurgeAckLevel(ctx, taskType, purgedLevel, batchLastMessageID)
+			purgedLevel = batchLastMessageID
+		}
+
+		if len(token) == 0 {
+			break
+		}
+		pageToken = token
+
+		select {
+		case <-ctx.Done():
+			return purgedCount, ctx.Err()
+		case <-time.After(d.purgeBatchDelay()):
 		}
 	}
+
+	// no message is left up to the last message ID, so the ack level can skip the rest of the range
+	if purgedLevel != lastMessageID {
+		d.updatePurgeAckLevel(ctx, taskType, purgedLevel, lastMessageID)
+	}
+
+	return purgedCount, nil
 }
 
-func (d *dlqMessageHandlerImpl) fetchAndEmitDLQSize(ctx context.Context) error {
-	size, err := d.replicationQueue.GetDLQSize(ctx)
+// DryRunPurge counts the domain replication DLQ messages which Purge would delete up to the last message ID,
+// without deleting them or moving the ack level.
+func (d *dlqMessageHandlerImpl) DryRunPurge(
+	ctx context.Context,
+	lastMessageID int64,
+) (int64, error) {
+
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
 	if err != nil {
-		d.metricsClient.Scope(metrics.DomainReplicationQueueScope).IncCounter(metrics.DomainReplicationQueueSizeErrorCount)
-		return err
+		return 0, newDLQError(ErrDLQAckLevelNotFound, err)
 	}
 
-	d.metricsClient.Scope(metrics.DomainReplicationQueueScope).UpdateGauge(metrics.DomainReplicationQueueSizeGauge, float64(size))
+	var willDeleteCount int64
+	var pageToken []byte
+	for {
+		tasks, token, err := d.getMessagesFromDLQ(ctx, d.replicationQueue, AllTaskTypes, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, pageToken)
+		if err != nil {
+			return 0, err
+		}
+		willDeleteCount += int64(len(tasks))
+
+		if len(token) == 0 {
+			return willDeleteCount, nil
+		}
+		pageToken = token
+	}
+}
 
-	d.mu.Lock()
-	d.lastCount = size
-	d.mu.Unlock()
+func (d *dlqMessageHandlerImpl) updatePurgeAckLevel(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	ackLevel int64,
+	lastMessageID int64,
+) {
 
-	return nil
+	if err := d.replicationQueue.UpdateDLQAckLevel(
+		ctx,
+		taskType,
+		d.consumerGroup,
+		lastMessageID,
+	); err != nil {
+		d.logger.Error("Failed to update DLQ ack level after purging messages",
+			dlqTaskTypeTag(taskType),
+			tag.DLQAckLevel(ackLevel),
+			tag.DLQLastMessageID(lastMessageID),
+			tag.Error(err))
+	}
+}
+
+// MergeMessages merges domain replication DLQ messages.
+// A non-empty mergeRequestID fences every executed message, so re-driving an interrupted
+// merge with the same request ID skips the messages that were already applied.
+// A page which takes longer than the merge timeout is interrupted with ErrMergeTimeout.
+// A page of every task type is merged group by group instead if grouped merges are enabled, see mergeGroups.
+// With speculative retries, a message which fails with a transient error is retried in the background while the
+// rest of the page is merged, and the page is rolled back with ErrDLQSpeculativeRetryFailed if the retry fails.
+func (d *dlqMessageHandlerImpl) Merge(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	mergeRequestID string,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]byte, error) {
+
+	d.stateMachine.begin()
+	defer d.stateMachine.end()
+
+	// the messages of every task type are grouped together, so a merge of a single task type is not grouped
+	if taskType == AllTaskTypes && d.groupedMerge() {
+		return d.mergeGroups(ctx, lastMessageID, pageSize, pageToken)
+	}
+
+	// every step of the merge is correlated by the trace ID of the merge
+	ctx, traceID := contextWithDLQMergeTraceID(ctx)
+	logger := d.contextLogger(ctx)
+	startTime := d.timeSource.Now()
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, taskType, d.consumerGroup)
+	if err != nil {
+		return nil, newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+
+	// only reading and executing the page is bound by the merge timeout,
+	// progress is persisted with the context of the caller so it is kept on timeout
+	pageCtx := ctx
+	if timeout := d.mergeTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		pageCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	priorityMerge := d.priorityMerge()
+	mergeQueue := d.replicationQueue
+	if priorityMerge {
+		mergeQueue = NewPriorityReplicationQueue(d.replicationQueue)
+	}
+	messages, token, err := d.getMessagesFromDLQ(
+		pageCtx,
+		mergeQueue,
+		taskType,
+		ackLevel,
+		lastMessageID,
+		pageSize,
+		pageToken,
+	)
+	if err != nil {
+		if isMergeTimeout(ctx, pageCtx) {
+			return nil, &ErrMergeTimeout{AckLevel: ackLevel}
+		}
+		return nil, err
+	}
+	d.emitDLQMessageSizes(ctx, messages)
+	if !priorityMerge {
+		// messages are merged in the order they were enqueued regardless of the order the queue returns them in
+		sortDLQMessagesByID(messages)
+	}
+	if d.priorityFunc != nil {
+		sortDLQMessagesByDomainPriority(messages, d.priorityFunc, func(message *types.ReplicationTask, err error) {
+			logger.Warn("Failed to get domain priority of domain DLQ message", append(dlqMessageTags(message), tag.Error(err))...)
+		})
+		// the page is merged out of the order of the message IDs like a page merged by priority
+		priorityMerge = true
+	}
+	// nacked messages are at the tail of the DLQ, the merge stops before the first one which is not visible yet
+	messages, deferred := deferRetryingMessages(messages, d.timeSource.Now())
+	if deferred {
+		token = nil
+	}
+
+	executedIndex := -1
+	if mergeRequestID != "" {
+		fence, err := d.replicationQueue.GetDLQMergeFence(ctx, taskType, d.consumerGroup)
+		if err != nil {
+			return nil, err
+		}
+		if fence != nil && fence.RequestID == mergeRequestID {
+			executedIndex = getExecutedMessageIndex(messages, fence.MessageID)
+		}
+	}
+
+	// messages of the page are only deleted once every message of the page is merged or skipped,
+	// so a page which failed part way is kept as a whole and can be retried safely.
+	// Pages are not ordered by message ID when merged by priority, so the page is acknowledged up to its highest message ID.
+	progress := dlqMergeProgress{
+		taskType:      taskType,
+		startAckLevel: ackLevel,
+		ackLevel:      ackLevel,
+		startTime:     startTime,
+		traceID:       traceID,
+	}
+	// the merged messages are only a prefix of the page if the page is merged in the order of the message IDs,
+	// and a page with speculative retries is acknowledged as a whole as it is rolled back if a retry fails
+	speculativeRetry := d.speculativeRetry()
+	checkpointPerTask := !priorityMerge && !speculativeRetry && CheckpointGranularity(d.checkpointGranularity()) == CheckpointGranularityPerTask
+	// messages retried speculatively, a page is only acknowledged once all of them succeeded
+	var speculative []*dlqSpeculativeRetry
+	speculativeCtx := pageCtx
+	if speculativeRetry {
+		var cancel context.CancelFunc
+		speculativeCtx, cancel = context.WithCancel(pageCtx)
+		defer func() {
+			// the retries are abandoned if the merge stops before the end of the page
+			cancel()
+			for _, retry := range speculative {
+				<-retry.done
+			}
+		}()
+	}
+	mergeTimedOut := func() error {
+		if priorityMerge || len(speculative) > 0 {
+			// the merged messages are not a prefix of the page, or the page is rolled back
+			// as its speculative retries did not succeed yet, so none of them can be acknowledged
+			return &ErrMergeTimeout{MergedCount: progress.mergedCount, AckLevel: ackLevel}
+		}
+		if err := d.completeMerge(ctx, logger, &progress); err != nil {
+			return err
+		}
+		return &ErrMergeTimeout{MergedCount: progress.mergedCount, AckLevel: progress.ackLevel}
+	}
+	// up to parallelism messages are executed concurrently, while their results are committed in the order of the page,
+	// so the progress of the merge only ever covers the messages before the first one which is still executing
+	parallelism := d.parallelism()
+	pending := make([]dlqPendingMessage, 0, common.MaxInt(parallelism, 1))
+	head := 0
+	defer func() {
+		// messages still executing when the merge stops are not committed and stay in the DLQ
+		for _, p := range pending[head:] {
+			if p.result != nil {
+				<-p.result
+			}
+		}
+	}()
+	commit := func(maxPending int) error {
+		for len(pending)-head > maxPending {
+			p := pending[head]
+			pending[head] = dlqPendingMessage{}
+			head++
+			if p.executed {
+				err := p.err
+				if p.result != nil {
+					err = <-p.result
+				}
+				if speculativeRetry && err != nil && IsTransientError(err) && !isMergeTimeout(ctx, pageCtx) {
+					speculative = append(speculative, d.retrySpeculatively(speculativeCtx, p.message, err))
+					progress.processed(p.message)
+					continue
+				}
+				// the fence stays before the messages retried speculatively, so re-driving the merge executes them again
+				fenceRequestID := mergeRequestID
+				if len(speculative) > 0 {
+					fenceRequestID = ""
+				}
+				if err := d.commitMergedMessage(ctx, logger, pageCtx, fenceRequestID, p.message, err, &progress); err != nil {
+					return err
+				}
+			}
+			progress.processed(p.message)
+			if p.executed && checkpointPerTask {
+				if err := d.checkpointMerge(ctx, logger, &progress); err != nil {
+					return err
+				}
+			}
+		}
+		if head == len(pending) {
+			pending, head = pending[:0], 0
+		}
+		return nil
+	}
+	mergeFailed := func(err error) error {
+		if err == errDLQMergeTimedOut {
+			return mergeTimedOut()
+		}
+		return err
+	}
+
+	for i, message := range messages {
+		if i <= executedIndex {
+			pending = append(pending, dlqPendingMessage{message: message})
+			continue
+		}
+		if isMergeTimeout(ctx, pageCtx) {
+			if err := commit(0); err != nil {
+				return nil, mergeFailed(err)
+			}
+			return nil, mergeTimedOut()
+		}
+		if d.domainFilter != nil && !d.domainFilter(getReplicationTaskDomainID(message)) {
+			logger.Info("Skipping domain DLQ message of a filtered domain", dlqMessageTags(message)...)
+			d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQFilteredMessageCount)
+			pending = append(pending, dlqPendingMessage{message: message})
+			continue
+		}
+		if d.deduplicator.probablySeen(message) || d.isSeenTaskID(message) {
+			logger.Warn("Skipping duplicate domain DLQ message", dlqMessageTags(message)...)
+			d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQDuplicateSkippedCount)
+			pending = append(pending, dlqPendingMessage{message: message})
+			continue
+		}
+		if err := d.mergeRateLimiter.Wait(pageCtx); err != nil {
+			// the rate limiter fails without waiting if the wait would exceed the deadline of the page
+			if isMergeTimeout(ctx, pageCtx) || isMergeDeadlineFirst(ctx, pageCtx) {
+				if err := commit(0); err != nil {
+					return nil, mergeFailed(err)
+				}
+				return nil, mergeTimedOut()
+			}
+			return nil, err
+		}
+
+		executing := dlqPendingMessage{message: message, executed: true}
+		if parallelism > 1 {
+			result := make(chan error, 1)
+			go func(message *types.ReplicationTask) {
+				result <- d.executeJournaled(pageCtx, message)
+			}(message)
+			executing.result = result
+		} else {
+			executing.err = d.executeJournaled(pageCtx, message)
+		}
+		pending = append(pending, executing)
+		if err := commit(parallelism - 1); err != nil {
+			return nil, mergeFailed(err)
+		}
+	}
+	if err := commit(0); err != nil {
+		return nil, mergeFailed(err)
+	}
+	if err := d.awaitSpeculativeRetries(logger, speculative, &progress); err != nil {
+		if isMergeTimeout(ctx, pageCtx) {
+			return nil, &ErrMergeTimeout{MergedCount: progress.mergedCount, AckLevel: ackLevel}
+		}
+		return nil, err
+	}
+
+	if err := d.completeMerge(ctx, logger, &progress); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// commitMergedMessage records the result of executing a message of a page merged by Merge.
+// A message which failed with a transient error is nacked and one which exhausted its retry budget is skipped,
+// any other failure fails the merge.
+func (d *dlqMessageHandlerImpl) commitMergedMessage(
+	ctx context.Context,
+	logger log.Logger,
+	pageCtx context.Context,
+	mergeRequestID string,
+	message *types.ReplicationTask,
+	executeErr error,
+	progress *dlqMergeProgress,
+) error {
+
+	if executeErr != nil {
+		if isMergeTimeout(ctx, pageCtx) {
+			return errDLQMergeTimedOut
+		}
+		switch {
+		case d.skipInvalidMessage(ctx, message, executeErr):
+			progress.failedCount++
+		case IsTransientError(executeErr):
+			if err := d.nackMessage(ctx, message, executeErr); err != nil {
+				return err
+			}
+		case d.skipPoisonedMessage(ctx, message, executeErr):
+			progress.failedCount++
+		default:
+			return newDLQError(ErrDLQExecutorFailed, executeErr)
+		}
+	} else {
+		d.recordMergedMessage(message, progress)
+	}
+	if mergeRequestID != "" {
+		if err := d.replicationQueue.UpdateDLQMergeFence(ctx, progress.taskType, d.consumerGroup, &DLQMergeFence{
+			RequestID: mergeRequestID,
+			MessageID: message.SourceTaskID,
+		}); err != nil {
+			logger.WithTags(dlqMessageTags(message)...).Error("failed to update merge fence on merging domain DLQ message",
+				dlqTaskTypeTag(progress.taskType),
+				tag.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// recordMergedMessage records the message as executed by the merge
+func (d *dlqMessageHandlerImpl) recordMergedMessage(message *types.ReplicationTask, progress *dlqMergeProgress) {
+	progress.mergedCount++
+	if d.seenTaskIDs != nil {
+		progress.executedIDs = append(progress.executedIDs, message.SourceTaskID)
+	}
+	d.deduplicator.add(message)
+	d.emitDLQMessageAge(message)
+}
+
+// dlqPendingMessage is a message of a page merged by Merge which is not committed yet.
+// The result of an executed message is either err or, if the message is executed concurrently, sent on result.
+type dlqPendingMessage struct {
+	message  *types.ReplicationTask
+	executed bool
+	err      error
+	result   chan error
+}
+
+// dlqMergeProgress tracks the messages of a page processed by Merge
+type dlqMergeProgress struct {
+	taskType types.ReplicationTaskType
+	// startAckLevel is the ack level the page was read from, ackLevel the ack level the merge checkpointed last
+	startAckLevel  int64
+	ackLevel       int64
+	startTime      time.Time
+	traceID        string
+	processedCount int
+	firstMessageID int64
+	ackedMessageID int64
+	mergedCount    int64
+	failedCount    int64
+	// executedIDs are the SourceTaskIDs of the executed messages, only tracked if the handler remembers them
+	executedIDs []int64
+}
+
+func (p *dlqMergeProgress) processed(message *types.ReplicationTask) {
+	p.ackedMessageID = common.MaxInt64(p.ackedMessageID, message.SourceTaskID)
+	if p.processedCount == 0 || message.SourceTaskID < p.firstMessageID {
+		p.firstMessageID = message.SourceTaskID
+	}
+	p.processedCount++
+}
+
+// completeMerge checkpoints the processed messages and records the merge
+func (d *dlqMessageHandlerImpl) completeMerge(
+	ctx context.Context,
+	logger log.Logger,
+	progress *dlqMergeProgress,
+) error {
+
+	// the executed messages are remembered even if checkpointing them fails, as they are read again then
+	d.addSeenTaskIDs(progress.executedIDs)
+	progress.executedIDs = nil
+	if err := d.checkpointMerge(ctx, logger, progress); err != nil {
+		return err
+	}
+	taskType, ackedMessageID := progress.taskType, progress.ackedMessageID
+	if ackedMessageID > progress.startAckLevel {
+		if err := d.replicationQueue.RecordDLQMerge(ctx, DLQMergeRecord{
+			MergedAt:       d.timeSource.Now(),
+			StartMessageID: progress.firstMessageID,
+			EndMessageID:   ackedMessageID,
+			MergedCount:    progress.mergedCount,
+			FailedCount:    progress.failedCount,
+			Duration:       d.timeSource.Now().Sub(progress.startTime),
+			TriggeredBy:    dlqMergeTriggerFromContext(ctx),
+			TraceID:        progress.traceID,
+		}); err != nil {
+			logger.Error("failed to record merge history on merging domain DLQ message",
+				dlqTaskTypeTag(taskType),
+				tag.DLQLastMessageID(ackedMessageID),
+				tag.Error(err))
+		}
+		d.notify(ctx, DLQEvent{
+			Type:        DLQEventMergeCompleted,
+			AckLevel:    progress.ackLevel,
+			MergedCount: progress.mergedCount,
+			FailedCount: progress.failedCount,
+		})
+	}
+
+	atomic.StoreInt64(&d.lastMergeTime, d.timeSource.Now().UnixNano())
+	atomic.StoreInt64(&d.lastMergeCount, progress.mergedCount)
+	atomic.StoreInt64(&d.ackLevel, progress.ackLevel)
+	return nil
+}
+
+// checkpointMerge deletes the messages processed since the last checkpoint and moves the ack level past them
+func (d *dlqMessageHandlerImpl) checkpointMerge(
+	ctx context.Context,
+	logger log.Logger,
+	progress *dlqMergeProgress,
+) error {
+
+	taskType, ackLevel, ackedMessageID := progress.taskType, progress.ackLevel, progress.ackedMessageID
+	if ackedMessageID <= ackLevel {
+		return nil
+	}
+	d.stateMachine.transition(DLQStateCommitting)
+	if err := d.rangeDeleteMessages(
+		ctx,
+		taskType,
+		ackLevel,
+		ackedMessageID,
+	); err != nil {
+		logger.Error("failed to delete merged tasks on merging domain DLQ message",
+			dlqTaskTypeTag(taskType),
+			tag.DLQAckLevel(ackLevel),
+			tag.DLQLastMessageID(ackedMessageID),
+			tag.Error(err))
+		return newDLQDeleteError(err)
+	}
+	d.journal.forget(ackLevel, ackedMessageID)
+	// a concurrent merge may have moved the ack level, never let it go backwards
+	err := d.advanceAckLevel(ctx, taskType, ackLevel, ackedMessageID)
+	if err == ErrDLQAckLevelConflict || err == ErrVersionConflict {
+		return err
+	}
+	if err != nil {
+		logger.Error("failed to update ack level on merging domain DLQ message",
+			dlqTaskTypeTag(taskType),
+			tag.DLQAckLevel(ackLevel),
+			tag.DLQLastMessageID(ackedMessageID),
+			tag.Error(err))
+		return nil
+	}
+	progress.ackLevel = ackedMessageID
+	return nil
+}
+
+func isMergeTimeout(ctx context.Context, pageCtx context.Context) bool {
+	return ctx.Err() == nil && pageCtx != ctx && pageCtx.Err() == context.DeadlineExceeded
+}
+
+// isMergeDeadlineFirst reports whether the merge timeout expires before the deadline of the caller
+func isMergeDeadlineFirst(ctx context.Context, pageCtx context.Context) bool {
+	if ctx.Err() != nil || pageCtx == ctx {
+		return false
+	}
+	deadline, ok := pageCtx.Deadline()
+	parentDeadline, parentOk := ctx.Deadline()
+	return ok && (!parentOk || deadline.Before(parentDeadline))
+}
+
+func sortDLQMessagesByID(messages []*types.ReplicationTask) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].SourceTaskID < messages[j].SourceTaskID
+	})
+}
+
+// getExecutedMessageIndex returns the index of the last message of the page a merge fenced at messageID executed,
+// i.e. the index of the fenced message, or of the last message of the prefix of the page up to the fenced message ID
+// if the fenced message is not part of the page
+func getExecutedMessageIndex(messages []*types.ReplicationTask, messageID int64) int {
+	for i, message := range messages {
+		if message.SourceTaskID == messageID {
+			return i
+		}
+	}
+	executedIndex := -1
+	for i, message := range messages {
+		if message.SourceTaskID > messageID {
+			break
+		}
+		executedIndex = i
+	}
+	return executedIndex
+}
+
+// MergeShard merges the domain replication DLQ messages whose message ID falls into the given shard.
+// Messages are deleted one by one as shards are merged concurrently and the ack level is left untouched.
+func (d *dlqMessageHandlerImpl) MergeShard(
+	ctx context.Context,
+	shardID int,
+	shardCount int,
+) error {
+
+	if shardCount <= 0 || shardID < 0 || shardID >= shardCount {
+		return &types.BadRequestError{Message: fmt.Sprintf("invalid domain DLQ shard %v of %v", shardID, shardCount)}
+	}
+	if d.isPaused() {
+		return ErrDLQProcessingPaused
+	}
+	if d.growthRateMonitor.isPaused() {
+		return ErrDLQMergePaused
+	}
+
+	if d.locker == nil {
+		return d.mergeShard(ctx, shardID, shardCount)
+	}
+	// hosts may both own the shard while the membership ring changes, the lock keeps them from merging it twice
+	return d.withMergeLock(ctx, getDLQShardKey(shardID), func(ctx context.Context) error {
+		return d.mergeShard(ctx, shardID, shardCount)
+	})
+}
+
+func (d *dlqMessageHandlerImpl) mergeShard(
+	ctx context.Context,
+	shardID int,
+	shardCount int,
+) error {
+
+	d.stateMachine.begin()
+	defer d.stateMachine.end()
+
+	// cancel the stream if the merge stops early
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mergedCount int64
+	// the messages of the page read before the merge is paused are still merged
+	taskCh, errCh := d.streamDLQ(streamCtx, AllTaskTypes, common.EndMessageID, d.checkPaused)
+	for message := range taskCh {
+		if message.SourceTaskID%int64(shardCount) != int64(shardID) {
+			continue
+		}
+
+		if d.deduplicator.probablySeen(message) {
+			d.logger.WithTags(dlqMessageTags(message)...).Warn("Skipping duplicate domain DLQ message", tag.ShardID(shardID))
+			d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQDuplicateSkippedCount)
+		} else {
+			if err := d.mergeRateLimiter.Wait(ctx); err != nil {
+				return err
+			}
+			if err := d.executeJournaled(ctx, message); err != nil {
+				if !d.skipInvalidMessage(ctx, message, err) && !d.skipPoisonedMessage(ctx, message, err) {
+					return newDLQError(ErrDLQExecutorFailed, err)
+				}
+			} else {
+				mergedCount++
+				d.deduplicator.add(message)
+				d.emitDLQMessageAge(message)
+			}
+		}
+
+		d.stateMachine.transition(DLQStateCommitting)
+		if err := d.deleteMessage(ctx, message.SourceTaskID); err != nil {
+			d.logger.WithTags(dlqMessageTags(message)...).Error("failed to delete merged task on merging domain DLQ shard",
+				tag.ShardID(shardID),
+				tag.Error(err))
+			return newDLQDeleteError(err)
+		}
+	}
+	// a merge paused after its current page still reports the messages it merged
+	err := <-errCh
+	if err != nil && err != ErrDLQProcessingPaused {
+		return err
+	}
+
+	if mergedCount > 0 {
+		d.notify(ctx, DLQEvent{
+			Type:        DLQEventMergeCompleted,
+			AckLevel:    atomic.LoadInt64(&d.ackLevel),
+			MergedCount: mergedCount,
+		})
+	}
+	atomic.StoreInt64(&d.lastMergeTime, d.timeSource.Now().UnixNano())
+	atomic.StoreInt64(&d.lastMergeCount, mergedCount)
+	return err
+}
+
+// withMergeLock runs the merge round while holding the lock with the key, extending the lock every third of its TTL.
+// The merge is aborted if the lock cannot be extended, as another host may have acquired it.
+func (d *dlqMessageHandlerImpl) withMergeLock(
+	ctx context.Context,
+	key string,
+	merge func(context.Context) error,
+) error {
+
+	lock, err := d.locker.AcquireLock(ctx, key, d.mergeLockTTL)
+	if err != nil {
+		return err
+	}
+
+	mergeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	extendErrCh := make(chan error, 1)
+	go func() {
+		extendErrCh <- d.extendMergeLock(mergeCtx, lock, cancel)
+	}()
+
+	mergeErr := merge(mergeCtx)
+	cancel()
+	if err := <-extendErrCh; err != nil {
+		d.logger.Error("Lost domain DLQ merge lock, aborted the merge.", tag.Key(key), tag.Error(err))
+		return err
+	}
+	if err := lock.Release(ctx); err != nil {
+		d.logger.Warn("Failed to release domain DLQ merge lock.", tag.Key(key), tag.Error(err))
+	}
+	return mergeErr
+}
+
+// extendMergeLock extends the lock until the context is done, the merge is cancelled if extending the lock fails
+func (d *dlqMessageHandlerImpl) extendMergeLock(
+	ctx context.Context,
+	lock LockHandle,
+	cancelMerge context.CancelFunc,
+) error {
+
+	ticker := time.NewTicker(d.mergeLockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := lock.Extend(ctx); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				cancelMerge()
+				return err
+			}
+		}
+	}
+}
+
+// DryRunMerge reads domain replication DLQ messages and reports what a merge would apply
+// without executing the messages or modifying the DLQ.
+func (d *dlqMessageHandlerImpl) DryRunMerge(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+) ([]DLQMergePreview, error) {
+
+	// cancel the stream if the dry run stops early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	executor := &dryRunTaskExecutor{}
+	taskCh, errCh := d.StreamDLQ(ctx, taskType, lastMessageID)
+	for task := range taskCh {
+		if err := executor.ExecuteReplicationTask(task, task.SourceCluster); err != nil {
+			return nil, newDLQError(ErrDLQExecutorFailed, err)
+		}
+	}
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+	return executor.previews, nil
+}
+
+// SimulateMerge reads domain replication DLQ messages and reports the operations a merge would perform
+// on the domains read from the domain manager given to WithSimulationMode, without performing them
+// or modifying the DLQ. The messages which would fail are reported and do not stop the simulation.
+func (d *dlqMessageHandlerImpl) SimulateMerge(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+) ([]SimulatedOperation, error) {
+
+	if d.simulationDomains == nil {
+		return nil, ErrSimulationModeDisabled
+	}
+
+	// cancel the stream if the simulation stops early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	executor := NewSimulationReplicationTaskExecutor(d.simulationDomains, d.timeSource, d.logger)
+	taskCh, errCh := d.StreamDLQ(ctx, taskType, lastMessageID)
+	for task := range taskCh {
+		// the failure is recorded with the simulated operations
+		_ = executor.ExecuteReplicationTask(task, task.SourceCluster)
+	}
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+	return executor.Operations(), nil
+}
+
+// MergeHistory returns the most recent domain replication DLQ merges, newest first
+func (d *dlqMessageHandlerImpl) MergeHistory(
+	ctx context.Context,
+	limit int,
+) ([]DLQMergeRecord, error) {
+
+	return d.replicationQueue.GetDLQMergeHistory(ctx, limit)
+}
+
+// Forward publishes domain replication DLQ messages to the replication queue of another cluster and removes them from the DLQ.
+// Forwarding stops at the first message which is not a domain replication task. If publishing fails part way, only the
+// messages forwarded so far are removed from the DLQ.
+func (d *dlqMessageHandlerImpl) Forward(
+	ctx context.Context,
+	destinationCluster string,
+	lastMessageID int64,
+) error {
+
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
+	if err != nil {
+		return newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+
+	// cancel the stream if forwarding stops early
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	forwardedMessageID := ackLevel
+	var forwardErr error
+	taskCh, errCh := d.StreamDLQ(streamCtx, AllTaskTypes, lastMessageID)
+	for task := range taskCh {
+		if err := validateDomainReplicationTask(task); err != nil {
+			forwardErr = err
+			break
+		}
+		if err := d.replicationQueue.EnqueueForCluster(ctx, destinationCluster, task); err != nil {
+			d.logger.WithTags(dlqMessageTags(task)...).Error("failed to forward domain DLQ message",
+				tag.ClusterName(destinationCluster),
+				tag.Error(err))
+			if _, ok := err.(*types.BadRequestError); ok {
+				forwardErr = err
+			} else {
+				forwardErr = newDLQError(ErrDLQQueueFull, err)
+			}
+			break
+		}
+		forwardedMessageID = task.SourceTaskID
+	}
+	if forwardErr == nil {
+		forwardErr = <-errCh
+	}
+
+	if forwardedMessageID > ackLevel {
+		if err := d.rangeDeleteMessages(
+			ctx,
+			AllTaskTypes,
+			ackLevel,
+			forwardedMessageID,
+		); err != nil {
+			d.logger.Error("failed to delete forwarded tasks from domain DLQ",
+				tag.ClusterName(destinationCluster),
+				tag.DLQAckLevel(ackLevel),
+				tag.DLQLastMessageID(forwardedMessageID),
+				tag.Error(err))
+			return newDLQDeleteError(err)
+		}
+		if err := d.advanceAckLevel(ctx, AllTaskTypes, ackLevel, forwardedMessageID); err != nil {
+			d.logger.Error("failed to update ack level on forwarding domain DLQ message",
+				tag.ClusterName(destinationCluster),
+				tag.DLQAckLevel(ackLevel),
+				tag.DLQLastMessageID(forwardedMessageID),
+				tag.Error(err))
+			return err
+		}
+	}
+
+	return forwardErr
+}
+
+// Reinject publishes a single domain replication DLQ message to the replication queue, from which the remote clusters
+// read it like any other replication task, and removes it from the DLQ. The ack level is not moved since the messages
+// before the re-injected message may remain in the DLQ. Only domain replication tasks can be re-injected.
+func (d *dlqMessageHandlerImpl) Reinject(
+	ctx context.Context,
+	messageID int64,
+) error {
+
+	tasks, _, err := d.getMessagesFromDLQ(ctx, d.replicationQueue, AllTaskTypes, messageID-1, messageID, 1, nil)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 || tasks[0].SourceTaskID != messageID {
+		return newDLQError(ErrDLQMessageNotFound, &types.EntityNotExistsError{
+			Message: fmt.Sprintf("domain DLQ message %v does not exist", messageID),
+		})
+	}
+
+	task := tasks[0]
+	if err := validateDomainReplicationTask(task); err != nil {
+		return err
+	}
+
+	logger := d.logger.WithTags(dlqMessageTags(task)...)
+	// the replication queue assigns the next message ID of its own sequence to the task, so the DLQ message ID
+	// and the fields only kept in the DLQ are left out as they are for tasks published by the domain replicator
+	if err := d.replicationQueue.Publish(ctx, &types.ReplicationTask{
+		TaskType:                      task.TaskType,
+		DomainTaskAttributes:          task.DomainTaskAttributes,
+		SyncShardStatusTaskAttributes: task.SyncShardStatusTaskAttributes,
+		SyncActivityTaskAttributes:    task.SyncActivityTaskAttributes,
+		HistoryTaskV2Attributes:       task.HistoryTaskV2Attributes,
+		FailoverMarkerAttributes:      task.FailoverMarkerAttributes,
+		WorkflowResetTaskAttributes:   task.WorkflowResetTaskAttributes,
+		CreationTime:                  task.CreationTime,
+	}); err != nil {
+		logger.Error("failed to re-inject domain DLQ message", tag.Error(err))
+		return err
+	}
+
+	// the message is published before it is deleted, so it is never lost but may be published twice
+	if err := d.deleteMessage(ctx, messageID); err != nil {
+		logger.Error("failed to delete re-injected domain DLQ message", tag.Error(err))
+		return newDLQDeleteError(err)
+	}
+
+	logger.Info("Re-injected domain DLQ message to the replication queue.")
+	return nil
+}
+
+// Replay copies domain replication DLQ messages from the source queue into the DLQ of the destination queue,
+// e.g. to migrate the DLQ to another persistence backend. Each page is only acknowledged on the destination
+// once the destination DLQ grew by the page, so messages already replayed by an interrupted run are not copied again.
+// The source DLQ is left untouched.
+func (d *dlqMessageHandlerImpl) Replay(
+	ctx context.Context,
+	srcQueue ReplicationQueue,
+	dstQueue ReplicationQueue,
+	lastMessageID int64,
+) error {
+
+	ackLevel, err := srcQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
+	if err != nil {
+		return newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+	replayAckLevel, err := dstQueue.GetDLQReplayAckLevel(ctx)
+	if err != nil {
+		return err
+	}
+	// message IDs are assigned by the destination on enqueue, so overlapping IDs already in the
+	// destination DLQ are kept and only the replay ack level tells which messages were copied
+	firstMessageID := common.MaxInt64(ackLevel, replayAckLevel)
+
+	var pageToken []byte
+	for {
+		tasks, token, err := d.getMessagesFromDLQ(ctx, srcQueue, AllTaskTypes, firstMessageID, lastMessageID, dlqStreamPageSize, pageToken)
+		if err != nil {
+			return err
+		}
+
+		if len(tasks) > 0 {
+			sizeBefore, err := dstQueue.GetDLQSize(ctx, AllTaskTypes)
+			if err != nil {
+				return err
+			}
+			for start := 0; start < len(tasks); start += dlqEnqueueBatchSize {
+				batch := tasks[start:common.MinInt(start+dlqEnqueueBatchSize, len(tasks))]
+				for range batch {
+					if err := d.replayRateLimiter.Wait(ctx); err != nil {
+						return err
+					}
+				}
+				if err := dstQueue.EnqueueBatch(ctx, batch); err != nil {
+					d.logger.Error("failed to replay domain DLQ messages",
+						tag.DLQLastMessageID(batch[len(batch)-1].SourceTaskID),
+						tag.Error(err))
+					return newDLQError(ErrDLQQueueFull, err)
+				}
+			}
+
+			sizeAfter, err := dstQueue.GetDLQSize(ctx, AllTaskTypes)
+			if err != nil {
+				return err
+			}
+			if sizeAfter-sizeBefore < int64(len(tasks)) {
+				return &types.InternalServiceError{Message: fmt.Sprintf(
+					"failed to verify replayed domain DLQ messages up to %v: destination DLQ grew by %v messages, expected %v",
+					tasks[len(tasks)-1].SourceTaskID,
+					sizeAfter-sizeBefore,
+					len(tasks),
+				)}
+			}
+			if err := dstQueue.UpdateDLQReplayAckLevel(ctx, tasks[len(tasks)-1].SourceTaskID); err != nil {
+				d.logger.Error("failed to update replay ack level on replaying domain DLQ message",
+					tag.DLQLastMessageID(tasks[len(tasks)-1].SourceTaskID),
+					tag.Error(err))
+				return err
+			}
+		}
+
+		if len(token) == 0 {
+			return nil
+		}
+		pageToken = token
+	}
+}
+
+// GarbageCollectDLQ removes the domain replication DLQ messages soft deleted longer than olderThan ago
+func (d *dlqMessageHandlerImpl) GarbageCollectDLQ(
+	ctx context.Context,
+	olderThan time.Duration,
+) error {
+
+	if olderThan < 0 {
+		return &types.BadRequestError{Message: fmt.Sprintf("invalid domain DLQ garbage collection threshold %v", olderThan)}
+	}
+
+	deletedBefore := d.timeSource.Now().Add(-olderThan)
+	if err := d.replicationQueue.DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore); err != nil {
+		return newDLQError(ErrDLQDeleteFailed, err)
+	}
+
+	d.logger.Info("Garbage collected soft deleted domain DLQ messages.", tag.Timestamp(deletedBefore))
+	return nil
+}
+
+// archiveMessages archives the DLQ messages of the task type in the range before they are purged,
+// nothing is archived unless the handler was created WithArchival and archival is enabled
+func (d *dlqMessageHandlerImpl) archiveMessages(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+	tasks []*types.ReplicationTask,
+) error {
+
+	if d.dlqArchiver == nil || !d.archivalEnabled() {
+		return nil
+	}
+	URI, err := archiver.NewURI(d.archivalURI())
+	if err != nil {
+		return err
+	}
+	if err := d.dlqArchiver.Archive(ctx, URI, taskType, firstMessageID, lastMessageID, tasks); err != nil {
+		return err
+	}
+
+	d.logger.Info("Archived domain DLQ messages before purging them.",
+		dlqTaskTypeTag(taskType),
+		tag.DLQAckLevel(firstMessageID),
+		tag.DLQLastMessageID(lastMessageID),
+		tag.ArchivalURI(URI.String()),
+	)
+	return nil
+}
+
+// rangeDeleteMessages deletes the domain replication DLQ messages of the task type in the range,
+// the messages are only marked as deleted if soft deletion is enabled
+func (d *dlqMessageHandlerImpl) rangeDeleteMessages(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+
+	if d.softDelete() {
+		return d.replicationQueue.RangeSoftDeleteMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID)
+	}
+	return d.replicationQueue.RangeDeleteMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID)
+}
+
+// deleteMessage deletes a single domain replication DLQ message,
+// the message is only marked as deleted if soft deletion is enabled
+func (d *dlqMessageHandlerImpl) deleteMessage(
+	ctx context.Context,
+	messageID int64,
+) error {
+
+	var err error
+	if d.softDelete() {
+		err = d.replicationQueue.RangeSoftDeleteMessagesFromDLQ(ctx, AllTaskTypes, messageID-1, messageID)
+	} else {
+		err = d.replicationQueue.DeleteMessageFromDLQ(ctx, messageID)
+	}
+	if err == nil {
+		d.journal.forget(messageID-1, messageID)
+	}
+	return err
+}
+
+// getMessagesFromDLQ reads a page of DLQ messages from the queue, retrying transient persistence errors with backoff.
+// The latency of every read of the queue is recorded.
+func (d *dlqMessageHandlerImpl) getMessagesFromDLQ(
+	ctx context.Context,
+	queue ReplicationQueue,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	var messages []*types.ReplicationTask
+	var token []byte
+	var lastErr error
+	attempt := 0
+	read := func() error {
+		if attempt > 0 {
+			d.contextLogger(ctx).Debug("Retrying reading domain DLQ messages after a transient error",
+				dlqTaskTypeTag(taskType),
+				tag.Attempt(int32(attempt)),
+				tag.Error(lastErr))
+		}
+		attempt++
+		startTime := d.timeSource.Now()
+		messages, token, lastErr = queue.GetMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID, pageSize, pageToken)
+		d.getReadScope().RecordHistogramDuration(metrics.DomainReplicationDLQReadPageLatency, d.timeSource.Now().Sub(startTime))
+		return lastErr
+	}
+
+	maxRetries := d.readMaxRetries()
+	if maxRetries <= 0 {
+		err := read()
+		return messages, token, err
+	}
+	policy := backoff.NewExponentialRetryPolicy(dlqReadRetryInitialInterval)
+	policy.SetMaximumInterval(dlqReadRetryMaximumInterval)
+	policy.SetExpirationInterval(backoff.NoInterval)
+	policy.SetMaximumAttempts(maxRetries)
+	throttleRetry := backoff.NewThrottleRetry(
+		backoff.WithRetryPolicy(policy),
+		backoff.WithRetryableError(persistence.IsTransientError),
+	)
+	// the retrier returns the error of the attempt before the last one, so the last error is returned instead
+	if err := throttleRetry.Do(ctx, read); err != nil {
+		return nil, nil, lastErr
+	}
+	return messages, token, nil
+}
+
+// executeReplicationTask executes the message with the executor registered for its task type through the circuit breaker
+// protecting the replication task executors, a conflict with a local domain is resolved according to the conflict resolution policy of the domain
+func (d *dlqMessageHandlerImpl) executeReplicationTask(ctx context.Context, message *types.ReplicationTask) error {
+	// an incomplete task would fail inside the executor regardless of its health, so it is not counted by the breaker
+	if InvalidTaskPolicy(d.invalidTaskPolicy()) != InvalidTaskPolicyNone {
+		if err := ValidateReplicationTask(message); err != nil {
+			return err
+		}
+	}
+	return d.circuitBreaker.execute(func() error {
+		executor, ok := d.executors.GetExecutor(message.GetTaskType())
+		if !ok {
+			return ErrUnsupportedReplicationTaskType
+		}
+		err := d.executeWithMetrics(executor, message)
+		if err == ErrDuplicateTask {
+			// the task was applied before, e.g. by a merge which failed to delete the message
+			d.contextLogger(ctx).WithTags(dlqMessageTags(message)...).Warn("Skipping domain DLQ message which was already applied")
+			d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQDuplicateSkippedCount)
+			return nil
+		}
+		if err == ErrWorkflowNotFound || err == ErrWorkflowCompleted || err == ErrStaleFailoverMarker {
+			// the workflow the task belongs to can no longer be changed, or the domain already failed over
+			// past the failover marker, so the task is obsolete
+			d.contextLogger(ctx).WithTags(append(dlqMessageTags(message), tag.Error(err))...).Warn("Skipping obsolete domain DLQ message")
+			d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQObsoleteSkippedCount)
+			return nil
+		}
+		if err != ErrNameUUIDCollision {
+			return err
+		}
+		return d.resolveConflict(ctx, executor, message, err)
+	})
+}
+
+// executeWithMetrics executes the message with the executor, recording the latency of the execution
+// and counting its failures by error type per task type
+func (d *dlqMessageHandlerImpl) executeWithMetrics(executor ReplicationTaskExecutor, message *types.ReplicationTask) error {
+	scope := d.executeScope(message.GetTaskType())
+	startTime := d.timeSource.Now()
+	err := executor.ExecuteReplicationTask(message, message.SourceCluster)
+	scope.RecordHistogramDuration(metrics.DomainReplicationDLQExecuteLatency, d.timeSource.Now().Sub(startTime))
+	if err != nil {
+		scope.Tagged(metrics.ErrorTypeTag(dlqErrorType(err))).IncCounter(metrics.DomainReplicationDLQExecuteErrors)
+	}
+	return err
+}
+
+func (d *dlqMessageHandlerImpl) executeScope(taskType types.ReplicationTaskType) metrics.Scope {
+	return d.cachedScope(dlqScopeKey{taskType: taskType})
+}
+
+func (d *dlqMessageHandlerImpl) dlqMessageScope(message *types.ReplicationTask) metrics.Scope {
+	return d.cachedScope(dlqScopeKey{
+		taskType:      message.GetTaskType(),
+		domainName:    message.GetDomainTaskAttributes().GetInfo().GetName(),
+		sourceCluster: message.SourceCluster,
+		perMessage:    true,
+	})
+}
+
+func (d *dlqMessageHandlerImpl) cachedScope(key dlqScopeKey) metrics.Scope {
+	d.scopesLock.Lock()
+	defer d.scopesLock.Unlock()
+
+	scope, ok := d.scopes[key]
+	if !ok {
+		scope = d.metricsClient.Scope(metrics.DomainReplicationQueueScope, key.tags()...)
+		d.scopes[key] = scope
+	}
+	return scope
+}
+
+func (d *dlqMessageHandlerImpl) getReadScope() metrics.Scope {
+	d.readScopeOnce.Do(func() {
+		d.readScope = d.metricsClient.Scope(metrics.DomainReplicationQueueScope)
+	})
+	return d.readScope
+}
+
+func (d *dlqMessageHandlerImpl) resolveConflict(
+	ctx context.Context,
+	executor ReplicationTaskExecutor,
+	message *types.ReplicationTask,
+	conflictErr error,
+) error {
+
+	domainName := message.DomainTaskAttributes.GetInfo().GetName()
+	policies, err := d.replicationQueue.GetDLQConflictResolutionPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	policy, ok := policies[domainName]
+	if !ok {
+		policy = defaultConflictResolutionPolicy
+	}
+
+	logger := d.contextLogger(ctx).WithTags(dlqMessageTags(message)...)
+	switch policy {
+	case ConflictResolutionPolicyKeepLocal:
+		logger.Warn("Keeping local domain conflicting with domain DLQ message.", tag.WorkflowDomainName(domainName))
+		return nil
+	case ConflictResolutionPolicyOverwriteWithSource:
+		logger.Warn("Overwriting local domain conflicting with domain DLQ message.", tag.WorkflowDomainName(domainName))
+		return executor.Overwrite(message.DomainTaskAttributes)
+	default:
+		return conflictErr
+	}
+}
+
+// SetConflictResolutionPolicy sets how merges apply the domain DLQ messages of the domain which conflict with a local domain
+func (d *dlqMessageHandlerImpl) SetConflictResolutionPolicy(
+	ctx context.Context,
+	domainName string,
+	policy ConflictResolutionPolicy,
+) error {
+
+	if domainName == "" {
+		return &types.BadRequestError{Message: "domain name is not set"}
+	}
+	if _, err := ParseConflictResolutionPolicy(string(policy)); err != nil {
+		return err
+	}
+	return d.replicationQueue.UpdateDLQConflictResolutionPolicy(ctx, domainName, policy)
+}
+
+// GetConflictResolutionPolicies returns the conflict resolution policies of the domains not using the default policy
+func (d *dlqMessageHandlerImpl) GetConflictResolutionPolicies(
+	ctx context.Context,
+) (map[string]ConflictResolutionPolicy, error) {
+
+	return d.replicationQueue.GetDLQConflictResolutionPolicies(ctx)
+}
+
+// skipInvalidMessage returns true if the message failed validation and invalid messages are skipped,
+// so it can be removed with the rest of the page without being executed
+func (d *dlqMessageHandlerImpl) skipInvalidMessage(
+	ctx context.Context,
+	message *types.ReplicationTask,
+	executeErr error,
+) bool {
+
+	var invalidTaskErr *ErrInvalidTask
+	if !errors.As(executeErr, &invalidTaskErr) || InvalidTaskPolicy(d.invalidTaskPolicy()) != InvalidTaskPolicySkip {
+		return false
+	}
+
+	d.contextLogger(ctx).WithTags(dlqMessageTags(message)...).Warn("Dropping invalid domain DLQ message.",
+		tag.Error(executeErr),
+	)
+	d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQInvalidMessageCount)
+	return true
+}
+
+// skipPoisonedMessage records a failed merge attempt on the message and returns true
+// if the message has exhausted its retry budget and can be removed with the rest of the page
+func (d *dlqMessageHandlerImpl) skipPoisonedMessage(
+	ctx context.Context,
+	message *types.ReplicationTask,
+	executeErr error,
+) bool {
+
+	// the message was not executed, so the attempt does not count
+	if _, ok := executeErr.(*ErrCircuitBreakerOpen); ok {
+		return false
+	}
+	maxRetryAttempts := d.maxRetryAttempts()
+	if maxRetryAttempts <= 0 {
+		return false
+	}
+
+	attempts, err := d.replicationQueue.IncrementDLQMessageAttempts(ctx, message.SourceTaskID)
+	if err != nil {
+		d.contextLogger(ctx).WithTags(dlqMessageTags(message)...).Error("Failed to increment attempts of domain DLQ message", tag.Error(err))
+		return false
+	}
+	if attempts < maxRetryAttempts {
+		return false
+	}
+
+	d.contextLogger(ctx).WithTags(dlqMessageTags(message)...).Warn("Dropping poisoned domain DLQ message after exhausting retry attempts.",
+		tag.AttemptCount(int64(attempts)),
+		tag.Error(executeErr),
+	)
+	d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQPoisonedMessageCount)
+	return true
+}
+
+// nackMessage moves a message which failed with a transient error to the retry queue,
+// so the rest of the page can be merged without it
+func (d *dlqMessageHandlerImpl) nackMessage(
+	ctx context.Context,
+	message *types.ReplicationTask,
+	executeErr error,
+) error {
+
+	logger := d.contextLogger(ctx).WithTags(dlqMessageTags(message)...)
+	if err := d.replicationQueue.NackMessage(ctx, message.SourceTaskID, dlqNackRetryDelay); err != nil {
+		logger.Error("Failed to nack domain DLQ message", tag.Error(err))
+		var notExistsErr *types.EntityNotExistsError
+		if errors.As(err, &notExistsErr) {
+			return newDLQError(ErrDLQMessageNotFound, err)
+		}
+		return newDLQError(ErrDLQExecutorFailed, executeErr)
+	}
+	logger.Warn("Nacked domain DLQ message after a transient failure.", tag.Error(executeErr))
+	d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQNackedMessageCount)
+	return nil
+}
+
+// contextLogger returns the logger of the handler tagged with the trace ID of the merge the context belongs to
+func (d *dlqMessageHandlerImpl) contextLogger(ctx context.Context) log.Logger {
+	if traceID := dlqMergeTraceIDFromContext(ctx); traceID != "" {
+		return d.logger.WithTags(tag.DLQMergeTraceID(traceID))
+	}
+	return d.logger
+}
+
+func (d *dlqMessageHandlerImpl) emitDLQMessageAge(message *types.ReplicationTask) {
+	// messages enqueued before the enqueue time was persisted have no age
+	if message.EnqueuedAt.IsZero() {
+		return
+	}
+	d.dlqMessageScope(message).RecordHistogramDuration(
+		metrics.DomainReplicationDLQMessageAge,
+		d.timeSource.Now().Sub(message.EnqueuedAt),
+	)
+}
+
+// emitDLQMessageSizes records the persisted size of the messages of a page and reports the large ones,
+// as messages close to the row size limit of the persistence risk failing to be written
+func (d *dlqMessageHandlerImpl) emitDLQMessageSizes(ctx context.Context, messages []*types.ReplicationTask) {
+	logger := d.contextLogger(ctx)
+	threshold := d.largeMessageSize()
+	for _, message := range messages {
+		// the size is unknown for messages which were not read from the persistence
+		if message.PayloadSize <= 0 {
+			continue
+		}
+		d.dlqMessageScope(message).RecordHistogramValue(metrics.DomainReplicationDLQMessageSize, float64(message.PayloadSize))
+		if threshold > 0 && message.PayloadSize > threshold {
+			logger.WithTags(dlqMessageTags(message)...).Error("Domain DLQ message exceeds the large message threshold.",
+				tag.DLQMessageSizeBytes(message.PayloadSize),
+				tag.DLQLargeMessageThresholdBytes(threshold),
+			)
+		}
+	}
+}
+
+func (d *dlqMessageHandlerImpl) emitDLQSizeMetricsLoop() {
+	defer d.shutdownWG.Done()
+
+	timer := time.NewTimer(d.sizeEmitInterval())
+	defer timer.Stop()
+
+	var messageTypeCounts map[types.ReplicationTaskType]int64
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-timer.C:
+			err := d.fetchAndEmitDLQSize(context.Background())
+			if err != nil {
+				d.logger.Warn("Failed to get DLQ size.", tag.Error(err))
+			} else {
+				d.checkDLQDepth(context.Background())
+			}
+			counts, err := d.fetchAndEmitDLQMessageTypeCounts(context.Background(), messageTypeCounts)
+			if err != nil {
+				d.logger.Warn("Failed to get DLQ message type histogram.", tag.Error(err))
+			} else {
+				messageTypeCounts = counts
+			}
+			timer.Reset(d.sizeEmitInterval())
+		}
+	}
+}
+
+func (d *dlqMessageHandlerImpl) fetchAndEmitDLQSize(ctx context.Context) error {
+	size, err := d.replicationQueue.GetDLQSize(ctx, AllTaskTypes)
+	if err != nil {
+		d.metricsClient.Scope(metrics.DomainReplicationQueueScope).IncCounter(metrics.DomainReplicationQueueSizeErrorCount)
+		if !persistence.IsTransientError(err) {
+			atomic.StoreInt32(&d.ready, 0)
+		}
+		return err
+	}
+	atomic.StoreInt32(&d.ready, 1)
+
+	d.metricsClient.Scope(metrics.DomainReplicationQueueScope).UpdateGauge(metrics.DomainReplicationQueueSizeGauge, float64(size))
+
+	atomic.StoreInt64(&d.lastCount, size)
+
+	return nil
+}
+
+// fetchAndEmitDLQMessageTypeCounts emits the number of DLQ messages of each task type,
+// the task types which were emitted before but have no messages anymore are reset to zero
+func (d *dlqMessageHandlerImpl) fetchAndEmitDLQMessageTypeCounts(
+	ctx context.Context,
+	previousCounts map[types.ReplicationTaskType]int64,
+) (map[types.ReplicationTaskType]int64, error) {
+
+	counts, err := d.replicationQueue.GetDLQMessageTypeHistogram(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for taskType := range previousCounts {
+		if _, ok := counts[taskType]; !ok {
+			d.emitDLQMessageTypeCount(taskType, 0)
+		}
+	}
+	for taskType, count := range counts {
+		d.emitDLQMessageTypeCount(taskType, count)
+	}
+	return counts, nil
+}
+
+// checkDLQDepth notifies the hooks if the last size read of the DLQ exceeds the depth alert threshold
+func (d *dlqMessageHandlerImpl) checkDLQDepth(ctx context.Context) {
+	threshold := int64(d.depthAlertThreshold())
+	if threshold <= 0 {
+		return
+	}
+	depth := atomic.LoadInt64(&d.lastCount)
+	if depth <= threshold {
+		return
+	}
+	d.notify(ctx, DLQEvent{
+		Type:      DLQEventDepthExceeded,
+		AckLevel:  atomic.LoadInt64(&d.ackLevel),
+		Depth:     depth,
+		Threshold: threshold,
+	})
+}
+
+func (d *dlqMessageHandlerImpl) emitDLQMessageTypeCount(taskType types.ReplicationTaskType, count int64) {
+	d.metricsClient.Scope(metrics.DomainReplicationQueueScope, metrics.TaskTypeTag(taskType.String())).
+		UpdateGauge(metrics.DomainReplicationDLQMessageTypeCount, float64(count))
+}
+
+func (d *dlqMessageHandlerImpl) expireMessagesLoop() {
+	defer d.shutdownWG.Done()
+
+	timer := time.NewTimer(dlqExpiryInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-timer.C:
+			if err := d.expireMessages(context.Background()); err != nil {
+				d.logger.Warn("Failed to expire domain DLQ messages.", tag.Error(err))
+			}
+			timer.Reset(dlqExpiryInterval)
+		}
+	}
+}
+
+// watchdog reports the ack level as stalled when it did not advance for longer than the threshold,
+// which usually means a stuck task or a dead merge worker
+func (d *dlqMessageHandlerImpl) watchdog() {
+	defer d.shutdownWG.Done()
+
+	ticker := time.NewTicker(dlqWatchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+			if err := d.checkAckLevelProgress(context.Background()); err != nil {
+				d.logger.Warn("Failed to check domain DLQ ack level progress.", tag.Error(err))
+			}
+		}
+	}
+}
+
+func (d *dlqMessageHandlerImpl) checkAckLevelProgress(ctx context.Context) error {
+	threshold := d.stalledAckLevel()
+	if threshold <= 0 {
+		return nil
+	}
+
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
+	if err != nil {
+		return newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+
+	now := d.timeSource.Now()
+	if d.watchdogAckLevelSince.IsZero() || ackLevel != d.watchdogAckLevel {
+		d.watchdogAckLevel = ackLevel
+		d.watchdogAckLevelSince = now
+		return nil
+	}
+	stalledFor := now.Sub(d.watchdogAckLevelSince)
+	if stalledFor < threshold {
+		return nil
+	}
+
+	// the ack level of an empty DLQ has nothing to advance to
+	size, err := d.replicationQueue.GetDLQSize(ctx, AllTaskTypes)
+	if err != nil {
+		return err
+	}
+	if size == 0 {
+		d.watchdogAckLevelSince = now
+		return nil
+	}
+
+	d.metricsClient.Scope(metrics.DomainReplicationQueueScope).IncCounter(metrics.DomainReplicationDLQAckLevelStalledCount)
+	d.logger.Warn("Domain DLQ ack level has not advanced, a task may be stuck or the merge worker may be dead.",
+		tag.DLQAckLevel(ackLevel),
+		tag.DLQAckLevelStalledDuration(stalledFor),
+		tag.DLQSize(size),
+	)
+	d.notify(ctx, DLQEvent{
+		Type:       DLQEventAckLevelStalled,
+		AckLevel:   ackLevel,
+		Depth:      size,
+		StalledFor: stalledFor,
+	})
+	return nil
+}
+
+// notify sends the event to the notification hooks, a hook failing to deliver it does not keep it from the others
+func (d *dlqMessageHandlerImpl) notify(ctx context.Context, event DLQEvent) {
+	ctx, cancel := context.WithTimeout(ctx, dlqNotificationTimeout)
+	defer cancel()
+
+	event.Timestamp = d.timeSource.Now()
+	for _, hook := range d.notificationHooks {
+		if err := hook.Notify(ctx, event); err != nil {
+			d.metricsClient.Scope(metrics.DomainReplicationQueueScope).IncCounter(metrics.DomainReplicationDLQNotificationFailedCount)
+			d.logger.Warn("Failed to notify domain DLQ event.", tag.DLQEventType(string(event.Type)), tag.Error(err))
+		}
+	}
+}
+
+// expireMessages purges the domain replication DLQ messages enqueued longer than the message TTL ago.
+// Messages are removed in order, so expiry stops at the first message which is not expired
+// or was enqueued before the enqueue time was persisted.
+func (d *dlqMessageHandlerImpl) expireMessages(ctx context.Context) error {
+	ttl := d.messageTTL()
+	if ttl <= 0 {
+		return nil
+	}
+
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
+	if err != nil {
+		return newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+
+	// cancel the stream once the first message which is not expired is read
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	expireBefore := d.timeSource.Now().Add(-ttl)
+	expiredMessageID := ackLevel
+	var expiredMessages []*types.ReplicationTask
+	var reachedUnexpired bool
+	taskCh, errCh := d.StreamDLQ(streamCtx, AllTaskTypes, common.EndMessageID)
+	for task := range taskCh {
+		if task.EnqueuedAt.IsZero() || !task.EnqueuedAt.Before(expireBefore) {
+			reachedUnexpired = true
+			break
+		}
+		expiredMessageID = task.SourceTaskID
+		expiredMessages = append(expiredMessages, task)
+	}
+	if !reachedUnexpired {
+		if err := <-errCh; err != nil {
+			return err
+		}
+	}
+
+	if expiredMessageID <= ackLevel {
+		return nil
+	}
+	if err := d.rangeDeleteMessages(
+		ctx,
+		AllTaskTypes,
+		ackLevel,
+		expiredMessageID,
+	); err != nil {
+		return err
+	}
+	if err := d.advanceAckLevel(ctx, AllTaskTypes, ackLevel, expiredMessageID); err != nil {
+		return err
+	}
+
+	d.logger.Info("Expired domain DLQ messages.",
+		tag.DLQAckLevel(ackLevel),
+		tag.DLQLastMessageID(expiredMessageID),
+		tag.Counter(len(expiredMessages)),
+	)
+	for _, message := range expiredMessages {
+		d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQExpiredMessageCount)
+	}
+	atomic.StoreInt64(&d.ackLevel, expiredMessageID)
+	return nil
+}
+
+func dlqTaskTypeTag(taskType types.ReplicationTaskType) tag.Tag {
+	if taskType == AllTaskTypes {
+		return tag.ReplicationTaskType("All")
+	}
+	return tag.ReplicationTaskType(taskType.String())
+}
+
+// validateDomainReplicationTask rejects the DLQ messages which are not domain replication tasks from being published to the
+// domain replication queue, the remote clusters only apply its domain tasks and stop reading it at any other task type
+func validateDomainReplicationTask(task *types.ReplicationTask) error {
+	if task.GetTaskType() != types.ReplicationTaskTypeDomain {
+		return &types.BadRequestError{Message: fmt.Sprintf(
+			"domain DLQ message %v is a %v task, only domain replication tasks can be published to the domain replication queue",
+			task.SourceTaskID,
+			task.GetTaskType(),
+		)}
+	}
+	return nil
+}
+
+// dlqMessageTags returns the tags identifying a domain DLQ message in logs
+func dlqMessageTags(message *types.ReplicationTask) []tag.Tag {
+	tags := []tag.Tag{
+		tag.TaskID(message.SourceTaskID),
+		dlqTaskTypeTag(message.GetTaskType()),
+	}
+	if message.SourceCluster != "" {
+		tags = append(tags, tag.SourceCluster(message.SourceCluster))
+	}
+	if domainID := getReplicationTaskDomainID(message); domainID != "" {
+		tags = append(tags, tag.WorkflowDomainID(domainID))
+	}
+	return tags
+}
+
+// dlqScopeKey identifies a cached metrics scope of the DLQ handler, the execute scopes are only tagged with the task
+// type while the per message scopes are also tagged with the domain and source cluster of the message
+type dlqScopeKey struct {
+	taskType      types.ReplicationTaskType
+	domainName    string
+	sourceCluster string
+	perMessage    bool
+}
+
+func (k dlqScopeKey) tags() []metrics.Tag {
+	if !k.perMessage {
+		return []metrics.Tag{metrics.TaskTypeTag(k.taskType.String())}
+	}
+	return []metrics.Tag{
+		metrics.DomainTag(k.domainName),
+		metrics.SourceClusterTag(k.sourceCluster),
+		metrics.TaskTypeTag(k.taskType.String()),
+	}
+}
+
+// dlqMessageMetricsTags returns the dimensions of metrics emitted for a domain DLQ message
+func dlqMessageMetricsTags(message *types.ReplicationTask) []metrics.Tag {
+	return []metrics.Tag{
+		metrics.DomainTag(message.GetDomainTaskAttributes().GetInfo().GetName()),
+		metrics.SourceClusterTag(message.SourceCluster),
+		metrics.TaskTypeTag(message.GetTaskType().String()),
+	}
+}
+
+// dlqErrorType returns the name of the error the metrics are tagged with, the sentinel errors of the executors
+// are named after the failure as their type is shared with unrelated errors
+func dlqErrorType(err error) string {
+	switch err {
+	case ErrDuplicateTask:
+		return "DuplicateTask"
+	case ErrWorkflowNotFound:
+		return "WorkflowNotFound"
+	case ErrWorkflowCompleted:
+		return "WorkflowCompleted"
+	case ErrStaleFailoverMarker:
+		return "StaleFailoverMarker"
+	case ErrNameUUIDCollision:
+		return "NameUUIDCollision"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", err), "*")
+}
+
+func getReplicationTaskDomainID(message *types.ReplicationTask) string {
+	switch {
+	case message.DomainTaskAttributes != nil:
+		return message.DomainTaskAttributes.GetID()
+	case message.HistoryTaskV2Attributes != nil:
+		return message.HistoryTaskV2Attributes.GetDomainID()
+	case message.SyncActivityTaskAttributes != nil:
+		return message.SyncActivityTaskAttributes.GetDomainID()
+	case message.FailoverMarkerAttributes != nil:
+		return message.FailoverMarkerAttributes.GetDomainID()
+	case message.WorkflowResetTaskAttributes != nil:
+		return message.WorkflowResetTaskAttributes.GetDomainID()
+	default:
+		return ""
+	}
+}
+
+// getReplicationTaskType returns the task type persisted with the DLQ message, so the messages can be counted by type
+func getReplicationTaskType(message *types.ReplicationTask) *int32 {
+	if message.TaskType == nil {
+		return nil
+	}
+	taskType := int32(*message.TaskType)
+	return &taskType
+}
+
+// awaitShutdown waits for the wait group until the context is done
+func awaitShutdown(ctx context.Context, wg *sync.WaitGroup) error {
+	doneC := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(doneC)
+	}()
+
+	select {
+	case <-doneC:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build !race
+// +build !race
+
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/metrics"
+)
+
+// TestDLQMerge_Allocations guards the allocations of the merge hot path, which must not grow with the size of the page,
+// it is not run with the race detector, which allocates on its own
+func TestDLQMerge_Allocations(t *testing.T) {
+	const batchSize = 100
+	handler := newBenchmarkDLQMessageHandler(newInMemoryReplicationQueue(batchSize))
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := handler.Merge(context.Background(), AllTaskTypes, "", batchSize, batchSize, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	require.Less(t, allocs, float64(50))
+}
+
+func TestDLQStateMachine_Allocations(t *testing.T) {
+	stateMachine := newDLQStateMachine(nil, metrics.NewNoopMetricsClient())
+	allocs := testing.AllocsPerRun(100, func() {
+		stateMachine.begin()
+		stateMachine.transition(DLQStateExecuting)
+		stateMachine.transition(DLQStateCommitting)
+		stateMachine.end()
+	})
+	require.Zero(t, allocs)
+}
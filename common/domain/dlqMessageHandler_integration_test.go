@@ -0,0 +1,196 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build integration
+// +build integration
+
+// to run locally, make sure Cassandra is running,
+// then run cmd `go test -v ./common/domain -run TestDLQMessageHandlerIntegrationSuite -tags integration`
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql/public"
+	persistencetests "github.com/uber/cadence/common/persistence/persistence-tests"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// dlqMessageHandlerIntegrationSuite runs the domain DLQ handler against the replication queue
+	// backed by the persistence, so the messages left in the DLQ can be asserted
+	dlqMessageHandlerIntegrationSuite struct {
+		suite.Suite
+		persistencetests.TestBase
+
+		controller       *gomock.Controller
+		mockExecutor     *MockReplicationTaskExecutor
+		replicationQueue ReplicationQueue
+		handler          DLQMessageHandler
+	}
+)
+
+func TestDLQMessageHandlerIntegrationSuite(t *testing.T) {
+	s := new(dlqMessageHandlerIntegrationSuite)
+	suite.Run(t, s)
+}
+
+func (s *dlqMessageHandlerIntegrationSuite) SetupTest() {
+	s.TestBase = public.NewTestBaseWithPublicCassandra(&persistencetests.TestBaseOptions{})
+	s.TestBase.Setup()
+
+	s.controller = gomock.NewController(s.T())
+	s.mockExecutor = NewMockReplicationTaskExecutor(s.controller)
+	logger := loggerimpl.NewNopLogger()
+	metricsClient := metrics.NewNoopMetricsClient()
+	s.replicationQueue = NewReplicationQueue(s.DomainReplicationQueueMgr, "active", metricsClient, logger)
+	s.handler = NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(s.mockExecutor),
+		s.replicationQueue,
+		logger,
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(10000), dynamicconfig.GetIntPropertyFn(10000)),
+		WithPurgeBatchDelay(dynamicconfig.GetDurationPropertyFn(0)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+		WithMetricsClient(metricsClient),
+	)
+}
+
+func (s *dlqMessageHandlerIntegrationSuite) TearDownTest() {
+	s.controller.Finish()
+	s.TearDownWorkflowStore()
+}
+
+func (s *dlqMessageHandlerIntegrationSuite) TestReadMergePurge() {
+	ctx := context.Background()
+	var domainIDs []string
+	for i := 0; i < 5; i++ {
+		domainID := uuid.New()
+		domainIDs = append(domainIDs, domainID)
+		s.NoError(s.replicationQueue.PublishToDLQ(ctx, &types.ReplicationTask{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceCluster:        "standby",
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: domainID},
+		}))
+	}
+
+	// Read returns the published messages in order
+	messages, token, err := s.handler.Read(ctx, AllTaskTypes, common.EndMessageID, 100, nil)
+	s.NoError(err)
+	s.Empty(token)
+	s.Len(messages, 5)
+	for i, message := range messages {
+		s.Equal(domainIDs[i], message.DomainTaskAttributes.GetID())
+		s.Equal("standby", message.SourceCluster)
+		s.False(message.EnqueuedAt.IsZero())
+	}
+
+	// Merge applies and deletes the first three messages
+	var mergedDomainIDs []string
+	s.mockExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), "standby").DoAndReturn(
+		func(task *types.ReplicationTask, _ string) error {
+			mergedDomainIDs = append(mergedDomainIDs, task.DomainTaskAttributes.GetID())
+			return nil
+		},
+	).Times(3)
+	mergedMessageID := messages[2].SourceTaskID
+	_, err = s.handler.Merge(ctx, AllTaskTypes, "", mergedMessageID, 100, nil)
+	s.NoError(err)
+	s.Equal(domainIDs[:3], mergedDomainIDs)
+
+	ackLevel, err := s.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(mergedMessageID, ackLevel)
+	size, err := s.replicationQueue.GetDLQSize(ctx, AllTaskTypes)
+	s.NoError(err)
+	s.Equal(int64(2), size)
+	history, err := s.replicationQueue.GetDLQMergeHistory(ctx, 10)
+	s.NoError(err)
+	s.Len(history, 1)
+	s.Equal(int64(3), history[0].MergedCount)
+	s.Equal(mergedMessageID, history[0].EndMessageID)
+
+	remaining, _, err := s.handler.Read(ctx, AllTaskTypes, common.EndMessageID, 100, nil)
+	s.NoError(err)
+	s.Len(remaining, 2)
+	s.Equal(domainIDs[3], remaining[0].DomainTaskAttributes.GetID())
+	s.Equal(domainIDs[4], remaining[1].DomainTaskAttributes.GetID())
+
+	// Purge deletes the remaining messages without applying them
+	lastMessageID := remaining[1].SourceTaskID
+	s.NoError(s.handler.Purge(ctx, AllTaskTypes, lastMessageID))
+
+	ackLevel, err = s.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(lastMessageID, ackLevel)
+	size, err = s.replicationQueue.GetDLQSize(ctx, AllTaskTypes)
+	s.NoError(err)
+	s.Equal(int64(0), size)
+	remaining, _, err = s.handler.Read(ctx, AllTaskTypes, common.EndMessageID, 100, nil)
+	s.NoError(err)
+	s.Empty(remaining)
+}
+
+func (s *dlqMessageHandlerIntegrationSuite) TestReinject() {
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		s.NoError(s.replicationQueue.Publish(ctx, &types.ReplicationTask{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: uuid.New()},
+		}))
+	}
+	reinjectedDomainID := uuid.New()
+	s.NoError(s.replicationQueue.PublishToDLQ(ctx, &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceCluster:        "standby",
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: reinjectedDomainID},
+	}))
+	messages, _, err := s.handler.Read(ctx, AllTaskTypes, common.EndMessageID, 100, nil)
+	s.NoError(err)
+	s.Len(messages, 1)
+
+	s.NoError(s.handler.Reinject(ctx, messages[0].SourceTaskID))
+
+	// the re-injected message follows the published messages in the replication queue without a gap
+	tasks, publishedMessageID, err := s.replicationQueue.GetReplicationMessages(ctx, "standby", common.EmptyMessageID, 2)
+	s.NoError(err)
+	s.Len(tasks, 2)
+	tasks, lastMessageID, err := s.replicationQueue.GetReplicationMessages(ctx, "standby", publishedMessageID, 100)
+	s.NoError(err)
+	s.Len(tasks, 1)
+	s.Equal(reinjectedDomainID, tasks[0].DomainTaskAttributes.GetID())
+	s.Equal(publishedMessageID+1, lastMessageID)
+
+	messages, _, err = s.handler.Read(ctx, AllTaskTypes, common.EndMessageID, 100, nil)
+	s.NoError(err)
+	s.Empty(messages)
+
+	err = s.handler.Reinject(ctx, lastMessageID+100)
+	s.True(errors.Is(err, ErrDLQMessageNotFound))
+}
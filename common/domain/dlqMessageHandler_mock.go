@@ -28,10 +28,11 @@ package domain
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-
 	types "github.com/uber/cadence/common/types"
 )
 
@@ -58,6 +59,20 @@ func (m *MockDLQMessageHandler) EXPECT() *MockDLQMessageHandlerMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockDLQMessageHandler) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockDLQMessageHandlerMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDLQMessageHandler)(nil).Close))
+}
+
 // Count mocks base method.
 func (m *MockDLQMessageHandler) Count(ctx context.Context, forceFetch bool) (int64, error) {
 	m.ctrl.T.Helper()
@@ -73,39 +88,240 @@ func (mr *MockDLQMessageHandlerMockRecorder) Count(ctx, forceFetch interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockDLQMessageHandler)(nil).Count), ctx, forceFetch)
 }
 
+// DryRunMerge mocks base method.
+func (m *MockDLQMessageHandler) DryRunMerge(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) ([]DLQMergePreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunMerge", ctx, taskType, lastMessageID)
+	ret0, _ := ret[0].([]DLQMergePreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunMerge indicates an expected call of DryRunMerge.
+func (mr *MockDLQMessageHandlerMockRecorder) DryRunMerge(ctx, taskType, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunMerge", reflect.TypeOf((*MockDLQMessageHandler)(nil).DryRunMerge), ctx, taskType, lastMessageID)
+}
+
+// DryRunPurge mocks base method.
+func (m *MockDLQMessageHandler) DryRunPurge(ctx context.Context, lastMessageID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunPurge", ctx, lastMessageID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunPurge indicates an expected call of DryRunPurge.
+func (mr *MockDLQMessageHandlerMockRecorder) DryRunPurge(ctx, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunPurge", reflect.TypeOf((*MockDLQMessageHandler)(nil).DryRunPurge), ctx, lastMessageID)
+}
+
+// Export mocks base method.
+func (m *MockDLQMessageHandler) Export(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64, w io.Writer, format ExportFormat) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, taskType, lastMessageID, w, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockDLQMessageHandlerMockRecorder) Export(ctx, taskType, lastMessageID, w, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockDLQMessageHandler)(nil).Export), ctx, taskType, lastMessageID, w, format)
+}
+
+// Forward mocks base method.
+func (m *MockDLQMessageHandler) Forward(ctx context.Context, destinationCluster string, lastMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forward", ctx, destinationCluster, lastMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Forward indicates an expected call of Forward.
+func (mr *MockDLQMessageHandlerMockRecorder) Forward(ctx, destinationCluster, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forward", reflect.TypeOf((*MockDLQMessageHandler)(nil).Forward), ctx, destinationCluster, lastMessageID)
+}
+
+// GarbageCollectDLQ mocks base method.
+func (m *MockDLQMessageHandler) GarbageCollectDLQ(ctx context.Context, olderThan time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GarbageCollectDLQ", ctx, olderThan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GarbageCollectDLQ indicates an expected call of GarbageCollectDLQ.
+func (mr *MockDLQMessageHandlerMockRecorder) GarbageCollectDLQ(ctx, olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GarbageCollectDLQ", reflect.TypeOf((*MockDLQMessageHandler)(nil).GarbageCollectDLQ), ctx, olderThan)
+}
+
+// GetConflictResolutionPolicies mocks base method.
+func (m *MockDLQMessageHandler) GetConflictResolutionPolicies(ctx context.Context) (map[string]ConflictResolutionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConflictResolutionPolicies", ctx)
+	ret0, _ := ret[0].(map[string]ConflictResolutionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConflictResolutionPolicies indicates an expected call of GetConflictResolutionPolicies.
+func (mr *MockDLQMessageHandlerMockRecorder) GetConflictResolutionPolicies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConflictResolutionPolicies", reflect.TypeOf((*MockDLQMessageHandler)(nil).GetConflictResolutionPolicies), ctx)
+}
+
+// Health mocks base method.
+func (m *MockDLQMessageHandler) Health() DLQHealth {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health")
+	ret0, _ := ret[0].(DLQHealth)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockDLQMessageHandlerMockRecorder) Health() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockDLQMessageHandler)(nil).Health))
+}
+
+// Import mocks base method.
+func (m *MockDLQMessageHandler) Import(ctx context.Context, r io.Reader, format ExportFormat) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, r, format)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockDLQMessageHandlerMockRecorder) Import(ctx, r, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockDLQMessageHandler)(nil).Import), ctx, r, format)
+}
+
 // Merge mocks base method.
-func (m *MockDLQMessageHandler) Merge(ctx context.Context, lastMessageID int64, pageSize int, pageToken []byte) ([]byte, error) {
+func (m *MockDLQMessageHandler) Merge(ctx context.Context, taskType types.ReplicationTaskType, mergeRequestID string, lastMessageID int64, pageSize int, pageToken []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Merge", ctx, lastMessageID, pageSize, pageToken)
+	ret := m.ctrl.Call(m, "Merge", ctx, taskType, mergeRequestID, lastMessageID, pageSize, pageToken)
 	ret0, _ := ret[0].([]byte)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Merge indicates an expected call of Merge.
-func (mr *MockDLQMessageHandlerMockRecorder) Merge(ctx, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+func (mr *MockDLQMessageHandlerMockRecorder) Merge(ctx, taskType, mergeRequestID, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockDLQMessageHandler)(nil).Merge), ctx, lastMessageID, pageSize, pageToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockDLQMessageHandler)(nil).Merge), ctx, taskType, mergeRequestID, lastMessageID, pageSize, pageToken)
+}
+
+// MergeHistory mocks base method.
+func (m *MockDLQMessageHandler) MergeHistory(ctx context.Context, limit int) ([]DLQMergeRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeHistory", ctx, limit)
+	ret0, _ := ret[0].([]DLQMergeRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeHistory indicates an expected call of MergeHistory.
+func (mr *MockDLQMessageHandlerMockRecorder) MergeHistory(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeHistory", reflect.TypeOf((*MockDLQMessageHandler)(nil).MergeHistory), ctx, limit)
+}
+
+// MergeShard mocks base method.
+func (m *MockDLQMessageHandler) MergeShard(ctx context.Context, shardID, shardCount int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeShard", ctx, shardID, shardCount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeShard indicates an expected call of MergeShard.
+func (mr *MockDLQMessageHandlerMockRecorder) MergeShard(ctx, shardID, shardCount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeShard", reflect.TypeOf((*MockDLQMessageHandler)(nil).MergeShard), ctx, shardID, shardCount)
+}
+
+// Pause mocks base method.
+func (m *MockDLQMessageHandler) Pause(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockDLQMessageHandlerMockRecorder) Pause(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockDLQMessageHandler)(nil).Pause), ctx)
 }
 
 // Purge mocks base method.
-func (m *MockDLQMessageHandler) Purge(ctx context.Context, lastMessageID int64) error {
+func (m *MockDLQMessageHandler) Purge(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Purge", ctx, lastMessageID)
+	ret := m.ctrl.Call(m, "Purge", ctx, taskType, lastMessageID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Purge indicates an expected call of Purge.
-func (mr *MockDLQMessageHandlerMockRecorder) Purge(ctx, lastMessageID interface{}) *gomock.Call {
+func (mr *MockDLQMessageHandlerMockRecorder) Purge(ctx, taskType, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockDLQMessageHandler)(nil).Purge), ctx, taskType, lastMessageID)
+}
+
+// PurgeByDomain mocks base method.
+func (m *MockDLQMessageHandler) PurgeByDomain(ctx context.Context, domainID string, lastMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeByDomain", ctx, domainID, lastMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeByDomain indicates an expected call of PurgeByDomain.
+func (mr *MockDLQMessageHandlerMockRecorder) PurgeByDomain(ctx, domainID, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeByDomain", reflect.TypeOf((*MockDLQMessageHandler)(nil).PurgeByDomain), ctx, domainID, lastMessageID)
+}
+
+// PurgeDomain mocks base method.
+func (m *MockDLQMessageHandler) PurgeDomain(ctx context.Context, domainID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDomain", ctx, domainID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDomain indicates an expected call of PurgeDomain.
+func (mr *MockDLQMessageHandlerMockRecorder) PurgeDomain(ctx, domainID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDomain", reflect.TypeOf((*MockDLQMessageHandler)(nil).PurgeDomain), ctx, domainID)
+}
+
+// PurgeWithBatchSize mocks base method.
+func (m *MockDLQMessageHandler) PurgeWithBatchSize(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64, batchSize int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeWithBatchSize", ctx, taskType, lastMessageID, batchSize)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeWithBatchSize indicates an expected call of PurgeWithBatchSize.
+func (mr *MockDLQMessageHandlerMockRecorder) PurgeWithBatchSize(ctx, taskType, lastMessageID, batchSize interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockDLQMessageHandler)(nil).Purge), ctx, lastMessageID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeWithBatchSize", reflect.TypeOf((*MockDLQMessageHandler)(nil).PurgeWithBatchSize), ctx, taskType, lastMessageID, batchSize)
 }
 
 // Read mocks base method.
-func (m *MockDLQMessageHandler) Read(ctx context.Context, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error) {
+func (m *MockDLQMessageHandler) Read(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Read", ctx, lastMessageID, pageSize, pageToken)
+	ret := m.ctrl.Call(m, "Read", ctx, taskType, lastMessageID, pageSize, pageToken)
 	ret0, _ := ret[0].([]*types.ReplicationTask)
 	ret1, _ := ret[1].([]byte)
 	ret2, _ := ret[2].(error)
@@ -113,9 +329,140 @@ func (m *MockDLQMessageHandler) Read(ctx context.Context, lastMessageID int64, p
 }
 
 // Read indicates an expected call of Read.
-func (mr *MockDLQMessageHandlerMockRecorder) Read(ctx, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+func (mr *MockDLQMessageHandlerMockRecorder) Read(ctx, taskType, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockDLQMessageHandler)(nil).Read), ctx, taskType, lastMessageID, pageSize, pageToken)
+}
+
+// ReadByDomain mocks base method.
+func (m *MockDLQMessageHandler) ReadByDomain(ctx context.Context, domainID string, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadByDomain", ctx, domainID, lastMessageID, pageSize, pageToken)
+	ret0, _ := ret[0].([]*types.ReplicationTask)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReadByDomain indicates an expected call of ReadByDomain.
+func (mr *MockDLQMessageHandlerMockRecorder) ReadByDomain(ctx, domainID, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByDomain", reflect.TypeOf((*MockDLQMessageHandler)(nil).ReadByDomain), ctx, domainID, lastMessageID, pageSize, pageToken)
+}
+
+// ReadByTimeRange mocks base method.
+func (m *MockDLQMessageHandler) ReadByTimeRange(ctx context.Context, startTime, endTime time.Time, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadByTimeRange", ctx, startTime, endTime, pageSize, pageToken)
+	ret0, _ := ret[0].([]*types.ReplicationTask)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReadByTimeRange indicates an expected call of ReadByTimeRange.
+func (mr *MockDLQMessageHandlerMockRecorder) ReadByTimeRange(ctx, startTime, endTime, pageSize, pageToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByTimeRange", reflect.TypeOf((*MockDLQMessageHandler)(nil).ReadByTimeRange), ctx, startTime, endTime, pageSize, pageToken)
+}
+
+// Ready mocks base method.
+func (m *MockDLQMessageHandler) Ready() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockDLQMessageHandlerMockRecorder) Ready() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockDLQMessageHandler)(nil).Ready))
+}
+
+// Reinject mocks base method.
+func (m *MockDLQMessageHandler) Reinject(ctx context.Context, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reinject", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reinject indicates an expected call of Reinject.
+func (mr *MockDLQMessageHandlerMockRecorder) Reinject(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reinject", reflect.TypeOf((*MockDLQMessageHandler)(nil).Reinject), ctx, messageID)
+}
+
+// Replay mocks base method.
+func (m *MockDLQMessageHandler) Replay(ctx context.Context, srcQueue, dstQueue ReplicationQueue, lastMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, srcQueue, dstQueue, lastMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockDLQMessageHandlerMockRecorder) Replay(ctx, srcQueue, dstQueue, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockDLQMessageHandler)(nil).Replay), ctx, srcQueue, dstQueue, lastMessageID)
+}
+
+// Resume mocks base method.
+func (m *MockDLQMessageHandler) Resume(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockDLQMessageHandlerMockRecorder) Resume(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockDLQMessageHandler)(nil).Read), ctx, lastMessageID, pageSize, pageToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockDLQMessageHandler)(nil).Resume), ctx)
+}
+
+// SetConflictResolutionPolicy mocks base method.
+func (m *MockDLQMessageHandler) SetConflictResolutionPolicy(ctx context.Context, domainName string, policy ConflictResolutionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetConflictResolutionPolicy", ctx, domainName, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetConflictResolutionPolicy indicates an expected call of SetConflictResolutionPolicy.
+func (mr *MockDLQMessageHandlerMockRecorder) SetConflictResolutionPolicy(ctx, domainName, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConflictResolutionPolicy", reflect.TypeOf((*MockDLQMessageHandler)(nil).SetConflictResolutionPolicy), ctx, domainName, policy)
+}
+
+// Shutdown mocks base method.
+func (m *MockDLQMessageHandler) Shutdown(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shutdown", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockDLQMessageHandlerMockRecorder) Shutdown(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockDLQMessageHandler)(nil).Shutdown), ctx)
+}
+
+// SimulateMerge mocks base method.
+func (m *MockDLQMessageHandler) SimulateMerge(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) ([]SimulatedOperation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateMerge", ctx, taskType, lastMessageID)
+	ret0, _ := ret[0].([]SimulatedOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateMerge indicates an expected call of SimulateMerge.
+func (mr *MockDLQMessageHandlerMockRecorder) SimulateMerge(ctx, taskType, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateMerge", reflect.TypeOf((*MockDLQMessageHandler)(nil).SimulateMerge), ctx, taskType, lastMessageID)
 }
 
 // Start mocks base method.
@@ -141,3 +488,18 @@ func (mr *MockDLQMessageHandlerMockRecorder) Stop() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockDLQMessageHandler)(nil).Stop))
 }
+
+// StreamDLQ mocks base method.
+func (m *MockDLQMessageHandler) StreamDLQ(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) (<-chan *types.ReplicationTask, <-chan error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamDLQ", ctx, taskType, lastMessageID)
+	ret0, _ := ret[0].(<-chan *types.ReplicationTask)
+	ret1, _ := ret[1].(<-chan error)
+	return ret0, ret1
+}
+
+// StreamDLQ indicates an expected call of StreamDLQ.
+func (mr *MockDLQMessageHandlerMockRecorder) StreamDLQ(ctx, taskType, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamDLQ", reflect.TypeOf((*MockDLQMessageHandler)(nil).StreamDLQ), ctx, taskType, lastMessageID)
+}
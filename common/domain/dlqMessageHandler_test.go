@@ -21,17 +21,40 @@
 package domain
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/metrics/prometheus"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/quotas"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -45,6 +68,7 @@ type (
 		mockReplicationTaskExecutor *MockReplicationTaskExecutor
 		mockReplicationQueue        *MockReplicationQueue
 		dlqMessageHandler           *dlqMessageHandlerImpl
+		ignoredGoroutines           goleak.Option
 	}
 )
 
@@ -62,6 +86,7 @@ func (s *dlqMessageHandlerSuite) TearDownSuite() {
 
 func (s *dlqMessageHandlerSuite) SetupTest() {
 	s.Assertions = require.New(s.T())
+	s.ignoredGoroutines = goleak.IgnoreCurrent()
 	s.controller = gomock.NewController(s.T())
 
 	s.mockReplicationTaskExecutor = NewMockReplicationTaskExecutor(s.controller)
@@ -69,14 +94,79 @@ func (s *dlqMessageHandlerSuite) SetupTest() {
 
 	logger := loggerimpl.NewLoggerForTest(s.Suite)
 	s.dlqMessageHandler = NewDLQMessageHandler(
-		s.mockReplicationTaskExecutor,
+		NewReplicationTaskExecutorRegistry(s.mockReplicationTaskExecutor),
 		s.mockReplicationQueue,
 		logger,
-		metrics.NewNoopMetricsClient(),
+		WithMaxRetries(dynamicconfig.GetIntPropertyFn(3)),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithPurgeBatchDelay(dynamicconfig.GetDurationPropertyFn(0)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
 	).(*dlqMessageHandlerImpl)
+	s.mockReplicationQueue.EXPECT().GetDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup).Return(false, nil).Times(1)
+	s.dlqMessageHandler.Start()
 }
 
 func (s *dlqMessageHandlerSuite) TearDownTest() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.NoError(s.dlqMessageHandler.Shutdown(ctx))
+	goleak.VerifyNone(s.T(), s.ignoredGoroutines)
+}
+
+func (s *dlqMessageHandlerSuite) TestShutdown_WaitsForCurrentWork() {
+	s.NoError(s.dlqMessageHandler.Close())
+	var intervals int32
+	sizeEmitInterval := func(...dynamicconfig.FilterOption) time.Duration {
+		// only the first size emission is due while the test runs
+		if atomic.AddInt32(&intervals, 1) == 1 {
+			return time.Millisecond
+		}
+		return time.Hour
+	}
+	s.dlqMessageHandler = NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(s.mockReplicationTaskExecutor),
+		s.mockReplicationQueue,
+		loggerimpl.NewNopLogger(),
+		WithSizeEmitInterval(sizeEmitInterval),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithPurgeBatchDelay(dynamicconfig.GetDurationPropertyFn(0)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+	).(*dlqMessageHandlerImpl)
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	s.mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).DoAndReturn(
+		func(context.Context, types.ReplicationTaskType) (int64, error) {
+			close(fetching)
+			<-release
+			return 5, nil
+		},
+	).Times(1)
+	s.mockReplicationQueue.EXPECT().GetDLQMessageTypeHistogram(gomock.Any()).Return(nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup).Return(false, nil).Times(1)
+
+	s.dlqMessageHandler.Start()
+	<-fetching
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	s.Equal(context.DeadlineExceeded, s.dlqMessageHandler.Shutdown(ctx))
+
+	// the size emission in progress completes before the loop exits
+	close(release)
+	s.NoError(s.dlqMessageHandler.Shutdown(context.Background()))
+	s.Equal(int64(5), s.dlqMessageHandler.Health().CurrentDLQDepth)
+}
+
+func (s *dlqMessageHandlerSuite) TestShutdown_NotStarted() {
+	handler := NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(s.mockReplicationTaskExecutor),
+		s.mockReplicationQueue,
+		loggerimpl.NewNopLogger(),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithPurgeBatchDelay(dynamicconfig.GetDurationPropertyFn(0)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+	)
+	s.NoError(handler.Shutdown(context.Background()))
+	s.NoError(handler.Close())
 }
 
 func (s *dlqMessageHandlerSuite) TestReadMessages() {
@@ -91,17 +181,35 @@ func (s *dlqMessageHandlerSuite) TestReadMessages() {
 			SourceTaskID: 1,
 		},
 	}
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID, pageSize, pageToken).
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
 		Return(tasks, nil, nil).Times(1)
 
-	resp, token, err := s.dlqMessageHandler.Read(context.Background(), lastMessageID, pageSize, pageToken)
+	resp, token, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, pageToken)
 
 	s.NoError(err)
 	s.Equal(tasks, resp)
 	s.Nil(token)
 }
 
+func (s *dlqMessageHandlerSuite) TestReadMessages_ConsumerGroup() {
+	s.dlqMessageHandler.consumerGroup = "audit-exporter"
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID: 31,
+		},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, "audit-exporter").Return(int64(30), nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(30), int64(40), 100, nil).
+		Return(tasks, nil, nil).Times(1)
+
+	resp, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, 40, 100, nil)
+
+	s.NoError(err)
+	s.Equal(tasks, resp)
+}
+
 func (s *dlqMessageHandlerSuite) TestReadMessages_ThrowErrorOnGetDLQAckLevel() {
 	lastMessageID := int64(20)
 	pageSize := 100
@@ -114,13 +222,14 @@ func (s *dlqMessageHandlerSuite) TestReadMessages_ThrowErrorOnGetDLQAckLevel() {
 		},
 	}
 	testError := fmt.Errorf("test")
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(int64(-1), testError).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(-1), testError).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(tasks, nil, nil).Times(0)
 
-	_, _, err := s.dlqMessageHandler.Read(context.Background(), lastMessageID, pageSize, pageToken)
+	_, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, pageToken)
 
-	s.Equal(testError, err)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQAckLevelNotFound))
 }
 
 func (s *dlqMessageHandlerSuite) TestReadMessages_ThrowErrorOnReadMessages() {
@@ -130,88 +239,372 @@ func (s *dlqMessageHandlerSuite) TestReadMessages_ThrowErrorOnReadMessages() {
 	pageToken := []byte{}
 
 	testError := fmt.Errorf("test")
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID, pageSize, pageToken).
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
 		Return(nil, nil, testError).Times(1)
 
-	_, _, err := s.dlqMessageHandler.Read(context.Background(), lastMessageID, pageSize, pageToken)
+	_, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, pageToken)
 
 	s.Equal(testError, err)
 }
 
-func (s *dlqMessageHandlerSuite) TestPurgeMessages() {
+func (s *dlqMessageHandlerSuite) TestReadMessages_RetryTransientErrors() {
+	core, logs := observer.New(zap.DebugLevel)
+	s.dlqMessageHandler.logger = loggerimpl.NewLogger(zap.New(core))
+	const maxRetries = 3
+	s.dlqMessageHandler.readMaxRetries = dynamicconfig.GetIntPropertyFn(maxRetries)
 	ackLevel := int64(10)
 	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
 
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID).Return(nil).Times(1)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), lastMessageID).Return(nil).Times(1)
-	err := s.dlqMessageHandler.Purge(context.Background(), lastMessageID)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	// every retry but the last one fails
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+			Return(nil, nil, &persistence.TimeoutError{Msg: "timeout"}).Times(maxRetries),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+			Return(tasks, nil, nil).Times(1),
+	)
 
+	resp, token, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, pageToken)
 	s.NoError(err)
+	s.Equal(tasks, resp)
+	s.Nil(token)
+	s.Equal(maxRetries, logs.FilterMessage("Retrying reading domain DLQ messages after a transient error").Len())
 }
 
-func (s *dlqMessageHandlerSuite) TestPurgeMessages_ThrowErrorOnGetDLQAckLevel() {
+func (s *dlqMessageHandlerSuite) TestReadMessages_RetriesExhausted() {
+	s.dlqMessageHandler.readMaxRetries = dynamicconfig.GetIntPropertyFn(2)
+	ackLevel := int64(10)
 	lastMessageID := int64(20)
-	testError := fmt.Errorf("test")
+	pageSize := 100
+	pageToken := []byte{}
 
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(int64(-1), testError).Times(1)
-	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(0)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any()).Times(0)
-	err := s.dlqMessageHandler.Purge(context.Background(), lastMessageID)
+	lastError := &persistence.TimeoutError{Msg: "last timeout"}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+			Return(nil, nil, &persistence.TimeoutError{Msg: "timeout"}).Times(2),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+			Return(nil, nil, lastError).Times(1),
+	)
 
-	s.Equal(testError, err)
+	_, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, pageToken)
+	s.Equal(lastError, err)
 }
 
-func (s *dlqMessageHandlerSuite) TestPurgeMessages_ThrowErrorOnPurgeMessages() {
+func (s *dlqMessageHandlerSuite) TestReadMessages_NonTransientErrorNotRetried() {
+	s.dlqMessageHandler.readMaxRetries = dynamicconfig.GetIntPropertyFn(3)
 	ackLevel := int64(10)
 	lastMessageID := int64(20)
-	testError := fmt.Errorf("test")
+	pageSize := 100
+	pageToken := []byte{}
 
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID).Return(testError).Times(1)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any()).Times(0)
-	err := s.dlqMessageHandler.Purge(context.Background(), lastMessageID)
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+		Return(nil, nil, testError).Times(1)
 
+	_, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, pageToken)
 	s.Equal(testError, err)
 }
 
-func (s *dlqMessageHandlerSuite) TestMergeMessages() {
+func (s *dlqMessageHandlerSuite) TestReadMessages_ClampPageSize() {
 	ackLevel := int64(10)
 	lastMessageID := int64(20)
-	pageSize := 100
-	pageToken := []byte{}
-	messageID := int64(11)
 
-	domainAttribute := &types.DomainTaskAttributes{
-		ID: uuid.New(),
+	tests := []struct {
+		pageSize         int
+		expectedPageSize int
+	}{
+		{pageSize: 0, expectedPageSize: 1000},
+		{pageSize: -1, expectedPageSize: 1000},
+		{pageSize: 1, expectedPageSize: 1},
+		{pageSize: 1000, expectedPageSize: 1000},
+	}
+	for _, tt := range tests {
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, tt.expectedPageSize, nil).
+			Return(nil, nil, nil).Times(1)
+
+		_, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, tt.pageSize, nil)
+		s.NoError(err)
 	}
+}
+
+func (s *dlqMessageHandlerSuite) TestReadMessages_PageSizeExceeded() {
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup).Times(0)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, int64(20), 1001, nil)
 
+	s.Equal(&ErrPageSizeExceeded{MaxPageSize: 1000}, err)
+	s.EqualError(err, "domain DLQ page size exceeds the maximum of 1000")
+}
+
+func (s *dlqMessageHandlerSuite) TestReadByDomain() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
 	tasks := []*types.ReplicationTask{
 		{
 			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
-			SourceTaskID:         messageID,
-			DomainTaskAttributes: domainAttribute,
+			SourceTaskID:         11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID"},
 		},
 	}
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID, pageSize, pageToken).
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQByDomain(gomock.Any(), "domainID", ackLevel, lastMessageID, 1000, nil).
 		Return(tasks, nil, nil).Times(1)
-	s.mockReplicationTaskExecutor.EXPECT().Execute(domainAttribute).Return(nil).Times(1)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), messageID).Return(nil).Times(1)
-	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), ackLevel, messageID).Return(nil).Times(1)
 
-	token, err := s.dlqMessageHandler.Merge(context.Background(), lastMessageID, pageSize, pageToken)
+	resp, token, err := s.dlqMessageHandler.ReadByDomain(context.Background(), "domainID", lastMessageID, 0, nil)
+
 	s.NoError(err)
+	s.Equal(tasks, resp)
 	s.Nil(token)
 }
 
-func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnGetDLQAckLevel() {
+func (s *dlqMessageHandlerSuite) TestReadByDomain_PageSizeExceeded() {
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup).Times(0)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQByDomain(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, _, err := s.dlqMessageHandler.ReadByDomain(context.Background(), "domainID", int64(20), 1001, nil)
+
+	s.Equal(&ErrPageSizeExceeded{MaxPageSize: 1000}, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReadByTimeRange() {
+	ackLevel := int64(10)
+	startTime := time.Unix(1600000000, 0)
+	endTime := startTime.Add(24 * time.Hour)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, EnqueuedAt: startTime.Add(time.Hour)},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQByTimeRange(gomock.Any(), startTime, endTime, ackLevel, common.EndMessageID, 100, nil).
+		Return(tasks, []byte{1}, nil).Times(1)
+
+	resp, token, err := s.dlqMessageHandler.ReadByTimeRange(context.Background(), startTime, endTime, 100, nil)
+
+	s.NoError(err)
+	s.Equal(tasks, resp)
+	s.Equal([]byte{1}, token)
+}
+
+func (s *dlqMessageHandlerSuite) TestReadByTimeRange_InvalidTimeRange() {
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQByTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	startTime := time.Unix(1600000000, 0)
+
+	_, _, err := s.dlqMessageHandler.ReadByTimeRange(context.Background(), startTime, startTime, 100, nil)
+
+	s.IsType(&types.BadRequestError{}, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeByDomain() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQByDomain(gomock.Any(), "domainID", ackLevel, lastMessageID).Return(nil).Times(1)
+	// the messages of other domains remain in the DLQ, so the ack level must not move
+	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.PurgeByDomain(context.Background(), "domainID", lastMessageID)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeByDomain_ThrowErrorOnRangeDelete() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQByDomain(gomock.Any(), "domainID", ackLevel, lastMessageID).Return(testError).Times(1)
+
+	err := s.dlqMessageHandler.PurgeByDomain(context.Background(), "domainID", lastMessageID)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQDeleteFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeDomain() {
+	// all the messages of the domain are deleted, whatever the ack level
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQByDomain(gomock.Any(), "domainID", int64(common.EmptyMessageID), int64(math.MaxInt64)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+	s.NoError(s.dlqMessageHandler.PurgeDomain(context.Background(), "domainID"))
+
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQByDomain(gomock.Any(), "domainID", gomock.Any(), gomock.Any()).Return(testError).Times(1)
+	err := s.dlqMessageHandler.PurgeDomain(context.Background(), "domainID")
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQDeleteFailed))
+}
+
+// Expected call order:
+//  1. GetDLQAckLevel
+//  2. RangeDeleteMessagesFromDLQ
+//  3. UpdateDLQAckLevel, only once the messages are deleted
+func (s *dlqMessageHandlerSuite) TestPurgeMessages() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, nil).
+			Return(tasks, nil, nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, lastMessageID).Return(nil),
+	)
+	err := s.dlqMessageHandler.Purge(context.Background(), AllTaskTypes, lastMessageID)
+
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeMessages_Archival() {
+	dlqArchiver, URI, cleanup := newLocalDLQArchiver(s.T())
+	defer cleanup()
+	s.dlqMessageHandler.dlqArchiver = dlqArchiver
+	s.dlqMessageHandler.archivalEnabled = dynamicconfig.GetBoolPropertyFn(true)
+	s.dlqMessageHandler.archivalURI = dynamicconfig.GetStringPropertyFn(URI.String())
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, nil).
+			Return(tasks, nil, nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, lastMessageID).Return(nil),
+	)
+	err := s.dlqMessageHandler.Purge(context.Background(), AllTaskTypes, lastMessageID)
+	s.NoError(err)
+
+	archived, err := dlqArchiver.Get(context.Background(), URI, AllTaskTypes, ackLevel, 12)
+	s.NoError(err)
+	s.Equal(tasks, archived)
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeMessages_ArchivalFailed() {
+	dlqArchiver, _, cleanup := newLocalDLQArchiver(s.T())
+	defer cleanup()
+	s.dlqMessageHandler.dlqArchiver = dlqArchiver
+	s.dlqMessageHandler.archivalEnabled = dynamicconfig.GetBoolPropertyFn(true)
+	s.dlqMessageHandler.archivalURI = dynamicconfig.GetStringPropertyFn("unknown:///tmp/dlq")
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, nil).
+		Return([]*types.ReplicationTask{{SourceTaskID: 11}}, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any()).Times(0)
+	err := s.dlqMessageHandler.Purge(context.Background(), AllTaskTypes, lastMessageID)
+
+	s.True(errors.Is(err, ErrDLQArchivalFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeMessages_SoftDelete() {
+	s.dlqMessageHandler.softDelete = dynamicconfig.GetBoolPropertyFn(true)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, nil).
+			Return(tasks, nil, nil),
+		s.mockReplicationQueue.EXPECT().RangeSoftDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, lastMessageID).Return(nil),
+	)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	err := s.dlqMessageHandler.Purge(context.Background(), AllTaskTypes, lastMessageID)
+
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestGarbageCollectDLQ() {
+	now := time.Now()
+	s.dlqMessageHandler.timeSource = clock.NewEventTimeSource().Update(now)
+	s.mockReplicationQueue.EXPECT().DeleteSoftDeletedMessagesFromDLQ(gomock.Any(), now.Add(-time.Hour)).Return(nil).Times(1)
+
+	err := s.dlqMessageHandler.GarbageCollectDLQ(context.Background(), time.Hour)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestGarbageCollectDLQ_ThrowErrorOnDelete() {
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().DeleteSoftDeletedMessagesFromDLQ(gomock.Any(), gomock.Any()).Return(testError).Times(1)
+
+	err := s.dlqMessageHandler.GarbageCollectDLQ(context.Background(), time.Hour)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQDeleteFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestGarbageCollectDLQ_InvalidThreshold() {
+	s.mockReplicationQueue.EXPECT().DeleteSoftDeletedMessagesFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.GarbageCollectDLQ(context.Background(), -time.Hour)
+	s.IsType(&types.BadRequestError{}, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeMessages_ThrowErrorOnGetDLQAckLevel() {
+	lastMessageID := int64(20)
+	testError := fmt.Errorf("test")
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(-1), testError).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(0)
+	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any()).Times(0)
+	err := s.dlqMessageHandler.Purge(context.Background(), AllTaskTypes, lastMessageID)
+
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQAckLevelNotFound))
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeMessages_ThrowErrorOnPurgeMessages() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	testError := fmt.Errorf("test")
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, nil).
+		Return([]*types.ReplicationTask{{SourceTaskID: lastMessageID}}, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID).Return(testError).Times(1)
+	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any()).Times(0)
+	err := s.dlqMessageHandler.Purge(context.Background(), AllTaskTypes, lastMessageID)
+
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQDeleteFailed))
+}
+
+// Expected call order:
+//  1. GetDLQAckLevel
+//  2. GetMessagesFromDLQ from the ack level
+//  3. ExecuteReplicationTask of each message of the page
+//  4. RangeDeleteMessagesFromDLQ of the page
+//  5. CompareAndSwapDLQAckLevel, only once the page is deleted
+//  6. RecordDLQMerge
+func (s *dlqMessageHandlerSuite) TestMergeMessages() {
+	ackLevel := int64(10)
 	lastMessageID := int64(20)
 	pageSize := 100
 	pageToken := []byte{}
 	messageID := int64(11)
-	testError := fmt.Errorf("test")
+
 	domainAttribute := &types.DomainTaskAttributes{
 		ID: uuid.New(),
 	}
@@ -223,142 +616,3925 @@ func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnGetDLQAckLevel()
 			DomainTaskAttributes: domainAttribute,
 		},
 	}
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(int64(-1), testError).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(tasks, nil, nil).Times(0)
-	s.mockReplicationTaskExecutor.EXPECT().Execute(gomock.Any()).Times(0)
-	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any()).Times(0)
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+			Return(tasks, nil, nil),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, messageID).Return(nil),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, messageID).Return(nil),
+		s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, record DLQMergeRecord) error {
+				s.Equal(messageID, record.StartMessageID)
+				s.Equal(messageID, record.EndMessageID)
+				s.Equal(int64(1), record.MergedCount)
+				s.Equal(int64(0), record.FailedCount)
+				s.Equal("test-operator", record.TriggeredBy)
+				// a trace ID is generated for merges without one
+				s.NotEmpty(record.TraceID)
+				s.False(record.MergedAt.IsZero())
+				return nil
+			},
+		),
+	)
 
-	token, err := s.dlqMessageHandler.Merge(context.Background(), lastMessageID, pageSize, pageToken)
-	s.Equal(testError, err)
+	ctx := ContextWithDLQMergeTrigger(context.Background(), "test-operator")
+	token, err := s.dlqMessageHandler.Merge(ctx, AllTaskTypes, "", lastMessageID, pageSize, pageToken)
+	s.NoError(err)
 	s.Nil(token)
 }
 
-func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnGetDLQMessages() {
+// Expected call order:
+//  1. GetDLQAckLevel
+//  2. GetMessagesFromDLQ from the ack level
+//  3. ExecuteReplicationTask of the message
+//  4. RangeDeleteMessagesFromDLQ of the page
+//  5. CompareAndSwapDLQAckLevel
+//  6. RecordDLQMerge, whose failure does not fail the merge
+func (s *dlqMessageHandlerSuite) TestMergeMessages_IgnoreErrorOnRecordMergeHistory() {
 	ackLevel := int64(10)
 	lastMessageID := int64(20)
 	pageSize := 100
-	pageToken := []byte{}
-	testError := fmt.Errorf("test")
-
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID, pageSize, pageToken).
-		Return(nil, nil, testError).Times(1)
-	s.mockReplicationTaskExecutor.EXPECT().Execute(gomock.Any()).Times(0)
-	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any()).Times(0)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+			Return(tasks, nil, nil),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil),
+		s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(fmt.Errorf("test")),
+	)
 
-	token, err := s.dlqMessageHandler.Merge(context.Background(), lastMessageID, pageSize, pageToken)
-	s.Equal(testError, err)
-	s.Nil(token)
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Equal(int64(11), s.dlqMessageHandler.Health().AckLevel)
 }
 
-func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnHandleReceivingTask() {
+func (s *dlqMessageHandlerSuite) TestMergeMessages_RecordMergeWithTimeSource() {
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	s.dlqMessageHandler.timeSource = timeSource
 	ackLevel := int64(10)
 	lastMessageID := int64(20)
 	pageSize := 100
-	pageToken := []byte{}
-	messageID1 := int64(11)
-	messageID2 := int64(12)
-	testError := fmt.Errorf("test")
-	domainAttribute1 := &types.DomainTaskAttributes{
-		ID: uuid.New(),
-	}
-	domainAttribute2 := &types.DomainTaskAttributes{
-		ID: uuid.New(),
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
 	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").
+		DoAndReturn(func(*types.ReplicationTask, string) error {
+			timeSource.Update(time.Unix(1005, 0))
+			return nil
+		})
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(time.Unix(1005, 0), record.MergedAt)
+			s.Equal(5*time.Second, record.Duration)
+			return nil
+		})
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_DomainFilter() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	s.dlqMessageHandler.domainFilter = NewDomainBlocklistFilter([]string{"pending-deletion"})
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
 	tasks := []*types.ReplicationTask{
 		{
 			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
-			SourceTaskID:         messageID1,
-			DomainTaskAttributes: domainAttribute1,
+			SourceTaskID:         11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "pending-deletion"},
 		},
 		{
 			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
-			SourceTaskID:         messageID2,
-			DomainTaskAttributes: domainAttribute2,
+			SourceTaskID:         12,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "active"},
+		},
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         13,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "pending-deletion"},
 		},
 	}
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID, pageSize, pageToken).
-		Return(tasks, nil, nil).Times(1)
-	s.mockReplicationTaskExecutor.EXPECT().Execute(domainAttribute1).Return(nil).Times(1)
-	s.mockReplicationTaskExecutor.EXPECT().Execute(domainAttribute2).Return(testError).Times(1)
-	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), messageID1).Return(nil).Times(1)
-	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), messageID2).Times(0)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), messageID1).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	// only the message of the domain which is not filtered is executed
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	// the filtered messages are deleted with the rest of the page
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(11), record.StartMessageID)
+			s.Equal(int64(13), record.EndMessageID)
+			s.Equal(int64(1), record.MergedCount)
+			s.Equal(int64(0), record.FailedCount)
+			return nil
+		},
+	)
 
-	token, err := s.dlqMessageHandler.Merge(context.Background(), lastMessageID, pageSize, pageToken)
-	s.Equal(testError, err)
-	s.Nil(token)
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Equal(int64(2), sumCapturedMetrics(assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_filtered_messages")))
 }
 
-func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnDeleteMessages() {
+func (s *dlqMessageHandlerSuite) TestMergeMessages_PrometheusMetrics() {
+	registry := prom.NewRegistry()
+	metricsClient, err := prometheus.NewDLQMetricsClient(registry, nil)
+	s.NoError(err)
+	s.dlqMessageHandler.metricsClient = metricsClient
+	s.dlqMessageHandler.domainFilter = NewDomainBlocklistFilter([]string{"pending-deletion"})
 	ackLevel := int64(10)
 	lastMessageID := int64(20)
 	pageSize := 100
-	pageToken := []byte{}
-	messageID1 := int64(11)
-	messageID2 := int64(12)
-	testError := fmt.Errorf("test")
-	domainAttribute1 := &types.DomainTaskAttributes{
-		ID: uuid.New(),
-	}
-	domainAttribute2 := &types.DomainTaskAttributes{
-		ID: uuid.New(),
-	}
 	tasks := []*types.ReplicationTask{
 		{
 			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
-			SourceTaskID:         messageID1,
-			DomainTaskAttributes: domainAttribute1,
+			SourceTaskID:         11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "pending-deletion"},
 		},
 		{
 			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
-			SourceTaskID:         messageID2,
-			DomainTaskAttributes: domainAttribute2,
+			SourceTaskID:         12,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "active"},
 		},
 	}
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID, pageSize, pageToken).
-		Return(tasks, nil, nil).Times(1)
-	s.mockReplicationTaskExecutor.EXPECT().Execute(domainAttribute1).Return(nil).Times(1)
-	s.mockReplicationTaskExecutor.EXPECT().Execute(domainAttribute2).Return(nil).Times(1)
-	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), ackLevel, messageID2).Return(testError).Times(1)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), messageID1).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-	token, err := s.dlqMessageHandler.Merge(context.Background(), lastMessageID, pageSize, pageToken)
-	s.Error(err)
-	s.Nil(token)
+	_, err = s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.NoError(testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP cadence_dlq_filtered_messages_total Number of domain DLQ messages of filtered domains deleted on merge.
+# TYPE cadence_dlq_filtered_messages_total counter
+cadence_dlq_filtered_messages_total{domain="_unknown_",source_cluster="_unknown_",task_type="Domain"} 1
+`), "cadence_dlq_filtered_messages_total"))
 }
 
-func (s *dlqMessageHandlerSuite) TestMergeMessages_IgnoreErrorOnUpdateDLQAckLevel() {
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Timeout() {
+	s.dlqMessageHandler.mergeTimeout = dynamicconfig.GetDurationPropertyFn(50 * time.Millisecond)
 	ackLevel := int64(10)
 	lastMessageID := int64(20)
 	pageSize := 100
-	pageToken := []byte{}
-	messageID := int64(11)
-	testError := fmt.Errorf("test")
-	domainAttribute := &types.DomainTaskAttributes{
-		ID: uuid.New(),
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
 	}
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+			Return(tasks, nil, nil),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil),
+		// the executor is slow, so the page times out once it returns
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").DoAndReturn(
+			func(*types.ReplicationTask, string) error {
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			},
+		),
+		// the messages merged before the timeout are acknowledged
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, record DLQMergeRecord) error {
+				s.Equal(int64(11), record.StartMessageID)
+				s.Equal(int64(12), record.EndMessageID)
+				s.Equal(int64(2), record.MergedCount)
+				return nil
+			},
+		),
+	)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.Equal(&ErrMergeTimeout{MergedCount: 2, AckLevel: 12}, err)
+	s.Equal(int64(12), atomic.LoadInt64(&s.dlqMessageHandler.ackLevel))
+}
 
+func (s *dlqMessageHandlerSuite) TestMergeMessages_TimeoutPriorityMerge() {
+	s.dlqMessageHandler.mergeTimeout = dynamicconfig.GetDurationPropertyFn(50 * time.Millisecond)
+	s.dlqMessageHandler.priorityMerge = dynamicconfig.GetBoolPropertyFn(true)
+	ackLevel := int64(10)
 	tasks := []*types.ReplicationTask{
-		{
-			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
-			SourceTaskID:         messageID,
-			DomainTaskAttributes: domainAttribute,
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12, Priority: DLQMessagePriorityHigh},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, Priority: DLQMessagePriorityDefault},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(20), 100, nil).
+		Return(tasks, nil, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), "").DoAndReturn(
+		func(*types.ReplicationTask, string) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
 		},
+	).Times(1)
+	// the merged message is not a prefix of the page, so nothing is acknowledged
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", 20, 100, nil)
+	s.Equal(&ErrMergeTimeout{MergedCount: 1, AckLevel: ackLevel}, err)
+}
+
+// TestDLQMergeOrdering merges pages of random permutations of message IDs, the ack level must only ever
+// move to the highest message ID merged without a gap, never to the last message ID of the permutation
+func (s *dlqMessageHandlerSuite) TestDLQMergeOrdering() {
+	const (
+		iterations = 20
+		pageSize   = 10
+	)
+	random := rand.New(rand.NewSource(0))
+	// every page is read from its own range of message IDs, so no message is skipped as a duplicate of a previous page
+	newPermutedPage := func(iteration int) (int64, []*types.ReplicationTask) {
+		ackLevel := int64(iteration * 100)
+		tasks := make([]*types.ReplicationTask, 0, pageSize)
+		for _, i := range random.Perm(pageSize) {
+			tasks = append(tasks, &types.ReplicationTask{
+				TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+				SourceTaskID: ackLevel + int64(i) + 1,
+			})
+		}
+		return ackLevel, tasks
 	}
-	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any()).Return(ackLevel, nil).Times(1)
-	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), ackLevel, lastMessageID, pageSize, pageToken).
-		Return(tasks, nil, nil).Times(1)
-	s.mockReplicationTaskExecutor.EXPECT().Execute(domainAttribute).Return(nil).Times(1)
-	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), ackLevel, messageID).Return(nil).Times(1)
-	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), messageID).Return(testError).Times(1)
 
-	token, err := s.dlqMessageHandler.Merge(context.Background(), lastMessageID, pageSize, pageToken)
-	s.NoError(err)
-	s.Nil(token)
+	s.Run("merged page", func() {
+		for iteration := 0; iteration < iterations; iteration++ {
+			ackLevel, tasks := newPermutedPage(iteration)
+			maxMessageID := ackLevel + pageSize
+			s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+			s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, maxMessageID, pageSize, nil).
+				Return(tasks, nil, nil).Times(1)
+			s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), "").Return(nil).Times(pageSize)
+			s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, maxMessageID).Return(nil).Times(1)
+			s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, maxMessageID).Return(nil).Times(1)
+			s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+			_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", maxMessageID, pageSize, nil)
+			s.NoError(err)
+			s.Equal(maxMessageID, s.dlqMessageHandler.Health().AckLevel)
+		}
+	})
+
+	s.Run("failed message", func() {
+		s.dlqMessageHandler.checkpointGranularity = dynamicconfig.GetStringPropertyFn(string(CheckpointGranularityPerTask))
+		defer func() {
+			s.dlqMessageHandler.checkpointGranularity = dynamicconfig.GetStringPropertyFn(string(CheckpointGranularityPerPage))
+		}()
+		testError := fmt.Errorf("test")
+		for iteration := 0; iteration < iterations; iteration++ {
+			startAckLevel, tasks := newPermutedPage(iteration + iterations)
+			maxMessageID := startAckLevel + pageSize
+			failedMessageID := tasks[random.Intn(pageSize)].SourceTaskID
+			ackLevel := startAckLevel
+			s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+			s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, maxMessageID, pageSize, nil).
+				Return(tasks, nil, nil).Times(1)
+			s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), "").DoAndReturn(
+				func(task *types.ReplicationTask, _ string) error {
+					s.LessOrEqual(task.SourceTaskID, failedMessageID)
+					if task.SourceTaskID == failedMessageID {
+						return testError
+					}
+					return nil
+				},
+			).Times(int(failedMessageID - startAckLevel))
+			s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), failedMessageID).Return(1, nil).Times(1)
+			// every checkpoint deletes the messages following the previous one and never goes past the failed message
+			s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ types.ReplicationTaskType, firstMessageID int64, lastMessageID int64) error {
+					s.Equal(ackLevel, firstMessageID)
+					s.Less(lastMessageID, failedMessageID)
+					return nil
+				},
+			).Times(int(failedMessageID - startAckLevel - 1))
+			s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ types.ReplicationTaskType, _ string, expectedAckLevel int64, newAckLevel int64) error {
+					s.Equal(ackLevel, expectedAckLevel)
+					s.Less(newAckLevel, failedMessageID)
+					ackLevel = newAckLevel
+					return nil
+				},
+			).Times(int(failedMessageID - startAckLevel - 1))
+
+			_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", maxMessageID, pageSize, nil)
+			s.True(errors.Is(err, testError))
+			// the ack level stops at the highest message ID merged before the failed one
+			s.Equal(failedMessageID-1, ackLevel)
+		}
+	})
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_OrderingGuarantee() {
+	const (
+		taskCount = 50
+		pageSize  = 10
+	)
+	lastMessageID := int64(taskCount)
+	random := rand.New(rand.NewSource(0))
+	ackLevel := int64(0)
+	var ackLevels []int64
+	var executedIDs []int64
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).DoAndReturn(
+		func(_ context.Context, _ types.ReplicationTaskType, _ string) (int64, error) {
+			return ackLevel, nil
+		},
+	).Times(taskCount / pageSize)
+	// every page holds the messages following the ack level, returned in shuffled order
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, gomock.Any(), lastMessageID, pageSize, nil).DoAndReturn(
+		func(_ context.Context, _ types.ReplicationTaskType, firstMessageID int64, _ int64, pageSize int, _ []byte) ([]*types.ReplicationTask, []byte, error) {
+			tasks := make([]*types.ReplicationTask, 0, pageSize)
+			for id := firstMessageID + 1; id <= firstMessageID+int64(pageSize); id++ {
+				tasks = append(tasks, &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: id})
+			}
+			random.Shuffle(len(tasks), func(i, j int) { tasks[i], tasks[j] = tasks[j], tasks[i] })
+			return tasks, nil, nil
+		},
+	).Times(taskCount / pageSize)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(task *types.ReplicationTask, _ string) error {
+			executedIDs = append(executedIDs, task.SourceTaskID)
+			return nil
+		},
+	).Times(taskCount)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, gomock.Any(), gomock.Any()).Return(nil).Times(taskCount / pageSize)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ types.ReplicationTaskType, _ string, expectedAckLevel int64, newAckLevel int64) error {
+			s.Equal(ackLevel, expectedAckLevel)
+			ackLevels = append(ackLevels, newAckLevel)
+			ackLevel = newAckLevel
+			return nil
+		},
+	).Times(taskCount / pageSize)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(taskCount / pageSize)
+
+	for i := 0; i < taskCount/pageSize; i++ {
+		_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+		s.NoError(err)
+	}
+
+	s.Len(executedIDs, taskCount)
+	for i := 1; i < len(executedIDs); i++ {
+		s.Less(executedIDs[i-1], executedIDs[i])
+	}
+	s.Len(ackLevels, taskCount/pageSize)
+	for i := 1; i < len(ackLevels); i++ {
+		s.LessOrEqual(ackLevels[i-1], ackLevels[i])
+	}
+	s.Equal(lastMessageID, ackLevel)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_TraceID() {
+	core, logs := observer.New(zap.DebugLevel)
+	s.dlqMessageHandler.logger = loggerimpl.NewLogger(zap.New(core))
+	s.dlqMessageHandler.largeMessageSize = dynamicconfig.GetIntPropertyFn(1000)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	traceID := "some random trace ID"
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, PayloadSize: 2000},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).DoAndReturn(
+		func(ctx context.Context, _ types.ReplicationTaskType, _ string) (int64, error) {
+			s.Equal(traceID, dlqMergeTraceIDFromContext(ctx))
+			return ackLevel, nil
+		},
+	).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(testError).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).DoAndReturn(
+		func(ctx context.Context, _ int64) (int, error) {
+			s.Equal(traceID, dlqMergeTraceIDFromContext(ctx))
+			return 3, nil
+		},
+	).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(testError).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, record DLQMergeRecord) error {
+			s.Equal(traceID, dlqMergeTraceIDFromContext(ctx))
+			s.Equal(traceID, record.TraceID)
+			return testError
+		},
+	).Times(1)
+
+	ctx := ContextWithDLQMergeTraceID(context.Background(), traceID)
+	_, err := s.dlqMessageHandler.Merge(ctx, AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+
+	// the large message, the poisoned message, the failed ack level update and the failed merge record are logged
+	entries := logs.AllUntimed()
+	s.Len(entries, 4)
+	for _, entry := range entries {
+		s.Equal(traceID, entry.ContextMap()["xdc-dlq-merge-trace-id"], entry.Message)
+	}
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_NackTransientError() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(&types.ServiceBusyError{}).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).Times(1)
+	// the transient failure is retried later instead of counting towards the retry attempts of the message
+	s.mockReplicationQueue.EXPECT().NackMessage(gomock.Any(), int64(12), dlqNackRetryDelay).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(2), record.MergedCount)
+			s.Equal(int64(0), record.FailedCount)
+			return nil
+		},
+	)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Equal(int64(1), sumCapturedMetrics(assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_nacked_messages")))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_NackFailed() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	executeErr := &types.ServiceBusyError{}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(executeErr).Times(1)
+	s.mockReplicationQueue.EXPECT().NackMessage(gomock.Any(), int64(11), dlqNackRetryDelay).Return(fmt.Errorf("test")).Times(1)
+	// the merge is aborted without acknowledging the page
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, executeErr))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_SpeculativeRetry_Succeeded() {
+	s.dlqMessageHandler.speculativeRetry = dynamicconfig.GetBoolPropertyFn(true)
+	s.dlqMessageHandler.speculativeBackoff = backoff.NewExponentialRetryPolicy(time.Millisecond)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	pageMerged := make(chan struct{})
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	gomock.InOrder(
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(&types.InternalServiceError{}).Times(1),
+		// the retry only succeeds once the rest of the page is merged, so the merge does not wait for it
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").DoAndReturn(
+			func(*types.ReplicationTask, string) error {
+				<-pageMerged
+				return nil
+			},
+		).Times(1),
+	)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").DoAndReturn(
+		func(*types.ReplicationTask, string) error {
+			close(pageMerged)
+			return nil
+		},
+	).Times(1)
+	s.mockReplicationQueue.EXPECT().NackMessage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(3), record.MergedCount)
+			s.Equal(int64(0), record.FailedCount)
+			return nil
+		},
+	)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_SpeculativeRetry_Failed() {
+	s.dlqMessageHandler.speculativeRetry = dynamicconfig.GetBoolPropertyFn(true)
+	policy := backoff.NewExponentialRetryPolicy(time.Millisecond)
+	policy.SetMaximumAttempts(2)
+	s.dlqMessageHandler.speculativeBackoff = policy
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	mergeRequestID := "merge-request-id"
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	s.mockReplicationQueue.EXPECT().GetDLQMergeFence(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(nil, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	// the failed execution of the merge and the two retries allowed by the retry policy
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(&types.InternalServiceError{}).Times(3)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).Times(1)
+	// the fence stays before the retried message, so re-driving the merge executes it again
+	s.mockReplicationQueue.EXPECT().UpdateDLQMergeFence(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, &DLQMergeFence{
+		RequestID: mergeRequestID,
+		MessageID: 11,
+	}).Return(nil).Times(1)
+	// the page is rolled back, none of its messages is acknowledged
+	s.mockReplicationQueue.EXPECT().NackMessage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, mergeRequestID, lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, ErrDLQSpeculativeRetryFailed))
+	s.IsType(&types.InternalServiceError{}, errors.Unwrap(err))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_NackMessageNotFound() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	notExistsErr := &types.EntityNotExistsError{Message: "domain DLQ message 11 not found"}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(&types.ServiceBusyError{}).Times(1)
+	s.mockReplicationQueue.EXPECT().NackMessage(gomock.Any(), int64(11), dlqNackRetryDelay).Return(notExistsErr).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, ErrDLQMessageNotFound))
+	s.False(errors.Is(err, ErrDLQExecutorFailed))
+	var dlqErr *DLQError
+	s.True(errors.As(err, &dlqErr))
+	s.Equal(notExistsErr, dlqErr.Cause)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Backpressure() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	s.dlqMessageHandler.backpressureRetries = dynamicconfig.GetIntPropertyFn(5)
+	s.dlqMessageHandler.backpressure = newDLQBackpressure(time.Millisecond, 10*time.Millisecond)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	// the destination throttles a burst of executions, the merge pauses and retries the message until it succeeds
+	gomock.InOrder(
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(&types.ServiceBusyError{}).Times(4),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1),
+	)
+	s.mockReplicationQueue.EXPECT().NackMessage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(2), record.MergedCount)
+			s.Equal(int64(0), record.FailedCount)
+			return nil
+		},
+	)
+
+	start := time.Now()
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	// the pauses of 1, 2, 4 and 8ms are shortened by up to 20% of jitter
+	s.True(time.Since(start) >= 12*time.Millisecond)
+	active, emitted := backpressureActiveGauge(scope.Snapshot())
+	s.True(emitted)
+	s.Equal(float64(0), active)
+	s.True(s.dlqMessageHandler.backpressure.remaining(time.Now()) <= 0)
+	// throttling does not open the circuit breaker
+	s.Equal(DLQCircuitBreakerStateClosed, s.dlqMessageHandler.Health().CircuitBreakerState)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Backpressure_AttemptsExhausted() {
+	s.dlqMessageHandler.backpressureRetries = dynamicconfig.GetIntPropertyFn(2)
+	s.dlqMessageHandler.backpressure = newDLQBackpressure(time.Millisecond, 10*time.Millisecond)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(&types.ServiceBusyError{}).Times(3)
+	// the message still throttled after its retries is retried later like any other transient failure
+	s.mockReplicationQueue.EXPECT().NackMessage(gomock.Any(), int64(11), dlqNackRetryDelay).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_DeferRetryingMessages() {
+	now := time.Now()
+	timeSource := clock.NewEventTimeSource()
+	timeSource.Update(now)
+	s.dlqMessageHandler.timeSource = timeSource
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 2
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, RetryAfter: now.Add(-time.Second)},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12, RetryAfter: now.Add(time.Minute)},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, []byte{1}, nil)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	// the message which is not visible yet is neither merged nor deleted
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	// the merge stops at the message which is not visible yet
+	s.Nil(token)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnGetDLQAckLevel() {
+	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
+	messageID := int64(11)
+	testError := fmt.Errorf("test")
+	domainAttribute := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID,
+			DomainTaskAttributes: domainAttribute,
+		},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(-1), testError).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(tasks, nil, nil).Times(0)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, pageToken)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQAckLevelNotFound))
+	s.Nil(token)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnGetDLQMessages() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
+	testError := fmt.Errorf("test")
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+		Return(nil, nil, testError).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, pageToken)
+	s.Equal(testError, err)
+	s.Nil(token)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnHandleReceivingTask() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
+	messageID1 := int64(11)
+	messageID2 := int64(12)
+	testError := fmt.Errorf("test")
+	domainAttribute1 := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+	domainAttribute2 := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID1,
+			DomainTaskAttributes: domainAttribute1,
+		},
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID2,
+			DomainTaskAttributes: domainAttribute2,
+		},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(testError).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), messageID2).Return(1, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, pageToken)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+	s.Nil(token)
+}
+
+// Expected call order:
+//  1. GetDLQAckLevel
+//  2. GetMessagesFromDLQ from the ack level
+//  3. ExecuteReplicationTask of each message of the page
+//  4. RangeDeleteMessagesFromDLQ of the page, which fails
+//
+// The ack level is never moved.
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ThrowErrorOnDeleteMessages() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
+	messageID1 := int64(11)
+	messageID2 := int64(12)
+	testError := fmt.Errorf("test")
+	domainAttribute1 := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+	domainAttribute2 := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID1,
+			DomainTaskAttributes: domainAttribute1,
+		},
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID2,
+			DomainTaskAttributes: domainAttribute2,
+		},
+	}
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+			Return(tasks, nil, nil),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, messageID2).Return(testError),
+	)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, pageToken)
+	s.Error(err)
+	s.Nil(token)
+}
+
+// Expected call order:
+//  1. GetDLQAckLevel
+//  2. GetMessagesFromDLQ from the ack level
+//  3. ExecuteReplicationTask of the message
+//  4. RangeDeleteMessagesFromDLQ of the page
+//  5. CompareAndSwapDLQAckLevel, whose failure does not fail the merge
+//  6. RecordDLQMerge
+func (s *dlqMessageHandlerSuite) TestMergeMessages_IgnoreErrorOnUpdateDLQAckLevel() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
+	messageID := int64(11)
+	testError := fmt.Errorf("test")
+	domainAttribute := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID,
+			DomainTaskAttributes: domainAttribute,
+		},
+	}
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+			Return(tasks, nil, nil),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, messageID).Return(nil),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, messageID).Return(testError),
+		s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, pageToken)
+	s.NoError(err)
+	s.Nil(token)
+}
+
+// Expected call order:
+//  1. GetDLQAckLevel of the task type
+//  2. RangeDeleteMessagesFromDLQ of the task type
+//  3. UpdateDLQAckLevel of the task type, only once the messages are deleted
+func (s *dlqMessageHandlerSuite) TestPurgeMessages_PerTaskType() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	taskType := types.ReplicationTaskTypeHistory
+
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), taskType, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), taskType, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, nil).
+			Return([]*types.ReplicationTask{{TaskType: taskType.Ptr(), SourceTaskID: lastMessageID}}, nil, nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), taskType, ackLevel, lastMessageID).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), taskType, DefaultConsumerGroup, lastMessageID).Return(nil),
+	)
+	err := s.dlqMessageHandler.Purge(context.Background(), taskType, lastMessageID)
+
+	s.NoError(err)
+}
+
+// Expected call order:
+//  1. GetDLQAckLevel
+//  2. GetMessagesFromDLQ, RangeDeleteMessagesFromDLQ and UpdateDLQAckLevel of each batch
+//  3. UpdateDLQAckLevel to the last message ID once no batch is left
+func (s *dlqMessageHandlerSuite) TestPurgeWithBatchSize_MultipleBatches() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	batchSize := 2
+	token1 := []byte{1}
+	token2 := []byte{2}
+	batch1 := []*types.ReplicationTask{{SourceTaskID: 11}, {SourceTaskID: 12}}
+	batch2 := []*types.ReplicationTask{{SourceTaskID: 13}, {SourceTaskID: 14}}
+	batch3 := []*types.ReplicationTask{{SourceTaskID: 15}}
+
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, batchSize, nil).
+			Return(batch1, token1, nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, batchSize, token1).
+			Return(batch2, token2, nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(12), int64(14)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(14)).Return(nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, batchSize, token2).
+			Return(batch3, nil, nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(14), int64(15)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(15)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, lastMessageID).Return(nil),
+	)
+	purgedCount, err := s.dlqMessageHandler.PurgeWithBatchSize(context.Background(), AllTaskTypes, lastMessageID, batchSize)
+
+	s.NoError(err)
+	s.Equal(int64(5), purgedCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestDryRunPurge() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	token := []byte{1}
+	page1 := []*types.ReplicationTask{{SourceTaskID: 11}, {SourceTaskID: 12}, {SourceTaskID: 15}}
+	page2 := []*types.ReplicationTask{{SourceTaskID: 19}, {SourceTaskID: 20}}
+
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, nil).
+			Return(page1, token, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqPurgeDefaultBatchSize, token).
+			Return(page2, nil, nil),
+	)
+	// the messages are only counted, they are neither deleted nor acknowledged
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	willDeleteCount, err := s.dlqMessageHandler.DryRunPurge(context.Background(), lastMessageID)
+
+	s.NoError(err)
+	s.Equal(int64(5), willDeleteCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestDryRunPurge_ThrowErrorOnGetDLQAckLevel() {
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(-1), errors.New("test"))
+
+	willDeleteCount, err := s.dlqMessageHandler.DryRunPurge(context.Background(), 20)
+
+	s.True(errors.Is(err, ErrDLQAckLevelNotFound))
+	s.Equal(int64(0), willDeleteCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeWithBatchSize_InvalidBatchSize() {
+	purgedCount, err := s.dlqMessageHandler.PurgeWithBatchSize(context.Background(), AllTaskTypes, 20, 0)
+
+	s.IsType(&types.BadRequestError{}, err)
+	s.Equal(int64(0), purgedCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestPurgeWithBatchSize_CanceledBetweenBatches() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.dlqMessageHandler.purgeBatchDelay = dynamicconfig.GetDurationPropertyFn(time.Hour)
+
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, 1, nil).
+			Return([]*types.ReplicationTask{{SourceTaskID: 11}}, []byte{1}, nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil),
+		s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(11)).DoAndReturn(
+			func(context.Context, types.ReplicationTaskType, string, int64) error {
+				cancel()
+				return nil
+			}),
+	)
+	purgedCount, err := s.dlqMessageHandler.PurgeWithBatchSize(ctx, AllTaskTypes, lastMessageID, 1)
+
+	s.Equal(context.Canceled, err)
+	s.Equal(int64(1), purgedCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_CustomTaskType() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	customTaskType := types.ReplicationTaskType(100)
+	tasks := []*types.ReplicationTask{
+		{TaskType: customTaskType.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	customExecutor := NewMockReplicationTaskExecutor(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(customTaskType, customExecutor)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	customExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Nil(token)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_UnregisteredTaskType() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	s.dlqMessageHandler.executors.UnregisterExecutor(types.ReplicationTaskTypeDomain)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(1, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, ErrUnsupportedReplicationTaskType))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_HistoryTask() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: 11, HistoryTaskV2Attributes: newHistoryTaskV2Attributes()},
+		{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: 12, HistoryTaskV2Attributes: newHistoryTaskV2Attributes()},
+	}
+	historyExecutor := NewMockHistoryReplicationTaskExecutor(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(types.ReplicationTaskTypeHistoryV2, historyExecutor)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	// the events of the first task were applied before, so executing it again is not a failure
+	historyExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(ErrDuplicateTask).Times(1)
+	historyExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(2), record.MergedCount)
+			s.Equal(int64(0), record.FailedCount)
+			return nil
+		},
+	).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Nil(token)
+	captured := assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_duplicate_skipped", map[string]string{
+		"taskType": types.ReplicationTaskTypeHistoryV2.String(),
+	})
+	s.Equal(int64(1), sumCapturedMetrics(captured))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_HistoryTask_Error() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: 11, HistoryTaskV2Attributes: newHistoryTaskV2Attributes()},
+	}
+	historyErr := &types.RetryTaskV2Error{DomainID: "domain-id"}
+	historyClient := history.NewMockClient(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(
+		types.ReplicationTaskTypeHistoryV2,
+		NewHistoryReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger()),
+	)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	historyClient.EXPECT().ReplicateEventsV2(gomock.Any(), gomock.Any()).Return(historyErr).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(1, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+	var retryErr *types.RetryTaskV2Error
+	s.True(errors.As(err, &retryErr))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_SyncActivityTask_WorkflowNotFound() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeSyncActivity.Ptr(), SourceTaskID: 11, SyncActivityTaskAttributes: newSyncActivityTaskAttributes()},
+		{TaskType: types.ReplicationTaskTypeSyncActivity.Ptr(), SourceTaskID: 12, SyncActivityTaskAttributes: newSyncActivityTaskAttributes()},
+		{TaskType: types.ReplicationTaskTypeSyncActivity.Ptr(), SourceTaskID: 13, SyncActivityTaskAttributes: newSyncActivityTaskAttributes()},
+	}
+	historyClient := history.NewMockClient(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(
+		types.ReplicationTaskTypeSyncActivity,
+		NewSyncActivityReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger()),
+	)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	// the workflows of the first two tasks are gone or completed, so their tasks are deleted without being applied
+	gomock.InOrder(
+		historyClient.EXPECT().SyncActivity(gomock.Any(), gomock.Any()).Return(&types.EntityNotExistsError{}),
+		historyClient.EXPECT().SyncActivity(gomock.Any(), gomock.Any()).Return(&types.WorkflowExecutionAlreadyCompletedError{}),
+		historyClient.EXPECT().SyncActivity(gomock.Any(), gomock.Any()).Return(nil),
+	)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Nil(token)
+	captured := assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_obsolete_skipped", map[string]string{
+		"taskType": types.ReplicationTaskTypeSyncActivity.String(),
+	})
+	s.Equal(int64(2), sumCapturedMetrics(captured))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ExecuteMetrics() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeSyncActivity.Ptr(), SourceTaskID: 12, SyncActivityTaskAttributes: newSyncActivityTaskAttributes()},
+		{TaskType: types.ReplicationTaskTypeSyncActivity.Ptr(), SourceTaskID: 13, SyncActivityTaskAttributes: newSyncActivityTaskAttributes()},
+	}
+	historyClient := history.NewMockClient(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(
+		types.ReplicationTaskTypeSyncActivity,
+		NewSyncActivityReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger()),
+	)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	gomock.InOrder(
+		historyClient.EXPECT().SyncActivity(gomock.Any(), gomock.Any()).Return(&types.EntityNotExistsError{}),
+		historyClient.EXPECT().SyncActivity(gomock.Any(), gomock.Any()).Return(nil),
+	)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+
+	latencies := captureMetrics(scope.Snapshot(), "dlq_execute_latency")
+	s.Len(latencies, 2)
+	s.True(containsMetricWithTags(latencies, map[string]string{"taskType": types.ReplicationTaskTypeDomain.String()}))
+	s.True(containsMetricWithTags(latencies, map[string]string{"taskType": types.ReplicationTaskTypeSyncActivity.String()}))
+	s.Equal(int64(3), sumCapturedMetrics(latencies))
+
+	errs := captureMetrics(scope.Snapshot(), "dlq_execute_errors")
+	s.Len(errs, 1)
+	s.True(containsMetricWithTags(errs, map[string]string{
+		"taskType":   types.ReplicationTaskTypeSyncActivity.String(),
+		"error_type": "WorkflowNotFound",
+	}))
+	s.Equal(int64(1), sumCapturedMetrics(errs))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ExecuteLatency() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	s.dlqMessageHandler.timeSource = timeSource
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	delays := map[int64]time.Duration{11: 3 * time.Millisecond, 12: 100 * time.Millisecond}
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), "").DoAndReturn(
+		func(task *types.ReplicationTask, _ string) error {
+			timeSource.Update(timeSource.Now().Add(delays[task.SourceTaskID]))
+			return nil
+		},
+	).Times(len(tasks))
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+
+	latencies := dlqLatencyHistograms(scope.Snapshot(), "dlq_execute_latency")
+	s.Len(latencies, 1)
+	// the delays fall into the buckets of 4ms and 128ms
+	s.Equal(map[time.Duration]int64{4 * time.Millisecond: 1, 128 * time.Millisecond: 1}, latencies[0])
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_FailoverMarker_Stale() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeFailoverMarker.Ptr(), SourceTaskID: 11, FailoverMarkerAttributes: newFailoverMarkerAttributes(5)},
+		{TaskType: types.ReplicationTaskTypeFailoverMarker.Ptr(), SourceTaskID: 12, FailoverMarkerAttributes: newFailoverMarkerAttributes(20)},
+	}
+	domainManager := persistence.NewMockDomainManager(s.controller)
+	historyClient := history.NewMockClient(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(
+		types.ReplicationTaskTypeFailoverMarker,
+		NewFailoverMarkerReplicationTaskExecutor(domainManager, historyClient, loggerimpl.NewNopLogger()),
+	)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	// the domain already failed over past the first marker, so its task is deleted without being applied
+	domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).Return(&persistence.GetDomainResponse{FailoverVersion: 10}, nil).Times(2)
+	expectNotifiedFailoverMarker(historyClient, newFailoverMarkerAttributes(20))
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Nil(token)
+	captured := assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_obsolete_skipped", map[string]string{
+		"taskType": types.ReplicationTaskTypeFailoverMarker.String(),
+	})
+	s.Equal(int64(1), sumCapturedMetrics(captured))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Grouped() {
+	s.dlqMessageHandler.groupedMerge = dynamicconfig.GetBoolPropertyFn(true)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	newTask := func(id int64, correlationID string) *types.ReplicationTask {
+		return &types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: id, CorrelationID: correlationID}
+	}
+	groups := map[string][]*types.ReplicationTask{
+		"run-a": {newTask(11, "run-a"), newTask(13, "run-a")},
+		"run-b": {newTask(12, "run-b")},
+	}
+	historyExecutor := NewMockHistoryReplicationTaskExecutor(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(types.ReplicationTaskTypeHistoryV2, historyExecutor)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQGrouped(gomock.Any(), ackLevel, lastMessageID, pageSize, nil).
+		Return(groups, []byte("token"), nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	// the tasks of a group are merged and deleted together before the next group
+	gomock.InOrder(
+		historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-a"][0], "").Return(nil),
+		historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-a"][1], "").Return(nil),
+		s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(nil),
+		s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(13)).Return(nil),
+		historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-b"][0], "").Return(nil),
+		s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(12)).Return(nil),
+	)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(11), record.StartMessageID)
+			s.Equal(int64(13), record.EndMessageID)
+			s.Equal(int64(3), record.MergedCount)
+			return nil
+		},
+	).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Equal([]byte("token"), token)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Grouped_Failure() {
+	s.dlqMessageHandler.groupedMerge = dynamicconfig.GetBoolPropertyFn(true)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	newTask := func(id int64, correlationID string) *types.ReplicationTask {
+		return &types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: id, CorrelationID: correlationID}
+	}
+	groups := map[string][]*types.ReplicationTask{
+		"run-a": {newTask(11, "run-a"), newTask(12, "run-a")},
+		"run-b": {newTask(13, "run-b"), newTask(14, "run-b")},
+	}
+	historyErr := &types.RetryTaskV2Error{DomainID: "domain-id"}
+	historyExecutor := NewMockHistoryReplicationTaskExecutor(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(types.ReplicationTaskTypeHistoryV2, historyExecutor)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQGrouped(gomock.Any(), ackLevel, lastMessageID, pageSize, nil).
+		Return(groups, nil, nil).Times(1)
+	historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-a"][0], "").Return(nil).Times(1)
+	historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-a"][1], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(12)).Return(nil).Times(1)
+	// the task after the failed one depends on it, so it is not executed and the whole group stays in the DLQ
+	historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-b"][0], "").Return(historyErr).Times(1)
+	historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-b"][1], "").Times(0)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(13)).Return(1, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(13)).Times(0)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(14)).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Grouped_PoisonedMessage() {
+	s.dlqMessageHandler.groupedMerge = dynamicconfig.GetBoolPropertyFn(true)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	groups := map[string][]*types.ReplicationTask{
+		"run-a": {
+			{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: 11, CorrelationID: "run-a"},
+			{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: 12, CorrelationID: "run-a"},
+		},
+	}
+	historyExecutor := NewMockHistoryReplicationTaskExecutor(s.controller)
+	s.dlqMessageHandler.executors.RegisterExecutor(types.ReplicationTaskTypeHistoryV2, historyExecutor)
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQGrouped(gomock.Any(), ackLevel, lastMessageID, pageSize, nil).
+		Return(groups, nil, nil).Times(1)
+	// the first task exhausted its retry attempts, so it is dropped with the task depending on it
+	historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-a"][0], "").Return(errors.New("poisoned")).Times(1)
+	historyExecutor.EXPECT().ExecuteReplicationTask(groups["run-a"][1], "").Times(0)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(3, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(0), record.MergedCount)
+			s.Equal(int64(2), record.FailedCount)
+			return nil
+		},
+	).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Nil(token)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_RouteToSourceCluster() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, SourceCluster: "cluster-b"},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12, SourceCluster: "cluster-c"},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	executorB := NewMockReplicationTaskExecutor(s.controller)
+	executorC := NewMockReplicationTaskExecutor(s.controller)
+	s.dlqMessageHandler.executors = NewReplicationTaskExecutorRegistry(NewClusterReplicationTaskExecutor(
+		s.mockReplicationTaskExecutor,
+		map[string]ReplicationTaskExecutor{
+			"cluster-b": executorB,
+			"cluster-c": executorC,
+		},
+	))
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	executorB.EXPECT().ExecuteReplicationTask(tasks[0], "cluster-b").Return(nil).Times(1)
+	executorC.EXPECT().ExecuteReplicationTask(tasks[1], "cluster-c").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_CircuitBreakerOpen() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	testError := fmt.Errorf("test")
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	s.dlqMessageHandler.circuitBreaker = newDLQCircuitBreaker(1, time.Minute, timeSource, metrics.NoopScope(metrics.Frontend), loggerimpl.NewNopLogger())
+
+	// the first merge opens the breaker
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(2)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(testError).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(1, nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+	s.Equal(DLQCircuitBreakerStateOpen, s.dlqMessageHandler.Health().CircuitBreakerState)
+
+	// the second merge is rejected without executing the message or counting an attempt
+	_, err = s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	var circuitBreakerErr *ErrCircuitBreakerOpen
+	s.True(errors.As(err, &circuitBreakerErr))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+
+	timeSource.Update(time.Unix(1060, 0))
+	s.Equal(DLQCircuitBreakerStateHalfOpen, s.dlqMessageHandler.Health().CircuitBreakerState)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ConflictPolicy_ErrorOnConflict() {
+	tasks := s.mergeConflictingMessage(map[string]ConflictResolutionPolicy{})
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), tasks[0].SourceTaskID).Return(1, nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", 20, 100, nil)
+	s.True(errors.Is(err, ErrNameUUIDCollision))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ConflictPolicy_OverwriteWithSource() {
+	tasks := s.mergeConflictingMessage(map[string]ConflictResolutionPolicy{"test-domain": ConflictResolutionPolicyOverwriteWithSource})
+	s.mockReplicationTaskExecutor.EXPECT().Overwrite(tasks[0].DomainTaskAttributes).Return(nil).Times(1)
+	s.expectMergedPage(tasks)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", 20, 100, nil)
+	s.NoError(err)
+	s.Equal(int64(1), s.dlqMessageHandler.Health().LastMergeCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ConflictPolicy_KeepLocal() {
+	tasks := s.mergeConflictingMessage(map[string]ConflictResolutionPolicy{"test-domain": ConflictResolutionPolicyKeepLocal})
+	s.mockReplicationTaskExecutor.EXPECT().Overwrite(gomock.Any()).Times(0)
+	s.expectMergedPage(tasks)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", 20, 100, nil)
+	s.NoError(err)
+}
+
+// mergeConflictingMessage sets up a merge of a page holding a domain DLQ message which conflicts with a local domain
+func (s *dlqMessageHandlerSuite) mergeConflictingMessage(policies map[string]ConflictResolutionPolicy) []*types.ReplicationTask {
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID: 11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{
+				ID:   uuid.New(),
+				Info: &types.DomainInfo{Name: "test-domain"},
+			},
+		},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), int64(20), 100, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(ErrNameUUIDCollision).Times(1)
+	s.mockReplicationQueue.EXPECT().GetDLQConflictResolutionPolicies(gomock.Any()).Return(policies, nil).Times(1)
+	return tasks
+}
+
+func (s *dlqMessageHandlerSuite) expectMergedPage(tasks []*types.ReplicationTask) {
+	lastMessageID := tasks[len(tasks)-1].SourceTaskID
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), lastMessageID).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(10), lastMessageID).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+}
+
+func (s *dlqMessageHandlerSuite) TestSetConflictResolutionPolicy() {
+	s.mockReplicationQueue.EXPECT().UpdateDLQConflictResolutionPolicy(gomock.Any(), "test-domain", ConflictResolutionPolicyKeepLocal).Return(nil).Times(1)
+	s.NoError(s.dlqMessageHandler.SetConflictResolutionPolicy(context.Background(), "test-domain", ConflictResolutionPolicyKeepLocal))
+
+	err := s.dlqMessageHandler.SetConflictResolutionPolicy(context.Background(), "", ConflictResolutionPolicyKeepLocal)
+	s.IsType(&types.BadRequestError{}, err)
+	err = s.dlqMessageHandler.SetConflictResolutionPolicy(context.Background(), "test-domain", "Unknown")
+	s.IsType(&types.BadRequestError{}, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_InvalidTask_Skip() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	s.dlqMessageHandler.invalidTaskPolicy = dynamicconfig.GetStringPropertyFn(string(InvalidTaskPolicySkip))
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12, DomainTaskAttributes: newValidDomainTaskAttributes()},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	// the invalid message is removed with the rest of the page without being executed
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(1), record.MergedCount)
+			s.Equal(int64(1), record.FailedCount)
+			return nil
+		},
+	).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Nil(token)
+	s.Equal(int64(1), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_invalid_messages")))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_InvalidTask_Fail() {
+	s.dlqMessageHandler.invalidTaskPolicy = dynamicconfig.GetStringPropertyFn(string(InvalidTaskPolicyFail))
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	// the invalid message is kept in the DLQ until it exhausts its retry attempts
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(1, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+	var invalidTaskErr *ErrInvalidTask
+	s.True(errors.As(err, &invalidTaskErr))
+	s.Equal("DomainTaskAttributes", invalidTaskErr.Field)
+	s.Equal(DLQCircuitBreakerStateClosed, s.dlqMessageHandler.Health().CircuitBreakerState)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_SkipPoisonedMessage() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
+	messageID1 := int64(11)
+	messageID2 := int64(12)
+	testError := fmt.Errorf("test")
+	domainAttribute1 := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+	domainAttribute2 := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID1,
+			DomainTaskAttributes: domainAttribute1,
+		},
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID2,
+			DomainTaskAttributes: domainAttribute2,
+		},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(testError).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), messageID1).Return(3, nil).Times(1)
+	// the poisoned message is deleted with the rest of the page
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, messageID2).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, messageID2).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(messageID1, record.StartMessageID)
+			s.Equal(messageID2, record.EndMessageID)
+			s.Equal(int64(1), record.MergedCount)
+			s.Equal(int64(1), record.FailedCount)
+			s.Equal(unknownDLQMergeTrigger, record.TriggeredBy)
+			return nil
+		},
+	).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, pageToken)
+	s.NoError(err)
+	s.Nil(token)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_PoisonedMessageMetricTags() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:      types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:  11,
+			SourceCluster: "standby",
+			DomainTaskAttributes: &types.DomainTaskAttributes{
+				ID:   uuid.New(),
+				Info: &types.DomainInfo{Name: "test-domain"},
+			},
+		},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "standby").Return(fmt.Errorf("test")).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(3, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	captured := assertDLQMetricTags(s.T(), scope.Snapshot(), "domain_replication_dlq_poisoned_message", map[string]string{
+		"domain":         "test-domain",
+		"source_cluster": "standby",
+		"taskType":       types.ReplicationTaskTypeDomain.String(),
+	})
+	s.Equal(int64(1), sumCapturedMetrics(captured))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_IncrementAttemptsOnEachFailedMerge() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
+	messageID := int64(11)
+	testError := fmt.Errorf("test")
+	domainAttribute := &types.DomainTaskAttributes{
+		ID: uuid.New(),
+	}
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         messageID,
+			DomainTaskAttributes: domainAttribute,
+		},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+		Return(tasks, nil, nil).Times(2)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(testError).Times(2)
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), messageID).Return(1, nil).Times(1),
+		s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), messageID).Return(2, nil).Times(1),
+	)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	for i := 0; i < 2; i++ {
+		_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, pageToken)
+		s.True(errors.Is(err, testError))
+		s.True(errors.Is(err, ErrDLQExecutorFailed))
+	}
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ReDriveWithSameRequestID_ExecutesOnce() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	pageToken := []byte{}
+	mergeRequestID := uuid.New()
+	testError := fmt.Errorf("test")
+	var tasks []*types.ReplicationTask
+	for messageID := int64(11); messageID <= 13; messageID++ {
+		tasks = append(tasks, &types.ReplicationTask{
+			TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID: messageID,
+			DomainTaskAttributes: &types.DomainTaskAttributes{
+				ID: uuid.New(),
+			},
+		})
+	}
+
+	// persisted merge fence shared by both merge attempts
+	var fence *DLQMergeFence
+	s.mockReplicationQueue.EXPECT().GetDLQMergeFence(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).
+		DoAndReturn(func(_ context.Context, _ types.ReplicationTaskType, _ string) (*DLQMergeFence, error) {
+			return fence, nil
+		}).Times(2)
+	s.mockReplicationQueue.EXPECT().UpdateDLQMergeFence(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ types.ReplicationTaskType, _ string, f *DLQMergeFence) error {
+			fence = f
+			return nil
+		}).Times(3)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, pageToken).
+		Return(tasks, nil, nil).Times(2)
+
+	// first merge applies two messages and crashes on the third one
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	gomock.InOrder(
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(testError).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).Times(1),
+	)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(13)).Return(1, nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, mergeRequestID, lastMessageID, pageSize, pageToken)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+	s.Equal(&DLQMergeFence{RequestID: mergeRequestID, MessageID: 12}, fence)
+
+	// re-driven merge only applies the remaining message
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	token, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, mergeRequestID, lastMessageID, pageSize, pageToken)
+	s.NoError(err)
+	s.Nil(token)
+}
+
+func (s *dlqMessageHandlerSuite) TestStreamDLQ() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageToken := []byte{1}
+	tasks1 := []*types.ReplicationTask{{SourceTaskID: 11}, {SourceTaskID: 12}}
+	tasks2 := []*types.ReplicationTask{{SourceTaskID: 13}}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+			Return(tasks1, pageToken, nil).Times(1),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, pageToken).
+			Return(tasks2, nil, nil).Times(1),
+	)
+
+	taskCh, errCh := s.dlqMessageHandler.StreamDLQ(context.Background(), AllTaskTypes, lastMessageID)
+	var result []*types.ReplicationTask
+	for task := range taskCh {
+		result = append(result, task)
+	}
+	s.NoError(<-errCh)
+	s.Equal(append(tasks1, tasks2...), result)
+}
+
+func (s *dlqMessageHandlerSuite) TestStreamDLQ_Error() {
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(0), testError).Times(1)
+
+	taskCh, errCh := s.dlqMessageHandler.StreamDLQ(context.Background(), AllTaskTypes, 20)
+	_, ok := <-taskCh
+	s.False(ok)
+	err := <-errCh
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQAckLevelNotFound))
+	_, ok = <-errCh
+	s.False(ok)
+}
+
+func (s *dlqMessageHandlerSuite) TestStreamDLQ_ContextCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	taskCh, errCh := s.dlqMessageHandler.StreamDLQ(ctx, AllTaskTypes, 20)
+	_, ok := <-taskCh
+	s.False(ok)
+	s.Equal(context.Canceled, <-errCh)
+}
+
+func (s *dlqMessageHandlerSuite) TestExportImport() {
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID: 11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{
+				DomainOperation: types.DomainOperationUpdate.Ptr(),
+				ID:              uuid.New(),
+				Info: &types.DomainInfo{
+					Name: "test-domain",
+					Data: map[string]string{"k": "v"},
+				},
+				Config: &types.DomainConfiguration{
+					WorkflowExecutionRetentionPeriodInDays: 7,
+					EmitMetric:                             true,
+				},
+				ReplicationConfig: &types.DomainReplicationConfiguration{
+					ActiveClusterName: "active",
+				},
+				ConfigVersion: 3,
+			},
+		},
+		{
+			TaskType:     types.ReplicationTaskTypeHistoryV2.Ptr(),
+			SourceTaskID: 12,
+			HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{
+				DomainID:   uuid.New(),
+				WorkflowID: "workflow-id",
+				RunID:      uuid.New(),
+				Events: &types.DataBlob{
+					EncodingType: types.EncodingTypeThriftRW.Ptr(),
+					Data:         []byte("line1\nline2"),
+				},
+			},
+		},
+	}
+
+	for _, format := range []ExportFormat{ExportFormatJSON, ExportFormatProtoText} {
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil).Times(1)
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), lastMessageID, dlqStreamPageSize, nil).
+			Return(tasks, nil, nil).Times(1)
+
+		var buffer bytes.Buffer
+		s.NoError(s.dlqMessageHandler.Export(context.Background(), AllTaskTypes, lastMessageID, &buffer, format))
+
+		var imported []*types.ReplicationTask
+		s.mockReplicationQueue.EXPECT().EnqueueBatch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, batch []*types.ReplicationTask) error {
+				imported = append(imported, batch...)
+				return nil
+			}).Times(1)
+		s.NoError(s.dlqMessageHandler.Import(context.Background(), &buffer, format))
+		s.Equal(tasks, imported)
+	}
+}
+
+func (s *dlqMessageHandlerSuite) TestImport_Batches() {
+	var buffer bytes.Buffer
+	encode, err := newDLQTaskEncoder(&buffer, ExportFormatJSON)
+	s.NoError(err)
+	var tasks []*types.ReplicationTask
+	for i := 0; i < 2*dlqEnqueueBatchSize+5; i++ {
+		task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: int64(i)}
+		tasks = append(tasks, task)
+		s.NoError(encode(task))
+	}
+
+	var batchSizes []int
+	var imported []*types.ReplicationTask
+	s.mockReplicationQueue.EXPECT().EnqueueBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, batch []*types.ReplicationTask) error {
+			batchSizes = append(batchSizes, len(batch))
+			imported = append(imported, batch...)
+			return nil
+		}).Times(3)
+	s.NoError(s.dlqMessageHandler.Import(context.Background(), &buffer, ExportFormatJSON))
+	s.Equal([]int{dlqEnqueueBatchSize, dlqEnqueueBatchSize, 5}, batchSizes)
+	s.Equal(tasks, imported)
+}
+
+func (s *dlqMessageHandlerSuite) TestImport_InvalidPayload() {
+	s.mockReplicationQueue.EXPECT().EnqueueBatch(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Import(context.Background(), bytes.NewBufferString("not a task"), ExportFormatProtoText)
+	s.Error(err)
+}
+
+func TestDLQErrorType(t *testing.T) {
+	require.Equal(t, "DuplicateTask", dlqErrorType(ErrDuplicateTask))
+	require.Equal(t, "NameUUIDCollision", dlqErrorType(ErrNameUUIDCollision))
+	require.Equal(t, "types.BadRequestError", dlqErrorType(ErrInvalidDomainID))
+	require.Equal(t, "types.ServiceBusyError", dlqErrorType(&types.ServiceBusyError{}))
+}
+
+func TestParseExportFormat(t *testing.T) {
+	format, err := ParseExportFormat("json")
+	require.NoError(t, err)
+	require.Equal(t, ExportFormatJSON, format)
+
+	format, err = ParseExportFormat("ProtoText")
+	require.NoError(t, err)
+	require.Equal(t, ExportFormatProtoText, format)
+
+	_, err = ParseExportFormat("yaml")
+	require.Error(t, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_RateLimited() {
+	limiter := &countingLimiter{Limiter: s.dlqMessageHandler.mergeRateLimiter}
+	s.dlqMessageHandler.mergeRateLimiter = limiter
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Equal(2, limiter.waitCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_RateLimitHotReload() {
+	mergeRPS := 1000
+	s.NoError(s.dlqMessageHandler.Close())
+	s.dlqMessageHandler = NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(s.mockReplicationTaskExecutor),
+		s.mockReplicationQueue,
+		loggerimpl.NewNopLogger(),
+		WithRateLimit(func(...dynamicconfig.FilterOption) int { return mergeRPS }, dynamicconfig.GetIntPropertyFn(1000)),
+		WithPurgeBatchDelay(dynamicconfig.GetDurationPropertyFn(0)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+	).(*dlqMessageHandlerImpl)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	// merge is not throttled with the initial rate
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := s.dlqMessageHandler.Merge(ctx, AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+
+	// lowered rate takes effect on the next merge, the second message cannot be applied within the deadline
+	mergeRPS = 1
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = s.dlqMessageHandler.Merge(ctx, AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.Error(err)
+}
+
+type countingLimiter struct {
+	quotas.Limiter
+	waitCount int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waitCount++
+	return l.Limiter.Wait(ctx)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_EmitMessageAge() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, EnqueuedAt: time.Now().Add(-time.Minute)},
+		// messages without enqueue time are not measured
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	// histogram samples are reset when a snapshot is taken
+	snapshot := scope.Snapshot()
+	assertDLQMetricTags(s.T(), snapshot, "dlq_message_age", map[string]string{
+		"domain":         "_unknown_",
+		"source_cluster": "_unknown_",
+		"taskType":       types.ReplicationTaskTypeDomain.String(),
+	})
+
+	var histograms []tally.HistogramSnapshot
+	for _, histogram := range snapshot.Histograms() {
+		if histogram.Name() == "test.dlq_message_age" {
+			histograms = append(histograms, histogram)
+		}
+	}
+	s.Len(histograms, 1)
+	for _, histogram := range histograms {
+		var count int64
+		for bucket, c := range histogram.Durations() {
+			if c > 0 {
+				s.True(bucket >= time.Minute)
+			}
+			count += c
+		}
+		s.Equal(int64(1), count)
+	}
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_EmitMessageSize() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	core, logs := observer.New(zap.ErrorLevel)
+	s.dlqMessageHandler.logger = loggerimpl.NewLogger(zap.New(core))
+	s.dlqMessageHandler.largeMessageSize = dynamicconfig.GetIntPropertyFn(1000)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, PayloadSize: 100},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12, PayloadSize: 2000},
+		// messages without a size were not read from the persistence and are not measured
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+
+	captured := assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_message_size_bytes", map[string]string{
+		"taskType": types.ReplicationTaskTypeDomain.String(),
+	})
+	s.Equal(int64(2), sumCapturedMetrics(captured))
+	// only the message above the threshold is reported
+	entries := logs.AllUntimed()
+	s.Len(entries, 1)
+	fields := entries[0].ContextMap()
+	s.Equal(int64(12), fields["queue-task-id"])
+	s.Equal(int64(2000), fields["xdc-dlq-message-size-bytes"])
+	s.Equal(int64(1000), fields["xdc-dlq-large-message-threshold-bytes"])
+}
+
+func (s *dlqMessageHandlerSuite) TestReadMessages_EmitMessageSize() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, PayloadSize: 300},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+
+	_, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, nil)
+	s.NoError(err)
+
+	var sizes []tally.HistogramSnapshot
+	for _, histogram := range scope.Snapshot().Histograms() {
+		if histogram.Name() == "test.dlq_message_size_bytes" {
+			sizes = append(sizes, histogram)
+		}
+	}
+	s.Len(sizes, 1)
+	// the size falls into the bucket of 512 bytes
+	s.Equal(int64(1), sizes[0].Values()[512])
+}
+
+func (s *dlqMessageHandlerSuite) TestReadMessages_EmitReadPageLatency() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	s.dlqMessageHandler.timeSource = timeSource
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).DoAndReturn(
+		func(context.Context, types.ReplicationTaskType, int64, int64, int, []byte) ([]*types.ReplicationTask, []byte, error) {
+			timeSource.Update(timeSource.Now().Add(40 * time.Millisecond))
+			return nil, nil, nil
+		},
+	).Times(1)
+
+	_, _, err := s.dlqMessageHandler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, nil)
+	s.NoError(err)
+
+	latencies := dlqLatencyHistograms(scope.Snapshot(), "dlq_read_page_latency")
+	s.Len(latencies, 1)
+	// the delay of the read falls into the bucket of 64ms
+	s.Equal(map[time.Duration]int64{64 * time.Millisecond: 1}, latencies[0])
+}
+
+func (s *dlqMessageHandlerSuite) TestEmitDLQMessageTypeCounts() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQMessageTypeHistogram(gomock.Any()).Return(map[types.ReplicationTaskType]int64{
+			types.ReplicationTaskTypeDomain:    3,
+			types.ReplicationTaskTypeHistoryV2: 1,
+		}, nil).Times(1),
+		s.mockReplicationQueue.EXPECT().GetDLQMessageTypeHistogram(gomock.Any()).Return(map[types.ReplicationTaskType]int64{
+			types.ReplicationTaskTypeDomain: 2,
+		}, nil).Times(1),
+	)
+
+	counts, err := s.dlqMessageHandler.fetchAndEmitDLQMessageTypeCounts(context.Background(), nil)
+	s.NoError(err)
+	s.Equal(map[string]float64{
+		types.ReplicationTaskTypeDomain.String():    3,
+		types.ReplicationTaskTypeHistoryV2.String(): 1,
+	}, dlqMessageTypeCountGauges(scope.Snapshot()))
+
+	// the task types without messages anymore are reset
+	_, err = s.dlqMessageHandler.fetchAndEmitDLQMessageTypeCounts(context.Background(), counts)
+	s.NoError(err)
+	s.Equal(map[string]float64{
+		types.ReplicationTaskTypeDomain.String():    2,
+		types.ReplicationTaskTypeHistoryV2.String(): 0,
+	}, dlqMessageTypeCountGauges(scope.Snapshot()))
+}
+
+func (s *dlqMessageHandlerSuite) TestEmitDLQMessageTypeCounts_Error() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	s.mockReplicationQueue.EXPECT().GetDLQMessageTypeHistogram(gomock.Any()).Return(nil, errors.New("test")).Times(1)
+
+	_, err := s.dlqMessageHandler.fetchAndEmitDLQMessageTypeCounts(context.Background(), nil)
+	s.Error(err)
+	s.Empty(dlqMessageTypeCountGauges(scope.Snapshot()))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_AckLevelConflict() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).
+		Return(ErrDLQAckLevelConflict).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.Equal(ErrDLQAckLevelConflict, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_ConcurrentMergeDoesNotRegressAckLevel() {
+	var mu sync.Mutex
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+
+	// both merges read the same ack level before either of them moves it
+	var readBarrier sync.WaitGroup
+	readBarrier.Add(2)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).DoAndReturn(
+		func(_ context.Context, _ types.ReplicationTaskType, _ string) (int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return ackLevel, nil
+		},
+	).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), lastMessageID, pageSize, nil).DoAndReturn(
+		func(_ context.Context, _ types.ReplicationTaskType, _, _ int64, _ int, _ []byte) ([]*types.ReplicationTask, []byte, error) {
+			readBarrier.Done()
+			readBarrier.Wait()
+			return tasks, nil, nil
+		},
+	).Times(2)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), int64(12)).Return(nil).Times(2)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(10), int64(12)).DoAndReturn(
+		func(_ context.Context, _ types.ReplicationTaskType, _ string, expectedLevel int64, newLevel int64) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if ackLevel != expectedLevel {
+				return ErrDLQAckLevelConflict
+			}
+			ackLevel = newLevel
+			return nil
+		},
+	).Times(2)
+	// only the merge which moved the ack level records its batch
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	var conflicts int
+	for _, err := range errs {
+		if err != nil {
+			s.Equal(ErrDLQAckLevelConflict, err)
+			conflicts++
+		}
+	}
+	s.Equal(1, conflicts)
+	s.Equal(int64(12), ackLevel)
+}
+
+// Expected call order:
+//  1. GetDLQAckLevel, once by Forward and once by the stream
+//  2. GetMessagesFromDLQ from the ack level
+//  3. EnqueueForCluster of each message
+//  4. RangeDeleteMessagesFromDLQ of the forwarded messages
+//  5. CompareAndSwapDLQAckLevel, only once the forwarded messages are deleted
+func (s *dlqMessageHandlerSuite) TestForwardMessages() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2),
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+			Return(tasks, nil, nil),
+		s.mockReplicationQueue.EXPECT().EnqueueForCluster(gomock.Any(), "destination", tasks[0]).Return(nil),
+		s.mockReplicationQueue.EXPECT().EnqueueForCluster(gomock.Any(), "destination", tasks[1]).Return(nil),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil),
+	)
+
+	err := s.dlqMessageHandler.Forward(context.Background(), "destination", lastMessageID)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestForwardMessages_PartialFailure() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().EnqueueForCluster(gomock.Any(), "destination", tasks[0]).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().EnqueueForCluster(gomock.Any(), "destination", tasks[1]).Return(testError).Times(1)
+	// only the forwarded message is removed, the rest stay in the DLQ
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1)
+
+	err := s.dlqMessageHandler.Forward(context.Background(), "destination", lastMessageID)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQQueueFull))
+}
+
+func (s *dlqMessageHandlerSuite) TestForwardMessages_NothingForwarded() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().EnqueueForCluster(gomock.Any(), "destination", tasks[0]).Return(testError).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Forward(context.Background(), "destination", lastMessageID)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQQueueFull))
+}
+
+func (s *dlqMessageHandlerSuite) TestForwardMessages_UnsupportedTaskType() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	// forwarding stops before the history task, which the remote clusters cannot apply from the replication queue
+	s.mockReplicationQueue.EXPECT().EnqueueForCluster(gomock.Any(), "destination", tasks[0]).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1)
+
+	err := s.dlqMessageHandler.Forward(context.Background(), "destination", lastMessageID)
+	s.IsType(&types.BadRequestError{}, err)
+	s.False(errors.Is(err, ErrDLQQueueFull))
+}
+
+func (s *dlqMessageHandlerSuite) TestForwardMessages_InvalidDestinationCluster() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	invalidClusterErr := &types.BadRequestError{Message: "invalid destination cluster"}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().EnqueueForCluster(gomock.Any(), "", tasks[0]).Return(invalidClusterErr).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Forward(context.Background(), "", lastMessageID)
+	s.Equal(invalidClusterErr, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReinject() {
+	messageID := int64(12)
+	task := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID:         messageID,
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domain-id"},
+		CreationTime:         common.Int64Ptr(100),
+		SourceCluster:        "standby",
+		EnqueuedAt:           time.Unix(0, 200),
+	}
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, messageID-1, messageID, 1, nil).
+			Return([]*types.ReplicationTask{task}, nil, nil),
+		// the DLQ message ID and the fields only kept in the DLQ are not published
+		s.mockReplicationQueue.EXPECT().Publish(gomock.Any(), &types.ReplicationTask{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domain-id"},
+			CreationTime:         common.Int64Ptr(100),
+		}).Return(nil),
+		s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), messageID).Return(nil),
+	)
+	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Reinject(context.Background(), messageID)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReinject_MessageNotFound() {
+	messageID := int64(12)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, messageID-1, messageID, 1, nil).
+		Return(nil, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Reinject(context.Background(), messageID)
+	s.True(errors.Is(err, ErrDLQMessageNotFound))
+	var notExistsErr *types.EntityNotExistsError
+	s.True(errors.As(err, &notExistsErr))
+}
+
+func (s *dlqMessageHandlerSuite) TestReinject_PublishFailed() {
+	messageID := int64(12)
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, messageID-1, messageID, 1, nil).
+		Return([]*types.ReplicationTask{{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: messageID}}, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(testError).Times(1)
+	// the message stays in the DLQ
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Reinject(context.Background(), messageID)
+	s.Equal(testError, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReinject_UnsupportedTaskType() {
+	messageID := int64(12)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, messageID-1, messageID, 1, nil).
+		Return([]*types.ReplicationTask{{
+			TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+			SourceTaskID:            messageID,
+			HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{DomainID: "domain-id"},
+		}}, nil, nil).Times(1)
+	// a task without domain attributes would stop the remote clusters from reading the replication queue
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Reinject(context.Background(), messageID)
+	s.IsType(&types.BadRequestError{}, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReinject_PublishUnsupportedTaskType() {
+	messageID := int64(12)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, messageID-1, messageID, 1, nil).
+		Return([]*types.ReplicationTask{{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: messageID}}, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(ErrUnsupportedReplicationTaskType).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	// the publish error is not reported as a full queue
+	err := s.dlqMessageHandler.Reinject(context.Background(), messageID)
+	s.Equal(ErrUnsupportedReplicationTaskType, err)
+	s.False(errors.Is(err, ErrDLQQueueFull))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_SkipDuplicates() {
+	s.dlqMessageHandler.deduplicator = newDLQDeduplicator(dynamicconfig.GetIntPropertyFn(100), dynamicconfig.GetFloatPropertyFn(0.0001))
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	// duplicated messages of the same task have different DLQ message IDs
+	tasks := []*types.ReplicationTask{
+		newDomainTaskForDeduplication("domainA", 1),
+		newDomainTaskForDeduplication("domainB", 1),
+		newDomainTaskForDeduplication("domainA", 1),
+		newDomainTaskForDeduplication("domainA", 2),
+		newDomainTaskForDeduplication("domainB", 1),
+	}
+	for i, task := range tasks {
+		task.SourceTaskID = ackLevel + int64(i) + 1
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[3], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(15)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(15)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	captured := assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_duplicate_skipped",
+		map[string]string{"domain": "domainA", "taskType": types.ReplicationTaskTypeDomain.String()},
+		map[string]string{"domain": "domainB", "taskType": types.ReplicationTaskTypeDomain.String()},
+	)
+	s.Equal(int64(2), sumCapturedMetrics(captured))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_SkipSeenTaskIDs() {
+	s.dlqMessageHandler.seenTaskIDs = newDLQSeenTaskIDs(100)
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+			Return(tasks[:2], nil, nil).Times(1),
+		// the ack level did not move, so the executed messages are read again
+		s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+			Return(tasks, nil, nil).Times(1),
+	)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).Times(1)
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(errors.New("test")).Times(1),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1),
+	)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(1), record.MergedCount)
+			return nil
+		},
+	).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, ErrDLQDeleteFailed))
+	_, err = s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	captured := assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_duplicate_skipped")
+	s.Equal(int64(2), sumCapturedMetrics(captured))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_RetryFailedMessageIsNotDuplicate() {
+	s.dlqMessageHandler.deduplicator = newDLQDeduplicator(dynamicconfig.GetIntPropertyFn(100), dynamicconfig.GetFloatPropertyFn(0.0001))
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		newDomainTaskForDeduplication("domainA", 1),
+		newDomainTaskForDeduplication("domainA", 1),
+	}
+	tasks[0].SourceTaskID = 11
+	tasks[1].SourceTaskID = 12
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(3, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(fmt.Errorf("test")).Times(1)
+	// the duplicate is still executed since the first message failed
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_PartialPageFailureKeepsPage() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	testError := fmt.Errorf("test")
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(2)
+
+	// the first message is poisoned and the third one fails, nothing of the page is deleted
+	gomock.InOrder(
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(testError).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(testError).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(testError).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).Times(1),
+	)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(3, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(13)).Return(1, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+
+	// the whole page is committed once it is retried successfully
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err = s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_CheckpointPerTask() {
+	s.dlqMessageHandler.checkpointGranularity = dynamicconfig.GetStringPropertyFn(string(CheckpointGranularityPerTask))
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	testError := fmt.Errorf("test")
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+
+	// the merge crashes on the third message after checkpointing the first two
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	gomock.InOrder(
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(11), int64(12)).Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(11), int64(12)).Return(nil).Times(1),
+		s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(testError).Times(1),
+	)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(13)).Return(1, nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, testError))
+
+	// the retried merge resumes from the checkpoint without executing the checkpointed messages again
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(12), nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(12), lastMessageID, pageSize, nil).
+		Return(tasks[2:], nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(12), int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(12), int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record DLQMergeRecord) error {
+			s.Equal(int64(13), record.StartMessageID)
+			s.Equal(int64(13), record.EndMessageID)
+			s.Equal(int64(1), record.MergedCount)
+			return nil
+		}).Times(1)
+
+	_, err = s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Equal(int64(13), s.dlqMessageHandler.Health().AckLevel)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_CheckpointPerTask_PriorityMerge() {
+	s.dlqMessageHandler.checkpointGranularity = dynamicconfig.GetStringPropertyFn(string(CheckpointGranularityPerTask))
+	s.dlqMessageHandler.priorityMerge = dynamicconfig.GetBoolPropertyFn(true)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), "").Return(nil).Times(2)
+	// pages merged by priority are only checkpointed once the whole page is merged
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_DomainPriority() {
+	tiers := map[string]SLATier{
+		"critical-domain":    SLATierCritical,
+		"standard-domain":    SLATierStandard,
+		"best-effort-domain": SLATierBestEffort,
+	}
+	s.dlqMessageHandler.priorityFunc = NewSLATierPriorityFunc(testDomainMetadataReader(func(domainID string) (map[string]string, error) {
+		return map[string]string{DomainDataKeySLATier: string(tiers[domainID])}, nil
+	}))
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	newTask := func(messageID int64, domainID string) *types.ReplicationTask {
+		return &types.ReplicationTask{
+			TaskType:                   types.ReplicationTaskTypeSyncActivity.Ptr(),
+			SourceTaskID:               messageID,
+			SyncActivityTaskAttributes: &types.SyncActivityTaskAttributes{DomainID: domainID},
+		}
+	}
+	tasks := []*types.ReplicationTask{
+		newTask(11, "best-effort-domain"),
+		newTask(12, "standard-domain"),
+		newTask(13, "critical-domain"),
+		newTask(14, "best-effort-domain"),
+		newTask(15, "critical-domain"),
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	var merged []int64
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), "").DoAndReturn(
+		func(task *types.ReplicationTask, _ string) error {
+			merged = append(merged, task.SourceTaskID)
+			return nil
+		},
+	).Times(len(tasks))
+	// the page is acknowledged up to its highest message ID once it is merged
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(15)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(15)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Equal([]int64{13, 15, 12, 11, 14}, merged)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Parallelism_PreservesOrder() {
+	s.dlqMessageHandler.parallelism = dynamicconfig.GetIntPropertyFn(4)
+	s.dlqMessageHandler.checkpointGranularity = dynamicconfig.GetStringPropertyFn(string(CheckpointGranularityPerTask))
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+
+	// the first message only finishes after the others did
+	var later sync.WaitGroup
+	later.Add(2)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").DoAndReturn(
+		func(*types.ReplicationTask, string) error {
+			later.Wait()
+			return nil
+		}).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").DoAndReturn(
+		func(*types.ReplicationTask, string) error {
+			later.Done()
+			return nil
+		}).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").DoAndReturn(
+		func(*types.ReplicationTask, string) error {
+			later.Done()
+			return nil
+		}).Times(1)
+	// the ack level still moves through the page in order
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(11), int64(12)).Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(11), int64(12)).Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(12), int64(13)).Return(nil).Times(1),
+		s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(12), int64(13)).Return(nil).Times(1),
+	)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.NoError(err)
+	s.Equal(int64(13), s.dlqMessageHandler.Health().AckLevel)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Parallelism_FailureStopsAtFailedMessage() {
+	s.dlqMessageHandler.parallelism = dynamicconfig.GetIntPropertyFn(4)
+	s.dlqMessageHandler.checkpointGranularity = dynamicconfig.GetStringPropertyFn(string(CheckpointGranularityPerTask))
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	testError := fmt.Errorf("test")
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[1], "").Return(testError).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).MaxTimes(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(12)).Return(1, nil).Times(1)
+	// only the messages before the failed one are acked, even if later ones were executed
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, testError))
+}
+
+func (s *dlqMessageHandlerSuite) TestDryRunMerge() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	enqueuedAt := time.Now()
+	domainID := uuid.New()
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID: 11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{
+				DomainOperation: types.DomainOperationUpdate.Ptr(),
+				ID:              domainID,
+				Info:            &types.DomainInfo{Name: "test-domain"},
+				FailoverVersion: 5,
+			},
+			EnqueuedAt: enqueuedAt,
+		},
+		{
+			TaskType:     types.ReplicationTaskTypeHistoryV2.Ptr(),
+			SourceTaskID: 12,
+		},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+
+	// a dry run never executes messages nor modifies the DLQ
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), gomock.Any()).Times(0)
+
+	previews, err := s.dlqMessageHandler.DryRunMerge(context.Background(), AllTaskTypes, lastMessageID)
+	s.NoError(err)
+	s.Equal([]DLQMergePreview{
+		{
+			MessageID:       11,
+			TaskType:        types.ReplicationTaskTypeDomain,
+			DomainID:        domainID,
+			DomainName:      "test-domain",
+			DomainOperation: types.DomainOperationUpdate,
+			FailoverVersion: 5,
+			EnqueuedAt:      enqueuedAt,
+		},
+		{
+			MessageID: 12,
+			TaskType:  types.ReplicationTaskTypeHistoryV2,
+		},
+	}, previews)
+	s.Zero(s.dlqMessageHandler.Health().LastMergeTime)
+}
+
+func (s *dlqMessageHandlerSuite) TestDryRunMerge_ReadError() {
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(-1), testError).Times(1)
+
+	previews, err := s.dlqMessageHandler.DryRunMerge(context.Background(), AllTaskTypes, 20)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQAckLevelNotFound))
+	s.Nil(previews)
+}
+
+func (s *dlqMessageHandlerSuite) TestSimulateMerge() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		newSimulatedDomainTask(11, types.DomainOperationUpdate, "old-id", "domain-old", 3, 5),
+		{TaskType: types.ReplicationTaskTypeSyncShardStatus.Ptr(), SourceTaskID: 12},
+		newSimulatedDomainTask(13, types.DomainOperationCreate, "new-id", "domain-new", 1, 1),
+	}
+	s.dlqMessageHandler.simulationDomains = newSimulatedDomainManager(s.controller)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+
+	// a simulation never executes messages nor modifies the DLQ
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	operations, err := s.dlqMessageHandler.SimulateMerge(context.Background(), AllTaskTypes, lastMessageID)
+	s.NoError(err)
+	// the message which would fail does not stop the simulation
+	s.Equal([]SimulatedOperation{
+		{MessageID: 11, TaskType: types.ReplicationTaskTypeDomain, Operation: SimulatedOperationUpdateDomain, DomainID: "old-id", DomainName: "domain-old", FailoverVersion: 5},
+		{MessageID: 12, TaskType: types.ReplicationTaskTypeSyncShardStatus, Operation: SimulatedOperationFail, Error: ErrUnsupportedReplicationTaskType.Error()},
+		{MessageID: 13, TaskType: types.ReplicationTaskTypeDomain, Operation: SimulatedOperationCreateDomain, DomainID: "new-id", DomainName: "domain-new", FailoverVersion: 1},
+	}, operations)
+}
+
+func (s *dlqMessageHandlerSuite) TestSimulateMerge_Disabled() {
+	operations, err := s.dlqMessageHandler.SimulateMerge(context.Background(), AllTaskTypes, 20)
+	s.Equal(ErrSimulationModeDisabled, err)
+	s.Nil(operations)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard() {
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 14},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	// only messages of shard 1 of 2 are merged and deleted, the ack level is left untouched
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[2], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(13)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 1, 2)
+	s.NoError(err)
+	s.Equal(int64(2), s.dlqMessageHandler.Health().LastMergeCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_SoftDelete() {
+	s.dlqMessageHandler.softDelete = dynamicconfig.GetBoolPropertyFn(true)
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeSoftDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_InvalidTask() {
+	s.dlqMessageHandler.invalidTaskPolicy = dynamicconfig.GetStringPropertyFn(string(InvalidTaskPolicySkip))
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(nil).Times(1)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	s.NoError(err)
+	s.Equal(int64(0), s.dlqMessageHandler.Health().LastMergeCount)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_ExecuteError() {
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(testError).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(1, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQExecutorFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_DeleteError() {
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(testError).Times(1)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQDeleteFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_MessageNotFound() {
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	// the message was deleted concurrently
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(&types.EntityNotExistsError{}).Times(1)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	var entityNotExistsErr *types.EntityNotExistsError
+	s.True(errors.As(err, &entityNotExistsErr))
+	s.True(errors.Is(err, ErrDLQMessageNotFound))
+	s.False(errors.Is(err, ErrDLQDeleteFailed))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_Lock() {
+	locker := NewMockDistributedLocker(s.controller)
+	lock := NewMockLockHandle(s.controller)
+	s.dlqMessageHandler.locker = locker
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	locker.EXPECT().AcquireLock(gomock.Any(), "domain-dlq-shard-0", dlqMergeLockTTL).Return(lock, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(nil).Times(1)
+	lock.EXPECT().Release(gomock.Any()).Return(nil).Times(1)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_LockHeld() {
+	locker := NewMockDistributedLocker(s.controller)
+	s.dlqMessageHandler.locker = locker
+	locker.EXPECT().AcquireLock(gomock.Any(), "domain-dlq-shard-1", dlqMergeLockTTL).Return(nil, ErrLockHeld).Times(1)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 1, 2)
+	s.Equal(ErrLockHeld, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_LockLostMidMerge() {
+	core, logs := observer.New(zap.ErrorLevel)
+	s.dlqMessageHandler.logger = loggerimpl.NewLogger(zap.New(core))
+	locker := NewMockDistributedLocker(s.controller)
+	lock := NewMockLockHandle(s.controller)
+	s.dlqMessageHandler.locker = locker
+	s.dlqMessageHandler.mergeLockTTL = 30 * time.Millisecond
+	ackLevel := int64(10)
+	locker.EXPECT().AcquireLock(gomock.Any(), "domain-dlq-shard-0", 30*time.Millisecond).Return(lock, nil).Times(1)
+	lock.EXPECT().Extend(gomock.Any()).Return(ErrLockLost).Times(1)
+	lock.EXPECT().Release(gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	// the read is still in progress when the lock is lost
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		DoAndReturn(func(ctx context.Context, _ types.ReplicationTaskType, _, _ int64, _ int, _ []byte) ([]*types.ReplicationTask, []byte, error) {
+			<-ctx.Done()
+			return nil, nil, ctx.Err()
+		}).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	s.Equal(ErrLockLost, err)
+	entries := logs.FilterMessage("Lost domain DLQ merge lock, aborted the merge.").AllUntimed()
+	s.Len(entries, 1)
+	s.Equal("domain-dlq-shard-0", entries[0].ContextMap()["key"])
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_DynamicConfigChange() {
+	s.NoError(s.dlqMessageHandler.Close())
+	client := dynamicconfig.NewInMemoryClient()
+	dc := dynamicconfig.NewCollection(client, loggerimpl.NewNopLogger())
+	s.dlqMessageHandler = NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(s.mockReplicationTaskExecutor),
+		s.mockReplicationQueue,
+		loggerimpl.NewLoggerForTest(s.Suite),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+		WithBatchSize(dc.GetIntProperty(dynamicconfig.DomainDLQMaxReadPageSize, 1000)),
+	).(*dlqMessageHandlerImpl)
+	ackLevel := int64(10)
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(task, "").Return(nil).Times(2)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(nil).Times(2)
+
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return([]*types.ReplicationTask{task}, nil, nil).Times(1)
+	s.NoError(s.dlqMessageHandler.MergeShard(context.Background(), 0, 1))
+
+	// the handler is not restarted, the next merge round reads the new page size
+	s.NoError(client.UpdateValue(dynamicconfig.DomainDLQMaxReadPageSize, 2))
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, 2, nil).
+		Return([]*types.ReplicationTask{task}, nil, nil).Times(1)
+	s.NoError(s.dlqMessageHandler.MergeShard(context.Background(), 0, 1))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_InvalidShard() {
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 2, 2)
+	s.IsType(&types.BadRequestError{}, err)
+
+	err = s.dlqMessageHandler.MergeShard(context.Background(), 0, 0)
+	s.IsType(&types.BadRequestError{}, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeShard_Paused() {
+	s.mockReplicationQueue.EXPECT().UpdateDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup, true).Return(nil).Times(1)
+	s.NoError(s.dlqMessageHandler.Pause(context.Background()))
+	s.True(s.dlqMessageHandler.Health().Paused)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	s.Equal(ErrDLQProcessingPaused, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestPause_StopsAfterCurrentPage() {
+	ackLevel := int64(10)
+	pageToken := []byte{1}
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	paused := make(chan error, 1)
+	s.mockReplicationQueue.EXPECT().UpdateDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup, true).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	// the merge is paused while its first page is read
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		DoAndReturn(func(context.Context, types.ReplicationTaskType, int64, int64, int, []byte) ([]*types.ReplicationTask, []byte, error) {
+			go func() {
+				paused <- s.dlqMessageHandler.Pause(context.Background())
+			}()
+			s.Eventually(s.dlqMessageHandler.isPaused, 10*time.Second, time.Millisecond)
+			return tasks, pageToken, nil
+		}).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, pageToken).Times(0)
+	// the messages of the page are still merged, and Pause waits for them
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), "").DoAndReturn(
+		func(*types.ReplicationTask, string) error {
+			s.Empty(paused)
+			return nil
+		}).Times(2)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(12)).Return(nil).Times(1)
+
+	err := s.dlqMessageHandler.MergeShard(context.Background(), 0, 1)
+	s.Equal(ErrDLQProcessingPaused, err)
+	s.Equal(int64(2), s.dlqMessageHandler.Health().LastMergeCount)
+	select {
+	case err := <-paused:
+		s.NoError(err)
+	case <-time.After(10 * time.Second):
+		s.Fail("Pause did not return once the merge stopped")
+	}
+}
+
+func (s *dlqMessageHandlerSuite) TestPause_UpdateFailed() {
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().UpdateDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup, true).Return(testError).Times(1)
+
+	err := s.dlqMessageHandler.Pause(context.Background())
+	s.Equal(testError, err)
+	s.False(s.dlqMessageHandler.Health().Paused)
+}
+
+func (s *dlqMessageHandlerSuite) TestPause_MergeStillRunning() {
+	s.mockReplicationQueue.EXPECT().UpdateDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup, true).Return(nil).Times(1)
+	s.dlqMessageHandler.stateMachine.begin()
+	defer s.dlqMessageHandler.stateMachine.end()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := s.dlqMessageHandler.Pause(ctx)
+	s.Equal(context.DeadlineExceeded, err)
+	// the merges are paused nonetheless
+	s.True(s.dlqMessageHandler.Health().Paused)
+}
+
+func (s *dlqMessageHandlerSuite) TestResume() {
+	atomic.StoreInt32(&s.dlqMessageHandler.paused, 1)
+	s.mockReplicationQueue.EXPECT().UpdateDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup, false).Return(nil).Times(1)
+
+	s.NoError(s.dlqMessageHandler.Resume(context.Background()))
+	s.False(s.dlqMessageHandler.Health().Paused)
+}
+
+func (s *dlqMessageHandlerSuite) TestStart_LoadsPausedState() {
+	s.NoError(s.dlqMessageHandler.Close())
+	s.dlqMessageHandler = NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(s.mockReplicationTaskExecutor),
+		s.mockReplicationQueue,
+		loggerimpl.NewLoggerForTest(s.Suite),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+	).(*dlqMessageHandlerImpl)
+	// the handler was paused before the process restarted
+	s.mockReplicationQueue.EXPECT().GetDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup).Return(true, nil).Times(1)
+
+	s.dlqMessageHandler.Start()
+	s.True(s.dlqMessageHandler.Health().Paused)
+	s.Equal(ErrDLQProcessingPaused, s.dlqMessageHandler.MergeShard(context.Background(), 0, 1))
+}
+
+func (s *dlqMessageHandlerSuite) TestExpireMessages() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	now := time.Now()
+	s.dlqMessageHandler.timeSource = clock.NewEventTimeSource().Update(now)
+	s.dlqMessageHandler.messageTTL = dynamicconfig.GetDurationPropertyFn(24 * time.Hour)
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, EnqueuedAt: now.Add(-48 * time.Hour)},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12, EnqueuedAt: now.Add(-25 * time.Hour)},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13, EnqueuedAt: now.Add(-time.Hour)},
+		// messages are only expired in order
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 14, EnqueuedAt: now.Add(-48 * time.Hour)},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+
+	err := s.dlqMessageHandler.expireMessages(context.Background())
+	s.NoError(err)
+	s.Equal(int64(12), s.dlqMessageHandler.Health().AckLevel)
+
+	captured := assertDLQMetricTags(s.T(), scope.Snapshot(), "dlq_expired_messages", map[string]string{
+		"taskType": types.ReplicationTaskTypeDomain.String(),
+	})
+	s.Equal(int64(2), sumCapturedMetrics(captured))
+}
+
+func (s *dlqMessageHandlerSuite) TestExpireMessages_NothingExpired() {
+	now := time.Now()
+	s.dlqMessageHandler.timeSource = clock.NewEventTimeSource().Update(now)
+	s.dlqMessageHandler.messageTTL = dynamicconfig.GetDurationPropertyFn(24 * time.Hour)
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		// messages without enqueue time are never expired
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12, EnqueuedAt: now.Add(-48 * time.Hour)},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup, gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.expireMessages(context.Background())
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestExpireMessages_AdvanceTime() {
+	now := time.Now()
+	timeSource := clock.NewEventTimeSource().Update(now)
+	s.dlqMessageHandler.timeSource = timeSource
+	s.dlqMessageHandler.messageTTL = dynamicconfig.GetDurationPropertyFn(24 * time.Hour)
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, EnqueuedAt: now},
+	}
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(4)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, common.EndMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(2)
+
+	s.NoError(s.dlqMessageHandler.expireMessages(context.Background()))
+
+	// the message expires once the TTL passed
+	timeSource.Update(now.Add(24*time.Hour + time.Second))
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1)
+	s.NoError(s.dlqMessageHandler.expireMessages(context.Background()))
+}
+
+func (s *dlqMessageHandlerSuite) TestExpireMessages_Disabled() {
+	s.dlqMessageHandler.messageTTL = dynamicconfig.GetDurationPropertyFn(0)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), gomock.Any(), DefaultConsumerGroup).Times(0)
+
+	err := s.dlqMessageHandler.expireMessages(context.Background())
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestCheckAckLevelProgress_Stalled() {
+	core, logs := observer.New(zap.WarnLevel)
+	s.dlqMessageHandler.logger = loggerimpl.NewLogger(zap.New(core))
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	s.dlqMessageHandler.stalledAckLevel = dynamicconfig.GetDurationPropertyFn(10 * time.Minute)
+	now := time.Now()
+	timeSource := clock.NewEventTimeSource().Update(now)
+	s.dlqMessageHandler.timeSource = timeSource
+
+	// the ack level is frozen at 10 while the DLQ holds messages
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil).Times(3)
+	s.mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(5), nil).Times(1)
+
+	s.NoError(s.dlqMessageHandler.checkAckLevelProgress(context.Background()))
+	timeSource.Update(now.Add(5 * time.Minute))
+	s.NoError(s.dlqMessageHandler.checkAckLevelProgress(context.Background()))
+	s.Empty(captureMetrics(scope.Snapshot(), "dlq_ack_level_stalled"))
+	s.Equal(0, logs.Len())
+
+	timeSource.Update(now.Add(11 * time.Minute))
+	s.NoError(s.dlqMessageHandler.checkAckLevelProgress(context.Background()))
+	s.Equal(int64(1), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_ack_level_stalled")))
+	s.Equal(1, logs.FilterMessageSnippet("ack level has not advanced").Len())
+	s.Equal(int64(10), logs.All()[0].ContextMap()["xdc-dlq-ack-level"])
+}
+
+func (s *dlqMessageHandlerSuite) TestCheckAckLevelProgress_Advanced() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	s.dlqMessageHandler.stalledAckLevel = dynamicconfig.GetDurationPropertyFn(10 * time.Minute)
+	now := time.Now()
+	timeSource := clock.NewEventTimeSource().Update(now)
+	s.dlqMessageHandler.timeSource = timeSource
+
+	gomock.InOrder(
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil),
+		s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(12), nil),
+	)
+	s.mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), gomock.Any()).Times(0)
+
+	s.NoError(s.dlqMessageHandler.checkAckLevelProgress(context.Background()))
+	timeSource.Update(now.Add(11 * time.Minute))
+	s.NoError(s.dlqMessageHandler.checkAckLevelProgress(context.Background()))
+	s.Empty(captureMetrics(scope.Snapshot(), "dlq_ack_level_stalled"))
+}
+
+func (s *dlqMessageHandlerSuite) TestCheckAckLevelProgress_EmptyDLQ() {
+	scope := tally.NewTestScope("test", nil)
+	s.dlqMessageHandler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+	s.dlqMessageHandler.stalledAckLevel = dynamicconfig.GetDurationPropertyFn(10 * time.Minute)
+	now := time.Now()
+	timeSource := clock.NewEventTimeSource().Update(now)
+	s.dlqMessageHandler.timeSource = timeSource
+
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil).Times(2)
+	s.mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), nil).Times(1)
+
+	s.NoError(s.dlqMessageHandler.checkAckLevelProgress(context.Background()))
+	timeSource.Update(now.Add(11 * time.Minute))
+	s.NoError(s.dlqMessageHandler.checkAckLevelProgress(context.Background()))
+	s.Empty(captureMetrics(scope.Snapshot(), "dlq_ack_level_stalled"))
+}
+
+func (s *dlqMessageHandlerSuite) TestCheckAckLevelProgress_Disabled() {
+	s.dlqMessageHandler.stalledAckLevel = dynamicconfig.GetDurationPropertyFn(0)
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	s.NoError(s.dlqMessageHandler.checkAckLevelProgress(context.Background()))
+}
+
+func (s *dlqMessageHandlerSuite) TestReplay() {
+	srcQueue := NewMockReplicationQueue(s.controller)
+	dstQueue := NewMockReplicationQueue(s.controller)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	page1 := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	page2 := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	pageToken := []byte{1}
+	srcQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	dstQueue.EXPECT().GetDLQReplayAckLevel(gomock.Any()).Return(int64(-1), nil).Times(1)
+	srcQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(page1, pageToken, nil).Times(1)
+	srcQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, pageToken).
+		Return(page2, nil, nil).Times(1)
+	gomock.InOrder(
+		dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), nil),
+		dstQueue.EXPECT().EnqueueBatch(gomock.Any(), page1).Return(nil),
+		dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(2), nil),
+		dstQueue.EXPECT().UpdateDLQReplayAckLevel(gomock.Any(), int64(12)).Return(nil),
+		dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(2), nil),
+		dstQueue.EXPECT().EnqueueBatch(gomock.Any(), page2).Return(nil),
+		dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(3), nil),
+		dstQueue.EXPECT().UpdateDLQReplayAckLevel(gomock.Any(), int64(13)).Return(nil),
+	)
+
+	err := s.dlqMessageHandler.Replay(context.Background(), srcQueue, dstQueue, lastMessageID)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReplay_ResumeWithOverlappingDestination() {
+	srcQueue := NewMockReplicationQueue(s.controller)
+	dstQueue := NewMockReplicationQueue(s.controller)
+	ackLevel := int64(10)
+	replayAckLevel := int64(12)
+	lastMessageID := int64(20)
+	// message 11 and 12 were replayed by an interrupted run, the destination
+	// also holds messages of its own with IDs overlapping the source
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+	}
+	srcQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	dstQueue.EXPECT().GetDLQReplayAckLevel(gomock.Any()).Return(replayAckLevel, nil).Times(1)
+	srcQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, replayAckLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	gomock.InOrder(
+		dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(15), nil),
+		dstQueue.EXPECT().EnqueueBatch(gomock.Any(), tasks).Return(nil),
+		dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(16), nil),
+		dstQueue.EXPECT().UpdateDLQReplayAckLevel(gomock.Any(), int64(13)).Return(nil),
+	)
+
+	err := s.dlqMessageHandler.Replay(context.Background(), srcQueue, dstQueue, lastMessageID)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReplay_VerificationFailure() {
+	srcQueue := NewMockReplicationQueue(s.controller)
+	dstQueue := NewMockReplicationQueue(s.controller)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	srcQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	dstQueue.EXPECT().GetDLQReplayAckLevel(gomock.Any()).Return(int64(-1), nil).Times(1)
+	srcQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), nil).Times(1)
+	dstQueue.EXPECT().EnqueueBatch(gomock.Any(), tasks).Return(nil).Times(1)
+	dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(1), nil).Times(1)
+	dstQueue.EXPECT().UpdateDLQReplayAckLevel(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Replay(context.Background(), srcQueue, dstQueue, lastMessageID)
+	s.IsType(&types.InternalServiceError{}, err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReplay_Batches() {
+	srcQueue := NewMockReplicationQueue(s.controller)
+	dstQueue := NewMockReplicationQueue(s.controller)
+	ackLevel := int64(10)
+	lastMessageID := int64(100)
+	var tasks []*types.ReplicationTask
+	for i := 0; i < dlqEnqueueBatchSize+5; i++ {
+		tasks = append(tasks, &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: ackLevel + 1 + int64(i)})
+	}
+	srcQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	dstQueue.EXPECT().GetDLQReplayAckLevel(gomock.Any()).Return(int64(-1), nil).Times(1)
+	srcQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	gomock.InOrder(
+		dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), nil),
+		dstQueue.EXPECT().EnqueueBatch(gomock.Any(), tasks[:dlqEnqueueBatchSize]).Return(nil),
+		dstQueue.EXPECT().EnqueueBatch(gomock.Any(), tasks[dlqEnqueueBatchSize:]).Return(nil),
+		dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(len(tasks)), nil),
+		dstQueue.EXPECT().UpdateDLQReplayAckLevel(gomock.Any(), tasks[len(tasks)-1].SourceTaskID).Return(nil),
+	)
+
+	err := s.dlqMessageHandler.Replay(context.Background(), srcQueue, dstQueue, lastMessageID)
+	s.NoError(err)
+}
+
+func (s *dlqMessageHandlerSuite) TestReplay_EnqueueError() {
+	srcQueue := NewMockReplicationQueue(s.controller)
+	dstQueue := NewMockReplicationQueue(s.controller)
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	testError := fmt.Errorf("test")
+	srcQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	dstQueue.EXPECT().GetDLQReplayAckLevel(gomock.Any()).Return(int64(-1), nil).Times(1)
+	srcQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, dlqStreamPageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	dstQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), nil).Times(1)
+	dstQueue.EXPECT().EnqueueBatch(gomock.Any(), tasks).Return(testError).Times(1)
+	dstQueue.EXPECT().UpdateDLQReplayAckLevel(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.dlqMessageHandler.Replay(context.Background(), srcQueue, dstQueue, lastMessageID)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQQueueFull))
+}
+
+func (s *dlqMessageHandlerSuite) TestLogFields() {
+	core, logs := observer.New(zap.WarnLevel)
+	s.dlqMessageHandler.logger = loggerimpl.NewLogger(zap.New(core))
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	pageSize := 100
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "some random domain ID"},
+		},
+	}
+	testError := fmt.Errorf("test")
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, pageSize, nil).
+		Return(tasks, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(tasks[0], "").Return(testError).Times(1)
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), int64(11)).Return(3, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(testError).Times(1)
+
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	s.True(errors.Is(err, testError))
+	s.True(errors.Is(err, ErrDLQDeleteFailed))
+
+	entries := logs.AllUntimed()
+	s.Len(entries, 2)
+	// the poisoned message is identified by its ID, type and domain
+	s.Equal(zap.WarnLevel, entries[0].Level)
+	poisonedFields := entries[0].ContextMap()
+	s.Equal(int64(11), poisonedFields["queue-task-id"])
+	s.Equal("Domain", poisonedFields["xdc-replication-task-type"])
+	s.Equal("some random domain ID", poisonedFields["wf-domain-id"])
+	// the failed delete is identified by the range of the page
+	s.Equal(zap.ErrorLevel, entries[1].Level)
+	deleteFields := entries[1].ContextMap()
+	s.Equal("All", deleteFields["xdc-replication-task-type"])
+	s.Equal(ackLevel, deleteFields["xdc-dlq-ack-level"])
+	s.Equal(int64(11), deleteFields["xdc-dlq-last-message-id"])
+	s.Equal("test", deleteFields["error"])
+}
+
+// Expected call order:
+//  1. RangeDeleteMessagesFromDLQ
+//  2. CompareAndSwapDLQAckLevel
+//
+// The ack level is moved before the messages are deleted, which the mock must report.
+func TestInOrder_DetectsAckLevelUpdatedBeforeDelete(t *testing.T) {
+	reporter := &recordingTestReporter{}
+	controller := gomock.NewController(reporter)
+	mockReplicationQueue := NewMockReplicationQueue(controller)
+	gomock.InOrder(
+		mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), int64(12)).Return(nil),
+		mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(10), int64(12)).Return(nil),
+	)
+
+	reporter.call(func() {
+		_ = mockReplicationQueue.CompareAndSwapDLQAckLevel(context.Background(), AllTaskTypes, DefaultConsumerGroup, 10, 12)
+	})
+	require.Len(t, reporter.failures, 1)
+	require.Contains(t, reporter.failures[0], "CompareAndSwapDLQAckLevel")
+	require.Contains(t, reporter.failures[0], "RangeDeleteMessagesFromDLQ")
+}
+
+// recordingTestReporter records the failures reported by gomock instead of failing the test
+type recordingTestReporter struct {
+	failures []string
+}
+
+type recordingTestReporterFatal struct{}
+
+func (r *recordingTestReporter) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTestReporter) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+	panic(recordingTestReporterFatal{})
+}
+
+func (r *recordingTestReporter) Helper() {}
+
+// call runs fn and recovers from the fatal failure reported by gomock
+func (r *recordingTestReporter) call(fn func()) {
+	defer func() {
+		if p := recover(); p != nil {
+			if _, ok := p.(recordingTestReporterFatal); !ok {
+				panic(p)
+			}
+		}
+	}()
+	fn()
+}
+
+// capturedMetric is a metric emitted to a tally test scope
+type capturedMetric struct {
+	tags  map[string]string
+	value int64
+}
+
+// assertDLQMetricTags asserts that the named metric was emitted in the snapshot, that every
+// emission carries the domain DLQ message dimensions and that each of the expected tag sets was
+// emitted. It returns the captured emissions of the metric.
+func assertDLQMetricTags(
+	t *testing.T,
+	snapshot tally.Snapshot,
+	name string,
+	expected ...map[string]string,
+) []capturedMetric {
+	t.Helper()
+	captured := captureMetrics(snapshot, name)
+	require.NotEmpty(t, captured, "metric %v was not emitted", name)
+	for _, metric := range captured {
+		for _, dimension := range []string{"domain", "source_cluster", "taskType"} {
+			require.Contains(t, metric.tags, dimension, "metric %v is missing the %v dimension", name, dimension)
+		}
+	}
+	for _, tags := range expected {
+		require.True(t, containsMetricWithTags(captured, tags), "metric %v was not emitted with tags %v", name, tags)
+	}
+	return captured
+}
+
+// captureMetrics returns the counters and histograms with the given name in the snapshot.
+// The value of a histogram is the number of recorded samples.
+func captureMetrics(snapshot tally.Snapshot, name string) []capturedMetric {
+	fullName := "test." + name
+	var captured []capturedMetric
+	for _, counter := range snapshot.Counters() {
+		if counter.Name() == fullName {
+			captured = append(captured, capturedMetric{tags: counter.Tags(), value: counter.Value()})
+		}
+	}
+	for _, histogram := range snapshot.Histograms() {
+		if histogram.Name() != fullName {
+			continue
+		}
+		var count int64
+		for _, c := range histogram.Durations() {
+			count += c
+		}
+		for _, c := range histogram.Values() {
+			count += c
+		}
+		captured = append(captured, capturedMetric{tags: histogram.Tags(), value: count})
+	}
+	return captured
+}
+
+// dlqLatencyHistograms returns the non-empty buckets of the duration histograms with the given name in the snapshot
+func dlqLatencyHistograms(snapshot tally.Snapshot, name string) []map[time.Duration]int64 {
+	var latencies []map[time.Duration]int64
+	for _, histogram := range snapshot.Histograms() {
+		if histogram.Name() != "test."+name {
+			continue
+		}
+		buckets := make(map[time.Duration]int64)
+		for upperBound, count := range histogram.Durations() {
+			if count > 0 {
+				buckets[upperBound] = count
+			}
+		}
+		latencies = append(latencies, buckets)
+	}
+	return latencies
+}
+
+func containsMetricWithTags(captured []capturedMetric, tags map[string]string) bool {
+	for _, metric := range captured {
+		matches := true
+		for key, value := range tags {
+			if metric.tags[key] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// dlqMessageTypeCountGauges returns the DLQ message type count gauges in the snapshot by task type
+func dlqMessageTypeCountGauges(snapshot tally.Snapshot) map[string]float64 {
+	gauges := make(map[string]float64)
+	for _, gauge := range snapshot.Gauges() {
+		if gauge.Name() == "test.dlq_message_type_count" {
+			gauges[gauge.Tags()["taskType"]] = gauge.Value()
+		}
+	}
+	return gauges
+}
+
+func sumCapturedMetrics(captured []capturedMetric) int64 {
+	var sum int64
+	for _, metric := range captured {
+		sum += metric.value
+	}
+	return sum
+}
+
+// inMemoryReplicationQueue serves a pre-populated DLQ to benchmarks, acknowledging or deleting
+// messages does not remove them so every iteration of a benchmark merges the same messages
+type inMemoryReplicationQueue struct {
+	ReplicationQueue
+	messages []*types.ReplicationTask
+}
+
+func newInMemoryReplicationQueue(size int) *inMemoryReplicationQueue {
+	queue := &inMemoryReplicationQueue{}
+	for i := 1; i <= size; i++ {
+		queue.messages = append(queue.messages, &types.ReplicationTask{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         int64(i),
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID"},
+		})
+	}
+	return queue
+}
+
+func (q *inMemoryReplicationQueue) GetDLQAckLevel(context.Context, types.ReplicationTaskType, string) (int64, error) {
+	return 0, nil
+}
+
+func (q *inMemoryReplicationQueue) GetMessagesFromDLQ(
+	_ context.Context,
+	_ types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+	start := int(firstMessageID)
+	if len(pageToken) > 0 {
+		start = int(binary.BigEndian.Uint64(pageToken))
+	}
+	end := common.MinInt(start+pageSize, common.MinInt(len(q.messages), int(lastMessageID)))
+	if end >= common.MinInt(len(q.messages), int(lastMessageID)) {
+		return q.messages[start:end], nil, nil
+	}
+	token := make([]byte, 8)
+	binary.BigEndian.PutUint64(token, uint64(end))
+	return q.messages[start:end], token, nil
+}
+
+func (q *inMemoryReplicationQueue) RangeDeleteMessagesFromDLQ(context.Context, types.ReplicationTaskType, int64, int64) error {
+	return nil
+}
+
+func (q *inMemoryReplicationQueue) CompareAndSwapDLQAckLevel(context.Context, types.ReplicationTaskType, string, int64, int64) error {
+	return nil
+}
+
+func (q *inMemoryReplicationQueue) RecordDLQMerge(context.Context, DLQMergeRecord) error {
+	return nil
+}
+
+type noopReplicationTaskExecutor struct {
+	ReplicationTaskExecutor
+}
+
+func (e *noopReplicationTaskExecutor) ExecuteReplicationTask(*types.ReplicationTask, string) error {
+	return nil
+}
+
+func newBenchmarkDLQMessageHandler(queue ReplicationQueue) *dlqMessageHandlerImpl {
+	return NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(&noopReplicationTaskExecutor{}),
+		queue,
+		loggerimpl.NewNopLogger(),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(math.MaxInt32), dynamicconfig.GetIntPropertyFn(math.MaxInt32)),
+		WithPurgeBatchDelay(dynamicconfig.GetDurationPropertyFn(0)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+	).(*dlqMessageHandlerImpl)
+}
+
+func benchmarkDLQMerge(b *testing.B, batchSize int) {
+	handler := newBenchmarkDLQMessageHandler(newInMemoryReplicationQueue(batchSize))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := handler.Merge(context.Background(), AllTaskTypes, "", int64(batchSize), batchSize, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDLQMerge_SmallBatch(b *testing.B) {
+	benchmarkDLQMerge(b, 100)
+}
+
+func BenchmarkDLQMerge_LargeBatch(b *testing.B) {
+	benchmarkDLQMerge(b, 1000)
+}
+
+// sleepingReplicationTaskExecutor simulates the round trip of applying a replication task
+type sleepingReplicationTaskExecutor struct {
+	ReplicationTaskExecutor
+	latency time.Duration
+}
+
+func (e *sleepingReplicationTaskExecutor) ExecuteReplicationTask(*types.ReplicationTask, string) error {
+	time.Sleep(e.latency)
+	return nil
+}
+
+func BenchmarkDLQMerge_Parallelism(b *testing.B) {
+	const batchSize = 100
+	for _, parallelism := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			handler := newBenchmarkDLQMessageHandler(newInMemoryReplicationQueue(batchSize))
+			handler.executors = NewReplicationTaskExecutorRegistry(&sleepingReplicationTaskExecutor{latency: 100 * time.Microsecond})
+			handler.parallelism = dynamicconfig.GetIntPropertyFn(parallelism)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := handler.Merge(context.Background(), AllTaskTypes, "", batchSize, batchSize, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDLQRead_PagedScan(b *testing.B) {
+	const size, pageSize = 10000, 100
+	handler := newBenchmarkDLQMessageHandler(newInMemoryReplicationQueue(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var token []byte
+		for {
+			_, next, err := handler.Read(context.Background(), AllTaskTypes, size, pageSize, token)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(next) == 0 {
+				break
+			}
+			token = next
+		}
+	}
+}
+
+// restartJournaledDLQMessageHandler replaces the handler of the suite by a new one with the execution journal enabled,
+// as after a restart of the process, the journal is persisted in the journal of the test
+func (s *dlqMessageHandlerSuite) restartJournaledDLQMessageHandler() {
+	s.NoError(s.dlqMessageHandler.Close())
+	s.dlqMessageHandler = NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(s.mockReplicationTaskExecutor),
+		s.mockReplicationQueue,
+		loggerimpl.NewLoggerForTest(s.Suite),
+		WithMaxRetries(dynamicconfig.GetIntPropertyFn(3)),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+		WithExecutionJournal(dynamicconfig.GetBoolPropertyFn(true)),
+	).(*dlqMessageHandlerImpl)
+	s.mockReplicationQueue.EXPECT().GetDLQProcessingPaused(gomock.Any(), DefaultConsumerGroup).Return(false, nil).Times(1)
+	s.dlqMessageHandler.Start()
+}
+
+func (s *dlqMessageHandlerSuite) expectPersistedJournal(journal *DLQExecutionJournal) {
+	s.mockReplicationQueue.EXPECT().GetDLQExecutionJournal(gomock.Any(), DefaultConsumerGroup).
+		DoAndReturn(func(context.Context, string) (*DLQExecutionJournal, error) {
+			persisted := *journal
+			return &persisted, nil
+		}).AnyTimes()
+	s.mockReplicationQueue.EXPECT().UpdateDLQExecutionJournal(gomock.Any(), DefaultConsumerGroup, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, updated *DLQExecutionJournal) error {
+			*journal = *updated
+			return nil
+		}).AnyTimes()
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Journal_CrashBeforeDelete() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	messageID := int64(11)
+	task := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID:         messageID,
+		DomainTaskAttributes: newValidDomainTaskAttributes(),
+	}
+	journal := &DLQExecutionJournal{}
+	s.expectPersistedJournal(journal)
+	s.restartJournaledDLQMessageHandler()
+
+	// the process crashes once the message is executed, before it is deleted
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, 100, nil).
+		Return([]*types.ReplicationTask{task}, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(task, "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, messageID).
+		Return(errors.New("crash")).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, 100, nil)
+	s.Error(err)
+	s.Equal(&DLQExecutionJournal{Applied: []int64{messageID}}, journal)
+
+	// the restarted handler deletes the message without applying it again
+	s.restartJournaledDLQMessageHandler()
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, 100, nil).
+		Return([]*types.ReplicationTask{task}, nil, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, messageID).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, messageID).Return(nil).Times(1)
+	_, err = s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, 100, nil)
+	s.NoError(err)
+	// the deleted message is no longer journaled
+	s.False(s.dlqMessageHandler.journal.isApplied(messageID))
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Journal_CrashDuringExecution() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	messageID := int64(11)
+	task := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID:         messageID,
+		DomainTaskAttributes: newValidDomainTaskAttributes(),
+	}
+	// the process crashed while the message was executing, it may or may not be applied
+	journal := &DLQExecutionJournal{Executing: []int64{messageID}}
+	s.expectPersistedJournal(journal)
+	s.restartJournaledDLQMessageHandler()
+
+	// the restarted handler executes it again
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, 100, nil).
+		Return([]*types.ReplicationTask{task}, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(task, "").Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, messageID).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, messageID).Return(nil).Times(1)
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, 100, nil)
+	s.NoError(err)
+	s.Empty(journal.Executing)
+}
+
+func (s *dlqMessageHandlerSuite) TestMergeMessages_Journal_FailedExecution() {
+	ackLevel := int64(10)
+	lastMessageID := int64(20)
+	messageID := int64(11)
+	task := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID:         messageID,
+		DomainTaskAttributes: newValidDomainTaskAttributes(),
+	}
+	journal := &DLQExecutionJournal{}
+	s.expectPersistedJournal(journal)
+	s.restartJournaledDLQMessageHandler()
+
+	// a failed execution is not journaled as applied, so the message is executed again after a restart
+	s.mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	s.mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, lastMessageID, 100, nil).
+		Return([]*types.ReplicationTask{task}, nil, nil).Times(1)
+	s.mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(task, "").Return(&types.BadRequestError{}).AnyTimes()
+	s.mockReplicationQueue.EXPECT().IncrementDLQMessageAttempts(gomock.Any(), messageID).Return(1, nil).AnyTimes()
+	s.mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	_, err := s.dlqMessageHandler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, 100, nil)
+	s.Error(err)
+	s.Equal(&DLQExecutionJournal{}, journal)
 }
@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+const dlqAckLevelMaxConflictRetries = 3
+
+// advanceAckLevel moves the ack level from expectedLevel to newLevel, it returns ErrDLQAckLevelConflict if the ack
+// level was moved concurrently. With versioned ack levels, the update is conditioned on the version of the ack levels
+// the ack level was read at, and is retried while the ack levels change version without the ack level moving.
+func (d *dlqMessageHandlerImpl) advanceAckLevel(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	expectedLevel int64,
+	newLevel int64,
+) error {
+
+	if !d.versionedAckLevel() {
+		return d.replicationQueue.CompareAndSwapDLQAckLevel(ctx, taskType, d.consumerGroup, expectedLevel, newLevel)
+	}
+
+	maxRetries := d.ackLevelRetries()
+	for attempt := 0; ; attempt++ {
+		ackLevel, version, err := d.replicationQueue.GetDLQAckLevelWithVersion(ctx, taskType, d.consumerGroup)
+		if err != nil {
+			return err
+		}
+		if ackLevel != expectedLevel {
+			return ErrDLQAckLevelConflict
+		}
+		err = d.replicationQueue.UpdateDLQAckLevelWithVersion(ctx, taskType, d.consumerGroup, newLevel, version)
+		if err != ErrVersionConflict {
+			return err
+		}
+		d.metricsClient.Scope(metrics.DomainReplicationQueueScope).IncCounter(metrics.DomainReplicationDLQAckVersionConflicts)
+		if attempt >= maxRetries {
+			return err
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// dlqSnapshotMergedMessageCount is the number of most recent merges whose message IDs are hashed into a snapshot
+	dlqSnapshotMergedMessageCount = 10
+	// dlqSnapshotMergeHistoryLimit bounds the merge history read to find the merges preceding a snapshot on restore
+	dlqSnapshotMergeHistoryLimit = 1000
+)
+
+type (
+	// DLQAckLevelSnapshot is a copy of the domain DLQ ack levels, taken to restore them if they are lost.
+	// MergedMessageIDsHash identifies the most recent merges at the time of the snapshot,
+	// so that a snapshot is only restored onto the DLQ it was taken from.
+	DLQAckLevelSnapshot struct {
+		CreatedAt            time.Time        `json:"createdAt"`
+		AckLevels            map[string]int64 `json:"ackLevels"`
+		MergedMessageIDsHash string           `json:"mergedMessageIDsHash"`
+	}
+)
+
+// ErrDLQAckLevelSnapshotMismatch is returned when a snapshot does not match the merge history of the DLQ
+var ErrDLQAckLevelSnapshotMismatch = &types.BadRequestError{Message: "domain DLQ ack level snapshot does not match the DLQ merge history"}
+
+// SnapshotDLQAckLevel returns a snapshot of the ack levels of every task type and consumer group of the DLQ
+func (q *replicationQueueImpl) SnapshotDLQAckLevel(
+	ctx context.Context,
+) (*DLQAckLevelSnapshot, error) {
+	ackLevels, err := q.queue.GetDLQAckLevels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, err := q.GetDLQMergeHistory(ctx, dlqSnapshotMergedMessageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &DLQAckLevelSnapshot{
+		CreatedAt:            time.Now(),
+		AckLevels:            make(map[string]int64, len(ackLevels)),
+		MergedMessageIDsHash: hashMergedMessageIDs(history),
+	}
+	for key, ackLevel := range ackLevels {
+		snapshot.AckLevels[key] = ackLevel
+	}
+	return snapshot, nil
+}
+
+// RestoreDLQAckLevel writes the ack levels of the snapshot back, after validating it against the merge history.
+// Ack levels only move forward, so restoring a snapshot never causes merged messages to be merged again.
+func (q *replicationQueueImpl) RestoreDLQAckLevel(
+	ctx context.Context,
+	snapshot *DLQAckLevelSnapshot,
+) error {
+	if err := validateDLQAckLevelSnapshot(ctx, q, snapshot); err != nil {
+		return err
+	}
+
+	for key, ackLevel := range snapshot.AckLevels {
+		if err := q.queue.UpdateDLQAckLevel(ctx, ackLevel, key); err != nil {
+			return fmt.Errorf("failed to restore dlq ack level %v: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// validateDLQAckLevelSnapshot returns an error if the snapshot was not taken from the DLQ with the merge history
+func validateDLQAckLevelSnapshot(
+	ctx context.Context,
+	mergeHistory DLQMergeHistory,
+	snapshot *DLQAckLevelSnapshot,
+) error {
+	if snapshot == nil {
+		return &types.BadRequestError{Message: "domain DLQ ack level snapshot is empty"}
+	}
+
+	history, err := mergeHistory.GetDLQMergeHistory(ctx, dlqSnapshotMergeHistoryLimit)
+	if err != nil {
+		return err
+	}
+	// merges done after the snapshot was taken are not part of its hash
+	preceding := make([]DLQMergeRecord, 0, dlqSnapshotMergedMessageCount)
+	for _, record := range history {
+		if record.MergedAt.After(snapshot.CreatedAt) {
+			continue
+		}
+		preceding = append(preceding, record)
+		if len(preceding) == dlqSnapshotMergedMessageCount {
+			break
+		}
+	}
+	if hashMergedMessageIDs(preceding) != snapshot.MergedMessageIDsHash {
+		return ErrDLQAckLevelSnapshotMismatch
+	}
+	return nil
+}
+
+// hashMergedMessageIDs returns the hex encoded SHA-256 of the message ID ranges of the merge records
+func hashMergedMessageIDs(records []DLQMergeRecord) string {
+	hash := sha256.New()
+	for _, record := range records {
+		hash.Write([]byte(strconv.FormatInt(record.StartMessageID, 10)))
+		hash.Write([]byte{'-'})
+		hash.Write([]byte(strconv.FormatInt(record.EndMessageID, 10)))
+		hash.Write([]byte{','})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
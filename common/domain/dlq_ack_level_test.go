@@ -0,0 +1,168 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+func newVersionedAckLevelHandler(queue ReplicationQueue, maxConflictRetries int) *dlqMessageHandlerImpl {
+	return NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(nil),
+		queue,
+		loggerimpl.NewNopLogger(),
+		WithVersionedAckLevel(dynamicconfig.GetBoolPropertyFn(true), dynamicconfig.GetIntPropertyFn(maxConflictRetries)),
+	).(*dlqMessageHandlerImpl)
+}
+
+func TestAdvanceAckLevel_CompareAndSwapByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	queue := NewMockReplicationQueue(ctrl)
+	handler := NewDLQMessageHandler(NewReplicationTaskExecutorRegistry(nil), queue, loggerimpl.NewNopLogger()).(*dlqMessageHandlerImpl)
+
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(10), int64(20)).Return(nil)
+	assert.NoError(t, handler.advanceAckLevel(context.Background(), AllTaskTypes, 10, 20))
+}
+
+func TestAdvanceAckLevel_Versioned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	queue := NewMockReplicationQueue(ctrl)
+	handler := newVersionedAckLevelHandler(queue, 3)
+	scope := tally.NewTestScope("test", nil)
+	handler.metricsClient = metrics.NewClient(scope, metrics.Frontend)
+
+	// the ack levels changed version once between the read and the update
+	gomock.InOrder(
+		queue.EXPECT().GetDLQAckLevelWithVersion(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), int64(5), nil),
+		queue.EXPECT().UpdateDLQAckLevelWithVersion(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(20), int64(5)).Return(ErrVersionConflict),
+		queue.EXPECT().GetDLQAckLevelWithVersion(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), int64(6), nil),
+		queue.EXPECT().UpdateDLQAckLevelWithVersion(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(20), int64(6)).Return(nil),
+	)
+	assert.NoError(t, handler.advanceAckLevel(context.Background(), AllTaskTypes, 10, 20))
+	assert.Equal(t, int64(1), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_ack_level_version_conflicts")))
+}
+
+func TestAdvanceAckLevel_Versioned_RetriesExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	queue := NewMockReplicationQueue(ctrl)
+	handler := newVersionedAckLevelHandler(queue, 1)
+
+	queue.EXPECT().GetDLQAckLevelWithVersion(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), int64(5), nil).Times(2)
+	queue.EXPECT().UpdateDLQAckLevelWithVersion(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(20), int64(5)).Return(ErrVersionConflict).Times(2)
+	assert.Equal(t, ErrVersionConflict, handler.advanceAckLevel(context.Background(), AllTaskTypes, 10, 20))
+}
+
+func TestAdvanceAckLevel_Versioned_AckLevelMoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	queue := NewMockReplicationQueue(ctrl)
+	handler := newVersionedAckLevelHandler(queue, 3)
+
+	queue.EXPECT().GetDLQAckLevelWithVersion(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(15), int64(5), nil)
+	assert.Equal(t, ErrDLQAckLevelConflict, handler.advanceAckLevel(context.Background(), AllTaskTypes, 10, 20))
+}
+
+// versionedAckLevelQueue keeps the DLQ ack levels in memory, the ack level updates are conditioned on their version
+type versionedAckLevelQueue struct {
+	ReplicationQueue
+
+	sync.Mutex
+	ackLevel int64
+	version  int64
+}
+
+func (q *versionedAckLevelQueue) GetDLQAckLevelWithVersion(
+	_ context.Context,
+	_ types.ReplicationTaskType,
+	_ string,
+) (int64, int64, error) {
+	q.Lock()
+	defer q.Unlock()
+	return q.ackLevel, q.version, nil
+}
+
+func (q *versionedAckLevelQueue) UpdateDLQAckLevelWithVersion(
+	_ context.Context,
+	_ types.ReplicationTaskType,
+	_ string,
+	newLevel int64,
+	expectedVersion int64,
+) error {
+	q.Lock()
+	defer q.Unlock()
+	if q.version != expectedVersion {
+		return ErrVersionConflict
+	}
+	q.ackLevel = newLevel
+	q.version++
+	return nil
+}
+
+func TestAdvanceAckLevel_Versioned_Concurrent(t *testing.T) {
+	const handlers = 10
+	const advances = 20
+	queue := &versionedAckLevelQueue{}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, handlers)
+	for i := 0; i < handlers; i++ {
+		wg.Add(1)
+		go func(handler *dlqMessageHandlerImpl) {
+			defer wg.Done()
+			for advanced := 0; advanced < advances; {
+				ackLevel, _, _ := queue.GetDLQAckLevelWithVersion(context.Background(), AllTaskTypes, DefaultConsumerGroup)
+				err := handler.advanceAckLevel(context.Background(), AllTaskTypes, ackLevel, ackLevel+1)
+				switch err {
+				case nil:
+					advanced++
+				case ErrDLQAckLevelConflict, ErrVersionConflict:
+				default:
+					errs <- err
+					return
+				}
+			}
+		}(newVersionedAckLevelHandler(queue, 3))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// every advance moved the ack level by one from the level it read, so none of them was lost
+	assert.Equal(t, int64(handlers*advances), queue.ackLevel)
+	assert.Equal(t, int64(handlers*advances), queue.version)
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/pborman/uuid"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/archiver/provider"
+	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// dlqArchivalServiceName is the service name the bootstrap container of the DLQ archiver is registered with,
+	// so the archiver provider builds the history archivers of the DLQ apart from those of the services
+	dlqArchivalServiceName = "domain-replication-dlq"
+	// dlqArchiveMarkerName is the name of the marker events the archived DLQ messages are recorded as
+	dlqArchiveMarkerName = "DomainReplicationDLQMessage"
+	// dlqArchiveGetPageSize is the number of archived messages read from the archiver at once
+	dlqArchiveGetPageSize = 1000
+)
+
+type (
+	// DLQArchiver archives domain replication DLQ messages to long-term storage before they are purged.
+	// An archive is identified by the cluster of the DLQ, the task type and the range of message IDs
+	// (firstMessageID, lastMessageID] it holds, which the history archiver encodes in the archive filename.
+	DLQArchiver interface {
+		Archive(
+			ctx context.Context,
+			URI archiver.URI,
+			taskType types.ReplicationTaskType,
+			firstMessageID int64,
+			lastMessageID int64,
+			tasks []*types.ReplicationTask,
+		) error
+		Get(
+			ctx context.Context,
+			URI archiver.URI,
+			taskType types.ReplicationTaskType,
+			firstMessageID int64,
+			lastMessageID int64,
+		) ([]*types.ReplicationTask, error)
+	}
+
+	// dlqArchiverImpl archives the DLQ messages with the history archiver of the scheme of the URI.
+	// The history archivers read the histories they archive from the history manager of their bootstrap container,
+	// so the messages being archived are served to them as marker events by archivedMessages.
+	dlqArchiverImpl struct {
+		archiverProvider provider.ArchiverProvider
+		clusterMetadata  cluster.Metadata
+		archivedMessages *dlqArchiveHistoryManager
+		logger           log.Logger
+		metricsClient    metrics.Client
+
+		registerOnce sync.Once
+		registerErr  error
+	}
+
+	// dlqArchiveHistoryManager serves the DLQ messages being archived as history branches,
+	// only ReadHistoryBranchByBatch is implemented as it is the only method used by the history archivers
+	dlqArchiveHistoryManager struct {
+		persistence.HistoryManager
+
+		sync.Mutex
+		branches map[string][]*types.History
+	}
+)
+
+var _ DLQArchiver = (*dlqArchiverImpl)(nil)
+
+// NewDLQArchiver returns a DLQArchiver archiving the DLQ messages of the current cluster with the history archivers of the provider
+func NewDLQArchiver(
+	archiverProvider provider.ArchiverProvider,
+	clusterMetadata cluster.Metadata,
+	logger log.Logger,
+	metricsClient metrics.Client,
+) DLQArchiver {
+
+	return &dlqArchiverImpl{
+		archiverProvider: archiverProvider,
+		clusterMetadata:  clusterMetadata,
+		archivedMessages: &dlqArchiveHistoryManager{branches: make(map[string][]*types.History)},
+		logger:           logger,
+		metricsClient:    metricsClient,
+	}
+}
+
+// Archive archives the DLQ messages of the task type in the range, replacing a previous archive of the same range
+func (a *dlqArchiverImpl) Archive(
+	ctx context.Context,
+	URI archiver.URI,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+	tasks []*types.ReplicationTask,
+) error {
+
+	if len(tasks) == 0 {
+		return nil
+	}
+	historyArchiver, err := a.historyArchiver(URI)
+	if err != nil {
+		return err
+	}
+
+	batches := make([]*types.History, 0, len(tasks))
+	for i, task := range tasks {
+		details, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+		batches = append(batches, &types.History{Events: []*types.HistoryEvent{{
+			ID:        int64(i) + common.FirstEventID,
+			Timestamp: task.CreationTime,
+			EventType: types.EventTypeMarkerRecorded.Ptr(),
+			// the close failover version of the archive is its last message ID
+			Version: lastMessageID,
+			TaskID:  task.SourceTaskID,
+			MarkerRecordedEventAttributes: &types.MarkerRecordedEventAttributes{
+				MarkerName: dlqArchiveMarkerName,
+				Details:    details,
+			},
+		}}})
+	}
+
+	branchToken := []byte(uuid.New())
+	a.archivedMessages.put(branchToken, batches)
+	defer a.archivedMessages.remove(branchToken)
+
+	request := a.archiveRequest(taskType, firstMessageID, lastMessageID)
+	return historyArchiver.Archive(ctx, URI, &archiver.ArchiveHistoryRequest{
+		DomainID:             request.DomainID,
+		DomainName:           request.DomainID,
+		WorkflowID:           request.WorkflowID,
+		RunID:                request.RunID,
+		BranchToken:          branchToken,
+		NextEventID:          int64(len(batches)) + common.FirstEventID,
+		CloseFailoverVersion: lastMessageID,
+	})
+}
+
+// Get returns the DLQ messages of the task type archived for the range
+func (a *dlqArchiverImpl) Get(
+	ctx context.Context,
+	URI archiver.URI,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+) ([]*types.ReplicationTask, error) {
+
+	historyArchiver, err := a.historyArchiver(URI)
+	if err != nil {
+		return nil, err
+	}
+
+	request := a.archiveRequest(taskType, firstMessageID, lastMessageID)
+	var tasks []*types.ReplicationTask
+	for {
+		response, err := historyArchiver.Get(ctx, URI, request)
+		if err != nil {
+			return nil, err
+		}
+		for _, batch := range response.HistoryBatches {
+			for _, event := range batch.Events {
+				if event.MarkerRecordedEventAttributes.GetMarkerName() != dlqArchiveMarkerName {
+					continue
+				}
+				task := &types.ReplicationTask{}
+				if err := json.Unmarshal(event.MarkerRecordedEventAttributes.Details, task); err != nil {
+					return nil, err
+				}
+				tasks = append(tasks, task)
+			}
+		}
+		if len(response.NextPageToken) == 0 {
+			return tasks, nil
+		}
+		request.NextPageToken = response.NextPageToken
+	}
+}
+
+// archiveRequest returns the request reading the archive of the range, the cluster name, the task type
+// and the range are encoded in the domain ID, workflow ID, run ID and close failover version of the archive
+func (a *dlqArchiverImpl) archiveRequest(
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+) *archiver.GetHistoryRequest {
+
+	return &archiver.GetHistoryRequest{
+		DomainID:             a.clusterMetadata.GetCurrentClusterName(),
+		WorkflowID:           fmt.Sprintf("%v-%d", dlqArchivalServiceName, int32(taskType)),
+		RunID:                fmt.Sprintf("%d-%d", firstMessageID, lastMessageID),
+		CloseFailoverVersion: common.Int64Ptr(lastMessageID),
+		PageSize:             dlqArchiveGetPageSize,
+	}
+}
+
+// historyArchiver returns the history archiver of the scheme of the URI,
+// the bootstrap container of the DLQ is registered with the provider the first time an archiver is needed
+func (a *dlqArchiverImpl) historyArchiver(URI archiver.URI) (archiver.HistoryArchiver, error) {
+	a.registerOnce.Do(func() {
+		a.registerErr = a.archiverProvider.RegisterBootstrapContainer(
+			dlqArchivalServiceName,
+			&archiver.HistoryBootstrapContainer{
+				HistoryV2Manager: a.archivedMessages,
+				Logger:           a.logger,
+				MetricsClient:    a.metricsClient,
+				ClusterMetadata:  a.clusterMetadata,
+			},
+			nil,
+		)
+	})
+	if a.registerErr != nil {
+		return nil, a.registerErr
+	}
+
+	historyArchiver, err := a.archiverProvider.GetHistoryArchiver(URI.Scheme(), dlqArchivalServiceName)
+	if err != nil {
+		return nil, err
+	}
+	if err := historyArchiver.ValidateURI(URI); err != nil {
+		return nil, err
+	}
+	return historyArchiver, nil
+}
+
+func (m *dlqArchiveHistoryManager) put(branchToken []byte, batches []*types.History) {
+	m.Lock()
+	defer m.Unlock()
+	m.branches[string(branchToken)] = batches
+}
+
+func (m *dlqArchiveHistoryManager) remove(branchToken []byte) {
+	m.Lock()
+	defer m.Unlock()
+	delete(m.branches, string(branchToken))
+}
+
+// ReadHistoryBranchByBatch returns the batches of the branch starting at the min event ID in a single page
+func (m *dlqArchiveHistoryManager) ReadHistoryBranchByBatch(
+	_ context.Context,
+	request *persistence.ReadHistoryBranchRequest,
+) (*persistence.ReadHistoryBranchByBatchResponse, error) {
+
+	m.Lock()
+	batches, ok := m.branches[string(request.BranchToken)]
+	m.Unlock()
+	if !ok {
+		return nil, &types.EntityNotExistsError{Message: "domain DLQ archive not found"}
+	}
+
+	var history []*types.History
+	for _, batch := range batches {
+		if batch.Events[0].ID >= request.MinEventID {
+			history = append(history, batch)
+		}
+	}
+	if len(history) == 0 {
+		return nil, &types.EntityNotExistsError{Message: "domain DLQ archive has no more messages"}
+	}
+	return &persistence.ReadHistoryBranchByBatchResponse{
+		History:          history,
+		LastFirstEventID: history[len(history)-1].Events[0].ID,
+	}, nil
+}
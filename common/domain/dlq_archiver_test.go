@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/archiver/provider"
+	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+// newLocalDLQArchiver returns a DLQArchiver archiving to a temporary directory of the local file system
+// and the URI of the directory, which is removed by the returned cleanup function
+func newLocalDLQArchiver(t *testing.T) (DLQArchiver, archiver.URI, func()) {
+	dir, err := ioutil.TempDir("", "dlq_archiver")
+	require.NoError(t, err)
+	URI, err := archiver.NewURI("file://" + dir)
+	require.NoError(t, err)
+
+	archiverProvider := provider.NewArchiverProvider(
+		&config.HistoryArchiverProvider{Filestore: &config.FilestoreArchiver{FileMode: "0666", DirMode: "0766"}},
+		nil,
+	)
+	dlqArchiver := NewDLQArchiver(
+		archiverProvider,
+		cluster.GetTestClusterMetadata(true, true),
+		loggerimpl.NewNopLogger(),
+		metrics.NewNoopMetricsClient(),
+	)
+	return dlqArchiver, URI, func() { os.RemoveAll(dir) }
+}
+
+func TestDLQArchiver_ArchiveAndGet(t *testing.T) {
+	dlqArchiver, URI, cleanup := newLocalDLQArchiver(t)
+	defer cleanup()
+	tasks := []*types.ReplicationTask{
+		newSimulatedDomainTask(11, types.DomainOperationCreate, "id-1", "domain-1", 0, 1),
+		{TaskType: types.ReplicationTaskTypeSyncActivity.Ptr(), SourceTaskID: 12, SourceCluster: "standby", CreationTime: common.Int64Ptr(100)},
+		newSimulatedDomainTask(14, types.DomainOperationUpdate, "id-1", "domain-1", 1, 1),
+	}
+
+	err := dlqArchiver.Archive(context.Background(), URI, AllTaskTypes, 10, 14, tasks)
+	require.NoError(t, err)
+
+	files, err := ioutil.ReadDir(URI.Path())
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Contains(t, files[0].Name(), "_14.history")
+
+	archived, err := dlqArchiver.Get(context.Background(), URI, AllTaskTypes, 10, 14)
+	require.NoError(t, err)
+	assert.Equal(t, tasks, archived)
+}
+
+func TestDLQArchiver_ArchivesAreKeyedByTaskTypeAndRange(t *testing.T) {
+	dlqArchiver, URI, cleanup := newLocalDLQArchiver(t)
+	defer cleanup()
+	domainTasks := []*types.ReplicationTask{newSimulatedDomainTask(11, types.DomainOperationCreate, "id-1", "domain-1", 0, 1)}
+	laterDomainTasks := []*types.ReplicationTask{newSimulatedDomainTask(12, types.DomainOperationUpdate, "id-1", "domain-1", 1, 1)}
+
+	require.NoError(t, dlqArchiver.Archive(context.Background(), URI, types.ReplicationTaskTypeDomain, 10, 11, domainTasks))
+	require.NoError(t, dlqArchiver.Archive(context.Background(), URI, types.ReplicationTaskTypeDomain, 11, 12, laterDomainTasks))
+	require.NoError(t, dlqArchiver.Archive(context.Background(), URI, AllTaskTypes, 10, 11, laterDomainTasks))
+
+	files, err := ioutil.ReadDir(URI.Path())
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	archived, err := dlqArchiver.Get(context.Background(), URI, types.ReplicationTaskTypeDomain, 10, 11)
+	require.NoError(t, err)
+	assert.Equal(t, domainTasks, archived)
+	archived, err = dlqArchiver.Get(context.Background(), URI, types.ReplicationTaskTypeDomain, 11, 12)
+	require.NoError(t, err)
+	assert.Equal(t, laterDomainTasks, archived)
+
+	_, err = dlqArchiver.Get(context.Background(), URI, types.ReplicationTaskTypeDomain, 12, 13)
+	assert.IsType(t, &types.EntityNotExistsError{}, err)
+}
+
+func TestDLQArchiver_NoTasks(t *testing.T) {
+	dlqArchiver, URI, cleanup := newLocalDLQArchiver(t)
+	defer cleanup()
+
+	require.NoError(t, dlqArchiver.Archive(context.Background(), URI, AllTaskTypes, 10, 14, nil))
+
+	files, err := ioutil.ReadDir(URI.Path())
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestDLQArchiver_UnknownScheme(t *testing.T) {
+	dlqArchiver, _, cleanup := newLocalDLQArchiver(t)
+	defer cleanup()
+	tasks := []*types.ReplicationTask{{SourceTaskID: 11}}
+
+	URI, err := archiver.NewURI("unknown:///tmp/dlq")
+	require.NoError(t, err)
+	err = dlqArchiver.Archive(context.Background(), URI, AllTaskTypes, 10, 11, tasks)
+	assert.Equal(t, provider.ErrUnknownScheme, err)
+}
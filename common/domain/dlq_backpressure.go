@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	dlqBackpressureInitialInterval = time.Second
+	dlqBackpressureMaximumInterval = time.Minute
+)
+
+// dlqBackpressure pauses the merges of the handler while the destination cluster is throttling them.
+// The pause doubles with every throttled execution up to the maximum interval and is reset by a successful execution.
+type dlqBackpressure struct {
+	sync.Mutex
+	policy      *backoff.ExponentialRetryPolicy
+	attempts    int
+	pausedUntil time.Time
+}
+
+func newDLQBackpressure(initialInterval time.Duration, maximumInterval time.Duration) *dlqBackpressure {
+	policy := backoff.NewExponentialRetryPolicy(initialInterval)
+	policy.SetMaximumInterval(maximumInterval)
+	policy.SetExpirationInterval(backoff.NoInterval)
+	return &dlqBackpressure{
+		policy: policy,
+	}
+}
+
+// throttled pauses the merges, an execution throttled while the merges are already paused
+// was started before the pause and does not extend it
+func (b *dlqBackpressure) throttled(now time.Time) {
+	b.Lock()
+	defer b.Unlock()
+
+	if now.Before(b.pausedUntil) {
+		return
+	}
+	b.pausedUntil = now.Add(b.policy.ComputeNextDelay(0, b.attempts))
+	b.attempts++
+}
+
+// released resets the pause once an execution succeeded
+func (b *dlqBackpressure) released() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.attempts = 0
+}
+
+// remaining returns how long the merges stay paused
+func (b *dlqBackpressure) remaining(now time.Time) time.Duration {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.pausedUntil.Sub(now)
+}
+
+// isThrottled tells whether the destination cluster rejected the execution because it is saturated
+func isThrottled(err error) bool {
+	var serviceBusyErr *types.ServiceBusyError
+	return errors.As(err, &serviceBusyErr)
+}
+
+// executeWithBackpressure executes the message once the merges are no longer paused. A throttled execution
+// pauses the merges and is retried after the pause, until the message exhausted its backpressure attempts.
+func (d *dlqMessageHandlerImpl) executeWithBackpressure(ctx context.Context, message *types.ReplicationTask) error {
+	maxAttempts := d.backpressureRetries()
+	for attempt := 0; ; attempt++ {
+		if maxAttempts > 0 {
+			if err := d.waitForBackpressure(ctx); err != nil {
+				return err
+			}
+		}
+		err := d.executeReplicationTask(ctx, message)
+		if err == nil {
+			d.backpressure.released()
+			return nil
+		}
+		if !isThrottled(err) || attempt >= maxAttempts {
+			return err
+		}
+		d.backpressure.throttled(time.Now())
+		d.contextLogger(ctx).WithTags(dlqMessageTags(message)...).Warn("Pausing domain DLQ merge as the destination is throttling it.",
+			tag.AttemptCount(int64(attempt+1)),
+			tag.Error(err),
+		)
+	}
+}
+
+// waitForBackpressure waits until the merges are no longer paused
+func (d *dlqMessageHandlerImpl) waitForBackpressure(ctx context.Context) error {
+	pause := d.backpressure.remaining(time.Now())
+	if pause <= 0 {
+		return nil
+	}
+
+	scope := d.metricsClient.Scope(metrics.DomainReplicationQueueScope)
+	scope.UpdateGauge(metrics.DomainReplicationDLQMergeBackpressureActive, 1)
+	defer scope.UpdateGauge(metrics.DomainReplicationDLQMergeBackpressureActive, 0)
+
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
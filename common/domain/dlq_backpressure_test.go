@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestDLQBackpressure(t *testing.T) {
+	backpressure := newDLQBackpressure(time.Second, 5*time.Second)
+	now := time.Now()
+	assert.True(t, backpressure.remaining(now) <= 0)
+
+	// every throttled execution doubles the pause, less up to 20% of jitter, up to the maximum interval
+	for _, pause := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		backpressure.throttled(now)
+		remaining := backpressure.remaining(now)
+		assert.True(t, remaining <= pause && remaining >= pause*8/10, "pause %v is not about %v", remaining, pause)
+		// executions throttled during the pause were started before it and do not extend it
+		backpressure.throttled(now.Add(remaining / 2))
+		assert.Equal(t, remaining, backpressure.remaining(now))
+		now = now.Add(remaining)
+	}
+
+	backpressure.released()
+	backpressure.throttled(now)
+	remaining := backpressure.remaining(now)
+	assert.True(t, remaining <= time.Second && remaining >= 800*time.Millisecond)
+}
+
+func TestIsThrottled(t *testing.T) {
+	assert.True(t, isThrottled(&types.ServiceBusyError{}))
+	assert.True(t, isThrottled(newDLQError(ErrDLQExecutorFailed, &types.ServiceBusyError{})))
+	assert.False(t, isThrottled(&types.InternalServiceError{}))
+	assert.False(t, isThrottled(nil))
+}
+
+func backpressureActiveGauge(snapshot tally.Snapshot) (float64, bool) {
+	for _, gauge := range snapshot.Gauges() {
+		if gauge.Name() == "test.dlq_merge_backpressure_active" {
+			return gauge.Value(), true
+		}
+	}
+	return 0, false
+}
+
+func TestWaitForBackpressure(t *testing.T) {
+	scope := tally.NewTestScope("test", nil)
+	dlqHandler := NewDLQMessageHandler(
+		nil,
+		nil,
+		loggerimpl.NewNopLogger(),
+		WithMetricsClient(metrics.NewClient(scope, metrics.Frontend)),
+	).(*dlqMessageHandlerImpl)
+	assert.NoError(t, dlqHandler.waitForBackpressure(context.Background()))
+	_, emitted := backpressureActiveGauge(scope.Snapshot())
+	assert.False(t, emitted)
+
+	dlqHandler.backpressure = newDLQBackpressure(time.Hour, time.Hour)
+	dlqHandler.backpressure.throttled(time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- dlqHandler.waitForBackpressure(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		active, _ := backpressureActiveGauge(scope.Snapshot())
+		return active == 1
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-waitErr)
+	active, _ := backpressureActiveGauge(scope.Snapshot())
+	assert.Equal(t, float64(0), active)
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// DLQCircuitBreakerStateClosed is the state of the circuit breaker letting every message through
+	DLQCircuitBreakerStateClosed DLQCircuitBreakerState = "closed"
+	// DLQCircuitBreakerStateOpen is the state of the circuit breaker rejecting every message
+	DLQCircuitBreakerStateOpen DLQCircuitBreakerState = "open"
+	// DLQCircuitBreakerStateHalfOpen is the state of the circuit breaker letting a single message through
+	// to probe whether the executor recovered
+	DLQCircuitBreakerStateHalfOpen DLQCircuitBreakerState = "half-open"
+
+	dlqCircuitBreakerFailureThreshold = 5
+	dlqCircuitBreakerOpenTimeout      = time.Minute
+	// dlqCircuitBreakerFailureRateWindow is the number of the latest calls the failure rate is computed over
+	dlqCircuitBreakerFailureRateWindow = 100
+)
+
+type (
+	// DLQCircuitBreakerState is the state of the circuit breaker protecting the replication task executor from merges
+	DLQCircuitBreakerState string
+
+	// ErrCircuitBreakerOpen is returned by a merge when the replication task executor failed too many times in a row,
+	// the merge can be retried once RetryAfter elapsed
+	ErrCircuitBreakerOpen struct {
+		RetryAfter time.Duration
+	}
+
+	// dlqCircuitBreaker stops merges from hammering an unavailable executor: it opens after
+	// failureThreshold consecutive failures, and once openTimeout elapsed lets a single probe
+	// through which either closes it again or keeps it open for another openTimeout
+	dlqCircuitBreaker struct {
+		sync.Mutex
+
+		failureThreshold int
+		openTimeout      time.Duration
+		timeSource       clock.TimeSource
+		metricsScope     metrics.Scope
+		logger           log.Logger
+
+		state               DLQCircuitBreakerState
+		consecutiveFailures int
+		openedAt            time.Time
+		probing             bool
+
+		// outcomes of the latest calls, true for a failure, used as a ring buffer once full
+		outcomes     []bool
+		nextOutcome  int
+		failureCount int
+	}
+)
+
+func (e *ErrCircuitBreakerOpen) Error() string {
+	return fmt.Sprintf("domain DLQ circuit breaker is open, retry after %v", e.RetryAfter)
+}
+
+func newDLQCircuitBreaker(
+	failureThreshold int,
+	openTimeout time.Duration,
+	timeSource clock.TimeSource,
+	metricsScope metrics.Scope,
+	logger log.Logger,
+) *dlqCircuitBreaker {
+	return &dlqCircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		timeSource:       timeSource,
+		metricsScope:     metricsScope,
+		logger:           logger,
+		state:            DLQCircuitBreakerStateClosed,
+		outcomes:         make([]bool, 0, dlqCircuitBreakerFailureRateWindow),
+	}
+}
+
+// execute runs fn unless the circuit breaker is open and records its outcome
+func (b *dlqCircuitBreaker) execute(fn func() error) error {
+	if err := b.beforeCall(); err != nil {
+		return err
+	}
+	err := fn()
+	b.afterCall(err)
+	return err
+}
+
+func (b *dlqCircuitBreaker) currentState() DLQCircuitBreakerState {
+	b.Lock()
+	defer b.Unlock()
+
+	b.halfOpenIfExpiredLocked()
+	return b.state
+}
+
+func (b *dlqCircuitBreaker) beforeCall() error {
+	b.Lock()
+	defer b.Unlock()
+
+	b.halfOpenIfExpiredLocked()
+	switch b.state {
+	case DLQCircuitBreakerStateOpen:
+		return &ErrCircuitBreakerOpen{RetryAfter: b.openedAt.Add(b.openTimeout).Sub(b.timeSource.Now())}
+	case DLQCircuitBreakerStateHalfOpen:
+		if b.probing {
+			return &ErrCircuitBreakerOpen{RetryAfter: b.openTimeout}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+func (b *dlqCircuitBreaker) afterCall(err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.probing = false
+	failed := isDLQCircuitBreakerFailure(err)
+	b.recordOutcomeLocked(failed)
+	if !failed {
+		b.consecutiveFailures = 0
+		if b.state == DLQCircuitBreakerStateHalfOpen {
+			b.transitionLocked(DLQCircuitBreakerStateClosed)
+		}
+		return
+	}
+
+	b.consecutiveFailures++
+	if b.state == DLQCircuitBreakerStateHalfOpen || b.consecutiveFailures >= b.failureThreshold {
+		b.openedAt = b.timeSource.Now()
+		b.transitionLocked(DLQCircuitBreakerStateOpen)
+	}
+}
+
+// failureRate returns the ratio of failed calls among the latest dlqCircuitBreakerFailureRateWindow calls
+func (b *dlqCircuitBreaker) failureRate() float64 {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.failureRateLocked()
+}
+
+func (b *dlqCircuitBreaker) failureRateLocked() float64 {
+	if len(b.outcomes) == 0 {
+		return 0
+	}
+	return float64(b.failureCount) / float64(len(b.outcomes))
+}
+
+func (b *dlqCircuitBreaker) recordOutcomeLocked(failed bool) {
+	if len(b.outcomes) < cap(b.outcomes) {
+		b.outcomes = append(b.outcomes, failed)
+	} else {
+		if b.outcomes[b.nextOutcome] {
+			b.failureCount--
+		}
+		b.outcomes[b.nextOutcome] = failed
+		b.nextOutcome = (b.nextOutcome + 1) % len(b.outcomes)
+	}
+	if failed {
+		b.failureCount++
+	}
+	b.metricsScope.UpdateGauge(metrics.DomainReplicationDLQBreakerFailureRate, b.failureRateLocked())
+}
+
+func (b *dlqCircuitBreaker) halfOpenIfExpiredLocked() {
+	if b.state == DLQCircuitBreakerStateOpen && !b.timeSource.Now().Before(b.openedAt.Add(b.openTimeout)) {
+		b.transitionLocked(DLQCircuitBreakerStateHalfOpen)
+	}
+}
+
+func (b *dlqCircuitBreaker) transitionLocked(state DLQCircuitBreakerState) {
+	if b.state == state {
+		return
+	}
+	b.logger.Warn("Domain DLQ circuit breaker state changed.",
+		tag.DLQCircuitBreakerPreviousState(string(b.state)),
+		tag.DLQCircuitBreakerState(string(state)),
+		tag.Counter(b.consecutiveFailures),
+	)
+	b.state = state
+	switch state {
+	case DLQCircuitBreakerStateOpen:
+		b.metricsScope.IncCounter(metrics.DomainReplicationDLQBreakerOpened)
+	case DLQCircuitBreakerStateClosed:
+		b.metricsScope.IncCounter(metrics.DomainReplicationDLQBreakerClosed)
+	case DLQCircuitBreakerStateHalfOpen:
+		b.metricsScope.IncCounter(metrics.DomainReplicationDLQBreakerHalfOpen)
+	}
+}
+
+// isDLQCircuitBreakerFailure tells whether the error means the executor is unavailable,
+// bad requests are specific to the message and are left to the poisoned message handling,
+// and a throttling executor is available and left to the merge backpressure
+func isDLQCircuitBreakerFailure(err error) bool {
+	switch err.(type) {
+	case nil, *types.BadRequestError, *types.ServiceBusyError:
+		return false
+	default:
+		return true
+	}
+}
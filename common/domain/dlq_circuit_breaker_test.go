@@ -0,0 +1,182 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestDLQCircuitBreaker_Transitions(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	breaker := newDLQCircuitBreaker(2, time.Minute, timeSource, metrics.NoopScope(metrics.Frontend), loggerimpl.NewNopLogger())
+	testErr := fmt.Errorf("test")
+	calls := 0
+	fail := func() error { calls++; return testErr }
+	succeed := func() error { calls++; return nil }
+
+	// closed: failures below the threshold keep the breaker closed
+	assert.Equal(t, DLQCircuitBreakerStateClosed, breaker.currentState())
+	assert.Equal(t, testErr, breaker.execute(fail))
+	assert.Equal(t, DLQCircuitBreakerStateClosed, breaker.currentState())
+
+	// closed -> open once the threshold is reached
+	assert.Equal(t, testErr, breaker.execute(fail))
+	assert.Equal(t, DLQCircuitBreakerStateOpen, breaker.currentState())
+
+	// open: calls are rejected without reaching the executor
+	err := breaker.execute(succeed)
+	require.IsType(t, &ErrCircuitBreakerOpen{}, err)
+	assert.Equal(t, time.Minute, err.(*ErrCircuitBreakerOpen).RetryAfter)
+	assert.Equal(t, 2, calls)
+
+	// open -> half-open once the open timeout elapsed, a failed probe opens it again
+	timeSource.Update(time.Unix(1060, 0))
+	assert.Equal(t, DLQCircuitBreakerStateHalfOpen, breaker.currentState())
+	assert.Equal(t, testErr, breaker.execute(fail))
+	assert.Equal(t, DLQCircuitBreakerStateOpen, breaker.currentState())
+	assert.IsType(t, &ErrCircuitBreakerOpen{}, breaker.execute(succeed))
+	assert.Equal(t, 3, calls)
+
+	// half-open -> closed on a successful probe
+	timeSource.Update(time.Unix(1120, 0))
+	assert.Equal(t, DLQCircuitBreakerStateHalfOpen, breaker.currentState())
+	assert.NoError(t, breaker.execute(succeed))
+	assert.Equal(t, DLQCircuitBreakerStateClosed, breaker.currentState())
+
+	// the failure count was reset by the success
+	assert.Equal(t, testErr, breaker.execute(fail))
+	assert.Equal(t, DLQCircuitBreakerStateClosed, breaker.currentState())
+	assert.Equal(t, 5, calls)
+}
+
+func TestDLQCircuitBreaker_SingleProbeWhenHalfOpen(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	breaker := newDLQCircuitBreaker(1, time.Minute, timeSource, metrics.NoopScope(metrics.Frontend), loggerimpl.NewNopLogger())
+	assert.Error(t, breaker.execute(func() error { return fmt.Errorf("test") }))
+
+	timeSource.Update(time.Unix(1060, 0))
+	err := breaker.execute(func() error {
+		// another call while the probe is in flight is rejected
+		assert.IsType(t, &ErrCircuitBreakerOpen{}, breaker.execute(func() error { return nil }))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, DLQCircuitBreakerStateClosed, breaker.currentState())
+}
+
+func TestDLQCircuitBreaker_IgnoreBadRequest(t *testing.T) {
+	breaker := newDLQCircuitBreaker(1, time.Minute, clock.NewEventTimeSource(), metrics.NoopScope(metrics.Frontend), loggerimpl.NewNopLogger())
+	badRequest := &types.BadRequestError{Message: "test"}
+
+	assert.Equal(t, badRequest, breaker.execute(func() error { return badRequest }))
+	assert.Equal(t, DLQCircuitBreakerStateClosed, breaker.currentState())
+}
+
+func TestDLQCircuitBreaker_IgnoreServiceBusy(t *testing.T) {
+	breaker := newDLQCircuitBreaker(1, time.Minute, clock.NewEventTimeSource(), metrics.NoopScope(metrics.Frontend), loggerimpl.NewNopLogger())
+	serviceBusy := &types.ServiceBusyError{Message: "test"}
+
+	assert.Equal(t, serviceBusy, breaker.execute(func() error { return serviceBusy }))
+	assert.Equal(t, DLQCircuitBreakerStateClosed, breaker.currentState())
+}
+
+func TestDLQCircuitBreaker_Metrics(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scope := tally.NewTestScope("test", nil)
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	dlqHandler, mockReplicationTaskExecutor, _ := newNotifyingDLQMessageHandler(
+		controller,
+		"",
+		WithDestinationCluster("standby"),
+		WithTimeSource(timeSource),
+		WithMetricsClient(metrics.NewClient(scope, metrics.Frontend)),
+	)
+	dlqHandler.circuitBreaker.failureThreshold = 1
+	testErr := fmt.Errorf("test")
+	calls := 0
+	mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(*types.ReplicationTask, string) error {
+			calls++
+			if calls%2 == 1 {
+				return testErr
+			}
+			return nil
+		}).Times(4)
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 1}
+
+	for i := 0; i < 2; i++ {
+		// closed -> open on a failure, open -> half-open once the open timeout elapsed, half-open -> closed on a success
+		assert.Equal(t, testErr, dlqHandler.executeReplicationTask(context.Background(), task))
+		assert.Equal(t, DLQCircuitBreakerStateOpen, dlqHandler.circuitBreaker.currentState())
+		timeSource.Update(timeSource.Now().Add(time.Minute))
+		assert.NoError(t, dlqHandler.executeReplicationTask(context.Background(), task))
+		assert.Equal(t, DLQCircuitBreakerStateClosed, dlqHandler.circuitBreaker.currentState())
+	}
+
+	snapshot := scope.Snapshot()
+	destinationTags := map[string]string{"destination_cluster": "standby"}
+	for _, name := range []string{"dlq_circuit_breaker_opened", "dlq_circuit_breaker_half_open", "dlq_circuit_breaker_closed"} {
+		captured := captureMetrics(snapshot, name)
+		assert.Equal(t, int64(2), sumCapturedMetrics(captured), name)
+		assert.True(t, containsMetricWithTags(captured, destinationTags), name)
+	}
+	var failureRate []float64
+	for _, gauge := range snapshot.Gauges() {
+		if gauge.Name() == "test.dlq_circuit_breaker_failure_rate" {
+			assert.Equal(t, "standby", gauge.Tags()["destination_cluster"])
+			failureRate = append(failureRate, gauge.Value())
+		}
+	}
+	assert.Equal(t, []float64{0.5}, failureRate)
+	assert.Equal(t, 0.5, dlqHandler.circuitBreaker.failureRate())
+}
+
+func TestDLQCircuitBreaker_FailureRateWindow(t *testing.T) {
+	breaker := newDLQCircuitBreaker(math.MaxInt32, time.Minute, clock.NewEventTimeSource(), metrics.NoopScope(metrics.Frontend), loggerimpl.NewNopLogger())
+	assert.Equal(t, float64(0), breaker.failureRate())
+
+	for i := 0; i < dlqCircuitBreakerFailureRateWindow; i++ {
+		assert.Error(t, breaker.execute(func() error { return fmt.Errorf("test") }))
+	}
+	assert.Equal(t, float64(1), breaker.failureRate())
+
+	// the successes evict the oldest failures from the window
+	for i := 0; i < dlqCircuitBreakerFailureRateWindow/4; i++ {
+		assert.NoError(t, breaker.execute(func() error { return nil }))
+	}
+	assert.Equal(t, 0.75, breaker.failureRate())
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package domain
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// DLQCompressionCodecZstd is the codec of DLQ message payloads compressed with zstd
+	DLQCompressionCodecZstd = "zstd"
+
+	// dlqCompressionDisabled is the compression threshold of queues which do not compress DLQ message payloads
+	dlqCompressionDisabled = -1
+	// dlqMaxDecompressedPayloadSize bounds the memory a corrupted compressed payload can make a read allocate
+	dlqMaxDecompressedPayloadSize = 64 * 1024 * 1024
+)
+
+// the zstd encoder and decoder are safe for concurrent use, they are created once as the decoder starts goroutines
+var (
+	dlqZstdEncoder, _ = zstd.NewWriter(nil)
+	dlqZstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(dlqMaxDecompressedPayloadSize))
+)
+
+// WithCompression compresses the payloads of DLQ messages larger than compressionThresholdBytes with zstd.
+// Compressed messages are decompressed transparently on read regardless of this option,
+// it must only be enabled once every host reading the DLQ can decompress messages.
+func WithCompression(compressionEnabled bool, compressionThresholdBytes int) ReplicationQueueOption {
+	return func(q *replicationQueueImpl) {
+		q.compressionEnabled = compressionEnabled
+		q.compressionThresholdBytes = compressionThresholdBytes
+	}
+}
+
+func compressDLQPayload(payload []byte) []byte {
+	return dlqZstdEncoder.EncodeAll(payload, make([]byte, 0, len(payload)/2))
+}
+
+func decompressDLQPayload(codec string, payload []byte) ([]byte, error) {
+	switch codec {
+	case "":
+		return payload, nil
+	case DLQCompressionCodecZstd:
+		decompressed, err := dlqZstdDecoder.DecodeAll(payload, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress DLQ message: %w", err)
+		}
+		return decompressed, nil
+	default:
+		return nil, fmt.Errorf("unknown DLQ message compression codec %v", codec)
+	}
+}
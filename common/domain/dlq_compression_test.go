@@ -0,0 +1,208 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+// testLargeReplicationTask returns a domain replication task of about size bytes,
+// its data resembles the search attribute configurations large domains carry
+func testLargeReplicationTask(size int) *types.ReplicationTask {
+	var sb strings.Builder
+	for i := 0; sb.Len() < size; i++ {
+		fmt.Fprintf(&sb, "CustomSearchAttribute%d:Keyword;", i)
+	}
+	return &types.ReplicationTask{
+		TaskType: types.ReplicationTaskTypeDomain.Ptr(),
+		DomainTaskAttributes: &types.DomainTaskAttributes{
+			DomainOperation: types.DomainOperationUpdate.Ptr(),
+			ID:              "some random domain ID",
+			Info: &types.DomainInfo{
+				Name: "some random domain name",
+				Data: map[string]string{"searchAttributes": sb.String()},
+			},
+		},
+		SourceCluster: "cluster-b",
+	}
+}
+
+func TestEncodeDecodeReplicationTask_Compressed(t *testing.T) {
+	task := testLargeReplicationTask(10 * 1024)
+
+	data, err := encodeReplicationTask(task, DLQSerializationFormatThrift, nil, 1024)
+	require.NoError(t, err)
+	envelope, err := unmarshalDLQEnvelope(data)
+	require.NoError(t, err)
+	assert.Equal(t, DLQCompressionCodecZstd, envelope.CompressionCodec)
+	assert.Less(t, len(data), 5*1024)
+
+	decoded, err := DecodeReplicationTask(data)
+	require.NoError(t, err)
+	assert.Equal(t, task, decoded)
+}
+
+func TestEncodeDecodeReplicationTask_BelowCompressionThreshold(t *testing.T) {
+	task := testLargeReplicationTask(512)
+
+	data, err := encodeReplicationTask(task, DLQSerializationFormatThrift, nil, 1024)
+	require.NoError(t, err)
+	envelope, err := unmarshalDLQEnvelope(data)
+	require.NoError(t, err)
+	assert.Empty(t, envelope.CompressionCodec)
+
+	decoded, err := DecodeReplicationTask(data)
+	require.NoError(t, err)
+	assert.Equal(t, task, decoded)
+}
+
+func TestEncodeDecodeReplicationTask_CompressedAndEncrypted(t *testing.T) {
+	keys := newTestEncryptionKeyProvider("key-1")
+	task := testLargeReplicationTask(10 * 1024)
+
+	data, err := encodeReplicationTask(task, DLQSerializationFormatThrift, keys, 0)
+	require.NoError(t, err)
+	// the payload is compressed before it is encrypted, as ciphertext does not compress
+	assert.Less(t, len(data), 5*1024)
+
+	envelope, err := unmarshalDLQEnvelope(data)
+	require.NoError(t, err)
+	assert.Equal(t, DLQSchemaVersionEncrypted, envelope.SchemaVersion)
+	assert.Equal(t, DLQCompressionCodecZstd, envelope.CompressionCodec)
+	require.NoError(t, envelope.decrypt(keys))
+	decoded, err := envelope.decode()
+	require.NoError(t, err)
+	assert.Equal(t, task, decoded)
+}
+
+func TestDecompressDLQPayload(t *testing.T) {
+	payload := []byte(strings.Repeat("payload", 100))
+
+	decompressed, err := decompressDLQPayload(DLQCompressionCodecZstd, compressDLQPayload(payload))
+	require.NoError(t, err)
+	assert.Equal(t, payload, decompressed)
+
+	decompressed, err = decompressDLQPayload("", payload)
+	require.NoError(t, err)
+	assert.Equal(t, payload, decompressed)
+
+	_, err = decompressDLQPayload(DLQCompressionCodecZstd, payload)
+	assert.Error(t, err)
+
+	_, err = decompressDLQPayload("lz4", payload)
+	assert.Error(t, err)
+}
+
+func TestReplicationQueue_CompressionRoundTrip(t *testing.T) {
+	mockQueue := persistence.NewMockQueueManager(gomock.NewController(t))
+	queue := NewReplicationQueue(
+		mockQueue,
+		"testCluster",
+		metrics.NewNoopMetricsClient(),
+		loggerimpl.NewNopLogger(),
+		WithCompression(true, 1024),
+	)
+	task := testLargeReplicationTask(100 * 1024)
+
+	var persisted []byte
+	mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "some random domain ID", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ *int32, payload []byte) error {
+			persisted = payload
+			return nil
+		}).Times(1)
+	require.NoError(t, queue.PublishToDLQ(context.Background(), task))
+	assert.Less(t, len(persisted), 50*1024)
+
+	mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).
+		Return([]*persistence.QueueMessage{{ID: 1, Payload: persisted}}, nil, nil).Times(1)
+	tasks, _, err := queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task.DomainTaskAttributes, tasks[0].DomainTaskAttributes)
+	assert.Equal(t, len(persisted), tasks[0].PayloadSize)
+}
+
+func TestReplicationQueue_CompressionDisabled(t *testing.T) {
+	mockQueue := persistence.NewMockQueueManager(gomock.NewController(t))
+	queue := NewReplicationQueue(
+		mockQueue,
+		"testCluster",
+		metrics.NewNoopMetricsClient(),
+		loggerimpl.NewNopLogger(),
+		WithCompression(false, 1024),
+	)
+
+	mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "some random domain ID", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ *int32, payload []byte) error {
+			envelope, err := unmarshalDLQEnvelope(payload)
+			require.NoError(t, err)
+			assert.Empty(t, envelope.CompressionCodec)
+			return nil
+		}).Times(1)
+	require.NoError(t, queue.PublishToDLQ(context.Background(), testLargeReplicationTask(10*1024)))
+}
+
+func BenchmarkDLQPayloadCompression(b *testing.B) {
+	for _, size := range []struct {
+		name  string
+		bytes int
+	}{
+		{name: "10KB", bytes: 10 * 1024},
+		{name: "100KB", bytes: 100 * 1024},
+		{name: "1MB", bytes: 1024 * 1024},
+	} {
+		task := testLargeReplicationTask(size.bytes)
+		for _, compression := range []struct {
+			name      string
+			threshold int
+		}{
+			{name: "uncompressed", threshold: dlqCompressionDisabled},
+			{name: "compressed", threshold: 0},
+		} {
+			b.Run(fmt.Sprintf("%v/%v", size.name, compression.name), func(b *testing.B) {
+				b.ReportAllocs()
+				var stored int
+				for i := 0; i < b.N; i++ {
+					data, err := encodeReplicationTask(task, DLQSerializationFormatThrift, nil, compression.threshold)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := DecodeReplicationTask(data); err != nil {
+						b.Fatal(err)
+					}
+					stored = len(data)
+				}
+				b.ReportMetric(float64(stored), "stored-bytes")
+			})
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+
+	"go.uber.org/multierr"
+
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/dynamicconfig"
+)
+
+type (
+	// DLQHandlerConfig documents the tunable parameters of the domain DLQ handler of the frontend.
+	// The dynamicconfig tag of a field is the dynamic config key LoadDLQHandlerConfig reads it from,
+	// durations are serialized as nanoseconds to JSON and as duration strings such as 5m to YAML.
+	DLQHandlerConfig struct {
+		MaxRetryAttempts               int           `json:"maxRetryAttempts" yaml:"maxRetryAttempts" dynamicconfig:"frontend.domainDLQMaxRetryAttempts"`
+		SizeEmitInterval               time.Duration `json:"sizeEmitInterval" yaml:"sizeEmitInterval" dynamicconfig:"frontend.domainDLQSizeEmitInterval"`
+		MergeRateLimit                 int           `json:"mergeRateLimit" yaml:"mergeRateLimit" dynamicconfig:"frontend.domainDLQMergeRPS"`
+		ReplayRateLimit                int           `json:"replayRateLimit" yaml:"replayRateLimit" dynamicconfig:"frontend.domainDLQReplayRPS"`
+		DeduplicationWindowSize        int           `json:"deduplicationWindowSize" yaml:"deduplicationWindowSize" dynamicconfig:"frontend.domainDLQDeduplicationWindowSize"`
+		DeduplicationFalsePositiveRate float64       `json:"deduplicationFalsePositiveRate" yaml:"deduplicationFalsePositiveRate" dynamicconfig:"frontend.domainDLQDeduplicationFalsePositiveRate"`
+		MergeShardCount                int           `json:"mergeShardCount" yaml:"mergeShardCount" dynamicconfig:"frontend.domainDLQMergeShardCount"`
+		MergeShardInterval             time.Duration `json:"mergeShardInterval" yaml:"mergeShardInterval" dynamicconfig:"frontend.domainDLQMergeShardInterval"`
+		MessageTTL                     time.Duration `json:"messageTTL" yaml:"messageTTL" dynamicconfig:"frontend.domainDLQMessageTTL"`
+		PurgeBatchDelay                time.Duration `json:"purgeBatchDelay" yaml:"purgeBatchDelay" dynamicconfig:"frontend.domainDLQPurgeBatchDelay"`
+		PriorityMergeEnabled           bool          `json:"priorityMergeEnabled" yaml:"priorityMergeEnabled" dynamicconfig:"frontend.domainDLQPriorityMergeEnabled"`
+		MaxPageSize                    int           `json:"maxPageSize" yaml:"maxPageSize" dynamicconfig:"frontend.domainDLQMaxReadPageSize"`
+		SoftDeleteEnabled              bool          `json:"softDeleteEnabled" yaml:"softDeleteEnabled" dynamicconfig:"frontend.domainDLQSoftDeleteEnabled"`
+		LargeMessageThresholdBytes     int           `json:"largeMessageThresholdBytes" yaml:"largeMessageThresholdBytes" dynamicconfig:"frontend.domainDLQLargeMessageThresholdBytes"`
+		StalledAckThreshold            time.Duration `json:"stalledAckThreshold" yaml:"stalledAckThreshold" dynamicconfig:"frontend.domainDLQStalledAckThreshold"`
+		DepthAlertThreshold            int           `json:"depthAlertThreshold" yaml:"depthAlertThreshold" dynamicconfig:"frontend.domainDLQDepthAlertThreshold"`
+		NotificationWebhookURL         string        `json:"notificationWebhookURL" yaml:"notificationWebhookURL" dynamicconfig:"frontend.domainDLQNotificationWebhookURL"`
+		MergeTimeout                   time.Duration `json:"mergeTimeout" yaml:"mergeTimeout" dynamicconfig:"frontend.domainDLQMergeTimeout"`
+		ReadMaxRetries                 int           `json:"readMaxRetries" yaml:"readMaxRetries" dynamicconfig:"frontend.domainDLQReadMaxRetries"`
+		CheckpointGranularity          string        `json:"checkpointGranularity" yaml:"checkpointGranularity" dynamicconfig:"frontend.domainDLQMergeCheckpointGranularity"`
+		Parallelism                    int           `json:"parallelism" yaml:"parallelism" dynamicconfig:"frontend.domainDLQMergeParallelism"`
+		InvalidTaskPolicy              string        `json:"invalidTaskPolicy" yaml:"invalidTaskPolicy" dynamicconfig:"frontend.domainDLQInvalidMessagePolicy"`
+		BackpressureMaxAttempts        int           `json:"backpressureMaxAttempts" yaml:"backpressureMaxAttempts" dynamicconfig:"frontend.domainDLQMergeBackpressureMaxAttempts"`
+		GroupedMergeEnabled            bool          `json:"groupedMergeEnabled" yaml:"groupedMergeEnabled" dynamicconfig:"frontend.domainDLQGroupedMergeEnabled"`
+		ExecutionJournalEnabled        bool          `json:"executionJournalEnabled" yaml:"executionJournalEnabled" dynamicconfig:"frontend.domainDLQExecutionJournalEnabled"`
+		VersionedAckLevelEnabled       bool          `json:"versionedAckLevelEnabled" yaml:"versionedAckLevelEnabled" dynamicconfig:"frontend.domainDLQVersionedAckLevelEnabled"`
+		AckLevelMaxConflictRetries     int           `json:"ackLevelMaxConflictRetries" yaml:"ackLevelMaxConflictRetries" dynamicconfig:"frontend.domainDLQAckLevelMaxConflictRetries"`
+		DLQArchivalEnabled             bool          `json:"dlqArchivalEnabled" yaml:"dlqArchivalEnabled" dynamicconfig:"frontend.domainDLQArchivalEnabled"`
+		DLQArchivalURI                 string        `json:"dlqArchivalURI" yaml:"dlqArchivalURI" dynamicconfig:"frontend.domainDLQArchivalURI"`
+		GrowthRateSampleInterval       time.Duration `json:"growthRateSampleInterval" yaml:"growthRateSampleInterval" dynamicconfig:"frontend.domainDLQGrowthRateSampleInterval"`
+		MaxGrowthRate                  float64       `json:"maxGrowthRate" yaml:"maxGrowthRate" dynamicconfig:"frontend.domainDLQMaxGrowthRate"`
+	}
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// DefaultDLQHandlerConfig returns the values the frontend uses for the parameters which are not set in dynamic config
+func DefaultDLQHandlerConfig() DLQHandlerConfig {
+	return DLQHandlerConfig{
+		MaxRetryAttempts:               5,
+		SizeEmitInterval:               5 * time.Minute,
+		MergeRateLimit:                 10,
+		ReplayRateLimit:                100,
+		DeduplicationWindowSize:        0,
+		DeduplicationFalsePositiveRate: 0.0001,
+		MergeShardCount:                0,
+		MergeShardInterval:             time.Minute,
+		MessageTTL:                     30 * 24 * time.Hour,
+		PurgeBatchDelay:                100 * time.Millisecond,
+		PriorityMergeEnabled:           false,
+		MaxPageSize:                    1000,
+		SoftDeleteEnabled:              false,
+		LargeMessageThresholdBytes:     1024 * 1024,
+		StalledAckThreshold:            time.Hour,
+		DepthAlertThreshold:            0,
+		NotificationWebhookURL:         "",
+		MergeTimeout:                   5 * time.Minute,
+		ReadMaxRetries:                 3,
+		CheckpointGranularity:          string(CheckpointGranularityPerPage),
+		Parallelism:                    1,
+		InvalidTaskPolicy:              string(InvalidTaskPolicyFail),
+		BackpressureMaxAttempts:        10,
+		GroupedMergeEnabled:            false,
+		ExecutionJournalEnabled:        false,
+		VersionedAckLevelEnabled:       false,
+		AckLevelMaxConflictRetries:     dlqAckLevelMaxConflictRetries,
+		DLQArchivalEnabled:             false,
+		DLQArchivalURI:                 "",
+		GrowthRateSampleInterval:       time.Minute,
+		MaxGrowthRate:                  0,
+	}
+}
+
+// LoadDLQHandlerConfig reads the current value of every parameter from dynamic config,
+// the parameters which are not set keep the value they have in defaults
+func LoadDLQHandlerConfig(dc *dynamicconfig.Collection, defaults DLQHandlerConfig) (DLQHandlerConfig, error) {
+	config := defaults
+	value := reflect.ValueOf(&config).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		keyName := field.Tag.Get("dynamicconfig")
+		key, ok := dynamicconfig.KeyNames[keyName]
+		if !ok {
+			return DLQHandlerConfig{}, fmt.Errorf("unknown dynamic config key %q of domain DLQ handler config %v", keyName, field.Name)
+		}
+
+		fieldValue := value.Field(i)
+		switch {
+		case field.Type == durationType:
+			fieldValue.SetInt(int64(dc.GetDurationProperty(key, time.Duration(fieldValue.Int()))()))
+		case field.Type.Kind() == reflect.Int:
+			fieldValue.SetInt(int64(dc.GetIntProperty(key, int(fieldValue.Int()))()))
+		case field.Type.Kind() == reflect.Float64:
+			fieldValue.SetFloat(dc.GetFloat64Property(key, fieldValue.Float())())
+		case field.Type.Kind() == reflect.Bool:
+			fieldValue.SetBool(dc.GetBoolProperty(key, fieldValue.Bool())())
+		case field.Type.Kind() == reflect.String:
+			fieldValue.SetString(dc.GetStringProperty(key, fieldValue.String())())
+		default:
+			return DLQHandlerConfig{}, fmt.Errorf("unsupported type %v of domain DLQ handler config %v", field.Type, field.Name)
+		}
+	}
+	return config, nil
+}
+
+// ValidateConfig returns every parameter of the config with a value the DLQ handler does not support
+func ValidateConfig(cfg DLQHandlerConfig) error {
+	var errs error
+	invalid := func(format string, args ...interface{}) {
+		errs = multierr.Append(errs, fmt.Errorf(format, args...))
+	}
+
+	for name, value := range map[string]int{
+		"maxRetryAttempts":           cfg.MaxRetryAttempts,
+		"deduplicationWindowSize":    cfg.DeduplicationWindowSize,
+		"mergeShardCount":            cfg.MergeShardCount,
+		"largeMessageThresholdBytes": cfg.LargeMessageThresholdBytes,
+		"depthAlertThreshold":        cfg.DepthAlertThreshold,
+		"readMaxRetries":             cfg.ReadMaxRetries,
+		"backpressureMaxAttempts":    cfg.BackpressureMaxAttempts,
+	} {
+		if value < 0 {
+			invalid("%v must not be negative, got %v", name, value)
+		}
+	}
+	for name, value := range map[string]int{
+		"mergeRateLimit":  cfg.MergeRateLimit,
+		"replayRateLimit": cfg.ReplayRateLimit,
+		"maxPageSize":     cfg.MaxPageSize,
+		"parallelism":     cfg.Parallelism,
+	} {
+		if value <= 0 {
+			invalid("%v must be positive, got %v", name, value)
+		}
+	}
+	for name, value := range map[string]time.Duration{
+		"messageTTL":          cfg.MessageTTL,
+		"purgeBatchDelay":     cfg.PurgeBatchDelay,
+		"stalledAckThreshold": cfg.StalledAckThreshold,
+		"mergeTimeout":        cfg.MergeTimeout,
+	} {
+		if value < 0 {
+			invalid("%v must not be negative, got %v", name, value)
+		}
+	}
+	for name, value := range map[string]time.Duration{
+		"sizeEmitInterval":         cfg.SizeEmitInterval,
+		"mergeShardInterval":       cfg.MergeShardInterval,
+		"growthRateSampleInterval": cfg.GrowthRateSampleInterval,
+	} {
+		if value <= 0 {
+			invalid("%v must be positive, got %v", name, value)
+		}
+	}
+
+	if cfg.DeduplicationFalsePositiveRate <= 0 || cfg.DeduplicationFalsePositiveRate >= 1 {
+		invalid("deduplicationFalsePositiveRate must be between 0 and 1, got %v", cfg.DeduplicationFalsePositiveRate)
+	}
+	if cfg.MaxGrowthRate < 0 {
+		invalid("maxGrowthRate must not be negative, got %v", cfg.MaxGrowthRate)
+	}
+	if cfg.NotificationWebhookURL != "" {
+		if err := validateWebhookURL(cfg.NotificationWebhookURL); err != nil {
+			invalid("notificationWebhookURL %q is invalid: %v", cfg.NotificationWebhookURL, err)
+		}
+	}
+	if cfg.DLQArchivalEnabled && cfg.DLQArchivalURI == "" {
+		invalid("dlqArchivalURI must be set when dlqArchivalEnabled is true")
+	}
+	if cfg.DLQArchivalURI != "" {
+		if URI, err := archiver.NewURI(cfg.DLQArchivalURI); err != nil {
+			invalid("dlqArchivalURI %q is invalid: %v", cfg.DLQArchivalURI, err)
+		} else if URI.Scheme() == "" {
+			invalid("dlqArchivalURI %q has no scheme", cfg.DLQArchivalURI)
+		}
+	}
+	switch CheckpointGranularity(cfg.CheckpointGranularity) {
+	case CheckpointGranularityPerPage, CheckpointGranularityPerTask:
+	default:
+		invalid("checkpointGranularity must be %v or %v, got %q", CheckpointGranularityPerPage, CheckpointGranularityPerTask, cfg.CheckpointGranularity)
+	}
+	switch InvalidTaskPolicy(cfg.InvalidTaskPolicy) {
+	case InvalidTaskPolicyNone, InvalidTaskPolicyFail, InvalidTaskPolicySkip:
+	default:
+		invalid("invalidTaskPolicy must be %v, %v or %v, got %q", InvalidTaskPolicyNone, InvalidTaskPolicyFail, InvalidTaskPolicySkip, cfg.InvalidTaskPolicy)
+	}
+	return errs
+}
+
+func validateWebhookURL(webhookURL string) error {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return errors.New("the scheme must be http or https")
+	}
+	if parsed.Host == "" {
+		return errors.New("the host is empty")
+	}
+	return nil
+}
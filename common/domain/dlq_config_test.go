@@ -0,0 +1,200 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v2"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+)
+
+func TestDLQHandlerConfig_JSON(t *testing.T) {
+	config := DefaultDLQHandlerConfig()
+	config.NotificationWebhookURL = "https://alerts.example.com/dlq"
+	config.CheckpointGranularity = string(CheckpointGranularityPerTask)
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"mergeTimeout":300000000000`)
+	assert.Contains(t, string(data), `"checkpointGranularity":"PerTask"`)
+
+	var decoded DLQHandlerConfig
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, config, decoded)
+}
+
+func TestDLQHandlerConfig_YAML(t *testing.T) {
+	config := DefaultDLQHandlerConfig()
+	config.InvalidTaskPolicy = string(InvalidTaskPolicySkip)
+
+	data, err := yaml.Marshal(config)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "mergeTimeout: 5m0s")
+	assert.Contains(t, string(data), "invalidTaskPolicy: Skip")
+
+	var decoded DLQHandlerConfig
+	require.NoError(t, yaml.Unmarshal(data, &decoded))
+	assert.Equal(t, config, decoded)
+
+	partial := DefaultDLQHandlerConfig()
+	require.NoError(t, yaml.Unmarshal([]byte("mergeShardInterval: 30s\nparallelism: 4\n"), &partial))
+	assert.Equal(t, 30*time.Second, partial.MergeShardInterval)
+	assert.Equal(t, 4, partial.Parallelism)
+	assert.Equal(t, DefaultDLQHandlerConfig().MergeTimeout, partial.MergeTimeout)
+}
+
+func TestLoadDLQHandlerConfig(t *testing.T) {
+	client := dynamicconfig.NewInMemoryClient()
+	collection := dynamicconfig.NewCollection(client, log.NewNoop())
+
+	config, err := LoadDLQHandlerConfig(collection, DefaultDLQHandlerConfig())
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDLQHandlerConfig(), config)
+
+	require.NoError(t, client.UpdateValue(dynamicconfig.DomainDLQMergeTimeout, time.Minute))
+	require.NoError(t, client.UpdateValue(dynamicconfig.DomainDLQMergeParallelism, 8))
+	require.NoError(t, client.UpdateValue(dynamicconfig.DomainDLQDeduplicationFalsePositiveRate, 0.01))
+	require.NoError(t, client.UpdateValue(dynamicconfig.DomainDLQSoftDeleteEnabled, true))
+	require.NoError(t, client.UpdateValue(dynamicconfig.DomainDLQInvalidMessagePolicy, string(InvalidTaskPolicySkip)))
+
+	config, err = LoadDLQHandlerConfig(collection, DefaultDLQHandlerConfig())
+	require.NoError(t, err)
+	expected := DefaultDLQHandlerConfig()
+	expected.MergeTimeout = time.Minute
+	expected.Parallelism = 8
+	expected.DeduplicationFalsePositiveRate = 0.01
+	expected.SoftDeleteEnabled = true
+	expected.InvalidTaskPolicy = string(InvalidTaskPolicySkip)
+	assert.Equal(t, expected, config)
+	assert.NoError(t, ValidateConfig(config))
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		update         func(*DLQHandlerConfig)
+		expectedErrors []string
+	}{
+		{
+			name:   "defaults",
+			update: func(*DLQHandlerConfig) {},
+		},
+		{
+			name: "negative values",
+			update: func(config *DLQHandlerConfig) {
+				config.MaxRetryAttempts = -1
+				config.MessageTTL = -time.Second
+			},
+			expectedErrors: []string{"maxRetryAttempts", "messageTTL"},
+		},
+		{
+			name: "zero values",
+			update: func(config *DLQHandlerConfig) {
+				config.MergeRateLimit = 0
+				config.Parallelism = 0
+				config.SizeEmitInterval = 0
+			},
+			expectedErrors: []string{"mergeRateLimit", "parallelism", "sizeEmitInterval"},
+		},
+		{
+			name: "false positive rate out of range",
+			update: func(config *DLQHandlerConfig) {
+				config.DeduplicationFalsePositiveRate = 1
+			},
+			expectedErrors: []string{"deduplicationFalsePositiveRate"},
+		},
+		{
+			name: "webhook url without http scheme",
+			update: func(config *DLQHandlerConfig) {
+				config.NotificationWebhookURL = "ftp://alerts.example.com"
+			},
+			expectedErrors: []string{"notificationWebhookURL"},
+		},
+		{
+			name: "webhook url without host",
+			update: func(config *DLQHandlerConfig) {
+				config.NotificationWebhookURL = "/dlq"
+			},
+			expectedErrors: []string{"notificationWebhookURL"},
+		},
+		{
+			name: "archival enabled without uri",
+			update: func(config *DLQHandlerConfig) {
+				config.DLQArchivalEnabled = true
+			},
+			expectedErrors: []string{"dlqArchivalURI"},
+		},
+		{
+			name: "archival uri without scheme",
+			update: func(config *DLQHandlerConfig) {
+				config.DLQArchivalURI = "/tmp/dlq"
+			},
+			expectedErrors: []string{"dlqArchivalURI"},
+		},
+		{
+			name: "growth rate monitor",
+			update: func(config *DLQHandlerConfig) {
+				config.GrowthRateSampleInterval = 0
+				config.MaxGrowthRate = -1
+			},
+			expectedErrors: []string{"growthRateSampleInterval", "maxGrowthRate"},
+		},
+		{
+			name: "unknown enum values",
+			update: func(config *DLQHandlerConfig) {
+				config.CheckpointGranularity = "PerShard"
+				config.InvalidTaskPolicy = "Retry"
+			},
+			expectedErrors: []string{"checkpointGranularity", "invalidTaskPolicy"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := DefaultDLQHandlerConfig()
+			test.update(&config)
+
+			err := ValidateConfig(config)
+			if len(test.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			errs := multierr.Errors(err)
+			require.Len(t, errs, len(test.expectedErrors))
+			for _, name := range test.expectedErrors {
+				found := false
+				for _, err := range errs {
+					found = found || strings.HasPrefix(err.Error(), name)
+				}
+				assert.True(t, found, "expected an error for %v in %v", name, err)
+			}
+		})
+	}
+}
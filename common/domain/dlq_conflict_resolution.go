@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// ConflictResolutionPolicyErrorOnConflict fails the merge of a domain DLQ message conflicting with a local domain
+	ConflictResolutionPolicyErrorOnConflict ConflictResolutionPolicy = "ErrorOnConflict"
+	// ConflictResolutionPolicyOverwriteWithSource replaces the conflicting local domain with the domain of the source cluster
+	ConflictResolutionPolicyOverwriteWithSource ConflictResolutionPolicy = "OverwriteWithSource"
+	// ConflictResolutionPolicyKeepLocal keeps the conflicting local domain and drops the domain DLQ message
+	ConflictResolutionPolicyKeepLocal ConflictResolutionPolicy = "KeepLocal"
+
+	// DLQConflictPoliciesPath is the path the DLQ conflict resolution policies handler is served on by the debug HTTP server
+	DLQConflictPoliciesPath = "/api/v1/admin/dlq/conflict-policies"
+
+	defaultConflictResolutionPolicy = ConflictResolutionPolicyErrorOnConflict
+)
+
+type (
+	// ConflictResolutionPolicy tells how a merge applies a domain DLQ message which conflicts with a local domain,
+	// i.e. a local domain has the same name but another ID, or the same ID but another name
+	ConflictResolutionPolicy string
+
+	// DLQConflictPolicyUpdate sets the conflict resolution policy of a domain through the DLQ conflict resolution policies handler
+	DLQConflictPolicyUpdate struct {
+		DomainName string                   `json:"domainName"`
+		Policy     ConflictResolutionPolicy `json:"policy"`
+	}
+
+	dlqConflictPoliciesHandler struct {
+		dlqHandler DLQMessageHandler
+	}
+)
+
+// ParseConflictResolutionPolicy returns the conflict resolution policy of the given name
+func ParseConflictResolutionPolicy(name string) (ConflictResolutionPolicy, error) {
+	switch policy := ConflictResolutionPolicy(name); policy {
+	case ConflictResolutionPolicyErrorOnConflict, ConflictResolutionPolicyOverwriteWithSource, ConflictResolutionPolicyKeepLocal:
+		return policy, nil
+	default:
+		return "", &types.BadRequestError{Message: fmt.Sprintf(
+			"unknown conflict resolution policy %q, supported policies are %v, %v and %v",
+			name,
+			ConflictResolutionPolicyErrorOnConflict,
+			ConflictResolutionPolicyOverwriteWithSource,
+			ConflictResolutionPolicyKeepLocal,
+		)}
+	}
+}
+
+// NewDLQConflictPoliciesHandler returns an HTTP handler which lists the conflict resolution policies of the domains as JSON on GET,
+// and sets the conflict resolution policy of a domain from a DLQConflictPolicyUpdate JSON body on PUT
+func NewDLQConflictPoliciesHandler(dlqHandler DLQMessageHandler) http.Handler {
+	return &dlqConflictPoliciesHandler{
+		dlqHandler: dlqHandler,
+	}
+}
+
+func (h *dlqConflictPoliciesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		policies, err := h.dlqHandler.GetConflictResolutionPolicies(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(policies); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	case http.MethodPut:
+		var update DLQConflictPolicyUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := h.dlqHandler.SetConflictResolutionPolicy(r.Context(), update.DomainName, update.Policy); err != nil {
+			status := http.StatusInternalServerError
+			if _, ok := err.(*types.BadRequestError); ok {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestParseConflictResolutionPolicy(t *testing.T) {
+	for _, policy := range []ConflictResolutionPolicy{
+		ConflictResolutionPolicyErrorOnConflict,
+		ConflictResolutionPolicyOverwriteWithSource,
+		ConflictResolutionPolicyKeepLocal,
+	} {
+		parsed, err := ParseConflictResolutionPolicy(string(policy))
+		assert.NoError(t, err)
+		assert.Equal(t, policy, parsed)
+	}
+
+	_, err := ParseConflictResolutionPolicy("keeplocal")
+	assert.IsType(t, &types.BadRequestError{}, err)
+}
+
+func TestDLQConflictPoliciesHandler(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	policies := map[string]ConflictResolutionPolicy{"test-domain": ConflictResolutionPolicyKeepLocal}
+	dlqHandler := NewMockDLQMessageHandler(controller)
+	dlqHandler.EXPECT().GetConflictResolutionPolicies(gomock.Any()).Return(policies, nil).Times(1)
+	dlqHandler.EXPECT().SetConflictResolutionPolicy(gomock.Any(), "test-domain", ConflictResolutionPolicyOverwriteWithSource).Return(nil).Times(1)
+
+	server := httptest.NewServer(NewDLQConflictPoliciesHandler(dlqHandler))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var result map[string]ConflictResolutionPolicy
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	resp.Body.Close()
+	assert.Equal(t, policies, result)
+
+	resp = putDLQConflictPolicy(t, server.URL, `{"domainName":"test-domain","policy":"OverwriteWithSource"}`)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestDLQConflictPoliciesHandler_Errors(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	dlqHandler := NewMockDLQMessageHandler(controller)
+	dlqHandler.EXPECT().GetConflictResolutionPolicies(gomock.Any()).Return(nil, fmt.Errorf("test")).Times(1)
+	dlqHandler.EXPECT().SetConflictResolutionPolicy(gomock.Any(), "test-domain", ConflictResolutionPolicy("Unknown")).
+		Return(&types.BadRequestError{Message: "test"}).Times(1)
+	dlqHandler.EXPECT().SetConflictResolutionPolicy(gomock.Any(), "test-domain", ConflictResolutionPolicyKeepLocal).
+		Return(fmt.Errorf("test")).Times(1)
+
+	server := httptest.NewServer(NewDLQConflictPoliciesHandler(dlqHandler))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	resp, err = http.Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`not json`, http.StatusBadRequest},
+		{`{"domainName":"test-domain","policy":"Unknown"}`, http.StatusBadRequest},
+		{`{"domainName":"test-domain","policy":"KeepLocal"}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		resp := putDLQConflictPolicy(t, server.URL, tt.body)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.body)
+	}
+}
+
+func putDLQConflictPolicy(t *testing.T, url string, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	dlqAckLevelUpdatedKey = "domainReplication-ack-updated"
+)
+
+type (
+	// DLQConsumerAckLevel is the ack level of a consumer group of the domain DLQ for a task type
+	DLQConsumerAckLevel struct {
+		ConsumerGroup string
+		// TaskType is AllTaskTypes for the consumers of all task types
+		TaskType types.ReplicationTaskType
+		AckLevel int64
+		// UpdatedAt is zero if the ack level was not updated since the update times are recorded
+		UpdatedAt time.Time
+	}
+)
+
+// GetDLQConsumerAckLevels returns the ack levels of all the consumer groups of the DLQ,
+// ordered by consumer group and task type
+func (q *replicationQueueImpl) GetDLQConsumerAckLevels(
+	ctx context.Context,
+) ([]*DLQConsumerAckLevel, error) {
+	dlqMetadata, err := q.queue.GetDLQAckLevels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mergeTokens, err := q.queue.GetDLQMergeTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var consumers []*DLQConsumerAckLevel
+	for key, ackLevel := range dlqMetadata {
+		taskType, consumerGroup, ok := parseDLQAckLevelKey(key)
+		if !ok {
+			continue
+		}
+		consumer := &DLQConsumerAckLevel{
+			ConsumerGroup: consumerGroup,
+			TaskType:      taskType,
+			AckLevel:      ackLevel,
+		}
+		if token, ok := mergeTokens[getDLQAckLevelUpdatedKey(key)]; ok {
+			if updatedAt, err := strconv.ParseInt(token, 10, 64); err == nil {
+				consumer.UpdatedAt = time.Unix(0, updatedAt)
+			}
+		}
+		consumers = append(consumers, consumer)
+	}
+	sortDLQConsumerAckLevels(consumers)
+	return consumers, nil
+}
+
+// recordDLQAckLevelUpdate records when the ack level was updated, it is only informational
+// so the ack level update does not fail if it cannot be recorded
+func (q *replicationQueueImpl) recordDLQAckLevelUpdate(ctx context.Context, ackLevelKey string) {
+	err := q.queue.UpdateDLQMergeToken(
+		ctx,
+		strconv.FormatInt(time.Now().UnixNano(), 10),
+		getDLQAckLevelUpdatedKey(ackLevelKey),
+	)
+	if err != nil {
+		q.logger.Warn("Failed to record the update time of domain DLQ ack level.", tag.Value(ackLevelKey), tag.Error(err))
+	}
+}
+
+func sortDLQConsumerAckLevels(consumers []*DLQConsumerAckLevel) {
+	sort.Slice(consumers, func(i, j int) bool {
+		if consumers[i].ConsumerGroup != consumers[j].ConsumerGroup {
+			return consumers[i].ConsumerGroup < consumers[j].ConsumerGroup
+		}
+		return consumers[i].TaskType < consumers[j].TaskType
+	})
+}
+
+func getDLQAckLevelUpdatedKey(ackLevelKey string) string {
+	return fmt.Sprintf("%v/%v", dlqAckLevelUpdatedKey, ackLevelKey)
+}
+
+// parseDLQAckLevelKey returns the task type and the consumer group of the ack level key,
+// it returns false for the keys of the DLQ metadata which are not ack levels of a consumer group
+func parseDLQAckLevelKey(key string) (types.ReplicationTaskType, string, bool) {
+	if !strings.HasPrefix(key, localDomainReplicationCluster) {
+		return 0, "", false
+	}
+	suffix := strings.TrimPrefix(key, localDomainReplicationCluster)
+
+	consumerGroup := DefaultConsumerGroup
+	if i := strings.Index(suffix, "@"); i >= 0 {
+		suffix, consumerGroup = suffix[:i], suffix[i+1:]
+	}
+	if suffix == "" {
+		return AllTaskTypes, consumerGroup, true
+	}
+	if !strings.HasPrefix(suffix, "-") {
+		return 0, "", false
+	}
+
+	var taskType types.ReplicationTaskType
+	if err := taskType.UnmarshalText([]byte(suffix[1:])); err != nil {
+		return 0, "", false
+	}
+	return taskType, consumerGroup, true
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestParseDLQAckLevelKey(t *testing.T) {
+	tests := []struct {
+		taskType      types.ReplicationTaskType
+		consumerGroup string
+	}{
+		{AllTaskTypes, DefaultConsumerGroup},
+		{types.ReplicationTaskTypeDomain, DefaultConsumerGroup},
+		{types.ReplicationTaskTypeWorkflowReset, DefaultConsumerGroup},
+		{AllTaskTypes, "replay"},
+		{types.ReplicationTaskTypeHistoryV2, "shard-1"},
+	}
+	for _, tt := range tests {
+		taskType, consumerGroup, ok := parseDLQAckLevelKey(getDLQAckLevelKey(tt.taskType, tt.consumerGroup))
+		assert.True(t, ok)
+		assert.Equal(t, tt.taskType, taskType)
+		assert.Equal(t, tt.consumerGroup, consumerGroup)
+	}
+
+	for _, key := range []string{dlqReplayAckLevelKey, "domainReplicationX", "someCluster"} {
+		_, _, ok := parseDLQAckLevelKey(key)
+		assert.False(t, ok, key)
+	}
+}
+
+func (s *replicationQueueSuite) TestGetDLQConsumerAckLevels() {
+	updatedAt := time.Unix(1700000000, 0)
+	s.mockQueue.EXPECT().GetDLQAckLevels(gomock.Any()).Return(map[string]int64{
+		"domainReplication":             10,
+		"domainReplication-History@b":   20,
+		"domainReplication@b":           30,
+		"domainReplication-Domain@a":    40,
+		dlqReplayAckLevelKey:            50,
+		"domainReplication-unknown@c":   60,
+		"domainReplication-HistoryV2@a": 70,
+	}, nil).Times(1)
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).Return(map[string]string{
+		getDLQAckLevelUpdatedKey("domainReplication@b"): strconv.FormatInt(updatedAt.UnixNano(), 10),
+		dlqExecutionJournalKey:                          "{}",
+	}, nil).Times(1)
+
+	consumers, err := s.replicationQueue.GetDLQConsumerAckLevels(context.Background())
+	s.NoError(err)
+	s.Equal([]*DLQConsumerAckLevel{
+		{ConsumerGroup: "a", TaskType: types.ReplicationTaskTypeDomain, AckLevel: 40},
+		{ConsumerGroup: "a", TaskType: types.ReplicationTaskTypeHistoryV2, AckLevel: 70},
+		{ConsumerGroup: "b", TaskType: AllTaskTypes, AckLevel: 30, UpdatedAt: updatedAt},
+		{ConsumerGroup: "b", TaskType: types.ReplicationTaskTypeHistory, AckLevel: 20},
+		{ConsumerGroup: DefaultConsumerGroup, TaskType: AllTaskTypes, AckLevel: 10},
+	}, consumers)
+}
+
+func (s *replicationQueueSuite) TestGetDLQConsumerAckLevels_Error() {
+	s.mockQueue.EXPECT().GetDLQAckLevels(gomock.Any()).Return(nil, errors.New("some error")).Times(1)
+
+	_, err := s.replicationQueue.GetDLQConsumerAckLevels(context.Background())
+	s.Error(err)
+}
+
+func (s *replicationQueueSuite) TestUpdateDLQAckLevel_RecordUpdateTimeFailed() {
+	key := getDLQAckLevelKey(AllTaskTypes, "replay")
+	s.mockQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), int64(10), key).Return(nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQMergeToken(gomock.Any(), gomock.Any(), getDLQAckLevelUpdatedKey(key)).
+		Return(errors.New("some error")).Times(1)
+
+	// the update time is informational, the ack level update succeeds without it
+	err := s.replicationQueue.UpdateDLQAckLevel(context.Background(), AllTaskTypes, "replay", 10)
+	s.NoError(err)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	defaultDLQDeduplicationFalsePositiveRate = 0.0001
+)
+
+type (
+	// dlqDeduplicator remembers recently merged DLQ messages in a sliding window made of two bloom filters.
+	// Once the current filter is full it replaces the previous one, so a message is remembered
+	// for at least windowSize and at most twice windowSize merged messages.
+	dlqDeduplicator struct {
+		windowSize        dynamicconfig.IntPropertyFn
+		falsePositiveRate dynamicconfig.FloatPropertyFn
+
+		mu       sync.Mutex
+		current  *bloomFilter
+		previous *bloomFilter
+	}
+
+	bloomFilter struct {
+		bits      []uint64
+		numHashes uint64
+		capacity  int
+		count     int
+	}
+)
+
+// newDLQSeenTaskIDs returns an LRU cache of the SourceTaskIDs of executed DLQ messages,
+// or nil if the capacity is not positive
+func newDLQSeenTaskIDs(capacity int) cache.Cache {
+	if capacity <= 0 {
+		return nil
+	}
+	// the cache evicts once it holds MaxCount entries, so it keeps one entry less
+	return cache.New(&cache.Options{MaxCount: capacity + 1})
+}
+
+func newDLQDeduplicator(
+	windowSize dynamicconfig.IntPropertyFn,
+	falsePositiveRate dynamicconfig.FloatPropertyFn,
+) *dlqDeduplicator {
+	return &dlqDeduplicator{
+		windowSize:        windowSize,
+		falsePositiveRate: falsePositiveRate,
+	}
+}
+
+// probablySeen returns true if the task was probably merged within the window
+func (d *dlqDeduplicator) probablySeen(task *types.ReplicationTask) bool {
+	if d.windowSize() <= 0 {
+		return false
+	}
+
+	h1, h2 := dlqTaskHash(task)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return (d.current != nil && d.current.contains(h1, h2)) ||
+		(d.previous != nil && d.previous.contains(h1, h2))
+}
+
+// add records the task as merged
+func (d *dlqDeduplicator) add(task *types.ReplicationTask) {
+	windowSize := d.windowSize()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if windowSize <= 0 {
+		d.current, d.previous = nil, nil
+		return
+	}
+	if d.current == nil || d.current.count >= d.current.capacity {
+		d.previous = d.current
+		d.current = newBloomFilter(windowSize, d.falsePositiveRate())
+	}
+	d.current.add(dlqTaskHash(task))
+}
+
+// dlqTaskHash hashes the content of the task. The SourceTaskID of a task read from the DLQ is the ID
+// of the DLQ message, so duplicated messages of the same task are identified by their content instead.
+func dlqTaskHash(task *types.ReplicationTask) (uint64, uint64) {
+	content := *task
+	content.SourceTaskID = 0
+	content.EnqueuedAt = time.Time{}
+	// the task only contains json serializable fields
+	payload, _ := json.Marshal(&content)
+
+	h := fnv.New128a()
+	h.Write(payload)
+	sum := h.Sum(nil)
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:])
+}
+
+func newBloomFilter(capacity int, falsePositiveRate float64) *bloomFilter {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = defaultDLQDeduplicationFalsePositiveRate
+	}
+	numBits := math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	numHashes := math.Max(1, math.Round(numBits/float64(capacity)*math.Ln2))
+	return &bloomFilter{
+		bits:      make([]uint64, (uint64(numBits)+63)/64),
+		numHashes: uint64(numHashes),
+		capacity:  capacity,
+	}
+}
+
+func (f *bloomFilter) add(h1, h2 uint64) {
+	numBits := uint64(len(f.bits)) * 64
+	for i := uint64(0); i < f.numHashes; i++ {
+		bit := (h1 + i*h2) % numBits
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+	f.count++
+}
+
+func (f *bloomFilter) contains(h1, h2 uint64) bool {
+	numBits := uint64(len(f.bits)) * 64
+	for i := uint64(0); i < f.numHashes; i++ {
+		bit := (h1 + i*h2) % numBits
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isSeenTaskID returns true if the DLQ message was executed by a merge before. The SourceTaskID of a DLQ message
+// is its message ID, so a message executed by a merge which failed to move the ack level past it is read again.
+func (d *dlqMessageHandlerImpl) isSeenTaskID(message *types.ReplicationTask) bool {
+	return d.seenTaskIDs != nil && d.seenTaskIDs.Get(message.SourceTaskID) != nil
+}
+
+// addSeenTaskIDs remembers the SourceTaskIDs of the executed DLQ messages
+func (d *dlqMessageHandlerImpl) addSeenTaskIDs(taskIDs []int64) {
+	if d.seenTaskIDs == nil {
+		return
+	}
+	for _, taskID := range taskIDs {
+		d.seenTaskIDs.Put(taskID, struct{}{})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestDLQDeduplicator(t *testing.T) {
+	deduplicator := newDLQDeduplicator(dynamicconfig.GetIntPropertyFn(100), dynamicconfig.GetFloatPropertyFn(0.0001))
+
+	task := newDomainTaskForDeduplication("domain", 1)
+	assert.False(t, deduplicator.probablySeen(task))
+	deduplicator.add(task)
+	assert.True(t, deduplicator.probablySeen(task))
+
+	// the same task read from another DLQ message is a duplicate
+	duplicate := newDomainTaskForDeduplication("domain", 1)
+	duplicate.SourceTaskID = 100
+	duplicate.EnqueuedAt = time.Now()
+	assert.True(t, deduplicator.probablySeen(duplicate))
+
+	assert.False(t, deduplicator.probablySeen(newDomainTaskForDeduplication("domain", 2)))
+	assert.False(t, deduplicator.probablySeen(newDomainTaskForDeduplication("other-domain", 1)))
+}
+
+func TestDLQDeduplicator_SlidingWindow(t *testing.T) {
+	windowSize := 10
+	deduplicator := newDLQDeduplicator(dynamicconfig.GetIntPropertyFn(windowSize), dynamicconfig.GetFloatPropertyFn(0.0001))
+
+	first := newDomainTaskForDeduplication("domain", 0)
+	deduplicator.add(first)
+	for i := 1; i < 2*windowSize; i++ {
+		deduplicator.add(newDomainTaskForDeduplication("domain", int64(i)))
+	}
+	// remembered while its filter is the previous one
+	assert.True(t, deduplicator.probablySeen(first))
+
+	deduplicator.add(newDomainTaskForDeduplication("domain", int64(2*windowSize)))
+	assert.False(t, deduplicator.probablySeen(first))
+}
+
+func TestDLQDeduplicator_Disabled(t *testing.T) {
+	windowSize := 0
+	deduplicator := newDLQDeduplicator(
+		func(...dynamicconfig.FilterOption) int { return windowSize },
+		dynamicconfig.GetFloatPropertyFn(0.0001),
+	)
+
+	task := newDomainTaskForDeduplication("domain", 1)
+	deduplicator.add(task)
+	assert.False(t, deduplicator.probablySeen(task))
+
+	windowSize = 10
+	deduplicator.add(task)
+	assert.True(t, deduplicator.probablySeen(task))
+
+	windowSize = 0
+	assert.False(t, deduplicator.probablySeen(task))
+}
+
+func TestBloomFilter_FalsePositiveRate(t *testing.T) {
+	capacity := 10000
+	falsePositiveRate := 0.01
+	filter := newBloomFilter(capacity, falsePositiveRate)
+	for i := 0; i < capacity; i++ {
+		filter.add(dlqTaskHash(newDomainTaskForDeduplication("domain", int64(i))))
+	}
+
+	falsePositives := 0
+	for i := capacity; i < 2*capacity; i++ {
+		if filter.contains(dlqTaskHash(newDomainTaskForDeduplication("domain", int64(i)))) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, float64(falsePositives)/float64(capacity), 2*falsePositiveRate)
+}
+
+func newDomainTaskForDeduplication(domainName string, failoverVersion int64) *types.ReplicationTask {
+	return &types.ReplicationTask{
+		TaskType: types.ReplicationTaskTypeDomain.Ptr(),
+		DomainTaskAttributes: &types.DomainTaskAttributes{
+			Info:            &types.DomainInfo{Name: domainName},
+			FailoverVersion: failoverVersion,
+		},
+	}
+}
+
+func TestDLQSeenTaskIDs(t *testing.T) {
+	handler := &dlqMessageHandlerImpl{seenTaskIDs: newDLQSeenTaskIDs(2)}
+	message := func(taskID int64) *types.ReplicationTask {
+		return &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: taskID}
+	}
+
+	assert.False(t, handler.isSeenTaskID(message(1)))
+	handler.addSeenTaskIDs([]int64{1, 2})
+	assert.True(t, handler.isSeenTaskID(message(1)))
+	assert.True(t, handler.isSeenTaskID(message(2)))
+	assert.False(t, handler.isSeenTaskID(message(3)))
+
+	// the least recently seen task ID is evicted once the cache is full
+	handler.addSeenTaskIDs([]int64{3})
+	assert.False(t, handler.isSeenTaskID(message(1)))
+	assert.True(t, handler.isSeenTaskID(message(2)))
+	assert.True(t, handler.isSeenTaskID(message(3)))
+}
+
+func TestDLQSeenTaskIDs_Disabled(t *testing.T) {
+	handler := &dlqMessageHandlerImpl{seenTaskIDs: newDLQSeenTaskIDs(0)}
+	assert.Nil(t, handler.seenTaskIDs)
+
+	handler.addSeenTaskIDs([]int64{1})
+	assert.False(t, handler.isSeenTaskID(&types.ReplicationTask{SourceTaskID: 1}))
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// DomainPurgeFunc purges the domain replication DLQ messages of a domain, e.g. DLQMessageHandler.PurgeDomain
+	DomainPurgeFunc func(ctx context.Context, domainID string) error
+
+	dlqCleanupDomainManager struct {
+		persistence.DomainManager
+
+		purgeDomain DomainPurgeFunc
+		logger      log.Logger
+	}
+)
+
+// NewDLQCleanupDomainManager wraps the domain manager to purge the domain replication DLQ messages of the domains
+// it deletes, the messages of a deleted domain can never be merged and would only waste storage
+func NewDLQCleanupDomainManager(
+	domainManager persistence.DomainManager,
+	purgeDomain DomainPurgeFunc,
+	logger log.Logger,
+) persistence.DomainManager {
+
+	return &dlqCleanupDomainManager{
+		DomainManager: domainManager,
+		purgeDomain:   purgeDomain,
+		logger:        logger,
+	}
+}
+
+// DeleteDomain deletes the domain, then purges its DLQ messages
+func (m *dlqCleanupDomainManager) DeleteDomain(
+	ctx context.Context,
+	request *persistence.DeleteDomainRequest,
+) error {
+
+	if err := m.DomainManager.DeleteDomain(ctx, request); err != nil {
+		return err
+	}
+	m.purge(ctx, request.ID)
+	return nil
+}
+
+// DeleteDomainByName deletes the domain, then purges its DLQ messages
+func (m *dlqCleanupDomainManager) DeleteDomainByName(
+	ctx context.Context,
+	request *persistence.DeleteDomainByNameRequest,
+) error {
+
+	// the DLQ messages are indexed by domain ID
+	resp, err := m.DomainManager.GetDomain(ctx, &persistence.GetDomainRequest{Name: request.Name})
+	if err != nil {
+		return err
+	}
+	if err := m.DomainManager.DeleteDomainByName(ctx, request); err != nil {
+		return err
+	}
+	m.purge(ctx, resp.Info.ID)
+	return nil
+}
+
+// purge does not fail the deletion, the domain is already deleted and the deletion is not retried
+func (m *dlqCleanupDomainManager) purge(ctx context.Context, domainID string) {
+	if err := m.purgeDomain(ctx, domainID); err != nil {
+		m.logger.Error("Failed to purge domain DLQ messages of deleted domain.",
+			tag.WorkflowDomainID(domainID),
+			tag.Error(err))
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+// domainDLQ keeps the domain IDs of the DLQ messages in memory
+type domainDLQ struct {
+	ReplicationQueue
+
+	sync.Mutex
+	messages map[int64]string
+}
+
+func (q *domainDLQ) RangeDeleteMessagesFromDLQByDomain(
+	_ context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	q.Lock()
+	defer q.Unlock()
+	for messageID, messageDomainID := range q.messages {
+		if messageDomainID == domainID && messageID > firstMessageID && messageID <= lastMessageID {
+			delete(q.messages, messageID)
+		}
+	}
+	return nil
+}
+
+func (q *domainDLQ) messageIDs() []int64 {
+	q.Lock()
+	defer q.Unlock()
+	var messageIDs []int64
+	for messageID := range q.messages {
+		messageIDs = append(messageIDs, messageID)
+	}
+	sort.Slice(messageIDs, func(i, j int) bool { return messageIDs[i] < messageIDs[j] })
+	return messageIDs
+}
+
+func newDLQCleanupTest(t *testing.T) (*domainDLQ, *persistence.MockDomainManager, persistence.DomainManager) {
+	controller := gomock.NewController(t)
+	dlq := &domainDLQ{messages: map[int64]string{
+		0: "domain-a",
+		1: "domain-b",
+		2: "domain-a",
+		3: "domain-b",
+	}}
+	handler := NewDLQMessageHandler(NewReplicationTaskExecutorRegistry(nil), dlq, loggerimpl.NewNopLogger())
+	domainManager := persistence.NewMockDomainManager(controller)
+	return dlq, domainManager, NewDLQCleanupDomainManager(domainManager, handler.PurgeDomain, loggerimpl.NewNopLogger())
+}
+
+func TestDLQCleanupDomainManager_DeleteDomain(t *testing.T) {
+	dlq, domainManager, cleanupManager := newDLQCleanupTest(t)
+
+	domainManager.EXPECT().DeleteDomain(gomock.Any(), &persistence.DeleteDomainRequest{ID: "domain-a"}).Return(nil).Times(1)
+	assert.NoError(t, cleanupManager.DeleteDomain(context.Background(), &persistence.DeleteDomainRequest{ID: "domain-a"}))
+	// only the messages of the other domain remain
+	assert.Equal(t, []int64{1, 3}, dlq.messageIDs())
+}
+
+func TestDLQCleanupDomainManager_DeleteDomainByName(t *testing.T) {
+	dlq, domainManager, cleanupManager := newDLQCleanupTest(t)
+
+	domainManager.EXPECT().GetDomain(gomock.Any(), &persistence.GetDomainRequest{Name: "name-b"}).
+		Return(&persistence.GetDomainResponse{Info: &persistence.DomainInfo{ID: "domain-b", Name: "name-b"}}, nil).Times(1)
+	domainManager.EXPECT().DeleteDomainByName(gomock.Any(), &persistence.DeleteDomainByNameRequest{Name: "name-b"}).Return(nil).Times(1)
+	assert.NoError(t, cleanupManager.DeleteDomainByName(context.Background(), &persistence.DeleteDomainByNameRequest{Name: "name-b"}))
+	assert.Equal(t, []int64{0, 2}, dlq.messageIDs())
+}
+
+func TestDLQCleanupDomainManager_DeleteFailed(t *testing.T) {
+	dlq, domainManager, cleanupManager := newDLQCleanupTest(t)
+	testError := errors.New("test")
+
+	// the messages of a domain which was not deleted are kept
+	domainManager.EXPECT().DeleteDomain(gomock.Any(), gomock.Any()).Return(testError).Times(1)
+	assert.Equal(t, testError, cleanupManager.DeleteDomain(context.Background(), &persistence.DeleteDomainRequest{ID: "domain-a"}))
+	domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).Return(nil, &types.EntityNotExistsError{}).Times(1)
+	assert.Error(t, cleanupManager.DeleteDomainByName(context.Background(), &persistence.DeleteDomainByNameRequest{Name: "name-a"}))
+	assert.Equal(t, []int64{0, 1, 2, 3}, dlq.messageIDs())
+}
+
+func TestDLQCleanupDomainManager_PurgeFailed(t *testing.T) {
+	controller := gomock.NewController(t)
+	domainManager := persistence.NewMockDomainManager(controller)
+	cleanupManager := NewDLQCleanupDomainManager(
+		domainManager,
+		func(context.Context, string) error { return errors.New("test") },
+		loggerimpl.NewNopLogger(),
+	)
+
+	// the domain is deleted, a failed purge does not fail the deletion
+	domainManager.EXPECT().DeleteDomain(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	assert.NoError(t, cleanupManager.DeleteDomain(context.Background(), &persistence.DeleteDomainRequest{ID: "domain-a"}))
+}
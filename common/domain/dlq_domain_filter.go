@@ -0,0 +1,39 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package domain
+
+type (
+	// DomainFilterFunc decides whether the DLQ messages of a domain are merged,
+	// messages of domains it returns false for are deleted without being executed
+	DomainFilterFunc func(domainID string) bool
+)
+
+// NewDomainBlocklistFilter returns a filter excluding the given domains from DLQ merges,
+// e.g. domains pending deletion
+func NewDomainBlocklistFilter(domainIDs []string) DomainFilterFunc {
+	blocklist := make(map[string]struct{}, len(domainIDs))
+	for _, domainID := range domainIDs {
+		blocklist[domainID] = struct{}{}
+	}
+	return func(domainID string) bool {
+		_, blocked := blocklist[domainID]
+		return !blocked
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"github.com/uber/cadence/common/types"
+)
+
+// dryRunTaskExecutor is a ReplicationTaskExecutor which records the tasks it is given instead of applying them
+type dryRunTaskExecutor struct {
+	previews []DLQMergePreview
+}
+
+var _ ReplicationTaskExecutor = (*dryRunTaskExecutor)(nil)
+
+func (e *dryRunTaskExecutor) ExecuteReplicationTask(task *types.ReplicationTask, _ string) error {
+	preview := newDLQMergePreview(task.DomainTaskAttributes)
+	preview.MessageID = task.SourceTaskID
+	preview.TaskType = task.GetTaskType()
+	preview.EnqueuedAt = task.EnqueuedAt
+	e.previews = append(e.previews, preview)
+	return nil
+}
+
+func (e *dryRunTaskExecutor) Execute(task *types.DomainTaskAttributes) error {
+	preview := newDLQMergePreview(task)
+	preview.TaskType = types.ReplicationTaskTypeDomain
+	e.previews = append(e.previews, preview)
+	return nil
+}
+
+func (e *dryRunTaskExecutor) Overwrite(task *types.DomainTaskAttributes) error {
+	return e.Execute(task)
+}
+
+// AddMigration is a no-op, previews show domain replication tasks as they were persisted
+func (e *dryRunTaskExecutor) AddMigration(_, _ int, _ MigrationFunc) error {
+	return nil
+}
+
+func newDLQMergePreview(task *types.DomainTaskAttributes) DLQMergePreview {
+	if task == nil {
+		return DLQMergePreview{}
+	}
+	preview := DLQMergePreview{
+		DomainID:        task.ID,
+		DomainOperation: task.GetDomainOperation(),
+		FailoverVersion: task.FailoverVersion,
+	}
+	if task.Info != nil {
+		preview.DomainName = task.Info.Name
+	}
+	return preview
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package domain
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// DLQEncryptionKeySize is the size of the AES-256 keys DLQ message payloads are encrypted with
+const DLQEncryptionKeySize = 32
+
+type (
+	// EncryptionKeyProvider supplies the AES-256-GCM keys used to encrypt DLQ message payloads at rest.
+	// Keys are identified by an ID which is persisted with each message, so that messages encrypted
+	// before a key rotation can still be decrypted as long as the provider keeps the old key.
+	EncryptionKeyProvider interface {
+		// CurrentKey returns the key new messages are encrypted with
+		CurrentKey() (keyID string, key []byte, err error)
+		// GetKey returns the key with the given ID
+		GetKey(keyID string) ([]byte, error)
+	}
+
+	// ReplicationQueueOption configures optional features of the replication queue
+	ReplicationQueueOption func(*replicationQueueImpl)
+)
+
+// WithEncryption encrypts the payloads of DLQ messages with the keys of the provider.
+// Messages read with a key other than the current one are re-encrypted with the current key.
+func WithEncryption(keyProvider EncryptionKeyProvider) ReplicationQueueOption {
+	return func(q *replicationQueueImpl) {
+		q.encryptionKeys = keyProvider
+	}
+}
+
+func newDLQPayloadCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != DLQEncryptionKeySize {
+		return nil, fmt.Errorf("DLQ encryption key must be %v bytes, got %v", DLQEncryptionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptDLQPayload(key []byte, plaintext []byte) (nonce []byte, ciphertext []byte, err error) {
+	aead, err := newDLQPayloadCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, aead.Seal(nil, nonce, plaintext, nil), nil
+}
+
+func decryptDLQPayload(key []byte, nonce []byte, ciphertext []byte) ([]byte, error) {
+	aead, err := newDLQPayloadCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid DLQ payload nonce size %v", len(nonce))
+	}
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
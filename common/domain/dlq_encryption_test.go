@@ -0,0 +1,220 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package domain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+type testEncryptionKeyProvider struct {
+	currentKeyID string
+	keys         map[string][]byte
+}
+
+func newTestEncryptionKeyProvider(keyIDs ...string) *testEncryptionKeyProvider {
+	provider := &testEncryptionKeyProvider{keys: make(map[string][]byte)}
+	for i, keyID := range keyIDs {
+		provider.keys[keyID] = bytes.Repeat([]byte{byte(i + 1)}, DLQEncryptionKeySize)
+		provider.currentKeyID = keyID
+	}
+	return provider
+}
+
+func (p *testEncryptionKeyProvider) CurrentKey() (string, []byte, error) {
+	return p.currentKeyID, p.keys[p.currentKeyID], nil
+}
+
+func (p *testEncryptionKeyProvider) GetKey(keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %v", keyID)
+	}
+	return key, nil
+}
+
+func newEncryptedReplicationQueue(t *testing.T, keys EncryptionKeyProvider) (*replicationQueueImpl, *persistence.MockQueueManager) {
+	mockQueue := persistence.NewMockQueueManager(gomock.NewController(t))
+	queue := NewReplicationQueue(
+		mockQueue,
+		"testCluster",
+		metrics.NewNoopMetricsClient(),
+		loggerimpl.NewNopLogger(),
+		WithEncryption(keys),
+	).(*replicationQueueImpl)
+	return queue, mockQueue
+}
+
+func testEncryptedReplicationTask() *types.ReplicationTask {
+	return &types.ReplicationTask{
+		TaskType: types.ReplicationTaskTypeDomain.Ptr(),
+		DomainTaskAttributes: &types.DomainTaskAttributes{
+			DomainOperation: types.DomainOperationUpdate.Ptr(),
+			ID:              "some random domain ID",
+			Info:            &types.DomainInfo{Name: "secret-domain"},
+		},
+		SourceCluster: "cluster-b",
+	}
+}
+
+func TestEncryptDecryptDLQPayload(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, DLQEncryptionKeySize)
+
+	nonce, ciphertext, err := encryptDLQPayload(key, []byte("payload"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "payload")
+
+	plaintext, err := decryptDLQPayload(key, nonce, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), plaintext)
+
+	_, err = decryptDLQPayload(bytes.Repeat([]byte{2}, DLQEncryptionKeySize), nonce, ciphertext)
+	assert.Error(t, err)
+
+	_, _, err = encryptDLQPayload([]byte("short key"), []byte("payload"))
+	assert.Error(t, err)
+}
+
+func TestEncodeDecodeReplicationTask_Encrypted(t *testing.T) {
+	keys := newTestEncryptionKeyProvider("key-1")
+	task := testEncryptedReplicationTask()
+
+	data, err := encodeReplicationTask(task, DLQSerializationFormatThrift, keys, dlqCompressionDisabled)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"schemaVersion":2`)
+	assert.Contains(t, string(data), `"keyID":"key-1"`)
+	assert.NotContains(t, string(data), "secret-domain")
+
+	envelope, err := unmarshalDLQEnvelope(data)
+	require.NoError(t, err)
+	require.NoError(t, envelope.decrypt(keys))
+	decoded, err := envelope.decode()
+	require.NoError(t, err)
+	assert.Equal(t, task, decoded)
+
+	// hosts without the keys can not decode the message
+	_, err = DecodeReplicationTask(data)
+	assert.Error(t, err)
+}
+
+func TestReplicationQueue_EncryptionRoundTrip(t *testing.T) {
+	keys := newTestEncryptionKeyProvider("key-1")
+	queue, mockQueue := newEncryptedReplicationQueue(t, keys)
+	task := testEncryptedReplicationTask()
+
+	var persisted []byte
+	mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "some random domain ID", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ *int32, payload []byte) error {
+			persisted = payload
+			return nil
+		}).Times(1)
+	require.NoError(t, queue.PublishToDLQ(context.Background(), task))
+	assert.NotContains(t, string(persisted), "secret-domain")
+
+	mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).
+		Return([]*persistence.QueueMessage{{ID: 1, Payload: persisted}}, nil, nil).Times(1)
+	mockQueue.EXPECT().UpdateDLQMessagePayload(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	tasks, _, err := queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task.DomainTaskAttributes, tasks[0].DomainTaskAttributes)
+	assert.Equal(t, "cluster-b", tasks[0].SourceCluster)
+	assert.Equal(t, DLQSchemaVersion1, tasks[0].SchemaVersion)
+}
+
+func TestReplicationQueue_EncryptionKeyRotation(t *testing.T) {
+	keys := newTestEncryptionKeyProvider("key-1")
+	persisted, err := encodeReplicationTask(testEncryptedReplicationTask(), DLQSerializationFormatThrift, keys, dlqCompressionDisabled)
+	require.NoError(t, err)
+	unencrypted, err := EncodeReplicationTask(testEncryptedReplicationTask())
+	require.NoError(t, err)
+
+	keys = newTestEncryptionKeyProvider("key-1", "key-2")
+	queue, mockQueue := newEncryptedReplicationQueue(t, keys)
+
+	rewritten := make(map[int64][]byte)
+	mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).
+		Return([]*persistence.QueueMessage{{ID: 1, Payload: persisted}, {ID: 2, Payload: unencrypted}}, nil, nil).Times(1)
+	mockQueue.EXPECT().UpdateDLQMessagePayload(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, messageID int64, payload []byte) error {
+			rewritten[messageID] = payload
+			return nil
+		}).Times(2)
+
+	tasks, _, err := queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	require.Len(t, rewritten, 2)
+	for messageID, payload := range rewritten {
+		envelope, err := unmarshalDLQEnvelope(payload)
+		require.NoError(t, err)
+		assert.Equal(t, DLQSchemaVersionEncrypted, envelope.SchemaVersion, messageID)
+		assert.Equal(t, "key-2", envelope.KeyID, messageID)
+		require.NoError(t, envelope.decrypt(keys))
+		decoded, err := envelope.decode()
+		require.NoError(t, err)
+		assert.Equal(t, testEncryptedReplicationTask(), decoded)
+	}
+}
+
+func TestReplicationQueue_EncryptionKeyRotation_UpdateFailure(t *testing.T) {
+	keys := newTestEncryptionKeyProvider("key-1")
+	persisted, err := encodeReplicationTask(testEncryptedReplicationTask(), DLQSerializationFormatThrift, keys, dlqCompressionDisabled)
+	require.NoError(t, err)
+
+	keys = newTestEncryptionKeyProvider("key-1", "key-2")
+	queue, mockQueue := newEncryptedReplicationQueue(t, keys)
+	mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).
+		Return([]*persistence.QueueMessage{{ID: 1, Payload: persisted}}, nil, nil).Times(1)
+	mockQueue.EXPECT().UpdateDLQMessagePayload(gomock.Any(), int64(1), gomock.Any()).
+		Return(errors.New("persistence error")).Times(1)
+
+	// the message is still readable with the old key and re-encrypted on a later read
+	tasks, _, err := queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
+
+func TestReplicationQueue_EncryptionKeyMissing(t *testing.T) {
+	persisted, err := encodeReplicationTask(testEncryptedReplicationTask(), DLQSerializationFormatThrift, newTestEncryptionKeyProvider("key-1"), dlqCompressionDisabled)
+	require.NoError(t, err)
+
+	queue, mockQueue := newEncryptedReplicationQueue(t, newTestEncryptionKeyProvider("key-2"))
+	mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).
+		Return([]*persistence.QueueMessage{{ID: 1, Payload: persisted}}, nil, nil).Times(1)
+
+	_, _, err = queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "key-1")
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"sync"
+
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// DeduplicationFilter remembers the source task IDs of the replication tasks enqueued to the DLQ,
+	// so a task a producer enqueues again after a retry is not added to the DLQ twice.
+	// It may report false positives but must not forget a task it marked seen within its window.
+	DeduplicationFilter interface {
+		// IsDuplicate returns true if the task was probably enqueued before
+		IsDuplicate(ctx context.Context, taskID int64) (bool, error)
+		// MarkSeen records the task as enqueued
+		MarkSeen(ctx context.Context, taskID int64) error
+	}
+
+	// bloomDeduplicationFilter remembers the task IDs in a sliding window of two bloom filters,
+	// once the current filter is full it replaces the previous one
+	bloomDeduplicationFilter struct {
+		capacity          int
+		falsePositiveRate float64
+
+		sync.Mutex
+		current  *bloomFilter
+		previous *bloomFilter
+	}
+)
+
+var _ DeduplicationFilter = (*bloomDeduplicationFilter)(nil)
+
+// NewBloomDeduplicationFilter returns an in-memory DeduplicationFilter remembering at least capacity
+// and at most twice capacity task IDs. It only deduplicates the tasks enqueued by the same host,
+// a filter shared by the hosts of the cluster can be plugged in through the same interface.
+func NewBloomDeduplicationFilter(capacity int, falsePositiveRate float64) DeduplicationFilter {
+	return &bloomDeduplicationFilter{
+		capacity:          capacity,
+		falsePositiveRate: falsePositiveRate,
+	}
+}
+
+func (f *bloomDeduplicationFilter) IsDuplicate(_ context.Context, taskID int64) (bool, error) {
+	h1, h2 := taskIDHash(taskID)
+
+	f.Lock()
+	defer f.Unlock()
+	return (f.current != nil && f.current.contains(h1, h2)) ||
+		(f.previous != nil && f.previous.contains(h1, h2)), nil
+}
+
+func (f *bloomDeduplicationFilter) MarkSeen(_ context.Context, taskID int64) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.current == nil || f.current.count >= f.current.capacity {
+		f.previous = f.current
+		f.current = newBloomFilter(f.capacity, f.falsePositiveRate)
+	}
+	f.current.add(taskIDHash(taskID))
+	return nil
+}
+
+func taskIDHash(taskID int64) (uint64, uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(taskID))
+	h := fnv.New128a()
+	h.Write(buf[:])
+	sum := h.Sum(nil)
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:])
+}
+
+// WithEnqueueDeduplication drops the tasks the filter reports as duplicates when they are enqueued to the DLQ.
+// Tasks without a source task ID are always enqueued, as are all tasks while the filter is unavailable.
+func WithEnqueueDeduplication(filter DeduplicationFilter) ReplicationQueueOption {
+	return func(q *replicationQueueImpl) {
+		q.deduplicationFilter = filter
+	}
+}
+
+// isDuplicate tells whether the task was enqueued to the DLQ before, the task is assumed not
+// to be a duplicate if the filter fails so that no task is lost while the filter is unavailable
+func (q *replicationQueueImpl) isDuplicate(ctx context.Context, task *types.ReplicationTask) bool {
+	if q.deduplicationFilter == nil || task.SourceTaskID <= 0 {
+		return false
+	}
+	duplicate, err := q.deduplicationFilter.IsDuplicate(ctx, task.SourceTaskID)
+	if err != nil {
+		q.metricsClient.Scope(metrics.DomainReplicationQueueScope).IncCounter(metrics.DomainReplicationDLQEnqueueDedupErrors)
+		q.logger.Warn("Failed to check whether domain DLQ message is a duplicate.", tag.TaskID(task.SourceTaskID), tag.Error(err))
+		return false
+	}
+	if duplicate {
+		q.metricsClient.Scope(metrics.DomainReplicationQueueScope, dlqMessageMetricsTags(task)...).
+			IncCounter(metrics.DomainReplicationDLQEnqueueDuplicateCount)
+		q.logger.Info("Dropping duplicate domain DLQ message.", tag.TaskID(task.SourceTaskID))
+	}
+	return duplicate
+}
+
+// markSeen records the enqueued tasks in the filter, a task the filter fails to record may be enqueued again
+func (q *replicationQueueImpl) markSeen(ctx context.Context, tasks ...*types.ReplicationTask) {
+	if q.deduplicationFilter == nil {
+		return
+	}
+	for _, task := range tasks {
+		if task.SourceTaskID <= 0 {
+			continue
+		}
+		if err := q.deduplicationFilter.MarkSeen(ctx, task.SourceTaskID); err != nil {
+			q.metricsClient.Scope(metrics.DomainReplicationQueueScope).IncCounter(metrics.DomainReplicationDLQEnqueueDedupErrors)
+			q.logger.Warn("Failed to mark domain DLQ message as seen.", tag.TaskID(task.SourceTaskID), tag.Error(err))
+		}
+	}
+}
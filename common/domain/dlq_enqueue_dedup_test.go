@@ -0,0 +1,188 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+// unavailableDeduplicationFilter fails every call, as a filter backed by an unreachable store would
+type unavailableDeduplicationFilter struct{}
+
+func (unavailableDeduplicationFilter) IsDuplicate(context.Context, int64) (bool, error) {
+	return false, errors.New("deduplication filter unavailable")
+}
+
+func (unavailableDeduplicationFilter) MarkSeen(context.Context, int64) error {
+	return errors.New("deduplication filter unavailable")
+}
+
+func newDeduplicatingReplicationQueue(
+	controller *gomock.Controller,
+	filter DeduplicationFilter,
+	scope tally.Scope,
+) (ReplicationQueue, *persistence.MockQueueManager) {
+
+	mockQueue := persistence.NewMockQueueManager(controller)
+	replicationQueue := NewReplicationQueue(
+		mockQueue,
+		"testCluster",
+		metrics.NewClient(scope, metrics.Frontend),
+		loggerimpl.NewNopLogger(),
+		WithEnqueueDeduplication(filter),
+	)
+	return replicationQueue, mockQueue
+}
+
+func TestBloomDeduplicationFilter(t *testing.T) {
+	ctx := context.Background()
+	filter := NewBloomDeduplicationFilter(10, 0.0001)
+
+	duplicate, err := filter.IsDuplicate(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+	require.NoError(t, filter.MarkSeen(ctx, 1))
+	duplicate, err = filter.IsDuplicate(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+
+	// the task is remembered while it is in one of the two filters of the window
+	for taskID := int64(2); taskID <= 20; taskID++ {
+		require.NoError(t, filter.MarkSeen(ctx, taskID))
+	}
+	duplicate, err = filter.IsDuplicate(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+	require.NoError(t, filter.MarkSeen(ctx, 21))
+	duplicate, err = filter.IsDuplicate(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+}
+
+func TestPublishToDLQ_Deduplication(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scope := tally.NewTestScope("test", nil)
+	replicationQueue, mockQueue := newDeduplicatingReplicationQueue(controller, NewBloomDeduplicationFilter(100, 0.0001), scope)
+	task := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID:         11,
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID"},
+	}
+	// tasks without a source task ID cannot be deduplicated
+	unknownTask := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID"},
+	}
+	mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "domainID", gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	require.NoError(t, replicationQueue.PublishToDLQ(context.Background(), task))
+	require.NoError(t, replicationQueue.PublishToDLQ(context.Background(), task))
+	require.NoError(t, replicationQueue.PublishToDLQ(context.Background(), unknownTask))
+	require.NoError(t, replicationQueue.PublishToDLQ(context.Background(), unknownTask))
+	assert.Equal(t, int64(1), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_enqueue_duplicates")))
+}
+
+func TestPublishToDLQ_Deduplication_EnqueueFailed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	replicationQueue, mockQueue := newDeduplicatingReplicationQueue(controller, NewBloomDeduplicationFilter(100, 0.0001), tally.NoopScope)
+	task := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID:         11,
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID"},
+	}
+	enqueueErr := errors.New("enqueue failed")
+	gomock.InOrder(
+		mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "domainID", gomock.Any(), gomock.Any()).Return(enqueueErr),
+		// a task which failed to be enqueued is not marked as seen, so the retry of the producer enqueues it
+		mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "domainID", gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	assert.Equal(t, enqueueErr, replicationQueue.PublishToDLQ(context.Background(), task))
+	assert.NoError(t, replicationQueue.PublishToDLQ(context.Background(), task))
+}
+
+func TestPublishToDLQ_Deduplication_FilterUnavailable(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scope := tally.NewTestScope("test", nil)
+	replicationQueue, mockQueue := newDeduplicatingReplicationQueue(controller, unavailableDeduplicationFilter{}, scope)
+	task := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID:         11,
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID"},
+	}
+	// duplicates are enqueued rather than tasks being dropped while the filter is unavailable
+	mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "domainID", gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	require.NoError(t, replicationQueue.PublishToDLQ(context.Background(), task))
+	require.NoError(t, replicationQueue.PublishToDLQ(context.Background(), task))
+	assert.Equal(t, int64(4), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_enqueue_deduplication_errors")))
+}
+
+func TestEnqueueBatch_Deduplication(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	replicationQueue, mockQueue := newDeduplicatingReplicationQueue(controller, NewBloomDeduplicationFilter(100, 0.0001), tally.NoopScope)
+	newTask := func(sourceTaskID int64) *types.ReplicationTask {
+		return &types.ReplicationTask{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         sourceTaskID,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID"},
+		}
+	}
+	var enqueued [][]int64
+	mockQueue.EXPECT().EnqueueMessagesToDLQ(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, messages []*persistence.QueueMessage) error {
+			var sourceTaskIDs []int64
+			for _, message := range messages {
+				task, err := DecodeReplicationTask(message.Payload)
+				require.NoError(t, err)
+				sourceTaskIDs = append(sourceTaskIDs, task.SourceTaskID)
+			}
+			enqueued = append(enqueued, sourceTaskIDs)
+			return nil
+		},
+	).Times(2)
+
+	require.NoError(t, replicationQueue.EnqueueBatch(context.Background(), []*types.ReplicationTask{newTask(1), newTask(2), newTask(1)}))
+	require.NoError(t, replicationQueue.EnqueueBatch(context.Background(), []*types.ReplicationTask{newTask(2), newTask(3)}))
+	// a batch of duplicates only is not enqueued
+	require.NoError(t, replicationQueue.EnqueueBatch(context.Background(), []*types.ReplicationTask{newTask(3)}))
+	assert.Equal(t, [][]int64{{1, 2}, {3}}, enqueued)
+}
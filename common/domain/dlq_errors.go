@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/uber/cadence/common/types"
+)
+
+var (
+	// ErrDLQAckLevelNotFound is returned by the DLQ handler when the ack level of the domain DLQ cannot be read
+	ErrDLQAckLevelNotFound = errors.New("domain DLQ ack level not found")
+	// ErrDLQMessageNotFound is returned by the DLQ handler when a domain DLQ message was removed before it could be handled
+	ErrDLQMessageNotFound = errors.New("domain DLQ message not found")
+	// ErrDLQExecutorFailed is returned by the DLQ handler when executing a domain DLQ message fails
+	ErrDLQExecutorFailed = errors.New("failed to execute domain DLQ message")
+	// ErrDLQDeleteFailed is returned by the DLQ handler when handled domain DLQ messages cannot be deleted
+	ErrDLQDeleteFailed = errors.New("failed to delete domain DLQ messages")
+	// ErrDLQQueueFull is returned by the DLQ handler when a queue does not accept the domain DLQ messages enqueued to it
+	ErrDLQQueueFull = errors.New("failed to enqueue domain DLQ messages")
+	// ErrDLQArchivalFailed is returned by the DLQ handler when domain DLQ messages cannot be archived before they are purged
+	ErrDLQArchivalFailed = errors.New("failed to archive domain DLQ messages")
+	// ErrDLQMergePaused is returned by MergeShard while the domain DLQ grows faster than the growth rate threshold
+	ErrDLQMergePaused = errors.New("domain DLQ merges are paused as the DLQ grows faster than the growth rate threshold")
+	// ErrDLQProcessingPaused is returned by MergeShard while the domain DLQ merges are paused by an operator
+	ErrDLQProcessingPaused = errors.New("domain DLQ processing is paused")
+	// ErrDLQSpeculativeRetryFailed is returned by Merge when a message retried speculatively keeps failing and its page is rolled back
+	ErrDLQSpeculativeRetryFailed = errors.New("speculative retry of domain DLQ message failed")
+
+	// errDLQMergeTimedOut tells Merge that a message failed because merging the page timed out
+	errDLQMergeTimedOut = errors.New("domain DLQ merge timed out")
+)
+
+type (
+	// DLQError is returned by the DLQ handler for the failure modes of the domain DLQ.
+	// errors.Is matches it against its Kind, one of the ErrDLQ sentinel errors,
+	// and errors.As reaches the error which caused it.
+	DLQError struct {
+		Kind  error
+		Cause error
+	}
+)
+
+func (e *DLQError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Cause)
+}
+
+// Unwrap returns the error which caused the failure
+func (e *DLQError) Unwrap() error {
+	return e.Cause
+}
+
+// Is returns true if the target is the kind of the failure
+func (e *DLQError) Is(target error) bool {
+	return e.Kind == target
+}
+
+func newDLQError(kind error, cause error) error {
+	return &DLQError{Kind: kind, Cause: cause}
+}
+
+// newDLQDeleteError returns the failure of deleting domain DLQ messages,
+// a message which no longer exists is reported as not found
+func newDLQDeleteError(cause error) error {
+	var notExistsErr *types.EntityNotExistsError
+	if errors.As(cause, &notExistsErr) {
+		return newDLQError(ErrDLQMessageNotFound, cause)
+	}
+	return newDLQError(ErrDLQDeleteFailed, cause)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestDLQError(t *testing.T) {
+	cause := &types.ServiceBusyError{Message: "busy"}
+	err := newDLQError(ErrDLQQueueFull, cause)
+
+	assert.True(t, errors.Is(err, ErrDLQQueueFull))
+	assert.False(t, errors.Is(err, ErrDLQDeleteFailed))
+	assert.True(t, errors.Is(err, cause))
+	var serviceBusyErr *types.ServiceBusyError
+	assert.True(t, errors.As(err, &serviceBusyErr))
+	assert.Equal(t, cause, serviceBusyErr)
+	assert.Equal(t, "failed to enqueue domain DLQ messages: busy", err.Error())
+
+	// the kind is kept when the error is wrapped again
+	wrapped := fmt.Errorf("merge: %w", err)
+	assert.True(t, errors.Is(wrapped, ErrDLQQueueFull))
+	var dlqErr *DLQError
+	assert.True(t, errors.As(wrapped, &dlqErr))
+	assert.Equal(t, ErrDLQQueueFull, dlqErr.Kind)
+}
+
+func TestNewDLQDeleteError(t *testing.T) {
+	err := newDLQDeleteError(fmt.Errorf("test"))
+	assert.True(t, errors.Is(err, ErrDLQDeleteFailed))
+	assert.False(t, errors.Is(err, ErrDLQMessageNotFound))
+
+	err = newDLQDeleteError(&types.EntityNotExistsError{})
+	assert.True(t, errors.Is(err, ErrDLQMessageNotFound))
+	assert.False(t, errors.Is(err, ErrDLQDeleteFailed))
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+
+	sharedv1 "github.com/uber/cadence/.gen/proto/shared/v1"
+	"github.com/uber/cadence/common/types"
+	protomapper "github.com/uber/cadence/common/types/mapper/proto"
+)
+
+// ExportFormat is the serialization format of exported DLQ messages
+type ExportFormat int
+
+const (
+	// ExportFormatJSON serializes each replication task as a JSON object per line
+	ExportFormatJSON ExportFormat = iota
+	// ExportFormatProtoText serializes each replication task as a compact proto text message per line
+	ExportFormatProtoText
+)
+
+type (
+	dlqTaskEncodeFn func(task *types.ReplicationTask) error
+	dlqTaskDecodeFn func() (*types.ReplicationTask, error)
+)
+
+// ParseExportFormat parses the export format from its name
+func ParseExportFormat(format string) (ExportFormat, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return ExportFormatJSON, nil
+	case "prototext":
+		return ExportFormatProtoText, nil
+	default:
+		return 0, fmt.Errorf("unknown export format %q, supported formats are json and prototext", format)
+	}
+}
+
+func newDLQTaskEncoder(w io.Writer, format ExportFormat) (dlqTaskEncodeFn, error) {
+	switch format {
+	case ExportFormatJSON:
+		encoder := json.NewEncoder(w)
+		return func(task *types.ReplicationTask) error {
+			return encoder.Encode(task)
+		}, nil
+	case ExportFormatProtoText:
+		return func(task *types.ReplicationTask) error {
+			_, err := fmt.Fprintln(w, proto.CompactTextString(protomapper.FromReplicationTask(task)))
+			return err
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format: %v", format)
+	}
+}
+
+func newDLQTaskDecoder(r io.Reader, format ExportFormat) (dlqTaskDecodeFn, error) {
+	switch format {
+	case ExportFormatJSON:
+		decoder := json.NewDecoder(r)
+		return func() (*types.ReplicationTask, error) {
+			var task types.ReplicationTask
+			if err := decoder.Decode(&task); err != nil {
+				return nil, err
+			}
+			return &task, nil
+		}, nil
+	case ExportFormatProtoText:
+		reader := bufio.NewReader(r)
+		return func() (*types.ReplicationTask, error) {
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil && err != io.EOF {
+					return nil, err
+				}
+				line = strings.TrimSpace(line)
+				if line == "" {
+					if err == io.EOF {
+						return nil, io.EOF
+					}
+					continue
+				}
+
+				var task sharedv1.ReplicationTask
+				if err := proto.UnmarshalText(line, &task); err != nil {
+					return nil, fmt.Errorf("failed to decode replication task: %v", err)
+				}
+				return protomapper.ToReplicationTask(&task), nil
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format: %v", format)
+	}
+}
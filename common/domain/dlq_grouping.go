@@ -0,0 +1,183 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+// getDLQCorrelationID returns the correlation ID a replication task is enqueued to the DLQ with,
+// the tasks of a workflow run depend on each other and are correlated by the run ID
+func getDLQCorrelationID(task *types.ReplicationTask) string {
+	switch task.GetTaskType() {
+	case types.ReplicationTaskTypeHistoryV2:
+		return task.HistoryTaskV2Attributes.GetRunID()
+	case types.ReplicationTaskTypeSyncActivity:
+		return task.SyncActivityTaskAttributes.GetRunID()
+	default:
+		return ""
+	}
+}
+
+// getDLQGroupKey returns the key of the group of the message, a message without a correlation ID
+// is a group of its own keyed by its message ID, which never collides with a run ID
+func getDLQGroupKey(message *types.ReplicationTask) string {
+	if message.CorrelationID != "" {
+		return message.CorrelationID
+	}
+	return strconv.FormatInt(message.SourceTaskID, 10)
+}
+
+// groupDLQMessages groups the messages by correlation ID, the messages of a group are ordered by message ID
+func groupDLQMessages(messages []*types.ReplicationTask) map[string][]*types.ReplicationTask {
+	sorted := make([]*types.ReplicationTask, len(messages))
+	copy(sorted, messages)
+	sortDLQMessagesByID(sorted)
+
+	groups := make(map[string][]*types.ReplicationTask)
+	for _, message := range sorted {
+		key := getDLQGroupKey(message)
+		groups[key] = append(groups[key], message)
+	}
+	return groups
+}
+
+// sortDLQGroups returns the groups in the order of the first message ID of each group
+func sortDLQGroups(groups map[string][]*types.ReplicationTask) [][]*types.ReplicationTask {
+	sorted := make([][]*types.ReplicationTask, 0, len(groups))
+	for _, group := range groups {
+		if len(group) > 0 {
+			sorted = append(sorted, group)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][0].SourceTaskID < sorted[j][0].SourceTaskID
+	})
+	return sorted
+}
+
+// mergeGroups merges a page of messages of every task type group by group, in the order of the first message of each group.
+// The messages of a group are deleted together once every one of them is merged, so a failed group does not
+// make the groups merged before it execute again. A group split by the end of the page is merged page by page.
+// The messages are executed one at a time, as the messages of a group depend on each other.
+func (d *dlqMessageHandlerImpl) mergeGroups(
+	ctx context.Context,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]byte, error) {
+
+	ctx, traceID := contextWithDLQMergeTraceID(ctx)
+	logger := d.contextLogger(ctx)
+	startTime := time.Now()
+	ackLevel, err := d.replicationQueue.GetDLQAckLevel(ctx, AllTaskTypes, d.consumerGroup)
+	if err != nil {
+		return nil, newDLQError(ErrDLQAckLevelNotFound, err)
+	}
+
+	groups, token, err := d.replicationQueue.GetMessagesFromDLQGrouped(ctx, ackLevel, lastMessageID, pageSize, pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := dlqMergeProgress{
+		taskType:      AllTaskTypes,
+		startAckLevel: ackLevel,
+		ackLevel:      ackLevel,
+		startTime:     startTime,
+		traceID:       traceID,
+	}
+	for _, group := range sortDLQGroups(groups) {
+		if err := d.mergeGroup(ctx, logger, group, &progress); err != nil {
+			return nil, err
+		}
+		d.stateMachine.transition(DLQStateCommitting)
+		for _, message := range group {
+			if err := d.deleteMessage(ctx, message.SourceTaskID); err != nil {
+				logger.WithTags(dlqMessageTags(message)...).Error("failed to delete merged task on merging domain DLQ group",
+					tag.DLQCorrelationID(message.CorrelationID),
+					tag.Error(err))
+				return nil, newDLQDeleteError(err)
+			}
+			progress.processed(message)
+		}
+	}
+
+	// the messages are deleted already, completing the merge moves the ack level past the page and records the merge
+	if err := d.completeMerge(ctx, logger, &progress); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// mergeGroup executes the messages of the group in order, the messages after a failed one are not executed.
+// If the failed message is dropped the messages after it depend on it, so they are dropped with it,
+// otherwise the merge fails and every message of the group stays in the DLQ.
+func (d *dlqMessageHandlerImpl) mergeGroup(
+	ctx context.Context,
+	logger log.Logger,
+	group []*types.ReplicationTask,
+	progress *dlqMergeProgress,
+) error {
+
+	for i, message := range group {
+		if d.domainFilter != nil && !d.domainFilter(getReplicationTaskDomainID(message)) {
+			logger.Info("Skipping domain DLQ message of a filtered domain", dlqMessageTags(message)...)
+			d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQFilteredMessageCount)
+			continue
+		}
+		if d.deduplicator.probablySeen(message) {
+			logger.Warn("Skipping duplicate domain DLQ message", dlqMessageTags(message)...)
+			d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQDuplicateSkippedCount)
+			continue
+		}
+		if err := d.mergeRateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		err := d.executeJournaled(ctx, message)
+		if err == nil {
+			progress.mergedCount++
+			d.deduplicator.add(message)
+			d.emitDLQMessageAge(message)
+			continue
+		}
+		if !d.skipInvalidMessage(ctx, message, err) && !d.skipPoisonedMessage(ctx, message, err) {
+			return newDLQError(ErrDLQExecutorFailed, err)
+		}
+		progress.failedCount += int64(len(group) - i)
+		if dropped := len(group) - i - 1; dropped > 0 {
+			logger.WithTags(dlqMessageTags(message)...).Warn("Dropping the domain DLQ messages correlated with a dropped message",
+				tag.DLQCorrelationID(message.CorrelationID),
+				tag.Counter(dropped))
+		}
+		return nil
+	}
+	return nil
+}
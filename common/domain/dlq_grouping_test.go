@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func dlqMessageIDs(messages []*types.ReplicationTask) []int64 {
+	ids := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.SourceTaskID)
+	}
+	return ids
+}
+
+func TestGetDLQCorrelationID(t *testing.T) {
+	assert.Equal(t, "run-id", getDLQCorrelationID(&types.ReplicationTask{
+		TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+		HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{RunID: "run-id"},
+	}))
+	assert.Equal(t, "run-id", getDLQCorrelationID(&types.ReplicationTask{
+		TaskType:                   types.ReplicationTaskTypeSyncActivity.Ptr(),
+		SyncActivityTaskAttributes: &types.SyncActivityTaskAttributes{RunID: "run-id"},
+	}))
+	// the tasks without a workflow run are not correlated
+	assert.Empty(t, getDLQCorrelationID(&types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domain-id"},
+	}))
+	assert.Empty(t, getDLQCorrelationID(&types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr()}))
+}
+
+func TestGroupDLQMessages(t *testing.T) {
+	messages := []*types.ReplicationTask{
+		{SourceTaskID: 5, CorrelationID: "run-a"},
+		{SourceTaskID: 2, CorrelationID: "run-b"},
+		{SourceTaskID: 1, CorrelationID: "run-a"},
+		{SourceTaskID: 3},
+		{SourceTaskID: 4},
+	}
+
+	groups := groupDLQMessages(messages)
+	assert.Len(t, groups, 4)
+	assert.Equal(t, []int64{1, 5}, dlqMessageIDs(groups["run-a"]))
+	assert.Equal(t, []int64{2}, dlqMessageIDs(groups["run-b"]))
+	assert.Equal(t, []int64{3}, dlqMessageIDs(groups["3"]))
+	assert.Equal(t, []int64{4}, dlqMessageIDs(groups["4"]))
+	// the page itself is not reordered
+	assert.Equal(t, []int64{5, 2, 1, 3, 4}, dlqMessageIDs(messages))
+
+	sorted := sortDLQGroups(groups)
+	firstMessageIDs := make([]int64, 0, len(sorted))
+	for _, group := range sorted {
+		firstMessageIDs = append(firstMessageIDs, group[0].SourceTaskID)
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4}, firstMessageIDs)
+}
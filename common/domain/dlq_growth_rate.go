@@ -0,0 +1,180 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+)
+
+const (
+	// dlqGrowthRateWindowSize is the number of sampled intervals the moving average of the growth rate is computed over
+	dlqGrowthRateWindowSize = 5
+)
+
+type (
+	dlqDepthSample struct {
+		depth     int64
+		timestamp time.Time
+	}
+
+	// dlqGrowthRateMonitor computes the moving average of the growth rate of the DLQ depth over its last samples
+	// and pauses the merges of the DLQ shards while the growth rate exceeds the threshold
+	dlqGrowthRateMonitor struct {
+		sync.Mutex
+		windowSize int
+		samples    []dlqDepthSample
+		paused     bool
+	}
+)
+
+func newDLQGrowthRateMonitor(windowSize int) *dlqGrowthRateMonitor {
+	return &dlqGrowthRateMonitor{
+		windowSize: windowSize,
+		samples:    make([]dlqDepthSample, 0, windowSize+1),
+	}
+}
+
+// sample records the depth of the DLQ and returns its growth rate in messages per second averaged over the window,
+// ok is false until the window holds two samples which were not taken at the same time
+func (m *dlqGrowthRateMonitor) sample(now time.Time, depth int64) (rate float64, ok bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(m.samples) > m.windowSize {
+		copy(m.samples, m.samples[1:])
+		m.samples = m.samples[:len(m.samples)-1]
+	}
+	m.samples = append(m.samples, dlqDepthSample{depth: depth, timestamp: now})
+
+	first, last := m.samples[0], m.samples[len(m.samples)-1]
+	elapsed := last.timestamp.Sub(first.timestamp).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return float64(last.depth-first.depth) / elapsed, true
+}
+
+// update pauses the merges while the growth rate exceeds the threshold and resumes them once it falls back below,
+// changed tells whether the merges were paused or resumed by the update
+func (m *dlqGrowthRateMonitor) update(rate float64, maxRate float64) (paused bool, changed bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	paused = rate > maxRate
+	changed = paused != m.paused
+	m.paused = paused
+	return paused, changed
+}
+
+// reset drops the samples and resumes the merges, it returns whether the merges were paused
+func (m *dlqGrowthRateMonitor) reset() bool {
+	m.Lock()
+	defer m.Unlock()
+
+	paused := m.paused
+	m.samples = m.samples[:0]
+	m.paused = false
+	return paused
+}
+
+func (m *dlqGrowthRateMonitor) isPaused() bool {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.paused
+}
+
+// growthRateMonitorLoop samples the depth of the DLQ at the sample interval
+func (d *dlqMessageHandlerImpl) growthRateMonitorLoop() {
+	defer d.shutdownWG.Done()
+
+	timer := time.NewTimer(d.growthRateInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-timer.C:
+			if err := d.checkGrowthRate(context.Background()); err != nil {
+				d.logger.Warn("Failed to sample domain DLQ depth.", tag.Error(err))
+			}
+			timer.Reset(d.growthRateInterval())
+		}
+	}
+}
+
+// checkGrowthRate samples the depth of the DLQ, pausing the merges of the DLQ shards while the moving average
+// of its growth rate exceeds the max growth rate and resuming them once it falls back below.
+// The hooks are notified whenever the merges are paused or resumed.
+func (d *dlqMessageHandlerImpl) checkGrowthRate(ctx context.Context) error {
+	maxRate := d.maxGrowthRate()
+	if maxRate <= 0 {
+		if d.growthRateMonitor.reset() {
+			d.logger.Info("Resuming domain DLQ merges as the growth rate threshold was disabled.")
+		}
+		return nil
+	}
+
+	depth, err := d.replicationQueue.GetDLQSize(ctx, AllTaskTypes)
+	if err != nil {
+		return err
+	}
+	rate, ok := d.growthRateMonitor.sample(d.timeSource.Now(), depth)
+	if !ok {
+		return nil
+	}
+	scope := d.metricsClient.Scope(metrics.DomainReplicationQueueScope)
+	scope.UpdateGauge(metrics.DomainReplicationDLQGrowthRate, rate)
+
+	paused, changed := d.growthRateMonitor.update(rate, maxRate)
+	if !changed {
+		return nil
+	}
+	event := DLQEvent{
+		Type:          DLQEventGrowthRateRecovered,
+		AckLevel:      atomic.LoadInt64(&d.ackLevel),
+		Depth:         depth,
+		GrowthRate:    rate,
+		MaxGrowthRate: maxRate,
+	}
+	if paused {
+		scope.IncCounter(metrics.DomainReplicationDLQGrowthRateExceeded)
+		d.logger.Warn("Pausing domain DLQ merges as the DLQ grows faster than the growth rate threshold.",
+			tag.DLQGrowthRate(rate),
+			tag.DLQMaxGrowthRate(maxRate),
+		)
+		event.Type = DLQEventGrowthRateExceeded
+	} else {
+		d.logger.Info("Resuming domain DLQ merges as the growth rate fell back below the threshold.",
+			tag.DLQGrowthRate(rate),
+			tag.DLQMaxGrowthRate(maxRate),
+		)
+	}
+	d.notify(ctx, event)
+	return nil
+}
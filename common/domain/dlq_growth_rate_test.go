@@ -0,0 +1,152 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/metrics"
+)
+
+func TestDLQGrowthRateMonitor_MovingAverage(t *testing.T) {
+	monitor := newDLQGrowthRateMonitor(2)
+	now := time.Unix(1600000000, 0)
+
+	_, ok := monitor.sample(now, 100)
+	assert.False(t, ok)
+	_, ok = monitor.sample(now, 110)
+	assert.False(t, ok)
+
+	rate, ok := monitor.sample(now.Add(10*time.Second), 200)
+	require.True(t, ok)
+	assert.Equal(t, float64(10), rate)
+	// the first sample falls out of the window of two intervals
+	rate, ok = monitor.sample(now.Add(20*time.Second), 210)
+	require.True(t, ok)
+	assert.Equal(t, float64(5), rate)
+	rate, ok = monitor.sample(now.Add(30*time.Second), 10)
+	require.True(t, ok)
+	assert.Equal(t, -9.5, rate)
+}
+
+func TestDLQGrowthRateMonitor_PauseAndResume(t *testing.T) {
+	monitor := newDLQGrowthRateMonitor(dlqGrowthRateWindowSize)
+
+	paused, changed := monitor.update(1, 2)
+	assert.False(t, paused)
+	assert.False(t, changed)
+	paused, changed = monitor.update(3, 2)
+	assert.True(t, paused)
+	assert.True(t, changed)
+	paused, changed = monitor.update(4, 2)
+	assert.True(t, paused)
+	assert.False(t, changed)
+	assert.True(t, monitor.isPaused())
+	paused, changed = monitor.update(2, 2)
+	assert.False(t, paused)
+	assert.True(t, changed)
+
+	monitor.update(3, 2)
+	assert.True(t, monitor.reset())
+	assert.False(t, monitor.isPaused())
+	assert.False(t, monitor.reset())
+}
+
+func TestDLQGrowthRate_PausesAndResumesMerges(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	recorder, server := newDLQEventRecorder(t)
+	defer server.Close()
+
+	now := time.Unix(1600000000, 0).UTC()
+	timeSource := clock.NewEventTimeSource().Update(now)
+	scope := tally.NewTestScope("test", nil)
+	dlqHandler, _, mockReplicationQueue := newNotifyingDLQMessageHandler(
+		controller,
+		server.URL,
+		WithGrowthRateMonitor(dynamicconfig.GetDurationPropertyFn(10*time.Second), dynamicconfig.GetFloatPropertyFn(1)),
+		WithTimeSource(timeSource),
+		WithMetricsClient(metrics.NewClient(scope, metrics.Frontend)),
+	)
+	gomock.InOrder(
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(100), nil),
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(200), nil),
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(200), nil),
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(100), nil),
+	)
+
+	require.NoError(t, dlqHandler.checkGrowthRate(context.Background()))
+	assert.Empty(t, recorder.recorded())
+
+	timeSource.Update(now.Add(10 * time.Second))
+	require.NoError(t, dlqHandler.checkGrowthRate(context.Background()))
+	assert.Equal(t, ErrDLQMergePaused, dlqHandler.MergeShard(context.Background(), 0, 1))
+	assert.Equal(t, int64(1), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_growth_rate_exceeded")))
+	assert.Equal(t, float64(10), scope.Snapshot().Gauges()["test.dlq_growth_rate+operation=DomainReplicationQueue"].Value())
+
+	// the moving average keeps the merges paused until the DLQ drained the messages it grew by
+	timeSource.Update(now.Add(20 * time.Second))
+	require.NoError(t, dlqHandler.checkGrowthRate(context.Background()))
+	assert.True(t, dlqHandler.growthRateMonitor.isPaused())
+
+	timeSource.Update(now.Add(30 * time.Second))
+	require.NoError(t, dlqHandler.checkGrowthRate(context.Background()))
+	assert.False(t, dlqHandler.growthRateMonitor.isPaused())
+	assert.Equal(t, int64(1), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_growth_rate_exceeded")))
+	assert.Equal(t, []DLQEvent{
+		{
+			Type:          DLQEventGrowthRateExceeded,
+			Timestamp:     now.Add(10 * time.Second),
+			AckLevel:      -1,
+			Depth:         200,
+			GrowthRate:    10,
+			MaxGrowthRate: 1,
+		},
+		{
+			Type:          DLQEventGrowthRateRecovered,
+			Timestamp:     now.Add(30 * time.Second),
+			AckLevel:      -1,
+			Depth:         100,
+			MaxGrowthRate: 1,
+		},
+	}, recorder.recorded())
+}
+
+func TestDLQGrowthRate_Disabled(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	dlqHandler, _, _ := newNotifyingDLQMessageHandler(controller, "")
+	dlqHandler.growthRateMonitor.update(10, 1)
+
+	// the depth is not sampled and the merges resume
+	require.NoError(t, dlqHandler.checkGrowthRate(context.Background()))
+	assert.False(t, dlqHandler.growthRateMonitor.isPaused())
+}
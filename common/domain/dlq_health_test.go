@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestDLQHealthHandler(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReplicationTaskExecutor := NewMockReplicationTaskExecutor(controller)
+	mockReplicationQueue := NewMockReplicationQueue(controller)
+	dlqHandler := NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(mockReplicationTaskExecutor),
+		mockReplicationQueue,
+		loggerimpl.NewNopLogger(),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithPurgeBatchDelay(dynamicconfig.GetDurationPropertyFn(0)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+	)
+	server := httptest.NewServer(NewDLQHealthHandler(dlqHandler))
+	defer server.Close()
+
+	// the handler is not ready before its first poll
+	health := getDLQHealth(t, server.URL, http.StatusServiceUnavailable)
+	assert.False(t, health.Ready)
+	assert.True(t, health.LastMergeTime.IsZero())
+	assert.Equal(t, int64(0), health.LastMergeCount)
+	assert.Equal(t, int64(-1), health.CurrentDLQDepth)
+	assert.Equal(t, int64(-1), health.AckLevel)
+	assert.Equal(t, DLQCircuitBreakerStateClosed, health.CircuitBreakerState)
+
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(5), nil).Times(1)
+	mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(20), 100, nil).
+		Return(tasks, nil, nil).Times(1)
+	mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := dlqHandler.Count(context.Background(), true)
+	require.NoError(t, err)
+	beforeMerge := time.Now()
+	_, err = dlqHandler.Merge(context.Background(), AllTaskTypes, "", 20, 100, nil)
+	require.NoError(t, err)
+
+	health = getDLQHealth(t, server.URL, http.StatusOK)
+	assert.True(t, health.Ready)
+	assert.False(t, health.LastMergeTime.Before(beforeMerge))
+	assert.Equal(t, int64(2), health.LastMergeCount)
+	assert.Equal(t, int64(5), health.CurrentDLQDepth)
+	assert.Equal(t, int64(12), health.AckLevel)
+}
+
+func TestDLQHealthHandler_MethodNotAllowed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := httptest.NewServer(NewDLQHealthHandler(NewMockDLQMessageHandler(controller)))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestDLQHealthHandler_Readiness(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReplicationQueue := NewMockReplicationQueue(controller)
+	dlqHandler := NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(NewMockReplicationTaskExecutor(controller)),
+		mockReplicationQueue,
+		loggerimpl.NewNopLogger(),
+	)
+	server := httptest.NewServer(NewDLQHealthHandler(dlqHandler))
+	defer server.Close()
+
+	gomock.InOrder(
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), nil),
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), &persistence.TimeoutError{Msg: "timeout"}),
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), errors.New("persistent error")),
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(0), nil),
+	)
+
+	assert.False(t, dlqHandler.Ready())
+	getDLQHealth(t, server.URL, http.StatusServiceUnavailable)
+
+	_, err := dlqHandler.Count(context.Background(), true)
+	require.NoError(t, err)
+	assert.True(t, dlqHandler.Ready())
+	getDLQHealth(t, server.URL, http.StatusOK)
+
+	// a transient error keeps the handler ready
+	_, err = dlqHandler.Count(context.Background(), true)
+	require.Error(t, err)
+	assert.True(t, dlqHandler.Ready())
+	getDLQHealth(t, server.URL, http.StatusOK)
+
+	_, err = dlqHandler.Count(context.Background(), true)
+	require.Error(t, err)
+	assert.False(t, dlqHandler.Ready())
+	getDLQHealth(t, server.URL, http.StatusServiceUnavailable)
+
+	// the next successful poll makes it ready again
+	_, err = dlqHandler.Count(context.Background(), true)
+	require.NoError(t, err)
+	assert.True(t, dlqHandler.Ready())
+	getDLQHealth(t, server.URL, http.StatusOK)
+}
+
+func getDLQHealth(t *testing.T, url string, expectedStatusCode int) DLQHealth {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, expectedStatusCode, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var health DLQHealth
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	return health
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+const dlqJournalRecoveryTimeout = 10 * time.Second
+
+// dlqExecutionJournal is the write-ahead log of the messages executed by the merges of the handler. A message is
+// journaled as executing before it is executed and as applied once it succeeded, so a merge restarted after a
+// crash between the execution and the deletion of a message skips it instead of applying it twice.
+type dlqExecutionJournal struct {
+	sync.Mutex
+	queue         ReplicationQueue
+	consumerGroup string
+	recovered     bool
+	executing     map[int64]struct{}
+	applied       map[int64]struct{}
+}
+
+func newDLQExecutionJournal(queue ReplicationQueue, consumerGroup string) *dlqExecutionJournal {
+	return &dlqExecutionJournal{
+		queue:         queue,
+		consumerGroup: consumerGroup,
+		executing:     make(map[int64]struct{}),
+		applied:       make(map[int64]struct{}),
+	}
+}
+
+// recover loads the journal persisted before the handler restarted, only the first call reads it,
+// and returns the messages whose execution was interrupted
+func (j *dlqExecutionJournal) recover(ctx context.Context) ([]int64, error) {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.recovered {
+		return nil, nil
+	}
+	journal, err := j.queue.GetDLQExecutionJournal(ctx, j.consumerGroup)
+	if err != nil {
+		return nil, err
+	}
+	for _, messageID := range journal.Applied {
+		j.applied[messageID] = struct{}{}
+	}
+	// an interrupted message may or may not be applied, it is executed again by the next merge
+	j.recovered = true
+	return journal.Executing, nil
+}
+
+func (j *dlqExecutionJournal) isApplied(messageID int64) bool {
+	j.Lock()
+	defer j.Unlock()
+
+	_, ok := j.applied[messageID]
+	return ok
+}
+
+// begin journals the message as executing, it must be persisted before the message is executed
+func (j *dlqExecutionJournal) begin(ctx context.Context, messageID int64) error {
+	j.Lock()
+	defer j.Unlock()
+
+	j.executing[messageID] = struct{}{}
+	return j.persistLocked(ctx)
+}
+
+// end journals the message as no longer executing, and as applied if its execution succeeded
+func (j *dlqExecutionJournal) end(ctx context.Context, messageID int64, applied bool) error {
+	j.Lock()
+	defer j.Unlock()
+
+	delete(j.executing, messageID)
+	if applied {
+		j.applied[messageID] = struct{}{}
+	}
+	return j.persistLocked(ctx)
+}
+
+// forget drops the deleted messages up to lastMessageID inclusive, the persisted journal drops them on its next update
+func (j *dlqExecutionJournal) forget(firstMessageID int64, lastMessageID int64) {
+	j.Lock()
+	defer j.Unlock()
+
+	for messageID := range j.applied {
+		if messageID > firstMessageID && messageID <= lastMessageID {
+			delete(j.applied, messageID)
+		}
+	}
+}
+
+func (j *dlqExecutionJournal) persistLocked(ctx context.Context) error {
+	return j.queue.UpdateDLQExecutionJournal(ctx, j.consumerGroup, &DLQExecutionJournal{
+		Executing: sortedMessageIDs(j.executing),
+		Applied:   sortedMessageIDs(j.applied),
+	})
+}
+
+func sortedMessageIDs(messageIDs map[int64]struct{}) []int64 {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+	sorted := make([]int64, 0, len(messageIDs))
+	for messageID := range messageIDs {
+		sorted = append(sorted, messageID)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// recoverJournal replays the execution journal persisted before the handler restarted
+func (d *dlqMessageHandlerImpl) recoverJournal(ctx context.Context) error {
+	interrupted, err := d.journal.recover(ctx)
+	if err != nil {
+		return err
+	}
+	for _, messageID := range interrupted {
+		d.logger.Warn("Domain DLQ message was interrupted while executing, the next merge executes it again.",
+			tag.TaskID(messageID))
+	}
+	return nil
+}
+
+// executeJournaled executes the message with backpressure, journaling the execution if the journal is enabled.
+// A message the journal has as applied was executed before a restart and is skipped.
+func (d *dlqMessageHandlerImpl) executeJournaled(ctx context.Context, message *types.ReplicationTask) error {
+	d.stateMachine.transition(DLQStateExecuting)
+	if !d.journalEnabled() {
+		return d.executeWithBackpressure(ctx, message)
+	}
+	if err := d.recoverJournal(ctx); err != nil {
+		return err
+	}
+
+	messageID := message.SourceTaskID
+	if d.journal.isApplied(messageID) {
+		d.contextLogger(ctx).WithTags(dlqMessageTags(message)...).Info("Skipping domain DLQ message applied before a restart.")
+		d.dlqMessageScope(message).IncCounter(metrics.DomainReplicationDLQDuplicateSkippedCount)
+		return nil
+	}
+	if err := d.journal.begin(ctx, messageID); err != nil {
+		return err
+	}
+	err := d.executeWithBackpressure(ctx, message)
+	if endErr := d.journal.end(ctx, messageID, err == nil); endErr != nil {
+		// the message stays journaled as executing, which only makes it executed again after a restart
+		d.contextLogger(ctx).WithTags(dlqMessageTags(message)...).Warn("Failed to journal the end of a domain DLQ message execution.",
+			tag.Error(endErr))
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination dlq_lock_mock.go
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// dlqLockConsumerGroupPrefix keeps the locks apart from the ack levels of the consumer groups of the DLQ
+	dlqLockConsumerGroupPrefix = "lock-"
+	// dlqMergeLockTTL is how long a merge round holds its lock without extending it
+	dlqMergeLockTTL = time.Minute
+)
+
+var (
+	// ErrLockHeld is returned by AcquireLock when another host holds the lock
+	ErrLockHeld = &types.ServiceBusyError{Message: "lock is held by another host"}
+	// ErrLockLost is returned by a LockHandle when the lock expired and was acquired by another host
+	ErrLockLost = errors.New("lock was lost to another host")
+)
+
+type (
+	// DistributedLocker acquires locks shared by all hosts of the cluster
+	DistributedLocker interface {
+		AcquireLock(ctx context.Context, key string, ttl time.Duration) (LockHandle, error)
+	}
+
+	// LockHandle is a lock held until it expires or is released
+	LockHandle interface {
+		// Extend holds the lock for another TTL
+		Extend(ctx context.Context) error
+		// Release releases the lock so that other hosts can acquire it before it expires
+		Release(ctx context.Context) error
+	}
+
+	// dlqAckLevelLocker stores the expiry of each lock as an ack level of the DLQ, so that locks are acquired
+	// and extended with the compare and swap of the DLQ ack levels, a lightweight transaction on Cassandra
+	dlqAckLevelLocker struct {
+		queue      ReplicationQueue
+		timeSource clock.TimeSource
+	}
+
+	dlqAckLevelLockHandle struct {
+		locker        *dlqAckLevelLocker
+		consumerGroup string
+		ttl           time.Duration
+		expiry        int64
+	}
+)
+
+// NewDistributedLocker returns a DistributedLocker which keeps its locks in the metadata of the domain replication DLQ.
+// Locks expire by the clock of the host which acquired them, so the TTL has to be well above the clock skew between hosts.
+func NewDistributedLocker(
+	queue ReplicationQueue,
+	timeSource clock.TimeSource,
+) DistributedLocker {
+	return &dlqAckLevelLocker{
+		queue:      queue,
+		timeSource: timeSource,
+	}
+}
+
+// AcquireLock acquires the lock with the key for the TTL, it fails with ErrLockHeld if another host holds the lock
+func (l *dlqAckLevelLocker) AcquireLock(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+) (LockHandle, error) {
+
+	consumerGroup := dlqLockConsumerGroupPrefix + key
+	expiry, err := l.queue.GetDLQAckLevel(ctx, AllTaskTypes, consumerGroup)
+	if err != nil {
+		return nil, err
+	}
+	now := l.timeSource.Now().UnixNano()
+	if expiry > now {
+		return nil, ErrLockHeld
+	}
+
+	handle := &dlqAckLevelLockHandle{
+		locker:        l,
+		consumerGroup: consumerGroup,
+		ttl:           ttl,
+		expiry:        now + int64(ttl),
+	}
+	if err := l.queue.CompareAndSwapDLQAckLevel(ctx, AllTaskTypes, consumerGroup, expiry, handle.expiry); err != nil {
+		if err == ErrDLQAckLevelConflict {
+			return nil, ErrLockHeld
+		}
+		return nil, err
+	}
+	return handle, nil
+}
+
+// Extend holds the lock for another TTL, it fails with ErrLockLost if another host acquired the lock
+func (h *dlqAckLevelLockHandle) Extend(ctx context.Context) error {
+	expiry := h.locker.timeSource.Now().UnixNano() + int64(h.ttl)
+	if err := h.swapExpiry(ctx, expiry); err != nil {
+		return err
+	}
+	h.expiry = expiry
+	return nil
+}
+
+// Release expires the lock, it fails with ErrLockLost if another host acquired the lock
+func (h *dlqAckLevelLockHandle) Release(ctx context.Context) error {
+	return h.swapExpiry(ctx, 0)
+}
+
+func (h *dlqAckLevelLockHandle) swapExpiry(ctx context.Context, expiry int64) error {
+	err := h.locker.queue.CompareAndSwapDLQAckLevel(ctx, AllTaskTypes, h.consumerGroup, h.expiry, expiry)
+	if err == ErrDLQAckLevelConflict {
+		return ErrLockLost
+	}
+	return err
+}
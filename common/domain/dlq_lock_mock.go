@@ -0,0 +1,124 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dlq_lock.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDistributedLocker is a mock of DistributedLocker interface.
+type MockDistributedLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockDistributedLockerMockRecorder
+}
+
+// MockDistributedLockerMockRecorder is the mock recorder for MockDistributedLocker.
+type MockDistributedLockerMockRecorder struct {
+	mock *MockDistributedLocker
+}
+
+// NewMockDistributedLocker creates a new mock instance.
+func NewMockDistributedLocker(ctrl *gomock.Controller) *MockDistributedLocker {
+	mock := &MockDistributedLocker{ctrl: ctrl}
+	mock.recorder = &MockDistributedLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDistributedLocker) EXPECT() *MockDistributedLockerMockRecorder {
+	return m.recorder
+}
+
+// AcquireLock mocks base method.
+func (m *MockDistributedLocker) AcquireLock(ctx context.Context, key string, ttl time.Duration) (LockHandle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireLock", ctx, key, ttl)
+	ret0, _ := ret[0].(LockHandle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireLock indicates an expected call of AcquireLock.
+func (mr *MockDistributedLockerMockRecorder) AcquireLock(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLock", reflect.TypeOf((*MockDistributedLocker)(nil).AcquireLock), ctx, key, ttl)
+}
+
+// MockLockHandle is a mock of LockHandle interface.
+type MockLockHandle struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockHandleMockRecorder
+}
+
+// MockLockHandleMockRecorder is the mock recorder for MockLockHandle.
+type MockLockHandleMockRecorder struct {
+	mock *MockLockHandle
+}
+
+// NewMockLockHandle creates a new mock instance.
+func NewMockLockHandle(ctrl *gomock.Controller) *MockLockHandle {
+	mock := &MockLockHandle{ctrl: ctrl}
+	mock.recorder = &MockLockHandleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockHandle) EXPECT() *MockLockHandleMockRecorder {
+	return m.recorder
+}
+
+// Extend mocks base method.
+func (m *MockLockHandle) Extend(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Extend", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Extend indicates an expected call of Extend.
+func (mr *MockLockHandleMockRecorder) Extend(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Extend", reflect.TypeOf((*MockLockHandle)(nil).Extend), ctx)
+}
+
+// Release mocks base method.
+func (m *MockLockHandle) Release(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockLockHandleMockRecorder) Release(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLockHandle)(nil).Release), ctx)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+)
+
+func TestDistributedLocker_AcquireLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	queue := NewMockReplicationQueue(ctrl)
+	now := time.Unix(1000, 0)
+	locker := NewDistributedLocker(queue, clock.NewEventTimeSource().Update(now))
+	expiry := now.Add(time.Minute).UnixNano()
+
+	// a lock which was never acquired is free
+	queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key").Return(int64(common.EmptyMessageID), nil)
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key", int64(common.EmptyMessageID), expiry).Return(nil)
+	lock, err := locker.AcquireLock(context.Background(), "key", time.Minute)
+	require.NoError(t, err)
+
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key", expiry, int64(0)).Return(nil)
+	assert.NoError(t, lock.Release(context.Background()))
+
+	// an expired lock is free
+	expired := now.Add(-time.Second).UnixNano()
+	queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key").Return(expired, nil)
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key", expired, expiry).Return(nil)
+	_, err = locker.AcquireLock(context.Background(), "key", time.Minute)
+	assert.NoError(t, err)
+}
+
+func TestDistributedLocker_AcquireLock_Held(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	queue := NewMockReplicationQueue(ctrl)
+	now := time.Unix(1000, 0)
+	locker := NewDistributedLocker(queue, clock.NewEventTimeSource().Update(now))
+
+	queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key").Return(now.Add(time.Second).UnixNano(), nil)
+	_, err := locker.AcquireLock(context.Background(), "key", time.Minute)
+	assert.Equal(t, ErrLockHeld, err)
+
+	// another host acquired the lock between the read and the swap
+	queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key").Return(int64(0), nil)
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key", int64(0), gomock.Any()).Return(ErrDLQAckLevelConflict)
+	_, err = locker.AcquireLock(context.Background(), "key", time.Minute)
+	assert.Equal(t, ErrLockHeld, err)
+
+	testError := errors.New("test")
+	queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key").Return(int64(0), testError)
+	_, err = locker.AcquireLock(context.Background(), "key", time.Minute)
+	assert.Equal(t, testError, err)
+}
+
+func TestDistributedLocker_Extend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	queue := NewMockReplicationQueue(ctrl)
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1000, 0))
+	locker := NewDistributedLocker(queue, timeSource)
+
+	queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key").Return(int64(0), nil)
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key", int64(0), time.Unix(1060, 0).UnixNano()).Return(nil)
+	lock, err := locker.AcquireLock(context.Background(), "key", time.Minute)
+	require.NoError(t, err)
+
+	timeSource.Update(time.Unix(1030, 0))
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key", time.Unix(1060, 0).UnixNano(), time.Unix(1090, 0).UnixNano()).Return(nil)
+	assert.NoError(t, lock.Extend(context.Background()))
+
+	// the lock expired and was acquired by another host
+	timeSource.Update(time.Unix(1200, 0))
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key", time.Unix(1090, 0).UnixNano(), time.Unix(1260, 0).UnixNano()).Return(ErrDLQAckLevelConflict)
+	assert.Equal(t, ErrLockLost, lock.Extend(context.Background()))
+	queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, "lock-key", time.Unix(1090, 0).UnixNano(), int64(0)).Return(ErrDLQAckLevelConflict)
+	assert.Equal(t, ErrLockLost, lock.Release(context.Background()))
+}
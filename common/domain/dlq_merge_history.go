@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	// DLQMergeHistoryPath is the path the DLQ merge history handler is served on by the debug HTTP server
+	DLQMergeHistoryPath = "/debug/dlq/merge-history"
+
+	defaultDLQMergeHistoryLimit = 100
+	unknownDLQMergeTrigger      = "unknown"
+)
+
+type (
+	dlqMergeTriggerKey struct{}
+	dlqMergeTraceIDKey struct{}
+
+	dlqMergeHistoryHandler struct {
+		dlqHandler DLQMessageHandler
+	}
+)
+
+// ContextWithDLQMergeTrigger returns a copy of the context which attributes the DLQ merges made with it to triggeredBy
+func ContextWithDLQMergeTrigger(ctx context.Context, triggeredBy string) context.Context {
+	return context.WithValue(ctx, dlqMergeTriggerKey{}, triggeredBy)
+}
+
+func dlqMergeTriggerFromContext(ctx context.Context) string {
+	if triggeredBy, ok := ctx.Value(dlqMergeTriggerKey{}).(string); ok && triggeredBy != "" {
+		return triggeredBy
+	}
+	return unknownDLQMergeTrigger
+}
+
+// ContextWithDLQMergeTraceID returns a copy of the context which correlates the DLQ merges made with it
+// with the trace traceID, e.g. the trace of the request which triggered the merges
+func ContextWithDLQMergeTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, dlqMergeTraceIDKey{}, traceID)
+}
+
+func dlqMergeTraceIDFromContext(ctx context.Context) string {
+	if traceID, ok := ctx.Value(dlqMergeTraceIDKey{}).(string); ok {
+		return traceID
+	}
+	return ""
+}
+
+// contextWithDLQMergeTraceID returns a context carrying the trace ID of the merge,
+// a new trace ID is generated if the context does not carry one yet
+func contextWithDLQMergeTraceID(ctx context.Context) (context.Context, string) {
+	if traceID := dlqMergeTraceIDFromContext(ctx); traceID != "" {
+		return ctx, traceID
+	}
+	traceID := uuid.New()
+	return ContextWithDLQMergeTraceID(ctx, traceID), traceID
+}
+
+// NewDLQMergeHistoryHandler returns an HTTP handler which reports the most recent domain DLQ merges as JSON.
+// The number of records is set by the optional limit query parameter.
+func NewDLQMergeHistoryHandler(dlqHandler DLQMessageHandler) http.Handler {
+	return &dlqMergeHistoryHandler{
+		dlqHandler: dlqHandler,
+	}
+}
+
+func (h *dlqMergeHistoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := defaultDLQMergeHistoryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "invalid limit: "+value, http.StatusBadRequest)
+			return
+		}
+	}
+
+	history, err := h.dlqHandler.MergeHistory(r.Context(), limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDLQMergeHistoryHandler(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	history := []DLQMergeRecord{
+		{
+			MergedAt:       time.Unix(100, 0).UTC(),
+			StartMessageID: 11,
+			EndMessageID:   20,
+			MergedCount:    9,
+			FailedCount:    1,
+			Duration:       time.Second,
+			TriggeredBy:    "cadence-cli",
+			TraceID:        "trace-id",
+		},
+	}
+	dlqHandler := NewMockDLQMessageHandler(controller)
+	dlqHandler.EXPECT().MergeHistory(gomock.Any(), defaultDLQMergeHistoryLimit).Return(history, nil).Times(1)
+	dlqHandler.EXPECT().MergeHistory(gomock.Any(), 5).Return(history, nil).Times(1)
+
+	server := httptest.NewServer(NewDLQMergeHistoryHandler(dlqHandler))
+	defer server.Close()
+
+	for _, url := range []string{server.URL, server.URL + "?limit=5"} {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var result []DLQMergeRecord
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		resp.Body.Close()
+		assert.Equal(t, history, result)
+	}
+}
+
+func TestDLQMergeHistoryHandler_Errors(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	dlqHandler := NewMockDLQMessageHandler(controller)
+	dlqHandler.EXPECT().MergeHistory(gomock.Any(), defaultDLQMergeHistoryLimit).Return(nil, fmt.Errorf("test")).Times(1)
+
+	server := httptest.NewServer(NewDLQMergeHistoryHandler(dlqHandler))
+	defer server.Close()
+
+	tests := []struct {
+		method string
+		url    string
+		status int
+	}{
+		{http.MethodPost, server.URL, http.StatusMethodNotAllowed},
+		{http.MethodGet, server.URL + "?limit=abc", http.StatusBadRequest},
+		{http.MethodGet, server.URL + "?limit=0", http.StatusBadRequest},
+		{http.MethodGet, server.URL, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.url, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, tt.status, resp.StatusCode, tt.url)
+	}
+}
+
+func TestDLQMergeTriggerFromContext(t *testing.T) {
+	assert.Equal(t, unknownDLQMergeTrigger, dlqMergeTriggerFromContext(context.Background()))
+	assert.Equal(t, unknownDLQMergeTrigger, dlqMergeTriggerFromContext(ContextWithDLQMergeTrigger(context.Background(), "")))
+	assert.Equal(t, "cadence-cli", dlqMergeTriggerFromContext(ContextWithDLQMergeTrigger(context.Background(), "cadence-cli")))
+}
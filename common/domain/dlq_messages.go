@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// DLQMessagesPath is the path the DLQ messages handler is served on by the debug HTTP server
+	DLQMessagesPath = "/api/v1/admin/dlq/messages"
+
+	defaultDLQMessagesPageSize = 100
+)
+
+type (
+	// DLQMessage is the JSON representation of a domain DLQ message
+	DLQMessage struct {
+		MessageID  int64                      `json:"messageID"`
+		TaskType   *types.ReplicationTaskType `json:"taskType,omitempty"`
+		EnqueuedAt *time.Time                 `json:"enqueuedAt,omitempty"`
+		Task       *types.ReplicationTask     `json:"task"`
+	}
+
+	// DLQMessagesResponse is a page of domain DLQ messages served by the DLQ messages handler
+	DLQMessagesResponse struct {
+		Messages      []DLQMessage `json:"messages"`
+		NextPageToken string       `json:"nextPageToken,omitempty"`
+	}
+
+	dlqMessagesHandler struct {
+		dlqHandler DLQMessageHandler
+	}
+)
+
+// NewDLQMessage returns the JSON representation of the domain DLQ message
+func NewDLQMessage(task *types.ReplicationTask) DLQMessage {
+	message := DLQMessage{
+		MessageID: task.SourceTaskID,
+		TaskType:  task.TaskType,
+		Task:      task,
+	}
+	// messages enqueued before the enqueue time was persisted have no enqueue time
+	if !task.EnqueuedAt.IsZero() {
+		enqueuedAt := task.EnqueuedAt
+		message.EnqueuedAt = &enqueuedAt
+	}
+	return message
+}
+
+// NewDLQMessagesHandler returns an HTTP handler which serves a page of domain DLQ messages as JSON.
+// The page is selected by the optional lastMessageID, pageSize and pageToken query parameters.
+func NewDLQMessagesHandler(dlqHandler DLQMessageHandler) http.Handler {
+	return &dlqMessagesHandler{
+		dlqHandler: dlqHandler,
+	}
+}
+
+func (h *dlqMessagesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	lastMessageID := common.EndMessageID
+	if value := query.Get("lastMessageID"); value != "" {
+		var err error
+		if lastMessageID, err = strconv.ParseInt(value, 10, 64); err != nil {
+			http.Error(w, "invalid lastMessageID: "+value, http.StatusBadRequest)
+			return
+		}
+	}
+	pageSize := defaultDLQMessagesPageSize
+	if value := query.Get("pageSize"); value != "" {
+		var err error
+		if pageSize, err = strconv.Atoi(value); err != nil || pageSize <= 0 {
+			http.Error(w, "invalid pageSize: "+value, http.StatusBadRequest)
+			return
+		}
+	}
+	var pageToken []byte
+	if value := query.Get("pageToken"); value != "" {
+		var err error
+		if pageToken, err = base64.URLEncoding.DecodeString(value); err != nil {
+			http.Error(w, "invalid pageToken: "+value, http.StatusBadRequest)
+			return
+		}
+	}
+
+	tasks, token, err := h.dlqHandler.Read(r.Context(), AllTaskTypes, lastMessageID, pageSize, pageToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.(type) {
+		case *types.BadRequestError, *ErrPageSizeExceeded:
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	response := DLQMessagesResponse{
+		Messages: make([]DLQMessage, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		response.Messages = append(response.Messages, NewDLQMessage(task))
+	}
+	if len(token) != 0 {
+		response.NextPageToken = base64.URLEncoding.EncodeToString(token)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
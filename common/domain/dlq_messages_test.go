@@ -0,0 +1,150 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestDLQMessagesHandler(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	enqueuedAt := time.Unix(100, 0).UTC()
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID: 11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{
+				DomainOperation: types.DomainOperationUpdate.Ptr(),
+				ID:              "some random domain ID",
+			},
+			EnqueuedAt: enqueuedAt,
+		},
+	}
+	pageToken := []byte{1, 2, 3}
+	nextPageToken := []byte{4, 5, 6}
+	dlqHandler := NewMockDLQMessageHandler(controller)
+	dlqHandler.EXPECT().Read(gomock.Any(), AllTaskTypes, common.EndMessageID, defaultDLQMessagesPageSize, nil).
+		Return(tasks, nextPageToken, nil).Times(1)
+	dlqHandler.EXPECT().Read(gomock.Any(), AllTaskTypes, int64(20), 5, pageToken).
+		Return(nil, nil, nil).Times(1)
+
+	server := httptest.NewServer(NewDLQMessagesHandler(dlqHandler))
+	defer server.Close()
+
+	response := getDLQMessages(t, server.URL)
+	assert.Equal(t, base64.URLEncoding.EncodeToString(nextPageToken), response.NextPageToken)
+	require.Len(t, response.Messages, 1)
+	assert.Equal(t, int64(11), response.Messages[0].MessageID)
+	assert.Equal(t, types.ReplicationTaskTypeDomain, *response.Messages[0].TaskType)
+	assert.Equal(t, enqueuedAt, *response.Messages[0].EnqueuedAt)
+	assert.Equal(t, tasks[0].DomainTaskAttributes, response.Messages[0].Task.DomainTaskAttributes)
+
+	response = getDLQMessages(t, fmt.Sprintf(
+		"%v?lastMessageID=20&pageSize=5&pageToken=%v",
+		server.URL,
+		base64.URLEncoding.EncodeToString(pageToken),
+	))
+	assert.Empty(t, response.NextPageToken)
+	assert.Empty(t, response.Messages)
+}
+
+func TestDLQMessagesHandler_HumanReadableJSON(t *testing.T) {
+	message := NewDLQMessage(&types.ReplicationTask{
+		TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID: 11,
+		DomainTaskAttributes: &types.DomainTaskAttributes{
+			DomainOperation: types.DomainOperationUpdate.Ptr(),
+		},
+	})
+
+	data, err := json.Marshal(message)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"messageID":11`)
+	assert.Contains(t, string(data), `"taskType":"Domain"`)
+	assert.Contains(t, string(data), `"domainOperation":"Update"`)
+	assert.NotContains(t, string(data), "enqueuedAt")
+}
+
+func TestDLQMessagesHandler_Errors(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	dlqHandler := NewMockDLQMessageHandler(controller)
+	dlqHandler.EXPECT().Read(gomock.Any(), AllTaskTypes, int64(1), defaultDLQMessagesPageSize, nil).
+		Return(nil, nil, &types.BadRequestError{Message: "test"}).Times(1)
+	dlqHandler.EXPECT().Read(gomock.Any(), AllTaskTypes, common.EndMessageID, defaultDLQMessagesPageSize, nil).
+		Return(nil, nil, fmt.Errorf("test")).Times(1)
+	dlqHandler.EXPECT().Read(gomock.Any(), AllTaskTypes, common.EndMessageID, 5000, nil).
+		Return(nil, nil, &ErrPageSizeExceeded{MaxPageSize: 1000}).Times(1)
+
+	server := httptest.NewServer(NewDLQMessagesHandler(dlqHandler))
+	defer server.Close()
+
+	tests := []struct {
+		method string
+		url    string
+		status int
+	}{
+		{http.MethodPost, server.URL, http.StatusMethodNotAllowed},
+		{http.MethodGet, server.URL + "?lastMessageID=abc", http.StatusBadRequest},
+		{http.MethodGet, server.URL + "?pageSize=abc", http.StatusBadRequest},
+		{http.MethodGet, server.URL + "?pageSize=0", http.StatusBadRequest},
+		{http.MethodGet, server.URL + "?pageToken=%25%25", http.StatusBadRequest},
+		{http.MethodGet, server.URL + "?lastMessageID=1", http.StatusBadRequest},
+		{http.MethodGet, server.URL + "?pageSize=5000", http.StatusBadRequest},
+		{http.MethodGet, server.URL, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.url, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, tt.status, resp.StatusCode, tt.url)
+	}
+}
+
+func getDLQMessages(t *testing.T, url string) DLQMessagesResponse {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var response DLQMessagesResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	return response
+}
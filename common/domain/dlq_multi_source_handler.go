@@ -0,0 +1,314 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/multierr"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// MultiSourceDLQMessageHandler handles the domain DLQs a hub cluster receives from several source clusters.
+	// Every source cluster has its own DLQ handler, which tracks the ack level of the DLQ of the source cluster,
+	// and the handlers of the source clusters are called concurrently.
+	MultiSourceDLQMessageHandler interface {
+		common.Daemon
+
+		// Shutdown stops the handlers of all source clusters and waits for them to finish their current work
+		Shutdown(ctx context.Context) error
+		// Handler returns the DLQ handler of the source cluster
+		Handler(sourceCluster string) (DLQMessageHandler, bool)
+		// SourceClusters returns the source clusters in name order
+		SourceClusters() []string
+
+		Count(ctx context.Context, forceFetch bool) (map[string]int64, error)
+		Read(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
+		Merge(ctx context.Context, taskType types.ReplicationTaskType, mergeRequestID string, lastMessageID int64, pageSize int, pageToken []byte) ([]byte, error)
+		Purge(ctx context.Context, taskType types.ReplicationTaskType, lastMessageID int64) error
+		Health() map[string]DLQHealth
+	}
+
+	multiSourceDLQMessageHandlerImpl struct {
+		handlers       map[string]DLQMessageHandler
+		sourceClusters []string
+		logger         log.Logger
+		status         int32
+	}
+
+	// multiSourceDLQPageToken holds the page tokens of the source clusters which have more pages to read,
+	// the source clusters which are not in the token have been read completely
+	multiSourceDLQPageToken struct {
+		Sources map[string][]byte `json:"sources"`
+	}
+)
+
+var _ MultiSourceDLQMessageHandler = (*multiSourceDLQMessageHandlerImpl)(nil)
+
+// NewMultiSourceDLQMessageHandler returns a handler of the domain DLQs of several source clusters,
+// for hub-and-spoke topologies in which the hub receives DLQ messages from every spoke.
+// The handlers are keyed by the name of the source cluster whose DLQ they handle.
+func NewMultiSourceDLQMessageHandler(
+	handlers map[string]DLQMessageHandler,
+	logger log.Logger,
+) MultiSourceDLQMessageHandler {
+
+	sourceClusters := make([]string, 0, len(handlers))
+	for sourceCluster := range handlers {
+		sourceClusters = append(sourceClusters, sourceCluster)
+	}
+	sort.Strings(sourceClusters)
+	return &multiSourceDLQMessageHandlerImpl{
+		handlers:       handlers,
+		sourceClusters: sourceClusters,
+		logger:         logger,
+	}
+}
+
+// Start starts the handlers of all source clusters
+func (h *multiSourceDLQMessageHandlerImpl) Start() {
+	if !atomic.CompareAndSwapInt32(&h.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	for _, sourceCluster := range h.sourceClusters {
+		h.handlers[sourceCluster].Start()
+	}
+	h.logger.Info("Multi source domain DLQ handler started.")
+}
+
+// Stop stops the handlers of all source clusters
+func (h *multiSourceDLQMessageHandlerImpl) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), dlqShutdownTimeout)
+	defer cancel()
+	if err := h.Shutdown(ctx); err != nil {
+		h.logger.Warn("Multi source domain DLQ handler timed out on shutdown.", tag.LifeCycleStopTimedout, tag.Error(err))
+	}
+}
+
+// Shutdown stops the handlers of all source clusters concurrently and waits for them to exit
+func (h *multiSourceDLQMessageHandlerImpl) Shutdown(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&h.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return nil
+	}
+
+	err := multierr.Combine(h.fanOut(h.sourceClusters, func(_ int, handler DLQMessageHandler) error {
+		return handler.Shutdown(ctx)
+	})...)
+	if err != nil {
+		return err
+	}
+	h.logger.Info("Multi source domain DLQ handler stopped.")
+	return nil
+}
+
+// Handler returns the DLQ handler of the source cluster
+func (h *multiSourceDLQMessageHandlerImpl) Handler(sourceCluster string) (DLQMessageHandler, bool) {
+	handler, ok := h.handlers[sourceCluster]
+	return handler, ok
+}
+
+// SourceClusters returns the source clusters in name order
+func (h *multiSourceDLQMessageHandlerImpl) SourceClusters() []string {
+	return append([]string(nil), h.sourceClusters...)
+}
+
+// Count returns the number of messages in the DLQ of every source cluster
+func (h *multiSourceDLQMessageHandlerImpl) Count(ctx context.Context, forceFetch bool) (map[string]int64, error) {
+	counts := make([]int64, len(h.sourceClusters))
+	errs := h.fanOut(h.sourceClusters, func(i int, handler DLQMessageHandler) error {
+		var err error
+		counts[i], err = handler.Count(ctx, forceFetch)
+		return err
+	})
+	if err := multierr.Combine(errs...); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(h.sourceClusters))
+	for i, sourceCluster := range h.sourceClusters {
+		result[sourceCluster] = counts[i]
+	}
+	return result, nil
+}
+
+// Read reads a page of the DLQ of every source cluster concurrently and returns the messages of the pages
+// in the order of the source clusters. The page size and last message ID apply to each source cluster, as
+// every DLQ has message IDs of its own. Messages which carry no source cluster are tagged with the source
+// cluster of their DLQ. The returned page token continues the reads of the source clusters which have more
+// pages and is nil once every DLQ is read completely.
+func (h *multiSourceDLQMessageHandlerImpl) Read(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	sourceClusters, tokens, err := h.deserializePageToken(pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pages := make([][]*types.ReplicationTask, len(sourceClusters))
+	nextTokens := make([][]byte, len(sourceClusters))
+	errs := h.fanOut(sourceClusters, func(i int, handler DLQMessageHandler) error {
+		var err error
+		pages[i], nextTokens[i], err = handler.Read(ctx, taskType, lastMessageID, pageSize, tokens[i])
+		return err
+	})
+	if err := multierr.Combine(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	var messages []*types.ReplicationTask
+	for i, sourceCluster := range sourceClusters {
+		for _, message := range pages[i] {
+			if message.SourceCluster == "" {
+				message.SourceCluster = sourceCluster
+			}
+		}
+		messages = append(messages, pages[i]...)
+	}
+	nextPageToken, err := serializeMultiSourceDLQPageToken(sourceClusters, nextTokens)
+	if err != nil {
+		return nil, nil, err
+	}
+	return messages, nextPageToken, nil
+}
+
+// Merge merges a page of the DLQ of every source cluster concurrently, moving the ack level of every
+// DLQ on its own. The page size and last message ID apply to each source cluster. A source cluster whose
+// merge fails does not stop the merges of the others, and the retried merge starts from the ack levels
+// of the source clusters.
+func (h *multiSourceDLQMessageHandlerImpl) Merge(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	mergeRequestID string,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]byte, error) {
+
+	sourceClusters, tokens, err := h.deserializePageToken(pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	nextTokens := make([][]byte, len(sourceClusters))
+	errs := h.fanOut(sourceClusters, func(i int, handler DLQMessageHandler) error {
+		var err error
+		nextTokens[i], err = handler.Merge(ctx, taskType, mergeRequestID, lastMessageID, pageSize, tokens[i])
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			h.logger.Error("Failed to merge domain DLQ of source cluster", tag.SourceCluster(sourceClusters[i]), tag.Error(err))
+		}
+	}
+	if err := multierr.Combine(errs...); err != nil {
+		return nil, err
+	}
+	return serializeMultiSourceDLQPageToken(sourceClusters, nextTokens)
+}
+
+// Purge purges the DLQ of every source cluster concurrently, the last message ID applies to each source cluster
+func (h *multiSourceDLQMessageHandlerImpl) Purge(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	lastMessageID int64,
+) error {
+
+	return multierr.Combine(h.fanOut(h.sourceClusters, func(_ int, handler DLQMessageHandler) error {
+		return handler.Purge(ctx, taskType, lastMessageID)
+	})...)
+}
+
+// Health returns the health of the handler of every source cluster, including the ack level of its DLQ
+func (h *multiSourceDLQMessageHandlerImpl) Health() map[string]DLQHealth {
+	health := make(map[string]DLQHealth, len(h.sourceClusters))
+	for _, sourceCluster := range h.sourceClusters {
+		health[sourceCluster] = h.handlers[sourceCluster].Health()
+	}
+	return health
+}
+
+// deserializePageToken returns the source clusters to read and their page tokens, a nil token reads every source cluster
+func (h *multiSourceDLQMessageHandlerImpl) deserializePageToken(pageToken []byte) ([]string, [][]byte, error) {
+	if len(pageToken) == 0 {
+		return h.sourceClusters, make([][]byte, len(h.sourceClusters)), nil
+	}
+
+	var token multiSourceDLQPageToken
+	if err := json.Unmarshal(pageToken, &token); err != nil {
+		return nil, nil, &types.BadRequestError{Message: "invalid multi source domain DLQ page token"}
+	}
+	var sourceClusters []string
+	var tokens [][]byte
+	for _, sourceCluster := range h.sourceClusters {
+		if sourceToken, ok := token.Sources[sourceCluster]; ok {
+			sourceClusters = append(sourceClusters, sourceCluster)
+			tokens = append(tokens, sourceToken)
+		}
+	}
+	if len(sourceClusters) != len(token.Sources) {
+		return nil, nil, &types.BadRequestError{Message: "multi source domain DLQ page token has unknown source clusters"}
+	}
+	return sourceClusters, tokens, nil
+}
+
+// fanOut calls fn with the handlers of the source clusters concurrently and returns their errors in the order of the source clusters
+func (h *multiSourceDLQMessageHandlerImpl) fanOut(sourceClusters []string, fn func(int, DLQMessageHandler) error) []error {
+	errs := make([]error, len(sourceClusters))
+	var wg sync.WaitGroup
+	wg.Add(len(sourceClusters))
+	for i, sourceCluster := range sourceClusters {
+		go func(i int, handler DLQMessageHandler) {
+			defer wg.Done()
+			errs[i] = fn(i, handler)
+		}(i, h.handlers[sourceCluster])
+	}
+	wg.Wait()
+	return errs
+}
+
+// serializeMultiSourceDLQPageToken returns the page token of the source clusters with more pages, or nil if there are none
+func serializeMultiSourceDLQPageToken(sourceClusters []string, tokens [][]byte) ([]byte, error) {
+	token := multiSourceDLQPageToken{Sources: make(map[string][]byte)}
+	for i, sourceCluster := range sourceClusters {
+		if len(tokens[i]) > 0 {
+			token.Sources[sourceCluster] = tokens[i]
+		}
+	}
+	if len(token.Sources) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(token)
+}
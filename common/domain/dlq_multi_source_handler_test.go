@@ -0,0 +1,185 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+type multiSourceDLQTestSource struct {
+	queue    *MockReplicationQueue
+	executor *MockReplicationTaskExecutor
+}
+
+func newTestMultiSourceDLQMessageHandler(
+	controller *gomock.Controller,
+	sourceClusters ...string,
+) (MultiSourceDLQMessageHandler, map[string]multiSourceDLQTestSource) {
+
+	sources := make(map[string]multiSourceDLQTestSource, len(sourceClusters))
+	handlers := make(map[string]DLQMessageHandler, len(sourceClusters))
+	for _, sourceCluster := range sourceClusters {
+		source := multiSourceDLQTestSource{
+			queue:    NewMockReplicationQueue(controller),
+			executor: NewMockReplicationTaskExecutor(controller),
+		}
+		sources[sourceCluster] = source
+		handlers[sourceCluster] = NewDLQMessageHandler(
+			NewReplicationTaskExecutorRegistry(source.executor),
+			source.queue,
+			loggerimpl.NewNopLogger(),
+			WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+			WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+		)
+	}
+	return NewMultiSourceDLQMessageHandler(handlers, loggerimpl.NewNopLogger()), sources
+}
+
+func TestMultiSourceDLQMessageHandler_Read(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	handler, sources := newTestMultiSourceDLQMessageHandler(controller, "spoke-b", "spoke-a")
+	spokeA, spokeB := sources["spoke-a"], sources["spoke-b"]
+	lastMessageID := int64(100)
+	pageSize := 2
+
+	// the DLQs of the source clusters are at different ack levels
+	spokeA.queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil).Times(2)
+	spokeB.queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(50), nil).Times(1)
+	spokeA.queue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), lastMessageID, pageSize, nil).Return(
+		[]*types.ReplicationTask{
+			{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+			{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+		}, []byte("spoke-a-token"), nil,
+	).Times(1)
+	spokeB.queue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(50), lastMessageID, pageSize, nil).Return(
+		[]*types.ReplicationTask{
+			{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 51, SourceCluster: "spoke-b"},
+		}, nil, nil,
+	).Times(1)
+
+	messages, token, err := handler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, nil)
+	require.NoError(t, err)
+	require.NotNil(t, token)
+	require.Len(t, messages, 3)
+	assert.Equal(t, int64(11), messages[0].SourceTaskID)
+	assert.Equal(t, "spoke-a", messages[0].SourceCluster)
+	assert.Equal(t, int64(12), messages[1].SourceTaskID)
+	assert.Equal(t, int64(51), messages[2].SourceTaskID)
+	assert.Equal(t, "spoke-b", messages[2].SourceCluster)
+
+	// only the source cluster with more pages is read with the next page token
+	spokeA.queue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), lastMessageID, pageSize, []byte("spoke-a-token")).Return(
+		[]*types.ReplicationTask{
+			{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 13},
+		}, nil, nil,
+	).Times(1)
+
+	messages, token, err = handler.Read(context.Background(), AllTaskTypes, lastMessageID, pageSize, token)
+	require.NoError(t, err)
+	assert.Nil(t, token)
+	require.Len(t, messages, 1)
+	assert.Equal(t, int64(13), messages[0].SourceTaskID)
+}
+
+func TestMultiSourceDLQMessageHandler_Read_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	handler, sources := newTestMultiSourceDLQMessageHandler(controller, "spoke-a", "spoke-b")
+	readErr := errors.New("failed to read DLQ")
+	sources["spoke-a"].queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil).Times(1)
+	sources["spoke-a"].queue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), int64(100), 10, nil).Return(nil, nil, readErr).Times(1)
+	sources["spoke-b"].queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(50), nil).Times(1)
+	sources["spoke-b"].queue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(50), int64(100), 10, nil).Return(nil, nil, nil).Times(1)
+
+	_, _, err := handler.Read(context.Background(), AllTaskTypes, 100, 10, nil)
+	assert.Error(t, err)
+}
+
+func TestMultiSourceDLQMessageHandler_Read_InvalidPageToken(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	handler, _ := newTestMultiSourceDLQMessageHandler(controller, "spoke-a", "spoke-b")
+
+	_, _, err := handler.Read(context.Background(), AllTaskTypes, 100, 10, []byte("invalid"))
+	assert.IsType(t, &types.BadRequestError{}, err)
+	_, _, err = handler.Read(context.Background(), AllTaskTypes, 100, 10, []byte(`{"sources":{"spoke-c":"dG9rZW4="}}`))
+	assert.IsType(t, &types.BadRequestError{}, err)
+}
+
+func TestMultiSourceDLQMessageHandler_Merge_IndependentAckLevels(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	handler, sources := newTestMultiSourceDLQMessageHandler(controller, "spoke-a", "spoke-b")
+	spokeA, spokeB := sources["spoke-a"], sources["spoke-b"]
+	lastMessageID := int64(100)
+	pageSize := 10
+	spokeATask := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11, SourceCluster: "spoke-a"}
+	spokeBTask := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 51, SourceCluster: "spoke-b"}
+
+	spokeA.queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil).Times(1)
+	spokeA.queue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), lastMessageID, pageSize, nil).
+		Return([]*types.ReplicationTask{spokeATask}, nil, nil).Times(1)
+	spokeA.executor.EXPECT().ExecuteReplicationTask(spokeATask, "spoke-a").Return(nil).Times(1)
+	spokeA.queue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), int64(11)).Return(nil).Times(1)
+	spokeA.queue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, int64(10), int64(11)).Return(nil).Times(1)
+	spokeA.queue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	// the merge of spoke-b fails without moving its ack level, the ack level of spoke-a moves on its own
+	spokeB.queue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(50), nil).Times(1)
+	spokeB.queue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(50), lastMessageID, pageSize, nil).
+		Return([]*types.ReplicationTask{spokeBTask}, nil, nil).Times(1)
+	spokeB.executor.EXPECT().ExecuteReplicationTask(spokeBTask, "spoke-b").Return(errors.New("failed to execute")).AnyTimes()
+	spokeB.queue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	_, err := handler.Merge(context.Background(), AllTaskTypes, "", lastMessageID, pageSize, nil)
+	assert.Error(t, err)
+}
+
+func TestMultiSourceDLQMessageHandler_Count(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	handler, sources := newTestMultiSourceDLQMessageHandler(controller, "spoke-a", "spoke-b")
+	sources["spoke-a"].queue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(3), nil).Times(1)
+	sources["spoke-b"].queue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(7), nil).Times(1)
+
+	counts, err := handler.Count(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"spoke-a": 3, "spoke-b": 7}, counts)
+	assert.Equal(t, []string{"spoke-a", "spoke-b"}, handler.SourceClusters())
+	_, ok := handler.Handler("spoke-c")
+	assert.False(t, ok)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/types"
+)
+
+// dlqNackRetryDelay is how long a message nacked on a transient merge failure stays invisible to merges
+const dlqNackRetryDelay = time.Minute
+
+type (
+	// TransientError is implemented by replication task execution errors which may succeed when retried.
+	// Execution errors which do not implement it are permanent unless they are transient service errors.
+	TransientError interface {
+		error
+		Transient() bool
+	}
+)
+
+// IsTransientError returns true if executing the replication task again may succeed
+func IsTransientError(err error) bool {
+	var transientErr TransientError
+	if errors.As(err, &transientErr) {
+		return transientErr.Transient()
+	}
+	return common.IsServiceTransientError(err)
+}
+
+// NackMessage moves the DLQ message to the retry queue at the tail of the DLQ,
+// where it is not merged before retryAfter elapsed. The message gets a new message ID.
+func (q *replicationQueueImpl) NackMessage(
+	ctx context.Context,
+	messageID int64,
+	retryAfter time.Duration,
+) error {
+
+	messages, _, err := q.queue.ReadMessagesFromDLQ(ctx, messageID-1, messageID, 1, nil)
+	if err != nil {
+		return err
+	}
+	tasks, err := q.decodeDLQMessages(ctx, messages, AllTaskTypes)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return &types.EntityNotExistsError{Message: fmt.Sprintf("domain DLQ message %v does not exist", messageID)}
+	}
+
+	task := tasks[0]
+	task.RetryAfter = time.Now().Add(retryAfter)
+	payload, err := q.encodeDLQMessage(task)
+	if err != nil {
+		return err
+	}
+	// the message is enqueued again before it is deleted, so it is never lost
+	if err := q.queue.EnqueueMessageToDLQ(ctx, getReplicationTaskDomainID(task), getReplicationTaskType(task), payload); err != nil {
+		return err
+	}
+	return q.queue.DeleteMessageFromDLQ(ctx, messageID)
+}
+
+// deferRetryingMessages removes the nacked messages which are not visible yet from the page,
+// together with every message after them, so acknowledging the page never deletes them.
+// It returns true if any message was removed.
+func deferRetryingMessages(messages []*types.ReplicationTask, now time.Time) ([]*types.ReplicationTask, bool) {
+	firstRetrying := int64(-1)
+	for _, message := range messages {
+		if message.RetryAfter.After(now) && (firstRetrying == -1 || message.SourceTaskID < firstRetrying) {
+			firstRetrying = message.SourceTaskID
+		}
+	}
+	if firstRetrying == -1 {
+		return messages, false
+	}
+
+	visible := make([]*types.ReplicationTask, 0, len(messages))
+	for _, message := range messages {
+		if message.SourceTaskID < firstRetrying {
+			visible = append(visible, message)
+		}
+	}
+	return visible, true
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+type testTransientError struct {
+	transient bool
+}
+
+func (e *testTransientError) Error() string {
+	return "test"
+}
+
+func (e *testTransientError) Transient() bool {
+	return e.transient
+}
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(&types.ServiceBusyError{}))
+	assert.True(t, IsTransientError(&types.InternalServiceError{}))
+	assert.True(t, IsTransientError(&testTransientError{transient: true}))
+	assert.True(t, IsTransientError(fmt.Errorf("wrapped: %w", &testTransientError{transient: true})))
+
+	assert.False(t, IsTransientError(&testTransientError{transient: false}))
+	assert.False(t, IsTransientError(&types.BadRequestError{}))
+	assert.False(t, IsTransientError(errors.New("test")))
+	assert.False(t, IsTransientError(&ErrCircuitBreakerOpen{}))
+}
+
+func TestDeferRetryingMessages(t *testing.T) {
+	now := time.Now()
+	messages := []*types.ReplicationTask{
+		{SourceTaskID: 11},
+		{SourceTaskID: 14, RetryAfter: now.Add(time.Minute)},
+		{SourceTaskID: 12, RetryAfter: now.Add(-time.Minute)},
+		{SourceTaskID: 13, RetryAfter: now.Add(time.Minute)},
+		{SourceTaskID: 15},
+	}
+
+	visible, deferred := deferRetryingMessages(messages, now)
+	assert.True(t, deferred)
+	assert.Equal(t, []*types.ReplicationTask{messages[0], messages[2]}, visible)
+
+	visible, deferred = deferRetryingMessages(messages[:1], now)
+	assert.False(t, deferred)
+	assert.Equal(t, messages[:1], visible)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+)
+
+const (
+	// DLQEventAckLevelStalled is notified when the ack level of a non empty DLQ has not advanced for the stalled threshold
+	DLQEventAckLevelStalled DLQEventType = "AckLevelStalled"
+	// DLQEventDepthExceeded is notified when the number of DLQ messages exceeds the depth threshold
+	DLQEventDepthExceeded DLQEventType = "DepthExceeded"
+	// DLQEventMergeCompleted is notified when a merge acknowledged DLQ messages
+	DLQEventMergeCompleted DLQEventType = "MergeCompleted"
+	// DLQEventGrowthRateExceeded is notified when the DLQ grows faster than the growth rate threshold and the merges are paused
+	DLQEventGrowthRateExceeded DLQEventType = "GrowthRateExceeded"
+	// DLQEventGrowthRateRecovered is notified when the growth rate of the DLQ falls back below the threshold and the merges resume
+	DLQEventGrowthRateRecovered DLQEventType = "GrowthRateRecovered"
+
+	// dlqNotificationTimeout bounds how long the hooks may take to deliver a notification
+	dlqNotificationTimeout = 10 * time.Second
+)
+
+type (
+	// DLQEventType is the condition of the domain DLQ a notification is sent for
+	DLQEventType string
+
+	// DLQEvent describes a condition of the domain DLQ operators are notified of,
+	// the fields which do not apply to the type of the event are left empty
+	DLQEvent struct {
+		Type       DLQEventType  `json:"type"`
+		Timestamp  time.Time     `json:"timestamp"`
+		AckLevel   int64         `json:"ackLevel"`
+		Depth      int64         `json:"depth,omitempty"`
+		Threshold  int64         `json:"threshold,omitempty"`
+		StalledFor time.Duration `json:"stalledFor,omitempty"`
+		// MergedCount and FailedCount are set for DLQEventMergeCompleted
+		MergedCount int64 `json:"mergedCount,omitempty"`
+		FailedCount int64 `json:"failedCount,omitempty"`
+		// GrowthRate and MaxGrowthRate are set for the growth rate events, in messages per second
+		GrowthRate    float64 `json:"growthRate,omitempty"`
+		MaxGrowthRate float64 `json:"maxGrowthRate,omitempty"`
+	}
+
+	// NotificationHook is notified by the DLQ handler of the conditions of the domain DLQ,
+	// to integrate alerting such as PagerDuty or Slack
+	NotificationHook interface {
+		Notify(ctx context.Context, event DLQEvent) error
+	}
+
+	// WebhookNotificationHook POSTs the events as JSON to a webhook URL
+	WebhookNotificationHook struct {
+		url    dynamicconfig.StringPropertyFn
+		client *http.Client
+	}
+
+	noopNotificationHook struct{}
+)
+
+// NewNoopNotificationHook returns a hook which discards the events, the hook of the DLQ handler by default
+func NewNoopNotificationHook() NotificationHook {
+	return noopNotificationHook{}
+}
+
+func (noopNotificationHook) Notify(context.Context, DLQEvent) error {
+	return nil
+}
+
+// NewWebhookNotificationHook returns a hook which POSTs the events to the URL, no events are sent while the URL is empty
+func NewWebhookNotificationHook(url dynamicconfig.StringPropertyFn, client *http.Client) *WebhookNotificationHook {
+	if client == nil {
+		client = &http.Client{Timeout: dlqNotificationTimeout}
+	}
+	return &WebhookNotificationHook{
+		url:    url,
+		client: client,
+	}
+}
+
+// Notify POSTs the event to the webhook, responses other than 2xx are returned as errors
+func (h *WebhookNotificationHook) Notify(ctx context.Context, event DLQEvent) error {
+	url := h.url()
+	if url == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := h.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("domain DLQ notification webhook responded with status %v", response.Status)
+	}
+	return nil
+}
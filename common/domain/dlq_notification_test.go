@@ -0,0 +1,256 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+// dlqEventRecorder is a webhook which records the DLQ events POSTed to it
+type dlqEventRecorder struct {
+	sync.Mutex
+	events []DLQEvent
+}
+
+func newDLQEventRecorder(t *testing.T) (*dlqEventRecorder, *httptest.Server) {
+	recorder := &dlqEventRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var event DLQEvent
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&event)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		recorder.Lock()
+		defer recorder.Unlock()
+		recorder.events = append(recorder.events, event)
+	}))
+	return recorder, server
+}
+
+func (r *dlqEventRecorder) recorded() []DLQEvent {
+	r.Lock()
+	defer r.Unlock()
+	return append([]DLQEvent(nil), r.events...)
+}
+
+func newNotifyingDLQMessageHandler(
+	controller *gomock.Controller,
+	url string,
+	opts ...DLQOption,
+) (*dlqMessageHandlerImpl, *MockReplicationTaskExecutor, *MockReplicationQueue) {
+
+	mockReplicationTaskExecutor := NewMockReplicationTaskExecutor(controller)
+	mockReplicationQueue := NewMockReplicationQueue(controller)
+	opts = append([]DLQOption{
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+		WithNotificationHooks(NewWebhookNotificationHook(dynamicconfig.GetStringPropertyFn(url), nil)),
+	}, opts...)
+	dlqHandler := NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(mockReplicationTaskExecutor),
+		mockReplicationQueue,
+		loggerimpl.NewNopLogger(),
+		opts...,
+	)
+	return dlqHandler.(*dlqMessageHandlerImpl), mockReplicationTaskExecutor, mockReplicationQueue
+}
+
+func TestWebhookNotificationHook(t *testing.T) {
+	recorder, server := newDLQEventRecorder(t)
+	defer server.Close()
+
+	event := DLQEvent{
+		Type:       DLQEventAckLevelStalled,
+		Timestamp:  time.Unix(1600000000, 0).UTC(),
+		AckLevel:   10,
+		Depth:      5,
+		StalledFor: time.Hour,
+	}
+	hook := NewWebhookNotificationHook(dynamicconfig.GetStringPropertyFn(server.URL), nil)
+	require.NoError(t, hook.Notify(context.Background(), event))
+	assert.Equal(t, []DLQEvent{event}, recorder.recorded())
+}
+
+func TestWebhookNotificationHook_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	hook := NewWebhookNotificationHook(dynamicconfig.GetStringPropertyFn(server.URL), nil)
+	err := hook.Notify(context.Background(), DLQEvent{Type: DLQEventMergeCompleted})
+	assert.EqualError(t, err, "domain DLQ notification webhook responded with status 503 Service Unavailable")
+
+	hook = NewWebhookNotificationHook(dynamicconfig.GetStringPropertyFn(server.URL), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, errors.Is(hook.Notify(ctx, DLQEvent{Type: DLQEventMergeCompleted}), context.Canceled))
+}
+
+func TestWebhookNotificationHook_EmptyURL(t *testing.T) {
+	hook := NewWebhookNotificationHook(dynamicconfig.GetStringPropertyFn(""), nil)
+	assert.NoError(t, hook.Notify(context.Background(), DLQEvent{Type: DLQEventMergeCompleted}))
+}
+
+func TestDLQNotification_AckLevelStalled(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	recorder, server := newDLQEventRecorder(t)
+	defer server.Close()
+
+	now := time.Unix(1600000000, 0).UTC()
+	timeSource := clock.NewEventTimeSource().Update(now)
+	dlqHandler, _, mockReplicationQueue := newNotifyingDLQMessageHandler(
+		controller,
+		server.URL,
+		WithStalledAckThreshold(dynamicconfig.GetDurationPropertyFn(10*time.Minute)),
+		WithTimeSource(timeSource),
+	)
+	mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(int64(10), nil).Times(2)
+	mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(5), nil).Times(1)
+
+	require.NoError(t, dlqHandler.checkAckLevelProgress(context.Background()))
+	assert.Empty(t, recorder.recorded())
+	timeSource.Update(now.Add(11 * time.Minute))
+	require.NoError(t, dlqHandler.checkAckLevelProgress(context.Background()))
+	assert.Equal(t, []DLQEvent{{
+		Type:       DLQEventAckLevelStalled,
+		Timestamp:  now.Add(11 * time.Minute),
+		AckLevel:   10,
+		Depth:      5,
+		StalledFor: 11 * time.Minute,
+	}}, recorder.recorded())
+}
+
+func TestDLQNotification_DepthExceeded(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	recorder, server := newDLQEventRecorder(t)
+	defer server.Close()
+
+	now := time.Unix(1600000000, 0).UTC()
+	dlqHandler, _, mockReplicationQueue := newNotifyingDLQMessageHandler(
+		controller,
+		server.URL,
+		WithDepthAlertThreshold(dynamicconfig.GetIntPropertyFn(100)),
+		WithTimeSource(clock.NewEventTimeSource().Update(now)),
+	)
+	gomock.InOrder(
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(100), nil),
+		mockReplicationQueue.EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(101), nil),
+	)
+
+	require.NoError(t, dlqHandler.fetchAndEmitDLQSize(context.Background()))
+	dlqHandler.checkDLQDepth(context.Background())
+	assert.Empty(t, recorder.recorded())
+	require.NoError(t, dlqHandler.fetchAndEmitDLQSize(context.Background()))
+	dlqHandler.checkDLQDepth(context.Background())
+	assert.Equal(t, []DLQEvent{{
+		Type:      DLQEventDepthExceeded,
+		Timestamp: now,
+		AckLevel:  -1,
+		Depth:     101,
+		Threshold: 100,
+	}}, recorder.recorded())
+}
+
+func TestDLQNotification_MergeCompleted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	recorder, server := newDLQEventRecorder(t)
+	defer server.Close()
+
+	now := time.Unix(1600000000, 0).UTC()
+	dlqHandler, mockReplicationTaskExecutor, mockReplicationQueue := newNotifyingDLQMessageHandler(
+		controller,
+		server.URL,
+		WithTimeSource(clock.NewEventTimeSource().Update(now)),
+	)
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12},
+	}
+	mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(20), 100, nil).
+		Return(tasks, nil, nil).Times(1)
+	mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(12)).Return(nil).Times(1)
+	mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(12)).Return(nil).Times(1)
+	mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	_, err := dlqHandler.Merge(context.Background(), AllTaskTypes, "", 20, 100, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []DLQEvent{{
+		Type:        DLQEventMergeCompleted,
+		Timestamp:   now,
+		AckLevel:    12,
+		MergedCount: 2,
+	}}, recorder.recorded())
+}
+
+func TestDLQNotification_HookFailure(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	recorder, server := newDLQEventRecorder(t)
+	defer server.Close()
+
+	scope := tally.NewTestScope("test", nil)
+	dlqHandler, _, _ := newNotifyingDLQMessageHandler(
+		controller,
+		failing.URL,
+		WithNotificationHooks(
+			NewWebhookNotificationHook(dynamicconfig.GetStringPropertyFn(failing.URL), nil),
+			NewWebhookNotificationHook(dynamicconfig.GetStringPropertyFn(server.URL), nil),
+		),
+		WithMetricsClient(metrics.NewClient(scope, metrics.Frontend)),
+	)
+
+	dlqHandler.notify(context.Background(), DLQEvent{Type: DLQEventMergeCompleted, MergedCount: 1})
+	events := recorder.recorded()
+	require.Len(t, events, 1)
+	assert.Equal(t, DLQEventMergeCompleted, events[0].Type)
+	assert.Equal(t, int64(1), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_notification_failed")))
+}
@@ -0,0 +1,389 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// DLQOption configures the DLQ message handler created by NewDLQMessageHandler
+	DLQOption func(*dlqMessageHandlerConfig)
+
+	// dlqMessageHandlerConfig holds the configuration of the DLQ message handler,
+	// the defaults leave every optional feature of the handler disabled.
+	// The handler reads the dynamic config properties whenever it uses them, so changes apply without a restart.
+	dlqMessageHandlerConfig struct {
+		consumerGroup                  string
+		maxRetryAttempts               dynamicconfig.IntPropertyFn
+		sizeEmitInterval               dynamicconfig.DurationPropertyFn
+		mergeRPS                       dynamicconfig.IntPropertyFn
+		replayRPS                      dynamicconfig.IntPropertyFn
+		deduplicationWindowSize        dynamicconfig.IntPropertyFn
+		deduplicationFalsePositiveRate dynamicconfig.FloatPropertyFn
+		seenTaskIDsCapacity            int
+		messageTTL                     dynamicconfig.DurationPropertyFn
+		purgeBatchDelay                dynamicconfig.DurationPropertyFn
+		priorityMergeEnabled           dynamicconfig.BoolPropertyFn
+		groupedMergeEnabled            dynamicconfig.BoolPropertyFn
+		speculativeRetryEnabled        dynamicconfig.BoolPropertyFn
+		executionJournalEnabled        dynamicconfig.BoolPropertyFn
+		versionedAckLevelEnabled       dynamicconfig.BoolPropertyFn
+		ackLevelMaxConflictRetries     dynamicconfig.IntPropertyFn
+		maxReadPageSize                dynamicconfig.IntPropertyFn
+		softDeleteEnabled              dynamicconfig.BoolPropertyFn
+		largeMessageThresholdBytes     dynamicconfig.IntPropertyFn
+		stalledAckThreshold            dynamicconfig.DurationPropertyFn
+		depthAlertThreshold            dynamicconfig.IntPropertyFn
+		mergeTimeout                   dynamicconfig.DurationPropertyFn
+		readMaxRetries                 dynamicconfig.IntPropertyFn
+		checkpointGranularity          dynamicconfig.StringPropertyFn
+		parallelism                    dynamicconfig.IntPropertyFn
+		backpressureMaxAttempts        dynamicconfig.IntPropertyFn
+		invalidTaskPolicy              dynamicconfig.StringPropertyFn
+		domainFilter                   DomainFilterFunc
+		priorityFunc                   PriorityFunc
+		locker                         DistributedLocker
+		notificationHooks              []NotificationHook
+		simulationDomains              persistence.DomainManager
+		archiver                       DLQArchiver
+		archivalEnabled                dynamicconfig.BoolPropertyFn
+		archivalURI                    dynamicconfig.StringPropertyFn
+		growthRateSampleInterval       dynamicconfig.DurationPropertyFn
+		maxGrowthRate                  dynamicconfig.FloatPropertyFn
+		stateChangeObserver            StateChangeFunc
+		destinationCluster             string
+		timeSource                     clock.TimeSource
+		metricsClient                  metrics.Client
+	}
+)
+
+func defaultDLQMessageHandlerConfig() *dlqMessageHandlerConfig {
+	return &dlqMessageHandlerConfig{
+		consumerGroup:                  DefaultConsumerGroup,
+		maxRetryAttempts:               dynamicconfig.GetIntPropertyFn(0),
+		sizeEmitInterval:               dynamicconfig.GetDurationPropertyFn(time.Minute),
+		mergeRPS:                       dynamicconfig.GetIntPropertyFn(10),
+		replayRPS:                      dynamicconfig.GetIntPropertyFn(100),
+		deduplicationWindowSize:        dynamicconfig.GetIntPropertyFn(0),
+		deduplicationFalsePositiveRate: dynamicconfig.GetFloatPropertyFn(0.0001),
+		messageTTL:                     dynamicconfig.GetDurationPropertyFn(0),
+		purgeBatchDelay:                dynamicconfig.GetDurationPropertyFn(100 * time.Millisecond),
+		priorityMergeEnabled:           dynamicconfig.GetBoolPropertyFn(false),
+		groupedMergeEnabled:            dynamicconfig.GetBoolPropertyFn(false),
+		speculativeRetryEnabled:        dynamicconfig.GetBoolPropertyFn(false),
+		executionJournalEnabled:        dynamicconfig.GetBoolPropertyFn(false),
+		versionedAckLevelEnabled:       dynamicconfig.GetBoolPropertyFn(false),
+		ackLevelMaxConflictRetries:     dynamicconfig.GetIntPropertyFn(dlqAckLevelMaxConflictRetries),
+		maxReadPageSize:                dynamicconfig.GetIntPropertyFn(1000),
+		softDeleteEnabled:              dynamicconfig.GetBoolPropertyFn(false),
+		largeMessageThresholdBytes:     dynamicconfig.GetIntPropertyFn(1024 * 1024),
+		stalledAckThreshold:            dynamicconfig.GetDurationPropertyFn(0),
+		depthAlertThreshold:            dynamicconfig.GetIntPropertyFn(0),
+		mergeTimeout:                   dynamicconfig.GetDurationPropertyFn(0),
+		readMaxRetries:                 dynamicconfig.GetIntPropertyFn(3),
+		checkpointGranularity:          dynamicconfig.GetStringPropertyFn(string(CheckpointGranularityPerPage)),
+		parallelism:                    dynamicconfig.GetIntPropertyFn(1),
+		backpressureMaxAttempts:        dynamicconfig.GetIntPropertyFn(0),
+		invalidTaskPolicy:              dynamicconfig.GetStringPropertyFn(string(InvalidTaskPolicyNone)),
+		notificationHooks:              []NotificationHook{NewNoopNotificationHook()},
+		archivalEnabled:                dynamicconfig.GetBoolPropertyFn(false),
+		archivalURI:                    dynamicconfig.GetStringPropertyFn(""),
+		growthRateSampleInterval:       dynamicconfig.GetDurationPropertyFn(time.Minute),
+		maxGrowthRate:                  dynamicconfig.GetFloatPropertyFn(0),
+		timeSource:                     clock.NewRealTimeSource(),
+		metricsClient:                  metrics.NewNoopMetricsClient(),
+	}
+}
+
+// WithConsumerGroup keeps the ack levels of the handler independent of other consumers of the DLQ
+func WithConsumerGroup(consumerGroup string) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.consumerGroup = consumerGroup
+	}
+}
+
+// WithMetricsClient emits the metrics of the handler to the client instead of discarding them
+func WithMetricsClient(metricsClient metrics.Client) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.metricsClient = metricsClient
+	}
+}
+
+// WithRateLimit limits the messages merged and replayed per second, 10 and 100 by default
+func WithRateLimit(mergeRPS dynamicconfig.IntPropertyFn, replayRPS dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.mergeRPS = mergeRPS
+		c.replayRPS = replayRPS
+	}
+}
+
+// WithMaxRetries drops a message from the DLQ once merging it failed maxRetryAttempts times,
+// messages are never dropped by default
+func WithMaxRetries(maxRetryAttempts dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.maxRetryAttempts = maxRetryAttempts
+	}
+}
+
+// WithReadMaxRetries retries reading a page of the DLQ up to readMaxRetries times on transient errors, 3 by default
+func WithReadMaxRetries(readMaxRetries dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.readMaxRetries = readMaxRetries
+	}
+}
+
+// WithCheckpointGranularity sets how often a merge deletes the merged messages and moves the ack level,
+// PerPage by default
+func WithCheckpointGranularity(checkpointGranularity dynamicconfig.StringPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.checkpointGranularity = checkpointGranularity
+	}
+}
+
+// WithParallelism executes up to parallelism messages of a merged page concurrently, one at a time by default.
+// The results are still committed in the order of the page.
+func WithParallelism(parallelism dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.parallelism = parallelism
+	}
+}
+
+// WithBackpressure pauses the merges with an exponential backoff of up to a minute when the destination cluster
+// is throttling them, and retries a throttled message up to maxAttempts times after pausing. 0 disables the backpressure.
+func WithBackpressure(maxAttempts dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.backpressureMaxAttempts = maxAttempts
+	}
+}
+
+// WithInvalidTaskPolicy validates the messages before they are merged and handles the invalid ones per the policy,
+// the messages are not validated by default
+func WithInvalidTaskPolicy(invalidTaskPolicy dynamicconfig.StringPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.invalidTaskPolicy = invalidTaskPolicy
+	}
+}
+
+// WithBatchSize sets the maximum number of messages read from the DLQ in one page, 1000 by default
+func WithBatchSize(maxReadPageSize dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.maxReadPageSize = maxReadPageSize
+	}
+}
+
+// WithSizeEmitInterval sets how often the size of the DLQ is emitted, every minute by default
+func WithSizeEmitInterval(sizeEmitInterval dynamicconfig.DurationPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.sizeEmitInterval = sizeEmitInterval
+	}
+}
+
+// WithDeduplication skips messages probably merged within the last windowSize merged messages
+func WithDeduplication(windowSize dynamicconfig.IntPropertyFn, falsePositiveRate dynamicconfig.FloatPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.deduplicationWindowSize = windowSize
+		c.deduplicationFalsePositiveRate = falsePositiveRate
+	}
+}
+
+// WithSeenTaskIDs remembers the SourceTaskIDs of the last capacity messages executed by merges, so a message read
+// again before the ack level moved past it is skipped as a duplicate instead of being executed twice
+func WithSeenTaskIDs(capacity int) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.seenTaskIDsCapacity = capacity
+	}
+}
+
+// WithMessageTTL expires messages enqueued longer than the TTL ago
+func WithMessageTTL(messageTTL dynamicconfig.DurationPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.messageTTL = messageTTL
+	}
+}
+
+// WithPurgeBatchDelay sets the delay between two batches of a purge, 100ms by default
+func WithPurgeBatchDelay(purgeBatchDelay dynamicconfig.DurationPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.purgeBatchDelay = purgeBatchDelay
+	}
+}
+
+// WithPriorityMerge merges the messages of a page by priority instead of by message ID
+func WithPriorityMerge(priorityMergeEnabled dynamicconfig.BoolPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.priorityMergeEnabled = priorityMergeEnabled
+	}
+}
+
+// WithGroupedMerge merges the messages of a page group by group, the messages of a group are those with the same correlation ID
+func WithGroupedMerge(groupedMergeEnabled dynamicconfig.BoolPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.groupedMergeEnabled = groupedMergeEnabled
+	}
+}
+
+// WithSpeculativeRetry retries a message of a page which failed with a transient error in the background while
+// the rest of the page is merged, instead of nacking it. The page is only acknowledged once every retried message
+// succeeded, otherwise the page is rolled back and kept in the DLQ as a whole.
+func WithSpeculativeRetry(speculativeRetryEnabled dynamicconfig.BoolPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.speculativeRetryEnabled = speculativeRetryEnabled
+	}
+}
+
+// WithExecutionJournal journals the executed messages until they are deleted, so a merge restarted after a crash
+// does not apply them again. Every execution persists the journal twice.
+func WithExecutionJournal(executionJournalEnabled dynamicconfig.BoolPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.executionJournalEnabled = executionJournalEnabled
+	}
+}
+
+// WithVersionedAckLevel conditions the ack level updates on the version of the ack levels they were read at,
+// an update is retried up to maxConflictRetries times while the ack levels change version concurrently
+func WithVersionedAckLevel(enabled dynamicconfig.BoolPropertyFn, maxConflictRetries dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.versionedAckLevelEnabled = enabled
+		c.ackLevelMaxConflictRetries = maxConflictRetries
+	}
+}
+
+// WithSoftDelete marks handled messages as deleted instead of removing them
+func WithSoftDelete(softDeleteEnabled dynamicconfig.BoolPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.softDeleteEnabled = softDeleteEnabled
+	}
+}
+
+// WithLargeMessageThreshold sets the payload size above which messages are reported as large, 1MB by default
+func WithLargeMessageThreshold(largeMessageThresholdBytes dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.largeMessageThresholdBytes = largeMessageThresholdBytes
+	}
+}
+
+// WithStalledAckThreshold reports the ack level as stalled once it has not moved for the threshold
+func WithStalledAckThreshold(stalledAckThreshold dynamicconfig.DurationPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.stalledAckThreshold = stalledAckThreshold
+	}
+}
+
+// WithDepthAlertThreshold notifies the hooks while the DLQ holds more messages than the threshold, 0 disables it
+func WithDepthAlertThreshold(depthAlertThreshold dynamicconfig.IntPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.depthAlertThreshold = depthAlertThreshold
+	}
+}
+
+// WithMergeTimeout interrupts merging a page which takes longer than the timeout
+func WithMergeTimeout(mergeTimeout dynamicconfig.DurationPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.mergeTimeout = mergeTimeout
+	}
+}
+
+// WithDomainFilter only merges the messages of the domains accepted by the filter
+func WithDomainFilter(domainFilter DomainFilterFunc) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.domainFilter = domainFilter
+	}
+}
+
+// WithDomainPriority merges the messages of a page from the highest priority of their domain to the lowest,
+// e.g. by the SLA tier of the domains with NewSLATierPriorityFunc.
+// Pages are no longer merged in the order of the message IDs, as when merged WithPriorityMerge.
+func WithDomainPriority(priorityFunc PriorityFunc) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.priorityFunc = priorityFunc
+	}
+}
+
+// WithDistributedLocker makes the handler hold a lock of the locker while merging a shard of the DLQ,
+// so that the shard is not merged by two hosts at the same time
+func WithDistributedLocker(locker DistributedLocker) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.locker = locker
+	}
+}
+
+// WithNotificationHooks replaces the no-op hook of the handler with the hooks notified of the DLQ events
+func WithNotificationHooks(hooks ...NotificationHook) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.notificationHooks = hooks
+	}
+}
+
+// WithSimulationMode lets SimulateMerge simulate merges on the domains read from domainManager,
+// which is never written to, the simulation mode is disabled by default
+func WithSimulationMode(domainManager persistence.DomainManager) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.simulationDomains = domainManager
+	}
+}
+
+// WithArchival archives the purged messages to the URI with the archiver before they are deleted while archival is enabled,
+// a batch which fails to be archived is not purged. The messages are not archived by default.
+func WithArchival(archiver DLQArchiver, enabled dynamicconfig.BoolPropertyFn, URI dynamicconfig.StringPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.archiver = archiver
+		c.archivalEnabled = enabled
+		c.archivalURI = URI
+	}
+}
+
+// WithGrowthRateMonitor samples the depth of the DLQ at the sample interval and pauses the merges of the DLQ shards
+// while the moving average of its growth rate exceeds maxGrowthRate messages per second, 0 disables the pause
+func WithGrowthRateMonitor(sampleInterval dynamicconfig.DurationPropertyFn, maxGrowthRate dynamicconfig.FloatPropertyFn) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.growthRateSampleInterval = sampleInterval
+		c.maxGrowthRate = maxGrowthRate
+	}
+}
+
+// WithStateChangeObserver calls the observer on every transition of the state of the handler,
+// e.g. to wait for the handler to be idle before shutting it down
+func WithStateChangeObserver(observer StateChangeFunc) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.stateChangeObserver = observer
+	}
+}
+
+// WithDestinationCluster tags the circuit breaker metrics of the handler with the cluster
+// the replication tasks of the DLQ are applied to
+func WithDestinationCluster(clusterName string) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.destinationCluster = clusterName
+	}
+}
+
+// WithTimeSource sets the time source of the handler, the real time by default
+func WithTimeSource(timeSource clock.TimeSource) DLQOption {
+	return func(c *dlqMessageHandlerConfig) {
+		c.timeSource = timeSource
+	}
+}
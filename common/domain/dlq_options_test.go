@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+)
+
+func TestNewDLQMessageHandler_Defaults(t *testing.T) {
+	handler, ok := NewDLQMessageHandler(nil, nil, loggerimpl.NewNopLogger()).(*dlqMessageHandlerImpl)
+	require.True(t, ok)
+
+	assert.Equal(t, DefaultConsumerGroup, handler.consumerGroup)
+	assert.Equal(t, 0, handler.maxRetryAttempts())
+	assert.Equal(t, 1000, handler.maxReadPageSize())
+	assert.Equal(t, 3, handler.readMaxRetries())
+	assert.Equal(t, string(CheckpointGranularityPerPage), handler.checkpointGranularity())
+	assert.Equal(t, 1, handler.parallelism())
+	assert.Equal(t, 100*time.Millisecond, handler.purgeBatchDelay())
+	assert.Zero(t, handler.messageTTL())
+	assert.Zero(t, handler.mergeTimeout())
+	assert.False(t, handler.priorityMerge())
+	assert.False(t, handler.softDelete())
+	assert.Nil(t, handler.domainFilter)
+	assert.NotNil(t, handler.timeSource)
+	assert.NotNil(t, handler.metricsClient)
+}
+
+func TestNewDLQMessageHandler_Options(t *testing.T) {
+	timeSource := clock.NewEventTimeSource()
+	metricsClient := metrics.NewClient(tally.NewTestScope("test", nil), metrics.Frontend)
+	handler := NewDLQMessageHandler(
+		nil,
+		nil,
+		loggerimpl.NewNopLogger(),
+		WithConsumerGroup("consumer"),
+		WithMaxRetries(dynamicconfig.GetIntPropertyFn(5)),
+		WithBatchSize(dynamicconfig.GetIntPropertyFn(100)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+		WithMergeTimeout(dynamicconfig.GetDurationPropertyFn(time.Minute)),
+		WithSoftDelete(dynamicconfig.GetBoolPropertyFn(true)),
+		WithDomainFilter(NewDomainBlocklistFilter([]string{"blocked"})),
+		WithTimeSource(timeSource),
+		WithMetricsClient(metricsClient),
+	).(*dlqMessageHandlerImpl)
+
+	assert.Equal(t, "consumer", handler.consumerGroup)
+	assert.Equal(t, 5, handler.maxRetryAttempts())
+	assert.Equal(t, 100, handler.maxReadPageSize())
+	assert.Equal(t, 0, handler.readMaxRetries())
+	assert.Equal(t, time.Minute, handler.mergeTimeout())
+	assert.True(t, handler.softDelete())
+	assert.False(t, handler.domainFilter("blocked"))
+	assert.Equal(t, timeSource, handler.timeSource)
+	assert.Equal(t, metricsClient, handler.metricsClient)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// dlqPausedStateLoadTimeout bounds the read of the persisted paused state when the handler starts
+	dlqPausedStateLoadTimeout = 10 * time.Second
+)
+
+// Pause pauses the merges of the DLQ shards until Resume is called, also across restarts of the handler.
+// The merges in progress stop once they merged their current page, Pause returns after they stopped
+// or with the context error if they are still running once the context is done.
+func (d *dlqMessageHandlerImpl) Pause(ctx context.Context) error {
+	if err := d.replicationQueue.UpdateDLQProcessingPaused(ctx, d.consumerGroup, true); err != nil {
+		return err
+	}
+	if atomic.CompareAndSwapInt32(&d.paused, 0, 1) {
+		d.logger.Info("Domain DLQ processing paused.")
+	}
+	return d.stateMachine.waitIdle(ctx)
+}
+
+// Resume resumes the merges of the DLQ shards paused by Pause
+func (d *dlqMessageHandlerImpl) Resume(ctx context.Context) error {
+	if err := d.replicationQueue.UpdateDLQProcessingPaused(ctx, d.consumerGroup, false); err != nil {
+		return err
+	}
+	if atomic.CompareAndSwapInt32(&d.paused, 1, 0) {
+		d.logger.Info("Domain DLQ processing resumed.")
+	}
+	return nil
+}
+
+// loadPausedState restores the paused state persisted by the last Pause or Resume
+func (d *dlqMessageHandlerImpl) loadPausedState(ctx context.Context) error {
+	paused, err := d.replicationQueue.GetDLQProcessingPaused(ctx, d.consumerGroup)
+	if err != nil {
+		return err
+	}
+	if paused {
+		atomic.StoreInt32(&d.paused, 1)
+		d.logger.Info("Domain DLQ processing is paused.")
+	}
+	return nil
+}
+
+func (d *dlqMessageHandlerImpl) isPaused() bool {
+	return atomic.LoadInt32(&d.paused) == 1
+}
+
+// checkPaused stops the merges of the DLQ shards before they read the next page while processing is paused
+func (d *dlqMessageHandlerImpl) checkPaused() error {
+	if d.isPaused() {
+		return ErrDLQProcessingPaused
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/uber/cadence/common/quotas"
+)
+
+const (
+	dlqRateLimiterRPSTTL   = time.Minute
+	dlqRateLimiterMinBurst = 1
+)
+
+type (
+	// dlqRateLimiter limits the rate of the merges and replays of DLQ messages to the rate of the dynamic config.
+	// quotas.DynamicRateLimiter passes the rate to the rate limiter on every call, which allocates it for each message,
+	// the rate is only passed here when it differs from the rate the rate limiter applies.
+	dlqRateLimiter struct {
+		rps     quotas.RPSFunc
+		limiter *quotas.RateLimiter
+	}
+)
+
+var _ quotas.Limiter = (*dlqRateLimiter)(nil)
+
+func newDLQRateLimiter(rps quotas.RPSFunc) *dlqRateLimiter {
+	initialRPS := rps()
+	return &dlqRateLimiter{
+		rps:     rps,
+		limiter: quotas.NewRateLimiter(&initialRPS, dlqRateLimiterRPSTTL, dlqRateLimiterMinBurst),
+	}
+}
+
+func (l *dlqRateLimiter) Allow() bool {
+	l.updateRPS()
+	return l.limiter.Allow()
+}
+
+func (l *dlqRateLimiter) Wait(ctx context.Context) error {
+	l.updateRPS()
+	return l.limiter.Wait(ctx)
+}
+
+func (l *dlqRateLimiter) Reserve() *rate.Reservation {
+	l.updateRPS()
+	return l.limiter.Reserve()
+}
+
+func (l *dlqRateLimiter) updateRPS() {
+	if rps := l.rps(); rps != l.limiter.Limit() {
+		// the rate is only allocated when it changes
+		newRPS := rps
+		l.limiter.UpdateMaxDispatch(&newRPS)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDLQRateLimiter_UpdatesRPS(t *testing.T) {
+	rps := 100.0
+	limiter := newDLQRateLimiter(func() float64 { return rps })
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Equal(t, 100.0, limiter.limiter.Limit())
+
+	// a lower rate is applied by the next call
+	rps = 10.0
+	assert.True(t, limiter.Allow())
+	assert.Equal(t, 10.0, limiter.limiter.Limit())
+}
+
+func TestDLQRateLimiter_NoAllocationsWhenRPSUnchanged(t *testing.T) {
+	limiter := newDLQRateLimiter(func() float64 { return 1000000 })
+	allocs := testing.AllocsPerRun(100, func() {
+		limiter.Allow()
+	})
+	assert.Zero(t, allocs)
+}
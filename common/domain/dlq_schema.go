@@ -0,0 +1,216 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/uber/cadence/common/codec"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// DLQSchemaVersionLegacy is the version of DLQ messages persisted as bare thrift before the envelope was introduced
+	DLQSchemaVersionLegacy = 0
+	// DLQSchemaVersion1 is the version of DLQ messages persisted as thrift wrapped in an envelope
+	DLQSchemaVersion1 = 1
+	// DLQSchemaVersionEncrypted is the version of DLQ messages whose thrift payload is encrypted
+	// with the key named in the envelope, it is only written when the queue is configured with encryption
+	DLQSchemaVersionEncrypted = 2
+	// DLQSchemaVersionCurrent is the version new DLQ messages are persisted with
+	DLQSchemaVersionCurrent = DLQSchemaVersion1
+)
+
+type (
+	// ErrUnknownSchemaVersion is returned when a DLQ message was persisted with a schema version
+	// this host does not know how to decode, e.g. by a newer host during a rolling upgrade
+	ErrUnknownSchemaVersion struct {
+		SchemaVersion int
+	}
+
+	// dlqEnvelope fields added within a schema version must be optional,
+	// so hosts of the same version which do not know them yet can still decode the message
+	dlqEnvelope struct {
+		SchemaVersion int    `json:"schemaVersion"`
+		Payload       []byte `json:"payload"`
+		SourceCluster string `json:"sourceCluster,omitempty"`
+		Priority      int    `json:"priority,omitempty"`
+		KeyID         string `json:"keyID,omitempty"`
+		Nonce         []byte `json:"nonce,omitempty"`
+		// RetryAfter is the unix time in nanoseconds before which a nacked message is not merged
+		RetryAfter int64 `json:"retryAfter,omitempty"`
+		// CompressionCodec is the codec the payload is compressed with before it is encrypted, empty if it is not compressed
+		CompressionCodec string `json:"compressionCodec,omitempty"`
+		CorrelationID    string `json:"correlationID,omitempty"`
+		// Format is the serialization format of the payload, empty for thrift
+		Format DLQSerializationFormat `json:"format,omitempty"`
+	}
+)
+
+var dlqPayloadEncoder = codec.NewThriftRWEncoder()
+
+func (e *ErrUnknownSchemaVersion) Error() string {
+	return fmt.Sprintf("unknown DLQ message schema version %v", e.SchemaVersion)
+}
+
+// EncodeReplicationTask serializes the replication task into a DLQ envelope of the current schema version
+func EncodeReplicationTask(task *types.ReplicationTask) ([]byte, error) {
+	return encodeReplicationTask(task, DLQSerializationFormatThrift, nil, dlqCompressionDisabled)
+}
+
+// encodeReplicationTask serializes the task in the format, compresses payloads larger than the compression threshold,
+// unless it is negative, and encrypts the payload with the current key of the provider, if there is one
+func encodeReplicationTask(
+	task *types.ReplicationTask,
+	format DLQSerializationFormat,
+	keys EncryptionKeyProvider,
+	compressionThreshold int,
+) ([]byte, error) {
+
+	payload, err := marshalDLQPayload(task, format)
+	if err != nil {
+		return nil, err
+	}
+	envelope := dlqEnvelope{
+		SchemaVersion: DLQSchemaVersionCurrent,
+		Payload:       payload,
+		SourceCluster: task.SourceCluster,
+		Priority:      task.Priority,
+		CorrelationID: task.CorrelationID,
+	}
+	// thrift messages do not record their format, so hosts which do not know the field yet can still decode them
+	if format != "" && format != DLQSerializationFormatThrift {
+		envelope.Format = format
+	}
+	if compressionThreshold >= 0 && len(payload) > compressionThreshold {
+		envelope.Payload = compressDLQPayload(payload)
+		envelope.CompressionCodec = DLQCompressionCodecZstd
+	}
+	if !task.RetryAfter.IsZero() {
+		envelope.RetryAfter = task.RetryAfter.UnixNano()
+	}
+	if keys != nil {
+		keyID, key, err := keys.CurrentKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get DLQ encryption key: %w", err)
+		}
+		if err := envelope.encrypt(keyID, key); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(envelope)
+}
+
+// DecodeReplicationTask deserializes a replication task from a DLQ envelope according to its schema version
+func DecodeReplicationTask(envelope []byte) (*types.ReplicationTask, error) {
+	task, _, err := decodeReplicationTask(envelope)
+	return task, err
+}
+
+func decodeReplicationTask(data []byte) (*types.ReplicationTask, int, error) {
+	envelope, err := unmarshalDLQEnvelope(data)
+	if err != nil {
+		return nil, envelope.SchemaVersion, err
+	}
+	schemaVersion := envelope.SchemaVersion
+	if err := envelope.decrypt(nil); err != nil {
+		return nil, schemaVersion, err
+	}
+	task, err := envelope.decode()
+	return task, schemaVersion, err
+}
+
+func unmarshalDLQEnvelope(data []byte) (dlqEnvelope, error) {
+	var envelope dlqEnvelope
+	// a thrift payload never starts with a JSON object, so anything else is a legacy message
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return dlqEnvelope{
+			SchemaVersion: DLQSchemaVersionLegacy,
+			Payload:       data,
+		}, nil
+	}
+	if envelope.SchemaVersion == DLQSchemaVersionLegacy {
+		return envelope, &ErrUnknownSchemaVersion{SchemaVersion: envelope.SchemaVersion}
+	}
+	return envelope, nil
+}
+
+// encrypt replaces the payload with its ciphertext under the given key
+func (e *dlqEnvelope) encrypt(keyID string, key []byte) error {
+	nonce, ciphertext, err := encryptDLQPayload(key, e.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt DLQ message: %w", err)
+	}
+	e.SchemaVersion = DLQSchemaVersionEncrypted
+	e.KeyID = keyID
+	e.Nonce = nonce
+	e.Payload = ciphertext
+	return nil
+}
+
+// decrypt replaces an encrypted payload with its plaintext, it is a no-op for unencrypted envelopes
+func (e *dlqEnvelope) decrypt(keys EncryptionKeyProvider) error {
+	if e.SchemaVersion != DLQSchemaVersionEncrypted {
+		return nil
+	}
+	if keys == nil {
+		return fmt.Errorf("DLQ message is encrypted with key %v but no encryption key is configured", e.KeyID)
+	}
+	key, err := keys.GetKey(e.KeyID)
+	if err != nil {
+		return fmt.Errorf("failed to get DLQ encryption key %v: %w", e.KeyID, err)
+	}
+	payload, err := decryptDLQPayload(key, e.Nonce, e.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt DLQ message with key %v: %w", e.KeyID, err)
+	}
+	e.SchemaVersion = DLQSchemaVersionCurrent
+	e.KeyID = ""
+	e.Nonce = nil
+	e.Payload = payload
+	return nil
+}
+
+func (e *dlqEnvelope) decode() (*types.ReplicationTask, error) {
+	switch e.SchemaVersion {
+	case DLQSchemaVersionLegacy, DLQSchemaVersion1:
+		// the envelope keeps the compressed payload, so it can be re-encrypted as is
+		payload, err := decompressDLQPayload(e.CompressionCodec, e.Payload)
+		if err != nil {
+			return nil, err
+		}
+		task, err := unmarshalDLQPayload(payload, e.Format)
+		if err != nil {
+			return nil, err
+		}
+		task.SourceCluster = e.SourceCluster
+		task.Priority = e.Priority
+		task.CorrelationID = e.CorrelationID
+		if e.RetryAfter != 0 {
+			task.RetryAfter = time.Unix(0, e.RetryAfter)
+		}
+		return task, nil
+	default:
+		return nil, &ErrUnknownSchemaVersion{SchemaVersion: e.SchemaVersion}
+	}
+}
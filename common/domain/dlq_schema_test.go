@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/codec"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/thrift"
+)
+
+func TestEncodeDecodeReplicationTask(t *testing.T) {
+	task := &types.ReplicationTask{
+		TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID: 10,
+		DomainTaskAttributes: &types.DomainTaskAttributes{
+			DomainOperation: types.DomainOperationUpdate.Ptr(),
+			ID:              "some random domain ID",
+			FailoverVersion: 100,
+		},
+		CreationTime: common.Int64Ptr(time.Now().UnixNano()),
+	}
+
+	envelope, err := EncodeReplicationTask(task)
+	require.NoError(t, err)
+	assert.Contains(t, string(envelope), `"schemaVersion":1`)
+
+	decoded, err := DecodeReplicationTask(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, task, decoded)
+}
+
+func TestEncodeDecodeReplicationTask_SourceCluster(t *testing.T) {
+	task := &types.ReplicationTask{
+		TaskType:      types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID:  10,
+		SourceCluster: "cluster-b",
+	}
+
+	envelope, err := EncodeReplicationTask(task)
+	require.NoError(t, err)
+	assert.Contains(t, string(envelope), `"sourceCluster":"cluster-b"`)
+
+	decoded, err := DecodeReplicationTask(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, "cluster-b", decoded.SourceCluster)
+
+	// the source cluster is not part of the replication wire format
+	assert.Empty(t, thrift.ToReplicationTask(thrift.FromReplicationTask(task)).SourceCluster)
+}
+
+func TestDecodeReplicationTask_Legacy(t *testing.T) {
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistory.Ptr()}
+	payload, err := codec.NewThriftRWEncoder().Encode(thrift.FromReplicationTask(task))
+	require.NoError(t, err)
+
+	decoded, schemaVersion, err := decodeReplicationTask(payload)
+	require.NoError(t, err)
+	assert.Equal(t, DLQSchemaVersionLegacy, schemaVersion)
+	assert.Equal(t, task, decoded)
+}
+
+func TestDecodeReplicationTask_UnknownSchemaVersion(t *testing.T) {
+	for _, envelope := range []string{
+		`{"schemaVersion":0,"payload":""}`,
+		`{"schemaVersion":3,"payload":""}`,
+	} {
+		_, err := DecodeReplicationTask([]byte(envelope))
+		assert.IsType(t, &ErrUnknownSchemaVersion{}, err, envelope)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/uber/cadence/.gen/go/replicator"
+	sharedv1 "github.com/uber/cadence/.gen/proto/shared/v1"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/proto"
+	"github.com/uber/cadence/common/types/mapper/thrift"
+)
+
+const (
+	// DLQSerializationFormatThrift encodes the replication tasks of DLQ messages with thrift, the format of
+	// the messages persisted before the format was recorded in the envelope
+	DLQSerializationFormatThrift DLQSerializationFormat = "thrift"
+	// DLQSerializationFormatJSON encodes the replication tasks of DLQ messages as JSON, for external tooling
+	DLQSerializationFormatJSON DLQSerializationFormat = "json"
+	// DLQSerializationFormatProto encodes the replication tasks of DLQ messages with protobuf
+	DLQSerializationFormatProto DLQSerializationFormat = "proto"
+)
+
+type (
+	// DLQSerializationFormat is the encoding of the replication task in the payload of a DLQ message
+	DLQSerializationFormat string
+)
+
+// WithSerializationFormat encodes the replication tasks of new DLQ messages in the format, thrift by default.
+// Messages are decoded in the format recorded in their envelope regardless of this option, so the format
+// can be changed while the DLQ holds messages, once every host reading the DLQ knows the new format.
+func WithSerializationFormat(format DLQSerializationFormat) ReplicationQueueOption {
+	return func(q *replicationQueueImpl) {
+		q.serializationFormat = format
+	}
+}
+
+// marshalDLQPayload encodes the fields of the task which are part of the replication wire format,
+// the fields only kept by the DLQ are persisted in the envelope
+func marshalDLQPayload(task *types.ReplicationTask, format DLQSerializationFormat) ([]byte, error) {
+	if format != DLQSerializationFormatJSON && isDLQOnlyTaskType(task.GetTaskType()) {
+		return nil, fmt.Errorf("replication task type %v can only be persisted in the %v DLQ message serialization format",
+			task.GetTaskType(), DLQSerializationFormatJSON)
+	}
+
+	switch format {
+	case "", DLQSerializationFormatThrift:
+		return dlqPayloadEncoder.Encode(thrift.FromReplicationTask(task))
+	case DLQSerializationFormatJSON:
+		wireTask := *task
+		wireTask.SourceCluster = ""
+		wireTask.Priority = DLQMessagePriorityDefault
+		wireTask.CorrelationID = ""
+		return json.Marshal(&wireTask)
+	case DLQSerializationFormatProto:
+		return proto.FromReplicationTask(task).Marshal()
+	default:
+		return nil, fmt.Errorf("unknown DLQ message serialization format %v", format)
+	}
+}
+
+func unmarshalDLQPayload(payload []byte, format DLQSerializationFormat) (*types.ReplicationTask, error) {
+	switch format {
+	case "", DLQSerializationFormatThrift:
+		var replicationTask replicator.ReplicationTask
+		if err := dlqPayloadEncoder.Decode(payload, &replicationTask); err != nil {
+			return nil, err
+		}
+		return thrift.ToReplicationTask(&replicationTask), nil
+	case DLQSerializationFormatJSON:
+		var task types.ReplicationTask
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return nil, err
+		}
+		return &task, nil
+	case DLQSerializationFormatProto:
+		var replicationTask sharedv1.ReplicationTask
+		if err := replicationTask.Unmarshal(payload); err != nil {
+			return nil, err
+		}
+		return proto.ToReplicationTask(&replicationTask), nil
+	default:
+		return nil, fmt.Errorf("unknown DLQ message serialization format %v", format)
+	}
+}
+
+// isDLQOnlyTaskType returns true for the replication task types which are not part of the replication wire format,
+// the tasks of the types cannot be encoded with thrift or protobuf
+func isDLQOnlyTaskType(taskType types.ReplicationTaskType) bool {
+	return taskType == types.ReplicationTaskTypeWorkflowReset
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+var dlqSerializationFormats = []DLQSerializationFormat{
+	DLQSerializationFormatThrift,
+	DLQSerializationFormatJSON,
+	DLQSerializationFormatProto,
+}
+
+func testSerializedReplicationTasks() []*types.ReplicationTask {
+	return []*types.ReplicationTask{
+		{
+			TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID: 11,
+			DomainTaskAttributes: &types.DomainTaskAttributes{
+				DomainOperation: types.DomainOperationUpdate.Ptr(),
+				ID:              "domain-id",
+				Info:            &types.DomainInfo{Name: "domain", Status: types.DomainStatusRegistered.Ptr(), Data: map[string]string{"k": "v"}},
+				// the proto API dropped EmitMetric, the proto mapper always sets it
+				Config: &types.DomainConfiguration{WorkflowExecutionRetentionPeriodInDays: 7, EmitMetric: true},
+				ReplicationConfig: &types.DomainReplicationConfiguration{
+					ActiveClusterName: "active",
+					Clusters:          []*types.ClusterReplicationConfiguration{{ClusterName: "active"}, {ClusterName: "standby"}},
+				},
+				ConfigVersion:   2,
+				FailoverVersion: 10,
+			},
+			CreationTime:  common.Int64Ptr(1600000000000000000),
+			SourceCluster: "standby",
+			Priority:      DLQMessagePriorityHigh,
+			CorrelationID: "domain-id",
+		},
+		{
+			TaskType:     types.ReplicationTaskTypeHistoryV2.Ptr(),
+			SourceTaskID: 12,
+			HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{
+				DomainID:            "domain-id",
+				WorkflowID:          "workflow-id",
+				RunID:               "run-id",
+				VersionHistoryItems: []*types.VersionHistoryItem{{EventID: 5, Version: 10}},
+				Events:              &types.DataBlob{EncodingType: types.EncodingTypeThriftRW.Ptr(), Data: []byte("events")},
+			},
+			SourceCluster: "standby",
+		},
+		{
+			TaskType:     types.ReplicationTaskTypeFailoverMarker.Ptr(),
+			SourceTaskID: 13,
+			FailoverMarkerAttributes: &types.FailoverMarkerAttributes{
+				DomainID:        "domain-id",
+				FailoverVersion: 20,
+				CreationTime:    common.Int64Ptr(1600000000000000000),
+			},
+		},
+	}
+}
+
+func TestDLQSerializationFormat_RoundTrip(t *testing.T) {
+	for _, format := range dlqSerializationFormats {
+		t.Run(string(format), func(t *testing.T) {
+			for _, task := range testSerializedReplicationTasks() {
+				data, err := encodeReplicationTask(task, format, nil, dlqCompressionDisabled)
+				require.NoError(t, err)
+
+				var envelope dlqEnvelope
+				require.NoError(t, json.Unmarshal(data, &envelope))
+				if format == DLQSerializationFormatThrift {
+					assert.Empty(t, envelope.Format)
+				} else {
+					assert.Equal(t, format, envelope.Format)
+				}
+
+				decoded, err := DecodeReplicationTask(data)
+				require.NoError(t, err)
+				assert.Equal(t, task, decoded)
+			}
+		})
+	}
+}
+
+func TestDLQSerializationFormat_JSONPayload(t *testing.T) {
+	task := testSerializedReplicationTasks()[0]
+
+	data, err := encodeReplicationTask(task, DLQSerializationFormatJSON, nil, dlqCompressionDisabled)
+	require.NoError(t, err)
+
+	// external tooling can read the payload as plain JSON, the fields only kept by the DLQ are in the envelope
+	var envelope dlqEnvelope
+	require.NoError(t, json.Unmarshal(data, &envelope))
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(envelope.Payload, &payload))
+	assert.Equal(t, float64(11), payload["sourceTaskId"])
+	assert.NotContains(t, payload, "sourceCluster")
+	assert.Equal(t, "standby", envelope.SourceCluster)
+}
+
+func TestDLQSerializationFormat_WorkflowResetTask(t *testing.T) {
+	task := &types.ReplicationTask{
+		TaskType:     types.ReplicationTaskTypeWorkflowReset.Ptr(),
+		SourceTaskID: 14,
+		WorkflowResetTaskAttributes: &types.WorkflowResetTaskAttributes{
+			DomainID:   "domain-id",
+			WorkflowID: "workflow-id",
+			RunID:      "run-id",
+			RequestID:  "request-id",
+		},
+	}
+
+	data, err := encodeReplicationTask(task, DLQSerializationFormatJSON, nil, dlqCompressionDisabled)
+	require.NoError(t, err)
+	decoded, err := DecodeReplicationTask(data)
+	require.NoError(t, err)
+	assert.Equal(t, task, decoded)
+
+	// the task type is not part of the replication wire format
+	for _, format := range []DLQSerializationFormat{DLQSerializationFormatThrift, DLQSerializationFormatProto} {
+		_, err := encodeReplicationTask(task, format, nil, dlqCompressionDisabled)
+		assert.Error(t, err, format)
+	}
+}
+
+func TestDLQSerializationFormat_CompressedAndEncrypted(t *testing.T) {
+	keys := newTestEncryptionKeyProvider("key-1")
+	for _, format := range dlqSerializationFormats {
+		t.Run(string(format), func(t *testing.T) {
+			task := testSerializedReplicationTasks()[0]
+			data, err := encodeReplicationTask(task, format, keys, 0)
+			require.NoError(t, err)
+
+			envelope, err := unmarshalDLQEnvelope(data)
+			require.NoError(t, err)
+			require.NoError(t, envelope.decrypt(keys))
+			decoded, err := envelope.decode()
+			require.NoError(t, err)
+			assert.Equal(t, task, decoded)
+		})
+	}
+}
+
+func TestDLQSerializationFormat_Unknown(t *testing.T) {
+	task := testSerializedReplicationTasks()[0]
+
+	_, err := encodeReplicationTask(task, "avro", nil, dlqCompressionDisabled)
+	assert.Error(t, err)
+
+	_, err = DecodeReplicationTask([]byte(`{"schemaVersion":1,"payload":"","format":"avro"}`))
+	assert.Error(t, err)
+}
+
+func TestDLQSerializationFormat_MixedFormatQueue(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tasks := testSerializedReplicationTasks()
+	mockQueue := persistence.NewMockQueueManager(controller)
+	var messages []*persistence.QueueMessage
+	mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ *int32, payload []byte) error {
+			messages = append(messages, &persistence.QueueMessage{ID: tasks[len(messages)].SourceTaskID, Payload: payload})
+			return nil
+		},
+	).Times(len(tasks))
+
+	// the format is changed while the DLQ holds messages, e.g. during a rolling migration
+	for i, task := range tasks {
+		replicationQueue := NewReplicationQueue(
+			mockQueue,
+			"testCluster",
+			metrics.NewNoopMetricsClient(),
+			loggerimpl.NewNopLogger(),
+			WithSerializationFormat(dlqSerializationFormats[i%len(dlqSerializationFormats)]),
+		)
+		require.NoError(t, replicationQueue.PublishToDLQ(context.Background(), task))
+	}
+	mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(10), int64(20), 100, nil).Return(messages, nil, nil).Times(1)
+
+	// the queue reads the messages of every format, whatever format it writes
+	replicationQueue := NewReplicationQueue(mockQueue, "testCluster", metrics.NewNoopMetricsClient(), loggerimpl.NewNopLogger())
+	decoded, _, err := replicationQueue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 10, 20, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(tasks))
+	for i, task := range decoded {
+		expected := *tasks[i]
+		expected.SchemaVersion = DLQSchemaVersionCurrent
+		expected.PayloadSize = len(messages[i].Payload)
+		if expected.Priority == DLQMessagePriorityDefault {
+			expected.Priority = getDLQMessagePriority(tasks[i])
+		}
+		if expected.CorrelationID == "" {
+			expected.CorrelationID = getDLQCorrelationID(tasks[i])
+		}
+		assert.Equal(t, &expected, task)
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+)
+
+const (
+	dlqShardMembershipUpdateListenerName = "domain-dlq-shard-listener"
+	dlqShardReassignInterval             = time.Minute
+)
+
+type (
+	shardedDLQMessageHandler struct {
+		DLQMessageHandler
+
+		shardCount         dynamicconfig.IntPropertyFn
+		mergeInterval      dynamicconfig.DurationPropertyFn
+		membershipResolver membership.Resolver
+		logger             log.Logger
+		status             int32
+		done               chan struct{}
+		membershipUpdateCh chan *membership.ChangedEvent
+		shutdownWG         sync.WaitGroup
+
+		sync.Mutex
+		workers           map[int]context.CancelFunc
+		workersShardCount int
+		// resumed is closed and replaced on Resume to wake the merge workers up
+		resumed chan struct{}
+	}
+)
+
+// NewShardedDLQMessageHandler returns a DLQMessageHandler which partitions the domain DLQ by message ID
+// and runs a background merge worker for each shard owned by this host in the frontend membership ring.
+func NewShardedDLQMessageHandler(
+	handler DLQMessageHandler,
+	shardCount dynamicconfig.IntPropertyFn,
+	mergeInterval dynamicconfig.DurationPropertyFn,
+	membershipResolver membership.Resolver,
+	logger log.Logger,
+) DLQMessageHandler {
+	return &shardedDLQMessageHandler{
+		DLQMessageHandler:  handler,
+		shardCount:         shardCount,
+		mergeInterval:      mergeInterval,
+		membershipResolver: membershipResolver,
+		logger:             logger,
+		done:               make(chan struct{}),
+		membershipUpdateCh: make(chan *membership.ChangedEvent, 10),
+		workers:            make(map[int]context.CancelFunc),
+		resumed:            make(chan struct{}),
+	}
+}
+
+// Start starts the wrapped DLQ handler and the merge workers of the owned shards
+func (h *shardedDLQMessageHandler) Start() {
+	if !atomic.CompareAndSwapInt32(&h.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	h.DLQMessageHandler.Start()
+	if err := h.membershipResolver.Subscribe(service.Frontend, dlqShardMembershipUpdateListenerName, h.membershipUpdateCh); err != nil {
+		h.logger.Error("subscribing to membership resolver", tag.Error(err))
+	}
+	h.reassignShards()
+
+	h.shutdownWG.Add(1)
+	go h.shardManagementPump()
+	h.logger.Info("Domain DLQ shard handler started.")
+}
+
+// Stop stops the merge workers and the wrapped DLQ handler
+func (h *shardedDLQMessageHandler) Stop() {
+	if err := h.Close(); err != nil {
+		h.logger.Warn("Domain DLQ shard handler timed out on shutdown.", tag.LifeCycleStopTimedout)
+	}
+}
+
+// Close stops the merge workers and the wrapped DLQ handler, waiting up to a minute for them to exit
+func (h *shardedDLQMessageHandler) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), dlqShutdownTimeout)
+	defer cancel()
+	return h.Shutdown(ctx)
+}
+
+// Shutdown stops the merge workers and the wrapped DLQ handler and waits for them to exit
+func (h *shardedDLQMessageHandler) Shutdown(ctx context.Context) error {
+	if atomic.CompareAndSwapInt32(&h.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		if err := h.membershipResolver.Unsubscribe(service.Frontend, dlqShardMembershipUpdateListenerName); err != nil {
+			h.logger.Error("unsubscribing from membership resolver", tag.Error(err), tag.OperationFailed)
+		}
+		close(h.done)
+		h.Lock()
+		h.stopWorkersLocked()
+		h.Unlock()
+	}
+
+	if err := awaitShutdown(ctx, &h.shutdownWG); err != nil {
+		return err
+	}
+	if err := h.DLQMessageHandler.Shutdown(ctx); err != nil {
+		return err
+	}
+	h.logger.Info("Domain DLQ shard handler stopped.")
+	return nil
+}
+
+// Resume resumes the merges paused by Pause and starts a merge round of the owned shards right away
+func (h *shardedDLQMessageHandler) Resume(ctx context.Context) error {
+	if err := h.DLQMessageHandler.Resume(ctx); err != nil {
+		return err
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	close(h.resumed)
+	h.resumed = make(chan struct{})
+	return nil
+}
+
+func (h *shardedDLQMessageHandler) shardManagementPump() {
+	defer h.shutdownWG.Done()
+
+	reassignTicker := time.NewTicker(dlqShardReassignInterval)
+	defer reassignTicker.Stop()
+
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-reassignTicker.C:
+			h.reassignShards()
+		case changedEvent := <-h.membershipUpdateCh:
+			h.logger.Info("Ring membership changed, reassigning domain DLQ shards",
+				tag.NumberProcessed(len(changedEvent.HostsAdded)),
+				tag.NumberDeleted(len(changedEvent.HostsRemoved)),
+				tag.Number(int64(len(changedEvent.HostsUpdated))))
+			h.reassignShards()
+		}
+	}
+}
+
+// reassignShards starts a merge worker for each shard this host owns in the membership ring
+// and stops the workers of shards which moved to another host
+func (h *shardedDLQMessageHandler) reassignShards() {
+	shardCount := h.shardCount()
+	owned := make(map[int]struct{})
+	if shardCount > 0 {
+		self, err := h.membershipResolver.WhoAmI()
+		if err != nil {
+			h.logger.Error("Failed to look up self in membership ring for domain DLQ shards", tag.Error(err))
+			return
+		}
+		for shardID := 0; shardID < shardCount; shardID++ {
+			info, err := h.membershipResolver.Lookup(service.Frontend, getDLQShardKey(shardID))
+			if err != nil {
+				h.logger.Error("Failed to look up owner of domain DLQ shard", tag.ShardID(shardID), tag.Error(err))
+				continue
+			}
+			if info.Identity() == self.Identity() {
+				owned[shardID] = struct{}{}
+			}
+		}
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	select {
+	case <-h.done:
+		return
+	default:
+	}
+
+	// message IDs map to different shards once the shard count changes
+	if shardCount != h.workersShardCount {
+		h.stopWorkersLocked()
+		h.workersShardCount = shardCount
+	}
+	for shardID, cancel := range h.workers {
+		if _, ok := owned[shardID]; !ok {
+			h.logger.Info("Domain DLQ shard moved to another host", tag.ShardID(shardID))
+			cancel()
+			delete(h.workers, shardID)
+		}
+	}
+	for shardID := range owned {
+		if _, ok := h.workers[shardID]; ok {
+			continue
+		}
+		h.logger.Info("Domain DLQ shard acquired", tag.ShardID(shardID))
+		ctx, cancel := context.WithCancel(context.Background())
+		h.workers[shardID] = cancel
+		h.shutdownWG.Add(1)
+		go h.mergeShardLoop(ctx, shardID, shardCount, h.resumed)
+	}
+}
+
+func (h *shardedDLQMessageHandler) mergeShardLoop(
+	ctx context.Context,
+	shardID int,
+	shardCount int,
+	resumed <-chan struct{},
+) {
+	defer h.shutdownWG.Done()
+
+	timer := time.NewTimer(h.mergeInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-resumed:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		}
+		// taken before the merge round, so a Resume during the round starts another one
+		resumed = h.resumedCh()
+
+		switch err := h.MergeShard(ctx, shardID, shardCount); {
+		case err == nil || ctx.Err() != nil:
+		case err == ErrLockHeld:
+			h.logger.Info("Domain DLQ shard is merged by another host", tag.ShardID(shardID))
+		case err == ErrDLQMergePaused:
+			h.logger.Info("Domain DLQ shard merge is paused as the DLQ grows too fast", tag.ShardID(shardID))
+		case err == ErrDLQProcessingPaused:
+			h.logger.Info("Domain DLQ shard merge is paused", tag.ShardID(shardID))
+		default:
+			h.logger.Error("Failed to merge domain DLQ shard", tag.ShardID(shardID), tag.Error(err))
+		}
+		timer.Reset(h.mergeInterval())
+	}
+}
+
+func (h *shardedDLQMessageHandler) resumedCh() <-chan struct{} {
+	h.Lock()
+	defer h.Unlock()
+
+	return h.resumed
+}
+
+func (h *shardedDLQMessageHandler) stopWorkersLocked() {
+	for shardID, cancel := range h.workers {
+		cancel()
+		delete(h.workers, shardID)
+	}
+}
+
+// ownedShards returns the shards this host currently runs merge workers for
+func (h *shardedDLQMessageHandler) ownedShards() map[int]struct{} {
+	h.Lock()
+	defer h.Unlock()
+
+	shards := make(map[int]struct{}, len(h.workers))
+	for shardID := range h.workers {
+		shards[shardID] = struct{}{}
+	}
+	return shards
+}
+
+func getDLQShardKey(shardID int) string {
+	return fmt.Sprintf("domain-dlq-shard-%v", shardID)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+)
+
+func TestShardedDLQMessageHandler_ReassignOnMembershipChange(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	self := membership.NewHostInfo("self")
+	other := membership.NewHostInfo("other")
+	mockResolver := membership.NewMockResolver(controller)
+	mockResolver.EXPECT().WhoAmI().Return(self, nil).AnyTimes()
+	handler := newTestShardedDLQMessageHandler(controller, mockResolver, 4)
+
+	for shardID, owner := range []membership.HostInfo{self, self, other, other} {
+		mockResolver.EXPECT().Lookup(service.Frontend, getDLQShardKey(shardID)).Return(owner, nil).Times(1)
+	}
+	handler.reassignShards()
+	assert.Equal(t, map[int]struct{}{0: {}, 1: {}}, handler.ownedShards())
+
+	// the other host left the ring, its shards move to this host
+	for shardID := 0; shardID < 4; shardID++ {
+		mockResolver.EXPECT().Lookup(service.Frontend, getDLQShardKey(shardID)).Return(self, nil).Times(1)
+	}
+	handler.reassignShards()
+	assert.Equal(t, map[int]struct{}{0: {}, 1: {}, 2: {}, 3: {}}, handler.ownedShards())
+
+	// a new host joined the ring and took over some of the shards
+	for shardID, owner := range []membership.HostInfo{other, self, self, other} {
+		mockResolver.EXPECT().Lookup(service.Frontend, getDLQShardKey(shardID)).Return(owner, nil).Times(1)
+	}
+	handler.reassignShards()
+	assert.Equal(t, map[int]struct{}{1: {}, 2: {}}, handler.ownedShards())
+
+	handler.Lock()
+	handler.stopWorkersLocked()
+	handler.Unlock()
+	handler.shutdownWG.Wait()
+}
+
+func TestShardedDLQMessageHandler_ShardCountChange(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	self := membership.NewHostInfo("self")
+	mockResolver := membership.NewMockResolver(controller)
+	mockResolver.EXPECT().WhoAmI().Return(self, nil).AnyTimes()
+	mockResolver.EXPECT().Lookup(service.Frontend, gomock.Any()).Return(self, nil).AnyTimes()
+	shardCount := 2
+	handler := newTestShardedDLQMessageHandler(controller, mockResolver, 0)
+	handler.shardCount = func(...dynamicconfig.FilterOption) int { return shardCount }
+
+	handler.reassignShards()
+	assert.Equal(t, map[int]struct{}{0: {}, 1: {}}, handler.ownedShards())
+
+	// message IDs map to different shards, every worker is restarted
+	shardCount = 3
+	handler.reassignShards()
+	assert.Equal(t, map[int]struct{}{0: {}, 1: {}, 2: {}}, handler.ownedShards())
+	assert.Equal(t, 3, handler.workersShardCount)
+
+	shardCount = 0
+	handler.reassignShards()
+	assert.Empty(t, handler.ownedShards())
+	handler.shutdownWG.Wait()
+}
+
+func TestShardedDLQMessageHandler_WorkersMergeOwnedShards(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	self := membership.NewHostInfo("self")
+	other := membership.NewHostInfo("other")
+	mockResolver := membership.NewMockResolver(controller)
+	mockResolver.EXPECT().Subscribe(service.Frontend, dlqShardMembershipUpdateListenerName, gomock.Any()).Return(nil).Times(1)
+	mockResolver.EXPECT().Unsubscribe(service.Frontend, dlqShardMembershipUpdateListenerName).Return(nil).Times(1)
+	mockResolver.EXPECT().WhoAmI().Return(self, nil).AnyTimes()
+	mockResolver.EXPECT().Lookup(service.Frontend, getDLQShardKey(0)).Return(other, nil).AnyTimes()
+	mockResolver.EXPECT().Lookup(service.Frontend, getDLQShardKey(1)).Return(self, nil).AnyTimes()
+
+	mockHandler := NewMockDLQMessageHandler(controller)
+	mockHandler.EXPECT().Start().Times(1)
+	mockHandler.EXPECT().Shutdown(gomock.Any()).Return(nil).Times(1)
+	merged := make(chan struct{}, 1)
+	mockHandler.EXPECT().MergeShard(gomock.Any(), 1, 2).DoAndReturn(
+		func(_ interface{}, _ int, _ int) error {
+			select {
+			case merged <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	).MinTimes(1)
+
+	handler := NewShardedDLQMessageHandler(
+		mockHandler,
+		dynamicconfig.GetIntPropertyFn(2),
+		dynamicconfig.GetDurationPropertyFn(time.Millisecond),
+		mockResolver,
+		loggerimpl.NewNopLogger(),
+	)
+	handler.Start()
+	select {
+	case <-merged:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "shard was not merged")
+	}
+	handler.Stop()
+}
+
+func TestShardedDLQMessageHandler_ResumeStartsMergeRound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	self := membership.NewHostInfo("self")
+	mockResolver := membership.NewMockResolver(controller)
+	mockResolver.EXPECT().Subscribe(service.Frontend, dlqShardMembershipUpdateListenerName, gomock.Any()).Return(nil).Times(1)
+	mockResolver.EXPECT().Unsubscribe(service.Frontend, dlqShardMembershipUpdateListenerName).Return(nil).Times(1)
+	mockResolver.EXPECT().WhoAmI().Return(self, nil).AnyTimes()
+	mockResolver.EXPECT().Lookup(service.Frontend, getDLQShardKey(0)).Return(self, nil).AnyTimes()
+
+	mockHandler := NewMockDLQMessageHandler(controller)
+	mockHandler.EXPECT().Start().Times(1)
+	mockHandler.EXPECT().Shutdown(gomock.Any()).Return(nil).Times(1)
+	mockHandler.EXPECT().Resume(gomock.Any()).Return(nil).Times(1)
+	merged := make(chan struct{}, 1)
+	mockHandler.EXPECT().MergeShard(gomock.Any(), 0, 1).DoAndReturn(
+		func(_ interface{}, _ int, _ int) error {
+			merged <- struct{}{}
+			return nil
+		},
+	).Times(1)
+
+	// the merge interval is too long for a merge round to start unless it is resumed
+	handler := NewShardedDLQMessageHandler(
+		mockHandler,
+		dynamicconfig.GetIntPropertyFn(1),
+		dynamicconfig.GetDurationPropertyFn(time.Hour),
+		mockResolver,
+		loggerimpl.NewNopLogger(),
+	)
+	handler.Start()
+	require.NoError(t, handler.Resume(context.Background()))
+	select {
+	case <-merged:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "shard was not merged on resume")
+	}
+	handler.Stop()
+}
+
+func TestShardedDLQMessageHandler_ResumeFailed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockResolver := membership.NewMockResolver(controller)
+	handler := newTestShardedDLQMessageHandler(controller, mockResolver, 1)
+	resumeErr := errors.New("resume failed")
+	handler.DLQMessageHandler.(*MockDLQMessageHandler).EXPECT().Resume(gomock.Any()).Return(resumeErr).Times(1)
+	resumed := handler.resumedCh()
+
+	assert.Equal(t, resumeErr, handler.Resume(context.Background()))
+	select {
+	case <-resumed:
+		assert.Fail(t, "merge round started although the handler was not resumed")
+	default:
+	}
+}
+
+func newTestShardedDLQMessageHandler(
+	controller *gomock.Controller,
+	resolver membership.Resolver,
+	shardCount int,
+) *shardedDLQMessageHandler {
+	return NewShardedDLQMessageHandler(
+		NewMockDLQMessageHandler(controller),
+		dynamicconfig.GetIntPropertyFn(shardCount),
+		dynamicconfig.GetDurationPropertyFn(time.Hour),
+		resolver,
+		loggerimpl.NewNopLogger(),
+	).(*shardedDLQMessageHandler)
+}
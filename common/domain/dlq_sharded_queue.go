@@ -0,0 +1,871 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/dgryski/go-farm"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// shardedReplicationQueue partitions the domain replication DLQ into shards by the workflow run ID of the messages,
+	// so they are not all written to a single partition of the database. The shards are replication queues with their
+	// own message IDs and ack levels, the message with ID l in shard s has the ID l*numShards+s in the sharded DLQ.
+	shardedReplicationQueue struct {
+		// ReplicationQueue is shard 0, which also keeps the replication messages and the DLQ metadata which is not per shard
+		ReplicationQueue
+
+		shards []ReplicationQueue
+		logger log.Logger
+	}
+
+	// shardedDLQPageToken is the page token of a read of the sharded DLQ, with the read position of every shard
+	shardedDLQPageToken struct {
+		Shards []dlqShardReadPosition `json:"shards"`
+	}
+
+	// dlqShardReadPosition is where the read of the sharded DLQ continues in a shard
+	dlqShardReadPosition struct {
+		// Done is true once every message of the shard in the read range was returned
+		Done bool `json:"done,omitempty"`
+		// FirstMessageID is the exclusive message ID of the shard its read starts from
+		FirstMessageID int64 `json:"firstMessageID"`
+		// PageToken skips the pages of the read of the shard from FirstMessageID which were already returned
+		PageToken []byte `json:"pageToken,omitempty"`
+	}
+
+	// readDLQShardFn reads a page of the DLQ messages of the shard, the message IDs are the IDs in the shard
+	readDLQShardFn func(
+		ctx context.Context,
+		shard ReplicationQueue,
+		firstMessageID int64,
+		lastMessageID int64,
+		pageSize int,
+		pageToken []byte,
+	) ([]*types.ReplicationTask, []byte, error)
+
+	dlqConsumer struct {
+		consumerGroup string
+		taskType      types.ReplicationTaskType
+	}
+)
+
+var _ ReplicationQueue = (*shardedReplicationQueue)(nil)
+
+// NewShardedReplicationQueue creates a ReplicationQueue whose DLQ is sharded by workflow run ID into a shard per queue manager,
+// queues[0] is the queue manager of the replication queue so the DLQ messages enqueued before it was sharded stay readable.
+// The messages without a workflow run ID, such as the domain replication tasks, are enqueued to shard 0.
+func NewShardedReplicationQueue(
+	queues []persistence.QueueManager,
+	clusterName string,
+	metricsClient metrics.Client,
+	logger log.Logger,
+	opts ...ReplicationQueueOption,
+) ReplicationQueue {
+	if len(queues) == 1 {
+		return NewReplicationQueue(queues[0], clusterName, metricsClient, logger, opts...)
+	}
+
+	shards := make([]ReplicationQueue, 0, len(queues))
+	for shardID, queue := range queues {
+		shards = append(shards, NewReplicationQueue(queue, clusterName, metricsClient, logger.WithTags(tag.ShardID(shardID)), opts...))
+	}
+	return newShardedReplicationQueue(shards, logger)
+}
+
+func newShardedReplicationQueue(shards []ReplicationQueue, logger log.Logger) *shardedReplicationQueue {
+	return &shardedReplicationQueue{
+		ReplicationQueue: shards[0],
+		shards:           shards,
+		logger:           logger,
+	}
+}
+
+func (q *shardedReplicationQueue) Start() {
+	for _, shard := range q.shards {
+		shard.Start()
+	}
+}
+
+func (q *shardedReplicationQueue) Stop() {
+	for _, shard := range q.shards {
+		shard.Stop()
+	}
+}
+
+func (q *shardedReplicationQueue) PublishToDLQ(
+	ctx context.Context,
+	message interface{},
+) error {
+	task, ok := message.(*types.ReplicationTask)
+	if !ok {
+		return errors.New("wrong message type")
+	}
+
+	return q.shards[q.getDLQShard(task)].PublishToDLQ(ctx, task)
+}
+
+// EnqueueBatch publishes the tasks to the DLQ in a persistence round trip per shard,
+// the tasks of a shard get consecutive message IDs of the shard in the order given
+func (q *shardedReplicationQueue) EnqueueBatch(
+	ctx context.Context,
+	tasks []*types.ReplicationTask,
+) error {
+
+	batches := make([][]*types.ReplicationTask, len(q.shards))
+	for _, task := range tasks {
+		shardID := q.getDLQShard(task)
+		batches[shardID] = append(batches[shardID], task)
+	}
+	return q.fanOut(func(shardID int, shard ReplicationQueue) error {
+		if len(batches[shardID]) == 0 {
+			return nil
+		}
+		return shard.EnqueueBatch(ctx, batches[shardID])
+	})
+}
+
+func (q *shardedReplicationQueue) GetMessagesFromDLQ(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	return q.readDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken, func(
+		ctx context.Context,
+		shard ReplicationQueue,
+		firstMessageID int64,
+		lastMessageID int64,
+		pageSize int,
+		pageToken []byte,
+	) ([]*types.ReplicationTask, []byte, error) {
+		return shard.GetMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID, pageSize, pageToken)
+	})
+}
+
+// GetMessagesFromDLQByDomain returns the DLQ messages of a single domain
+func (q *shardedReplicationQueue) GetMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	return q.readDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken, func(
+		ctx context.Context,
+		shard ReplicationQueue,
+		firstMessageID int64,
+		lastMessageID int64,
+		pageSize int,
+		pageToken []byte,
+	) ([]*types.ReplicationTask, []byte, error) {
+		return shard.GetMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken)
+	})
+}
+
+// GetMessagesFromDLQByTimeRange returns the DLQ messages enqueued between startTime, inclusive, and endTime, exclusive
+func (q *shardedReplicationQueue) GetMessagesFromDLQByTimeRange(
+	ctx context.Context,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	return q.readDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken, func(
+		ctx context.Context,
+		shard ReplicationQueue,
+		firstMessageID int64,
+		lastMessageID int64,
+		pageSize int,
+		pageToken []byte,
+	) ([]*types.ReplicationTask, []byte, error) {
+		return shard.GetMessagesFromDLQByTimeRange(ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken)
+	})
+}
+
+// GetMessagesFromDLQGrouped returns a page of DLQ messages grouped by their correlation ID,
+// the messages of a group are ordered by message ID and a message without a correlation ID is a group of its own
+func (q *shardedReplicationQueue) GetMessagesFromDLQGrouped(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) (map[string][]*types.ReplicationTask, []byte, error) {
+
+	replicationTasks, token, err := q.GetMessagesFromDLQ(ctx, AllTaskTypes, firstMessageID, lastMessageID, pageSize, pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return groupDLQMessages(replicationTasks), token, nil
+}
+
+// UpdateDLQAckLevel sets the ack level of every shard to its last message ID up to lastProcessedMessageID
+func (q *shardedReplicationQueue) UpdateDLQAckLevel(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	lastProcessedMessageID int64,
+) error {
+
+	return q.fanOut(func(shardID int, shard ReplicationQueue) error {
+		return shard.UpdateDLQAckLevel(ctx, taskType, consumerGroup, q.toShardMessageID(shardID, lastProcessedMessageID))
+	})
+}
+
+// CompareAndSwapDLQAckLevel sets the ack levels of the shards if they are still at the ack levels of expectedLevel,
+// either on all the shards or, if a shard fails to swap, on none of them
+func (q *shardedReplicationQueue) CompareAndSwapDLQAckLevel(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	expectedLevel int64,
+	newLevel int64,
+) error {
+
+	ackLevels, err := q.getShardDLQAckLevels(ctx, taskType, consumerGroup)
+	if err != nil {
+		return err
+	}
+	if q.getDLQAckLevel(ackLevels) != expectedLevel {
+		return ErrDLQAckLevelConflict
+	}
+
+	newAckLevels := q.getShardDLQAckLevelsOf(newLevel)
+	return q.swapShardDLQAckLevels(ctx, taskType, consumerGroup, ackLevels, newAckLevels, func(shardID int, shard ReplicationQueue) error {
+		return shard.CompareAndSwapDLQAckLevel(ctx, taskType, consumerGroup, ackLevels[shardID], newAckLevels[shardID])
+	})
+}
+
+func (q *shardedReplicationQueue) GetDLQAckLevel(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+) (int64, error) {
+
+	ackLevels, err := q.getShardDLQAckLevels(ctx, taskType, consumerGroup)
+	if err != nil {
+		return common.EmptyMessageID, err
+	}
+	return q.getDLQAckLevel(ackLevels), nil
+}
+
+// UpdateDLQAckLevelWithVersion sets the ack levels of the shards if none of the shards changed version since expectedVersion was read,
+// it returns ErrVersionConflict if any of them was updated since. The ack levels are set on all the shards or, if a shard fails
+// to update, on none of them.
+func (q *shardedReplicationQueue) UpdateDLQAckLevelWithVersion(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	newLevel int64,
+	expectedVersion int64,
+) error {
+
+	ackLevels, versions, err := q.getShardDLQAckLevelsWithVersion(ctx, taskType, consumerGroup)
+	if err != nil {
+		return err
+	}
+	if getDLQAckLevelVersion(versions) != expectedVersion {
+		return ErrVersionConflict
+	}
+
+	newAckLevels := q.getShardDLQAckLevelsOf(newLevel)
+	return q.swapShardDLQAckLevels(ctx, taskType, consumerGroup, ackLevels, newAckLevels, func(shardID int, shard ReplicationQueue) error {
+		return shard.UpdateDLQAckLevelWithVersion(ctx, taskType, consumerGroup, newAckLevels[shardID], versions[shardID])
+	})
+}
+
+// GetDLQAckLevelWithVersion returns the ack level with the version of the DLQ ack levels,
+// which is the sum of the versions of the shards so it changes whenever the ack levels of any shard change
+func (q *shardedReplicationQueue) GetDLQAckLevelWithVersion(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+) (int64, int64, error) {
+
+	ackLevels, versions, err := q.getShardDLQAckLevelsWithVersion(ctx, taskType, consumerGroup)
+	if err != nil {
+		return common.EmptyMessageID, 0, err
+	}
+	return q.getDLQAckLevel(ackLevels), getDLQAckLevelVersion(versions), nil
+}
+
+// GetDLQConsumerAckLevels returns the ack levels of all the consumer groups of the DLQ,
+// ordered by consumer group and task type
+func (q *shardedReplicationQueue) GetDLQConsumerAckLevels(
+	ctx context.Context,
+) ([]*DLQConsumerAckLevel, error) {
+
+	shardConsumers := make([][]*DLQConsumerAckLevel, len(q.shards))
+	if err := q.fanOut(func(shardID int, shard ReplicationQueue) (err error) {
+		shardConsumers[shardID], err = shard.GetDLQConsumerAckLevels(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	// the ack levels of a consumer are missing from the shards it never acknowledged messages of
+	ackLevels := make(map[dlqConsumer][]int64)
+	updatedAt := make(map[dlqConsumer]time.Time)
+	for shardID, consumers := range shardConsumers {
+		for _, consumer := range consumers {
+			key := dlqConsumer{consumerGroup: consumer.ConsumerGroup, taskType: consumer.TaskType}
+			if _, ok := ackLevels[key]; !ok {
+				ackLevels[key] = make([]int64, len(q.shards))
+				for i := range ackLevels[key] {
+					ackLevels[key][i] = common.EmptyMessageID
+				}
+			}
+			ackLevels[key][shardID] = consumer.AckLevel
+			if consumer.UpdatedAt.After(updatedAt[key]) {
+				updatedAt[key] = consumer.UpdatedAt
+			}
+		}
+	}
+
+	consumers := make([]*DLQConsumerAckLevel, 0, len(ackLevels))
+	for key, shardAckLevels := range ackLevels {
+		consumers = append(consumers, &DLQConsumerAckLevel{
+			ConsumerGroup: key.consumerGroup,
+			TaskType:      key.taskType,
+			AckLevel:      q.getDLQAckLevel(shardAckLevels),
+			UpdatedAt:     updatedAt[key],
+		})
+	}
+	sortDLQConsumerAckLevels(consumers)
+	return consumers, nil
+}
+
+// SnapshotDLQAckLevel returns a snapshot of the ack levels of every task type and consumer group of the DLQ
+func (q *shardedReplicationQueue) SnapshotDLQAckLevel(
+	ctx context.Context,
+) (*DLQAckLevelSnapshot, error) {
+
+	// the snapshot of shard 0 has the DLQ metadata which is not per shard
+	snapshot, err := q.shards[0].SnapshotDLQAckLevel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	consumers, err := q.GetDLQConsumerAckLevels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, consumer := range consumers {
+		snapshot.AckLevels[getDLQAckLevelKey(consumer.TaskType, consumer.ConsumerGroup)] = consumer.AckLevel
+	}
+	return snapshot, nil
+}
+
+// RestoreDLQAckLevel writes the ack levels of the snapshot back to the shards, after validating it against the merge history
+func (q *shardedReplicationQueue) RestoreDLQAckLevel(
+	ctx context.Context,
+	snapshot *DLQAckLevelSnapshot,
+) error {
+
+	if err := validateDLQAckLevelSnapshot(ctx, q, snapshot); err != nil {
+		return err
+	}
+
+	for key, ackLevel := range snapshot.AckLevels {
+		var err error
+		if key == dlqReplayAckLevelKey {
+			err = q.UpdateDLQReplayAckLevel(ctx, ackLevel)
+		} else if taskType, consumerGroup, ok := parseDLQAckLevelKey(key); ok {
+			err = q.UpdateDLQAckLevel(ctx, taskType, consumerGroup, ackLevel)
+		} else {
+			err = errors.New("unknown ack level key")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore dlq ack level %v: %v", key, err)
+		}
+	}
+	return nil
+}
+
+func (q *shardedReplicationQueue) RangeDeleteMessagesFromDLQ(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+
+	return q.fanOutRange(firstMessageID, lastMessageID, func(shard ReplicationQueue, firstMessageID int64, lastMessageID int64) error {
+		return shard.RangeDeleteMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID)
+	})
+}
+
+// RangeDeleteMessagesFromDLQByDomain deletes the DLQ messages of a single domain in the range
+func (q *shardedReplicationQueue) RangeDeleteMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+
+	return q.fanOutRange(firstMessageID, lastMessageID, func(shard ReplicationQueue, firstMessageID int64, lastMessageID int64) error {
+		return shard.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID)
+	})
+}
+
+// RangeSoftDeleteMessagesFromDLQ marks the DLQ messages in the range as deleted, they are no longer read
+// but kept until DeleteSoftDeletedMessagesFromDLQ removes them
+func (q *shardedReplicationQueue) RangeSoftDeleteMessagesFromDLQ(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+
+	return q.fanOutRange(firstMessageID, lastMessageID, func(shard ReplicationQueue, firstMessageID int64, lastMessageID int64) error {
+		return shard.RangeSoftDeleteMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID)
+	})
+}
+
+func (q *shardedReplicationQueue) DeleteMessageFromDLQ(
+	ctx context.Context,
+	messageID int64,
+) error {
+
+	shardID, shardMessageID := q.getDLQMessageShard(messageID)
+	return q.shards[shardID].DeleteMessageFromDLQ(ctx, shardMessageID)
+}
+
+// NackMessage moves the DLQ message to the retry queue at the tail of its shard,
+// where it is not merged before retryAfter elapsed. The message gets a new message ID.
+func (q *shardedReplicationQueue) NackMessage(
+	ctx context.Context,
+	messageID int64,
+	retryAfter time.Duration,
+) error {
+
+	shardID, shardMessageID := q.getDLQMessageShard(messageID)
+	return q.shards[shardID].NackMessage(ctx, shardMessageID, retryAfter)
+}
+
+// DeleteSoftDeletedMessagesFromDLQ removes the DLQ messages soft deleted before deletedBefore
+func (q *shardedReplicationQueue) DeleteSoftDeletedMessagesFromDLQ(
+	ctx context.Context,
+	deletedBefore time.Time,
+) error {
+
+	return q.fanOut(func(_ int, shard ReplicationQueue) error {
+		return shard.DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore)
+	})
+}
+
+func (q *shardedReplicationQueue) IncrementDLQMessageAttempts(
+	ctx context.Context,
+	messageID int64,
+) (int, error) {
+
+	shardID, shardMessageID := q.getDLQMessageShard(messageID)
+	return q.shards[shardID].IncrementDLQMessageAttempts(ctx, shardMessageID)
+}
+
+func (q *shardedReplicationQueue) GetDLQSize(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+) (int64, error) {
+
+	sizes := make([]int64, len(q.shards))
+	if err := q.fanOut(func(shardID int, shard ReplicationQueue) (err error) {
+		sizes[shardID], err = shard.GetDLQSize(ctx, taskType)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, shardSize := range sizes {
+		size += shardSize
+	}
+	return size, nil
+}
+
+// GetDLQMessageTypeHistogram returns the number of DLQ messages of each task type,
+// messages enqueued before the task type was persisted are not counted
+func (q *shardedReplicationQueue) GetDLQMessageTypeHistogram(
+	ctx context.Context,
+) (map[types.ReplicationTaskType]int64, error) {
+
+	shardHistograms := make([]map[types.ReplicationTaskType]int64, len(q.shards))
+	if err := q.fanOut(func(shardID int, shard ReplicationQueue) (err error) {
+		shardHistograms[shardID], err = shard.GetDLQMessageTypeHistogram(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	histogram := make(map[types.ReplicationTaskType]int64)
+	for _, shardHistogram := range shardHistograms {
+		for taskType, count := range shardHistogram {
+			histogram[taskType] += count
+		}
+	}
+	return histogram, nil
+}
+
+// readDLQ reads a page of the messages of all the shards in the order of their message IDs. Every shard is read a page at
+// a time, and only the messages up to the last message read from the shards which have more messages are returned,
+// so that the messages of their next pages come after the messages returned.
+func (q *shardedReplicationQueue) readDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+	read readDLQShardFn,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	positions, err := q.deserializeDLQPageToken(firstMessageID, pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pages := make([][]*types.ReplicationTask, len(q.shards))
+	tokens := make([][]byte, len(q.shards))
+	if err := q.fanOut(func(shardID int, shard ReplicationQueue) (err error) {
+		if positions[shardID].Done {
+			return nil
+		}
+		pages[shardID], tokens[shardID], err = q.readDLQShard(ctx, shardID, positions[shardID], lastMessageID, pageSize, read)
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	lastReadMessageID := int64(math.MaxInt64)
+	for shardID, page := range pages {
+		if len(tokens[shardID]) > 0 && page[len(page)-1].SourceTaskID < lastReadMessageID {
+			lastReadMessageID = page[len(page)-1].SourceTaskID
+		}
+	}
+	var messages []*types.ReplicationTask
+	for _, page := range pages {
+		for _, message := range page {
+			if message.SourceTaskID <= lastReadMessageID {
+				messages = append(messages, message)
+			}
+		}
+	}
+	sortDLQMessagesByID(messages)
+	if len(messages) > pageSize {
+		messages = messages[:pageSize]
+	}
+
+	returned := make([]int, len(q.shards))
+	for _, message := range messages {
+		shardID, _ := q.getDLQMessageShard(message.SourceTaskID)
+		returned[shardID]++
+	}
+	done := true
+	for shardID, page := range pages {
+		position := &positions[shardID]
+		switch {
+		case position.Done:
+		case returned[shardID] == len(page) && len(tokens[shardID]) == 0:
+			position.Done = true
+		case returned[shardID] == len(page):
+			position.PageToken = tokens[shardID]
+		case returned[shardID] > 0:
+			// the rest of the page is read again, from the last message returned
+			_, position.FirstMessageID = q.getDLQMessageShard(page[returned[shardID]-1].SourceTaskID)
+			position.PageToken = nil
+		}
+		done = done && position.Done
+	}
+	if done {
+		return messages, nil, nil
+	}
+
+	token, err := json.Marshal(shardedDLQPageToken{Shards: positions})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode sharded dlq page token: %v", err)
+	}
+	return messages, token, nil
+}
+
+// readDLQShard reads the next page of the shard with messages, the message IDs of the page are the IDs in the sharded DLQ
+func (q *shardedReplicationQueue) readDLQShard(
+	ctx context.Context,
+	shardID int,
+	position dlqShardReadPosition,
+	lastMessageID int64,
+	pageSize int,
+	read readDLQShardFn,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	pageToken := position.PageToken
+	for {
+		page, token, err := read(ctx, q.shards[shardID], position.FirstMessageID, q.toShardMessageID(shardID, lastMessageID), pageSize, pageToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		// a page whose messages were all filtered out by the shard is not its last page
+		if len(page) == 0 && len(token) > 0 {
+			pageToken = token
+			continue
+		}
+
+		for _, message := range page {
+			message.SourceTaskID = q.toDLQMessageID(shardID, message.SourceTaskID)
+		}
+		return page, token, nil
+	}
+}
+
+// deserializeDLQPageToken returns the read positions of the shards, a nil token reads every shard from firstMessageID
+func (q *shardedReplicationQueue) deserializeDLQPageToken(
+	firstMessageID int64,
+	pageToken []byte,
+) ([]dlqShardReadPosition, error) {
+
+	if len(pageToken) == 0 {
+		positions := make([]dlqShardReadPosition, len(q.shards))
+		for shardID := range positions {
+			positions[shardID].FirstMessageID = q.toShardMessageID(shardID, firstMessageID)
+		}
+		return positions, nil
+	}
+
+	var token shardedDLQPageToken
+	if err := json.Unmarshal(pageToken, &token); err != nil {
+		return nil, &types.BadRequestError{Message: "invalid sharded domain DLQ page token"}
+	}
+	if len(token.Shards) != len(q.shards) {
+		return nil, &types.BadRequestError{Message: "sharded domain DLQ page token has a different number of shards"}
+	}
+	return token.Shards, nil
+}
+
+func (q *shardedReplicationQueue) getShardDLQAckLevels(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+) ([]int64, error) {
+
+	ackLevels := make([]int64, len(q.shards))
+	err := q.fanOut(func(shardID int, shard ReplicationQueue) (err error) {
+		ackLevels[shardID], err = shard.GetDLQAckLevel(ctx, taskType, consumerGroup)
+		return err
+	})
+	return ackLevels, err
+}
+
+func (q *shardedReplicationQueue) getShardDLQAckLevelsWithVersion(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+) ([]int64, []int64, error) {
+
+	ackLevels := make([]int64, len(q.shards))
+	versions := make([]int64, len(q.shards))
+	err := q.fanOut(func(shardID int, shard ReplicationQueue) (err error) {
+		ackLevels[shardID], versions[shardID], err = shard.GetDLQAckLevelWithVersion(ctx, taskType, consumerGroup)
+		return err
+	})
+	return ackLevels, versions, err
+}
+
+// getShardDLQAckLevelsOf returns the ack levels of the shards of the ack level of the sharded DLQ
+func (q *shardedReplicationQueue) getShardDLQAckLevelsOf(ackLevel int64) []int64 {
+	ackLevels := make([]int64, len(q.shards))
+	for shardID := range q.shards {
+		ackLevels[shardID] = q.toShardMessageID(shardID, ackLevel)
+	}
+	return ackLevels
+}
+
+// swapShardDLQAckLevels moves the ack levels of the shards from ackLevels to newAckLevels with swap, a shard at a time.
+// The shards do not share a database partition, so they cannot be updated in a single write. Instead, if a shard fails
+// to swap, the shards swapped before it are swapped back to their ack levels. Swapping a shard back only fails if its ack
+// level was moved again concurrently, the shards are then left disagreeing and the ack level of the sharded DLQ is the
+// ack level of the least advanced shard, so no message is skipped.
+func (q *shardedReplicationQueue) swapShardDLQAckLevels(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	ackLevels []int64,
+	newAckLevels []int64,
+	swap func(shardID int, shard ReplicationQueue) error,
+) error {
+
+	for shardID, shard := range q.shards {
+		err := swap(shardID, shard)
+		if err == nil {
+			continue
+		}
+		for swappedShardID := shardID - 1; swappedShardID >= 0; swappedShardID-- {
+			if rollbackErr := q.shards[swappedShardID].CompareAndSwapDLQAckLevel(
+				ctx,
+				taskType,
+				consumerGroup,
+				newAckLevels[swappedShardID],
+				ackLevels[swappedShardID],
+			); rollbackErr != nil {
+				q.logger.Error("Failed to roll back domain DLQ shard ack level.",
+					tag.ShardID(swappedShardID),
+					tag.DLQAckLevel(newAckLevels[swappedShardID]),
+					tag.DLQPreviousAckLevel(ackLevels[swappedShardID]),
+					tag.Error(rollbackErr))
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// getDLQAckLevel returns the ack level of the sharded DLQ from the ack levels of the shards. The ack levels of the shards
+// are updated together, so the ack level is the one they were all updated to, which is the ack level of the most advanced shard.
+// If the shards disagree, as after a failed update, it is the ack level of the least advanced shard so no message is skipped.
+func (q *shardedReplicationQueue) getDLQAckLevel(shardAckLevels []int64) int64 {
+	minAckLevel, maxAckLevel := int64(math.MaxInt64), int64(math.MinInt64)
+	for shardID, shardAckLevel := range shardAckLevels {
+		ackLevel := q.toDLQMessageID(shardID, shardAckLevel)
+		if ackLevel < minAckLevel {
+			minAckLevel = ackLevel
+		}
+		if ackLevel > maxAckLevel {
+			maxAckLevel = ackLevel
+		}
+	}
+
+	for shardID, shardAckLevel := range shardAckLevels {
+		if q.toShardMessageID(shardID, maxAckLevel) != shardAckLevel {
+			return minAckLevel
+		}
+	}
+	return maxAckLevel
+}
+
+// getDLQShard returns the shard of the DLQ message, the messages of a workflow run are all enqueued to the same shard
+func (q *shardedReplicationQueue) getDLQShard(task *types.ReplicationTask) int {
+	runID := getReplicationTaskRunID(task)
+	if runID == "" {
+		return 0
+	}
+	return int(farm.Fingerprint32([]byte(runID)) % uint32(len(q.shards)))
+}
+
+// getDLQMessageShard returns the shard of the message with the message ID of the sharded DLQ and its message ID in the shard
+func (q *shardedReplicationQueue) getDLQMessageShard(messageID int64) (int, int64) {
+	numShards := int64(len(q.shards))
+	shardID := int((messageID%numShards + numShards) % numShards)
+	return shardID, q.toShardMessageID(shardID, messageID)
+}
+
+// toDLQMessageID returns the message ID of the sharded DLQ of the message with the message ID of the shard
+func (q *shardedReplicationQueue) toDLQMessageID(shardID int, shardMessageID int64) int64 {
+	return shardMessageID*int64(len(q.shards)) + int64(shardID)
+}
+
+// toShardMessageID returns the largest message ID of the shard whose message ID in the sharded DLQ is at most messageID
+func (q *shardedReplicationQueue) toShardMessageID(shardID int, messageID int64) int64 {
+	numShards := int64(len(q.shards))
+	offset := messageID - int64(shardID)
+	if offset < 0 {
+		return (offset - numShards + 1) / numShards
+	}
+	return offset / numShards
+}
+
+// fanOutRange calls fn with the range of message IDs of every shard between firstMessageID, exclusive,
+// and lastMessageID, inclusive, the shards without a message ID in the range are skipped
+func (q *shardedReplicationQueue) fanOutRange(
+	firstMessageID int64,
+	lastMessageID int64,
+	fn func(shard ReplicationQueue, firstMessageID int64, lastMessageID int64) error,
+) error {
+
+	return q.fanOut(func(shardID int, shard ReplicationQueue) error {
+		shardFirstMessageID, shardLastMessageID := q.toShardMessageID(shardID, firstMessageID), q.toShardMessageID(shardID, lastMessageID)
+		if shardFirstMessageID >= shardLastMessageID {
+			return nil
+		}
+		return fn(shard, shardFirstMessageID, shardLastMessageID)
+	})
+}
+
+// fanOut calls fn with every shard concurrently and returns the error of the first shard which failed
+func (q *shardedReplicationQueue) fanOut(fn func(shardID int, shard ReplicationQueue) error) error {
+	errs := make([]error, len(q.shards))
+	var wg sync.WaitGroup
+	wg.Add(len(q.shards))
+	for shardID, shard := range q.shards {
+		go func(shardID int, shard ReplicationQueue) {
+			defer wg.Done()
+			errs[shardID] = fn(shardID, shard)
+		}(shardID, shard)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDLQAckLevelVersion returns the version of the sharded DLQ ack levels, which changes whenever the version of a shard changes
+func getDLQAckLevelVersion(shardVersions []int64) int64 {
+	var version int64
+	for _, shardVersion := range shardVersions {
+		version += shardVersion
+	}
+	return version
+}
+
+// getReplicationTaskRunID returns the workflow run ID of the replication task, it is empty for the tasks which are not of a workflow run
+func getReplicationTaskRunID(task *types.ReplicationTask) string {
+	switch {
+	case task.HistoryTaskV2Attributes != nil:
+		return task.HistoryTaskV2Attributes.GetRunID()
+	case task.SyncActivityTaskAttributes != nil:
+		return task.SyncActivityTaskAttributes.GetRunID()
+	case task.WorkflowResetTaskAttributes != nil:
+		return task.WorkflowResetTaskAttributes.GetRunID()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+// pagedDLQShard is a DLQ shard which returns its messages a page at a time, and keeps the ack levels in memory
+type pagedDLQShard struct {
+	ReplicationQueue
+	messageIDs []int64
+	ackLevels  map[string]int64
+	version    int64
+	// updateErr is returned by the updates of the ack levels instead of updating them
+	updateErr error
+}
+
+func (s *pagedDLQShard) GetMessagesFromDLQ(
+	_ context.Context,
+	_ types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+	var messageIDs []int64
+	for _, messageID := range s.messageIDs {
+		if messageID > firstMessageID && messageID <= lastMessageID {
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+	start := 0
+	if len(pageToken) > 0 {
+		start, _ = strconv.Atoi(string(pageToken))
+	}
+	end := common.MinInt(start+pageSize, len(messageIDs))
+
+	var page []*types.ReplicationTask
+	for _, messageID := range messageIDs[start:end] {
+		page = append(page, &types.ReplicationTask{SourceTaskID: messageID})
+	}
+	if end == len(messageIDs) {
+		return page, nil, nil
+	}
+	return page, []byte(strconv.Itoa(end)), nil
+}
+
+func (s *pagedDLQShard) GetDLQAckLevel(_ context.Context, taskType types.ReplicationTaskType, consumerGroup string) (int64, error) {
+	if ackLevel, ok := s.ackLevels[getDLQAckLevelKey(taskType, consumerGroup)]; ok {
+		return ackLevel, nil
+	}
+	return common.EmptyMessageID, nil
+}
+
+func (s *pagedDLQShard) CompareAndSwapDLQAckLevel(
+	_ context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	expectedLevel int64,
+	newLevel int64,
+) error {
+	ackLevel, _ := s.GetDLQAckLevel(context.Background(), taskType, consumerGroup)
+	if ackLevel != expectedLevel {
+		return ErrDLQAckLevelConflict
+	}
+	if s.updateErr != nil {
+		return s.updateErr
+	}
+	s.ackLevels[getDLQAckLevelKey(taskType, consumerGroup)] = newLevel
+	s.version++
+	return nil
+}
+
+func (s *pagedDLQShard) GetDLQAckLevelWithVersion(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string) (int64, int64, error) {
+	ackLevel, err := s.GetDLQAckLevel(ctx, taskType, consumerGroup)
+	return ackLevel, s.version, err
+}
+
+func (s *pagedDLQShard) UpdateDLQAckLevelWithVersion(
+	_ context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	newLevel int64,
+	expectedVersion int64,
+) error {
+	if s.version != expectedVersion {
+		return ErrVersionConflict
+	}
+	if s.updateErr != nil {
+		return s.updateErr
+	}
+	s.ackLevels[getDLQAckLevelKey(taskType, consumerGroup)] = newLevel
+	s.version++
+	return nil
+}
+
+func newPagedShardedReplicationQueue(shardMessageIDs ...[]int64) *shardedReplicationQueue {
+	shards := make([]ReplicationQueue, 0, len(shardMessageIDs))
+	for _, messageIDs := range shardMessageIDs {
+		shards = append(shards, &pagedDLQShard{messageIDs: messageIDs, ackLevels: make(map[string]int64)})
+	}
+	return newShardedReplicationQueue(shards, loggerimpl.NewNopLogger())
+}
+
+func TestShardedReplicationQueue_MessageIDs(t *testing.T) {
+	queue := newPagedShardedReplicationQueue(nil, nil, nil)
+
+	for shardID := 0; shardID < 3; shardID++ {
+		for _, shardMessageID := range []int64{common.EmptyMessageID, 0, 1, 7} {
+			messageID := queue.toDLQMessageID(shardID, shardMessageID)
+			actualShardID, actualShardMessageID := queue.getDLQMessageShard(messageID)
+			assert.Equal(t, shardID, actualShardID)
+			assert.Equal(t, shardMessageID, actualShardMessageID)
+		}
+		// every shard is read from its start before the first message of the sharded DLQ
+		assert.Equal(t, int64(common.EmptyMessageID), queue.toShardMessageID(shardID, common.EmptyMessageID))
+	}
+
+	// message 5 is message 1 of shard 2, the last message of shard 0 up to it is message 1 which is message 3
+	assert.Equal(t, int64(1), queue.toShardMessageID(0, 5))
+	assert.Equal(t, int64(1), queue.toShardMessageID(1, 5))
+	assert.Equal(t, int64(1), queue.toShardMessageID(2, 5))
+	assert.Equal(t, int64(0), queue.toShardMessageID(2, 4))
+	assert.Equal(t, int64(-1), queue.toShardMessageID(2, 1))
+}
+
+func TestShardedReplicationQueue_EnqueueBatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	shards := []ReplicationQueue{NewMockReplicationQueue(controller), NewMockReplicationQueue(controller), NewMockReplicationQueue(controller)}
+	queue := newShardedReplicationQueue(shards, loggerimpl.NewNopLogger())
+
+	domainTask := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr()}
+	var tasks []*types.ReplicationTask
+	batches := make(map[int][]*types.ReplicationTask)
+	for i := 0; i < 10; i++ {
+		task := &types.ReplicationTask{
+			TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+			HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{RunID: fmt.Sprintf("run-%d", i)},
+		}
+		tasks = append(tasks, task)
+		shardID := queue.getDLQShard(task)
+		// the messages of a run are always in the same shard
+		assert.Equal(t, shardID, queue.getDLQShard(&types.ReplicationTask{
+			SyncActivityTaskAttributes: &types.SyncActivityTaskAttributes{RunID: fmt.Sprintf("run-%d", i)},
+		}))
+		batches[shardID] = append(batches[shardID], task)
+	}
+	tasks = append(tasks, domainTask)
+	batches[0] = append(batches[0], domainTask)
+
+	for shardID, batch := range batches {
+		shards[shardID].(*MockReplicationQueue).EXPECT().EnqueueBatch(gomock.Any(), batch).Return(nil).Times(1)
+	}
+	require.NoError(t, queue.EnqueueBatch(context.Background(), tasks))
+}
+
+func TestShardedReplicationQueue_GetMessagesFromDLQ(t *testing.T) {
+	// the messages 0 to 11 of the sharded DLQ, with the messages 1, 3, 4, 7 and 10 deleted
+	shardMessageIDs := [][]int64{{0, 2, 3}, {}, {0, 1, 2, 3}}
+
+	for pageSize := 1; pageSize <= 9; pageSize++ {
+		queue := newPagedShardedReplicationQueue(shardMessageIDs...)
+
+		var messageIDs []int64
+		var pageToken []byte
+		for {
+			messages, token, err := queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, common.EmptyMessageID, 100, pageSize, pageToken)
+			require.NoError(t, err)
+			assert.True(t, len(messages) <= pageSize)
+			for _, message := range messages {
+				messageIDs = append(messageIDs, message.SourceTaskID)
+			}
+			if len(token) == 0 {
+				break
+			}
+			pageToken = token
+		}
+		assert.Equal(t, []int64{0, 2, 5, 6, 8, 9, 11}, messageIDs, "page size %d", pageSize)
+	}
+
+	// the read range applies to the message IDs of the sharded DLQ
+	queue := newPagedShardedReplicationQueue(shardMessageIDs...)
+	messages, token, err := queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 2, 9, 10, nil)
+	require.NoError(t, err)
+	assert.Nil(t, token)
+	var messageIDs []int64
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.SourceTaskID)
+	}
+	assert.Equal(t, []int64{5, 6, 8, 9}, messageIDs)
+
+	_, _, err = queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, common.EmptyMessageID, 100, 10, []byte("invalid"))
+	assert.IsType(t, &types.BadRequestError{}, err)
+	_, _, err = queue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, common.EmptyMessageID, 100, 10, []byte(`{"shards":[{}]}`))
+	assert.IsType(t, &types.BadRequestError{}, err)
+}
+
+func TestShardedReplicationQueue_DLQAckLevel(t *testing.T) {
+	queue := newPagedShardedReplicationQueue(nil, nil, nil)
+	ctx := context.Background()
+
+	ackLevel, err := queue.GetDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup)
+	require.NoError(t, err)
+	assert.Equal(t, int64(common.EmptyMessageID), ackLevel)
+
+	require.NoError(t, queue.CompareAndSwapDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup, common.EmptyMessageID, 7))
+	ackLevel, err = queue.GetDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), ackLevel)
+	for shardID, expected := range []int64{2, 2, 1} {
+		assert.Equal(t, expected, queue.shards[shardID].(*pagedDLQShard).ackLevels[getDLQAckLevelKey(AllTaskTypes, DefaultConsumerGroup)])
+	}
+
+	assert.Equal(t, ErrDLQAckLevelConflict, queue.CompareAndSwapDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup, 5, 10))
+
+	// a shard which was not updated holds the ack level back, so its messages are not skipped
+	queue.shards[1].(*pagedDLQShard).ackLevels[getDLQAckLevelKey(AllTaskTypes, DefaultConsumerGroup)] = 0
+	ackLevel, err = queue.GetDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), ackLevel)
+}
+
+func TestShardedReplicationQueue_DLQAckLevel_RollBack(t *testing.T) {
+	queue := newPagedShardedReplicationQueue(nil, nil, nil)
+	ctx := context.Background()
+	key := getDLQAckLevelKey(AllTaskTypes, DefaultConsumerGroup)
+	require.NoError(t, queue.CompareAndSwapDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup, common.EmptyMessageID, 4))
+
+	// the last shard fails to update, so the shards updated before it are rolled back
+	testErr := fmt.Errorf("test")
+	queue.shards[2].(*pagedDLQShard).updateErr = testErr
+	assert.Equal(t, testErr, queue.CompareAndSwapDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup, 4, 7))
+	for shardID, expected := range []int64{1, 1, 0} {
+		assert.Equal(t, expected, queue.shards[shardID].(*pagedDLQShard).ackLevels[key])
+	}
+
+	_, version, err := queue.GetDLQAckLevelWithVersion(ctx, AllTaskTypes, DefaultConsumerGroup)
+	require.NoError(t, err)
+	assert.Equal(t, testErr, queue.UpdateDLQAckLevelWithVersion(ctx, AllTaskTypes, DefaultConsumerGroup, 7, version))
+	ackLevel, err := queue.GetDLQAckLevel(ctx, AllTaskTypes, DefaultConsumerGroup)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), ackLevel)
+
+	// once the shard recovers the ack levels are moved together
+	queue.shards[2].(*pagedDLQShard).updateErr = nil
+	_, version, err = queue.GetDLQAckLevelWithVersion(ctx, AllTaskTypes, DefaultConsumerGroup)
+	require.NoError(t, err)
+	require.NoError(t, queue.UpdateDLQAckLevelWithVersion(ctx, AllTaskTypes, DefaultConsumerGroup, 7, version))
+	for shardID, expected := range []int64{2, 2, 1} {
+		assert.Equal(t, expected, queue.shards[shardID].(*pagedDLQShard).ackLevels[key])
+	}
+}
+
+func TestShardedReplicationQueue_RangeDeleteMessagesFromDLQ(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	shards := []ReplicationQueue{NewMockReplicationQueue(controller), NewMockReplicationQueue(controller), NewMockReplicationQueue(controller)}
+	queue := newShardedReplicationQueue(shards, loggerimpl.NewNopLogger())
+
+	// the messages 4 and 5 are the message 1 of the shards 1 and 2, shard 0 has no message in the range
+	shards[1].(*MockReplicationQueue).EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(0), int64(1)).Return(nil).Times(1)
+	shards[2].(*MockReplicationQueue).EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(0), int64(1)).Return(nil).Times(1)
+	require.NoError(t, queue.RangeDeleteMessagesFromDLQ(context.Background(), AllTaskTypes, 3, 5))
+
+	shards[2].(*MockReplicationQueue).EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(1)).Return(nil).Times(1)
+	require.NoError(t, queue.DeleteMessageFromDLQ(context.Background(), 5))
+}
+
+func TestShardedReplicationQueue_GetDLQSize(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	shards := []ReplicationQueue{NewMockReplicationQueue(controller), NewMockReplicationQueue(controller)}
+	queue := newShardedReplicationQueue(shards, loggerimpl.NewNopLogger())
+
+	shards[0].(*MockReplicationQueue).EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(3), nil).Times(1)
+	shards[1].(*MockReplicationQueue).EXPECT().GetDLQSize(gomock.Any(), AllTaskTypes).Return(int64(4), nil).Times(1)
+	size, err := queue.GetDLQSize(context.Background(), AllTaskTypes)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), size)
+}
+
+// partitionedQueueManager serializes the writes to the DLQ of a queue, like the writes to a single database partition
+type partitionedQueueManager struct {
+	persistence.QueueManager
+	sync.Mutex
+	writeLatency time.Duration
+}
+
+func (q *partitionedQueueManager) EnqueueMessageToDLQ(context.Context, string, *int32, []byte) error {
+	q.Lock()
+	defer q.Unlock()
+	time.Sleep(q.writeLatency)
+	return nil
+}
+
+// BenchmarkShardedDLQEnqueue compares the concurrent enqueues into a DLQ of a single shard, shards=1,
+// with those into a DLQ of 8 shards, shards=8, each shard serializing its writes
+func BenchmarkShardedDLQEnqueue(b *testing.B) {
+	for _, numShards := range []int{1, 8} {
+		var queues []persistence.QueueManager
+		for i := 0; i < numShards; i++ {
+			queues = append(queues, &partitionedQueueManager{writeLatency: 100 * time.Microsecond})
+		}
+		queue := NewShardedReplicationQueue(queues, "testCluster", metrics.NewNoopMetricsClient(), loggerimpl.NewNopLogger())
+
+		b.Run(fmt.Sprintf("shards=%d", numShards), func(b *testing.B) {
+			var runs int64
+			b.SetParallelism(8)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					task := &types.ReplicationTask{
+						TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(),
+						HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{
+							RunID: strconv.FormatInt(atomic.AddInt64(&runs, 1), 10),
+						},
+					}
+					if err := queue.PublishToDLQ(context.Background(), task); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"sync"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+// SimulatedOperationType is the type of an operation a replication task would have performed
+type SimulatedOperationType string
+
+const (
+	// SimulatedOperationCreateDomain creates the domain of a domain replication task
+	SimulatedOperationCreateDomain SimulatedOperationType = "CreateDomain"
+	// SimulatedOperationUpdateDomain updates the domain of a domain replication task
+	SimulatedOperationUpdateDomain SimulatedOperationType = "UpdateDomain"
+	// SimulatedOperationDeleteDomain deletes a local domain conflicting with a domain replication task
+	SimulatedOperationDeleteDomain SimulatedOperationType = "DeleteDomain"
+	// SimulatedOperationReplicateHistory applies the history events of a history replication task
+	SimulatedOperationReplicateHistory SimulatedOperationType = "ReplicateHistory"
+	// SimulatedOperationSyncActivity syncs the activity state of an activity sync replication task
+	SimulatedOperationSyncActivity SimulatedOperationType = "SyncActivity"
+	// SimulatedOperationApplyFailoverMarker applies the failover marker of a failover marker replication task
+	SimulatedOperationApplyFailoverMarker SimulatedOperationType = "ApplyFailoverMarker"
+	// SimulatedOperationResetWorkflow resets the workflow of a workflow reset replication task
+	SimulatedOperationResetWorkflow SimulatedOperationType = "ResetWorkflow"
+	// SimulatedOperationFail is a replication task which would have failed
+	SimulatedOperationFail SimulatedOperationType = "Fail"
+)
+
+var (
+	// ErrSimulationModeDisabled is returned by SimulateMerge when the handler was created without WithSimulationMode
+	ErrSimulationModeDisabled = &types.BadRequestError{Message: "domain DLQ merge simulation is not enabled"}
+)
+
+type (
+	// SimulatedOperation is an operation a replication task of the domain DLQ would have performed
+	SimulatedOperation struct {
+		MessageID       int64                     `json:"messageID"`
+		TaskType        types.ReplicationTaskType `json:"taskType"`
+		Operation       SimulatedOperationType    `json:"operation"`
+		DomainID        string                    `json:"domainID,omitempty"`
+		DomainName      string                    `json:"domainName,omitempty"`
+		FailoverVersion int64                     `json:"failoverVersion"`
+		Error           string                    `json:"error,omitempty"`
+	}
+
+	// SimulationReplicationTaskExecutor is a ReplicationTaskExecutor which records the operations
+	// the replication tasks would have performed instead of performing them
+	SimulationReplicationTaskExecutor interface {
+		ReplicationTaskExecutor
+		// Operations returns the operations the executed tasks would have performed, in execution order
+		Operations() []SimulatedOperation
+	}
+
+	simulationReplicationTaskExecutorImpl struct {
+		domains        *simulatedDomainManager
+		domainExecutor ReplicationTaskExecutor
+	}
+
+	// simulatedDomainManager reads the domains from the domain manager, and keeps the domains the simulated
+	// operations created, updated or deleted in memory so the later tasks see them
+	simulatedDomainManager struct {
+		persistence.DomainManager
+
+		sync.Mutex
+		message    *types.ReplicationTask
+		operations []SimulatedOperation
+		domains    map[string]*persistence.GetDomainResponse
+		deleted    map[string]struct{}
+	}
+)
+
+var _ SimulationReplicationTaskExecutor = (*simulationReplicationTaskExecutorImpl)(nil)
+
+// NewSimulationReplicationTaskExecutor creates a new instance of simulation replication task executor,
+// the domain replication tasks are simulated by the domain replication task executor reading the domains
+// from domainManager, which is never written to
+func NewSimulationReplicationTaskExecutor(
+	domainManager persistence.DomainManager,
+	timeSource clock.TimeSource,
+	logger log.Logger,
+) SimulationReplicationTaskExecutor {
+
+	domains := &simulatedDomainManager{
+		DomainManager: domainManager,
+		domains:       make(map[string]*persistence.GetDomainResponse),
+		deleted:       make(map[string]struct{}),
+	}
+	return &simulationReplicationTaskExecutorImpl{
+		domains:        domains,
+		domainExecutor: NewReplicationTaskExecutor(domains, timeSource, nil, logger),
+	}
+}
+
+// ExecuteReplicationTask records the operations the replication task would have performed,
+// a task which would have failed is recorded as failed and its error is returned
+func (e *simulationReplicationTaskExecutorImpl) ExecuteReplicationTask(task *types.ReplicationTask, sourceCluster string) error {
+	e.domains.setMessage(task)
+	defer e.domains.setMessage(nil)
+
+	var err error
+	switch task.GetTaskType() {
+	case types.ReplicationTaskTypeDomain:
+		err = e.domainExecutor.ExecuteReplicationTask(task, sourceCluster)
+	case types.ReplicationTaskTypeHistoryV2:
+		e.domains.record(SimulatedOperationReplicateHistory, task.GetHistoryTaskV2Attributes().GetDomainID(), "", 0)
+	case types.ReplicationTaskTypeSyncActivity:
+		e.domains.record(SimulatedOperationSyncActivity, task.GetSyncActivityTaskAttributes().GetDomainID(), "", 0)
+	case types.ReplicationTaskTypeFailoverMarker:
+		marker := task.GetFailoverMarkerAttributes()
+		e.domains.record(SimulatedOperationApplyFailoverMarker, marker.GetDomainID(), "", marker.GetFailoverVersion())
+	case types.ReplicationTaskTypeWorkflowReset:
+		e.domains.record(SimulatedOperationResetWorkflow, task.GetWorkflowResetTaskAttributes().GetDomainID(), "", 0)
+	default:
+		err = ErrUnsupportedReplicationTaskType
+	}
+	if err != nil {
+		e.domains.recordFailure(err)
+	}
+	return err
+}
+
+// Execute records the operations the domain replication task would have performed
+func (e *simulationReplicationTaskExecutorImpl) Execute(task *types.DomainTaskAttributes) error {
+	err := e.domainExecutor.Execute(task)
+	if err != nil {
+		e.domains.recordFailure(err)
+	}
+	return err
+}
+
+// Overwrite records the deletion of the conflicting local domains with the operations of the domain replication task
+func (e *simulationReplicationTaskExecutorImpl) Overwrite(task *types.DomainTaskAttributes) error {
+	err := e.domainExecutor.Overwrite(task)
+	if err != nil {
+		e.domains.recordFailure(err)
+	}
+	return err
+}
+
+// AddMigration registers the migration of the domain replication tasks, so they are simulated as they would be executed
+func (e *simulationReplicationTaskExecutorImpl) AddMigration(fromVersion, toVersion int, fn MigrationFunc) error {
+	return e.domainExecutor.AddMigration(fromVersion, toVersion, fn)
+}
+
+// Operations returns the operations the executed tasks would have performed, in execution order
+func (e *simulationReplicationTaskExecutorImpl) Operations() []SimulatedOperation {
+	e.domains.Lock()
+	defer e.domains.Unlock()
+	return append([]SimulatedOperation(nil), e.domains.operations...)
+}
+
+func (m *simulatedDomainManager) setMessage(message *types.ReplicationTask) {
+	m.Lock()
+	defer m.Unlock()
+	m.message = message
+}
+
+func (m *simulatedDomainManager) record(
+	operation SimulatedOperationType,
+	domainID string,
+	domainName string,
+	failoverVersion int64,
+) {
+	m.Lock()
+	defer m.Unlock()
+	m.recordLocked(SimulatedOperation{
+		Operation:       operation,
+		DomainID:        domainID,
+		DomainName:      domainName,
+		FailoverVersion: failoverVersion,
+	})
+}
+
+func (m *simulatedDomainManager) recordFailure(err error) {
+	m.Lock()
+	defer m.Unlock()
+	operation := SimulatedOperation{Operation: SimulatedOperationFail, Error: err.Error()}
+	if attributes := m.message.GetDomainTaskAttributes(); attributes != nil {
+		operation.DomainID = attributes.ID
+		operation.DomainName = attributes.Info.GetName()
+		operation.FailoverVersion = attributes.FailoverVersion
+	}
+	m.recordLocked(operation)
+}
+
+func (m *simulatedDomainManager) recordLocked(operation SimulatedOperation) {
+	operation.TaskType = types.ReplicationTaskTypeDomain
+	if m.message != nil {
+		operation.MessageID = m.message.SourceTaskID
+		operation.TaskType = m.message.GetTaskType()
+	}
+	m.operations = append(m.operations, operation)
+}
+
+// GetDomain returns the domain as the simulated operations left it
+func (m *simulatedDomainManager) GetDomain(
+	ctx context.Context,
+	request *persistence.GetDomainRequest,
+) (*persistence.GetDomainResponse, error) {
+
+	m.Lock()
+	domain, ok := m.findDomainLocked(request)
+	m.Unlock()
+	if ok {
+		if domain == nil {
+			return nil, &types.EntityNotExistsError{Message: "domain was deleted by the simulation"}
+		}
+		return copyDomainResponse(domain), nil
+	}
+	return m.DomainManager.GetDomain(ctx, request)
+}
+
+// findDomainLocked returns true if the domain was changed by the simulated operations, and nil if it was deleted
+func (m *simulatedDomainManager) findDomainLocked(request *persistence.GetDomainRequest) (*persistence.GetDomainResponse, bool) {
+	if _, ok := m.deleted[request.ID]; ok && request.ID != "" {
+		return nil, true
+	}
+	if _, ok := m.deleted[request.Name]; ok && request.Name != "" {
+		return nil, true
+	}
+	for _, domain := range m.domains {
+		if (request.ID != "" && domain.Info.ID == request.ID) || (request.Name != "" && domain.Info.Name == request.Name) {
+			return domain, true
+		}
+	}
+	return nil, false
+}
+
+// CreateDomain records the creation of the domain, it fails if a domain with the same name or ID exists
+func (m *simulatedDomainManager) CreateDomain(
+	ctx context.Context,
+	request *persistence.CreateDomainRequest,
+) (*persistence.CreateDomainResponse, error) {
+
+	for _, getRequest := range []*persistence.GetDomainRequest{{ID: request.Info.ID}, {Name: request.Info.Name}} {
+		_, err := m.GetDomain(ctx, getRequest)
+		switch err.(type) {
+		case nil:
+			return nil, &types.DomainAlreadyExistsError{Message: "domain already exists"}
+		case *types.EntityNotExistsError:
+		default:
+			return nil, err
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	delete(m.deleted, request.Info.ID)
+	delete(m.deleted, request.Info.Name)
+	m.domains[request.Info.ID] = &persistence.GetDomainResponse{
+		Info:              request.Info,
+		Config:            request.Config,
+		ReplicationConfig: request.ReplicationConfig,
+		IsGlobalDomain:    request.IsGlobalDomain,
+		ConfigVersion:     request.ConfigVersion,
+		FailoverVersion:   request.FailoverVersion,
+		LastUpdatedTime:   request.LastUpdatedTime,
+	}
+	m.recordLocked(SimulatedOperation{
+		Operation:       SimulatedOperationCreateDomain,
+		DomainID:        request.Info.ID,
+		DomainName:      request.Info.Name,
+		FailoverVersion: request.FailoverVersion,
+	})
+	return &persistence.CreateDomainResponse{ID: request.Info.ID}, nil
+}
+
+// UpdateDomain records the update of the domain
+func (m *simulatedDomainManager) UpdateDomain(
+	_ context.Context,
+	request *persistence.UpdateDomainRequest,
+) error {
+
+	m.Lock()
+	defer m.Unlock()
+	m.domains[request.Info.ID] = &persistence.GetDomainResponse{
+		Info:                        request.Info,
+		Config:                      request.Config,
+		ReplicationConfig:           request.ReplicationConfig,
+		ConfigVersion:               request.ConfigVersion,
+		FailoverVersion:             request.FailoverVersion,
+		FailoverNotificationVersion: request.FailoverNotificationVersion,
+		PreviousFailoverVersion:     request.PreviousFailoverVersion,
+		FailoverEndTime:             request.FailoverEndTime,
+		LastUpdatedTime:             request.LastUpdatedTime,
+		NotificationVersion:         request.NotificationVersion,
+	}
+	m.recordLocked(SimulatedOperation{
+		Operation:       SimulatedOperationUpdateDomain,
+		DomainID:        request.Info.ID,
+		DomainName:      request.Info.Name,
+		FailoverVersion: request.FailoverVersion,
+	})
+	return nil
+}
+
+// DeleteDomain records the deletion of the domain
+func (m *simulatedDomainManager) DeleteDomain(
+	ctx context.Context,
+	request *persistence.DeleteDomainRequest,
+) error {
+
+	return m.deleteDomain(ctx, &persistence.GetDomainRequest{ID: request.ID})
+}
+
+// DeleteDomainByName records the deletion of the domain
+func (m *simulatedDomainManager) DeleteDomainByName(
+	ctx context.Context,
+	request *persistence.DeleteDomainByNameRequest,
+) error {
+
+	return m.deleteDomain(ctx, &persistence.GetDomainRequest{Name: request.Name})
+}
+
+func (m *simulatedDomainManager) deleteDomain(
+	ctx context.Context,
+	request *persistence.GetDomainRequest,
+) error {
+
+	domain, err := m.GetDomain(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	delete(m.domains, domain.Info.ID)
+	m.deleted[domain.Info.ID] = struct{}{}
+	m.deleted[domain.Info.Name] = struct{}{}
+	m.recordLocked(SimulatedOperation{
+		Operation:       SimulatedOperationDeleteDomain,
+		DomainID:        domain.Info.ID,
+		DomainName:      domain.Info.Name,
+		FailoverVersion: domain.FailoverVersion,
+	})
+	return nil
+}
+
+// copyDomainResponse copies the parts of the domain the domain replication task executor modifies
+func copyDomainResponse(domain *persistence.GetDomainResponse) *persistence.GetDomainResponse {
+	copied := *domain
+	if domain.ReplicationConfig != nil {
+		replicationConfig := *domain.ReplicationConfig
+		copied.ReplicationConfig = &replicationConfig
+	}
+	return &copied
+}
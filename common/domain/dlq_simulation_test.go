@@ -0,0 +1,179 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+func newSimulatedDomainTask(
+	messageID int64,
+	operation types.DomainOperation,
+	domainID string,
+	domainName string,
+	configVersion int64,
+	failoverVersion int64,
+) *types.ReplicationTask {
+	return &types.ReplicationTask{
+		TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID: messageID,
+		DomainTaskAttributes: &types.DomainTaskAttributes{
+			DomainOperation:   operation.Ptr(),
+			ID:                domainID,
+			Info:              &types.DomainInfo{Name: domainName, Status: types.DomainStatusRegistered.Ptr()},
+			Config:            &types.DomainConfiguration{WorkflowExecutionRetentionPeriodInDays: 1},
+			ReplicationConfig: &types.DomainReplicationConfiguration{ActiveClusterName: "active"},
+			ConfigVersion:     configVersion,
+			FailoverVersion:   failoverVersion,
+		},
+	}
+}
+
+// newSimulatedDomainManager returns a domain manager with a single domain which fails the test if it is written to
+func newSimulatedDomainManager(controller *gomock.Controller) persistence.DomainManager {
+	domainManager := persistence.NewMockDomainManager(controller)
+	existing := &persistence.GetDomainResponse{
+		Info:              &persistence.DomainInfo{ID: "old-id", Name: "domain-old", Status: persistence.DomainStatusRegistered},
+		Config:            &persistence.DomainConfig{Retention: 1},
+		ReplicationConfig: &persistence.DomainReplicationConfig{ActiveClusterName: "standby"},
+		IsGlobalDomain:    true,
+		ConfigVersion:     2,
+		FailoverVersion:   1,
+	}
+	domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.GetDomainRequest) (*persistence.GetDomainResponse, error) {
+			if request.ID == existing.Info.ID || request.Name == existing.Info.Name {
+				return copyDomainResponse(existing), nil
+			}
+			return nil, &types.EntityNotExistsError{}
+		},
+	).AnyTimes()
+	domainManager.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{NotificationVersion: 1}, nil).AnyTimes()
+	return domainManager
+}
+
+func TestSimulationReplicationTaskExecutor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	executor := NewSimulationReplicationTaskExecutor(
+		newSimulatedDomainManager(controller),
+		clock.NewEventTimeSource(),
+		loggerimpl.NewNopLogger(),
+	)
+
+	snapshot := []*types.ReplicationTask{
+		newSimulatedDomainTask(11, types.DomainOperationCreate, "new-id", "domain-new", 1, 1),
+		// the domain created by the previous message is updated
+		newSimulatedDomainTask(12, types.DomainOperationUpdate, "new-id", "domain-new", 1, 10),
+		newSimulatedDomainTask(13, types.DomainOperationUpdate, "old-id", "domain-old", 3, 5),
+		// the domain was already updated past the versions of the message
+		newSimulatedDomainTask(14, types.DomainOperationUpdate, "old-id", "domain-old", 3, 5),
+		{
+			TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+			SourceTaskID:            15,
+			HistoryTaskV2Attributes: newHistoryTaskV2Attributes(),
+		},
+		// a local domain has the name of the domain
+		newSimulatedDomainTask(16, types.DomainOperationCreate, "other-id", "domain-old", 1, 1),
+		{
+			TaskType:                 types.ReplicationTaskTypeFailoverMarker.Ptr(),
+			SourceTaskID:             17,
+			FailoverMarkerAttributes: newFailoverMarkerAttributes(20),
+		},
+	}
+	for _, task := range snapshot {
+		err := executor.ExecuteReplicationTask(task, "cluster")
+		if task.SourceTaskID == 16 {
+			assert.Equal(t, ErrNameUUIDCollision, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	assert.Equal(t, []SimulatedOperation{
+		{MessageID: 11, TaskType: types.ReplicationTaskTypeDomain, Operation: SimulatedOperationCreateDomain, DomainID: "new-id", DomainName: "domain-new", FailoverVersion: 1},
+		{MessageID: 12, TaskType: types.ReplicationTaskTypeDomain, Operation: SimulatedOperationUpdateDomain, DomainID: "new-id", DomainName: "domain-new", FailoverVersion: 10},
+		{MessageID: 13, TaskType: types.ReplicationTaskTypeDomain, Operation: SimulatedOperationUpdateDomain, DomainID: "old-id", DomainName: "domain-old", FailoverVersion: 5},
+		{MessageID: 15, TaskType: types.ReplicationTaskTypeHistoryV2, Operation: SimulatedOperationReplicateHistory, DomainID: "domain-id"},
+		{
+			MessageID:       16,
+			TaskType:        types.ReplicationTaskTypeDomain,
+			Operation:       SimulatedOperationFail,
+			DomainID:        "other-id",
+			DomainName:      "domain-old",
+			FailoverVersion: 1,
+			Error:           ErrNameUUIDCollision.Error(),
+		},
+		{MessageID: 17, TaskType: types.ReplicationTaskTypeFailoverMarker, Operation: SimulatedOperationApplyFailoverMarker, DomainID: "domain-id", FailoverVersion: 20},
+	}, executor.Operations())
+}
+
+func TestSimulationReplicationTaskExecutor_Overwrite(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	executor := NewSimulationReplicationTaskExecutor(
+		newSimulatedDomainManager(controller),
+		clock.NewEventTimeSource(),
+		loggerimpl.NewNopLogger(),
+	)
+
+	// the local domain with the name of the replicated domain is deleted before the domain is created
+	task := newSimulatedDomainTask(0, types.DomainOperationCreate, "other-id", "domain-old", 1, 1)
+	assert.NoError(t, executor.Overwrite(task.DomainTaskAttributes))
+	// the deleted domain no longer exists for the next tasks
+	assert.NoError(t, executor.Execute(newSimulatedDomainTask(0, types.DomainOperationCreate, "old-id", "domain-older", 1, 1).DomainTaskAttributes))
+
+	assert.Equal(t, []SimulatedOperation{
+		{TaskType: types.ReplicationTaskTypeDomain, Operation: SimulatedOperationDeleteDomain, DomainID: "old-id", DomainName: "domain-old", FailoverVersion: 1},
+		{TaskType: types.ReplicationTaskTypeDomain, Operation: SimulatedOperationCreateDomain, DomainID: "other-id", DomainName: "domain-old", FailoverVersion: 1},
+		{TaskType: types.ReplicationTaskTypeDomain, Operation: SimulatedOperationCreateDomain, DomainID: "old-id", DomainName: "domain-older", FailoverVersion: 1},
+	}, executor.Operations())
+}
+
+func TestSimulationReplicationTaskExecutor_UnsupportedTaskType(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	executor := NewSimulationReplicationTaskExecutor(
+		persistence.NewMockDomainManager(controller),
+		clock.NewEventTimeSource(),
+		loggerimpl.NewNopLogger(),
+	)
+
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeSyncShardStatus.Ptr(), SourceTaskID: 11}
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.ExecuteReplicationTask(task, "cluster"))
+	assert.Equal(t, []SimulatedOperation{
+		{
+			MessageID: 11,
+			TaskType:  types.ReplicationTaskTypeSyncShardStatus,
+			Operation: SimulatedOperationFail,
+			Error:     ErrUnsupportedReplicationTaskType.Error(),
+		},
+	}, executor.Operations())
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// DomainDataKeySLATier is the key of the SLA tier of a domain in the domain data
+	DomainDataKeySLATier = "sla-tier"
+
+	// SLATierCritical is the SLA tier of the domains whose DLQ messages are merged first
+	SLATierCritical SLATier = "critical"
+	// SLATierStandard is the SLA tier of the domains without a SLA tier
+	SLATierStandard SLATier = "standard"
+	// SLATierBestEffort is the SLA tier of the domains whose DLQ messages are merged last
+	SLATierBestEffort SLATier = "best-effort"
+)
+
+type (
+	// SLATier is the service level a domain is entitled to
+	SLATier string
+
+	// PriorityFunc returns the priority the DLQ messages of a domain are merged with, messages of the domains
+	// with a higher priority are merged first within a page
+	PriorityFunc func(domainID string) (int, error)
+
+	// DomainMetadataReader reads the metadata attached to a domain, i.e. its domain data
+	DomainMetadataReader interface {
+		GetDomainMetadata(domainID string) (map[string]string, error)
+	}
+
+	domainCacheMetadataReader struct {
+		domainCache cache.DomainCache
+	}
+)
+
+var slaTierPriorities = map[SLATier]int{
+	SLATierCritical:   2,
+	SLATierStandard:   1,
+	SLATierBestEffort: 0,
+}
+
+// NewSLATierPriorityFunc returns a PriorityFunc prioritizing the domains by the SLA tier in their metadata,
+// domains without a known SLA tier are merged as standard domains
+func NewSLATierPriorityFunc(reader DomainMetadataReader) PriorityFunc {
+	return func(domainID string) (int, error) {
+		metadata, err := reader.GetDomainMetadata(domainID)
+		if err != nil {
+			return slaTierPriorities[SLATierStandard], err
+		}
+		tier := SLATier(strings.ToLower(metadata[DomainDataKeySLATier]))
+		if priority, ok := slaTierPriorities[tier]; ok {
+			return priority, nil
+		}
+		return slaTierPriorities[SLATierStandard], nil
+	}
+}
+
+// NewDomainCacheMetadataReader returns a DomainMetadataReader reading the domain data from the domain cache
+func NewDomainCacheMetadataReader(domainCache cache.DomainCache) DomainMetadataReader {
+	return &domainCacheMetadataReader{
+		domainCache: domainCache,
+	}
+}
+
+func (r *domainCacheMetadataReader) GetDomainMetadata(domainID string) (map[string]string, error) {
+	entry, err := r.domainCache.GetDomainByID(domainID)
+	if err != nil {
+		return nil, err
+	}
+	return entry.GetInfo().Data, nil
+}
+
+// sortDLQMessagesByDomainPriority orders the messages from the highest priority of their domain to the lowest,
+// keeping the order of the messages of the same priority. The messages whose priority cannot be read
+// are given the priority the function returns along with the error, which onError is called with.
+func sortDLQMessagesByDomainPriority(
+	messages []*types.ReplicationTask,
+	priorityFunc PriorityFunc,
+	onError func(message *types.ReplicationTask, err error),
+) {
+
+	priorities := make(map[string]int)
+	priorityOf := func(message *types.ReplicationTask) int {
+		domainID := getReplicationTaskDomainID(message)
+		if priority, ok := priorities[domainID]; ok {
+			return priority
+		}
+		priority, err := priorityFunc(domainID)
+		if err != nil {
+			onError(message, err)
+		}
+		priorities[domainID] = priority
+		return priority
+	}
+
+	messagePriorities := make([]int, len(messages))
+	for i, message := range messages {
+		messagePriorities[i] = priorityOf(message)
+	}
+	sort.Stable(&dlqMessagesByPriority{messages: messages, priorities: messagePriorities})
+}
+
+type dlqMessagesByPriority struct {
+	messages   []*types.ReplicationTask
+	priorities []int
+}
+
+func (m *dlqMessagesByPriority) Len() int {
+	return len(m.messages)
+}
+
+func (m *dlqMessagesByPriority) Less(i, j int) bool {
+	return m.priorities[i] > m.priorities[j]
+}
+
+func (m *dlqMessagesByPriority) Swap(i, j int) {
+	m.messages[i], m.messages[j] = m.messages[j], m.messages[i]
+	m.priorities[i], m.priorities[j] = m.priorities[j], m.priorities[i]
+}
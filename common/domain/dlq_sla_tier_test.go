@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+type testDomainMetadataReader func(domainID string) (map[string]string, error)
+
+func (r testDomainMetadataReader) GetDomainMetadata(domainID string) (map[string]string, error) {
+	return r(domainID)
+}
+
+func TestSLATierPriorityFunc(t *testing.T) {
+	readErr := errors.New("failed to read domain")
+	metadata := map[string]map[string]string{
+		"critical-domain":    {DomainDataKeySLATier: "critical"},
+		"standard-domain":    {DomainDataKeySLATier: "standard"},
+		"best-effort-domain": {DomainDataKeySLATier: "Best-Effort"},
+		"unknown-tier":       {DomainDataKeySLATier: "gold"},
+		"no-tier":            {},
+	}
+	priorityFunc := NewSLATierPriorityFunc(testDomainMetadataReader(func(domainID string) (map[string]string, error) {
+		if domainID == "deleted-domain" {
+			return nil, readErr
+		}
+		return metadata[domainID], nil
+	}))
+
+	critical, err := priorityFunc("critical-domain")
+	assert.NoError(t, err)
+	standard, err := priorityFunc("standard-domain")
+	assert.NoError(t, err)
+	bestEffort, err := priorityFunc("best-effort-domain")
+	assert.NoError(t, err)
+	assert.True(t, critical > standard)
+	assert.True(t, standard > bestEffort)
+
+	// domains without a known SLA tier are standard domains
+	for _, domainID := range []string{"unknown-tier", "no-tier"} {
+		priority, err := priorityFunc(domainID)
+		assert.NoError(t, err)
+		assert.Equal(t, standard, priority, domainID)
+	}
+	priority, err := priorityFunc("deleted-domain")
+	assert.Equal(t, readErr, err)
+	assert.Equal(t, standard, priority)
+}
+
+func TestSortDLQMessagesByDomainPriority(t *testing.T) {
+	readErr := errors.New("failed to read domain")
+	tiers := map[string]string{
+		"critical-domain":    "critical",
+		"best-effort-domain": "best-effort",
+	}
+	reads := make(map[string]int)
+	priorityFunc := NewSLATierPriorityFunc(testDomainMetadataReader(func(domainID string) (map[string]string, error) {
+		reads[domainID]++
+		if domainID == "deleted-domain" {
+			return nil, readErr
+		}
+		return map[string]string{DomainDataKeySLATier: tiers[domainID]}, nil
+	}))
+	newTask := func(messageID int64, domainID string) *types.ReplicationTask {
+		return &types.ReplicationTask{
+			TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+			SourceTaskID:            messageID,
+			HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{DomainID: domainID},
+		}
+	}
+	messages := []*types.ReplicationTask{
+		newTask(1, "best-effort-domain"),
+		newTask(2, "deleted-domain"),
+		newTask(3, "critical-domain"),
+		newTask(4, "standard-domain"),
+		newTask(5, "best-effort-domain"),
+		newTask(6, "critical-domain"),
+	}
+
+	var failed []int64
+	sortDLQMessagesByDomainPriority(messages, priorityFunc, func(message *types.ReplicationTask, err error) {
+		assert.Equal(t, readErr, err)
+		failed = append(failed, message.SourceTaskID)
+	})
+
+	var messageIDs []int64
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.SourceTaskID)
+	}
+	// the messages of the same priority keep their order, the domain which cannot be read is a standard domain
+	assert.Equal(t, []int64{3, 6, 2, 4, 1, 5}, messageIDs)
+	assert.Equal(t, []int64{2}, failed)
+	// the priority of a domain is read once per page
+	assert.Equal(t, map[string]int{"best-effort-domain": 1, "deleted-domain": 1, "critical-domain": 1, "standard-domain": 1}, reads)
+}
+
+func TestDomainCacheMetadataReader(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	domainCache := cache.NewMockDomainCache(controller)
+	reader := NewDomainCacheMetadataReader(domainCache)
+
+	data := map[string]string{DomainDataKeySLATier: "critical"}
+	domainCache.EXPECT().GetDomainByID("domain-id").Return(cache.NewLocalDomainCacheEntryForTest(
+		&persistence.DomainInfo{ID: "domain-id", Data: data}, &persistence.DomainConfig{}, "", nil,
+	), nil).Times(1)
+	metadata, err := reader.GetDomainMetadata("domain-id")
+	assert.NoError(t, err)
+	assert.Equal(t, data, metadata)
+
+	domainErr := &types.EntityNotExistsError{Message: "domain"}
+	domainCache.EXPECT().GetDomainByID("other-domain-id").Return(nil, domainErr).Times(1)
+	_, err = reader.GetDomainMetadata("other-domain-id")
+	assert.Equal(t, domainErr, err)
+}
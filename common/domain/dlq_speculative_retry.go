@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// dlqSpeculativeRetryInitialInterval and dlqSpeculativeRetryMaximumInterval bound the backoff of speculative retries
+	dlqSpeculativeRetryInitialInterval = 100 * time.Millisecond
+	dlqSpeculativeRetryMaximumInterval = 2 * time.Second
+	// dlqSpeculativeRetryMaxAttempts is the number of retries after the failed execution of the merge
+	dlqSpeculativeRetryMaxAttempts = 3
+)
+
+// dlqSpeculativeRetry is a message of a page retried in the background while the rest of the page is merged,
+// err is the result of the retry once done is closed
+type dlqSpeculativeRetry struct {
+	message *types.ReplicationTask
+	done    chan struct{}
+	err     error
+}
+
+func newDLQSpeculativeRetryPolicy() backoff.RetryPolicy {
+	policy := backoff.NewExponentialRetryPolicy(dlqSpeculativeRetryInitialInterval)
+	policy.SetMaximumInterval(dlqSpeculativeRetryMaximumInterval)
+	policy.SetExpirationInterval(backoff.NoInterval)
+	policy.SetMaximumAttempts(dlqSpeculativeRetryMaxAttempts)
+	return policy
+}
+
+// retrySpeculatively retries the message which failed with the transient error in the background,
+// the retry backs off and stops once the message fails with an error which is not transient
+func (d *dlqMessageHandlerImpl) retrySpeculatively(
+	ctx context.Context,
+	message *types.ReplicationTask,
+	executeErr error,
+) *dlqSpeculativeRetry {
+
+	retry := &dlqSpeculativeRetry{message: message, done: make(chan struct{})}
+	go func() {
+		defer close(retry.done)
+
+		throttleRetry := backoff.NewThrottleRetry(
+			backoff.WithRetryPolicy(d.speculativeBackoff),
+			backoff.WithRetryableError(IsTransientError),
+		)
+		attempt := 0
+		lastErr := executeErr
+		if err := throttleRetry.Do(ctx, func() error {
+			// the failed execution of the merge is the first attempt, so the first retry backs off
+			if attempt++; attempt == 1 {
+				return lastErr
+			}
+			lastErr = d.executeJournaled(ctx, message)
+			return lastErr
+		}); err != nil {
+			// the retrier returns the error of the attempt before the last one, so the last error is kept instead
+			retry.err = lastErr
+		}
+	}()
+	return retry
+}
+
+// awaitSpeculativeRetries waits for the speculative retries of a page and records the retried messages as merged.
+// It returns the failure of the first retry which did not succeed, the page is rolled back then.
+func (d *dlqMessageHandlerImpl) awaitSpeculativeRetries(
+	logger log.Logger,
+	retries []*dlqSpeculativeRetry,
+	progress *dlqMergeProgress,
+) error {
+
+	var retryErr error
+	for _, retry := range retries {
+		<-retry.done
+		if retry.err != nil {
+			logger.WithTags(dlqMessageTags(retry.message)...).Warn("Speculative retry of domain DLQ message failed.", tag.Error(retry.err))
+			if retryErr == nil {
+				retryErr = newDLQError(ErrDLQSpeculativeRetryFailed, retry.err)
+			}
+			continue
+		}
+		d.recordMergedMessage(retry.message, progress)
+	}
+	if retryErr != nil {
+		logger.Warn("Rolling back domain DLQ page as a speculative retry failed.", dlqTaskTypeTag(progress.taskType))
+	}
+	return retryErr
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"sync"
+
+	"github.com/uber/cadence/common/metrics"
+)
+
+const (
+	// DLQStateIdle is the state of the handler while no merge is running
+	DLQStateIdle DLQState = "idle"
+	// DLQStateReading is the state of the handler while a merge reads a page of the DLQ
+	DLQStateReading DLQState = "reading"
+	// DLQStateExecuting is the state of the handler while a merge executes a message
+	DLQStateExecuting DLQState = "executing"
+	// DLQStateCommitting is the state of the handler while a merge deletes the merged messages and moves the ack level
+	DLQStateCommitting DLQState = "committing"
+)
+
+type (
+	// DLQState is the phase of the merges run by the domain DLQ handler
+	DLQState string
+
+	// StateChangeFunc is called with the previous and the new state of the handler on every transition.
+	// It is called synchronously in the order of the transitions, so it must not block nor call the handler.
+	StateChangeFunc func(from, to DLQState)
+
+	// dlqStateMachine tracks the state of the merges of the handler. Merges may run concurrently,
+	// in which case the state is the one entered last and the handler is only idle once every merge returned.
+	dlqStateMachine struct {
+		sync.Mutex
+
+		observer StateChangeFunc
+		scopes   map[DLQState]metrics.Scope
+
+		state       DLQState
+		activeCount int
+		// idle is closed once no merge is running, it is only created when waitIdle waits for the running merges
+		idle chan struct{}
+	}
+)
+
+func newDLQStateMachine(
+	observer StateChangeFunc,
+	metricsClient metrics.Client,
+) *dlqStateMachine {
+	scopes := make(map[DLQState]metrics.Scope)
+	for _, state := range []DLQState{DLQStateIdle, DLQStateReading, DLQStateExecuting, DLQStateCommitting} {
+		scopes[state] = metricsClient.Scope(metrics.DomainReplicationQueueScope, metrics.DLQStateTag(string(state)))
+	}
+	return &dlqStateMachine{
+		observer: observer,
+		scopes:   scopes,
+		state:    DLQStateIdle,
+	}
+}
+
+// begin starts a merge, which reads its first page
+func (m *dlqStateMachine) begin() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.activeCount++
+	m.transitionLocked(DLQStateReading)
+}
+
+// end ends a merge started by begin
+func (m *dlqStateMachine) end() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.activeCount--
+	if m.activeCount == 0 {
+		m.transitionLocked(DLQStateIdle)
+		if m.idle != nil {
+			close(m.idle)
+			m.idle = nil
+		}
+	}
+}
+
+// transition moves a running merge to the state, it is a no-op while no merge is running
+func (m *dlqStateMachine) transition(state DLQState) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.activeCount > 0 {
+		m.transitionLocked(state)
+	}
+}
+
+// waitIdle waits until no merge is running, it returns the context error if merges are still running once the context is done
+func (m *dlqStateMachine) waitIdle(ctx context.Context) error {
+	m.Lock()
+	if m.activeCount == 0 {
+		m.Unlock()
+		return nil
+	}
+	if m.idle == nil {
+		m.idle = make(chan struct{})
+	}
+	idle := m.idle
+	m.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *dlqStateMachine) currentState() DLQState {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.state
+}
+
+func (m *dlqStateMachine) transitionLocked(state DLQState) {
+	if m.state == state {
+		return
+	}
+	from := m.state
+	m.state = state
+	// transitions happen several times per page of every merge, so they are reported by the metric and the observer
+	// only, a log would allocate its tags on every transition even when its level is disabled
+	m.scopes[state].IncCounter(metrics.DomainReplicationDLQStateTransitions)
+	if m.observer != nil {
+		m.observer(from, state)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+type dlqStateTransition struct {
+	from DLQState
+	to   DLQState
+}
+
+func newChannelStateObserver() (StateChangeFunc, <-chan dlqStateTransition) {
+	transitions := make(chan dlqStateTransition, 100)
+	return func(from, to DLQState) {
+		transitions <- dlqStateTransition{from: from, to: to}
+	}, transitions
+}
+
+func awaitDLQStateTransition(t *testing.T, transitions <-chan dlqStateTransition) dlqStateTransition {
+	select {
+	case transition := <-transitions:
+		return transition
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for a domain DLQ state transition")
+		return dlqStateTransition{}
+	}
+}
+
+func TestDLQState_MergeTransitions(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	recorder, server := newDLQEventRecorder(t)
+	defer server.Close()
+
+	observer, transitions := newChannelStateObserver()
+	scope := tally.NewTestScope("test", nil)
+	dlqHandler, mockReplicationTaskExecutor, mockReplicationQueue := newNotifyingDLQMessageHandler(
+		controller,
+		server.URL,
+		WithStateChangeObserver(observer),
+		WithMetricsClient(metrics.NewClient(scope, metrics.Frontend)),
+	)
+	ackLevel := int64(10)
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11},
+	}
+	executing := make(chan struct{})
+	release := make(chan struct{})
+	mockReplicationQueue.EXPECT().GetDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup).Return(ackLevel, nil).Times(1)
+	mockReplicationQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(20), 100, nil).
+		Return(tasks, nil, nil).Times(1)
+	mockReplicationTaskExecutor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(*types.ReplicationTask, string) error {
+			close(executing)
+			<-release
+			return nil
+		}).Times(1)
+	mockReplicationQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), AllTaskTypes, ackLevel, int64(11)).Return(nil).Times(1)
+	mockReplicationQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), AllTaskTypes, DefaultConsumerGroup, ackLevel, int64(11)).Return(nil).Times(1)
+	mockReplicationQueue.EXPECT().RecordDLQMerge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	assert.Equal(t, DLQStateIdle, dlqHandler.Health().State)
+
+	mergeErr := make(chan error, 1)
+	go func() {
+		_, err := dlqHandler.Merge(context.Background(), AllTaskTypes, "", 20, 100, nil)
+		mergeErr <- err
+	}()
+	<-executing
+	assert.Equal(t, DLQStateExecuting, dlqHandler.Health().State)
+	close(release)
+
+	// an orchestrator waits for the handler to be idle before shutting it down
+	var observed []dlqStateTransition
+	for len(observed) == 0 || observed[len(observed)-1].to != DLQStateIdle {
+		observed = append(observed, awaitDLQStateTransition(t, transitions))
+	}
+	require.NoError(t, <-mergeErr)
+	assert.Equal(t, []dlqStateTransition{
+		{from: DLQStateIdle, to: DLQStateReading},
+		{from: DLQStateReading, to: DLQStateExecuting},
+		{from: DLQStateExecuting, to: DLQStateCommitting},
+		{from: DLQStateCommitting, to: DLQStateIdle},
+	}, observed)
+	assert.Equal(t, DLQStateIdle, dlqHandler.Health().State)
+	assert.Equal(t, int64(4), sumCapturedMetrics(captureMetrics(scope.Snapshot(), "dlq_state_transitions")))
+	assert.Len(t, recorder.recorded(), 1)
+}
+
+func TestDLQStateMachine_ConcurrentMerges(t *testing.T) {
+	observer, transitions := newChannelStateObserver()
+	stateMachine := newDLQStateMachine(observer, metrics.NewNoopMetricsClient())
+
+	// transitions outside of a merge are ignored
+	stateMachine.transition(DLQStateExecuting)
+	assert.Equal(t, DLQStateIdle, stateMachine.currentState())
+
+	stateMachine.begin()
+	stateMachine.transition(DLQStateExecuting)
+	stateMachine.begin()
+	stateMachine.end()
+	assert.Equal(t, DLQStateReading, stateMachine.currentState())
+	stateMachine.transition(DLQStateCommitting)
+	stateMachine.end()
+	assert.Equal(t, DLQStateIdle, stateMachine.currentState())
+
+	var observed []dlqStateTransition
+	for len(transitions) > 0 {
+		observed = append(observed, <-transitions)
+	}
+	assert.Equal(t, []dlqStateTransition{
+		{from: DLQStateIdle, to: DLQStateReading},
+		{from: DLQStateReading, to: DLQStateExecuting},
+		{from: DLQStateExecuting, to: DLQStateReading},
+		{from: DLQStateReading, to: DLQStateCommitting},
+		{from: DLQStateCommitting, to: DLQStateIdle},
+	}, observed)
+}
+
+func TestDLQStateMachine_WaitIdle(t *testing.T) {
+	stateMachine := newDLQStateMachine(nil, metrics.NewNoopMetricsClient())
+	assert.NoError(t, stateMachine.waitIdle(context.Background()))
+
+	stateMachine.begin()
+	stateMachine.begin()
+	stateMachine.end()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, stateMachine.waitIdle(ctx))
+
+	idle := make(chan error, 1)
+	go func() {
+		idle <- stateMachine.waitIdle(context.Background())
+	}()
+	stateMachine.end()
+	select {
+	case err := <-idle:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for the merges to end")
+	}
+}
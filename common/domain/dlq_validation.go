@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"fmt"
+
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// InvalidTaskPolicyNone executes the tasks without validating them
+	InvalidTaskPolicyNone InvalidTaskPolicy = "None"
+	// InvalidTaskPolicyFail fails an invalid task like any other task failing to execute,
+	// so it stays in the DLQ until it exhausts its retry attempts
+	InvalidTaskPolicyFail InvalidTaskPolicy = "Fail"
+	// InvalidTaskPolicySkip removes an invalid task from the DLQ without executing it
+	InvalidTaskPolicySkip InvalidTaskPolicy = "Skip"
+)
+
+type (
+	// InvalidTaskPolicy tells how a merge handles the DLQ messages which fail ValidateReplicationTask
+	InvalidTaskPolicy string
+
+	// ErrInvalidTask is returned by ValidateReplicationTask for a replication task missing a field
+	// its task type requires, Field is the path of the missing field
+	ErrInvalidTask struct {
+		TaskType string
+		Field    string
+	}
+)
+
+func (e *ErrInvalidTask) Error() string {
+	return fmt.Sprintf("invalid %v replication task: missing %v", e.TaskType, e.Field)
+}
+
+// ValidateReplicationTask checks the replication task has the fields its task type requires,
+// so that a task enqueued incomplete fails before it is executed. Task types without attributes are not validated.
+func ValidateReplicationTask(task *types.ReplicationTask) error {
+	if task == nil {
+		return &ErrInvalidTask{TaskType: "unknown", Field: "ReplicationTask"}
+	}
+	if task.TaskType == nil {
+		return &ErrInvalidTask{TaskType: "unknown", Field: "TaskType"}
+	}
+
+	taskType := task.GetTaskType()
+	missing := func(field string) error {
+		return &ErrInvalidTask{TaskType: taskType.String(), Field: field}
+	}
+	switch taskType {
+	case types.ReplicationTaskTypeDomain:
+		attributes := task.DomainTaskAttributes
+		switch {
+		case attributes == nil:
+			return missing("DomainTaskAttributes")
+		case attributes.DomainOperation == nil:
+			return missing("DomainTaskAttributes.DomainOperation")
+		case attributes.ID == "":
+			return missing("DomainTaskAttributes.ID")
+		case attributes.Info == nil:
+			return missing("DomainTaskAttributes.Info")
+		case attributes.Config == nil:
+			return missing("DomainTaskAttributes.Config")
+		case attributes.ReplicationConfig == nil:
+			return missing("DomainTaskAttributes.ReplicationConfig")
+		}
+	case types.ReplicationTaskTypeHistoryV2:
+		attributes := task.HistoryTaskV2Attributes
+		switch {
+		case attributes == nil:
+			return missing("HistoryTaskV2Attributes")
+		case attributes.DomainID == "":
+			return missing("HistoryTaskV2Attributes.DomainID")
+		case attributes.WorkflowID == "":
+			return missing("HistoryTaskV2Attributes.WorkflowID")
+		case attributes.RunID == "":
+			return missing("HistoryTaskV2Attributes.RunID")
+		case attributes.Events == nil:
+			return missing("HistoryTaskV2Attributes.Events")
+		}
+	case types.ReplicationTaskTypeSyncShardStatus:
+		if task.SyncShardStatusTaskAttributes == nil {
+			return missing("SyncShardStatusTaskAttributes")
+		}
+	case types.ReplicationTaskTypeSyncActivity:
+		attributes := task.SyncActivityTaskAttributes
+		switch {
+		case attributes == nil:
+			return missing("SyncActivityTaskAttributes")
+		case attributes.DomainID == "":
+			return missing("SyncActivityTaskAttributes.DomainID")
+		}
+	case types.ReplicationTaskTypeFailoverMarker:
+		if task.FailoverMarkerAttributes == nil {
+			return missing("FailoverMarkerAttributes")
+		}
+	case types.ReplicationTaskTypeWorkflowReset:
+		attributes := task.WorkflowResetTaskAttributes
+		switch {
+		case attributes == nil:
+			return missing("WorkflowResetTaskAttributes")
+		case attributes.DomainID == "":
+			return missing("WorkflowResetTaskAttributes.DomainID")
+		case attributes.WorkflowID == "":
+			return missing("WorkflowResetTaskAttributes.WorkflowID")
+		case attributes.RunID == "":
+			return missing("WorkflowResetTaskAttributes.RunID")
+		case attributes.RequestID == "":
+			return missing("WorkflowResetTaskAttributes.RequestID")
+		}
+	}
+	return nil
+}
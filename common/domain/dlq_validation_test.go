@@ -0,0 +1,181 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func newValidDomainTaskAttributes() *types.DomainTaskAttributes {
+	return &types.DomainTaskAttributes{
+		DomainOperation:   types.DomainOperationCreate.Ptr(),
+		ID:                "domain-id",
+		Info:              &types.DomainInfo{Name: "domain"},
+		Config:            &types.DomainConfiguration{},
+		ReplicationConfig: &types.DomainReplicationConfiguration{},
+	}
+}
+
+func TestValidateReplicationTask(t *testing.T) {
+	withDomainAttributes := func(update func(*types.DomainTaskAttributes)) *types.ReplicationTask {
+		attributes := newValidDomainTaskAttributes()
+		update(attributes)
+		return &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), DomainTaskAttributes: attributes}
+	}
+	withHistoryAttributes := func(update func(*types.HistoryTaskV2Attributes)) *types.ReplicationTask {
+		attributes := newHistoryTaskV2Attributes()
+		update(attributes)
+		return &types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), HistoryTaskV2Attributes: attributes}
+	}
+
+	tests := []struct {
+		name          string
+		task          *types.ReplicationTask
+		expectedError error
+	}{
+		{
+			name:          "nil task",
+			task:          nil,
+			expectedError: &ErrInvalidTask{TaskType: "unknown", Field: "ReplicationTask"},
+		},
+		{
+			name:          "missing task type",
+			task:          &types.ReplicationTask{DomainTaskAttributes: newValidDomainTaskAttributes()},
+			expectedError: &ErrInvalidTask{TaskType: "unknown", Field: "TaskType"},
+		},
+		{
+			name: "valid domain task",
+			task: withDomainAttributes(func(*types.DomainTaskAttributes) {}),
+		},
+		{
+			name:          "domain task without attributes",
+			task:          &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr()},
+			expectedError: &ErrInvalidTask{TaskType: "Domain", Field: "DomainTaskAttributes"},
+		},
+		{
+			name:          "domain task without operation",
+			task:          withDomainAttributes(func(a *types.DomainTaskAttributes) { a.DomainOperation = nil }),
+			expectedError: &ErrInvalidTask{TaskType: "Domain", Field: "DomainTaskAttributes.DomainOperation"},
+		},
+		{
+			name:          "domain task without ID",
+			task:          withDomainAttributes(func(a *types.DomainTaskAttributes) { a.ID = "" }),
+			expectedError: &ErrInvalidTask{TaskType: "Domain", Field: "DomainTaskAttributes.ID"},
+		},
+		{
+			name:          "domain task without info",
+			task:          withDomainAttributes(func(a *types.DomainTaskAttributes) { a.Info = nil }),
+			expectedError: &ErrInvalidTask{TaskType: "Domain", Field: "DomainTaskAttributes.Info"},
+		},
+		{
+			name:          "domain task without config",
+			task:          withDomainAttributes(func(a *types.DomainTaskAttributes) { a.Config = nil }),
+			expectedError: &ErrInvalidTask{TaskType: "Domain", Field: "DomainTaskAttributes.Config"},
+		},
+		{
+			name:          "domain task without replication config",
+			task:          withDomainAttributes(func(a *types.DomainTaskAttributes) { a.ReplicationConfig = nil }),
+			expectedError: &ErrInvalidTask{TaskType: "Domain", Field: "DomainTaskAttributes.ReplicationConfig"},
+		},
+		{
+			name: "valid history task",
+			task: withHistoryAttributes(func(*types.HistoryTaskV2Attributes) {}),
+		},
+		{
+			name:          "history task without attributes",
+			task:          &types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr()},
+			expectedError: &ErrInvalidTask{TaskType: "HistoryV2", Field: "HistoryTaskV2Attributes"},
+		},
+		{
+			name:          "history task without domain ID",
+			task:          withHistoryAttributes(func(a *types.HistoryTaskV2Attributes) { a.DomainID = "" }),
+			expectedError: &ErrInvalidTask{TaskType: "HistoryV2", Field: "HistoryTaskV2Attributes.DomainID"},
+		},
+		{
+			name:          "history task without workflow ID",
+			task:          withHistoryAttributes(func(a *types.HistoryTaskV2Attributes) { a.WorkflowID = "" }),
+			expectedError: &ErrInvalidTask{TaskType: "HistoryV2", Field: "HistoryTaskV2Attributes.WorkflowID"},
+		},
+		{
+			name:          "history task without run ID",
+			task:          withHistoryAttributes(func(a *types.HistoryTaskV2Attributes) { a.RunID = "" }),
+			expectedError: &ErrInvalidTask{TaskType: "HistoryV2", Field: "HistoryTaskV2Attributes.RunID"},
+		},
+		{
+			name:          "history task without events",
+			task:          withHistoryAttributes(func(a *types.HistoryTaskV2Attributes) { a.Events = nil }),
+			expectedError: &ErrInvalidTask{TaskType: "HistoryV2", Field: "HistoryTaskV2Attributes.Events"},
+		},
+		{
+			name:          "sync shard status task without attributes",
+			task:          &types.ReplicationTask{TaskType: types.ReplicationTaskTypeSyncShardStatus.Ptr()},
+			expectedError: &ErrInvalidTask{TaskType: "SyncShardStatus", Field: "SyncShardStatusTaskAttributes"},
+		},
+		{
+			name: "sync activity task without domain ID",
+			task: &types.ReplicationTask{
+				TaskType:                   types.ReplicationTaskTypeSyncActivity.Ptr(),
+				SyncActivityTaskAttributes: &types.SyncActivityTaskAttributes{},
+			},
+			expectedError: &ErrInvalidTask{TaskType: "SyncActivity", Field: "SyncActivityTaskAttributes.DomainID"},
+		},
+		{
+			name:          "failover marker task without attributes",
+			task:          &types.ReplicationTask{TaskType: types.ReplicationTaskTypeFailoverMarker.Ptr()},
+			expectedError: &ErrInvalidTask{TaskType: "FailoverMarker", Field: "FailoverMarkerAttributes"},
+		},
+		{
+			name:          "workflow reset task without attributes",
+			task:          &types.ReplicationTask{TaskType: types.ReplicationTaskTypeWorkflowReset.Ptr()},
+			expectedError: &ErrInvalidTask{TaskType: "WorkflowReset", Field: "WorkflowResetTaskAttributes"},
+		},
+		{
+			name: "workflow reset task without request ID",
+			task: &types.ReplicationTask{
+				TaskType: types.ReplicationTaskTypeWorkflowReset.Ptr(),
+				WorkflowResetTaskAttributes: &types.WorkflowResetTaskAttributes{
+					DomainID:   "domain-id",
+					WorkflowID: "workflow-id",
+					RunID:      "run-id",
+				},
+			},
+			expectedError: &ErrInvalidTask{TaskType: "WorkflowReset", Field: "WorkflowResetTaskAttributes.RequestID"},
+		},
+		{
+			name: "task type without attributes",
+			task: &types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryMetadata.Ptr()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedError, ValidateReplicationTask(tt.task))
+		})
+	}
+}
+
+func TestErrInvalidTask(t *testing.T) {
+	err := &ErrInvalidTask{TaskType: "Domain", Field: "DomainTaskAttributes.ID"}
+	assert.EqualError(t, err, "invalid Domain replication task: missing DomainTaskAttributes.ID")
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination failoverMarkerReplicationTaskExecutor_mock.go -self_package github.com/uber/cadence/common/domain -aux_files github.com/uber/cadence/common/domain=replicationTaskExecutor.go
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+var (
+	// ErrEmptyFailoverMarkerTask is the error to indicate empty failover marker replication task
+	ErrEmptyFailoverMarkerTask = &types.BadRequestError{Message: "empty failover marker replication task"}
+	// ErrStaleFailoverMarker is the error to indicate the failover version of the failover marker is not greater than
+	// the current failover version of its domain, the DLQ handler skips and deletes the task
+	ErrStaleFailoverMarker = errors.New("failover marker is older than the current domain failover version")
+)
+
+type (
+	// FailoverMarkerReplicationTaskExecutor is the interface which is to execute failover marker replication tasks,
+	// it can be registered as the executor of the failover marker replication tasks of the domain DLQ
+	FailoverMarkerReplicationTaskExecutor interface {
+		ReplicationTaskExecutor
+		// ExecuteFailoverMarkerTask applies the failover marker of the task,
+		// it returns ErrStaleFailoverMarker if the domain already failed over to the version of the marker or later
+		ExecuteFailoverMarkerTask(ctx context.Context, attributes *types.FailoverMarkerAttributes) error
+	}
+
+	failoverMarkerReplicationTaskExecutorImpl struct {
+		domainManager persistence.DomainManager
+		historyClient history.Client
+		logger        log.Logger
+
+		sync.Mutex
+		// failoverVersions are the versions of the last markers applied per domain ID, the markers are applied before
+		// the failover version of the domain is updated, so they are compared with the applied markers as well
+		failoverVersions map[string]int64
+	}
+)
+
+var _ FailoverMarkerReplicationTaskExecutor = (*failoverMarkerReplicationTaskExecutorImpl)(nil)
+
+// NewFailoverMarkerReplicationTaskExecutor creates a new instance of failover marker replication task executor,
+// which hands the failover markers to the failover coordinator of the history service
+func NewFailoverMarkerReplicationTaskExecutor(
+	domainManager persistence.DomainManager,
+	historyClient history.Client,
+	logger log.Logger,
+) FailoverMarkerReplicationTaskExecutor {
+
+	return &failoverMarkerReplicationTaskExecutorImpl{
+		domainManager:    domainManager,
+		historyClient:    historyClient,
+		logger:           logger,
+		failoverVersions: make(map[string]int64),
+	}
+}
+
+// ExecuteReplicationTask executes the failover marker replication task, tasks of the other types are not supported
+func (e *failoverMarkerReplicationTaskExecutorImpl) ExecuteReplicationTask(task *types.ReplicationTask, _ string) error {
+	if task.GetTaskType() != types.ReplicationTaskTypeFailoverMarker {
+		return ErrUnsupportedReplicationTaskType
+	}
+	attributes := task.FailoverMarkerAttributes
+	if attributes != nil && attributes.CreationTime == nil {
+		// the marker records when it was created on the task
+		marker := *attributes
+		marker.CreationTime = task.CreationTime
+		attributes = &marker
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWorkflowReplicationTaskContextTimeout)
+	defer cancel()
+	return e.ExecuteFailoverMarkerTask(ctx, attributes)
+}
+
+// ExecuteFailoverMarkerTask applies the failover marker if its failover version is greater than the current failover
+// version of the domain, the markers of a domain are applied one at a time so they are never applied out of order
+func (e *failoverMarkerReplicationTaskExecutorImpl) ExecuteFailoverMarkerTask(
+	ctx context.Context,
+	attributes *types.FailoverMarkerAttributes,
+) error {
+
+	if attributes == nil || attributes.DomainID == "" {
+		return ErrEmptyFailoverMarkerTask
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	resp, err := e.domainManager.GetDomain(ctx, &persistence.GetDomainRequest{ID: attributes.DomainID})
+	if err != nil {
+		return err
+	}
+	currentVersion := resp.FailoverVersion
+	if appliedVersion, ok := e.failoverVersions[attributes.DomainID]; ok && appliedVersion > currentVersion {
+		currentVersion = appliedVersion
+	}
+	if attributes.FailoverVersion <= currentVersion {
+		e.logger.Warn("Skipping stale failover marker",
+			tag.WorkflowDomainID(attributes.DomainID),
+			tag.FailoverVersion(attributes.FailoverVersion),
+			tag.CurrentVersion(currentVersion))
+		return ErrStaleFailoverMarker
+	}
+
+	if err := e.historyClient.NotifyFailoverMarkers(ctx, &types.NotifyFailoverMarkersRequest{
+		FailoverMarkerTokens: []*types.FailoverMarkerToken{{FailoverMarker: attributes}},
+	}); err != nil {
+		return err
+	}
+	e.failoverVersions[attributes.DomainID] = attributes.FailoverVersion
+	return nil
+}
+
+// Execute does not support domain replication tasks
+func (e *failoverMarkerReplicationTaskExecutorImpl) Execute(*types.DomainTaskAttributes) error {
+	return ErrUnsupportedReplicationTaskType
+}
+
+// Overwrite does not support domain replication tasks
+func (e *failoverMarkerReplicationTaskExecutorImpl) Overwrite(*types.DomainTaskAttributes) error {
+	return ErrUnsupportedReplicationTaskType
+}
+
+// AddMigration does nothing, the migrations only apply to domain replication tasks
+func (e *failoverMarkerReplicationTaskExecutorImpl) AddMigration(int, int, MigrationFunc) error {
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+func newFailoverMarkerAttributes(failoverVersion int64) *types.FailoverMarkerAttributes {
+	return &types.FailoverMarkerAttributes{
+		DomainID:        "domain-id",
+		FailoverVersion: failoverVersion,
+		CreationTime:    common.Int64Ptr(100),
+	}
+}
+
+func expectNotifiedFailoverMarker(client *history.MockClient, marker *types.FailoverMarkerAttributes) *gomock.Call {
+	return client.EXPECT().NotifyFailoverMarkers(gomock.Any(), &types.NotifyFailoverMarkersRequest{
+		FailoverMarkerTokens: []*types.FailoverMarkerToken{{FailoverMarker: marker}},
+	}).Return(nil).Times(1)
+}
+
+func TestFailoverMarkerReplicationTaskExecutor_ExecuteFailoverMarkerTask(t *testing.T) {
+	historyErr := &types.InternalServiceError{Message: "history"}
+	domainErr := &types.EntityNotExistsError{Message: "domain"}
+
+	tests := []struct {
+		name          string
+		attributes    *types.FailoverMarkerAttributes
+		mockSetup     func(*persistence.MockDomainManager, *history.MockClient)
+		expectedError error
+	}{
+		{
+			name:       "success",
+			attributes: newFailoverMarkerAttributes(20),
+			mockSetup: func(domainManager *persistence.MockDomainManager, client *history.MockClient) {
+				domainManager.EXPECT().GetDomain(gomock.Any(), &persistence.GetDomainRequest{ID: "domain-id"}).
+					Return(&persistence.GetDomainResponse{FailoverVersion: 10}, nil).Times(1)
+				expectNotifiedFailoverMarker(client, newFailoverMarkerAttributes(20))
+			},
+		},
+		{
+			name:       "marker of the current failover version",
+			attributes: newFailoverMarkerAttributes(10),
+			mockSetup: func(domainManager *persistence.MockDomainManager, client *history.MockClient) {
+				domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).
+					Return(&persistence.GetDomainResponse{FailoverVersion: 10}, nil).Times(1)
+			},
+			expectedError: ErrStaleFailoverMarker,
+		},
+		{
+			name:       "marker of an older failover version",
+			attributes: newFailoverMarkerAttributes(5),
+			mockSetup: func(domainManager *persistence.MockDomainManager, client *history.MockClient) {
+				domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).
+					Return(&persistence.GetDomainResponse{FailoverVersion: 10}, nil).Times(1)
+			},
+			expectedError: ErrStaleFailoverMarker,
+		},
+		{
+			name:       "domain error",
+			attributes: newFailoverMarkerAttributes(20),
+			mockSetup: func(domainManager *persistence.MockDomainManager, client *history.MockClient) {
+				domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).Return(nil, domainErr).Times(1)
+			},
+			expectedError: domainErr,
+		},
+		{
+			name:       "history service error",
+			attributes: newFailoverMarkerAttributes(20),
+			mockSetup: func(domainManager *persistence.MockDomainManager, client *history.MockClient) {
+				domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).
+					Return(&persistence.GetDomainResponse{FailoverVersion: 10}, nil).Times(1)
+				client.EXPECT().NotifyFailoverMarkers(gomock.Any(), gomock.Any()).Return(historyErr).Times(1)
+			},
+			expectedError: historyErr,
+		},
+		{
+			name:          "empty failover marker task",
+			mockSetup:     func(*persistence.MockDomainManager, *history.MockClient) {},
+			expectedError: ErrEmptyFailoverMarkerTask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			domainManager := persistence.NewMockDomainManager(controller)
+			historyClient := history.NewMockClient(controller)
+			tt.mockSetup(domainManager, historyClient)
+			executor := NewFailoverMarkerReplicationTaskExecutor(domainManager, historyClient, loggerimpl.NewNopLogger())
+
+			err := executor.ExecuteFailoverMarkerTask(context.Background(), tt.attributes)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestFailoverMarkerReplicationTaskExecutor_InOrder(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	historyClient := history.NewMockClient(controller)
+	executor := NewFailoverMarkerReplicationTaskExecutor(domainManager, historyClient, loggerimpl.NewNopLogger())
+
+	// the failover version of the domain is updated only once the markers are applied
+	domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetDomainResponse{FailoverVersion: 10}, nil).Times(3)
+	gomock.InOrder(
+		expectNotifiedFailoverMarker(historyClient, newFailoverMarkerAttributes(20)),
+		expectNotifiedFailoverMarker(historyClient, newFailoverMarkerAttributes(30)),
+		expectNotifiedFailoverMarker(historyClient, newFailoverMarkerAttributes(40)),
+	)
+	for _, failoverVersion := range []int64{20, 30, 40} {
+		assert.NoError(t, executor.ExecuteFailoverMarkerTask(context.Background(), newFailoverMarkerAttributes(failoverVersion)))
+	}
+}
+
+func TestFailoverMarkerReplicationTaskExecutor_OutOfOrder(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	historyClient := history.NewMockClient(controller)
+	executor := NewFailoverMarkerReplicationTaskExecutor(domainManager, historyClient, loggerimpl.NewNopLogger())
+
+	domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetDomainResponse{FailoverVersion: 10}, nil).Times(4)
+	gomock.InOrder(
+		expectNotifiedFailoverMarker(historyClient, newFailoverMarkerAttributes(30)),
+		expectNotifiedFailoverMarker(historyClient, newFailoverMarkerAttributes(40)),
+	)
+	assert.NoError(t, executor.ExecuteFailoverMarkerTask(context.Background(), newFailoverMarkerAttributes(30)))
+	// the marker of the earlier failover arrives after the one of the later failover, it is never applied
+	assert.Equal(t, ErrStaleFailoverMarker, executor.ExecuteFailoverMarkerTask(context.Background(), newFailoverMarkerAttributes(20)))
+	assert.Equal(t, ErrStaleFailoverMarker, executor.ExecuteFailoverMarkerTask(context.Background(), newFailoverMarkerAttributes(30)))
+	assert.NoError(t, executor.ExecuteFailoverMarkerTask(context.Background(), newFailoverMarkerAttributes(40)))
+}
+
+func TestFailoverMarkerReplicationTaskExecutor_ExecuteReplicationTask(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	historyClient := history.NewMockClient(controller)
+	executor := NewFailoverMarkerReplicationTaskExecutor(domainManager, historyClient, loggerimpl.NewNopLogger())
+
+	// the marker takes the creation time of the task
+	domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).
+		Return(&persistence.GetDomainResponse{FailoverVersion: 10}, nil).Times(1)
+	expectNotifiedFailoverMarker(historyClient, newFailoverMarkerAttributes(20))
+	assert.NoError(t, executor.ExecuteReplicationTask(&types.ReplicationTask{
+		TaskType:                 types.ReplicationTaskTypeFailoverMarker.Ptr(),
+		CreationTime:             common.Int64Ptr(100),
+		FailoverMarkerAttributes: &types.FailoverMarkerAttributes{DomainID: "domain-id", FailoverVersion: 20},
+	}, "cluster"))
+
+	// the executor only supports failover marker replication tasks
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.ExecuteReplicationTask(&types.ReplicationTask{
+		TaskType:                   types.ReplicationTaskTypeSyncActivity.Ptr(),
+		SyncActivityTaskAttributes: newSyncActivityTaskAttributes(),
+	}, "cluster"))
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.Execute(&types.DomainTaskAttributes{ID: "domain-id"}))
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.Overwrite(&types.DomainTaskAttributes{ID: "domain-id"}))
+	assert.NoError(t, executor.AddMigration(1, 2, func(attributes *types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		return nil, errors.New("migrations are not applied to failover marker replication tasks")
+	}))
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"sort"
+	"sync"
+
+	"go.uber.org/multierr"
+
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// fanOutMaxTrackedTasks bounds the partially applied tasks the fan-out executor tracks,
+	// the destinations of the tasks which are no longer tracked are applied again on retry
+	fanOutMaxTrackedTasks = 10000
+)
+
+type (
+	fanOutTaskKey struct {
+		sourceCluster string
+		sourceTaskID  int64
+	}
+
+	fanOutReplicationTaskExecutorImpl struct {
+		destinations     map[string]ReplicationTaskExecutor
+		destinationNames []string
+
+		sync.Mutex
+		// applied are the destinations a partially applied task succeeded on, removed once the task is applied everywhere
+		applied map[fanOutTaskKey]map[string]struct{}
+	}
+)
+
+var _ ReplicationTaskExecutor = (*fanOutReplicationTaskExecutorImpl)(nil)
+
+// NewFanOutReplicationTaskExecutor creates a replication task executor which applies every replication task
+// to the executors of all destination clusters concurrently, for active-active setups in which a DLQ message
+// has to reach multiple clusters. A task fails if it fails on any destination and the errors of the failed
+// destinations are combined. The destinations the task succeeded on are tracked by its source cluster and
+// task ID, so a retry of the task only applies it to the destinations it failed on.
+func NewFanOutReplicationTaskExecutor(
+	destinations map[string]ReplicationTaskExecutor,
+) ReplicationTaskExecutor {
+
+	destinationNames := make([]string, 0, len(destinations))
+	for destination := range destinations {
+		destinationNames = append(destinationNames, destination)
+	}
+	sort.Strings(destinationNames)
+	return &fanOutReplicationTaskExecutorImpl{
+		destinations:     destinations,
+		destinationNames: destinationNames,
+		applied:          make(map[fanOutTaskKey]map[string]struct{}),
+	}
+}
+
+// RegisterFanOutExecutor registers an executor of the task type applying the tasks to the local cluster with
+// the executor registered for the task type before, and to the destinations, keyed by cluster name
+func RegisterFanOutExecutor(
+	registry ReplicationTaskExecutorRegistry,
+	taskType types.ReplicationTaskType,
+	localCluster string,
+	destinations map[string]ReplicationTaskExecutor,
+) {
+
+	fanOutDestinations := make(map[string]ReplicationTaskExecutor, len(destinations)+1)
+	for destination, executor := range destinations {
+		fanOutDestinations[destination] = executor
+	}
+	if executor, ok := registry.GetExecutor(taskType); ok {
+		fanOutDestinations[localCluster] = executor
+	}
+	registry.RegisterExecutor(taskType, NewFanOutReplicationTaskExecutor(fanOutDestinations))
+}
+
+// ExecuteReplicationTask executes the replication task on the destinations it has not been applied to yet.
+// A destination on which the task is a duplicate or obsolete does not need it and counts as applied.
+func (e *fanOutReplicationTaskExecutorImpl) ExecuteReplicationTask(task *types.ReplicationTask, sourceCluster string) error {
+	key := fanOutTaskKey{sourceCluster: sourceCluster, sourceTaskID: task.GetSourceTaskID()}
+	pending := e.pendingDestinations(key)
+	errs := e.fanOut(pending, func(executor ReplicationTaskExecutor) error {
+		err := executor.ExecuteReplicationTask(task, sourceCluster)
+		if err == ErrDuplicateTask || err == ErrWorkflowNotFound || err == ErrWorkflowCompleted || err == ErrStaleFailoverMarker {
+			return nil
+		}
+		return err
+	})
+
+	e.Lock()
+	defer e.Unlock()
+
+	var combined error
+	applied := e.applied[key]
+	for i, destination := range pending {
+		if errs[i] != nil {
+			combined = multierr.Append(combined, errs[i])
+			continue
+		}
+		if applied == nil {
+			applied = make(map[string]struct{}, len(e.destinations))
+		}
+		applied[destination] = struct{}{}
+	}
+	if combined == nil {
+		delete(e.applied, key)
+		return nil
+	}
+	if _, ok := e.applied[key]; !ok && len(e.applied) >= fanOutMaxTrackedTasks {
+		// applying the task again to the destinations it succeeded on is safe, as they reject it as a duplicate
+		e.applied = make(map[fanOutTaskKey]map[string]struct{})
+	}
+	if applied != nil {
+		e.applied[key] = applied
+	}
+	return combined
+}
+
+// Execute executes the domain replication task on every destination
+func (e *fanOutReplicationTaskExecutorImpl) Execute(task *types.DomainTaskAttributes) error {
+	return multierr.Combine(e.fanOut(e.destinationNames, func(executor ReplicationTaskExecutor) error {
+		return executor.Execute(task)
+	})...)
+}
+
+// Overwrite overwrites the domain on every destination
+func (e *fanOutReplicationTaskExecutorImpl) Overwrite(task *types.DomainTaskAttributes) error {
+	return multierr.Combine(e.fanOut(e.destinationNames, func(executor ReplicationTaskExecutor) error {
+		return executor.Overwrite(task)
+	})...)
+}
+
+// AddMigration registers the migration with the executor of every destination
+func (e *fanOutReplicationTaskExecutorImpl) AddMigration(fromVersion, toVersion int, fn MigrationFunc) error {
+	// an executor may be the executor of several destinations
+	registered := make(map[ReplicationTaskExecutor]struct{}, len(e.destinations))
+	for _, destination := range e.destinationNames {
+		executor := e.destinations[destination]
+		if _, ok := registered[executor]; ok {
+			continue
+		}
+		if err := executor.AddMigration(fromVersion, toVersion, fn); err != nil {
+			return err
+		}
+		registered[executor] = struct{}{}
+	}
+	return nil
+}
+
+// pendingDestinations returns the destinations the task has not been applied to yet
+func (e *fanOutReplicationTaskExecutorImpl) pendingDestinations(key fanOutTaskKey) []string {
+	e.Lock()
+	defer e.Unlock()
+
+	applied := e.applied[key]
+	if len(applied) == 0 {
+		return e.destinationNames
+	}
+	pending := make([]string, 0, len(e.destinationNames)-len(applied))
+	for _, destination := range e.destinationNames {
+		if _, ok := applied[destination]; !ok {
+			pending = append(pending, destination)
+		}
+	}
+	return pending
+}
+
+// fanOut calls fn with the executors of the destinations concurrently and returns their errors in the order of the destinations
+func (e *fanOutReplicationTaskExecutorImpl) fanOut(destinations []string, fn func(ReplicationTaskExecutor) error) []error {
+	errs := make([]error, len(destinations))
+	var wg sync.WaitGroup
+	wg.Add(len(destinations))
+	for i, destination := range destinations {
+		go func(i int, executor ReplicationTaskExecutor) {
+			defer wg.Done()
+			errs[i] = fn(executor)
+		}(i, e.destinations[destination])
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestFanOutReplicationTaskExecutor_ExecutesConcurrently(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11}
+	destinations := map[string]ReplicationTaskExecutor{}
+	// every destination blocks until all of them were called, which only completes if they are called concurrently
+	var entered sync.WaitGroup
+	entered.Add(3)
+	allEntered := make(chan struct{})
+	go func() {
+		entered.Wait()
+		close(allEntered)
+	}()
+	for _, cluster := range []string{"cluster-a", "cluster-b", "cluster-c"} {
+		executor := NewMockReplicationTaskExecutor(controller)
+		executor.EXPECT().ExecuteReplicationTask(task, "standby").DoAndReturn(func(*types.ReplicationTask, string) error {
+			entered.Done()
+			select {
+			case <-allEntered:
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("destinations were not called concurrently")
+			}
+		}).Times(1)
+		destinations[cluster] = executor
+	}
+
+	executor := NewFanOutReplicationTaskExecutor(destinations)
+	assert.NoError(t, executor.ExecuteReplicationTask(task, "standby"))
+}
+
+func TestFanOutReplicationTaskExecutor_RetriesFailedDestinations(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11}
+	errB := errors.New("cluster-b unavailable")
+	errC := errors.New("cluster-c unavailable")
+	executorA := NewMockReplicationTaskExecutor(controller)
+	executorB := NewMockReplicationTaskExecutor(controller)
+	executorC := NewMockReplicationTaskExecutor(controller)
+	gomock.InOrder(
+		executorA.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil),
+		// the task is applied to every destination again once it completed
+		executorA.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil),
+	)
+	gomock.InOrder(
+		executorB.EXPECT().ExecuteReplicationTask(task, "standby").Return(errB),
+		executorB.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil),
+		executorB.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil),
+	)
+	gomock.InOrder(
+		executorC.EXPECT().ExecuteReplicationTask(task, "standby").Return(errC),
+		executorC.EXPECT().ExecuteReplicationTask(task, "standby").Return(errC),
+		executorC.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil),
+		executorC.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil),
+	)
+	executor := NewFanOutReplicationTaskExecutor(map[string]ReplicationTaskExecutor{
+		"cluster-a": executorA,
+		"cluster-b": executorB,
+		"cluster-c": executorC,
+	})
+
+	err := executor.ExecuteReplicationTask(task, "standby")
+	assert.True(t, errors.Is(err, errB))
+	assert.True(t, errors.Is(err, errC))
+	// only the destinations the task failed on are retried
+	assert.Equal(t, errC, executor.ExecuteReplicationTask(task, "standby"))
+	assert.NoError(t, executor.ExecuteReplicationTask(task, "standby"))
+	assert.NoError(t, executor.ExecuteReplicationTask(task, "standby"))
+}
+
+func TestFanOutReplicationTaskExecutor_TracksTasksBySourceTaskID(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11}
+	otherTask := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 12}
+	errB := errors.New("cluster-b unavailable")
+	executorA := NewMockReplicationTaskExecutor(controller)
+	executorB := NewMockReplicationTaskExecutor(controller)
+	executorA.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil).Times(1)
+	executorA.EXPECT().ExecuteReplicationTask(otherTask, "standby").Return(nil).Times(1)
+	executorB.EXPECT().ExecuteReplicationTask(task, "standby").Return(errB).Times(2)
+	executorB.EXPECT().ExecuteReplicationTask(otherTask, "standby").Return(nil).Times(1)
+	executor := NewFanOutReplicationTaskExecutor(map[string]ReplicationTaskExecutor{
+		"cluster-a": executorA,
+		"cluster-b": executorB,
+	})
+
+	assert.Equal(t, errB, executor.ExecuteReplicationTask(task, "standby"))
+	assert.NoError(t, executor.ExecuteReplicationTask(otherTask, "standby"))
+	assert.Equal(t, errB, executor.ExecuteReplicationTask(task, "standby"))
+}
+
+func TestFanOutReplicationTaskExecutor_DuplicateCountsAsApplied(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11}
+	errB := errors.New("cluster-b unavailable")
+	executorA := NewMockReplicationTaskExecutor(controller)
+	executorB := NewMockReplicationTaskExecutor(controller)
+	executorA.EXPECT().ExecuteReplicationTask(task, "standby").Return(ErrDuplicateTask).Times(1)
+	gomock.InOrder(
+		executorB.EXPECT().ExecuteReplicationTask(task, "standby").Return(errB),
+		executorB.EXPECT().ExecuteReplicationTask(task, "standby").Return(ErrWorkflowCompleted),
+	)
+	executor := NewFanOutReplicationTaskExecutor(map[string]ReplicationTaskExecutor{
+		"cluster-a": executorA,
+		"cluster-b": executorB,
+	})
+
+	assert.Equal(t, errB, executor.ExecuteReplicationTask(task, "standby"))
+	assert.NoError(t, executor.ExecuteReplicationTask(task, "standby"))
+}
+
+func TestFanOutReplicationTaskExecutor_ConcurrentTasks(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	errFailed := errors.New("odd tasks fail")
+	destinations := map[string]ReplicationTaskExecutor{}
+	for _, cluster := range []string{"cluster-a", "cluster-b"} {
+		executor := NewMockReplicationTaskExecutor(controller)
+		executor.EXPECT().ExecuteReplicationTask(gomock.Any(), "standby").DoAndReturn(func(task *types.ReplicationTask, _ string) error {
+			if task.SourceTaskID%2 == 1 {
+				return errFailed
+			}
+			return nil
+		}).AnyTimes()
+		destinations[cluster] = executor
+	}
+	executor := NewFanOutReplicationTaskExecutor(destinations)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 100)
+	for i := 0; i < len(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: int64(i)}
+			errs[i] = executor.ExecuteReplicationTask(task, "standby")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if i%2 == 1 {
+			assert.True(t, errors.Is(err, errFailed))
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+	assert.Empty(t, executor.(*fanOutReplicationTaskExecutorImpl).applied)
+}
+
+func TestFanOutReplicationTaskExecutor_ExecuteAndOverwrite(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	task := &types.DomainTaskAttributes{ID: "id-1"}
+	errB := errors.New("cluster-b unavailable")
+	executorA := NewMockReplicationTaskExecutor(controller)
+	executorB := NewMockReplicationTaskExecutor(controller)
+	executorA.EXPECT().Execute(task).Return(nil).Times(1)
+	executorB.EXPECT().Execute(task).Return(errB).Times(1)
+	executorA.EXPECT().Overwrite(task).Return(nil).Times(1)
+	executorB.EXPECT().Overwrite(task).Return(nil).Times(1)
+	executor := NewFanOutReplicationTaskExecutor(map[string]ReplicationTaskExecutor{
+		"cluster-a": executorA,
+		"cluster-b": executorB,
+	})
+
+	assert.Equal(t, errB, executor.Execute(task))
+	assert.NoError(t, executor.Overwrite(task))
+}
+
+func TestFanOutReplicationTaskExecutor_AddMigration(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	shared := NewMockReplicationTaskExecutor(controller)
+	other := NewMockReplicationTaskExecutor(controller)
+	shared.EXPECT().AddMigration(1, 2, gomock.Any()).Return(nil).Times(1)
+	other.EXPECT().AddMigration(1, 2, gomock.Any()).Return(nil).Times(1)
+	executor := NewFanOutReplicationTaskExecutor(map[string]ReplicationTaskExecutor{
+		"cluster-a": shared,
+		"cluster-b": shared,
+		"cluster-c": other,
+	})
+
+	assert.NoError(t, executor.AddMigration(1, 2, func(task *types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		return task, nil
+	}))
+}
+
+func TestRegisterFanOutExecutor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 11}
+	local := NewMockReplicationTaskExecutor(controller)
+	remote := NewMockReplicationTaskExecutor(controller)
+	local.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil).Times(1)
+	remote.EXPECT().ExecuteReplicationTask(task, "standby").Return(nil).Times(1)
+	registry := NewReplicationTaskExecutorRegistry(local)
+
+	RegisterFanOutExecutor(registry, types.ReplicationTaskTypeDomain, "active", map[string]ReplicationTaskExecutor{"remote": remote})
+
+	executor, ok := registry.GetExecutor(types.ReplicationTaskTypeDomain)
+	require.True(t, ok)
+	assert.NoError(t, executor.ExecuteReplicationTask(task, "standby"))
+	// the executors of the other task types are not fanned out
+	executor, ok = registry.GetExecutor(types.ReplicationTaskTypeHistoryV2)
+	require.True(t, ok)
+	assert.Equal(t, local, executor)
+}
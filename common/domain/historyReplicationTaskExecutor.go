@@ -0,0 +1,154 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination historyReplicationTaskExecutor_mock.go -self_package github.com/uber/cadence/common/domain -aux_files github.com/uber/cadence/common/domain=replicationTaskExecutor.go
+
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
+)
+
+var (
+	// ErrDuplicateTask is the error to indicate the history events of the replication task were already applied,
+	// the DLQ handler treats it as a successfully executed task
+	ErrDuplicateTask = errors.New("replication task was already applied")
+)
+
+type (
+	// HistoryReplicationTaskExecutor is the interface which is to execute workflow history replication tasks,
+	// it can be registered as the executor of the history replication tasks of the domain DLQ
+	HistoryReplicationTaskExecutor interface {
+		ReplicationTaskExecutor
+		// ExecuteHistoryTask replicates the history events of the task,
+		// it returns ErrDuplicateTask if the events were already applied
+		ExecuteHistoryTask(ctx context.Context, attributes *types.HistoryTaskV2Attributes) error
+	}
+
+	// HistoryReplicationTaskExecutorOption is an optional configuration of the history replication task executor
+	HistoryReplicationTaskExecutorOption func(*historyReplicationTaskExecutorImpl)
+
+	historyReplicationTaskExecutorImpl struct {
+		historyClient history.Client
+		shardRouter   ShardRouter
+		logger        log.Logger
+	}
+)
+
+var _ HistoryReplicationTaskExecutor = (*historyReplicationTaskExecutorImpl)(nil)
+
+// NewHistoryReplicationTaskExecutor creates a new instance of history replication task executor,
+// which applies the history events through the history service
+func NewHistoryReplicationTaskExecutor(
+	historyClient history.Client,
+	logger log.Logger,
+	opts ...HistoryReplicationTaskExecutorOption,
+) HistoryReplicationTaskExecutor {
+
+	executor := &historyReplicationTaskExecutorImpl{
+		historyClient: historyClient,
+		logger:        logger,
+	}
+	for _, opt := range opts {
+		opt(executor)
+	}
+	return executor
+}
+
+// WithShardRouter routes the tasks to the history shards of their workflows before they are forwarded,
+// a task whose shard has no owner in the membership ring fails and is retried by a later merge
+// instead of being forwarded to a host which does not own the shard
+func WithShardRouter(shardRouter ShardRouter) HistoryReplicationTaskExecutorOption {
+	return func(e *historyReplicationTaskExecutorImpl) {
+		e.shardRouter = shardRouter
+	}
+}
+
+// ExecuteReplicationTask executes the history replication task, tasks of the other types are not supported
+func (e *historyReplicationTaskExecutorImpl) ExecuteReplicationTask(task *types.ReplicationTask, _ string) error {
+	if task.GetTaskType() != types.ReplicationTaskTypeHistoryV2 {
+		return ErrUnsupportedReplicationTaskType
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWorkflowReplicationTaskContextTimeout)
+	defer cancel()
+	return e.ExecuteHistoryTask(ctx, task.HistoryTaskV2Attributes)
+}
+
+// ExecuteHistoryTask replicates the history events of the task to the history service.
+// The history service ignores the events it already applied, so the task can be executed more than once.
+func (e *historyReplicationTaskExecutorImpl) ExecuteHistoryTask(
+	ctx context.Context,
+	attributes *types.HistoryTaskV2Attributes,
+) error {
+
+	if attributes == nil {
+		return ErrEmptyWorkflowReplicationTask
+	}
+
+	if e.shardRouter != nil {
+		shardID, err := e.shardRouter.RouteToShard(attributes.WorkflowID)
+		if err != nil {
+			e.logger.Warn("Failed to route history replication task to its history shard",
+				tag.WorkflowDomainID(attributes.DomainID),
+				tag.WorkflowID(attributes.WorkflowID),
+				tag.WorkflowRunID(attributes.RunID),
+				tag.Error(err))
+			return err
+		}
+		e.logger.Debug("Routed history replication task to its history shard",
+			tag.WorkflowID(attributes.WorkflowID),
+			tag.ShardID(shardID))
+	}
+
+	// the history client forwards the request to the host owning the shard of the workflow
+	return e.historyClient.ReplicateEventsV2(ctx, &types.ReplicateEventsV2Request{
+		DomainUUID: attributes.DomainID,
+		WorkflowExecution: &types.WorkflowExecution{
+			WorkflowID: attributes.WorkflowID,
+			RunID:      attributes.RunID,
+		},
+		VersionHistoryItems: attributes.VersionHistoryItems,
+		Events:              attributes.Events,
+		// new run events does not need version history since there is no prior events
+		NewRunEvents: attributes.NewRunEvents,
+	})
+}
+
+// Execute does not support domain replication tasks
+func (e *historyReplicationTaskExecutorImpl) Execute(*types.DomainTaskAttributes) error {
+	return ErrUnsupportedReplicationTaskType
+}
+
+// Overwrite does not support domain replication tasks
+func (e *historyReplicationTaskExecutorImpl) Overwrite(*types.DomainTaskAttributes) error {
+	return ErrUnsupportedReplicationTaskType
+}
+
+// AddMigration does nothing, the migrations only apply to domain replication tasks
+func (e *historyReplicationTaskExecutorImpl) AddMigration(int, int, MigrationFunc) error {
+	return nil
+}
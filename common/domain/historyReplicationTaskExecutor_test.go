@@ -0,0 +1,192 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+func newHistoryTaskV2Attributes() *types.HistoryTaskV2Attributes {
+	return &types.HistoryTaskV2Attributes{
+		DomainID:   "domain-id",
+		WorkflowID: "workflow-id",
+		RunID:      "run-id",
+		VersionHistoryItems: []*types.VersionHistoryItem{
+			{EventID: 5, Version: 1},
+		},
+		Events: &types.DataBlob{
+			EncodingType: types.EncodingTypeThriftRW.Ptr(),
+			Data:         []byte("events"),
+		},
+		NewRunEvents: &types.DataBlob{
+			EncodingType: types.EncodingTypeThriftRW.Ptr(),
+			Data:         []byte("new run events"),
+		},
+	}
+}
+
+func TestHistoryReplicationTaskExecutor_ExecuteHistoryTask(t *testing.T) {
+	attributes := newHistoryTaskV2Attributes()
+	request := &types.ReplicateEventsV2Request{
+		DomainUUID: "domain-id",
+		WorkflowExecution: &types.WorkflowExecution{
+			WorkflowID: "workflow-id",
+			RunID:      "run-id",
+		},
+		VersionHistoryItems: attributes.VersionHistoryItems,
+		Events:              attributes.Events,
+		NewRunEvents:        attributes.NewRunEvents,
+	}
+	historyErr := &types.RetryTaskV2Error{DomainID: "domain-id"}
+
+	tests := []struct {
+		name          string
+		attributes    *types.HistoryTaskV2Attributes
+		mockSetup     func(*history.MockClient)
+		expectedError error
+	}{
+		{
+			name:       "success",
+			attributes: attributes,
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().ReplicateEventsV2(gomock.Any(), request).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "history service error",
+			attributes: attributes,
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().ReplicateEventsV2(gomock.Any(), request).Return(historyErr).Times(1)
+			},
+			expectedError: historyErr,
+		},
+		{
+			name:          "empty history task",
+			mockSetup:     func(client *history.MockClient) {},
+			expectedError: ErrEmptyWorkflowReplicationTask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			historyClient := history.NewMockClient(controller)
+			tt.mockSetup(historyClient)
+			executor := NewHistoryReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger())
+
+			err := executor.ExecuteHistoryTask(context.Background(), tt.attributes)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestHistoryReplicationTaskExecutor_ShardRouter(t *testing.T) {
+	routerErr := errors.New("no owner of the history shard")
+
+	tests := []struct {
+		name          string
+		mockSetup     func(*history.MockClient, *MockShardRouter)
+		expectedError error
+	}{
+		{
+			name: "routed to the history shard",
+			mockSetup: func(client *history.MockClient, router *MockShardRouter) {
+				gomock.InOrder(
+					router.EXPECT().RouteToShard("workflow-id").Return(3, nil).Times(1),
+					client.EXPECT().ReplicateEventsV2(gomock.Any(), gomock.Any()).Return(nil).Times(1),
+				)
+			},
+		},
+		{
+			name: "history shard without owner",
+			mockSetup: func(client *history.MockClient, router *MockShardRouter) {
+				router.EXPECT().RouteToShard("workflow-id").Return(0, routerErr).Times(1)
+				// the task is not forwarded until the shard has an owner
+				client.EXPECT().ReplicateEventsV2(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedError: routerErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			historyClient := history.NewMockClient(controller)
+			router := NewMockShardRouter(controller)
+			tt.mockSetup(historyClient, router)
+			executor := NewHistoryReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger(), WithShardRouter(router))
+
+			err := executor.ExecuteHistoryTask(context.Background(), newHistoryTaskV2Attributes())
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestHistoryReplicationTaskExecutor_ExecuteReplicationTask(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	historyClient := history.NewMockClient(controller)
+	executor := NewHistoryReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger())
+
+	historyClient.EXPECT().ReplicateEventsV2(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	assert.NoError(t, executor.ExecuteReplicationTask(&types.ReplicationTask{
+		TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+		HistoryTaskV2Attributes: newHistoryTaskV2Attributes(),
+	}, "cluster"))
+
+	// the executor only supports history replication tasks
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.ExecuteReplicationTask(&types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domain-id"},
+	}, "cluster"))
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.Execute(&types.DomainTaskAttributes{ID: "domain-id"}))
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.Overwrite(&types.DomainTaskAttributes{ID: "domain-id"}))
+	assert.NoError(t, executor.AddMigration(1, 2, func(attributes *types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		return nil, errors.New("migrations are not applied to history replication tasks")
+	}))
+}
+
+func TestHistoryReplicationTaskExecutor_Registry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	executor := NewHistoryReplicationTaskExecutor(history.NewMockClient(controller), loggerimpl.NewNopLogger())
+	registry := NewReplicationTaskExecutorRegistry(nil)
+	registry.RegisterExecutor(types.ReplicationTaskTypeHistoryV2, executor)
+
+	registered, ok := registry.GetExecutor(types.ReplicationTaskTypeHistoryV2)
+	assert.True(t, ok)
+	assert.Equal(t, executor, registered)
+}
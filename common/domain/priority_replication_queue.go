@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"sort"
+
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// DLQMessagePriorityDefault is the priority of workflow replication tasks and of DLQ messages enqueued before priorities
+	DLQMessagePriorityDefault = 0
+	// DLQMessagePriorityHigh is the priority of domain replication tasks, which the workflow replication tasks of the domain depend on
+	DLQMessagePriorityHigh = 1
+)
+
+type (
+	priorityReplicationQueue struct {
+		ReplicationQueue
+	}
+)
+
+// NewPriorityReplicationQueue wraps the replication queue so every page of DLQ messages is returned
+// bucketed by priority, from the highest priority to the lowest and by message ID within a priority.
+// Pages are no longer ordered by message ID, so readers acknowledging a page must use its highest message ID.
+func NewPriorityReplicationQueue(queue ReplicationQueue) ReplicationQueue {
+	return &priorityReplicationQueue{
+		ReplicationQueue: queue,
+	}
+}
+
+func (q *priorityReplicationQueue) GetMessagesFromDLQ(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	tasks, token, err := q.ReplicationQueue.GetMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID, pageSize, pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Priority > tasks[j].Priority
+	})
+	return tasks, token, nil
+}
+
+// getDLQMessagePriority returns the priority a replication task is enqueued to the DLQ with
+func getDLQMessagePriority(task *types.ReplicationTask) int {
+	if task.GetTaskType() == types.ReplicationTaskTypeDomain {
+		return DLQMessagePriorityHigh
+	}
+	return DLQMessagePriorityDefault
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestPriorityReplicationQueue_GetMessagesFromDLQ(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tasks := []*types.ReplicationTask{
+		{SourceTaskID: 11, Priority: DLQMessagePriorityDefault},
+		{SourceTaskID: 12, Priority: DLQMessagePriorityHigh},
+		{SourceTaskID: 13, Priority: DLQMessagePriorityDefault},
+		{SourceTaskID: 14, Priority: DLQMessagePriorityHigh},
+	}
+	mockQueue := NewMockReplicationQueue(controller)
+	mockQueue.EXPECT().GetMessagesFromDLQ(gomock.Any(), AllTaskTypes, int64(10), int64(20), 100, nil).
+		Return(tasks, []byte{1}, nil).Times(1)
+
+	result, token, err := NewPriorityReplicationQueue(mockQueue).GetMessagesFromDLQ(context.Background(), AllTaskTypes, 10, 20, 100, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, token)
+	assert.Equal(t, []int64{12, 14, 11, 13}, getSourceTaskIDs(result))
+}
+
+func TestMergeMessages_PriorityMerge(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// persist the DLQ in memory behind the queue manager
+	var messages []*persistence.QueueMessage
+	queueManager := persistence.NewMockQueueManager(controller)
+	queueManager.EXPECT().EnqueueMessageToDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ *int32, payload []byte) error {
+			messages = append(messages, &persistence.QueueMessage{ID: int64(len(messages) + 1), Payload: payload})
+			return nil
+		}).AnyTimes()
+	queueManager.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(-1), int64(100), 100, nil).DoAndReturn(
+		func(context.Context, int64, int64, int, []byte) ([]*persistence.QueueMessage, []byte, error) {
+			return messages, nil, nil
+		}).Times(1)
+	queueManager.EXPECT().GetDLQAckLevels(gomock.Any()).Return(map[string]int64{}, nil).Times(1)
+	queueManager.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), int64(-1), int64(4)).Return(nil).Times(1)
+	queueManager.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), int64(-1), int64(4), gomock.Any()).Return(nil).Times(1)
+	queueManager.EXPECT().UpdateDLQMergeToken(gomock.Any(), gomock.Any(), getDLQAckLevelUpdatedKey(localDomainReplicationCluster)).Return(nil).Times(1)
+	queueManager.EXPECT().InsertDLQMergeRecord(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record *persistence.DLQMergeRecord) error {
+			assert.Equal(t, int64(1), record.StartMessageID)
+			assert.Equal(t, int64(4), record.EndMessageID)
+			return nil
+		}).Times(1)
+	replicationQueue := NewReplicationQueue(queueManager, "testCluster", metrics.NewNoopMetricsClient(), loggerimpl.NewNopLogger())
+
+	tasks := []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), CreationTime: int64Ptr(1)},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), CreationTime: int64Ptr(2)},
+		{TaskType: types.ReplicationTaskTypeSyncActivity.Ptr(), CreationTime: int64Ptr(3)},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), CreationTime: int64Ptr(4)},
+	}
+	for _, task := range tasks {
+		require.NoError(t, replicationQueue.PublishToDLQ(context.Background(), task))
+	}
+
+	var executed []int64
+	executor := NewMockReplicationTaskExecutor(controller)
+	executor.EXPECT().ExecuteReplicationTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(task *types.ReplicationTask, _ string) error {
+			executed = append(executed, task.GetCreationTime())
+			return nil
+		}).Times(4)
+
+	dlqHandler := NewDLQMessageHandler(
+		NewReplicationTaskExecutorRegistry(executor),
+		replicationQueue,
+		loggerimpl.NewNopLogger(),
+		WithRateLimit(dynamicconfig.GetIntPropertyFn(1000), dynamicconfig.GetIntPropertyFn(1000)),
+		WithPurgeBatchDelay(dynamicconfig.GetDurationPropertyFn(0)),
+		WithPriorityMerge(dynamicconfig.GetBoolPropertyFn(true)),
+		WithReadMaxRetries(dynamicconfig.GetIntPropertyFn(0)),
+	)
+	_, err := dlqHandler.Merge(context.Background(), AllTaskTypes, "", 100, 100, nil)
+	require.NoError(t, err)
+
+	// domain replication tasks are merged before the workflow replication tasks
+	assert.Equal(t, []int64{2, 4, 1, 3}, executed)
+	assert.Equal(t, int64(4), dlqHandler.Health().AckLevel)
+}
+
+func TestGetExecutedMessageIndex(t *testing.T) {
+	messages := []*types.ReplicationTask{{SourceTaskID: 12}, {SourceTaskID: 14}, {SourceTaskID: 11}, {SourceTaskID: 13}}
+	assert.Equal(t, 2, getExecutedMessageIndex(messages, 11))
+	assert.Equal(t, -1, getExecutedMessageIndex(messages, 10))
+	assert.Equal(t, 0, getExecutedMessageIndex(messages, 12))
+
+	sorted := []*types.ReplicationTask{{SourceTaskID: 11}, {SourceTaskID: 13}, {SourceTaskID: 15}}
+	assert.Equal(t, 1, getExecutedMessageIndex(sorted, 14))
+	assert.Equal(t, 2, getExecutedMessageIndex(sorted, 20))
+}
+
+func getSourceTaskIDs(tasks []*types.ReplicationTask) []int64 {
+	var ids []int64
+	for _, task := range tasks {
+		ids = append(ids, task.SourceTaskID)
+	}
+	return ids
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
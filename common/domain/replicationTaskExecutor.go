@@ -28,6 +28,7 @@ import (
 
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
 )
@@ -49,6 +50,8 @@ var (
 	ErrInvalidDomainStatus = &types.BadRequestError{Message: "invalid domain status attribute"}
 	// ErrNameUUIDCollision is the error to indicate domain name / UUID collision
 	ErrNameUUIDCollision = &types.BadRequestError{Message: "domain replication encounter name / UUID collision"}
+	// ErrUnsupportedReplicationTaskType is the error to indicate the replication task type cannot be executed
+	ErrUnsupportedReplicationTaskType = &types.BadRequestError{Message: "unsupported replication task type"}
 )
 
 const (
@@ -61,26 +64,68 @@ type (
 	// ReplicationTaskExecutor is the interface which is to execute domain replication task
 	ReplicationTaskExecutor interface {
 		Execute(task *types.DomainTaskAttributes) error
+		// ExecuteReplicationTask executes the replication task, sourceCluster is the cluster the task
+		// was replicated from if it is known, so the task can be routed back to that cluster
+		ExecuteReplicationTask(task *types.ReplicationTask, sourceCluster string) error
+		// Overwrite executes the domain replication task after deleting the local domains conflicting with it
+		Overwrite(task *types.DomainTaskAttributes) error
+		// AddMigration registers the migration of domain replication tasks persisted in the DLQ with fromVersion
+		// to toVersion, it is applied by ExecuteReplicationTask before the task is executed
+		AddMigration(fromVersion, toVersion int, fn MigrationFunc) error
 	}
 
 	domainReplicationTaskExecutorImpl struct {
-		domainManager persistence.DomainManager
-		timeSource    clock.TimeSource
-		logger        log.Logger
+		domainManager    persistence.DomainManager
+		timeSource       clock.TimeSource
+		workflowExecutor WorkflowReplicationTaskExecutor
+		migrations       *replicationTaskMigrations
+		logger           log.Logger
 	}
 )
 
-// NewReplicationTaskExecutor create a new instance of domain replicator
+// NewReplicationTaskExecutor create a new instance of domain replicator,
+// workflowExecutor can be nil if workflow replication tasks are not expected
 func NewReplicationTaskExecutor(
 	domainManager persistence.DomainManager,
 	timeSource clock.TimeSource,
+	workflowExecutor WorkflowReplicationTaskExecutor,
 	logger log.Logger,
 ) ReplicationTaskExecutor {
 
 	return &domainReplicationTaskExecutorImpl{
-		domainManager: domainManager,
-		timeSource:    timeSource,
-		logger:        logger,
+		domainManager:    domainManager,
+		timeSource:       timeSource,
+		workflowExecutor: workflowExecutor,
+		migrations:       newReplicationTaskMigrations(),
+		logger:           logger,
+	}
+}
+
+// AddMigration registers the migration of domain replication tasks from fromVersion to toVersion
+func (h *domainReplicationTaskExecutorImpl) AddMigration(fromVersion, toVersion int, fn MigrationFunc) error {
+	return h.migrations.add(fromVersion, toVersion, fn)
+}
+
+// ExecuteReplicationTask dispatches the replication task to the executor of its task type,
+// tasks are applied to the local cluster whatever their source cluster
+func (h *domainReplicationTaskExecutorImpl) ExecuteReplicationTask(task *types.ReplicationTask, _ string) error {
+	switch task.GetTaskType() {
+	case types.ReplicationTaskTypeDomain:
+		if task.DomainTaskAttributes == nil {
+			return ErrEmptyDomainReplicationTask
+		}
+		attributes, err := h.migrations.migrate(task.DomainTaskAttributes, task.SchemaVersion)
+		if err != nil {
+			return err
+		}
+		return h.Execute(attributes)
+	case types.ReplicationTaskTypeHistoryV2, types.ReplicationTaskTypeSyncActivity:
+		if h.workflowExecutor == nil {
+			return ErrUnsupportedReplicationTaskType
+		}
+		return h.workflowExecutor.Execute(task)
+	default:
+		return ErrUnsupportedReplicationTaskType
 	}
 }
 
@@ -103,6 +148,50 @@ func (h *domainReplicationTaskExecutorImpl) Execute(task *types.DomainTaskAttrib
 	}
 }
 
+// Overwrite deletes the local domains which have the name or the ID of the replicated domain but not both,
+// so the domain replication task can be executed without a name / UUID collision
+func (h *domainReplicationTaskExecutorImpl) Overwrite(task *types.DomainTaskAttributes) error {
+	if err := h.validateDomainReplicationTask(task); err != nil {
+		return err
+	}
+
+	if err := h.deleteConflictingDomains(task); err != nil {
+		return err
+	}
+	return h.Execute(task)
+}
+
+func (h *domainReplicationTaskExecutorImpl) deleteConflictingDomains(task *types.DomainTaskAttributes) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultDomainRepliationTaskContextTimeout)
+	defer cancel()
+
+	for _, request := range []*persistence.GetDomainRequest{
+		{Name: task.Info.GetName()},
+		{ID: task.GetID()},
+	} {
+		resp, err := h.domainManager.GetDomain(ctx, request)
+		switch err.(type) {
+		case nil:
+		case *types.EntityNotExistsError:
+			continue
+		default:
+			return err
+		}
+		if resp.Info.ID == task.GetID() && resp.Info.Name == task.Info.GetName() {
+			continue
+		}
+
+		h.logger.Warn("Deleting local domain conflicting with domain replication task.",
+			tag.WorkflowDomainID(resp.Info.ID),
+			tag.WorkflowDomainName(resp.Info.Name),
+		)
+		if err := h.domainManager.DeleteDomain(ctx, &persistence.DeleteDomainRequest{ID: resp.Info.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // handleDomainCreationReplicationTask handles the domain creation replication task
 func (h *domainReplicationTaskExecutorImpl) handleDomainCreationReplicationTask(ctx context.Context, task *types.DomainTaskAttributes) error {
 	// task already validated
@@ -178,7 +267,14 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainCreationReplicationTask(
 		}
 
 		if recordExists {
-			// name -> id & id -> name check pass, this is duplication request
+			// name -> id & id -> name check pass, the domain was created before,
+			// the task may still carry attributes updated since at the source cluster
+			if updateErr := h.updateExistingDomain(ctx, task); updateErr != nil {
+				if _, ok := updateErr.(*types.EntityNotExistsError); ok {
+					return err
+				}
+				return updateErr
+			}
 			return nil
 		}
 		return err
@@ -189,6 +285,18 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainCreationReplicationTask(
 
 // handleDomainUpdateReplicationTask handles the domain update replication task
 func (h *domainReplicationTaskExecutorImpl) handleDomainUpdateReplicationTask(ctx context.Context, task *types.DomainTaskAttributes) error {
+	err := h.updateExistingDomain(ctx, task)
+	if _, ok := err.(*types.EntityNotExistsError); ok {
+		// this can happen if the create domain replication task is to processed.
+		// e.g. new cluster which does not have anything
+		return h.handleDomainCreationReplicationTask(ctx, task)
+	}
+	return err
+}
+
+// updateExistingDomain applies the attributes of the task to the local domain if they are newer,
+// the attributes the task does not set are left unchanged. It returns EntityNotExistsError if the domain does not exist.
+func (h *domainReplicationTaskExecutorImpl) updateExistingDomain(ctx context.Context, task *types.DomainTaskAttributes) error {
 	// task already validated
 	status, err := h.convertDomainStatusFromThrift(task.Info.Status)
 	if err != nil {
@@ -208,11 +316,6 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainUpdateReplicationTask(ct
 		Name: task.Info.GetName(),
 	})
 	if err != nil {
-		if _, ok := err.(*types.EntityNotExistsError); ok {
-			// this can happen if the create domain replication task is to processed.
-			// e.g. new cluster which does not have anything
-			return h.handleDomainCreationReplicationTask(ctx, task)
-		}
 		return err
 	}
 
@@ -235,17 +338,39 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainUpdateReplicationTask(ct
 			ID:          task.GetID(),
 			Name:        task.Info.GetName(),
 			Status:      status,
-			Description: task.Info.GetDescription(),
-			OwnerEmail:  task.Info.GetOwnerEmail(),
-			Data:        task.Info.Data,
+			Description: resp.Info.Description,
+			OwnerEmail:  resp.Info.OwnerEmail,
+			Data:        resp.Info.Data,
+		}
+		if task.Info.GetDescription() != "" {
+			request.Info.Description = task.Info.GetDescription()
+		}
+		if task.Info.GetOwnerEmail() != "" {
+			request.Info.OwnerEmail = task.Info.GetOwnerEmail()
+		}
+		if task.Info.Data != nil {
+			request.Info.Data = task.Info.Data
 		}
 		request.Config = &persistence.DomainConfig{
 			Retention:                task.Config.GetWorkflowExecutionRetentionPeriodInDays(),
 			EmitMetric:               task.Config.GetEmitMetric(),
-			HistoryArchivalStatus:    task.Config.GetHistoryArchivalStatus(),
-			HistoryArchivalURI:       task.Config.GetHistoryArchivalURI(),
-			VisibilityArchivalStatus: task.Config.GetVisibilityArchivalStatus(),
-			VisibilityArchivalURI:    task.Config.GetVisibilityArchivalURI(),
+			HistoryArchivalStatus:    resp.Config.HistoryArchivalStatus,
+			HistoryArchivalURI:       resp.Config.HistoryArchivalURI,
+			VisibilityArchivalStatus: resp.Config.VisibilityArchivalStatus,
+			VisibilityArchivalURI:    resp.Config.VisibilityArchivalURI,
+			BadBinaries:              resp.Config.BadBinaries,
+		}
+		if task.Config.HistoryArchivalStatus != nil {
+			request.Config.HistoryArchivalStatus = task.Config.GetHistoryArchivalStatus()
+		}
+		if task.Config.GetHistoryArchivalURI() != "" {
+			request.Config.HistoryArchivalURI = task.Config.GetHistoryArchivalURI()
+		}
+		if task.Config.VisibilityArchivalStatus != nil {
+			request.Config.VisibilityArchivalStatus = task.Config.GetVisibilityArchivalStatus()
+		}
+		if task.Config.GetVisibilityArchivalURI() != "" {
+			request.Config.VisibilityArchivalURI = task.Config.GetVisibilityArchivalURI()
 		}
 		if task.Config.GetBadBinaries() != nil {
 			request.Config.BadBinaries = *task.Config.GetBadBinaries()
@@ -24,7 +24,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 
@@ -66,6 +68,7 @@ func (s *domainReplicationTaskExecutorSuite) SetupTest() {
 	s.domainReplicator = NewReplicationTaskExecutor(
 		s.DomainManager,
 		clock.NewRealTimeSource(),
+		nil,
 		logger,
 	).(*domainReplicationTaskExecutorImpl)
 }
@@ -863,3 +866,242 @@ func (s *domainReplicationTaskExecutorSuite) TestExecute_UpdateDomainTask_NoUpda
 	s.Equal(int64(0), resp.FailoverNotificationVersion)
 	s.Equal(notificationVersion, resp.NotificationVersion)
 }
+
+func TestOverwrite_DeletesConflictingDomains(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	operation := types.DomainOperationCreate
+	status := types.DomainStatusRegistered
+	task := &types.DomainTaskAttributes{
+		DomainOperation: &operation,
+		ID:              uuid.New(),
+		Info: &types.DomainInfo{
+			Name:   "some random domain test name",
+			Status: &status,
+		},
+		Config:            &types.DomainConfiguration{},
+		ReplicationConfig: &types.DomainReplicationConfiguration{},
+	}
+	localDomainID := uuid.New()
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	gomock.InOrder(
+		domainManager.EXPECT().GetDomain(gomock.Any(), &persistence.GetDomainRequest{Name: task.Info.Name}).
+			Return(&persistence.GetDomainResponse{Info: &persistence.DomainInfo{ID: localDomainID, Name: task.Info.Name}}, nil),
+		domainManager.EXPECT().DeleteDomain(gomock.Any(), &persistence.DeleteDomainRequest{ID: localDomainID}).Return(nil),
+		domainManager.EXPECT().GetDomain(gomock.Any(), &persistence.GetDomainRequest{ID: task.ID}).
+			Return(nil, &types.EntityNotExistsError{}),
+		domainManager.EXPECT().CreateDomain(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *persistence.CreateDomainRequest) (*persistence.CreateDomainResponse, error) {
+				assert.Equal(t, task.ID, request.Info.ID)
+				assert.Equal(t, task.Info.Name, request.Info.Name)
+				return &persistence.CreateDomainResponse{ID: task.ID}, nil
+			}),
+	)
+
+	executor := NewReplicationTaskExecutor(domainManager, clock.NewRealTimeSource(), nil, loggerimpl.NewNopLogger())
+	assert.NoError(t, executor.Overwrite(task))
+}
+
+func TestOverwrite_NoConflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	operation := types.DomainOperationCreate
+	status := types.DomainStatusRegistered
+	task := &types.DomainTaskAttributes{
+		DomainOperation: &operation,
+		ID:              uuid.New(),
+		Info: &types.DomainInfo{
+			Name:   "some random domain test name",
+			Status: &status,
+		},
+		Config:            &types.DomainConfiguration{},
+		ReplicationConfig: &types.DomainReplicationConfiguration{},
+	}
+	localDomain := &persistence.GetDomainResponse{Info: &persistence.DomainInfo{ID: task.ID, Name: task.Info.Name}}
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).Return(localDomain, nil).Times(2)
+	domainManager.EXPECT().DeleteDomain(gomock.Any(), gomock.Any()).Times(0)
+	domainManager.EXPECT().CreateDomain(gomock.Any(), gomock.Any()).Return(&persistence.CreateDomainResponse{ID: task.ID}, nil).Times(1)
+
+	executor := NewReplicationTaskExecutor(domainManager, clock.NewRealTimeSource(), nil, loggerimpl.NewNopLogger())
+	assert.NoError(t, executor.Overwrite(task))
+}
+
+func TestExecuteReplicationTask_AppliesMigration(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	operation := types.DomainOperationCreate
+	status := types.DomainStatusRegistered
+	// the legacy task does not have the domain status
+	task := &types.ReplicationTask{
+		TaskType:      types.ReplicationTaskTypeDomain.Ptr(),
+		SchemaVersion: DLQSchemaVersionLegacy,
+		DomainTaskAttributes: &types.DomainTaskAttributes{
+			DomainOperation:   &operation,
+			ID:                uuid.New(),
+			Info:              &types.DomainInfo{Name: "some random domain test name"},
+			Config:            &types.DomainConfiguration{},
+			ReplicationConfig: &types.DomainReplicationConfiguration{},
+		},
+	}
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	domainManager.EXPECT().CreateDomain(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.CreateDomainRequest) (*persistence.CreateDomainResponse, error) {
+			assert.Equal(t, task.DomainTaskAttributes.ID, request.Info.ID)
+			assert.Equal(t, persistence.DomainStatusRegistered, request.Info.Status)
+			return &persistence.CreateDomainResponse{ID: request.Info.ID}, nil
+		}).Times(1)
+
+	executor := NewReplicationTaskExecutor(domainManager, clock.NewRealTimeSource(), nil, loggerimpl.NewNopLogger())
+	// without the migration the task fails validation
+	assert.Equal(t, ErrInvalidDomainStatus, executor.ExecuteReplicationTask(task, ""))
+
+	assert.NoError(t, executor.AddMigration(DLQSchemaVersionLegacy, DLQSchemaVersion1, func(
+		attributes *types.DomainTaskAttributes,
+	) (*types.DomainTaskAttributes, error) {
+		migrated := *attributes
+		migrated.Info = &types.DomainInfo{Name: attributes.Info.GetName(), Status: &status}
+		return &migrated, nil
+	}))
+	assert.NoError(t, executor.ExecuteReplicationTask(task, ""))
+	// the persisted task is not modified by the migration
+	assert.Nil(t, task.DomainTaskAttributes.Info.Status)
+}
+
+func TestExecute_RegisterDomainTask_DomainNotExist(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	operation := types.DomainOperationCreate
+	status := types.DomainStatusRegistered
+	task := &types.DomainTaskAttributes{
+		DomainOperation: &operation,
+		ID:              uuid.New(),
+		Info: &types.DomainInfo{
+			Name:        "some random domain test name",
+			Status:      &status,
+			Description: "some random test description",
+			OwnerEmail:  "some random test owner",
+		},
+		Config:            &types.DomainConfiguration{WorkflowExecutionRetentionPeriodInDays: 10},
+		ReplicationConfig: &types.DomainReplicationConfiguration{},
+	}
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	domainManager.EXPECT().CreateDomain(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.CreateDomainRequest) (*persistence.CreateDomainResponse, error) {
+			assert.Equal(t, task.ID, request.Info.ID)
+			assert.Equal(t, task.Info.Description, request.Info.Description)
+			assert.Equal(t, task.Info.OwnerEmail, request.Info.OwnerEmail)
+			return &persistence.CreateDomainResponse{ID: task.ID}, nil
+		}).Times(1)
+	domainManager.EXPECT().UpdateDomain(gomock.Any(), gomock.Any()).Times(0)
+
+	executor := NewReplicationTaskExecutor(domainManager, clock.NewRealTimeSource(), nil, loggerimpl.NewNopLogger())
+	assert.NoError(t, executor.Execute(task))
+}
+
+func TestExecute_RegisterDomainTask_DomainExist_UpdatesAttributes(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	operation := types.DomainOperationCreate
+	status := types.DomainStatusRegistered
+	task := &types.DomainTaskAttributes{
+		DomainOperation: &operation,
+		ID:              uuid.New(),
+		Info: &types.DomainInfo{
+			Name:       "some random domain test name",
+			Status:     &status,
+			OwnerEmail: "other random test owner",
+		},
+		Config:            &types.DomainConfiguration{WorkflowExecutionRetentionPeriodInDays: 10},
+		ReplicationConfig: &types.DomainReplicationConfiguration{},
+		ConfigVersion:     1,
+	}
+	localDomain := &persistence.GetDomainResponse{
+		Info: &persistence.DomainInfo{
+			ID:          task.ID,
+			Name:        task.Info.Name,
+			Description: "some random test description",
+			OwnerEmail:  "some random test owner",
+			Data:        map[string]string{"k": "v"},
+		},
+		Config:            &persistence.DomainConfig{Retention: 10},
+		ReplicationConfig: &persistence.DomainReplicationConfig{},
+	}
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	domainManager.EXPECT().CreateDomain(gomock.Any(), gomock.Any()).Return(nil, &types.DomainAlreadyExistsError{}).Times(1)
+	domainManager.EXPECT().GetDomain(gomock.Any(), gomock.Any()).Return(localDomain, nil).Times(3)
+	domainManager.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{NotificationVersion: 5}, nil).Times(1)
+	domainManager.EXPECT().UpdateDomain(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateDomainRequest) error {
+			// the description and data are not set in the task and are left unchanged
+			assert.Equal(t, "some random test description", request.Info.Description)
+			assert.Equal(t, map[string]string{"k": "v"}, request.Info.Data)
+			assert.Equal(t, task.Info.OwnerEmail, request.Info.OwnerEmail)
+			assert.Equal(t, task.ConfigVersion, request.ConfigVersion)
+			assert.Equal(t, int64(5), request.NotificationVersion)
+			return nil
+		}).Times(1)
+
+	executor := NewReplicationTaskExecutor(domainManager, clock.NewRealTimeSource(), nil, loggerimpl.NewNopLogger())
+	assert.NoError(t, executor.Execute(task))
+}
+
+func TestExecute_UpdateDomainTask_DomainExist_SkipsUnsetAttributes(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	operation := types.DomainOperationUpdate
+	status := types.DomainStatusRegistered
+	task := &types.DomainTaskAttributes{
+		DomainOperation: &operation,
+		ID:              uuid.New(),
+		Info: &types.DomainInfo{
+			Name:        "some random domain test name",
+			Status:      &status,
+			Description: "other random test description",
+		},
+		Config:            &types.DomainConfiguration{WorkflowExecutionRetentionPeriodInDays: 10},
+		ReplicationConfig: &types.DomainReplicationConfiguration{},
+		ConfigVersion:     1,
+	}
+	localDomain := &persistence.GetDomainResponse{
+		Info: &persistence.DomainInfo{
+			ID:          task.ID,
+			Name:        task.Info.Name,
+			Description: "some random test description",
+			OwnerEmail:  "some random test owner",
+		},
+		Config: &persistence.DomainConfig{
+			Retention:             10,
+			HistoryArchivalStatus: types.ArchivalStatusEnabled,
+			HistoryArchivalURI:    "some random history archival uri",
+		},
+		ReplicationConfig: &persistence.DomainReplicationConfig{},
+	}
+
+	domainManager := persistence.NewMockDomainManager(controller)
+	domainManager.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{}, nil).Times(1)
+	domainManager.EXPECT().GetDomain(gomock.Any(), &persistence.GetDomainRequest{Name: task.Info.Name}).Return(localDomain, nil).Times(1)
+	domainManager.EXPECT().UpdateDomain(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateDomainRequest) error {
+			assert.Equal(t, task.Info.Description, request.Info.Description)
+			assert.Equal(t, "some random test owner", request.Info.OwnerEmail)
+			assert.Equal(t, types.ArchivalStatusEnabled, request.Config.HistoryArchivalStatus)
+			assert.Equal(t, "some random history archival uri", request.Config.HistoryArchivalURI)
+			return nil
+		}).Times(1)
+	domainManager.EXPECT().CreateDomain(gomock.Any(), gomock.Any()).Times(0)
+
+	executor := NewReplicationTaskExecutor(domainManager, clock.NewRealTimeSource(), nil, loggerimpl.NewNopLogger())
+	assert.NoError(t, executor.Execute(task))
+}
@@ -57,6 +57,20 @@ func (m *MockReplicationTaskExecutor) EXPECT() *MockReplicationTaskExecutorMockR
 	return m.recorder
 }
 
+// AddMigration mocks base method.
+func (m *MockReplicationTaskExecutor) AddMigration(fromVersion, toVersion int, fn MigrationFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMigration", fromVersion, toVersion, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMigration indicates an expected call of AddMigration.
+func (mr *MockReplicationTaskExecutorMockRecorder) AddMigration(fromVersion, toVersion, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMigration", reflect.TypeOf((*MockReplicationTaskExecutor)(nil).AddMigration), fromVersion, toVersion, fn)
+}
+
 // Execute mocks base method.
 func (m *MockReplicationTaskExecutor) Execute(task *types.DomainTaskAttributes) error {
 	m.ctrl.T.Helper()
@@ -70,3 +84,31 @@ func (mr *MockReplicationTaskExecutorMockRecorder) Execute(task interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockReplicationTaskExecutor)(nil).Execute), task)
 }
+
+// ExecuteReplicationTask mocks base method.
+func (m *MockReplicationTaskExecutor) ExecuteReplicationTask(task *types.ReplicationTask, sourceCluster string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteReplicationTask", task, sourceCluster)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteReplicationTask indicates an expected call of ExecuteReplicationTask.
+func (mr *MockReplicationTaskExecutorMockRecorder) ExecuteReplicationTask(task, sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteReplicationTask", reflect.TypeOf((*MockReplicationTaskExecutor)(nil).ExecuteReplicationTask), task, sourceCluster)
+}
+
+// Overwrite mocks base method.
+func (m *MockReplicationTaskExecutor) Overwrite(task *types.DomainTaskAttributes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overwrite", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Overwrite indicates an expected call of Overwrite.
+func (mr *MockReplicationTaskExecutorMockRecorder) Overwrite(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overwrite", reflect.TypeOf((*MockReplicationTaskExecutor)(nil).Overwrite), task)
+}
@@ -18,15 +18,18 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination replication_queue_mock.go -self_package github.com/uber/common/persistence
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination replication_queue_mock.go -self_package github.com/uber/cadence/common/domain
 
 package domain
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -43,10 +46,26 @@ import (
 
 const (
 	purgeInterval                 = 5 * time.Minute
-	queueSizeQueryInterval        = 5 * time.Minute
 	localDomainReplicationCluster = "domainReplication"
+	dlqReplayAckLevelKey          = "domainReplication-replay"
+	dlqConflictPolicyKey          = "domainReplication-conflict-policy"
+	dlqExecutionJournalKey        = "domainReplication-journal"
+	dlqProcessingPausedKey        = "domainReplication-paused"
+	dlqRangeDeletePageSize        = 100
 )
 
+// AllTaskTypes is the sentinel task type addressing every message in the DLQ regardless of its task type
+const AllTaskTypes = types.ReplicationTaskType(-1)
+
+// DefaultConsumerGroup is the consumer group of the DLQ handler, its ack levels are stored under the legacy keys
+const DefaultConsumerGroup = "default"
+
+// ErrDLQAckLevelConflict is returned when the DLQ ack level was moved by a concurrent update, the caller can retry
+var ErrDLQAckLevelConflict = &types.ServiceBusyError{Message: "domain DLQ ack level was updated concurrently"}
+
+// ErrVersionConflict is returned when the DLQ ack levels changed version since they were read, the caller can read them again and retry
+var ErrVersionConflict = &types.ServiceBusyError{Message: "domain DLQ ack levels changed version concurrently"}
+
 var _ ReplicationQueue = (*replicationQueueImpl)(nil)
 
 // NewReplicationQueue creates a new ReplicationQueue instance
@@ -55,8 +74,9 @@ func NewReplicationQueue(
 	clusterName string,
 	metricsClient metrics.Client,
 	logger log.Logger,
+	opts ...ReplicationQueueOption,
 ) ReplicationQueue {
-	return &replicationQueueImpl{
+	q := &replicationQueueImpl{
 		queue:         queue,
 		clusterName:   clusterName,
 		metricsClient: metricsClient,
@@ -65,6 +85,10 @@ func NewReplicationQueue(
 		done:          make(chan bool),
 		status:        common.DaemonStatusInitialized,
 	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
 }
 
 type (
@@ -76,22 +100,99 @@ type (
 		encoder       codec.BinaryEncoder
 		done          chan bool
 		status        int32
+		// encryptionKeys is nil unless the queue was created WithEncryption
+		encryptionKeys EncryptionKeyProvider
+		// payloads larger than compressionThresholdBytes are compressed if the queue was created WithCompression
+		compressionEnabled        bool
+		compressionThresholdBytes int
+		// deduplicationFilter is nil unless the queue was created WithEnqueueDeduplication
+		deduplicationFilter DeduplicationFilter
+		// serializationFormat is empty for thrift unless the queue was created WithSerializationFormat
+		serializationFormat DLQSerializationFormat
+	}
+
+	// DLQMergeFence records the last DLQ message executed by a merge request,
+	// so that a re-driven merge with the same request ID does not apply it again
+	DLQMergeFence struct {
+		RequestID string `json:"requestID"`
+		MessageID int64  `json:"messageID"`
+	}
+
+	// DLQExecutionJournal is the write-ahead log of the DLQ messages executed by the merges of a consumer group
+	// which are not deleted yet, so a merge restarted after a crash does not apply them again
+	DLQExecutionJournal struct {
+		// Executing are the messages whose execution started and did not finish, they may or may not be applied
+		Executing []int64 `json:"executing,omitempty"`
+		// Applied are the messages which were executed successfully
+		Applied []int64 `json:"applied,omitempty"`
+	}
+
+	// DLQMergeRecord is the audit record of one batch of merged domain DLQ messages
+	DLQMergeRecord struct {
+		MergedAt       time.Time     `json:"mergedAt"`
+		StartMessageID int64         `json:"startMessageID"`
+		EndMessageID   int64         `json:"endMessageID"`
+		MergedCount    int64         `json:"mergedCount"`
+		FailedCount    int64         `json:"failedCount"`
+		Duration       time.Duration `json:"duration"`
+		TriggeredBy    string        `json:"triggeredBy"`
+		TraceID        string        `json:"traceID,omitempty"`
+	}
+
+	// DLQMergeHistory keeps the audit trail of domain DLQ merges
+	DLQMergeHistory interface {
+		RecordDLQMerge(ctx context.Context, record DLQMergeRecord) error
+		GetDLQMergeHistory(ctx context.Context, limit int) ([]DLQMergeRecord, error)
+	}
+
+	// clusterTaskEnvelope wraps the thrift payload of a replication task enqueued for a single remote cluster
+	clusterTaskEnvelope struct {
+		TargetCluster string `json:"targetCluster"`
+		Payload       []byte `json:"payload"`
 	}
 
 	// ReplicationQueue is used to publish and list domain replication tasks
 	ReplicationQueue interface {
 		common.Daemon
+		DLQMergeHistory
 		Publish(ctx context.Context, message interface{}) error
+		EnqueueForCluster(ctx context.Context, clusterName string, message interface{}) error
 		PublishToDLQ(ctx context.Context, message interface{}) error
-		GetReplicationMessages(ctx context.Context, lastMessageID int64, maxCount int) ([]*types.ReplicationTask, int64, error)
+		EnqueueBatch(ctx context.Context, tasks []*types.ReplicationTask) error
+		GetReplicationMessages(ctx context.Context, clusterName string, lastMessageID int64, maxCount int) ([]*types.ReplicationTask, int64, error)
 		UpdateAckLevel(ctx context.Context, lastProcessedMessageID int64, clusterName string) error
 		GetAckLevels(ctx context.Context) (map[string]int64, error)
-		GetMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
-		UpdateDLQAckLevel(ctx context.Context, lastProcessedMessageID int64) error
-		GetDLQAckLevel(ctx context.Context) (int64, error)
-		RangeDeleteMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64) error
+		GetMessagesFromDLQ(ctx context.Context, taskType types.ReplicationTaskType, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
+		GetMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
+		GetMessagesFromDLQByTimeRange(ctx context.Context, startTime time.Time, endTime time.Time, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error)
+		GetMessagesFromDLQGrouped(ctx context.Context, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) (map[string][]*types.ReplicationTask, []byte, error)
+		UpdateDLQAckLevel(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string, lastProcessedMessageID int64) error
+		CompareAndSwapDLQAckLevel(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string, expectedLevel int64, newLevel int64) error
+		GetDLQAckLevel(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string) (int64, error)
+		UpdateDLQAckLevelWithVersion(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string, newLevel int64, expectedVersion int64) error
+		GetDLQAckLevelWithVersion(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string) (int64, int64, error)
+		GetDLQConsumerAckLevels(ctx context.Context) ([]*DLQConsumerAckLevel, error)
+		UpdateDLQReplayAckLevel(ctx context.Context, lastReplayedMessageID int64) error
+		GetDLQReplayAckLevel(ctx context.Context) (int64, error)
+		UpdateDLQMergeFence(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string, fence *DLQMergeFence) error
+		GetDLQMergeFence(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string) (*DLQMergeFence, error)
+		UpdateDLQExecutionJournal(ctx context.Context, consumerGroup string, journal *DLQExecutionJournal) error
+		GetDLQExecutionJournal(ctx context.Context, consumerGroup string) (*DLQExecutionJournal, error)
+		UpdateDLQProcessingPaused(ctx context.Context, consumerGroup string, paused bool) error
+		GetDLQProcessingPaused(ctx context.Context, consumerGroup string) (bool, error)
+		SnapshotDLQAckLevel(ctx context.Context) (*DLQAckLevelSnapshot, error)
+		RestoreDLQAckLevel(ctx context.Context, snapshot *DLQAckLevelSnapshot) error
+		UpdateDLQConflictResolutionPolicy(ctx context.Context, domainName string, policy ConflictResolutionPolicy) error
+		GetDLQConflictResolutionPolicies(ctx context.Context) (map[string]ConflictResolutionPolicy, error)
+		RangeDeleteMessagesFromDLQ(ctx context.Context, taskType types.ReplicationTaskType, firstMessageID int64, lastMessageID int64) error
+		RangeDeleteMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID int64, lastMessageID int64) error
 		DeleteMessageFromDLQ(ctx context.Context, messageID int64) error
-		GetDLQSize(ctx context.Context) (int64, error)
+		NackMessage(ctx context.Context, messageID int64, retryAfter time.Duration) error
+		RangeSoftDeleteMessagesFromDLQ(ctx context.Context, taskType types.ReplicationTaskType, firstMessageID int64, lastMessageID int64) error
+		DeleteSoftDeletedMessagesFromDLQ(ctx context.Context, deletedBefore time.Time) error
+		IncrementDLQMessageAttempts(ctx context.Context, messageID int64) (int, error)
+		GetDLQSize(ctx context.Context, taskType types.ReplicationTaskType) (int64, error)
+		GetDLQMessageTypeHistogram(ctx context.Context) (map[types.ReplicationTaskType]int64, error)
 	}
 )
 
@@ -117,6 +218,9 @@ func (q *replicationQueueImpl) Publish(
 	if !ok {
 		return errors.New("wrong message type")
 	}
+	if isDLQOnlyTaskType(task.GetTaskType()) {
+		return ErrUnsupportedReplicationTaskType
+	}
 
 	bytes, err := q.encoder.Encode(thrift.FromReplicationTask(task))
 	if err != nil {
@@ -125,25 +229,140 @@ func (q *replicationQueueImpl) Publish(
 	return q.queue.EnqueueMessage(ctx, bytes)
 }
 
-func (q *replicationQueueImpl) PublishToDLQ(
+// EnqueueForCluster publishes a DLQ message to the replication queue for a single remote cluster, the other remote
+// clusters skip it when they read the queue. Only domain replication tasks are accepted, they are the only tasks the
+// remote clusters apply from the queue.
+func (q *replicationQueueImpl) EnqueueForCluster(
 	ctx context.Context,
+	clusterName string,
 	message interface{},
 ) error {
+	if clusterName == "" || clusterName == q.clusterName {
+		return &types.BadRequestError{Message: fmt.Sprintf("invalid destination cluster %q", clusterName)}
+	}
+
 	task, ok := message.(*types.ReplicationTask)
 	if !ok {
 		return errors.New("wrong message type")
 	}
+	if err := validateDomainReplicationTask(task); err != nil {
+		return err
+	}
 
-	bytes, err := q.encoder.Encode(thrift.FromReplicationTask(task))
+	// drop the fields describing the task position in the local DLQ
+	forwardTask := *task
+	forwardTask.SourceTaskID = 0
+	forwardTask.EnqueuedAt = time.Time{}
+	payload, err := q.encoder.Encode(thrift.FromReplicationTask(&forwardTask))
 	if err != nil {
 		return fmt.Errorf("failed to encode message: %v", err)
 	}
+	bytes, err := json.Marshal(clusterTaskEnvelope{TargetCluster: clusterName, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %v", err)
+	}
+	return q.queue.EnqueueMessage(ctx, bytes)
+}
+
+func (q *replicationQueueImpl) PublishToDLQ(
+	ctx context.Context,
+	message interface{},
+) error {
+	task, ok := message.(*types.ReplicationTask)
+	if !ok {
+		return errors.New("wrong message type")
+	}
+
+	if q.isDuplicate(ctx, task) {
+		return nil
+	}
+
+	bytes, err := q.encodeDLQMessage(task)
+	if err != nil {
+		return err
+	}
+
+	if err := q.queue.EnqueueMessageToDLQ(ctx, getReplicationTaskDomainID(task), getReplicationTaskType(task), bytes); err != nil {
+		return err
+	}
+	q.markSeen(ctx, task)
+	return nil
+}
+
+// EnqueueBatch publishes the tasks to the DLQ in a single persistence round trip,
+// the tasks get consecutive message IDs in the order given
+func (q *replicationQueueImpl) EnqueueBatch(
+	ctx context.Context,
+	tasks []*types.ReplicationTask,
+) error {
+
+	if len(tasks) == 0 {
+		return nil
+	}
 
-	return q.queue.EnqueueMessageToDLQ(ctx, bytes)
+	messages := make([]*persistence.QueueMessage, 0, len(tasks))
+	enqueued := make([]*types.ReplicationTask, 0, len(tasks))
+	batchTaskIDs := make(map[int64]struct{}, len(tasks))
+	for _, task := range tasks {
+		if q.isDuplicate(ctx, task) {
+			continue
+		}
+		if q.deduplicationFilter != nil && task.SourceTaskID > 0 {
+			// the filter only learns of the tasks of the batch once the batch is enqueued
+			if _, ok := batchTaskIDs[task.SourceTaskID]; ok {
+				continue
+			}
+			batchTaskIDs[task.SourceTaskID] = struct{}{}
+		}
+		bytes, err := q.encodeDLQMessage(task)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, &persistence.QueueMessage{
+			Payload:  bytes,
+			DomainID: getReplicationTaskDomainID(task),
+			TaskType: getReplicationTaskType(task),
+		})
+		enqueued = append(enqueued, task)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := q.queue.EnqueueMessagesToDLQ(ctx, messages); err != nil {
+		return err
+	}
+	q.markSeen(ctx, enqueued...)
+	return nil
 }
 
+func (q *replicationQueueImpl) encodeDLQMessage(task *types.ReplicationTask) ([]byte, error) {
+	if task.Priority == DLQMessagePriorityDefault || task.CorrelationID == "" {
+		dlqTask := *task
+		if dlqTask.Priority == DLQMessagePriorityDefault {
+			dlqTask.Priority = getDLQMessagePriority(task)
+		}
+		if dlqTask.CorrelationID == "" {
+			dlqTask.CorrelationID = getDLQCorrelationID(task)
+		}
+		task = &dlqTask
+	}
+	compressionThreshold := dlqCompressionDisabled
+	if q.compressionEnabled {
+		compressionThreshold = q.compressionThresholdBytes
+	}
+	bytes, err := encodeReplicationTask(task, q.serializationFormat, q.encryptionKeys, compressionThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode message: %v", err)
+	}
+	return bytes, nil
+}
+
+// GetReplicationMessages returns the replication tasks to be read by the given remote cluster,
+// the tasks enqueued for another cluster are skipped
 func (q *replicationQueueImpl) GetReplicationMessages(
 	ctx context.Context,
+	clusterName string,
 	lastMessageID int64,
 	maxCount int,
 ) ([]*types.ReplicationTask, int64, error) {
@@ -155,8 +374,19 @@ func (q *replicationQueueImpl) GetReplicationMessages(
 
 	var replicationTasks []*types.ReplicationTask
 	for _, message := range messages {
+		payload := message.Payload
+		// a thrift payload never starts with a JSON object, so only the tasks enqueued for a cluster decode as an envelope
+		var envelope clusterTaskEnvelope
+		if err := json.Unmarshal(message.Payload, &envelope); err == nil {
+			if envelope.TargetCluster != clusterName {
+				lastMessageID = message.ID
+				continue
+			}
+			payload = envelope.Payload
+		}
+
 		var replicationTask replicator.ReplicationTask
-		err := q.encoder.Decode(message.Payload, &replicationTask)
+		err := q.encoder.Decode(payload, &replicationTask)
 		if err != nil {
 			return nil, lastMessageID, fmt.Errorf("failed to decode task: %v", err)
 		}
@@ -189,6 +419,7 @@ func (q *replicationQueueImpl) GetAckLevels(
 
 func (q *replicationQueueImpl) GetMessagesFromDLQ(
 	ctx context.Context,
+	taskType types.ReplicationTaskType,
 	firstMessageID int64,
 	lastMessageID int64,
 	pageSize int,
@@ -200,58 +431,509 @@ func (q *replicationQueueImpl) GetMessagesFromDLQ(
 		return nil, nil, err
 	}
 
+	replicationTasks, err := q.decodeDLQMessages(ctx, messages, taskType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return replicationTasks, token, nil
+}
+
+// GetMessagesFromDLQByDomain returns the DLQ messages of a single domain
+func (q *replicationQueueImpl) GetMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	messages, token, err := q.queue.ReadMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replicationTasks, err := q.decodeDLQMessages(ctx, messages, AllTaskTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return replicationTasks, token, nil
+}
+
+// GetMessagesFromDLQByTimeRange returns the DLQ messages enqueued between startTime, inclusive, and endTime, exclusive
+func (q *replicationQueueImpl) GetMessagesFromDLQByTimeRange(
+	ctx context.Context,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*types.ReplicationTask, []byte, error) {
+
+	messages, token, err := q.queue.ReadMessagesFromDLQByTimeRange(ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replicationTasks, err := q.decodeDLQMessages(ctx, messages, AllTaskTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return replicationTasks, token, nil
+}
+
+// GetMessagesFromDLQGrouped returns a page of DLQ messages grouped by their correlation ID,
+// the messages of a group are ordered by message ID and a message without a correlation ID is a group of its own
+func (q *replicationQueueImpl) GetMessagesFromDLQGrouped(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) (map[string][]*types.ReplicationTask, []byte, error) {
+
+	replicationTasks, token, err := q.GetMessagesFromDLQ(ctx, AllTaskTypes, firstMessageID, lastMessageID, pageSize, pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return groupDLQMessages(replicationTasks), token, nil
+}
+
+func (q *replicationQueueImpl) decodeDLQMessages(
+	ctx context.Context,
+	messages []*persistence.QueueMessage,
+	taskType types.ReplicationTaskType,
+) ([]*types.ReplicationTask, error) {
+
 	var replicationTasks []*types.ReplicationTask
 	for _, message := range messages {
-		var replicationTask replicator.ReplicationTask
-		err := q.encoder.Decode(message.Payload, &replicationTask)
+		envelope, err := unmarshalDLQEnvelope(message.Payload)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to decode dlq task: %v", err)
+			return nil, fmt.Errorf("failed to decode dlq task: %w", err)
+		}
+		persistedVersion, persistedKeyID := envelope.SchemaVersion, envelope.KeyID
+		if err := envelope.decrypt(q.encryptionKeys); err != nil {
+			return nil, fmt.Errorf("failed to decode dlq task: %w", err)
 		}
+		// migrations apply to the decrypted payload, so an encrypted message reports the version it was encrypted from
+		schemaVersion := envelope.SchemaVersion
+		task, err := envelope.decode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode dlq task: %w", err)
+		}
+		q.metricsClient.Scope(metrics.DomainReplicationQueueScope, dlqMessageMetricsTags(task)...).
+			RecordHistogramValue(metrics.DomainReplicationDLQSchemaVersion, float64(persistedVersion))
+		q.reencryptDLQMessage(ctx, message.ID, persistedKeyID, envelope)
 
 		//Overwrite to local cluster message id
-		replicationTask.SourceTaskId = common.Int64Ptr(int64(message.ID))
-		replicationTasks = append(replicationTasks, thrift.ToReplicationTask(&replicationTask))
+		task.SourceTaskID = message.ID
+		if !matchesTaskType(task, taskType) {
+			continue
+		}
+		task.EnqueuedAt = message.EnqueuedAt
+		task.SchemaVersion = schemaVersion
+		task.PayloadSize = len(message.Payload)
+		replicationTasks = append(replicationTasks, task)
 	}
 
-	return replicationTasks, token, nil
+	return replicationTasks, nil
+}
+
+// reencryptDLQMessage rewrites the message with the current encryption key if it was persisted
+// unencrypted or with a rotated key. Failures are only logged, the message is re-encrypted on a later read.
+func (q *replicationQueueImpl) reencryptDLQMessage(
+	ctx context.Context,
+	messageID int64,
+	persistedKeyID string,
+	envelope dlqEnvelope,
+) {
+
+	if q.encryptionKeys == nil {
+		return
+	}
+	keyID, key, err := q.encryptionKeys.CurrentKey()
+	if err != nil {
+		q.logger.Warn("Failed to get DLQ encryption key.", tag.Error(err))
+		return
+	}
+	if keyID == persistedKeyID {
+		return
+	}
+
+	if err := envelope.encrypt(keyID, key); err != nil {
+		q.logger.Warn("Failed to re-encrypt DLQ message.", tag.TaskID(messageID), tag.Error(err))
+		return
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		q.logger.Warn("Failed to re-encrypt DLQ message.", tag.TaskID(messageID), tag.Error(err))
+		return
+	}
+	if err := q.queue.UpdateDLQMessagePayload(ctx, messageID, payload); err != nil {
+		q.logger.Warn("Failed to update re-encrypted DLQ message.", tag.TaskID(messageID), tag.Error(err))
+	}
 }
 
 func (q *replicationQueueImpl) UpdateDLQAckLevel(
 	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
 	lastProcessedMessageID int64,
 ) error {
-	return q.queue.UpdateDLQAckLevel(
+	key := getDLQAckLevelKey(taskType, consumerGroup)
+	if err := q.queue.UpdateDLQAckLevel(ctx, lastProcessedMessageID, key); err != nil {
+		return err
+	}
+	q.recordDLQAckLevelUpdate(ctx, key)
+	return nil
+}
+
+func (q *replicationQueueImpl) CompareAndSwapDLQAckLevel(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	expectedLevel int64,
+	newLevel int64,
+) error {
+	key := getDLQAckLevelKey(taskType, consumerGroup)
+	err := q.queue.CompareAndSwapDLQAckLevel(
 		ctx,
-		lastProcessedMessageID,
-		localDomainReplicationCluster,
+		expectedLevel,
+		newLevel,
+		key,
 	)
+	if _, ok := err.(*persistence.ConditionFailedError); ok {
+		return ErrDLQAckLevelConflict
+	}
+	if err != nil {
+		return err
+	}
+	q.recordDLQAckLevelUpdate(ctx, key)
+	return nil
 }
 
 func (q *replicationQueueImpl) GetDLQAckLevel(
 	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+) (int64, error) {
+	dlqMetadata, err := q.queue.GetDLQAckLevels(ctx)
+	if err != nil {
+		return common.EmptyMessageID, err
+	}
+
+	ackLevel, ok := dlqMetadata[getDLQAckLevelKey(taskType, consumerGroup)]
+	if !ok {
+		return common.EmptyMessageID, nil
+	}
+	return ackLevel, nil
+}
+
+// UpdateDLQAckLevelWithVersion sets the ack level if the DLQ ack levels are still at the version they were read at,
+// it returns ErrVersionConflict if any of them was updated since
+func (q *replicationQueueImpl) UpdateDLQAckLevelWithVersion(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	newLevel int64,
+	expectedVersion int64,
+) error {
+	key := getDLQAckLevelKey(taskType, consumerGroup)
+	err := q.queue.UpdateDLQAckLevelWithVersion(
+		ctx,
+		newLevel,
+		key,
+		expectedVersion,
+	)
+	if _, ok := err.(*persistence.ConditionFailedError); ok {
+		return ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+	q.recordDLQAckLevelUpdate(ctx, key)
+	return nil
+}
+
+// GetDLQAckLevelWithVersion returns the ack level with the version of the DLQ ack levels
+func (q *replicationQueueImpl) GetDLQAckLevelWithVersion(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+) (int64, int64, error) {
+	dlqMetadata, version, err := q.queue.GetDLQAckLevelsWithVersion(ctx)
+	if err != nil {
+		return common.EmptyMessageID, 0, err
+	}
+
+	ackLevel, ok := dlqMetadata[getDLQAckLevelKey(taskType, consumerGroup)]
+	if !ok {
+		return common.EmptyMessageID, version, nil
+	}
+	return ackLevel, version, nil
+}
+
+// UpdateDLQReplayAckLevel records the last message ID of the source DLQ replayed into this DLQ
+func (q *replicationQueueImpl) UpdateDLQReplayAckLevel(
+	ctx context.Context,
+	lastReplayedMessageID int64,
+) error {
+	return q.queue.UpdateDLQAckLevel(
+		ctx,
+		lastReplayedMessageID,
+		dlqReplayAckLevelKey,
+	)
+}
+
+// GetDLQReplayAckLevel returns the last message ID of the source DLQ replayed into this DLQ
+func (q *replicationQueueImpl) GetDLQReplayAckLevel(
+	ctx context.Context,
 ) (int64, error) {
 	dlqMetadata, err := q.queue.GetDLQAckLevels(ctx)
 	if err != nil {
 		return common.EmptyMessageID, err
 	}
 
-	ackLevel, ok := dlqMetadata[localDomainReplicationCluster]
+	ackLevel, ok := dlqMetadata[dlqReplayAckLevelKey]
 	if !ok {
 		return common.EmptyMessageID, nil
 	}
 	return ackLevel, nil
 }
 
+func (q *replicationQueueImpl) UpdateDLQMergeFence(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+	fence *DLQMergeFence,
+) error {
+	token, err := json.Marshal(fence)
+	if err != nil {
+		return fmt.Errorf("failed to encode dlq merge fence: %v", err)
+	}
+
+	return q.queue.UpdateDLQMergeToken(
+		ctx,
+		string(token),
+		getDLQAckLevelKey(taskType, consumerGroup),
+	)
+}
+
+func (q *replicationQueueImpl) GetDLQMergeFence(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	consumerGroup string,
+) (*DLQMergeFence, error) {
+	mergeTokens, err := q.queue.GetDLQMergeTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := mergeTokens[getDLQAckLevelKey(taskType, consumerGroup)]
+	if !ok || len(token) == 0 {
+		return nil, nil
+	}
+
+	var fence DLQMergeFence
+	if err := json.Unmarshal([]byte(token), &fence); err != nil {
+		return nil, fmt.Errorf("failed to decode dlq merge fence: %v", err)
+	}
+	return &fence, nil
+}
+
+// UpdateDLQExecutionJournal replaces the execution journal of the consumer group
+func (q *replicationQueueImpl) UpdateDLQExecutionJournal(
+	ctx context.Context,
+	consumerGroup string,
+	journal *DLQExecutionJournal,
+) error {
+	token, err := json.Marshal(journal)
+	if err != nil {
+		return fmt.Errorf("failed to encode dlq execution journal: %v", err)
+	}
+
+	return q.queue.UpdateDLQMergeToken(
+		ctx,
+		string(token),
+		getDLQExecutionJournalKey(consumerGroup),
+	)
+}
+
+// GetDLQExecutionJournal returns the execution journal of the consumer group, which is empty if it was never updated
+func (q *replicationQueueImpl) GetDLQExecutionJournal(
+	ctx context.Context,
+	consumerGroup string,
+) (*DLQExecutionJournal, error) {
+	mergeTokens, err := q.queue.GetDLQMergeTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	journal := &DLQExecutionJournal{}
+	token, ok := mergeTokens[getDLQExecutionJournalKey(consumerGroup)]
+	if !ok || len(token) == 0 {
+		return journal, nil
+	}
+	if err := json.Unmarshal([]byte(token), journal); err != nil {
+		return nil, fmt.Errorf("failed to decode dlq execution journal: %v", err)
+	}
+	return journal, nil
+}
+
+// UpdateDLQProcessingPaused records whether the merges of the consumer group are paused
+func (q *replicationQueueImpl) UpdateDLQProcessingPaused(
+	ctx context.Context,
+	consumerGroup string,
+	paused bool,
+) error {
+	return q.queue.UpdateDLQMergeToken(
+		ctx,
+		strconv.FormatBool(paused),
+		getDLQProcessingPausedKey(consumerGroup),
+	)
+}
+
+// GetDLQProcessingPaused returns whether the merges of the consumer group are paused, they are not unless paused once
+func (q *replicationQueueImpl) GetDLQProcessingPaused(
+	ctx context.Context,
+	consumerGroup string,
+) (bool, error) {
+	mergeTokens, err := q.queue.GetDLQMergeTokens(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	token, ok := mergeTokens[getDLQProcessingPausedKey(consumerGroup)]
+	if !ok || len(token) == 0 {
+		return false, nil
+	}
+	paused, err := strconv.ParseBool(token)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode dlq processing paused state: %v", err)
+	}
+	return paused, nil
+}
+
+// UpdateDLQConflictResolutionPolicy sets the conflict resolution policy of the domain. Each domain's policy is stored
+// under its own key so that concurrent updates of the policies of different domains don't overwrite each other,
+// the default policy is stored as an empty policy
+func (q *replicationQueueImpl) UpdateDLQConflictResolutionPolicy(
+	ctx context.Context,
+	domainName string,
+	policy ConflictResolutionPolicy,
+) error {
+	token := string(policy)
+	if policy == defaultConflictResolutionPolicy {
+		token = ""
+	}
+
+	return q.queue.UpdateDLQMergeToken(
+		ctx,
+		token,
+		getDLQConflictPolicyKey(domainName),
+	)
+}
+
+func (q *replicationQueueImpl) GetDLQConflictResolutionPolicies(
+	ctx context.Context,
+) (map[string]ConflictResolutionPolicy, error) {
+	mergeTokens, err := q.queue.GetDLQMergeTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]ConflictResolutionPolicy)
+	prefix := getDLQConflictPolicyKey("")
+	for key, token := range mergeTokens {
+		if !strings.HasPrefix(key, prefix) || len(token) == 0 {
+			continue
+		}
+		policy, err := ParseConflictResolutionPolicy(token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode dlq conflict resolution policy: %v", err)
+		}
+		policies[strings.TrimPrefix(key, prefix)] = policy
+	}
+	return policies, nil
+}
+
 func (q *replicationQueueImpl) RangeDeleteMessagesFromDLQ(
 	ctx context.Context,
+	taskType types.ReplicationTaskType,
 	firstMessageID int64,
 	lastMessageID int64,
 ) error {
-	return q.queue.RangeDeleteMessagesFromDLQ(
-		ctx,
-		firstMessageID,
-		lastMessageID,
-	)
+	if taskType == AllTaskTypes {
+		return q.queue.RangeDeleteMessagesFromDLQ(
+			ctx,
+			firstMessageID,
+			lastMessageID,
+		)
+	}
+
+	return q.deleteMessagesFromDLQOneByOne(ctx, taskType, firstMessageID, lastMessageID, func(messageID int64) error {
+		return q.queue.DeleteMessageFromDLQ(ctx, messageID)
+	})
+}
+
+// RangeSoftDeleteMessagesFromDLQ marks the DLQ messages in the range as deleted, they are no longer read
+// but kept until DeleteSoftDeletedMessagesFromDLQ removes them
+func (q *replicationQueueImpl) RangeSoftDeleteMessagesFromDLQ(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	if taskType == AllTaskTypes {
+		return q.queue.RangeSoftDeleteMessagesFromDLQ(
+			ctx,
+			firstMessageID,
+			lastMessageID,
+		)
+	}
+
+	return q.deleteMessagesFromDLQOneByOne(ctx, taskType, firstMessageID, lastMessageID, func(messageID int64) error {
+		return q.queue.RangeSoftDeleteMessagesFromDLQ(ctx, messageID-1, messageID)
+	})
+}
+
+// deleteMessagesFromDLQOneByOne deletes the DLQ messages of the task type in the range with deleteMessage.
+// Messages of other task types may be interleaved in the range, so only the matching ones are deleted one by one.
+func (q *replicationQueueImpl) deleteMessagesFromDLQOneByOne(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+	firstMessageID int64,
+	lastMessageID int64,
+	deleteMessage func(messageID int64) error,
+) error {
+	var pageToken []byte
+	for {
+		tasks, token, err := q.GetMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID, dlqRangeDeletePageSize, pageToken)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if err := deleteMessage(task.SourceTaskID); err != nil {
+				return err
+			}
+		}
+		if len(token) == 0 {
+			return nil
+		}
+		pageToken = token
+	}
+}
+
+// RangeDeleteMessagesFromDLQByDomain deletes the DLQ messages of a single domain in the range
+func (q *replicationQueueImpl) RangeDeleteMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+
+	return q.queue.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID)
 }
 
 func (q *replicationQueueImpl) DeleteMessageFromDLQ(
@@ -262,8 +944,152 @@ func (q *replicationQueueImpl) DeleteMessageFromDLQ(
 	return q.queue.DeleteMessageFromDLQ(ctx, messageID)
 }
 
-func (q *replicationQueueImpl) GetDLQSize(ctx context.Context) (int64, error) {
-	return q.queue.GetDLQSize(ctx)
+// DeleteSoftDeletedMessagesFromDLQ removes the DLQ messages soft deleted before deletedBefore
+func (q *replicationQueueImpl) DeleteSoftDeletedMessagesFromDLQ(
+	ctx context.Context,
+	deletedBefore time.Time,
+) error {
+
+	return q.queue.DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore)
+}
+
+func (q *replicationQueueImpl) IncrementDLQMessageAttempts(
+	ctx context.Context,
+	messageID int64,
+) (int, error) {
+
+	messages, _, err := q.queue.ReadMessagesFromDLQ(ctx, messageID-1, messageID, 1, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(messages) == 0 {
+		return 0, &types.EntityNotExistsError{Message: fmt.Sprintf("DLQ message %v does not exist", messageID)}
+	}
+
+	attempts := messages[0].Attempts + 1
+	if err := q.queue.UpdateDLQMessageAttempts(ctx, messageID, attempts); err != nil {
+		return 0, err
+	}
+	return attempts, nil
+}
+
+func (q *replicationQueueImpl) GetDLQSize(
+	ctx context.Context,
+	taskType types.ReplicationTaskType,
+) (int64, error) {
+	if taskType == AllTaskTypes {
+		return q.queue.GetDLQSize(ctx)
+	}
+
+	// The task type is only known after decoding the payload,
+	// so count the matching messages page by page
+	var size int64
+	var pageToken []byte
+	for {
+		tasks, token, err := q.GetMessagesFromDLQ(ctx, taskType, common.EmptyMessageID, math.MaxInt64, dlqRangeDeletePageSize, pageToken)
+		if err != nil {
+			return 0, err
+		}
+		size += int64(len(tasks))
+		if len(token) == 0 {
+			return size, nil
+		}
+		pageToken = token
+	}
+}
+
+// GetDLQMessageTypeHistogram returns the number of DLQ messages of each task type,
+// messages enqueued before the task type was persisted are not counted
+func (q *replicationQueueImpl) GetDLQMessageTypeHistogram(
+	ctx context.Context,
+) (map[types.ReplicationTaskType]int64, error) {
+
+	counts, err := q.queue.GetDLQMessageTypeHistogram(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := make(map[types.ReplicationTaskType]int64, len(counts))
+	for taskType, count := range counts {
+		histogram[types.ReplicationTaskType(taskType)] = count
+	}
+	return histogram, nil
+}
+
+func (q *replicationQueueImpl) RecordDLQMerge(
+	ctx context.Context,
+	record DLQMergeRecord,
+) error {
+	return q.queue.InsertDLQMergeRecord(ctx, &persistence.DLQMergeRecord{
+		MergedAt:       record.MergedAt,
+		StartMessageID: record.StartMessageID,
+		EndMessageID:   record.EndMessageID,
+		MergedCount:    record.MergedCount,
+		FailedCount:    record.FailedCount,
+		Duration:       record.Duration,
+		TriggeredBy:    record.TriggeredBy,
+		TraceID:        record.TraceID,
+	})
+}
+
+func (q *replicationQueueImpl) GetDLQMergeHistory(
+	ctx context.Context,
+	limit int,
+) ([]DLQMergeRecord, error) {
+	records, err := q.queue.GetDLQMergeHistory(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]DLQMergeRecord, 0, len(records))
+	for _, record := range records {
+		history = append(history, DLQMergeRecord{
+			MergedAt:       record.MergedAt,
+			StartMessageID: record.StartMessageID,
+			EndMessageID:   record.EndMessageID,
+			MergedCount:    record.MergedCount,
+			FailedCount:    record.FailedCount,
+			Duration:       record.Duration,
+			TriggeredBy:    record.TriggeredBy,
+			TraceID:        record.TraceID,
+		})
+	}
+	return history, nil
+}
+
+// getDLQAckLevelKey returns the key under which the DLQ ack level of the given task type and consumer group is stored.
+// AllTaskTypes and the DefaultConsumerGroup map to the legacy keys so existing ack levels remain valid.
+func getDLQAckLevelKey(taskType types.ReplicationTaskType, consumerGroup string) string {
+	key := localDomainReplicationCluster
+	if taskType != AllTaskTypes {
+		key = fmt.Sprintf("%v-%v", key, taskType)
+	}
+	if consumerGroup != DefaultConsumerGroup {
+		key = fmt.Sprintf("%v@%v", key, consumerGroup)
+	}
+	return key
+}
+
+func getDLQExecutionJournalKey(consumerGroup string) string {
+	if consumerGroup != DefaultConsumerGroup {
+		return fmt.Sprintf("%v@%v", dlqExecutionJournalKey, consumerGroup)
+	}
+	return dlqExecutionJournalKey
+}
+
+func getDLQConflictPolicyKey(domainName string) string {
+	return fmt.Sprintf("%v/%v", dlqConflictPolicyKey, domainName)
+}
+
+func getDLQProcessingPausedKey(consumerGroup string) string {
+	if consumerGroup != DefaultConsumerGroup {
+		return fmt.Sprintf("%v@%v", dlqProcessingPausedKey, consumerGroup)
+	}
+	return dlqProcessingPausedKey
+}
+
+func matchesTaskType(task *types.ReplicationTask, taskType types.ReplicationTaskType) bool {
+	return taskType == AllTaskTypes || task.GetTaskType() == taskType
 }
 
 func (q *replicationQueueImpl) purgeAckedMessages() error {
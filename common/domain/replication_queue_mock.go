@@ -29,12 +29,64 @@ package domain
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-
 	types "github.com/uber/cadence/common/types"
 )
 
+// MockDLQMergeHistory is a mock of DLQMergeHistory interface.
+type MockDLQMergeHistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockDLQMergeHistoryMockRecorder
+}
+
+// MockDLQMergeHistoryMockRecorder is the mock recorder for MockDLQMergeHistory.
+type MockDLQMergeHistoryMockRecorder struct {
+	mock *MockDLQMergeHistory
+}
+
+// NewMockDLQMergeHistory creates a new mock instance.
+func NewMockDLQMergeHistory(ctrl *gomock.Controller) *MockDLQMergeHistory {
+	mock := &MockDLQMergeHistory{ctrl: ctrl}
+	mock.recorder = &MockDLQMergeHistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDLQMergeHistory) EXPECT() *MockDLQMergeHistoryMockRecorder {
+	return m.recorder
+}
+
+// GetDLQMergeHistory mocks base method.
+func (m *MockDLQMergeHistory) GetDLQMergeHistory(ctx context.Context, limit int) ([]DLQMergeRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQMergeHistory", ctx, limit)
+	ret0, _ := ret[0].([]DLQMergeRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQMergeHistory indicates an expected call of GetDLQMergeHistory.
+func (mr *MockDLQMergeHistoryMockRecorder) GetDLQMergeHistory(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQMergeHistory", reflect.TypeOf((*MockDLQMergeHistory)(nil).GetDLQMergeHistory), ctx, limit)
+}
+
+// RecordDLQMerge mocks base method.
+func (m *MockDLQMergeHistory) RecordDLQMerge(ctx context.Context, record DLQMergeRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDLQMerge", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDLQMerge indicates an expected call of RecordDLQMerge.
+func (mr *MockDLQMergeHistoryMockRecorder) RecordDLQMerge(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDLQMerge", reflect.TypeOf((*MockDLQMergeHistory)(nil).RecordDLQMerge), ctx, record)
+}
+
 // MockReplicationQueue is a mock of ReplicationQueue interface.
 type MockReplicationQueue struct {
 	ctrl     *gomock.Controller
@@ -58,6 +110,20 @@ func (m *MockReplicationQueue) EXPECT() *MockReplicationQueueMockRecorder {
 	return m.recorder
 }
 
+// CompareAndSwapDLQAckLevel mocks base method.
+func (m *MockReplicationQueue) CompareAndSwapDLQAckLevel(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string, expectedLevel, newLevel int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSwapDLQAckLevel", ctx, taskType, consumerGroup, expectedLevel, newLevel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareAndSwapDLQAckLevel indicates an expected call of CompareAndSwapDLQAckLevel.
+func (mr *MockReplicationQueueMockRecorder) CompareAndSwapDLQAckLevel(ctx, taskType, consumerGroup, expectedLevel, newLevel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSwapDLQAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).CompareAndSwapDLQAckLevel), ctx, taskType, consumerGroup, expectedLevel, newLevel)
+}
+
 // DeleteMessageFromDLQ mocks base method.
 func (m *MockReplicationQueue) DeleteMessageFromDLQ(ctx context.Context, messageID int64) error {
 	m.ctrl.T.Helper()
@@ -72,6 +138,48 @@ func (mr *MockReplicationQueueMockRecorder) DeleteMessageFromDLQ(ctx, messageID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessageFromDLQ", reflect.TypeOf((*MockReplicationQueue)(nil).DeleteMessageFromDLQ), ctx, messageID)
 }
 
+// DeleteSoftDeletedMessagesFromDLQ mocks base method.
+func (m *MockReplicationQueue) DeleteSoftDeletedMessagesFromDLQ(ctx context.Context, deletedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSoftDeletedMessagesFromDLQ", ctx, deletedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSoftDeletedMessagesFromDLQ indicates an expected call of DeleteSoftDeletedMessagesFromDLQ.
+func (mr *MockReplicationQueueMockRecorder) DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSoftDeletedMessagesFromDLQ", reflect.TypeOf((*MockReplicationQueue)(nil).DeleteSoftDeletedMessagesFromDLQ), ctx, deletedBefore)
+}
+
+// EnqueueBatch mocks base method.
+func (m *MockReplicationQueue) EnqueueBatch(ctx context.Context, tasks []*types.ReplicationTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueBatch", ctx, tasks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueBatch indicates an expected call of EnqueueBatch.
+func (mr *MockReplicationQueueMockRecorder) EnqueueBatch(ctx, tasks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueBatch", reflect.TypeOf((*MockReplicationQueue)(nil).EnqueueBatch), ctx, tasks)
+}
+
+// EnqueueForCluster mocks base method.
+func (m *MockReplicationQueue) EnqueueForCluster(ctx context.Context, clusterName string, message interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueForCluster", ctx, clusterName, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueForCluster indicates an expected call of EnqueueForCluster.
+func (mr *MockReplicationQueueMockRecorder) EnqueueForCluster(ctx, clusterName, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueForCluster", reflect.TypeOf((*MockReplicationQueue)(nil).EnqueueForCluster), ctx, clusterName, message)
+}
+
 // GetAckLevels mocks base method.
 func (m *MockReplicationQueue) GetAckLevels(ctx context.Context) (map[string]int64, error) {
 	m.ctrl.T.Helper()
@@ -88,39 +196,175 @@ func (mr *MockReplicationQueueMockRecorder) GetAckLevels(ctx interface{}) *gomoc
 }
 
 // GetDLQAckLevel mocks base method.
-func (m *MockReplicationQueue) GetDLQAckLevel(ctx context.Context) (int64, error) {
+func (m *MockReplicationQueue) GetDLQAckLevel(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDLQAckLevel", ctx)
+	ret := m.ctrl.Call(m, "GetDLQAckLevel", ctx, taskType, consumerGroup)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetDLQAckLevel indicates an expected call of GetDLQAckLevel.
-func (mr *MockReplicationQueueMockRecorder) GetDLQAckLevel(ctx interface{}) *gomock.Call {
+func (mr *MockReplicationQueueMockRecorder) GetDLQAckLevel(ctx, taskType, consumerGroup interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQAckLevel), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQAckLevel), ctx, taskType, consumerGroup)
+}
+
+// GetDLQAckLevelWithVersion mocks base method.
+func (m *MockReplicationQueue) GetDLQAckLevelWithVersion(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQAckLevelWithVersion", ctx, taskType, consumerGroup)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetDLQAckLevelWithVersion indicates an expected call of GetDLQAckLevelWithVersion.
+func (mr *MockReplicationQueueMockRecorder) GetDLQAckLevelWithVersion(ctx, taskType, consumerGroup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQAckLevelWithVersion", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQAckLevelWithVersion), ctx, taskType, consumerGroup)
+}
+
+// GetDLQConflictResolutionPolicies mocks base method.
+func (m *MockReplicationQueue) GetDLQConflictResolutionPolicies(ctx context.Context) (map[string]ConflictResolutionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQConflictResolutionPolicies", ctx)
+	ret0, _ := ret[0].(map[string]ConflictResolutionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQConflictResolutionPolicies indicates an expected call of GetDLQConflictResolutionPolicies.
+func (mr *MockReplicationQueueMockRecorder) GetDLQConflictResolutionPolicies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQConflictResolutionPolicies", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQConflictResolutionPolicies), ctx)
+}
+
+// GetDLQConsumerAckLevels mocks base method.
+func (m *MockReplicationQueue) GetDLQConsumerAckLevels(ctx context.Context) ([]*DLQConsumerAckLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQConsumerAckLevels", ctx)
+	ret0, _ := ret[0].([]*DLQConsumerAckLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQConsumerAckLevels indicates an expected call of GetDLQConsumerAckLevels.
+func (mr *MockReplicationQueueMockRecorder) GetDLQConsumerAckLevels(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQConsumerAckLevels", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQConsumerAckLevels), ctx)
+}
+
+// GetDLQExecutionJournal mocks base method.
+func (m *MockReplicationQueue) GetDLQExecutionJournal(ctx context.Context, consumerGroup string) (*DLQExecutionJournal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQExecutionJournal", ctx, consumerGroup)
+	ret0, _ := ret[0].(*DLQExecutionJournal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQExecutionJournal indicates an expected call of GetDLQExecutionJournal.
+func (mr *MockReplicationQueueMockRecorder) GetDLQExecutionJournal(ctx, consumerGroup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQExecutionJournal", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQExecutionJournal), ctx, consumerGroup)
+}
+
+// GetDLQMergeFence mocks base method.
+func (m *MockReplicationQueue) GetDLQMergeFence(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string) (*DLQMergeFence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQMergeFence", ctx, taskType, consumerGroup)
+	ret0, _ := ret[0].(*DLQMergeFence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQMergeFence indicates an expected call of GetDLQMergeFence.
+func (mr *MockReplicationQueueMockRecorder) GetDLQMergeFence(ctx, taskType, consumerGroup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQMergeFence", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQMergeFence), ctx, taskType, consumerGroup)
+}
+
+// GetDLQMergeHistory mocks base method.
+func (m *MockReplicationQueue) GetDLQMergeHistory(ctx context.Context, limit int) ([]DLQMergeRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQMergeHistory", ctx, limit)
+	ret0, _ := ret[0].([]DLQMergeRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQMergeHistory indicates an expected call of GetDLQMergeHistory.
+func (mr *MockReplicationQueueMockRecorder) GetDLQMergeHistory(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQMergeHistory", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQMergeHistory), ctx, limit)
+}
+
+// GetDLQMessageTypeHistogram mocks base method.
+func (m *MockReplicationQueue) GetDLQMessageTypeHistogram(ctx context.Context) (map[types.ReplicationTaskType]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQMessageTypeHistogram", ctx)
+	ret0, _ := ret[0].(map[types.ReplicationTaskType]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQMessageTypeHistogram indicates an expected call of GetDLQMessageTypeHistogram.
+func (mr *MockReplicationQueueMockRecorder) GetDLQMessageTypeHistogram(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQMessageTypeHistogram", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQMessageTypeHistogram), ctx)
+}
+
+// GetDLQProcessingPaused mocks base method.
+func (m *MockReplicationQueue) GetDLQProcessingPaused(ctx context.Context, consumerGroup string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQProcessingPaused", ctx, consumerGroup)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQProcessingPaused indicates an expected call of GetDLQProcessingPaused.
+func (mr *MockReplicationQueueMockRecorder) GetDLQProcessingPaused(ctx, consumerGroup interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQProcessingPaused", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQProcessingPaused), ctx, consumerGroup)
+}
+
+// GetDLQReplayAckLevel mocks base method.
+func (m *MockReplicationQueue) GetDLQReplayAckLevel(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDLQReplayAckLevel", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDLQReplayAckLevel indicates an expected call of GetDLQReplayAckLevel.
+func (mr *MockReplicationQueueMockRecorder) GetDLQReplayAckLevel(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQReplayAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQReplayAckLevel), ctx)
 }
 
 // GetDLQSize mocks base method.
-func (m *MockReplicationQueue) GetDLQSize(ctx context.Context) (int64, error) {
+func (m *MockReplicationQueue) GetDLQSize(ctx context.Context, taskType types.ReplicationTaskType) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDLQSize", ctx)
+	ret := m.ctrl.Call(m, "GetDLQSize", ctx, taskType)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetDLQSize indicates an expected call of GetDLQSize.
-func (mr *MockReplicationQueueMockRecorder) GetDLQSize(ctx interface{}) *gomock.Call {
+func (mr *MockReplicationQueueMockRecorder) GetDLQSize(ctx, taskType interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQSize", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQSize), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDLQSize", reflect.TypeOf((*MockReplicationQueue)(nil).GetDLQSize), ctx, taskType)
 }
 
 // GetMessagesFromDLQ mocks base method.
-func (m *MockReplicationQueue) GetMessagesFromDLQ(ctx context.Context, firstMessageID, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error) {
+func (m *MockReplicationQueue) GetMessagesFromDLQ(ctx context.Context, taskType types.ReplicationTaskType, firstMessageID, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMessagesFromDLQ", ctx, firstMessageID, lastMessageID, pageSize, pageToken)
+	ret := m.ctrl.Call(m, "GetMessagesFromDLQ", ctx, taskType, firstMessageID, lastMessageID, pageSize, pageToken)
 	ret0, _ := ret[0].([]*types.ReplicationTask)
 	ret1, _ := ret[1].([]byte)
 	ret2, _ := ret[2].(error)
@@ -128,15 +372,63 @@ func (m *MockReplicationQueue) GetMessagesFromDLQ(ctx context.Context, firstMess
 }
 
 // GetMessagesFromDLQ indicates an expected call of GetMessagesFromDLQ.
-func (mr *MockReplicationQueueMockRecorder) GetMessagesFromDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+func (mr *MockReplicationQueueMockRecorder) GetMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesFromDLQ", reflect.TypeOf((*MockReplicationQueue)(nil).GetMessagesFromDLQ), ctx, taskType, firstMessageID, lastMessageID, pageSize, pageToken)
+}
+
+// GetMessagesFromDLQByDomain mocks base method.
+func (m *MockReplicationQueue) GetMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesFromDLQByDomain", ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken)
+	ret0, _ := ret[0].([]*types.ReplicationTask)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMessagesFromDLQByDomain indicates an expected call of GetMessagesFromDLQByDomain.
+func (mr *MockReplicationQueueMockRecorder) GetMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesFromDLQByDomain", reflect.TypeOf((*MockReplicationQueue)(nil).GetMessagesFromDLQByDomain), ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken)
+}
+
+// GetMessagesFromDLQByTimeRange mocks base method.
+func (m *MockReplicationQueue) GetMessagesFromDLQByTimeRange(ctx context.Context, startTime, endTime time.Time, firstMessageID, lastMessageID int64, pageSize int, pageToken []byte) ([]*types.ReplicationTask, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesFromDLQByTimeRange", ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken)
+	ret0, _ := ret[0].([]*types.ReplicationTask)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMessagesFromDLQByTimeRange indicates an expected call of GetMessagesFromDLQByTimeRange.
+func (mr *MockReplicationQueueMockRecorder) GetMessagesFromDLQByTimeRange(ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesFromDLQ", reflect.TypeOf((*MockReplicationQueue)(nil).GetMessagesFromDLQ), ctx, firstMessageID, lastMessageID, pageSize, pageToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesFromDLQByTimeRange", reflect.TypeOf((*MockReplicationQueue)(nil).GetMessagesFromDLQByTimeRange), ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken)
+}
+
+// GetMessagesFromDLQGrouped mocks base method.
+func (m *MockReplicationQueue) GetMessagesFromDLQGrouped(ctx context.Context, firstMessageID, lastMessageID int64, pageSize int, pageToken []byte) (map[string][]*types.ReplicationTask, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesFromDLQGrouped", ctx, firstMessageID, lastMessageID, pageSize, pageToken)
+	ret0, _ := ret[0].(map[string][]*types.ReplicationTask)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMessagesFromDLQGrouped indicates an expected call of GetMessagesFromDLQGrouped.
+func (mr *MockReplicationQueueMockRecorder) GetMessagesFromDLQGrouped(ctx, firstMessageID, lastMessageID, pageSize, pageToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesFromDLQGrouped", reflect.TypeOf((*MockReplicationQueue)(nil).GetMessagesFromDLQGrouped), ctx, firstMessageID, lastMessageID, pageSize, pageToken)
 }
 
 // GetReplicationMessages mocks base method.
-func (m *MockReplicationQueue) GetReplicationMessages(ctx context.Context, lastMessageID int64, maxCount int) ([]*types.ReplicationTask, int64, error) {
+func (m *MockReplicationQueue) GetReplicationMessages(ctx context.Context, clusterName string, lastMessageID int64, maxCount int) ([]*types.ReplicationTask, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReplicationMessages", ctx, lastMessageID, maxCount)
+	ret := m.ctrl.Call(m, "GetReplicationMessages", ctx, clusterName, lastMessageID, maxCount)
 	ret0, _ := ret[0].([]*types.ReplicationTask)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -144,9 +436,38 @@ func (m *MockReplicationQueue) GetReplicationMessages(ctx context.Context, lastM
 }
 
 // GetReplicationMessages indicates an expected call of GetReplicationMessages.
-func (mr *MockReplicationQueueMockRecorder) GetReplicationMessages(ctx, lastMessageID, maxCount interface{}) *gomock.Call {
+func (mr *MockReplicationQueueMockRecorder) GetReplicationMessages(ctx, clusterName, lastMessageID, maxCount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationMessages", reflect.TypeOf((*MockReplicationQueue)(nil).GetReplicationMessages), ctx, lastMessageID, maxCount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationMessages", reflect.TypeOf((*MockReplicationQueue)(nil).GetReplicationMessages), ctx, clusterName, lastMessageID, maxCount)
+}
+
+// IncrementDLQMessageAttempts mocks base method.
+func (m *MockReplicationQueue) IncrementDLQMessageAttempts(ctx context.Context, messageID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementDLQMessageAttempts", ctx, messageID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementDLQMessageAttempts indicates an expected call of IncrementDLQMessageAttempts.
+func (mr *MockReplicationQueueMockRecorder) IncrementDLQMessageAttempts(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementDLQMessageAttempts", reflect.TypeOf((*MockReplicationQueue)(nil).IncrementDLQMessageAttempts), ctx, messageID)
+}
+
+// NackMessage mocks base method.
+func (m *MockReplicationQueue) NackMessage(ctx context.Context, messageID int64, retryAfter time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NackMessage", ctx, messageID, retryAfter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NackMessage indicates an expected call of NackMessage.
+func (mr *MockReplicationQueueMockRecorder) NackMessage(ctx, messageID, retryAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NackMessage", reflect.TypeOf((*MockReplicationQueue)(nil).NackMessage), ctx, messageID, retryAfter)
 }
 
 // Publish mocks base method.
@@ -178,17 +499,88 @@ func (mr *MockReplicationQueueMockRecorder) PublishToDLQ(ctx, message interface{
 }
 
 // RangeDeleteMessagesFromDLQ mocks base method.
-func (m *MockReplicationQueue) RangeDeleteMessagesFromDLQ(ctx context.Context, firstMessageID, lastMessageID int64) error {
+func (m *MockReplicationQueue) RangeDeleteMessagesFromDLQ(ctx context.Context, taskType types.ReplicationTaskType, firstMessageID, lastMessageID int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RangeDeleteMessagesFromDLQ", ctx, firstMessageID, lastMessageID)
+	ret := m.ctrl.Call(m, "RangeDeleteMessagesFromDLQ", ctx, taskType, firstMessageID, lastMessageID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RangeDeleteMessagesFromDLQ indicates an expected call of RangeDeleteMessagesFromDLQ.
-func (mr *MockReplicationQueueMockRecorder) RangeDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID interface{}) *gomock.Call {
+func (mr *MockReplicationQueueMockRecorder) RangeDeleteMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeDeleteMessagesFromDLQ", reflect.TypeOf((*MockReplicationQueue)(nil).RangeDeleteMessagesFromDLQ), ctx, taskType, firstMessageID, lastMessageID)
+}
+
+// RangeDeleteMessagesFromDLQByDomain mocks base method.
+func (m *MockReplicationQueue) RangeDeleteMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID, lastMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RangeDeleteMessagesFromDLQByDomain", ctx, domainID, firstMessageID, lastMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RangeDeleteMessagesFromDLQByDomain indicates an expected call of RangeDeleteMessagesFromDLQByDomain.
+func (mr *MockReplicationQueueMockRecorder) RangeDeleteMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeDeleteMessagesFromDLQByDomain", reflect.TypeOf((*MockReplicationQueue)(nil).RangeDeleteMessagesFromDLQByDomain), ctx, domainID, firstMessageID, lastMessageID)
+}
+
+// RangeSoftDeleteMessagesFromDLQ mocks base method.
+func (m *MockReplicationQueue) RangeSoftDeleteMessagesFromDLQ(ctx context.Context, taskType types.ReplicationTaskType, firstMessageID, lastMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RangeSoftDeleteMessagesFromDLQ", ctx, taskType, firstMessageID, lastMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RangeSoftDeleteMessagesFromDLQ indicates an expected call of RangeSoftDeleteMessagesFromDLQ.
+func (mr *MockReplicationQueueMockRecorder) RangeSoftDeleteMessagesFromDLQ(ctx, taskType, firstMessageID, lastMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeSoftDeleteMessagesFromDLQ", reflect.TypeOf((*MockReplicationQueue)(nil).RangeSoftDeleteMessagesFromDLQ), ctx, taskType, firstMessageID, lastMessageID)
+}
+
+// RecordDLQMerge mocks base method.
+func (m *MockReplicationQueue) RecordDLQMerge(ctx context.Context, record DLQMergeRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDLQMerge", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDLQMerge indicates an expected call of RecordDLQMerge.
+func (mr *MockReplicationQueueMockRecorder) RecordDLQMerge(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDLQMerge", reflect.TypeOf((*MockReplicationQueue)(nil).RecordDLQMerge), ctx, record)
+}
+
+// RestoreDLQAckLevel mocks base method.
+func (m *MockReplicationQueue) RestoreDLQAckLevel(ctx context.Context, snapshot *DLQAckLevelSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDLQAckLevel", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreDLQAckLevel indicates an expected call of RestoreDLQAckLevel.
+func (mr *MockReplicationQueueMockRecorder) RestoreDLQAckLevel(ctx, snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDLQAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).RestoreDLQAckLevel), ctx, snapshot)
+}
+
+// SnapshotDLQAckLevel mocks base method.
+func (m *MockReplicationQueue) SnapshotDLQAckLevel(ctx context.Context) (*DLQAckLevelSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotDLQAckLevel", ctx)
+	ret0, _ := ret[0].(*DLQAckLevelSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotDLQAckLevel indicates an expected call of SnapshotDLQAckLevel.
+func (mr *MockReplicationQueueMockRecorder) SnapshotDLQAckLevel(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeDeleteMessagesFromDLQ", reflect.TypeOf((*MockReplicationQueue)(nil).RangeDeleteMessagesFromDLQ), ctx, firstMessageID, lastMessageID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotDLQAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).SnapshotDLQAckLevel), ctx)
 }
 
 // Start mocks base method.
@@ -230,15 +622,99 @@ func (mr *MockReplicationQueueMockRecorder) UpdateAckLevel(ctx, lastProcessedMes
 }
 
 // UpdateDLQAckLevel mocks base method.
-func (m *MockReplicationQueue) UpdateDLQAckLevel(ctx context.Context, lastProcessedMessageID int64) error {
+func (m *MockReplicationQueue) UpdateDLQAckLevel(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string, lastProcessedMessageID int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateDLQAckLevel", ctx, lastProcessedMessageID)
+	ret := m.ctrl.Call(m, "UpdateDLQAckLevel", ctx, taskType, consumerGroup, lastProcessedMessageID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateDLQAckLevel indicates an expected call of UpdateDLQAckLevel.
-func (mr *MockReplicationQueueMockRecorder) UpdateDLQAckLevel(ctx, lastProcessedMessageID interface{}) *gomock.Call {
+func (mr *MockReplicationQueueMockRecorder) UpdateDLQAckLevel(ctx, taskType, consumerGroup, lastProcessedMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).UpdateDLQAckLevel), ctx, taskType, consumerGroup, lastProcessedMessageID)
+}
+
+// UpdateDLQAckLevelWithVersion mocks base method.
+func (m *MockReplicationQueue) UpdateDLQAckLevelWithVersion(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string, newLevel, expectedVersion int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDLQAckLevelWithVersion", ctx, taskType, consumerGroup, newLevel, expectedVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDLQAckLevelWithVersion indicates an expected call of UpdateDLQAckLevelWithVersion.
+func (mr *MockReplicationQueueMockRecorder) UpdateDLQAckLevelWithVersion(ctx, taskType, consumerGroup, newLevel, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQAckLevelWithVersion", reflect.TypeOf((*MockReplicationQueue)(nil).UpdateDLQAckLevelWithVersion), ctx, taskType, consumerGroup, newLevel, expectedVersion)
+}
+
+// UpdateDLQConflictResolutionPolicy mocks base method.
+func (m *MockReplicationQueue) UpdateDLQConflictResolutionPolicy(ctx context.Context, domainName string, policy ConflictResolutionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDLQConflictResolutionPolicy", ctx, domainName, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDLQConflictResolutionPolicy indicates an expected call of UpdateDLQConflictResolutionPolicy.
+func (mr *MockReplicationQueueMockRecorder) UpdateDLQConflictResolutionPolicy(ctx, domainName, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQConflictResolutionPolicy", reflect.TypeOf((*MockReplicationQueue)(nil).UpdateDLQConflictResolutionPolicy), ctx, domainName, policy)
+}
+
+// UpdateDLQExecutionJournal mocks base method.
+func (m *MockReplicationQueue) UpdateDLQExecutionJournal(ctx context.Context, consumerGroup string, journal *DLQExecutionJournal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDLQExecutionJournal", ctx, consumerGroup, journal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDLQExecutionJournal indicates an expected call of UpdateDLQExecutionJournal.
+func (mr *MockReplicationQueueMockRecorder) UpdateDLQExecutionJournal(ctx, consumerGroup, journal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQExecutionJournal", reflect.TypeOf((*MockReplicationQueue)(nil).UpdateDLQExecutionJournal), ctx, consumerGroup, journal)
+}
+
+// UpdateDLQMergeFence mocks base method.
+func (m *MockReplicationQueue) UpdateDLQMergeFence(ctx context.Context, taskType types.ReplicationTaskType, consumerGroup string, fence *DLQMergeFence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDLQMergeFence", ctx, taskType, consumerGroup, fence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDLQMergeFence indicates an expected call of UpdateDLQMergeFence.
+func (mr *MockReplicationQueueMockRecorder) UpdateDLQMergeFence(ctx, taskType, consumerGroup, fence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQMergeFence", reflect.TypeOf((*MockReplicationQueue)(nil).UpdateDLQMergeFence), ctx, taskType, consumerGroup, fence)
+}
+
+// UpdateDLQProcessingPaused mocks base method.
+func (m *MockReplicationQueue) UpdateDLQProcessingPaused(ctx context.Context, consumerGroup string, paused bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDLQProcessingPaused", ctx, consumerGroup, paused)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDLQProcessingPaused indicates an expected call of UpdateDLQProcessingPaused.
+func (mr *MockReplicationQueueMockRecorder) UpdateDLQProcessingPaused(ctx, consumerGroup, paused interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQProcessingPaused", reflect.TypeOf((*MockReplicationQueue)(nil).UpdateDLQProcessingPaused), ctx, consumerGroup, paused)
+}
+
+// UpdateDLQReplayAckLevel mocks base method.
+func (m *MockReplicationQueue) UpdateDLQReplayAckLevel(ctx context.Context, lastReplayedMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDLQReplayAckLevel", ctx, lastReplayedMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDLQReplayAckLevel indicates an expected call of UpdateDLQReplayAckLevel.
+func (mr *MockReplicationQueueMockRecorder) UpdateDLQReplayAckLevel(ctx, lastReplayedMessageID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).UpdateDLQAckLevel), ctx, lastProcessedMessageID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDLQReplayAckLevel", reflect.TypeOf((*MockReplicationQueue)(nil).UpdateDLQReplayAckLevel), ctx, lastReplayedMessageID)
 }
@@ -0,0 +1,973 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/codec"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/thrift"
+)
+
+type (
+	replicationQueueSuite struct {
+		suite.Suite
+
+		*require.Assertions
+		controller *gomock.Controller
+
+		mockQueue        *persistence.MockQueueManager
+		replicationQueue *replicationQueueImpl
+	}
+)
+
+func TestReplicationQueueSuite(t *testing.T) {
+	s := new(replicationQueueSuite)
+	suite.Run(t, s)
+}
+
+func (s *replicationQueueSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+
+	s.mockQueue = persistence.NewMockQueueManager(s.controller)
+	s.replicationQueue = NewReplicationQueue(
+		s.mockQueue,
+		"testCluster",
+		metrics.NewNoopMetricsClient(),
+		loggerimpl.NewLoggerForTest(s.Suite),
+	).(*replicationQueueImpl)
+}
+
+func (s *replicationQueueSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *replicationQueueSuite) TestEnqueueForCluster() {
+	task := &types.ReplicationTask{
+		TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID: 10,
+		EnqueuedAt:   time.Now(),
+	}
+	var payload []byte
+	s.mockQueue.EXPECT().EnqueueMessage(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, message []byte) error {
+			payload = message
+			return nil
+		},
+	).Times(1)
+
+	err := s.replicationQueue.EnqueueForCluster(context.Background(), "destination", task)
+	s.NoError(err)
+	// the forwarded task is a copy
+	s.Equal(int64(10), task.SourceTaskID)
+
+	legacyTask := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), CreationTime: common.Int64Ptr(1)}
+	legacyPayload, err := codec.NewThriftRWEncoder().Encode(thrift.FromReplicationTask(legacyTask))
+	s.NoError(err)
+	s.mockQueue.EXPECT().ReadMessages(gomock.Any(), int64(0), 10).Return([]*persistence.QueueMessage{
+		{ID: 1, Payload: payload},
+		{ID: 2, Payload: legacyPayload},
+	}, nil).Times(2)
+
+	// the destination cluster reads the forwarded task along with the tasks of every cluster
+	tasks, lastMessageID, err := s.replicationQueue.GetReplicationMessages(context.Background(), "destination", 0, 10)
+	s.NoError(err)
+	s.Equal([]*types.ReplicationTask{{TaskType: types.ReplicationTaskTypeDomain.Ptr()}, legacyTask}, tasks)
+	s.Equal(int64(2), lastMessageID)
+
+	// the other clusters skip it
+	tasks, lastMessageID, err = s.replicationQueue.GetReplicationMessages(context.Background(), "other", 0, 10)
+	s.NoError(err)
+	s.Equal([]*types.ReplicationTask{legacyTask}, tasks)
+	s.Equal(int64(2), lastMessageID)
+}
+
+func (s *replicationQueueSuite) TestEnqueueForCluster_InvalidCluster() {
+	s.mockQueue.EXPECT().EnqueueMessage(gomock.Any(), gomock.Any()).Times(0)
+
+	task := &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr()}
+	for _, clusterName := range []string{"", "testCluster"} {
+		err := s.replicationQueue.EnqueueForCluster(context.Background(), clusterName, task)
+		s.IsType(&types.BadRequestError{}, err)
+	}
+}
+
+func (s *replicationQueueSuite) TestEnqueueForCluster_UnsupportedTaskType() {
+	s.mockQueue.EXPECT().EnqueueMessage(gomock.Any(), gomock.Any()).Times(0)
+
+	for _, taskType := range []types.ReplicationTaskType{
+		types.ReplicationTaskTypeHistoryV2,
+		types.ReplicationTaskTypeSyncActivity,
+		types.ReplicationTaskTypeFailoverMarker,
+	} {
+		err := s.replicationQueue.EnqueueForCluster(context.Background(), "destination", &types.ReplicationTask{TaskType: taskType.Ptr()})
+		s.IsType(&types.BadRequestError{}, err)
+	}
+}
+
+func (s *replicationQueueSuite) TestGetDLQAckLevel_AllTaskTypes() {
+	s.mockQueue.EXPECT().GetDLQAckLevels(gomock.Any()).Return(map[string]int64{
+		localDomainReplicationCluster: 10,
+		getDLQAckLevelKey(types.ReplicationTaskTypeHistory, DefaultConsumerGroup): 20,
+	}, nil).Times(1)
+
+	ackLevel, err := s.replicationQueue.GetDLQAckLevel(context.Background(), AllTaskTypes, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(int64(10), ackLevel)
+}
+
+func (s *replicationQueueSuite) TestGetDLQAckLevel_PerTaskType() {
+	s.mockQueue.EXPECT().GetDLQAckLevels(gomock.Any()).Return(map[string]int64{
+		localDomainReplicationCluster: 10,
+		getDLQAckLevelKey(types.ReplicationTaskTypeHistory, DefaultConsumerGroup): 20,
+	}, nil).Times(2)
+
+	ackLevel, err := s.replicationQueue.GetDLQAckLevel(context.Background(), types.ReplicationTaskTypeHistory, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(int64(20), ackLevel)
+
+	ackLevel, err = s.replicationQueue.GetDLQAckLevel(context.Background(), types.ReplicationTaskTypeDomain, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(int64(common.EmptyMessageID), ackLevel)
+}
+
+func (s *replicationQueueSuite) TestGetDLQAckLevel_PerConsumerGroup() {
+	s.mockQueue.EXPECT().GetDLQAckLevels(gomock.Any()).Return(map[string]int64{
+		localDomainReplicationCluster:                     10,
+		getDLQAckLevelKey(AllTaskTypes, "audit-exporter"): 30,
+	}, nil).Times(3)
+
+	ackLevel, err := s.replicationQueue.GetDLQAckLevel(context.Background(), AllTaskTypes, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(int64(10), ackLevel)
+
+	ackLevel, err = s.replicationQueue.GetDLQAckLevel(context.Background(), AllTaskTypes, "audit-exporter")
+	s.NoError(err)
+	s.Equal(int64(30), ackLevel)
+
+	ackLevel, err = s.replicationQueue.GetDLQAckLevel(context.Background(), AllTaskTypes, "another-consumer")
+	s.NoError(err)
+	s.Equal(int64(common.EmptyMessageID), ackLevel)
+}
+
+func (s *replicationQueueSuite) TestGetDLQAckLevelKey() {
+	s.Equal("domainReplication", getDLQAckLevelKey(AllTaskTypes, DefaultConsumerGroup))
+	s.Equal("domainReplication-Domain", getDLQAckLevelKey(types.ReplicationTaskTypeDomain, DefaultConsumerGroup))
+	s.Equal("domainReplication@audit-exporter", getDLQAckLevelKey(AllTaskTypes, "audit-exporter"))
+	s.Equal("domainReplication-Domain@audit-exporter", getDLQAckLevelKey(types.ReplicationTaskTypeDomain, "audit-exporter"))
+}
+
+func (s *replicationQueueSuite) TestUpdateDLQAckLevel_PerTaskType() {
+	s.mockQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), int64(10), getDLQAckLevelKey(types.ReplicationTaskTypeDomain, DefaultConsumerGroup)).Return(nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQMergeToken(gomock.Any(), gomock.Any(), "domainReplication-ack-updated/domainReplication-Domain").Return(nil).Times(1)
+
+	err := s.replicationQueue.UpdateDLQAckLevel(context.Background(), types.ReplicationTaskTypeDomain, DefaultConsumerGroup, 10)
+	s.NoError(err)
+}
+
+func (s *replicationQueueSuite) TestCompareAndSwapDLQAckLevel() {
+	s.mockQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), int64(10), int64(20), getDLQAckLevelKey(types.ReplicationTaskTypeDomain, DefaultConsumerGroup)).Return(nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQMergeToken(gomock.Any(), gomock.Any(), "domainReplication-ack-updated/domainReplication-Domain").Return(nil).Times(1)
+
+	err := s.replicationQueue.CompareAndSwapDLQAckLevel(context.Background(), types.ReplicationTaskTypeDomain, DefaultConsumerGroup, 10, 20)
+	s.NoError(err)
+}
+
+func (s *replicationQueueSuite) TestCompareAndSwapDLQAckLevel_Conflict() {
+	s.mockQueue.EXPECT().CompareAndSwapDLQAckLevel(gomock.Any(), int64(10), int64(20), getDLQAckLevelKey(AllTaskTypes, DefaultConsumerGroup)).
+		Return(&persistence.ConditionFailedError{}).Times(1)
+
+	err := s.replicationQueue.CompareAndSwapDLQAckLevel(context.Background(), AllTaskTypes, DefaultConsumerGroup, 10, 20)
+	s.Equal(ErrDLQAckLevelConflict, err)
+}
+
+func (s *replicationQueueSuite) TestGetDLQAckLevelWithVersion() {
+	s.mockQueue.EXPECT().GetDLQAckLevelsWithVersion(gomock.Any()).
+		Return(map[string]int64{getDLQAckLevelKey(AllTaskTypes, DefaultConsumerGroup): 10}, int64(5), nil).Times(2)
+
+	ackLevel, version, err := s.replicationQueue.GetDLQAckLevelWithVersion(context.Background(), AllTaskTypes, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(int64(10), ackLevel)
+	s.Equal(int64(5), version)
+
+	// an ack level which was never set is empty, at the version of the other ack levels
+	ackLevel, version, err = s.replicationQueue.GetDLQAckLevelWithVersion(context.Background(), AllTaskTypes, "other")
+	s.NoError(err)
+	s.Equal(int64(common.EmptyMessageID), ackLevel)
+	s.Equal(int64(5), version)
+}
+
+func (s *replicationQueueSuite) TestUpdateDLQAckLevelWithVersion() {
+	key := getDLQAckLevelKey(types.ReplicationTaskTypeDomain, DefaultConsumerGroup)
+	s.mockQueue.EXPECT().UpdateDLQAckLevelWithVersion(gomock.Any(), int64(20), key, int64(5)).Return(nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQMergeToken(gomock.Any(), gomock.Any(), getDLQAckLevelUpdatedKey(key)).Return(nil).Times(1)
+	err := s.replicationQueue.UpdateDLQAckLevelWithVersion(context.Background(), types.ReplicationTaskTypeDomain, DefaultConsumerGroup, 20, 5)
+	s.NoError(err)
+
+	s.mockQueue.EXPECT().UpdateDLQAckLevelWithVersion(gomock.Any(), int64(20), key, int64(5)).
+		Return(&persistence.ConditionFailedError{}).Times(1)
+	err = s.replicationQueue.UpdateDLQAckLevelWithVersion(context.Background(), types.ReplicationTaskTypeDomain, DefaultConsumerGroup, 20, 5)
+	s.Equal(ErrVersionConflict, err)
+}
+
+func (s *replicationQueueSuite) TestGetMessagesFromDLQ_FilterByTaskType() {
+	messages := []*persistence.QueueMessage{
+		s.newQueueMessage(1, types.ReplicationTaskTypeDomain),
+		s.newQueueMessage(2, types.ReplicationTaskTypeHistory),
+		s.newQueueMessage(3, types.ReplicationTaskTypeDomain),
+	}
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).Return(messages, nil, nil).Times(2)
+
+	tasks, token, err := s.replicationQueue.GetMessagesFromDLQ(context.Background(), types.ReplicationTaskTypeDomain, 0, 10, 100, nil)
+	s.NoError(err)
+	s.Nil(token)
+	s.Len(tasks, 2)
+	s.Equal(int64(1), tasks[0].SourceTaskID)
+	s.Equal(int64(3), tasks[1].SourceTaskID)
+
+	tasks, _, err = s.replicationQueue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	s.NoError(err)
+	s.Len(tasks, 3)
+}
+
+func (s *replicationQueueSuite) TestGetMessagesFromDLQ_EnqueuedAt() {
+	enqueuedAt := time.Now()
+	message := s.newQueueMessage(1, types.ReplicationTaskTypeDomain)
+	message.EnqueuedAt = enqueuedAt
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).
+		Return([]*persistence.QueueMessage{message}, nil, nil).Times(1)
+
+	tasks, _, err := s.replicationQueue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	s.NoError(err)
+	s.Len(tasks, 1)
+	s.Equal(enqueuedAt, tasks[0].EnqueuedAt)
+}
+
+func (s *replicationQueueSuite) TestRangeDeleteMessagesFromDLQ_AllTaskTypes() {
+	s.mockQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), int64(0), int64(10)).Return(nil).Times(1)
+
+	err := s.replicationQueue.RangeDeleteMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10)
+	s.NoError(err)
+}
+
+func (s *replicationQueueSuite) TestRangeDeleteMessagesFromDLQ_PerTaskType() {
+	messages := []*persistence.QueueMessage{
+		s.newQueueMessage(1, types.ReplicationTaskTypeDomain),
+		s.newQueueMessage(2, types.ReplicationTaskTypeHistory),
+	}
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), dlqRangeDeletePageSize, nil).Return(messages, nil, nil).Times(1)
+	s.mockQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(2)).Return(nil).Times(1)
+	s.mockQueue.EXPECT().RangeDeleteMessagesFromDLQ(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.replicationQueue.RangeDeleteMessagesFromDLQ(context.Background(), types.ReplicationTaskTypeHistory, 0, 10)
+	s.NoError(err)
+}
+
+func (s *replicationQueueSuite) TestRangeSoftDeleteMessagesFromDLQ_AllTaskTypes() {
+	s.mockQueue.EXPECT().RangeSoftDeleteMessagesFromDLQ(gomock.Any(), int64(0), int64(10)).Return(nil).Times(1)
+
+	err := s.replicationQueue.RangeSoftDeleteMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10)
+	s.NoError(err)
+}
+
+func (s *replicationQueueSuite) TestRangeSoftDeleteMessagesFromDLQ_PerTaskType() {
+	messages := []*persistence.QueueMessage{
+		s.newQueueMessage(1, types.ReplicationTaskTypeDomain),
+		s.newQueueMessage(2, types.ReplicationTaskTypeHistory),
+	}
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), dlqRangeDeletePageSize, nil).Return(messages, nil, nil).Times(1)
+	s.mockQueue.EXPECT().RangeSoftDeleteMessagesFromDLQ(gomock.Any(), int64(1), int64(2)).Return(nil).Times(1)
+	s.mockQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	err := s.replicationQueue.RangeSoftDeleteMessagesFromDLQ(context.Background(), types.ReplicationTaskTypeHistory, 0, 10)
+	s.NoError(err)
+}
+
+func (s *replicationQueueSuite) TestDeleteSoftDeletedMessagesFromDLQ() {
+	deletedBefore := time.Now()
+	s.mockQueue.EXPECT().DeleteSoftDeletedMessagesFromDLQ(gomock.Any(), deletedBefore).Return(nil).Times(1)
+
+	err := s.replicationQueue.DeleteSoftDeletedMessagesFromDLQ(context.Background(), deletedBefore)
+	s.NoError(err)
+}
+
+func (s *replicationQueueSuite) TestGetMessagesFromDLQByDomain() {
+	messages := []*persistence.QueueMessage{
+		s.newQueueMessage(1, types.ReplicationTaskTypeDomain),
+		s.newQueueMessage(3, types.ReplicationTaskTypeHistory),
+	}
+	s.mockQueue.EXPECT().ReadMessagesFromDLQByDomain(gomock.Any(), "domainID", int64(0), int64(10), 100, nil).
+		Return(messages, []byte{1}, nil).Times(1)
+
+	tasks, token, err := s.replicationQueue.GetMessagesFromDLQByDomain(context.Background(), "domainID", 0, 10, 100, nil)
+	s.NoError(err)
+	s.Equal([]byte{1}, token)
+	s.Len(tasks, 2)
+	s.Equal(int64(1), tasks[0].SourceTaskID)
+	s.Equal(int64(3), tasks[1].SourceTaskID)
+}
+
+func (s *replicationQueueSuite) TestGetMessagesFromDLQByTimeRange() {
+	startTime := time.Unix(1600000000, 0)
+	endTime := startTime.Add(time.Hour)
+	message := s.newQueueMessage(2, types.ReplicationTaskTypeDomain)
+	message.EnqueuedAt = startTime.Add(time.Minute)
+	s.mockQueue.EXPECT().ReadMessagesFromDLQByTimeRange(gomock.Any(), startTime, endTime, int64(0), int64(10), 100, nil).
+		Return([]*persistence.QueueMessage{message}, nil, nil).Times(1)
+
+	tasks, token, err := s.replicationQueue.GetMessagesFromDLQByTimeRange(context.Background(), startTime, endTime, 0, 10, 100, nil)
+	s.NoError(err)
+	s.Nil(token)
+	s.Len(tasks, 1)
+	s.Equal(int64(2), tasks[0].SourceTaskID)
+	s.Equal(message.EnqueuedAt, tasks[0].EnqueuedAt)
+}
+
+func (s *replicationQueueSuite) TestRangeDeleteMessagesFromDLQByDomain() {
+	s.mockQueue.EXPECT().RangeDeleteMessagesFromDLQByDomain(gomock.Any(), "domainID", int64(0), int64(10)).Return(nil).Times(1)
+
+	err := s.replicationQueue.RangeDeleteMessagesFromDLQByDomain(context.Background(), "domainID", 0, 10)
+	s.NoError(err)
+}
+
+func (s *replicationQueueSuite) TestIncrementDLQMessageAttempts() {
+	message := s.newQueueMessage(5, types.ReplicationTaskTypeDomain)
+	message.Attempts = 2
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(4), int64(5), 1, nil).
+		Return([]*persistence.QueueMessage{message}, nil, nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQMessageAttempts(gomock.Any(), int64(5), 3).Return(nil).Times(1)
+
+	attempts, err := s.replicationQueue.IncrementDLQMessageAttempts(context.Background(), 5)
+	s.NoError(err)
+	s.Equal(3, attempts)
+}
+
+func (s *replicationQueueSuite) TestIncrementDLQMessageAttempts_MessageNotExists() {
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(4), int64(5), 1, nil).Return(nil, nil, nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQMessageAttempts(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := s.replicationQueue.IncrementDLQMessageAttempts(context.Background(), 5)
+	s.IsType(&types.EntityNotExistsError{}, err)
+}
+
+func (s *replicationQueueSuite) TestGetDLQSize_AllTaskTypes() {
+	s.mockQueue.EXPECT().GetDLQSize(gomock.Any()).Return(int64(3), nil).Times(1)
+
+	size, err := s.replicationQueue.GetDLQSize(context.Background(), AllTaskTypes)
+	s.NoError(err)
+	s.Equal(int64(3), size)
+}
+
+func (s *replicationQueueSuite) TestGetDLQSize_PerTaskType() {
+	pageToken := []byte{1}
+	gomock.InOrder(
+		s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(-1), int64(math.MaxInt64), dlqRangeDeletePageSize, nil).
+			Return([]*persistence.QueueMessage{
+				s.newQueueMessage(1, types.ReplicationTaskTypeDomain),
+				s.newQueueMessage(2, types.ReplicationTaskTypeHistory),
+			}, pageToken, nil).Times(1),
+		s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(-1), int64(math.MaxInt64), dlqRangeDeletePageSize, pageToken).
+			Return([]*persistence.QueueMessage{
+				s.newQueueMessage(3, types.ReplicationTaskTypeDomain),
+			}, nil, nil).Times(1),
+	)
+	s.mockQueue.EXPECT().GetDLQSize(gomock.Any()).Times(0)
+
+	size, err := s.replicationQueue.GetDLQSize(context.Background(), types.ReplicationTaskTypeDomain)
+	s.NoError(err)
+	s.Equal(int64(2), size)
+}
+
+func (s *replicationQueueSuite) TestGetDLQMessageTypeHistogram() {
+	queueManager := &messageTypeCountingQueueManager{}
+	replicationQueue := NewReplicationQueue(
+		queueManager,
+		"testCluster",
+		metrics.NewNoopMetricsClient(),
+		loggerimpl.NewLoggerForTest(s.Suite),
+	)
+
+	s.NoError(replicationQueue.EnqueueBatch(context.Background(), []*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 1},
+		{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), SourceTaskID: 2},
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 3},
+	}))
+	s.NoError(replicationQueue.PublishToDLQ(context.Background(), &types.ReplicationTask{
+		TaskType:     types.ReplicationTaskTypeSyncActivity.Ptr(),
+		SourceTaskID: 4,
+	}))
+	s.NoError(replicationQueue.PublishToDLQ(context.Background(), &types.ReplicationTask{
+		TaskType:     types.ReplicationTaskTypeDomain.Ptr(),
+		SourceTaskID: 5,
+	}))
+
+	histogram, err := replicationQueue.GetDLQMessageTypeHistogram(context.Background())
+	s.NoError(err)
+	s.Equal(map[types.ReplicationTaskType]int64{
+		types.ReplicationTaskTypeDomain:       3,
+		types.ReplicationTaskTypeHistoryV2:    1,
+		types.ReplicationTaskTypeSyncActivity: 1,
+	}, histogram)
+}
+
+func (s *replicationQueueSuite) TestGetDLQMessageTypeHistogram_Error() {
+	s.mockQueue.EXPECT().GetDLQMessageTypeHistogram(gomock.Any()).Return(nil, fmt.Errorf("test")).Times(1)
+
+	_, err := s.replicationQueue.GetDLQMessageTypeHistogram(context.Background())
+	s.Error(err)
+}
+
+func (s *replicationQueueSuite) TestDLQReplayAckLevel() {
+	s.mockQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), int64(20), dlqReplayAckLevelKey).Return(nil).Times(1)
+	s.NoError(s.replicationQueue.UpdateDLQReplayAckLevel(context.Background(), 20))
+
+	s.mockQueue.EXPECT().GetDLQAckLevels(gomock.Any()).Return(map[string]int64{
+		localDomainReplicationCluster: 10,
+	}, nil).Times(1)
+	ackLevel, err := s.replicationQueue.GetDLQReplayAckLevel(context.Background())
+	s.NoError(err)
+	s.Equal(int64(common.EmptyMessageID), ackLevel)
+
+	s.mockQueue.EXPECT().GetDLQAckLevels(gomock.Any()).Return(map[string]int64{
+		localDomainReplicationCluster: 10,
+		dlqReplayAckLevelKey:          20,
+	}, nil).Times(1)
+	ackLevel, err = s.replicationQueue.GetDLQReplayAckLevel(context.Background())
+	s.NoError(err)
+	s.Equal(int64(20), ackLevel)
+}
+
+func (s *replicationQueueSuite) TestDLQMergeFence() {
+	fence := &DLQMergeFence{RequestID: "test-request", MessageID: 12}
+	var token string
+	s.mockQueue.EXPECT().UpdateDLQMergeToken(gomock.Any(), gomock.Any(), "domainReplication-Domain").
+		DoAndReturn(func(_ context.Context, t string, _ string) error {
+			token = t
+			return nil
+		}).Times(1)
+	s.NoError(s.replicationQueue.UpdateDLQMergeFence(context.Background(), types.ReplicationTaskTypeDomain, DefaultConsumerGroup, fence))
+
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		Return(map[string]string{"domainReplication-Domain": token}, nil).Times(1)
+	result, err := s.replicationQueue.GetDLQMergeFence(context.Background(), types.ReplicationTaskTypeDomain, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(fence, result)
+}
+
+func (s *replicationQueueSuite) TestDLQExecutionJournal() {
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		Return(map[string]string{"domainReplication": "{}"}, nil).Times(1)
+	journal, err := s.replicationQueue.GetDLQExecutionJournal(context.Background(), DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(&DLQExecutionJournal{}, journal)
+
+	key := dlqExecutionJournalKey + "@group-a"
+	var token string
+	s.mockQueue.EXPECT().UpdateDLQMergeToken(gomock.Any(), gomock.Any(), key).
+		DoAndReturn(func(_ context.Context, t string, _ string) error {
+			token = t
+			return nil
+		}).Times(1)
+	s.NoError(s.replicationQueue.UpdateDLQExecutionJournal(context.Background(), "group-a", &DLQExecutionJournal{
+		Executing: []int64{12},
+		Applied:   []int64{10, 11},
+	}))
+
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		DoAndReturn(func(context.Context) (map[string]string, error) {
+			return map[string]string{key: token}, nil
+		}).Times(2)
+	journal, err = s.replicationQueue.GetDLQExecutionJournal(context.Background(), "group-a")
+	s.NoError(err)
+	s.Equal(&DLQExecutionJournal{Executing: []int64{12}, Applied: []int64{10, 11}}, journal)
+	// the journal of a consumer group is not the journal of the others
+	journal, err = s.replicationQueue.GetDLQExecutionJournal(context.Background(), DefaultConsumerGroup)
+	s.NoError(err)
+	s.Equal(&DLQExecutionJournal{}, journal)
+}
+
+func (s *replicationQueueSuite) TestDLQProcessingPaused() {
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		Return(map[string]string{"domainReplication": "{}"}, nil).Times(1)
+	paused, err := s.replicationQueue.GetDLQProcessingPaused(context.Background(), DefaultConsumerGroup)
+	s.NoError(err)
+	s.False(paused)
+
+	key := dlqProcessingPausedKey + "@group-a"
+	s.mockQueue.EXPECT().UpdateDLQMergeToken(gomock.Any(), "true", key).Return(nil).Times(1)
+	s.NoError(s.replicationQueue.UpdateDLQProcessingPaused(context.Background(), "group-a", true))
+
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		Return(map[string]string{key: "true"}, nil).Times(2)
+	paused, err = s.replicationQueue.GetDLQProcessingPaused(context.Background(), "group-a")
+	s.NoError(err)
+	s.True(paused)
+	// the paused state of a consumer group is not the state of the others
+	paused, err = s.replicationQueue.GetDLQProcessingPaused(context.Background(), DefaultConsumerGroup)
+	s.NoError(err)
+	s.False(paused)
+
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		Return(map[string]string{dlqProcessingPausedKey: "invalid"}, nil).Times(1)
+	_, err = s.replicationQueue.GetDLQProcessingPaused(context.Background(), DefaultConsumerGroup)
+	s.Error(err)
+}
+
+func (s *replicationQueueSuite) TestDLQConflictResolutionPolicies() {
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		Return(map[string]string{"domainReplication": "{}"}, nil).Times(1)
+	policies, err := s.replicationQueue.GetDLQConflictResolutionPolicies(context.Background())
+	s.NoError(err)
+	s.Empty(policies)
+
+	// each domain's policy is updated without reading the policies of the other domains
+	tokens := map[string]string{getDLQConflictPolicyKey("domain-a"): string(ConflictResolutionPolicyKeepLocal)}
+	s.mockQueue.EXPECT().UpdateDLQMergeToken(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, token string, key string) error {
+			tokens[key] = token
+			return nil
+		}).Times(2)
+	s.NoError(s.replicationQueue.UpdateDLQConflictResolutionPolicy(context.Background(), "domain-b", ConflictResolutionPolicyOverwriteWithSource))
+	// the default policy is not stored
+	s.NoError(s.replicationQueue.UpdateDLQConflictResolutionPolicy(context.Background(), "domain-a", ConflictResolutionPolicyErrorOnConflict))
+	s.Equal("", tokens[getDLQConflictPolicyKey("domain-a")])
+
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).Return(tokens, nil).Times(1)
+	policies, err = s.replicationQueue.GetDLQConflictResolutionPolicies(context.Background())
+	s.NoError(err)
+	s.Equal(map[string]ConflictResolutionPolicy{"domain-b": ConflictResolutionPolicyOverwriteWithSource}, policies)
+
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		Return(map[string]string{getDLQConflictPolicyKey("domain-a"): "invalid"}, nil).Times(1)
+	_, err = s.replicationQueue.GetDLQConflictResolutionPolicies(context.Background())
+	s.Error(err)
+}
+
+func (s *replicationQueueSuite) TestGetDLQMergeFence_NotExists() {
+	s.mockQueue.EXPECT().GetDLQMergeTokens(gomock.Any()).
+		Return(map[string]string{"domainReplication": "{}"}, nil).Times(1)
+
+	result, err := s.replicationQueue.GetDLQMergeFence(context.Background(), types.ReplicationTaskTypeDomain, DefaultConsumerGroup)
+	s.NoError(err)
+	s.Nil(result)
+}
+
+func (s *replicationQueueSuite) TestDLQMergeHistory() {
+	record := DLQMergeRecord{
+		MergedAt:       time.Now(),
+		StartMessageID: 11,
+		EndMessageID:   20,
+		MergedCount:    9,
+		FailedCount:    1,
+		Duration:       time.Second,
+		TriggeredBy:    "cadence-cli",
+		TraceID:        "trace-id",
+	}
+	persisted := &persistence.DLQMergeRecord{
+		MergedAt:       record.MergedAt,
+		StartMessageID: record.StartMessageID,
+		EndMessageID:   record.EndMessageID,
+		MergedCount:    record.MergedCount,
+		FailedCount:    record.FailedCount,
+		Duration:       record.Duration,
+		TriggeredBy:    record.TriggeredBy,
+		TraceID:        record.TraceID,
+	}
+	s.mockQueue.EXPECT().InsertDLQMergeRecord(gomock.Any(), persisted).Return(nil).Times(1)
+	s.NoError(s.replicationQueue.RecordDLQMerge(context.Background(), record))
+
+	s.mockQueue.EXPECT().GetDLQMergeHistory(gomock.Any(), 10).Return([]*persistence.DLQMergeRecord{persisted}, nil).Times(1)
+	history, err := s.replicationQueue.GetDLQMergeHistory(context.Background(), 10)
+	s.NoError(err)
+	s.Equal([]DLQMergeRecord{record}, history)
+}
+
+func (s *replicationQueueSuite) TestSnapshotAndRestoreDLQAckLevel() {
+	mergedAt := time.Now().Add(-time.Minute)
+	history := []*persistence.DLQMergeRecord{
+		{MergedAt: mergedAt, StartMessageID: 21, EndMessageID: 30},
+		{MergedAt: mergedAt.Add(-time.Minute), StartMessageID: 11, EndMessageID: 20},
+	}
+	ackLevels := map[string]int64{
+		"domainReplication":         30,
+		"domainReplication@replica": 20,
+	}
+	s.mockQueue.EXPECT().GetDLQAckLevels(gomock.Any()).Return(ackLevels, nil).Times(1)
+	s.mockQueue.EXPECT().GetDLQMergeHistory(gomock.Any(), dlqSnapshotMergedMessageCount).Return(history, nil).Times(1)
+
+	snapshot, err := s.replicationQueue.SnapshotDLQAckLevel(context.Background())
+	s.NoError(err)
+	s.Equal(ackLevels, snapshot.AckLevels)
+	s.NotEmpty(snapshot.MergedMessageIDsHash)
+
+	// a merge done after the snapshot does not invalidate it
+	later := append([]*persistence.DLQMergeRecord{
+		{MergedAt: snapshot.CreatedAt.Add(time.Minute), StartMessageID: 31, EndMessageID: 40},
+	}, history...)
+	s.mockQueue.EXPECT().GetDLQMergeHistory(gomock.Any(), dlqSnapshotMergeHistoryLimit).Return(later, nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), int64(30), "domainReplication").Return(nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), int64(20), "domainReplication@replica").Return(nil).Times(1)
+	s.NoError(s.replicationQueue.RestoreDLQAckLevel(context.Background(), snapshot))
+}
+
+func (s *replicationQueueSuite) TestRestoreDLQAckLevel_HashMismatch() {
+	snapshot := &DLQAckLevelSnapshot{
+		CreatedAt:            time.Now(),
+		AckLevels:            map[string]int64{"domainReplication": 30},
+		MergedMessageIDsHash: hashMergedMessageIDs([]DLQMergeRecord{{StartMessageID: 11, EndMessageID: 20}}),
+	}
+	s.mockQueue.EXPECT().GetDLQMergeHistory(gomock.Any(), dlqSnapshotMergeHistoryLimit).Return([]*persistence.DLQMergeRecord{
+		{MergedAt: time.Now().Add(-time.Minute), StartMessageID: 11, EndMessageID: 25},
+	}, nil).Times(1)
+	s.mockQueue.EXPECT().UpdateDLQAckLevel(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	s.Equal(ErrDLQAckLevelSnapshotMismatch, s.replicationQueue.RestoreDLQAckLevel(context.Background(), snapshot))
+}
+
+func (s *replicationQueueSuite) TestRestoreDLQAckLevel_Nil() {
+	s.Error(s.replicationQueue.RestoreDLQAckLevel(context.Background(), nil))
+}
+
+func (s *replicationQueueSuite) TestNackMessage() {
+	message := s.newQueueMessage(12, types.ReplicationTaskTypeDomain)
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(11), int64(12), 1, nil).
+		Return([]*persistence.QueueMessage{message}, nil, nil).Times(1)
+	before := time.Now()
+	gomock.InOrder(
+		s.mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "", gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, _ *int32, payload []byte) error {
+				task, err := DecodeReplicationTask(payload)
+				s.NoError(err)
+				s.Equal(types.ReplicationTaskTypeDomain, task.GetTaskType())
+				s.False(task.RetryAfter.Before(before.Add(time.Minute)))
+				return nil
+			},
+		).Times(1),
+		s.mockQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), int64(12)).Return(nil).Times(1),
+	)
+
+	s.NoError(s.replicationQueue.NackMessage(context.Background(), 12, time.Minute))
+}
+
+func (s *replicationQueueSuite) TestNackMessage_MessageNotExists() {
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(11), int64(12), 1, nil).Return(nil, nil, nil).Times(1)
+
+	err := s.replicationQueue.NackMessage(context.Background(), 12, time.Minute)
+	s.IsType(&types.EntityNotExistsError{}, err)
+}
+
+func (s *replicationQueueSuite) TestNackMessage_EnqueueFailed() {
+	message := s.newQueueMessage(12, types.ReplicationTaskTypeDomain)
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(11), int64(12), 1, nil).
+		Return([]*persistence.QueueMessage{message}, nil, nil).Times(1)
+	s.mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "", gomock.Any(), gomock.Any()).Return(fmt.Errorf("test")).Times(1)
+	// the message is kept if it could not be moved to the retry queue
+	s.mockQueue.EXPECT().DeleteMessageFromDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	s.Error(s.replicationQueue.NackMessage(context.Background(), 12, time.Minute))
+}
+
+func (s *replicationQueueSuite) TestPublishToDLQ() {
+	task := &types.ReplicationTask{
+		TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+		SourceCluster:        "cluster-b",
+		DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID"},
+	}
+	s.mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "domainID", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ *int32, payload []byte) error {
+			var envelope dlqEnvelope
+			s.NoError(json.Unmarshal(payload, &envelope))
+			s.Equal(DLQSchemaVersionCurrent, envelope.SchemaVersion)
+			decoded, err := DecodeReplicationTask(payload)
+			s.NoError(err)
+			// domain replication tasks are enqueued with a high priority
+			expected := *task
+			expected.Priority = DLQMessagePriorityHigh
+			s.Equal(&expected, decoded)
+			return nil
+		},
+	).Times(1)
+
+	err := s.replicationQueue.PublishToDLQ(context.Background(), task)
+	s.NoError(err)
+	s.Equal(DLQMessagePriorityDefault, task.Priority)
+}
+
+func (s *replicationQueueSuite) TestPublishToDLQ_Priority() {
+	tests := []struct {
+		task     *types.ReplicationTask
+		priority int
+	}{
+		{&types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr()}, DLQMessagePriorityHigh},
+		{&types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr()}, DLQMessagePriorityDefault},
+		{&types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(), Priority: 2}, 2},
+	}
+	for _, tt := range tests {
+		s.mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), "", gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, _ *int32, payload []byte) error {
+				decoded, err := DecodeReplicationTask(payload)
+				s.NoError(err)
+				s.Equal(tt.priority, decoded.Priority)
+				return nil
+			},
+		).Times(1)
+		s.NoError(s.replicationQueue.PublishToDLQ(context.Background(), tt.task))
+	}
+}
+
+func (s *replicationQueueSuite) TestPublishToDLQ_CorrelationID() {
+	tests := []struct {
+		task          *types.ReplicationTask
+		correlationID string
+	}{
+		{&types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr()}, ""},
+		{&types.ReplicationTask{
+			TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+			HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{RunID: "run-id"},
+		}, "run-id"},
+		{&types.ReplicationTask{
+			TaskType:                   types.ReplicationTaskTypeSyncActivity.Ptr(),
+			SyncActivityTaskAttributes: &types.SyncActivityTaskAttributes{RunID: "run-id"},
+		}, "run-id"},
+		{&types.ReplicationTask{
+			TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+			HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{RunID: "run-id"},
+			CorrelationID:           "correlation-id",
+		}, "correlation-id"},
+	}
+	for _, tt := range tests {
+		s.mockQueue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, _ *int32, payload []byte) error {
+				decoded, err := DecodeReplicationTask(payload)
+				s.NoError(err)
+				s.Equal(tt.correlationID, decoded.CorrelationID)
+				return nil
+			},
+		).Times(1)
+		s.NoError(s.replicationQueue.PublishToDLQ(context.Background(), tt.task))
+	}
+}
+
+func (s *replicationQueueSuite) TestGetMessagesFromDLQGrouped() {
+	newMessage := func(id int64, correlationID string) *persistence.QueueMessage {
+		payload, err := EncodeReplicationTask(&types.ReplicationTask{
+			TaskType:      types.ReplicationTaskTypeHistoryV2.Ptr(),
+			CorrelationID: correlationID,
+		})
+		s.NoError(err)
+		return &persistence.QueueMessage{ID: id, Payload: payload}
+	}
+	messages := []*persistence.QueueMessage{
+		newMessage(3, "run-a"),
+		newMessage(1, "run-a"),
+		newMessage(2, "run-b"),
+		newMessage(4, ""),
+	}
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).Return(messages, []byte("token"), nil).Times(1)
+
+	groups, token, err := s.replicationQueue.GetMessagesFromDLQGrouped(context.Background(), 0, 10, 100, nil)
+	s.NoError(err)
+	s.Equal([]byte("token"), token)
+	s.Len(groups, 3)
+	s.Equal([]int64{1, 3}, dlqMessageIDs(groups["run-a"]))
+	s.Equal([]int64{2}, dlqMessageIDs(groups["run-b"]))
+	// a message without a correlation ID is a group of its own
+	s.Equal([]int64{4}, dlqMessageIDs(groups["4"]))
+}
+
+func (s *replicationQueueSuite) TestEnqueueBatch() {
+	tasks := []*types.ReplicationTask{
+		{
+			TaskType:             types.ReplicationTaskTypeDomain.Ptr(),
+			SourceTaskID:         1,
+			DomainTaskAttributes: &types.DomainTaskAttributes{ID: "domainID1"},
+		},
+		{
+			TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+			SourceTaskID:            2,
+			HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{DomainID: "domainID2"},
+		},
+	}
+	s.mockQueue.EXPECT().EnqueueMessagesToDLQ(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, messages []*persistence.QueueMessage) error {
+			s.Len(messages, len(tasks))
+			s.Equal("domainID1", messages[0].DomainID)
+			s.Equal("domainID2", messages[1].DomainID)
+			for i, message := range messages {
+				decoded, err := DecodeReplicationTask(message.Payload)
+				s.NoError(err)
+				s.Equal(tasks[i].SourceTaskID, decoded.SourceTaskID)
+				s.Equal(getDLQMessagePriority(tasks[i]), decoded.Priority)
+				s.Equal(int32(tasks[i].GetTaskType()), *message.TaskType)
+			}
+			return nil
+		},
+	).Times(1)
+
+	s.NoError(s.replicationQueue.EnqueueBatch(context.Background(), tasks))
+}
+
+func (s *replicationQueueSuite) TestEnqueueBatch_Empty() {
+	s.mockQueue.EXPECT().EnqueueMessagesToDLQ(gomock.Any(), gomock.Any()).Times(0)
+
+	s.NoError(s.replicationQueue.EnqueueBatch(context.Background(), nil))
+}
+
+func (s *replicationQueueSuite) TestGetMessagesFromDLQ_SchemaVersions() {
+	payload, err := EncodeReplicationTask(&types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistory.Ptr()})
+	s.NoError(err)
+	messages := []*persistence.QueueMessage{
+		s.newQueueMessage(1, types.ReplicationTaskTypeDomain),
+		{ID: 2, Payload: payload},
+	}
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).Return(messages, nil, nil).Times(1)
+
+	tasks, _, err := s.replicationQueue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	s.NoError(err)
+	s.Equal([]*types.ReplicationTask{
+		{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: 1, SchemaVersion: DLQSchemaVersionLegacy, PayloadSize: len(messages[0].Payload)},
+		{TaskType: types.ReplicationTaskTypeHistory.Ptr(), SourceTaskID: 2, SchemaVersion: DLQSchemaVersion1, PayloadSize: len(payload)},
+	}, tasks)
+}
+
+func (s *replicationQueueSuite) TestGetMessagesFromDLQ_UnknownSchemaVersion() {
+	messages := []*persistence.QueueMessage{
+		{ID: 1, Payload: []byte(`{"schemaVersion":42,"payload":""}`)},
+	}
+	s.mockQueue.EXPECT().ReadMessagesFromDLQ(gomock.Any(), int64(0), int64(10), 100, nil).Return(messages, nil, nil).Times(1)
+
+	_, _, err := s.replicationQueue.GetMessagesFromDLQ(context.Background(), AllTaskTypes, 0, 10, 100, nil)
+	var unknownVersionErr *ErrUnknownSchemaVersion
+	s.True(errors.As(err, &unknownVersionErr))
+	s.Equal(42, unknownVersionErr.SchemaVersion)
+}
+
+func (s *replicationQueueSuite) newQueueMessage(
+	id int64,
+	taskType types.ReplicationTaskType,
+) *persistence.QueueMessage {
+	payload, err := codec.NewThriftRWEncoder().Encode(thrift.FromReplicationTask(&types.ReplicationTask{
+		TaskType: taskType.Ptr(),
+	}))
+	s.NoError(err)
+	return &persistence.QueueMessage{
+		ID:      id,
+		Payload: payload,
+	}
+}
+
+// roundTripQueueManager simulates the latency of a persistence round trip on enqueuing to the DLQ
+type roundTripQueueManager struct {
+	persistence.QueueManager
+	roundTrip time.Duration
+}
+
+func (q *roundTripQueueManager) EnqueueMessageToDLQ(context.Context, string, *int32, []byte) error {
+	time.Sleep(q.roundTrip)
+	return nil
+}
+
+func (q *roundTripQueueManager) EnqueueMessagesToDLQ(context.Context, []*persistence.QueueMessage) error {
+	time.Sleep(q.roundTrip)
+	return nil
+}
+
+// messageTypeCountingQueueManager aggregates the task types persisted with the enqueued DLQ messages,
+// like the aggregation query of the persistence does
+type messageTypeCountingQueueManager struct {
+	persistence.QueueManager
+	counts map[int32]int64
+}
+
+func (q *messageTypeCountingQueueManager) EnqueueMessageToDLQ(_ context.Context, _ string, taskType *int32, _ []byte) error {
+	q.count(taskType)
+	return nil
+}
+
+func (q *messageTypeCountingQueueManager) EnqueueMessagesToDLQ(_ context.Context, messages []*persistence.QueueMessage) error {
+	for _, message := range messages {
+		q.count(message.TaskType)
+	}
+	return nil
+}
+
+func (q *messageTypeCountingQueueManager) GetDLQMessageTypeHistogram(context.Context) (map[int32]int64, error) {
+	return q.counts, nil
+}
+
+func (q *messageTypeCountingQueueManager) count(taskType *int32) {
+	if taskType == nil {
+		return
+	}
+	if q.counts == nil {
+		q.counts = make(map[int32]int64)
+	}
+	q.counts[*taskType]++
+}
+
+// BenchmarkEnqueueToDLQ compares enqueuing tasks one by one against a single batch
+func BenchmarkEnqueueToDLQ(b *testing.B) {
+	queue := NewReplicationQueue(
+		&roundTripQueueManager{roundTrip: time.Millisecond},
+		"testCluster",
+		metrics.NewNoopMetricsClient(),
+		loggerimpl.NewNopLogger(),
+	)
+	for _, size := range []int{10, 100, 1000} {
+		var tasks []*types.ReplicationTask
+		for i := 0; i < size; i++ {
+			tasks = append(tasks, &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr(), SourceTaskID: int64(i)})
+		}
+
+		b.Run(fmt.Sprintf("single/size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, task := range tasks {
+					if err := queue.PublishToDLQ(context.Background(), task); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("batch/size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := queue.EnqueueBatch(context.Background(), tasks); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination replication_task_executor_registry_mock.go
+
+package domain
+
+import (
+	"sync"
+
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// ReplicationTaskExecutorRegistry maps replication task types to the executors applying them,
+	// so operators can plug in the executors of their own replication task types
+	ReplicationTaskExecutorRegistry interface {
+		// RegisterExecutor registers the executor of the task type, replacing the executor registered before
+		RegisterExecutor(taskType types.ReplicationTaskType, executor ReplicationTaskExecutor)
+		// UnregisterExecutor removes the executor of the task type, the tasks of the type can no longer be executed
+		UnregisterExecutor(taskType types.ReplicationTaskType)
+		// GetExecutor returns the executor of the task type and false if no executor is registered for it
+		GetExecutor(taskType types.ReplicationTaskType) (ReplicationTaskExecutor, bool)
+	}
+
+	replicationTaskExecutorRegistryImpl struct {
+		sync.RWMutex
+		executors map[types.ReplicationTaskType]ReplicationTaskExecutor
+	}
+)
+
+// defaultReplicationTaskTypes are the replication task types the domain replication task executor applies
+var defaultReplicationTaskTypes = []types.ReplicationTaskType{
+	types.ReplicationTaskTypeDomain,
+	types.ReplicationTaskTypeHistoryV2,
+	types.ReplicationTaskTypeSyncActivity,
+}
+
+var _ ReplicationTaskExecutorRegistry = (*replicationTaskExecutorRegistryImpl)(nil)
+
+// NewReplicationTaskExecutorRegistry returns a registry with the domain replication task executor
+// registered for the task types it supports, defaultExecutor can be nil for an empty registry
+func NewReplicationTaskExecutorRegistry(
+	defaultExecutor ReplicationTaskExecutor,
+) ReplicationTaskExecutorRegistry {
+
+	registry := &replicationTaskExecutorRegistryImpl{
+		executors: make(map[types.ReplicationTaskType]ReplicationTaskExecutor),
+	}
+	if defaultExecutor != nil {
+		for _, taskType := range defaultReplicationTaskTypes {
+			registry.executors[taskType] = defaultExecutor
+		}
+	}
+	return registry
+}
+
+func (r *replicationTaskExecutorRegistryImpl) RegisterExecutor(
+	taskType types.ReplicationTaskType,
+	executor ReplicationTaskExecutor,
+) {
+
+	r.Lock()
+	defer r.Unlock()
+	r.executors[taskType] = executor
+}
+
+func (r *replicationTaskExecutorRegistryImpl) UnregisterExecutor(
+	taskType types.ReplicationTaskType,
+) {
+
+	r.Lock()
+	defer r.Unlock()
+	delete(r.executors, taskType)
+}
+
+func (r *replicationTaskExecutorRegistryImpl) GetExecutor(
+	taskType types.ReplicationTaskType,
+) (ReplicationTaskExecutor, bool) {
+
+	r.RLock()
+	defer r.RUnlock()
+	executor, ok := r.executors[taskType]
+	return executor, ok
+}
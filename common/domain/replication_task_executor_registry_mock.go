@@ -0,0 +1,97 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: replication_task_executor_registry.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	types "github.com/uber/cadence/common/types"
+)
+
+// MockReplicationTaskExecutorRegistry is a mock of ReplicationTaskExecutorRegistry interface.
+type MockReplicationTaskExecutorRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplicationTaskExecutorRegistryMockRecorder
+}
+
+// MockReplicationTaskExecutorRegistryMockRecorder is the mock recorder for MockReplicationTaskExecutorRegistry.
+type MockReplicationTaskExecutorRegistryMockRecorder struct {
+	mock *MockReplicationTaskExecutorRegistry
+}
+
+// NewMockReplicationTaskExecutorRegistry creates a new mock instance.
+func NewMockReplicationTaskExecutorRegistry(ctrl *gomock.Controller) *MockReplicationTaskExecutorRegistry {
+	mock := &MockReplicationTaskExecutorRegistry{ctrl: ctrl}
+	mock.recorder = &MockReplicationTaskExecutorRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplicationTaskExecutorRegistry) EXPECT() *MockReplicationTaskExecutorRegistryMockRecorder {
+	return m.recorder
+}
+
+// GetExecutor mocks base method.
+func (m *MockReplicationTaskExecutorRegistry) GetExecutor(taskType types.ReplicationTaskType) (ReplicationTaskExecutor, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExecutor", taskType)
+	ret0, _ := ret[0].(ReplicationTaskExecutor)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetExecutor indicates an expected call of GetExecutor.
+func (mr *MockReplicationTaskExecutorRegistryMockRecorder) GetExecutor(taskType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutor", reflect.TypeOf((*MockReplicationTaskExecutorRegistry)(nil).GetExecutor), taskType)
+}
+
+// RegisterExecutor mocks base method.
+func (m *MockReplicationTaskExecutorRegistry) RegisterExecutor(taskType types.ReplicationTaskType, executor ReplicationTaskExecutor) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterExecutor", taskType, executor)
+}
+
+// RegisterExecutor indicates an expected call of RegisterExecutor.
+func (mr *MockReplicationTaskExecutorRegistryMockRecorder) RegisterExecutor(taskType, executor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterExecutor", reflect.TypeOf((*MockReplicationTaskExecutorRegistry)(nil).RegisterExecutor), taskType, executor)
+}
+
+// UnregisterExecutor mocks base method.
+func (m *MockReplicationTaskExecutorRegistry) UnregisterExecutor(taskType types.ReplicationTaskType) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UnregisterExecutor", taskType)
+}
+
+// UnregisterExecutor indicates an expected call of UnregisterExecutor.
+func (mr *MockReplicationTaskExecutorRegistryMockRecorder) UnregisterExecutor(taskType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnregisterExecutor", reflect.TypeOf((*MockReplicationTaskExecutorRegistry)(nil).UnregisterExecutor), taskType)
+}
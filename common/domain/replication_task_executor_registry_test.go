@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestReplicationTaskExecutorRegistry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defaultExecutor := NewMockReplicationTaskExecutor(controller)
+	customExecutor := NewMockReplicationTaskExecutor(controller)
+	customTaskType := types.ReplicationTaskType(100)
+	registry := NewReplicationTaskExecutorRegistry(defaultExecutor)
+
+	for _, taskType := range []types.ReplicationTaskType{
+		types.ReplicationTaskTypeDomain,
+		types.ReplicationTaskTypeHistoryV2,
+		types.ReplicationTaskTypeSyncActivity,
+	} {
+		executor, ok := registry.GetExecutor(taskType)
+		assert.True(t, ok)
+		assert.Equal(t, defaultExecutor, executor)
+	}
+	_, ok := registry.GetExecutor(customTaskType)
+	assert.False(t, ok)
+
+	registry.RegisterExecutor(customTaskType, customExecutor)
+	executor, ok := registry.GetExecutor(customTaskType)
+	assert.True(t, ok)
+	assert.Equal(t, customExecutor, executor)
+
+	registry.RegisterExecutor(types.ReplicationTaskTypeDomain, customExecutor)
+	executor, ok = registry.GetExecutor(types.ReplicationTaskTypeDomain)
+	assert.True(t, ok)
+	assert.Equal(t, customExecutor, executor)
+
+	registry.UnregisterExecutor(customTaskType)
+	_, ok = registry.GetExecutor(customTaskType)
+	assert.False(t, ok)
+}
+
+func TestReplicationTaskExecutorRegistry_Empty(t *testing.T) {
+	registry := NewReplicationTaskExecutorRegistry(nil)
+
+	_, ok := registry.GetExecutor(types.ReplicationTaskTypeDomain)
+	assert.False(t, ok)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/uber/cadence/common/types"
+)
+
+// Domain replication task migrations upgrade the domain task attributes of tasks persisted in the domain DLQ
+// by an older Cadence version before they are executed.
+//
+// Migrations are numbered by the DLQ schema version the task was persisted with, see DLQSchemaVersionLegacy,
+// DLQSchemaVersion1 and so on. Versions are consecutive integers starting at 0 and a new version is introduced
+// whenever the format of persisted domain replication tasks changes. A migration registered from fromVersion
+// to toVersion converts the attributes of a task persisted with fromVersion into the attributes toVersion
+// would have persisted. A task is migrated by the migration registered from its version, then by the one
+// registered from the version it was migrated to, and so on until no migration is registered from the version
+// reached. Tasks replicated directly from a remote cluster are of the current version and are never migrated.
+
+type (
+	// MigrationFunc converts domain task attributes persisted with one DLQ schema version to the format of another
+	MigrationFunc func(*types.DomainTaskAttributes) (*types.DomainTaskAttributes, error)
+
+	// replicationTaskMigrations is the chain of migrations applied to domain replication tasks before execution
+	replicationTaskMigrations struct {
+		sync.RWMutex
+		migrations map[int]replicationTaskMigration
+	}
+
+	replicationTaskMigration struct {
+		toVersion int
+		migrate   MigrationFunc
+	}
+)
+
+// newReplicationTaskMigrations returns an empty chain, which executes tasks of every version as they are
+func newReplicationTaskMigrations() *replicationTaskMigrations {
+	return &replicationTaskMigrations{
+		migrations: make(map[int]replicationTaskMigration),
+	}
+}
+
+// add registers the migration from fromVersion to toVersion, toVersion must be greater than fromVersion
+// so the chain always terminates and only one migration can be registered from a version
+func (m *replicationTaskMigrations) add(fromVersion, toVersion int, fn MigrationFunc) error {
+	if fn == nil {
+		return &types.BadRequestError{Message: "domain replication task migration cannot be nil"}
+	}
+	if fromVersion < DLQSchemaVersionLegacy || toVersion <= fromVersion {
+		return &types.BadRequestError{Message: fmt.Sprintf(
+			"invalid domain replication task migration from version %v to version %v", fromVersion, toVersion)}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.migrations[fromVersion]; ok {
+		return &types.BadRequestError{Message: fmt.Sprintf(
+			"domain replication task migration from version %v is already registered", fromVersion)}
+	}
+	m.migrations[fromVersion] = replicationTaskMigration{
+		toVersion: toVersion,
+		migrate:   fn,
+	}
+	return nil
+}
+
+// migrate applies the chain of migrations starting at the version the task was persisted with
+func (m *replicationTaskMigrations) migrate(
+	task *types.DomainTaskAttributes,
+	version int,
+) (*types.DomainTaskAttributes, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+	for {
+		migration, ok := m.migrations[version]
+		if !ok {
+			return task, nil
+		}
+		migrated, err := migration.migrate(task)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate domain replication task from version %v to version %v: %w",
+				version, migration.toVersion, err)
+		}
+		task = migrated
+		version = migration.toVersion
+	}
+}
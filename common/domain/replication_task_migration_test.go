@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestReplicationTaskMigrations_NoMigration(t *testing.T) {
+	migrations := newReplicationTaskMigrations()
+	task := &types.DomainTaskAttributes{ID: "domain-id"}
+
+	for _, version := range []int{DLQSchemaVersionLegacy, DLQSchemaVersion1} {
+		migrated, err := migrations.migrate(task, version)
+		require.NoError(t, err)
+		assert.Equal(t, task, migrated)
+	}
+}
+
+func TestReplicationTaskMigrations_Chain(t *testing.T) {
+	migrations := newReplicationTaskMigrations()
+	var applied []string
+	require.NoError(t, migrations.add(0, 1, func(task *types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		applied = append(applied, "0->1")
+		return &types.DomainTaskAttributes{ID: task.ID, Info: &types.DomainInfo{Name: "migrated"}}, nil
+	}))
+	require.NoError(t, migrations.add(1, 3, func(task *types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		applied = append(applied, "1->3")
+		task.FailoverVersion = 3
+		return task, nil
+	}))
+
+	migrated, err := migrations.migrate(&types.DomainTaskAttributes{ID: "domain-id"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0->1", "1->3"}, applied)
+	assert.Equal(t, "domain-id", migrated.ID)
+	assert.Equal(t, "migrated", migrated.Info.GetName())
+	assert.Equal(t, int64(3), migrated.FailoverVersion)
+
+	// the chain starts at the version of the task
+	applied = nil
+	migrated, err = migrations.migrate(&types.DomainTaskAttributes{ID: "domain-id"}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1->3"}, applied)
+	assert.Nil(t, migrated.Info)
+}
+
+func TestReplicationTaskMigrations_Error(t *testing.T) {
+	migrations := newReplicationTaskMigrations()
+	migrationErr := errors.New("migration error")
+	require.NoError(t, migrations.add(0, 1, func(*types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		return nil, migrationErr
+	}))
+
+	_, err := migrations.migrate(&types.DomainTaskAttributes{}, 0)
+	assert.True(t, errors.Is(err, migrationErr))
+}
+
+func TestReplicationTaskMigrations_InvalidMigration(t *testing.T) {
+	noop := func(task *types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		return task, nil
+	}
+	migrations := newReplicationTaskMigrations()
+	require.NoError(t, migrations.add(0, 1, noop))
+
+	tests := []struct {
+		name        string
+		fromVersion int
+		toVersion   int
+		fn          MigrationFunc
+	}{
+		{name: "nil migration", fromVersion: 1, toVersion: 2, fn: nil},
+		{name: "negative version", fromVersion: -1, toVersion: 0, fn: noop},
+		{name: "same version", fromVersion: 1, toVersion: 1, fn: noop},
+		{name: "downgrade", fromVersion: 2, toVersion: 1, fn: noop},
+		{name: "already registered", fromVersion: 0, toVersion: 2, fn: noop},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migrations.add(tt.fromVersion, tt.toVersion, tt.fn)
+			assert.IsType(t, &types.BadRequestError{}, err)
+		})
+	}
+}
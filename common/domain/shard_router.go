@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination shard_router_mock.go
+
+package domain
+
+import (
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+)
+
+type (
+	// ShardRouter routes the workflow replication tasks of the domain DLQ to the history shards of their workflows
+	ShardRouter interface {
+		// RouteToShard returns the history shard of the workflow,
+		// it returns an error if no history host owns the shard in the membership ring
+		RouteToShard(workflowID string) (int, error)
+	}
+
+	membershipShardRouter struct {
+		numberOfShards int
+		resolver       membership.Resolver
+	}
+)
+
+var _ ShardRouter = (*membershipShardRouter)(nil)
+
+// NewShardRouter creates a ShardRouter which maps the workflows to the history shards the same way the history
+// client does, and looks the owners of the shards up in the history membership ring
+func NewShardRouter(numberOfShards int, resolver membership.Resolver) ShardRouter {
+	return &membershipShardRouter{
+		numberOfShards: numberOfShards,
+		resolver:       resolver,
+	}
+}
+
+func (r *membershipShardRouter) RouteToShard(workflowID string) (int, error) {
+	shardID := common.WorkflowIDToHistoryShard(workflowID, r.numberOfShards)
+	if _, err := r.resolver.Lookup(service.History, string(rune(shardID))); err != nil {
+		return 0, err
+	}
+	return shardID, nil
+}
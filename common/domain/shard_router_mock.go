@@ -0,0 +1,71 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: shard_router.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockShardRouter is a mock of ShardRouter interface.
+type MockShardRouter struct {
+	ctrl     *gomock.Controller
+	recorder *MockShardRouterMockRecorder
+}
+
+// MockShardRouterMockRecorder is the mock recorder for MockShardRouter.
+type MockShardRouterMockRecorder struct {
+	mock *MockShardRouter
+}
+
+// NewMockShardRouter creates a new mock instance.
+func NewMockShardRouter(ctrl *gomock.Controller) *MockShardRouter {
+	mock := &MockShardRouter{ctrl: ctrl}
+	mock.recorder = &MockShardRouterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShardRouter) EXPECT() *MockShardRouterMockRecorder {
+	return m.recorder
+}
+
+// RouteToShard mocks base method.
+func (m *MockShardRouter) RouteToShard(workflowID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RouteToShard", workflowID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RouteToShard indicates an expected call of RouteToShard.
+func (mr *MockShardRouterMockRecorder) RouteToShard(workflowID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RouteToShard", reflect.TypeOf((*MockShardRouter)(nil).RouteToShard), workflowID)
+}
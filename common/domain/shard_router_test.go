@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+)
+
+func TestShardRouter_RouteToShard(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	numberOfShards := 16
+	shardID := common.WorkflowIDToHistoryShard("workflow-id", numberOfShards)
+	mockResolver := membership.NewMockResolver(controller)
+	router := NewShardRouter(numberOfShards, mockResolver)
+
+	mockResolver.EXPECT().Lookup(service.History, string(rune(shardID))).Return(membership.NewHostInfo("history"), nil).Times(1)
+	routed, err := router.RouteToShard("workflow-id")
+	assert.NoError(t, err)
+	assert.Equal(t, shardID, routed)
+
+	// no history host owns the shard while the ring is empty
+	lookupErr := membership.ErrInsufficientHosts
+	mockResolver.EXPECT().Lookup(service.History, string(rune(shardID))).Return(membership.HostInfo{}, lookupErr).Times(1)
+	_, err = router.RouteToShard("workflow-id")
+	assert.Equal(t, lookupErr, err)
+}
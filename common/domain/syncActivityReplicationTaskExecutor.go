@@ -0,0 +1,135 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination syncActivityReplicationTaskExecutor_mock.go -self_package github.com/uber/cadence/common/domain -aux_files github.com/uber/cadence/common/domain=replicationTaskExecutor.go
+
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/types"
+)
+
+var (
+	// ErrWorkflowNotFound is the error to indicate the workflow of the replication task does not exist,
+	// the DLQ handler skips and deletes the task
+	ErrWorkflowNotFound = errors.New("workflow of the replication task was not found")
+	// ErrWorkflowCompleted is the error to indicate the workflow of the replication task is already completed,
+	// the DLQ handler skips and deletes the task
+	ErrWorkflowCompleted = errors.New("workflow of the replication task is already completed")
+)
+
+type (
+	// SyncActivityReplicationTaskExecutor is the interface which is to execute activity sync replication tasks,
+	// it can be registered as the executor of the activity sync replication tasks of the domain DLQ
+	SyncActivityReplicationTaskExecutor interface {
+		ReplicationTaskExecutor
+		// ExecuteSyncActivityTask syncs the activity state of the task, it returns ErrWorkflowNotFound
+		// or ErrWorkflowCompleted if the workflow of the activity can no longer be changed
+		ExecuteSyncActivityTask(ctx context.Context, attributes *types.SyncActivityTaskAttributes) error
+	}
+
+	syncActivityReplicationTaskExecutorImpl struct {
+		historyClient history.Client
+		logger        log.Logger
+	}
+)
+
+var _ SyncActivityReplicationTaskExecutor = (*syncActivityReplicationTaskExecutorImpl)(nil)
+
+// NewSyncActivityReplicationTaskExecutor creates a new instance of activity sync replication task executor,
+// which syncs the activity state through the history service
+func NewSyncActivityReplicationTaskExecutor(
+	historyClient history.Client,
+	logger log.Logger,
+) SyncActivityReplicationTaskExecutor {
+
+	return &syncActivityReplicationTaskExecutorImpl{
+		historyClient: historyClient,
+		logger:        logger,
+	}
+}
+
+// ExecuteReplicationTask executes the activity sync replication task, tasks of the other types are not supported
+func (e *syncActivityReplicationTaskExecutorImpl) ExecuteReplicationTask(task *types.ReplicationTask, _ string) error {
+	if task.GetTaskType() != types.ReplicationTaskTypeSyncActivity {
+		return ErrUnsupportedReplicationTaskType
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWorkflowReplicationTaskContextTimeout)
+	defer cancel()
+	return e.ExecuteSyncActivityTask(ctx, task.SyncActivityTaskAttributes)
+}
+
+// ExecuteSyncActivityTask syncs the activity state of the task to the history service
+func (e *syncActivityReplicationTaskExecutorImpl) ExecuteSyncActivityTask(
+	ctx context.Context,
+	attributes *types.SyncActivityTaskAttributes,
+) error {
+
+	if attributes == nil {
+		return ErrEmptyWorkflowReplicationTask
+	}
+
+	err := e.historyClient.SyncActivity(ctx, &types.SyncActivityRequest{
+		DomainID:           attributes.DomainID,
+		WorkflowID:         attributes.WorkflowID,
+		RunID:              attributes.RunID,
+		Version:            attributes.Version,
+		ScheduledID:        attributes.ScheduledID,
+		ScheduledTime:      attributes.ScheduledTime,
+		StartedID:          attributes.StartedID,
+		StartedTime:        attributes.StartedTime,
+		LastHeartbeatTime:  attributes.LastHeartbeatTime,
+		Details:            attributes.Details,
+		Attempt:            attributes.Attempt,
+		LastFailureReason:  attributes.LastFailureReason,
+		LastWorkerIdentity: attributes.LastWorkerIdentity,
+		LastFailureDetails: attributes.LastFailureDetails,
+		VersionHistory:     attributes.VersionHistory,
+	})
+	switch err.(type) {
+	case *types.EntityNotExistsError:
+		return ErrWorkflowNotFound
+	case *types.WorkflowExecutionAlreadyCompletedError:
+		return ErrWorkflowCompleted
+	default:
+		return err
+	}
+}
+
+// Execute does not support domain replication tasks
+func (e *syncActivityReplicationTaskExecutorImpl) Execute(*types.DomainTaskAttributes) error {
+	return ErrUnsupportedReplicationTaskType
+}
+
+// Overwrite does not support domain replication tasks
+func (e *syncActivityReplicationTaskExecutorImpl) Overwrite(*types.DomainTaskAttributes) error {
+	return ErrUnsupportedReplicationTaskType
+}
+
+// AddMigration does nothing, the migrations only apply to domain replication tasks
+func (e *syncActivityReplicationTaskExecutorImpl) AddMigration(int, int, MigrationFunc) error {
+	return nil
+}
@@ -0,0 +1,128 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: syncActivityReplicationTaskExecutor.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/uber/cadence/common/types"
+)
+
+// MockSyncActivityReplicationTaskExecutor is a mock of SyncActivityReplicationTaskExecutor interface.
+type MockSyncActivityReplicationTaskExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncActivityReplicationTaskExecutorMockRecorder
+}
+
+// MockSyncActivityReplicationTaskExecutorMockRecorder is the mock recorder for MockSyncActivityReplicationTaskExecutor.
+type MockSyncActivityReplicationTaskExecutorMockRecorder struct {
+	mock *MockSyncActivityReplicationTaskExecutor
+}
+
+// NewMockSyncActivityReplicationTaskExecutor creates a new mock instance.
+func NewMockSyncActivityReplicationTaskExecutor(ctrl *gomock.Controller) *MockSyncActivityReplicationTaskExecutor {
+	mock := &MockSyncActivityReplicationTaskExecutor{ctrl: ctrl}
+	mock.recorder = &MockSyncActivityReplicationTaskExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSyncActivityReplicationTaskExecutor) EXPECT() *MockSyncActivityReplicationTaskExecutorMockRecorder {
+	return m.recorder
+}
+
+// AddMigration mocks base method.
+func (m *MockSyncActivityReplicationTaskExecutor) AddMigration(fromVersion, toVersion int, fn MigrationFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMigration", fromVersion, toVersion, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMigration indicates an expected call of AddMigration.
+func (mr *MockSyncActivityReplicationTaskExecutorMockRecorder) AddMigration(fromVersion, toVersion, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMigration", reflect.TypeOf((*MockSyncActivityReplicationTaskExecutor)(nil).AddMigration), fromVersion, toVersion, fn)
+}
+
+// Execute mocks base method.
+func (m *MockSyncActivityReplicationTaskExecutor) Execute(task *types.DomainTaskAttributes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockSyncActivityReplicationTaskExecutorMockRecorder) Execute(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockSyncActivityReplicationTaskExecutor)(nil).Execute), task)
+}
+
+// ExecuteReplicationTask mocks base method.
+func (m *MockSyncActivityReplicationTaskExecutor) ExecuteReplicationTask(task *types.ReplicationTask, sourceCluster string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteReplicationTask", task, sourceCluster)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteReplicationTask indicates an expected call of ExecuteReplicationTask.
+func (mr *MockSyncActivityReplicationTaskExecutorMockRecorder) ExecuteReplicationTask(task, sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteReplicationTask", reflect.TypeOf((*MockSyncActivityReplicationTaskExecutor)(nil).ExecuteReplicationTask), task, sourceCluster)
+}
+
+// ExecuteSyncActivityTask mocks base method.
+func (m *MockSyncActivityReplicationTaskExecutor) ExecuteSyncActivityTask(ctx context.Context, attributes *types.SyncActivityTaskAttributes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteSyncActivityTask", ctx, attributes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteSyncActivityTask indicates an expected call of ExecuteSyncActivityTask.
+func (mr *MockSyncActivityReplicationTaskExecutorMockRecorder) ExecuteSyncActivityTask(ctx, attributes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteSyncActivityTask", reflect.TypeOf((*MockSyncActivityReplicationTaskExecutor)(nil).ExecuteSyncActivityTask), ctx, attributes)
+}
+
+// Overwrite mocks base method.
+func (m *MockSyncActivityReplicationTaskExecutor) Overwrite(task *types.DomainTaskAttributes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overwrite", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Overwrite indicates an expected call of Overwrite.
+func (mr *MockSyncActivityReplicationTaskExecutorMockRecorder) Overwrite(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overwrite", reflect.TypeOf((*MockSyncActivityReplicationTaskExecutor)(nil).Overwrite), task)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+func newSyncActivityTaskAttributes() *types.SyncActivityTaskAttributes {
+	return &types.SyncActivityTaskAttributes{
+		DomainID:           "domain-id",
+		WorkflowID:         "workflow-id",
+		RunID:              "run-id",
+		Version:            1,
+		ScheduledID:        5,
+		ScheduledTime:      common.Int64Ptr(100),
+		StartedID:          6,
+		StartedTime:        common.Int64Ptr(200),
+		LastHeartbeatTime:  common.Int64Ptr(300),
+		Details:            []byte("details"),
+		Attempt:            2,
+		LastFailureReason:  common.StringPtr("reason"),
+		LastWorkerIdentity: "worker",
+		LastFailureDetails: []byte("failure details"),
+		VersionHistory: &types.VersionHistory{
+			Items: []*types.VersionHistoryItem{{EventID: 6, Version: 1}},
+		},
+	}
+}
+
+func TestSyncActivityReplicationTaskExecutor_ExecuteSyncActivityTask(t *testing.T) {
+	attributes := newSyncActivityTaskAttributes()
+	request := &types.SyncActivityRequest{
+		DomainID:           "domain-id",
+		WorkflowID:         "workflow-id",
+		RunID:              "run-id",
+		Version:            1,
+		ScheduledID:        5,
+		ScheduledTime:      attributes.ScheduledTime,
+		StartedID:          6,
+		StartedTime:        attributes.StartedTime,
+		LastHeartbeatTime:  attributes.LastHeartbeatTime,
+		Details:            attributes.Details,
+		Attempt:            2,
+		LastFailureReason:  attributes.LastFailureReason,
+		LastWorkerIdentity: "worker",
+		LastFailureDetails: attributes.LastFailureDetails,
+		VersionHistory:     attributes.VersionHistory,
+	}
+	historyErr := &types.RetryTaskV2Error{DomainID: "domain-id"}
+
+	tests := []struct {
+		name          string
+		attributes    *types.SyncActivityTaskAttributes
+		mockSetup     func(*history.MockClient)
+		expectedError error
+	}{
+		{
+			name:       "success",
+			attributes: attributes,
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().SyncActivity(gomock.Any(), request).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "workflow not found",
+			attributes: attributes,
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().SyncActivity(gomock.Any(), request).Return(&types.EntityNotExistsError{}).Times(1)
+			},
+			expectedError: ErrWorkflowNotFound,
+		},
+		{
+			name:       "workflow completed",
+			attributes: attributes,
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().SyncActivity(gomock.Any(), request).Return(&types.WorkflowExecutionAlreadyCompletedError{}).Times(1)
+			},
+			expectedError: ErrWorkflowCompleted,
+		},
+		{
+			name:       "history service error",
+			attributes: attributes,
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().SyncActivity(gomock.Any(), request).Return(historyErr).Times(1)
+			},
+			expectedError: historyErr,
+		},
+		{
+			name:          "empty activity sync task",
+			mockSetup:     func(client *history.MockClient) {},
+			expectedError: ErrEmptyWorkflowReplicationTask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			historyClient := history.NewMockClient(controller)
+			tt.mockSetup(historyClient)
+			executor := NewSyncActivityReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger())
+
+			err := executor.ExecuteSyncActivityTask(context.Background(), tt.attributes)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestSyncActivityReplicationTaskExecutor_ExecuteReplicationTask(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	historyClient := history.NewMockClient(controller)
+	executor := NewSyncActivityReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger())
+
+	historyClient.EXPECT().SyncActivity(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	assert.NoError(t, executor.ExecuteReplicationTask(&types.ReplicationTask{
+		TaskType:                   types.ReplicationTaskTypeSyncActivity.Ptr(),
+		SyncActivityTaskAttributes: newSyncActivityTaskAttributes(),
+	}, "cluster"))
+
+	// the executor only supports activity sync replication tasks
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.ExecuteReplicationTask(&types.ReplicationTask{
+		TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+		HistoryTaskV2Attributes: newHistoryTaskV2Attributes(),
+	}, "cluster"))
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.Execute(&types.DomainTaskAttributes{ID: "domain-id"}))
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.Overwrite(&types.DomainTaskAttributes{ID: "domain-id"}))
+	assert.NoError(t, executor.AddMigration(1, 2, func(attributes *types.DomainTaskAttributes) (*types.DomainTaskAttributes, error) {
+		return nil, errors.New("migrations are not applied to activity sync replication tasks")
+	}))
+}
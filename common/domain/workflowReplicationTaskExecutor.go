@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination workflowReplicationTaskExecutor_mock.go
+
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	defaultWorkflowReplicationTaskContextTimeout = 30 * time.Second
+)
+
+var (
+	// ErrEmptyWorkflowReplicationTask is the error to indicate empty workflow replication task
+	ErrEmptyWorkflowReplicationTask = &types.BadRequestError{Message: "empty workflow replication task"}
+)
+
+type (
+	// WorkflowReplicationTaskExecutor is the interface which is to execute history and activity replication tasks
+	WorkflowReplicationTaskExecutor interface {
+		Execute(task *types.ReplicationTask) error
+	}
+
+	workflowReplicationTaskExecutorImpl struct {
+		historyClient   history.Client
+		historyExecutor HistoryReplicationTaskExecutor
+		logger          log.Logger
+	}
+)
+
+// NewWorkflowReplicationTaskExecutor creates a new instance of workflow replication task executor,
+// which applies the tasks through the history service
+func NewWorkflowReplicationTaskExecutor(
+	historyClient history.Client,
+	logger log.Logger,
+) WorkflowReplicationTaskExecutor {
+
+	return &workflowReplicationTaskExecutorImpl{
+		historyClient:   historyClient,
+		historyExecutor: NewHistoryReplicationTaskExecutor(historyClient, logger),
+		logger:          logger,
+	}
+}
+
+// Execute handles the history and activity replication task
+func (e *workflowReplicationTaskExecutorImpl) Execute(task *types.ReplicationTask) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWorkflowReplicationTaskContextTimeout)
+	defer cancel()
+
+	switch task.GetTaskType() {
+	case types.ReplicationTaskTypeHistoryV2:
+		return e.historyExecutor.ExecuteHistoryTask(ctx, task.HistoryTaskV2Attributes)
+	case types.ReplicationTaskTypeSyncActivity:
+		return e.handleSyncActivityReplicationTask(ctx, task.SyncActivityTaskAttributes)
+	default:
+		return ErrUnsupportedReplicationTaskType
+	}
+}
+
+func (e *workflowReplicationTaskExecutorImpl) handleSyncActivityReplicationTask(
+	ctx context.Context,
+	attr *types.SyncActivityTaskAttributes,
+) error {
+
+	if attr == nil {
+		return ErrEmptyWorkflowReplicationTask
+	}
+
+	return e.historyClient.SyncActivity(ctx, &types.SyncActivityRequest{
+		DomainID:           attr.DomainID,
+		WorkflowID:         attr.WorkflowID,
+		RunID:              attr.RunID,
+		Version:            attr.Version,
+		ScheduledID:        attr.ScheduledID,
+		ScheduledTime:      attr.ScheduledTime,
+		StartedID:          attr.StartedID,
+		StartedTime:        attr.StartedTime,
+		LastHeartbeatTime:  attr.LastHeartbeatTime,
+		Details:            attr.Details,
+		Attempt:            attr.Attempt,
+		LastFailureReason:  attr.LastFailureReason,
+		LastFailureDetails: attr.LastFailureDetails,
+		LastWorkerIdentity: attr.LastWorkerIdentity,
+		VersionHistory:     attr.GetVersionHistory(),
+	})
+}
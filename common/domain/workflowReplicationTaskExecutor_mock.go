@@ -0,0 +1,72 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: workflowReplicationTaskExecutor.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	types "github.com/uber/cadence/common/types"
+)
+
+// MockWorkflowReplicationTaskExecutor is a mock of WorkflowReplicationTaskExecutor interface.
+type MockWorkflowReplicationTaskExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowReplicationTaskExecutorMockRecorder
+}
+
+// MockWorkflowReplicationTaskExecutorMockRecorder is the mock recorder for MockWorkflowReplicationTaskExecutor.
+type MockWorkflowReplicationTaskExecutorMockRecorder struct {
+	mock *MockWorkflowReplicationTaskExecutor
+}
+
+// NewMockWorkflowReplicationTaskExecutor creates a new mock instance.
+func NewMockWorkflowReplicationTaskExecutor(ctrl *gomock.Controller) *MockWorkflowReplicationTaskExecutor {
+	mock := &MockWorkflowReplicationTaskExecutor{ctrl: ctrl}
+	mock.recorder = &MockWorkflowReplicationTaskExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowReplicationTaskExecutor) EXPECT() *MockWorkflowReplicationTaskExecutorMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockWorkflowReplicationTaskExecutor) Execute(task *types.ReplicationTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockWorkflowReplicationTaskExecutorMockRecorder) Execute(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockWorkflowReplicationTaskExecutor)(nil).Execute), task)
+}
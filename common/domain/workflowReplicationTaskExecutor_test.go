@@ -0,0 +1,180 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestExecuteReplicationTask_Dispatch(t *testing.T) {
+	historyTask := &types.ReplicationTask{
+		TaskType:                types.ReplicationTaskTypeHistoryV2.Ptr(),
+		HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{DomainID: "domain-id"},
+	}
+	activityTask := &types.ReplicationTask{
+		TaskType:                   types.ReplicationTaskTypeSyncActivity.Ptr(),
+		SyncActivityTaskAttributes: &types.SyncActivityTaskAttributes{DomainID: "domain-id"},
+	}
+
+	tests := []struct {
+		name                  string
+		task                  *types.ReplicationTask
+		withWorkflowExecutor  bool
+		expectWorkflowExecute bool
+		expectedErr           error
+	}{
+		{
+			name:        "empty domain task",
+			task:        &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr()},
+			expectedErr: ErrEmptyDomainReplicationTask,
+		},
+		{
+			name:                  "history task",
+			task:                  historyTask,
+			withWorkflowExecutor:  true,
+			expectWorkflowExecute: true,
+		},
+		{
+			name:                  "sync activity task",
+			task:                  activityTask,
+			withWorkflowExecutor:  true,
+			expectWorkflowExecute: true,
+		},
+		{
+			name:        "history task without workflow executor",
+			task:        historyTask,
+			expectedErr: ErrUnsupportedReplicationTaskType,
+		},
+		{
+			name:                 "unsupported task type",
+			task:                 &types.ReplicationTask{TaskType: types.ReplicationTaskTypeSyncShardStatus.Ptr()},
+			withWorkflowExecutor: true,
+			expectedErr:          ErrUnsupportedReplicationTaskType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			var workflowExecutor WorkflowReplicationTaskExecutor
+			if tt.withWorkflowExecutor {
+				mockWorkflowExecutor := NewMockWorkflowReplicationTaskExecutor(controller)
+				if tt.expectWorkflowExecute {
+					mockWorkflowExecutor.EXPECT().Execute(tt.task).Return(nil).Times(1)
+				}
+				workflowExecutor = mockWorkflowExecutor
+			}
+			executor := NewReplicationTaskExecutor(nil, nil, workflowExecutor, loggerimpl.NewNopLogger())
+
+			err := executor.ExecuteReplicationTask(tt.task, "")
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestWorkflowReplicationTaskExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name          string
+		task          *types.ReplicationTask
+		mockSetup     func(*history.MockClient)
+		expectedError error
+	}{
+		{
+			name: "history task",
+			task: &types.ReplicationTask{
+				TaskType: types.ReplicationTaskTypeHistoryV2.Ptr(),
+				HistoryTaskV2Attributes: &types.HistoryTaskV2Attributes{
+					DomainID:   "domain-id",
+					WorkflowID: "workflow-id",
+					RunID:      "run-id",
+				},
+			},
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().ReplicateEventsV2(gomock.Any(), &types.ReplicateEventsV2Request{
+					DomainUUID: "domain-id",
+					WorkflowExecution: &types.WorkflowExecution{
+						WorkflowID: "workflow-id",
+						RunID:      "run-id",
+					},
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "sync activity task",
+			task: &types.ReplicationTask{
+				TaskType: types.ReplicationTaskTypeSyncActivity.Ptr(),
+				SyncActivityTaskAttributes: &types.SyncActivityTaskAttributes{
+					DomainID:   "domain-id",
+					WorkflowID: "workflow-id",
+					RunID:      "run-id",
+				},
+			},
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().SyncActivity(gomock.Any(), &types.SyncActivityRequest{
+					DomainID:   "domain-id",
+					WorkflowID: "workflow-id",
+					RunID:      "run-id",
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:          "empty history task",
+			task:          &types.ReplicationTask{TaskType: types.ReplicationTaskTypeHistoryV2.Ptr()},
+			mockSetup:     func(client *history.MockClient) {},
+			expectedError: ErrEmptyWorkflowReplicationTask,
+		},
+		{
+			name:          "empty sync activity task",
+			task:          &types.ReplicationTask{TaskType: types.ReplicationTaskTypeSyncActivity.Ptr()},
+			mockSetup:     func(client *history.MockClient) {},
+			expectedError: ErrEmptyWorkflowReplicationTask,
+		},
+		{
+			name:          "domain task",
+			task:          &types.ReplicationTask{TaskType: types.ReplicationTaskTypeDomain.Ptr()},
+			mockSetup:     func(client *history.MockClient) {},
+			expectedError: ErrUnsupportedReplicationTaskType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			historyClient := history.NewMockClient(controller)
+			tt.mockSetup(historyClient)
+			executor := NewWorkflowReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger())
+
+			err := executor.Execute(tt.task)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -package $GOPACKAGE -source $GOFILE -destination workflowResetReplicationTaskExecutor_mock.go -self_package github.com/uber/cadence/common/domain -aux_files github.com/uber/cadence/common/domain=replicationTaskExecutor.go
+
+package domain
+
+import (
+	"context"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
+)
+
+var (
+	// ErrEmptyWorkflowResetTask is the error to indicate empty workflow reset replication task
+	ErrEmptyWorkflowResetTask = &types.BadRequestError{Message: "empty workflow reset replication task"}
+)
+
+type (
+	// WorkflowResetReplicationTaskExecutor is the interface which is to execute workflow reset replication tasks,
+	// it can be registered as the executor of the workflow reset replication tasks of the domain DLQ
+	WorkflowResetReplicationTaskExecutor interface {
+		ReplicationTaskExecutor
+		// ExecuteWorkflowResetTask resets the workflow of the task, it returns ErrDuplicateTask if the reset
+		// was already applied and ErrWorkflowNotFound if the workflow does not exist
+		ExecuteWorkflowResetTask(ctx context.Context, attributes *types.WorkflowResetTaskAttributes) error
+	}
+
+	workflowResetReplicationTaskExecutorImpl struct {
+		historyClient history.Client
+		logger        log.Logger
+	}
+)
+
+var _ WorkflowResetReplicationTaskExecutor = (*workflowResetReplicationTaskExecutorImpl)(nil)
+
+// NewWorkflowResetReplicationTaskExecutor creates a new instance of workflow reset replication task executor,
+// which resets the workflows through the history service
+func NewWorkflowResetReplicationTaskExecutor(
+	historyClient history.Client,
+	logger log.Logger,
+) WorkflowResetReplicationTaskExecutor {
+
+	return &workflowResetReplicationTaskExecutorImpl{
+		historyClient: historyClient,
+		logger:        logger,
+	}
+}
+
+// ExecuteReplicationTask executes the workflow reset replication task, tasks of the other types are not supported
+func (e *workflowResetReplicationTaskExecutorImpl) ExecuteReplicationTask(task *types.ReplicationTask, _ string) error {
+	if task.GetTaskType() != types.ReplicationTaskTypeWorkflowReset {
+		return ErrUnsupportedReplicationTaskType
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultWorkflowReplicationTaskContextTimeout)
+	defer cancel()
+	return e.ExecuteWorkflowResetTask(ctx, task.WorkflowResetTaskAttributes)
+}
+
+// ExecuteWorkflowResetTask resets the workflow of the task through the history service. The history service
+// deduplicates the resets by request ID and returns the current run of the workflow for a reset it already applied,
+// so the reset is a duplicate if the run it returns was already the current run before the reset.
+func (e *workflowResetReplicationTaskExecutorImpl) ExecuteWorkflowResetTask(
+	ctx context.Context,
+	attributes *types.WorkflowResetTaskAttributes,
+) error {
+
+	if attributes == nil {
+		return ErrEmptyWorkflowResetTask
+	}
+
+	current, err := e.historyClient.GetMutableState(ctx, &types.GetMutableStateRequest{
+		DomainUUID: attributes.DomainID,
+		Execution:  &types.WorkflowExecution{WorkflowID: attributes.WorkflowID},
+	})
+	if err != nil {
+		return convertWorkflowResetError(err)
+	}
+
+	resp, err := e.historyClient.ResetWorkflowExecution(ctx, &types.HistoryResetWorkflowExecutionRequest{
+		DomainUUID: attributes.DomainID,
+		ResetRequest: &types.ResetWorkflowExecutionRequest{
+			WorkflowExecution: &types.WorkflowExecution{
+				WorkflowID: attributes.WorkflowID,
+				RunID:      attributes.RunID,
+			},
+			Reason:                attributes.Reason,
+			DecisionFinishEventID: attributes.DecisionFinishEventID,
+			RequestID:             attributes.RequestID,
+			SkipSignalReapply:     attributes.SkipSignalReapply,
+		},
+	})
+	if err != nil {
+		return convertWorkflowResetError(err)
+	}
+
+	if resp.GetRunID() == current.GetExecution().GetRunID() {
+		e.logger.Warn("Skipping workflow reset which was already applied",
+			tag.WorkflowDomainID(attributes.DomainID),
+			tag.WorkflowID(attributes.WorkflowID),
+			tag.WorkflowRunID(attributes.RunID))
+		return ErrDuplicateTask
+	}
+	return nil
+}
+
+// Execute does not support domain replication tasks
+func (e *workflowResetReplicationTaskExecutorImpl) Execute(*types.DomainTaskAttributes) error {
+	return ErrUnsupportedReplicationTaskType
+}
+
+// Overwrite does not support domain replication tasks
+func (e *workflowResetReplicationTaskExecutorImpl) Overwrite(*types.DomainTaskAttributes) error {
+	return ErrUnsupportedReplicationTaskType
+}
+
+// AddMigration does nothing, the migrations only apply to domain replication tasks
+func (e *workflowResetReplicationTaskExecutorImpl) AddMigration(int, int, MigrationFunc) error {
+	return nil
+}
+
+func convertWorkflowResetError(err error) error {
+	if _, ok := err.(*types.EntityNotExistsError); ok {
+		return ErrWorkflowNotFound
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: workflowResetReplicationTaskExecutor.go
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/uber/cadence/common/types"
+)
+
+// MockWorkflowResetReplicationTaskExecutor is a mock of WorkflowResetReplicationTaskExecutor interface.
+type MockWorkflowResetReplicationTaskExecutor struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowResetReplicationTaskExecutorMockRecorder
+}
+
+// MockWorkflowResetReplicationTaskExecutorMockRecorder is the mock recorder for MockWorkflowResetReplicationTaskExecutor.
+type MockWorkflowResetReplicationTaskExecutorMockRecorder struct {
+	mock *MockWorkflowResetReplicationTaskExecutor
+}
+
+// NewMockWorkflowResetReplicationTaskExecutor creates a new mock instance.
+func NewMockWorkflowResetReplicationTaskExecutor(ctrl *gomock.Controller) *MockWorkflowResetReplicationTaskExecutor {
+	mock := &MockWorkflowResetReplicationTaskExecutor{ctrl: ctrl}
+	mock.recorder = &MockWorkflowResetReplicationTaskExecutorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowResetReplicationTaskExecutor) EXPECT() *MockWorkflowResetReplicationTaskExecutorMockRecorder {
+	return m.recorder
+}
+
+// AddMigration mocks base method.
+func (m *MockWorkflowResetReplicationTaskExecutor) AddMigration(fromVersion, toVersion int, fn MigrationFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMigration", fromVersion, toVersion, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMigration indicates an expected call of AddMigration.
+func (mr *MockWorkflowResetReplicationTaskExecutorMockRecorder) AddMigration(fromVersion, toVersion, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMigration", reflect.TypeOf((*MockWorkflowResetReplicationTaskExecutor)(nil).AddMigration), fromVersion, toVersion, fn)
+}
+
+// Execute mocks base method.
+func (m *MockWorkflowResetReplicationTaskExecutor) Execute(task *types.DomainTaskAttributes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockWorkflowResetReplicationTaskExecutorMockRecorder) Execute(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockWorkflowResetReplicationTaskExecutor)(nil).Execute), task)
+}
+
+// ExecuteReplicationTask mocks base method.
+func (m *MockWorkflowResetReplicationTaskExecutor) ExecuteReplicationTask(task *types.ReplicationTask, sourceCluster string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteReplicationTask", task, sourceCluster)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteReplicationTask indicates an expected call of ExecuteReplicationTask.
+func (mr *MockWorkflowResetReplicationTaskExecutorMockRecorder) ExecuteReplicationTask(task, sourceCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteReplicationTask", reflect.TypeOf((*MockWorkflowResetReplicationTaskExecutor)(nil).ExecuteReplicationTask), task, sourceCluster)
+}
+
+// ExecuteWorkflowResetTask mocks base method.
+func (m *MockWorkflowResetReplicationTaskExecutor) ExecuteWorkflowResetTask(ctx context.Context, attributes *types.WorkflowResetTaskAttributes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteWorkflowResetTask", ctx, attributes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteWorkflowResetTask indicates an expected call of ExecuteWorkflowResetTask.
+func (mr *MockWorkflowResetReplicationTaskExecutorMockRecorder) ExecuteWorkflowResetTask(ctx, attributes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWorkflowResetTask", reflect.TypeOf((*MockWorkflowResetReplicationTaskExecutor)(nil).ExecuteWorkflowResetTask), ctx, attributes)
+}
+
+// Overwrite mocks base method.
+func (m *MockWorkflowResetReplicationTaskExecutor) Overwrite(task *types.DomainTaskAttributes) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overwrite", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Overwrite indicates an expected call of Overwrite.
+func (mr *MockWorkflowResetReplicationTaskExecutorMockRecorder) Overwrite(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overwrite", reflect.TypeOf((*MockWorkflowResetReplicationTaskExecutor)(nil).Overwrite), task)
+}
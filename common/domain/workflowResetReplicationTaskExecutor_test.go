@@ -0,0 +1,167 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+func newWorkflowResetTaskAttributes() *types.WorkflowResetTaskAttributes {
+	return &types.WorkflowResetTaskAttributes{
+		DomainID:              "domain-id",
+		WorkflowID:            "workflow-id",
+		RunID:                 "run-id",
+		Reason:                "reason",
+		DecisionFinishEventID: 4,
+		RequestID:             "request-id",
+		SkipSignalReapply:     true,
+	}
+}
+
+func expectCurrentRun(client *history.MockClient, runID string) *gomock.Call {
+	return client.EXPECT().GetMutableState(gomock.Any(), &types.GetMutableStateRequest{
+		DomainUUID: "domain-id",
+		Execution:  &types.WorkflowExecution{WorkflowID: "workflow-id"},
+	}).Return(&types.GetMutableStateResponse{
+		Execution: &types.WorkflowExecution{WorkflowID: "workflow-id", RunID: runID},
+	}, nil).Times(1)
+}
+
+func TestWorkflowResetReplicationTaskExecutor_ExecuteWorkflowResetTask(t *testing.T) {
+	request := &types.HistoryResetWorkflowExecutionRequest{
+		DomainUUID: "domain-id",
+		ResetRequest: &types.ResetWorkflowExecutionRequest{
+			WorkflowExecution:     &types.WorkflowExecution{WorkflowID: "workflow-id", RunID: "run-id"},
+			Reason:                "reason",
+			DecisionFinishEventID: 4,
+			RequestID:             "request-id",
+			SkipSignalReapply:     true,
+		},
+	}
+	historyErr := &types.InternalServiceError{Message: "history"}
+
+	tests := []struct {
+		name          string
+		attributes    *types.WorkflowResetTaskAttributes
+		mockSetup     func(*history.MockClient)
+		expectedError error
+	}{
+		{
+			name:       "success",
+			attributes: newWorkflowResetTaskAttributes(),
+			mockSetup: func(client *history.MockClient) {
+				expectCurrentRun(client, "run-id")
+				client.EXPECT().ResetWorkflowExecution(gomock.Any(), request).
+					Return(&types.ResetWorkflowExecutionResponse{RunID: "reset-run-id"}, nil).Times(1)
+			},
+		},
+		{
+			name:       "reset already applied",
+			attributes: newWorkflowResetTaskAttributes(),
+			mockSetup: func(client *history.MockClient) {
+				// the history service returns the current run for the request ID of a reset it already applied
+				expectCurrentRun(client, "reset-run-id")
+				client.EXPECT().ResetWorkflowExecution(gomock.Any(), request).
+					Return(&types.ResetWorkflowExecutionResponse{RunID: "reset-run-id"}, nil).Times(1)
+			},
+			expectedError: ErrDuplicateTask,
+		},
+		{
+			name:       "workflow not found",
+			attributes: newWorkflowResetTaskAttributes(),
+			mockSetup: func(client *history.MockClient) {
+				client.EXPECT().GetMutableState(gomock.Any(), gomock.Any()).
+					Return(nil, &types.EntityNotExistsError{}).Times(1)
+			},
+			expectedError: ErrWorkflowNotFound,
+		},
+		{
+			name:       "run not found",
+			attributes: newWorkflowResetTaskAttributes(),
+			mockSetup: func(client *history.MockClient) {
+				expectCurrentRun(client, "other-run-id")
+				client.EXPECT().ResetWorkflowExecution(gomock.Any(), request).
+					Return(nil, &types.EntityNotExistsError{}).Times(1)
+			},
+			expectedError: ErrWorkflowNotFound,
+		},
+		{
+			name:       "history service error",
+			attributes: newWorkflowResetTaskAttributes(),
+			mockSetup: func(client *history.MockClient) {
+				expectCurrentRun(client, "run-id")
+				client.EXPECT().ResetWorkflowExecution(gomock.Any(), request).Return(nil, historyErr).Times(1)
+			},
+			expectedError: historyErr,
+		},
+		{
+			name:          "empty workflow reset task",
+			mockSetup:     func(*history.MockClient) {},
+			expectedError: ErrEmptyWorkflowResetTask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			historyClient := history.NewMockClient(controller)
+			tt.mockSetup(historyClient)
+			executor := NewWorkflowResetReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger())
+
+			err := executor.ExecuteWorkflowResetTask(context.Background(), tt.attributes)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestWorkflowResetReplicationTaskExecutor_ExecuteReplicationTask(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	historyClient := history.NewMockClient(controller)
+	executor := NewWorkflowResetReplicationTaskExecutor(historyClient, loggerimpl.NewNopLogger())
+
+	expectCurrentRun(historyClient, "run-id")
+	historyClient.EXPECT().ResetWorkflowExecution(gomock.Any(), gomock.Any()).
+		Return(&types.ResetWorkflowExecutionResponse{RunID: "reset-run-id"}, nil).Times(1)
+	assert.NoError(t, executor.ExecuteReplicationTask(&types.ReplicationTask{
+		TaskType:                    types.ReplicationTaskTypeWorkflowReset.Ptr(),
+		WorkflowResetTaskAttributes: newWorkflowResetTaskAttributes(),
+	}, "cluster"))
+
+	// the executor only supports workflow reset replication tasks
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.ExecuteReplicationTask(&types.ReplicationTask{
+		TaskType:                   types.ReplicationTaskTypeSyncActivity.Ptr(),
+		SyncActivityTaskAttributes: newSyncActivityTaskAttributes(),
+	}, "cluster"))
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.Execute(&types.DomainTaskAttributes{ID: "domain-id"}))
+	assert.Equal(t, ErrUnsupportedReplicationTaskType, executor.Overwrite(&types.DomainTaskAttributes{ID: "domain-id"}))
+}
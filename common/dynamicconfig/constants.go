@@ -37,7 +37,8 @@ func (k Key) String() string {
 //
 // Since our ratelimiters do int/float conversions, and zero or negative values
 // result in not allowing any requests, math.MaxInt is unsafe:
-//   int(float64(math.MaxInt)) // -9223372036854775808
+//
+//	int(float64(math.MaxInt)) // -9223372036854775808
 //
 // Much higher values are possible, but we can't handle 2 billion RPS, this is good enough.
 const UnlimitedRPS = math.MaxInt32
@@ -492,6 +493,214 @@ const (
 	// Default value: 0.1
 	// Allowed filters: N/A
 	DomainFailoverRefreshTimerJitterCoefficient
+	// DomainDLQMaxRetryAttempts is the max number of attempts to merge a domain DLQ message before it is dropped as poisoned
+	// KeyName: frontend.domainDLQMaxRetryAttempts
+	// Value type: Int
+	// Default value: 5
+	// Allowed filters: N/A
+	DomainDLQMaxRetryAttempts
+	// DomainDLQSizeEmitInterval is the interval to poll and emit the domain DLQ size gauge
+	// KeyName: frontend.domainDLQSizeEmitInterval
+	// Value type: Duration
+	// Default value: 5m (5*time.Minute)
+	// Allowed filters: N/A
+	DomainDLQSizeEmitInterval
+	// DomainDLQMergeRPS is the max rate of domain DLQ messages applied by a merge
+	// KeyName: frontend.domainDLQMergeRPS
+	// Value type: Int
+	// Default value: 10
+	// Allowed filters: N/A
+	DomainDLQMergeRPS
+	// DomainDLQReplayRPS is the max rate of domain DLQ messages written to the destination by a replay
+	// KeyName: frontend.domainDLQReplayRPS
+	// Value type: Int
+	// Default value: 100
+	// Allowed filters: N/A
+	DomainDLQReplayRPS
+	// DomainDLQDeduplicationWindowSize is the number of recently merged domain DLQ messages remembered to skip duplicates, 0 disables deduplication
+	// KeyName: frontend.domainDLQDeduplicationWindowSize
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: N/A
+	DomainDLQDeduplicationWindowSize
+	// DomainDLQDeduplicationFalsePositiveRate is the false positive rate of the filter used to skip duplicated domain DLQ messages
+	// KeyName: frontend.domainDLQDeduplicationFalsePositiveRate
+	// Value type: Float64
+	// Default value: 0.0001
+	// Allowed filters: N/A
+	DomainDLQDeduplicationFalsePositiveRate
+	// DomainDLQMergeShardCount is the number of shards the domain DLQ is split into for the background merge workers, 0 disables the workers
+	// KeyName: frontend.domainDLQMergeShardCount
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: N/A
+	DomainDLQMergeShardCount
+	// DomainDLQMergeShardInterval is the interval between two background merges of a domain DLQ shard
+	// KeyName: frontend.domainDLQMergeShardInterval
+	// Value type: Duration
+	// Default value: 1m (1*time.Minute)
+	// Allowed filters: N/A
+	DomainDLQMergeShardInterval
+	// DomainDLQMessageTTL is the duration after which domain DLQ messages are purged, 0 disables the expiry
+	// KeyName: frontend.domainDLQMessageTTL
+	// Value type: Duration
+	// Default value: 720h (30*24*time.Hour)
+	// Allowed filters: N/A
+	DomainDLQMessageTTL
+	// DomainDLQPurgeBatchDelay is the delay between two batches of domain DLQ messages deleted by a purge
+	// KeyName: frontend.domainDLQPurgeBatchDelay
+	// Value type: Duration
+	// Default value: 100ms (100*time.Millisecond)
+	// Allowed filters: N/A
+	DomainDLQPurgeBatchDelay
+	// DomainDLQPriorityMergeEnabled merges the high priority messages of a domain DLQ page before the other messages
+	// KeyName: frontend.domainDLQPriorityMergeEnabled
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	DomainDLQPriorityMergeEnabled
+	// DomainDLQMaxReadPageSize is the largest page of domain DLQ messages a read may request
+	// KeyName: frontend.domainDLQMaxReadPageSize
+	// Value type: Int
+	// Default value: 1000
+	// Allowed filters: N/A
+	DomainDLQMaxReadPageSize
+	// DomainDLQSoftDeleteEnabled marks deleted domain DLQ messages as deleted instead of removing them,
+	// they are removed once they are garbage collected
+	// KeyName: frontend.domainDLQSoftDeleteEnabled
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	DomainDLQSoftDeleteEnabled
+	// DomainDLQLargeMessageThresholdBytes is the serialized size above which a domain DLQ message is reported
+	// as large, as large messages risk failing to be written, 0 disables the report
+	// KeyName: frontend.domainDLQLargeMessageThresholdBytes
+	// Value type: Int
+	// Default value: 1048576
+	// Allowed filters: N/A
+	DomainDLQLargeMessageThresholdBytes
+	// DomainDLQStalledAckThreshold is how long the domain DLQ ack level may stay unchanged while the DLQ
+	// is not empty before it is reported as stalled, 0 disables the report
+	// KeyName: frontend.domainDLQStalledAckThreshold
+	// Value type: Duration
+	// Default value: 1h
+	// Allowed filters: N/A
+	DomainDLQStalledAckThreshold
+	// DomainDLQDepthAlertThreshold is the number of domain DLQ messages above which the notification webhook
+	// is alerted, 0 disables the alert
+	// KeyName: frontend.domainDLQDepthAlertThreshold
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: N/A
+	DomainDLQDepthAlertThreshold
+	// DomainDLQNotificationWebhookURL is the URL the domain DLQ events, such as a stalled ack level,
+	// an exceeded depth or a completed merge, are POSTed to as JSON, empty disables the notifications
+	// KeyName: frontend.domainDLQNotificationWebhookURL
+	// Value type: String
+	// Default value: ""
+	// Allowed filters: N/A
+	DomainDLQNotificationWebhookURL
+	// DomainDLQMergeTimeout is how long merging a page of the domain DLQ may take before it is interrupted,
+	// the messages merged until then stay acknowledged, 0 disables the timeout
+	// KeyName: frontend.domainDLQMergeTimeout
+	// Value type: Duration
+	// Default value: 5m
+	// Allowed filters: N/A
+	DomainDLQMergeTimeout
+	// DomainDLQReadMaxRetries is the number of times reading domain DLQ messages is retried with backoff
+	// on transient persistence errors before the error is returned, 0 disables the retries
+	// KeyName: frontend.domainDLQReadMaxRetries
+	// Value type: Int
+	// Default value: 3
+	// Allowed filters: N/A
+	DomainDLQReadMaxRetries
+	// DomainDLQMergeCheckpointGranularity is how often a merge of the domain DLQ deletes the merged messages
+	// and moves the ack level, PerTask after every message or PerPage after every page.
+	// PerTask keeps a crash from re-executing the merged messages of a page at the cost of more persistence writes,
+	// pages merged by priority are always checkpointed PerPage
+	// KeyName: frontend.domainDLQMergeCheckpointGranularity
+	// Value type: String
+	// Default value: PerPage
+	// Allowed filters: N/A
+	DomainDLQMergeCheckpointGranularity
+	// DomainDLQMergeParallelism is the number of messages of a page a merge of the domain DLQ executes concurrently,
+	// the results are committed in the order of the page regardless
+	// KeyName: frontend.domainDLQMergeParallelism
+	// Value type: Int
+	// Default value: 1
+	// Allowed filters: N/A
+	DomainDLQMergeParallelism
+	// DomainDLQInvalidMessagePolicy is how a merge of the domain DLQ handles the messages missing fields their
+	// task type requires, None executes them unvalidated, Fail fails them like any message failing to execute
+	// until they exhaust their retry attempts and Skip removes them without executing them
+	// KeyName: frontend.domainDLQInvalidMessagePolicy
+	// Value type: String
+	// Default value: Fail
+	// Allowed filters: N/A
+	DomainDLQInvalidMessagePolicy
+	// DomainDLQMergeBackpressureMaxAttempts is how many times a merge of the domain DLQ retries a message throttled
+	// by the destination cluster, pausing the merge with an exponential backoff of up to a minute before every retry.
+	// 0 disables the backpressure, so throttled messages are retried later like other transient failures
+	// KeyName: frontend.domainDLQMergeBackpressureMaxAttempts
+	// Value type: Int
+	// Default value: 10
+	// Allowed filters: N/A
+	DomainDLQMergeBackpressureMaxAttempts
+	// DomainDLQGroupedMergeEnabled merges the domain DLQ messages with the same correlation ID, e.g. the tasks of a workflow run,
+	// as a group, so the messages of a group after a failed one are not executed and the group is deleted as a whole
+	// KeyName: frontend.domainDLQGroupedMergeEnabled
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	DomainDLQGroupedMergeEnabled
+	// DomainDLQExecutionJournalEnabled journals the domain DLQ messages executed by the merges until they are deleted,
+	// so a merge restarted after a crash does not apply them again
+	// KeyName: frontend.domainDLQExecutionJournalEnabled
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	DomainDLQExecutionJournalEnabled
+	// DomainDLQVersionedAckLevelEnabled conditions the domain DLQ ack level updates on the version of the ack levels
+	// they were read at, instead of on the ack level alone
+	// KeyName: frontend.domainDLQVersionedAckLevelEnabled
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	DomainDLQVersionedAckLevelEnabled
+	// DomainDLQAckLevelMaxConflictRetries is the number of times a versioned domain DLQ ack level update is retried
+	// when the ack levels changed version concurrently
+	// KeyName: frontend.domainDLQAckLevelMaxConflictRetries
+	// Value type: Int
+	// Default value: 3
+	// Allowed filters: N/A
+	DomainDLQAckLevelMaxConflictRetries
+	// DomainDLQArchivalEnabled archives the purged domain DLQ messages to DomainDLQArchivalURI before they are deleted
+	// KeyName: frontend.domainDLQArchivalEnabled
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	DomainDLQArchivalEnabled
+	// DomainDLQArchivalURI is the URI of the history archiver the purged domain DLQ messages are archived to,
+	// such as file:///tmp/dlq, s3://bucket/dlq or gs://bucket/dlq
+	// KeyName: frontend.domainDLQArchivalURI
+	// Value type: String
+	// Default value: ""
+	// Allowed filters: N/A
+	DomainDLQArchivalURI
+	// DomainDLQGrowthRateSampleInterval is the interval the depth of the domain DLQ is sampled at
+	// to compute the moving average of its growth rate
+	// KeyName: frontend.domainDLQGrowthRateSampleInterval
+	// Value type: Duration
+	// Default value: 1m
+	// Allowed filters: N/A
+	DomainDLQGrowthRateSampleInterval
+	// DomainDLQMaxGrowthRate is the growth rate of the domain DLQ, in messages per second, above which
+	// the merges of the DLQ shards are paused until the growth rate falls back below it, 0 disables the pause
+	// KeyName: frontend.domainDLQMaxGrowthRate
+	// Value type: Float64
+	// Default value: 0
+	// Allowed filters: N/A
+	DomainDLQMaxGrowthRate
 	// FrontendErrorInjectionRate is rate for injecting random error in frontend client
 	// KeyName: frontend.errorInjectionRate
 	// Value type: Float64
@@ -2327,6 +2536,37 @@ var Keys = map[Key]string{
 	VisibilityArchivalQueryMaxPageSize:          "frontend.visibilityArchivalQueryMaxPageSize",
 	DomainFailoverRefreshInterval:               "frontend.domainFailoverRefreshInterval",
 	DomainFailoverRefreshTimerJitterCoefficient: "frontend.domainFailoverRefreshTimerJitterCoefficient",
+	DomainDLQMaxRetryAttempts:                   "frontend.domainDLQMaxRetryAttempts",
+	DomainDLQSizeEmitInterval:                   "frontend.domainDLQSizeEmitInterval",
+	DomainDLQMergeRPS:                           "frontend.domainDLQMergeRPS",
+	DomainDLQReplayRPS:                          "frontend.domainDLQReplayRPS",
+	DomainDLQDeduplicationWindowSize:            "frontend.domainDLQDeduplicationWindowSize",
+	DomainDLQDeduplicationFalsePositiveRate:     "frontend.domainDLQDeduplicationFalsePositiveRate",
+	DomainDLQMergeShardCount:                    "frontend.domainDLQMergeShardCount",
+	DomainDLQMergeShardInterval:                 "frontend.domainDLQMergeShardInterval",
+	DomainDLQMessageTTL:                         "frontend.domainDLQMessageTTL",
+	DomainDLQPurgeBatchDelay:                    "frontend.domainDLQPurgeBatchDelay",
+	DomainDLQPriorityMergeEnabled:               "frontend.domainDLQPriorityMergeEnabled",
+	DomainDLQMaxReadPageSize:                    "frontend.domainDLQMaxReadPageSize",
+	DomainDLQSoftDeleteEnabled:                  "frontend.domainDLQSoftDeleteEnabled",
+	DomainDLQLargeMessageThresholdBytes:         "frontend.domainDLQLargeMessageThresholdBytes",
+	DomainDLQStalledAckThreshold:                "frontend.domainDLQStalledAckThreshold",
+	DomainDLQDepthAlertThreshold:                "frontend.domainDLQDepthAlertThreshold",
+	DomainDLQNotificationWebhookURL:             "frontend.domainDLQNotificationWebhookURL",
+	DomainDLQMergeTimeout:                       "frontend.domainDLQMergeTimeout",
+	DomainDLQReadMaxRetries:                     "frontend.domainDLQReadMaxRetries",
+	DomainDLQMergeCheckpointGranularity:         "frontend.domainDLQMergeCheckpointGranularity",
+	DomainDLQMergeParallelism:                   "frontend.domainDLQMergeParallelism",
+	DomainDLQInvalidMessagePolicy:               "frontend.domainDLQInvalidMessagePolicy",
+	DomainDLQMergeBackpressureMaxAttempts:       "frontend.domainDLQMergeBackpressureMaxAttempts",
+	DomainDLQGroupedMergeEnabled:                "frontend.domainDLQGroupedMergeEnabled",
+	DomainDLQExecutionJournalEnabled:            "frontend.domainDLQExecutionJournalEnabled",
+	DomainDLQVersionedAckLevelEnabled:           "frontend.domainDLQVersionedAckLevelEnabled",
+	DomainDLQAckLevelMaxConflictRetries:         "frontend.domainDLQAckLevelMaxConflictRetries",
+	DomainDLQArchivalEnabled:                    "frontend.domainDLQArchivalEnabled",
+	DomainDLQArchivalURI:                        "frontend.domainDLQArchivalURI",
+	DomainDLQGrowthRateSampleInterval:           "frontend.domainDLQGrowthRateSampleInterval",
+	DomainDLQMaxGrowthRate:                      "frontend.domainDLQMaxGrowthRate",
 	FrontendErrorInjectionRate:                  "frontend.errorInjectionRate",
 	FrontendEmitSignalNameMetricsTag:            "frontend.emitSignalNameMetricsTag",
 	// matching settings
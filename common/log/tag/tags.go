@@ -704,6 +704,86 @@ func TokenLastEventVersion(version int64) Tag {
 	return newInt64("xdc-token-last-event-version", version)
 }
 
+// ReplicationTaskType returns tag for ReplicationTaskType
+func ReplicationTaskType(taskType string) Tag {
+	return newStringTag("xdc-replication-task-type", taskType)
+}
+
+// DLQAckLevel returns tag for DLQAckLevel
+func DLQAckLevel(ackLevel int64) Tag {
+	return newInt64("xdc-dlq-ack-level", ackLevel)
+}
+
+// DLQPreviousAckLevel returns tag for DLQPreviousAckLevel
+func DLQPreviousAckLevel(ackLevel int64) Tag {
+	return newInt64("xdc-dlq-previous-ack-level", ackLevel)
+}
+
+// DLQAckLevelOverrideReason returns tag for DLQAckLevelOverrideReason
+func DLQAckLevelOverrideReason(reason string) Tag {
+	return newStringTag("xdc-dlq-ack-level-override-reason", reason)
+}
+
+// DLQLastMessageID returns tag for DLQLastMessageID
+func DLQLastMessageID(lastMessageID int64) Tag {
+	return newInt64("xdc-dlq-last-message-id", lastMessageID)
+}
+
+// DLQCircuitBreakerState returns tag for DLQCircuitBreakerState
+func DLQCircuitBreakerState(state string) Tag {
+	return newStringTag("xdc-dlq-circuit-breaker-state", state)
+}
+
+// DLQCircuitBreakerPreviousState returns tag for DLQCircuitBreakerPreviousState
+func DLQCircuitBreakerPreviousState(state string) Tag {
+	return newStringTag("xdc-dlq-circuit-breaker-previous-state", state)
+}
+
+// DLQMergeTraceID returns tag for DLQMergeTraceID
+func DLQMergeTraceID(traceID string) Tag {
+	return newStringTag("xdc-dlq-merge-trace-id", traceID)
+}
+
+// DLQMessageSizeBytes returns tag for DLQMessageSizeBytes
+func DLQMessageSizeBytes(size int) Tag {
+	return newInt("xdc-dlq-message-size-bytes", size)
+}
+
+// DLQLargeMessageThresholdBytes returns tag for DLQLargeMessageThresholdBytes
+func DLQLargeMessageThresholdBytes(threshold int) Tag {
+	return newInt("xdc-dlq-large-message-threshold-bytes", threshold)
+}
+
+// DLQSize returns tag for DLQSize
+func DLQSize(size int64) Tag {
+	return newInt64("xdc-dlq-size", size)
+}
+
+// DLQAckLevelStalledDuration returns tag for DLQAckLevelStalledDuration
+func DLQAckLevelStalledDuration(duration time.Duration) Tag {
+	return newDurationTag("xdc-dlq-ack-level-stalled-duration", duration)
+}
+
+// DLQEventType returns tag for DLQEventType
+func DLQEventType(eventType string) Tag {
+	return newStringTag("xdc-dlq-event-type", eventType)
+}
+
+// DLQCorrelationID returns tag for DLQCorrelationID
+func DLQCorrelationID(correlationID string) Tag {
+	return newStringTag("xdc-dlq-correlation-id", correlationID)
+}
+
+// DLQGrowthRate returns tag for DLQGrowthRate
+func DLQGrowthRate(rate float64) Tag {
+	return newFloat64("xdc-dlq-growth-rate", rate)
+}
+
+// DLQMaxGrowthRate returns tag for DLQMaxGrowthRate
+func DLQMaxGrowthRate(rate float64) Tag {
+	return newFloat64("xdc-dlq-max-growth-rate", rate)
+}
+
 ///////////////////  Archival tags defined here: archival- ///////////////////
 // archival request tags
 
@@ -255,18 +255,34 @@ var (
 	StoreOperationGetHistoryTree            = storeOperation("get-history-tree")
 	StoreOperationGetAllHistoryTreeBranches = storeOperation("get-all-history-tree-branches")
 
-	StoreOperationEnqueueMessage             = storeOperation("enqueue-message")
-	StoreOperationReadMessages               = storeOperation("read-messages")
-	StoreOperationUpdateAckLevel             = storeOperation("update-ack-level")
-	StoreOperationGetAckLevels               = storeOperation("get-ack-levels")
-	StoreOperationDeleteMessagesBefore       = storeOperation("delete-messages-before")
-	StoreOperationEnqueueMessageToDLQ        = storeOperation("enqueue-message-to-dlq")
-	StoreOperationReadMessagesFromDLQ        = storeOperation("read-messages-from-dlq")
-	StoreOperationRangeDeleteMessagesFromDLQ = storeOperation("range-delete-messages-from-dlq")
-	StoreOperationUpdateDLQAckLevel          = storeOperation("update-dlq-ack-level")
-	StoreOperationGetDLQAckLevels            = storeOperation("get-dlq-ack-levels")
-	StoreOperationGetDLQSize                 = storeOperation("get-dlq-size")
-	StoreOperationDeleteMessageFromDLQ       = storeOperation("delete-message-from-dlq")
+	StoreOperationEnqueueMessage                     = storeOperation("enqueue-message")
+	StoreOperationReadMessages                       = storeOperation("read-messages")
+	StoreOperationUpdateAckLevel                     = storeOperation("update-ack-level")
+	StoreOperationGetAckLevels                       = storeOperation("get-ack-levels")
+	StoreOperationDeleteMessagesBefore               = storeOperation("delete-messages-before")
+	StoreOperationEnqueueMessageToDLQ                = storeOperation("enqueue-message-to-dlq")
+	StoreOperationEnqueueMessagesToDLQ               = storeOperation("enqueue-messages-to-dlq")
+	StoreOperationReadMessagesFromDLQ                = storeOperation("read-messages-from-dlq")
+	StoreOperationReadMessagesFromDLQByDomain        = storeOperation("read-messages-from-dlq-by-domain")
+	StoreOperationReadMessagesFromDLQByTimeRange     = storeOperation("read-messages-from-dlq-by-time-range")
+	StoreOperationRangeDeleteMessagesFromDLQ         = storeOperation("range-delete-messages-from-dlq")
+	StoreOperationRangeDeleteMessagesFromDLQByDomain = storeOperation("range-delete-messages-from-dlq-by-domain")
+	StoreOperationRangeSoftDeleteMessagesFromDLQ     = storeOperation("range-soft-delete-messages-from-dlq")
+	StoreOperationDeleteSoftDeletedMessagesFromDLQ   = storeOperation("delete-soft-deleted-messages-from-dlq")
+	StoreOperationUpdateDLQAckLevel                  = storeOperation("update-dlq-ack-level")
+	StoreOperationCompareAndSwapDLQAckLevel          = storeOperation("compare-and-swap-dlq-ack-level")
+	StoreOperationUpdateDLQAckLevelWithVersion       = storeOperation("update-dlq-ack-level-with-version")
+	StoreOperationGetDLQAckLevels                    = storeOperation("get-dlq-ack-levels")
+	StoreOperationGetDLQAckLevelsWithVersion         = storeOperation("get-dlq-ack-levels-with-version")
+	StoreOperationGetDLQSize                         = storeOperation("get-dlq-size")
+	StoreOperationGetDLQMessageTypeHistogram         = storeOperation("get-dlq-message-type-histogram")
+	StoreOperationUpdateDLQMessageAttempts           = storeOperation("update-dlq-message-attempts")
+	StoreOperationUpdateDLQMessagePayload            = storeOperation("update-dlq-message-payload")
+	StoreOperationUpdateDLQMergeToken                = storeOperation("UpdateDLQMergeToken")
+	StoreOperationGetDLQMergeTokens                  = storeOperation("GetDLQMergeTokens")
+	StoreOperationInsertDLQMergeRecord               = storeOperation("insert-dlq-merge-record")
+	StoreOperationGetDLQMergeHistory                 = storeOperation("get-dlq-merge-history")
+	StoreOperationDeleteMessageFromDLQ               = storeOperation("delete-message-from-dlq")
 
 	StoreOperationFetchDynamicConfig  = storeOperation("fetch-dynamic-config")
 	StoreOperationUpdateDynamicConfig = storeOperation("update-dynamic-config")
@@ -291,6 +307,10 @@ var (
 	AdminClientOperationCountDLQMessages                  = clientOperation("admin-count-dlq-messsages")
 	AdminClientOperationReadDLQMessages                   = clientOperation("admin-read-dlq-messsages")
 	AdminClientOperationPurgeDLQMessages                  = clientOperation("admin-purge-dlq-messsages")
+	AdminClientOperationForceUpdateDLQAckLevel            = clientOperation("admin-force-update-dlq-ack-level")
+	AdminClientOperationPauseDLQProcessing                = clientOperation("admin-pause-dlq-processing")
+	AdminClientOperationResumeDLQProcessing               = clientOperation("admin-resume-dlq-processing")
+	AdminClientOperationListDLQConsumers                  = clientOperation("admin-list-dlq-consumers")
 	AdminClientOperationMergeDLQMessages                  = clientOperation("admin-merge-dlq-messsages")
 	AdminClientOperationRefreshWorkflowTasks              = clientOperation("admin-refresh-wf-tasks")
 	AdminClientOperationResendReplicationTasks            = clientOperation("admin-resend-replication-tasks")
@@ -273,26 +273,58 @@ const (
 	PersistenceEnqueueMessageScope
 	// PersistenceEnqueueMessageToDLQScope tracks Enqueue DLQ calls made by service to persistence layer
 	PersistenceEnqueueMessageToDLQScope
+	// PersistenceEnqueueMessagesToDLQScope tracks EnqueueMessagesToDLQ calls made by service to persistence layer
+	PersistenceEnqueueMessagesToDLQScope
 	// PersistenceReadQueueMessagesScope tracks ReadMessages calls made by service to persistence layer
 	PersistenceReadQueueMessagesScope
 	// PersistenceReadQueueMessagesFromDLQScope tracks ReadMessagesFromDLQ calls made by service to persistence layer
 	PersistenceReadQueueMessagesFromDLQScope
+	// PersistenceReadQueueMessagesFromDLQByDomainScope tracks ReadMessagesFromDLQByDomain calls made by service to persistence layer
+	PersistenceReadQueueMessagesFromDLQByDomainScope
+	// PersistenceReadQueueMessagesFromDLQByTimeRangeScope tracks ReadMessagesFromDLQByTimeRange calls made by service to persistence layer
+	PersistenceReadQueueMessagesFromDLQByTimeRangeScope
 	// PersistenceDeleteQueueMessagesScope tracks DeleteMessages calls made by service to persistence layer
 	PersistenceDeleteQueueMessagesScope
 	// PersistenceDeleteQueueMessageFromDLQScope tracks DeleteMessageFromDLQ calls made by service to persistence layer
 	PersistenceDeleteQueueMessageFromDLQScope
 	// PersistenceRangeDeleteMessagesFromDLQScope tracks RangeDeleteMessagesFromDLQ calls made by service to persistence layer
 	PersistenceRangeDeleteMessagesFromDLQScope
+	// PersistenceRangeDeleteMessagesFromDLQByDomainScope tracks RangeDeleteMessagesFromDLQByDomain calls made by service to persistence layer
+	PersistenceRangeDeleteMessagesFromDLQByDomainScope
+	// PersistenceRangeSoftDeleteMessagesFromDLQScope tracks RangeSoftDeleteMessagesFromDLQ calls made by service to persistence layer
+	PersistenceRangeSoftDeleteMessagesFromDLQScope
+	// PersistenceDeleteSoftDeletedMessagesFromDLQScope tracks DeleteSoftDeletedMessagesFromDLQ calls made by service to persistence layer
+	PersistenceDeleteSoftDeletedMessagesFromDLQScope
 	// PersistenceUpdateAckLevelScope tracks UpdateAckLevel calls made by service to persistence layer
 	PersistenceUpdateAckLevelScope
 	// PersistenceGetAckLevelScope tracks GetAckLevel calls made by service to persistence layer
 	PersistenceGetAckLevelScope
 	// PersistenceUpdateDLQAckLevelScope tracks UpdateDLQAckLevel calls made by service to persistence layer
 	PersistenceUpdateDLQAckLevelScope
+	// PersistenceCompareAndSwapDLQAckLevelScope tracks CompareAndSwapDLQAckLevel calls made by service to persistence layer
+	PersistenceCompareAndSwapDLQAckLevelScope
+	// PersistenceUpdateDLQAckLevelWithVersionScope tracks UpdateDLQAckLevelWithVersion calls made by service to persistence layer
+	PersistenceUpdateDLQAckLevelWithVersionScope
 	// PersistenceGetDLQAckLevelScope tracks GetDLQAckLevel calls made by service to persistence layer
 	PersistenceGetDLQAckLevelScope
+	// PersistenceGetDLQAckLevelsWithVersionScope tracks GetDLQAckLevelsWithVersion calls made by service to persistence layer
+	PersistenceGetDLQAckLevelsWithVersionScope
 	// PersistenceGetDLQSizeScope tracks GetDLQSize calls made by service to persistence layer
 	PersistenceGetDLQSizeScope
+	// PersistenceGetDLQMessageTypeHistogramScope tracks GetDLQMessageTypeHistogram calls made by service to persistence layer
+	PersistenceGetDLQMessageTypeHistogramScope
+	// PersistenceUpdateDLQMessageAttemptsScope tracks UpdateDLQMessageAttempts calls made by service to persistence layer
+	PersistenceUpdateDLQMessageAttemptsScope
+	// PersistenceUpdateDLQMessagePayloadScope tracks UpdateDLQMessagePayload calls made by service to persistence layer
+	PersistenceUpdateDLQMessagePayloadScope
+	// PersistenceUpdateDLQMergeTokenScope tracks UpdateDLQMergeToken calls made by service to persistence layer
+	PersistenceUpdateDLQMergeTokenScope
+	// PersistenceGetDLQMergeTokensScope tracks GetDLQMergeTokens calls made by service to persistence layer
+	PersistenceGetDLQMergeTokensScope
+	// PersistenceInsertDLQMergeRecordScope tracks InsertDLQMergeRecord calls made by service to persistence layer
+	PersistenceInsertDLQMergeRecordScope
+	// PersistenceGetDLQMergeHistoryScope tracks GetDLQMergeHistory calls made by service to persistence layer
+	PersistenceGetDLQMergeHistoryScope
 	// PersistenceFetchDynamicConfigScope tracks FetchDynamicConfig calls made by service to persistence layer
 	PersistenceFetchDynamicConfigScope
 	// PersistenceUpdateDynamicConfigScope tracks UpdateDynamicConfig calls made by service to persistence layer
@@ -521,6 +553,14 @@ const (
 	AdminClientReadDLQMessagesScope
 	// AdminClientPurgeDLQMessagesScope tracks RPC calls to admin service
 	AdminClientPurgeDLQMessagesScope
+	// AdminClientForceUpdateDLQAckLevelScope tracks RPC calls to admin service
+	AdminClientForceUpdateDLQAckLevelScope
+	// AdminClientPauseDLQProcessingScope tracks RPC calls to admin service
+	AdminClientPauseDLQProcessingScope
+	// AdminClientResumeDLQProcessingScope tracks RPC calls to admin service
+	AdminClientResumeDLQProcessingScope
+	// AdminClientListDLQConsumersScope tracks RPC calls to admin service
+	AdminClientListDLQConsumersScope
 	// AdminClientMergeDLQMessagesScope tracks RPC calls to admin service
 	AdminClientMergeDLQMessagesScope
 	// AdminClientRefreshWorkflowTasksScope tracks RPC calls to admin service
@@ -765,6 +805,14 @@ const (
 	AdminReadDLQMessagesScope
 	// AdminPurgeDLQMessagesScope is the metric scope for admin.AdminPurgeDLQMessagesScope
 	AdminPurgeDLQMessagesScope
+	// AdminForceUpdateDLQAckLevelScope is the metric scope for admin.AdminForceUpdateDLQAckLevelScope
+	AdminForceUpdateDLQAckLevelScope
+	// AdminPauseDLQProcessingScope is the metric scope for admin.AdminPauseDLQProcessingScope
+	AdminPauseDLQProcessingScope
+	// AdminResumeDLQProcessingScope is the metric scope for admin.AdminResumeDLQProcessingScope
+	AdminResumeDLQProcessingScope
+	// AdminListDLQConsumersScope is the metric scope for admin.AdminListDLQConsumersScope
+	AdminListDLQConsumersScope
 	// AdminMergeDLQMessagesScope is the metric scope for admin.AdminMergeDLQMessagesScope
 	AdminMergeDLQMessagesScope
 	// AdminDescribeShardDistributionScope is the metric scope for admin.DescribeShardDistribution
@@ -1294,16 +1342,32 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceGetAllHistoryTreeBranchesScope:                {operation: "GetAllHistoryTreeBranches"},
 		PersistenceEnqueueMessageScope:                           {operation: "EnqueueMessage"},
 		PersistenceEnqueueMessageToDLQScope:                      {operation: "EnqueueMessageToDLQ"},
+		PersistenceEnqueueMessagesToDLQScope:                     {operation: "EnqueueMessagesToDLQ"},
 		PersistenceReadQueueMessagesScope:                        {operation: "ReadQueueMessages"},
 		PersistenceReadQueueMessagesFromDLQScope:                 {operation: "ReadQueueMessagesFromDLQ"},
+		PersistenceReadQueueMessagesFromDLQByDomainScope:         {operation: "ReadQueueMessagesFromDLQByDomain"},
+		PersistenceReadQueueMessagesFromDLQByTimeRangeScope:      {operation: "ReadQueueMessagesFromDLQByTimeRange"},
 		PersistenceDeleteQueueMessagesScope:                      {operation: "DeleteQueueMessages"},
 		PersistenceDeleteQueueMessageFromDLQScope:                {operation: "DeleteQueueMessageFromDLQ"},
 		PersistenceRangeDeleteMessagesFromDLQScope:               {operation: "RangeDeleteMessagesFromDLQ"},
+		PersistenceRangeDeleteMessagesFromDLQByDomainScope:       {operation: "RangeDeleteMessagesFromDLQByDomain"},
+		PersistenceRangeSoftDeleteMessagesFromDLQScope:           {operation: "RangeSoftDeleteMessagesFromDLQ"},
+		PersistenceDeleteSoftDeletedMessagesFromDLQScope:         {operation: "DeleteSoftDeletedMessagesFromDLQ"},
 		PersistenceUpdateAckLevelScope:                           {operation: "UpdateAckLevel"},
 		PersistenceGetAckLevelScope:                              {operation: "GetAckLevel"},
 		PersistenceUpdateDLQAckLevelScope:                        {operation: "UpdateDLQAckLevel"},
+		PersistenceCompareAndSwapDLQAckLevelScope:                {operation: "CompareAndSwapDLQAckLevel"},
+		PersistenceUpdateDLQAckLevelWithVersionScope:             {operation: "UpdateDLQAckLevelWithVersion"},
 		PersistenceGetDLQAckLevelScope:                           {operation: "GetDLQAckLevel"},
+		PersistenceGetDLQAckLevelsWithVersionScope:               {operation: "GetDLQAckLevelsWithVersion"},
 		PersistenceGetDLQSizeScope:                               {operation: "GetDLQSize"},
+		PersistenceGetDLQMessageTypeHistogramScope:               {operation: "GetDLQMessageTypeHistogram"},
+		PersistenceUpdateDLQMessageAttemptsScope:                 {operation: "UpdateDLQMessageAttempts"},
+		PersistenceUpdateDLQMessagePayloadScope:                  {operation: "UpdateDLQMessagePayload"},
+		PersistenceUpdateDLQMergeTokenScope:                      {operation: "UpdateDLQMergeToken"},
+		PersistenceGetDLQMergeTokensScope:                        {operation: "GetDLQMergeTokens"},
+		PersistenceInsertDLQMergeRecordScope:                     {operation: "InsertDLQMergeRecord"},
+		PersistenceGetDLQMergeHistoryScope:                       {operation: "GetDLQMergeHistory"},
 		PersistenceFetchDynamicConfigScope:                       {operation: "FetchDynamicConfig"},
 		PersistenceUpdateDynamicConfigScope:                      {operation: "UpdateDynamicConfig"},
 
@@ -1423,6 +1487,10 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		AdminClientCountDLQMessagesScope:                      {operation: "AdminClientCountDLQMessages", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
 		AdminClientReadDLQMessagesScope:                       {operation: "AdminClientReadDLQMessages", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
 		AdminClientPurgeDLQMessagesScope:                      {operation: "AdminClientPurgeDLQMessages", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientForceUpdateDLQAckLevelScope:                {operation: "AdminClientForceUpdateDLQAckLevel", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientPauseDLQProcessingScope:                    {operation: "AdminClientPauseDLQProcessing", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientResumeDLQProcessingScope:                   {operation: "AdminClientResumeDLQProcessing", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientListDLQConsumersScope:                      {operation: "AdminClientListDLQConsumers", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
 		AdminClientMergeDLQMessagesScope:                      {operation: "AdminClientMergeDLQMessages", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
 		AdminClientGetCrossClusterTasksScope:                  {operation: "AdminClientGetCrossClusterTasks", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
 		AdminClientRespondCrossClusterTasksCompletedScope:     {operation: "AdminClientRespondCrossClusterTasksCompleted", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
@@ -1525,6 +1593,10 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		AdminCountDLQMessagesScope:                  {operation: "AdminCountDLQMessages"},
 		AdminReadDLQMessagesScope:                   {operation: "AdminReadDLQMessages"},
 		AdminPurgeDLQMessagesScope:                  {operation: "AdminPurgeDLQMessages"},
+		AdminForceUpdateDLQAckLevelScope:            {operation: "AdminForceUpdateDLQAckLevel"},
+		AdminPauseDLQProcessingScope:                {operation: "AdminPauseDLQProcessing"},
+		AdminResumeDLQProcessingScope:               {operation: "AdminResumeDLQProcessing"},
+		AdminListDLQConsumersScope:                  {operation: "AdminListDLQConsumers"},
 		AdminMergeDLQMessagesScope:                  {operation: "AdminMergeDLQMessages"},
 		AdminDescribeHistoryHostScope:               {operation: "DescribeHistoryHost"},
 		AdminDescribeShardDistributionScope:         {operation: "AdminShardList"},
@@ -1917,6 +1989,33 @@ const (
 
 	DomainReplicationQueueSizeGauge
 	DomainReplicationQueueSizeErrorCount
+	DomainReplicationDLQPoisonedMessageCount
+	DomainReplicationDLQMessageAge
+	DomainReplicationDLQDuplicateSkippedCount
+	DomainReplicationDLQSchemaVersion
+	DomainReplicationDLQMessageSize
+	DomainReplicationDLQExpiredMessageCount
+	DomainReplicationDLQAckLevelStalledCount
+	DomainReplicationDLQFilteredMessageCount
+	DomainReplicationDLQNackedMessageCount
+	DomainReplicationDLQMessageTypeCount
+	DomainReplicationDLQNotificationFailedCount
+	DomainReplicationDLQInvalidMessageCount
+	DomainReplicationDLQMergeBackpressureActive
+	DomainReplicationDLQObsoleteSkippedCount
+	DomainReplicationDLQAckVersionConflicts
+	DomainReplicationDLQExecuteLatency
+	DomainReplicationDLQExecuteErrors
+	DomainReplicationDLQGrowthRate
+	DomainReplicationDLQGrowthRateExceeded
+	DomainReplicationDLQStateTransitions
+	DomainReplicationDLQEnqueueDuplicateCount
+	DomainReplicationDLQEnqueueDedupErrors
+	DomainReplicationDLQBreakerOpened
+	DomainReplicationDLQBreakerClosed
+	DomainReplicationDLQBreakerHalfOpen
+	DomainReplicationDLQBreakerFailureRate
+	DomainReplicationDLQReadPageLatency
 
 	ParentClosePolicyProcessorSuccess
 	ParentClosePolicyProcessorFailures
@@ -2466,12 +2565,39 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		CadenceErrRemoteSyncMatchFailedPerTaskListCounter: {
 			metricName: "cadence_errors_remote_syncmatch_failed_per_tl", metricRollupName: "cadence_errors_remote_syncmatch_failed", metricType: Counter,
 		},
-		CadenceShardSuccessGauge:             {metricName: "cadence_shard_success", metricType: Gauge},
-		CadenceShardFailureGauge:             {metricName: "cadence_shard_failure", metricType: Gauge},
-		DomainReplicationQueueSizeGauge:      {metricName: "domain_replication_queue_size", metricType: Gauge},
-		DomainReplicationQueueSizeErrorCount: {metricName: "domain_replication_queue_failed", metricType: Counter},
-		ParentClosePolicyProcessorSuccess:    {metricName: "parent_close_policy_processor_requests", metricType: Counter},
-		ParentClosePolicyProcessorFailures:   {metricName: "parent_close_policy_processor_errors", metricType: Counter},
+		CadenceShardSuccessGauge:                    {metricName: "cadence_shard_success", metricType: Gauge},
+		CadenceShardFailureGauge:                    {metricName: "cadence_shard_failure", metricType: Gauge},
+		DomainReplicationQueueSizeGauge:             {metricName: "domain_replication_queue_size", metricType: Gauge},
+		DomainReplicationQueueSizeErrorCount:        {metricName: "domain_replication_queue_failed", metricType: Counter},
+		DomainReplicationDLQPoisonedMessageCount:    {metricName: "domain_replication_dlq_poisoned_message", metricType: Counter},
+		DomainReplicationDLQMessageAge:              {metricName: "dlq_message_age", metricType: Histogram, buckets: DLQMessageAgeBuckets},
+		DomainReplicationDLQDuplicateSkippedCount:   {metricName: "dlq_duplicate_skipped", metricType: Counter},
+		DomainReplicationDLQSchemaVersion:           {metricName: "dlq_schema_version", metricType: Histogram, buckets: DLQSchemaVersionBuckets},
+		DomainReplicationDLQMessageSize:             {metricName: "dlq_message_size_bytes", metricType: Histogram, buckets: DLQMessageSizeBuckets},
+		DomainReplicationDLQExpiredMessageCount:     {metricName: "dlq_expired_messages", metricType: Counter},
+		DomainReplicationDLQAckLevelStalledCount:    {metricName: "dlq_ack_level_stalled", metricType: Counter},
+		DomainReplicationDLQFilteredMessageCount:    {metricName: "dlq_filtered_messages", metricType: Counter},
+		DomainReplicationDLQNackedMessageCount:      {metricName: "dlq_nacked_messages", metricType: Counter},
+		DomainReplicationDLQMessageTypeCount:        {metricName: "dlq_message_type_count", metricType: Gauge},
+		DomainReplicationDLQNotificationFailedCount: {metricName: "dlq_notification_failed", metricType: Counter},
+		DomainReplicationDLQInvalidMessageCount:     {metricName: "dlq_invalid_messages", metricType: Counter},
+		DomainReplicationDLQMergeBackpressureActive: {metricName: "dlq_merge_backpressure_active", metricType: Gauge},
+		DomainReplicationDLQObsoleteSkippedCount:    {metricName: "dlq_obsolete_skipped", metricType: Counter},
+		DomainReplicationDLQAckVersionConflicts:     {metricName: "dlq_ack_level_version_conflicts", metricType: Counter},
+		DomainReplicationDLQExecuteLatency:          {metricName: "dlq_execute_latency", metricType: Histogram, buckets: DLQExecuteLatencyBuckets},
+		DomainReplicationDLQExecuteErrors:           {metricName: "dlq_execute_errors", metricType: Counter},
+		DomainReplicationDLQGrowthRate:              {metricName: "dlq_growth_rate", metricType: Gauge},
+		DomainReplicationDLQGrowthRateExceeded:      {metricName: "dlq_growth_rate_exceeded", metricType: Counter},
+		DomainReplicationDLQStateTransitions:        {metricName: "dlq_state_transitions", metricType: Counter},
+		DomainReplicationDLQEnqueueDuplicateCount:   {metricName: "dlq_enqueue_duplicates", metricType: Counter},
+		DomainReplicationDLQEnqueueDedupErrors:      {metricName: "dlq_enqueue_deduplication_errors", metricType: Counter},
+		DomainReplicationDLQBreakerOpened:           {metricName: "dlq_circuit_breaker_opened", metricType: Counter},
+		DomainReplicationDLQBreakerClosed:           {metricName: "dlq_circuit_breaker_closed", metricType: Counter},
+		DomainReplicationDLQBreakerHalfOpen:         {metricName: "dlq_circuit_breaker_half_open", metricType: Counter},
+		DomainReplicationDLQBreakerFailureRate:      {metricName: "dlq_circuit_breaker_failure_rate", metricType: Gauge},
+		DomainReplicationDLQReadPageLatency:         {metricName: "dlq_read_page_latency", metricType: Histogram, buckets: DLQReadPageLatencyBuckets},
+		ParentClosePolicyProcessorSuccess:           {metricName: "parent_close_policy_processor_requests", metricType: Counter},
+		ParentClosePolicyProcessorFailures:          {metricName: "parent_close_policy_processor_errors", metricType: Counter},
 	},
 	History: {
 		TaskRequests:             {metricName: "task_requests", metricType: Counter},
@@ -2883,6 +3009,21 @@ var PersistenceLatencyBuckets = tally.DurationBuckets([]time.Duration{
 	60 * time.Second,
 })
 
+// DLQMessageAgeBuckets contains duration buckets for measuring how long messages stay in a DLQ
+var DLQMessageAgeBuckets = tally.MustMakeExponentialDurationBuckets(time.Second, 2, 22)
+
+// DLQSchemaVersionBuckets contains value buckets for the schema versions of messages read from a DLQ
+var DLQSchemaVersionBuckets = tally.MustMakeLinearValueBuckets(0, 1, 10)
+
+// DLQExecuteLatencyBuckets contains duration buckets for measuring how long executing a message read from a DLQ takes
+var DLQExecuteLatencyBuckets = tally.MustMakeExponentialDurationBuckets(time.Millisecond, 2, 16)
+
+// DLQReadPageLatencyBuckets contains duration buckets for measuring how long reading a page of messages from a DLQ takes
+var DLQReadPageLatencyBuckets = tally.MustMakeExponentialDurationBuckets(time.Millisecond, 2, 16)
+
+// DLQMessageSizeBuckets contains value buckets for the serialized size in bytes of messages read from a DLQ
+var DLQMessageSizeBuckets = tally.MustMakeExponentialValueBuckets(256, 2, 16)
+
 // ErrorClass is an enum to help with classifying SLA vs. non-SLA errors (SLA = "service level agreement")
 type ErrorClass uint8
 
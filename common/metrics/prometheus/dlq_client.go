@@ -0,0 +1,265 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// Package prometheus exports the metrics of the domain DLQ handler to Prometheus
+// without going through tally, so they can be scraped with Prometheus naming conventions.
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/metrics"
+)
+
+type (
+	// dlqMetric describes how a domain DLQ metric is exported to Prometheus
+	dlqMetric struct {
+		name       string
+		help       string
+		metricType metrics.MetricType
+		buckets    []float64
+	}
+
+	dlqMetricsClient struct {
+		counters   map[int]*prom.CounterVec
+		gauges     map[int]*prom.GaugeVec
+		histograms map[int]*prom.HistogramVec
+		fallback   metrics.Client
+	}
+
+	dlqMetricsScope struct {
+		client   *dlqMetricsClient
+		labels   prom.Labels
+		fallback metrics.Scope
+	}
+)
+
+// dlqLabelsByTagKey maps the keys of the tags of domain DLQ metrics to their Prometheus labels,
+// tags with other keys are not exported
+var dlqLabelsByTagKey = map[string]string{
+	metrics.DomainTag("").Key():        "domain",
+	metrics.SourceClusterTag("").Key(): "source_cluster",
+	metrics.TaskTypeTag("").Key():      "task_type",
+}
+
+var dlqLabelNames = []string{"domain", "source_cluster", "task_type"}
+
+var dlqMetrics = map[int]dlqMetric{
+	metrics.DomainReplicationQueueSizeGauge: {
+		name:       "cadence_dlq_size",
+		help:       "Number of messages in the domain DLQ.",
+		metricType: metrics.Gauge,
+	},
+	metrics.DomainReplicationQueueSizeErrorCount: {
+		name:       "cadence_dlq_size_errors_total",
+		help:       "Number of failures to get the size of the domain DLQ.",
+		metricType: metrics.Counter,
+	},
+	metrics.DomainReplicationDLQPoisonedMessageCount: {
+		name:       "cadence_dlq_poisoned_messages_total",
+		help:       "Number of domain DLQ messages skipped after exhausting their merge attempts.",
+		metricType: metrics.Counter,
+	},
+	metrics.DomainReplicationDLQMessageAge: {
+		name:       "cadence_dlq_message_age_seconds",
+		help:       "Time domain DLQ messages spent in the DLQ until they were merged.",
+		metricType: metrics.Histogram,
+		buckets:    prom.ExponentialBuckets(1, 2, 22),
+	},
+	metrics.DomainReplicationDLQDuplicateSkippedCount: {
+		name:       "cadence_dlq_duplicate_skipped_total",
+		help:       "Number of duplicate domain DLQ messages skipped on merge.",
+		metricType: metrics.Counter,
+	},
+	metrics.DomainReplicationDLQSchemaVersion: {
+		name:       "cadence_dlq_schema_version",
+		help:       "Schema versions of the domain DLQ messages read.",
+		metricType: metrics.Histogram,
+		buckets:    prom.LinearBuckets(0, 1, 10),
+	},
+	metrics.DomainReplicationDLQMessageSize: {
+		name:       "cadence_dlq_message_size_bytes",
+		help:       "Serialized size of the domain DLQ messages read.",
+		metricType: metrics.Histogram,
+		buckets:    prom.ExponentialBuckets(256, 2, 16),
+	},
+	metrics.DomainReplicationDLQExpiredMessageCount: {
+		name:       "cadence_dlq_expired_messages_total",
+		help:       "Number of domain DLQ messages purged after their TTL.",
+		metricType: metrics.Counter,
+	},
+	metrics.DomainReplicationDLQAckLevelStalledCount: {
+		name:       "cadence_dlq_ack_level_stalled_total",
+		help:       "Number of times the domain DLQ ack level was found stalled.",
+		metricType: metrics.Counter,
+	},
+	metrics.DomainReplicationDLQFilteredMessageCount: {
+		name:       "cadence_dlq_filtered_messages_total",
+		help:       "Number of domain DLQ messages of filtered domains deleted on merge.",
+		metricType: metrics.Counter,
+	},
+	metrics.DomainReplicationDLQNackedMessageCount: {
+		name:       "cadence_dlq_nacked_messages_total",
+		help:       "Number of domain DLQ messages moved to the retry queue after a transient merge failure.",
+		metricType: metrics.Counter,
+	},
+}
+
+var _ metrics.Client = (*dlqMetricsClient)(nil)
+
+// NewDLQMetricsClient returns a metrics client exporting the domain DLQ metrics to the Prometheus registerer,
+// other metrics are reported to the fallback client, which may be nil to drop them.
+// It is meant to be injected into the domain DLQ handler in place of its tally metrics client.
+func NewDLQMetricsClient(registerer prom.Registerer, fallback metrics.Client) (metrics.Client, error) {
+	if fallback == nil {
+		fallback = metrics.NewNoopMetricsClient()
+	}
+	client := &dlqMetricsClient{
+		counters:   make(map[int]*prom.CounterVec),
+		gauges:     make(map[int]*prom.GaugeVec),
+		histograms: make(map[int]*prom.HistogramVec),
+		fallback:   fallback,
+	}
+	for idx, def := range dlqMetrics {
+		var collector prom.Collector
+		switch def.metricType {
+		case metrics.Counter:
+			vec := prom.NewCounterVec(prom.CounterOpts{Name: def.name, Help: def.help}, dlqLabelNames)
+			client.counters[idx] = vec
+			collector = vec
+		case metrics.Gauge:
+			vec := prom.NewGaugeVec(prom.GaugeOpts{Name: def.name, Help: def.help}, dlqLabelNames)
+			client.gauges[idx] = vec
+			collector = vec
+		case metrics.Histogram:
+			vec := prom.NewHistogramVec(prom.HistogramOpts{Name: def.name, Help: def.help, Buckets: def.buckets}, dlqLabelNames)
+			client.histograms[idx] = vec
+			collector = vec
+		}
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func (c *dlqMetricsClient) IncCounter(scope int, counter int) {
+	c.Scope(scope).IncCounter(counter)
+}
+
+func (c *dlqMetricsClient) AddCounter(scope int, counter int, delta int64) {
+	c.Scope(scope).AddCounter(counter, delta)
+}
+
+func (c *dlqMetricsClient) StartTimer(scope int, timer int) tally.Stopwatch {
+	return c.fallback.StartTimer(scope, timer)
+}
+
+func (c *dlqMetricsClient) RecordTimer(scope int, timer int, d time.Duration) {
+	c.fallback.RecordTimer(scope, timer, d)
+}
+
+func (c *dlqMetricsClient) RecordHistogramDuration(scope int, timer int, d time.Duration) {
+	c.Scope(scope).RecordHistogramDuration(timer, d)
+}
+
+func (c *dlqMetricsClient) UpdateGauge(scope int, gauge int, value float64) {
+	c.Scope(scope).UpdateGauge(gauge, value)
+}
+
+func (c *dlqMetricsClient) Scope(scope int, tags ...metrics.Tag) metrics.Scope {
+	s := &dlqMetricsScope{
+		client:   c,
+		labels:   prom.Labels{},
+		fallback: c.fallback.Scope(scope, tags...),
+	}
+	for _, label := range dlqLabelNames {
+		s.labels[label] = ""
+	}
+	s.addLabels(tags)
+	return s
+}
+
+func (s *dlqMetricsScope) IncCounter(counter int) {
+	s.AddCounter(counter, 1)
+}
+
+func (s *dlqMetricsScope) AddCounter(counter int, delta int64) {
+	if vec, ok := s.client.counters[counter]; ok {
+		vec.With(s.labels).Add(float64(delta))
+		return
+	}
+	s.fallback.AddCounter(counter, delta)
+}
+
+func (s *dlqMetricsScope) StartTimer(timer int) metrics.Stopwatch {
+	return s.fallback.StartTimer(timer)
+}
+
+func (s *dlqMetricsScope) RecordTimer(timer int, d time.Duration) {
+	s.fallback.RecordTimer(timer, d)
+}
+
+func (s *dlqMetricsScope) RecordHistogramDuration(timer int, d time.Duration) {
+	if vec, ok := s.client.histograms[timer]; ok {
+		vec.With(s.labels).Observe(d.Seconds())
+		return
+	}
+	s.fallback.RecordHistogramDuration(timer, d)
+}
+
+func (s *dlqMetricsScope) RecordHistogramValue(timer int, value float64) {
+	if vec, ok := s.client.histograms[timer]; ok {
+		vec.With(s.labels).Observe(value)
+		return
+	}
+	s.fallback.RecordHistogramValue(timer, value)
+}
+
+func (s *dlqMetricsScope) UpdateGauge(gauge int, value float64) {
+	if vec, ok := s.client.gauges[gauge]; ok {
+		vec.With(s.labels).Set(value)
+		return
+	}
+	s.fallback.UpdateGauge(gauge, value)
+}
+
+func (s *dlqMetricsScope) Tagged(tags ...metrics.Tag) metrics.Scope {
+	tagged := &dlqMetricsScope{
+		client:   s.client,
+		labels:   make(prom.Labels, len(s.labels)),
+		fallback: s.fallback.Tagged(tags...),
+	}
+	for label, value := range s.labels {
+		tagged.labels[label] = value
+	}
+	tagged.addLabels(tags)
+	return tagged
+}
+
+func (s *dlqMetricsScope) addLabels(tags []metrics.Tag) {
+	for _, tag := range tags {
+		if label, ok := dlqLabelsByTagKey[tag.Key()]; ok {
+			s.labels[label] = tag.Value()
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/metrics"
+)
+
+func TestDLQMetricsClient_Counter(t *testing.T) {
+	client, err := NewDLQMetricsClient(prom.NewRegistry(), nil)
+	require.NoError(t, err)
+
+	scope := client.Scope(metrics.DomainReplicationQueueScope, metrics.DomainTag("test-domain"), metrics.SourceClusterTag("cluster-b"))
+	scope.Tagged(metrics.TaskTypeTag("Domain")).IncCounter(metrics.DomainReplicationDLQPoisonedMessageCount)
+	scope.Tagged(metrics.TaskTypeTag("Domain")).AddCounter(metrics.DomainReplicationDLQPoisonedMessageCount, 2)
+	client.IncCounter(metrics.DomainReplicationQueueScope, metrics.DomainReplicationDLQAckLevelStalledCount)
+
+	poisoned := client.(*dlqMetricsClient).counters[metrics.DomainReplicationDLQPoisonedMessageCount]
+	assert.Equal(t, float64(3), testutil.ToFloat64(poisoned.With(prom.Labels{
+		"domain":         "test-domain",
+		"source_cluster": "cluster-b",
+		"task_type":      "Domain",
+	})))
+	assert.NoError(t, testutil.CollectAndCompare(
+		client.(*dlqMetricsClient).counters[metrics.DomainReplicationDLQAckLevelStalledCount],
+		strings.NewReader(`
+# HELP cadence_dlq_ack_level_stalled_total Number of times the domain DLQ ack level was found stalled.
+# TYPE cadence_dlq_ack_level_stalled_total counter
+cadence_dlq_ack_level_stalled_total{domain="",source_cluster="",task_type=""} 1
+`),
+	))
+}
+
+func TestDLQMetricsClient_GaugeAndHistogram(t *testing.T) {
+	registry := prom.NewRegistry()
+	client, err := NewDLQMetricsClient(registry, nil)
+	require.NoError(t, err)
+
+	client.UpdateGauge(metrics.DomainReplicationQueueScope, metrics.DomainReplicationQueueSizeGauge, 42)
+	client.Scope(metrics.DomainReplicationQueueScope).RecordHistogramDuration(metrics.DomainReplicationDLQMessageAge, 3*time.Second)
+	client.Scope(metrics.DomainReplicationQueueScope).RecordHistogramValue(metrics.DomainReplicationDLQMessageSize, 1000)
+
+	c := client.(*dlqMetricsClient)
+	assert.Equal(t, float64(42), testutil.ToFloat64(c.gauges[metrics.DomainReplicationQueueSizeGauge]))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	histograms := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if metric.GetHistogram() != nil {
+				histograms[family.GetName()] = metric.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"cadence_dlq_message_age_seconds": 3,
+		"cadence_dlq_message_size_bytes":  1000,
+	}, histograms)
+}
+
+func TestDLQMetricsClient_Fallback(t *testing.T) {
+	testScope := tally.NewTestScope("test", nil)
+	client, err := NewDLQMetricsClient(prom.NewRegistry(), metrics.NewClient(testScope, metrics.Frontend))
+	require.NoError(t, err)
+
+	client.IncCounter(metrics.DomainReplicationQueueScope, metrics.CadenceRequests)
+	client.IncCounter(metrics.DomainReplicationQueueScope, metrics.DomainReplicationDLQExpiredMessageCount)
+
+	counters := testScope.Snapshot().Counters()
+	var names []string
+	for _, counter := range counters {
+		names = append(names, counter.Name())
+	}
+	// only the metrics which are not exported to Prometheus are reported to the fallback client
+	assert.Contains(t, names, "test.cadence_requests")
+	assert.NotContains(t, names, "test.dlq_expired_messages")
+}
+
+func TestDLQMetricsClient_NamingConventions(t *testing.T) {
+	for idx, def := range dlqMetrics {
+		assert.True(t, strings.HasPrefix(def.name, "cadence_dlq_"), def.name)
+		if def.metricType == metrics.Counter {
+			assert.True(t, strings.HasSuffix(def.name, "_total"), def.name)
+		}
+		_, ok := metrics.MetricDefs[metrics.Common][idx]
+		if !ok {
+			_, ok = metrics.MetricDefs[metrics.Frontend][idx]
+		}
+		assert.True(t, ok, def.name)
+	}
+}
+
+func TestNewDLQMetricsClient_AlreadyRegistered(t *testing.T) {
+	registry := prom.NewRegistry()
+	_, err := NewDLQMetricsClient(registry, nil)
+	require.NoError(t, err)
+
+	_, err = NewDLQMetricsClient(registry, nil)
+	assert.Error(t, err)
+}
@@ -48,6 +48,10 @@ const (
 	transport              = "transport"
 	caller                 = "caller"
 	signalName             = "signalName"
+	taskType               = "taskType"
+	errorType              = "error_type"
+	dlqState               = "dlq_state"
+	destinationCluster     = "destination_cluster"
 
 	allValue     = "all"
 	unknownValue = "_unknown_"
@@ -108,6 +112,11 @@ func ActiveClusterTag(value string) Tag {
 	return metricWithUnknown(activeCluster, value)
 }
 
+// DestinationClusterTag returns a new destination cluster tag.
+func DestinationClusterTag(value string) Tag {
+	return metricWithUnknown(destinationCluster, value)
+}
+
 // TaskListTag returns a new task list tag.
 func TaskListTag(value string) Tag {
 	if len(value) == 0 {
@@ -136,6 +145,21 @@ func ActivityTypeTag(value string) Tag {
 	return metricWithUnknown(activityType, value)
 }
 
+// TaskTypeTag returns a new task type tag.
+func TaskTypeTag(value string) Tag {
+	return metricWithUnknown(taskType, value)
+}
+
+// ErrorTypeTag returns a new error type tag.
+func ErrorTypeTag(value string) Tag {
+	return metricWithUnknown(errorType, value)
+}
+
+// DLQStateTag returns a new domain DLQ handler state tag.
+func DLQStateTag(value string) Tag {
+	return metricWithUnknown(dlqState, value)
+}
+
 // DecisionTypeTag returns a new decision type tag.
 func DecisionTypeTag(value string) Tag {
 	return metricWithUnknown(decisionType, value)
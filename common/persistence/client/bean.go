@@ -49,6 +49,7 @@ type (
 
 		GetDomainReplicationQueueManager() persistence.QueueManager
 		SetDomainReplicationQueueManager(persistence.QueueManager)
+		GetDomainReplicationQueueShardManager(int) (persistence.QueueManager, error)
 
 		GetShardManager() persistence.ShardManager
 		SetShardManager(persistence.ShardManager)
@@ -73,9 +74,11 @@ type (
 		historyManager                persistence.HistoryManager
 		configStoreManager            persistence.ConfigStoreManager
 		executionManagerFactory       persistence.ExecutionManagerFactory
+		queueManagerFactory           persistence.DomainReplicationQueueManagerFactory
 
 		sync.RWMutex
-		shardIDToExecutionManager map[int]persistence.ExecutionManager
+		shardIDToExecutionManager              map[int]persistence.ExecutionManager
+		shardIDToDomainReplicationQueueManager map[int]persistence.QueueManager
 	}
 
 	// Params contains dependencies for persistence
@@ -144,6 +147,7 @@ func NewBeanFromFactory(
 		historyMgr,
 		configStoreMgr,
 		factory,
+		factory,
 	), nil
 }
 
@@ -157,6 +161,7 @@ func NewBean(
 	historyManager persistence.HistoryManager,
 	configStoreManager persistence.ConfigStoreManager,
 	executionManagerFactory persistence.ExecutionManagerFactory,
+	queueManagerFactory persistence.DomainReplicationQueueManagerFactory,
 ) *BeanImpl {
 	return &BeanImpl{
 		domainManager:                 domainManager,
@@ -167,8 +172,10 @@ func NewBean(
 		historyManager:                historyManager,
 		configStoreManager:            configStoreManager,
 		executionManagerFactory:       executionManagerFactory,
+		queueManagerFactory:           queueManagerFactory,
 
-		shardIDToExecutionManager: make(map[int]persistence.ExecutionManager),
+		shardIDToExecutionManager:              make(map[int]persistence.ExecutionManager),
+		shardIDToDomainReplicationQueueManager: make(map[int]persistence.QueueManager),
 	}
 }
 
@@ -252,6 +259,41 @@ func (s *BeanImpl) SetDomainReplicationQueueManager(
 	s.domainReplicationQueueManager = domainReplicationQueueManager
 }
 
+// GetDomainReplicationQueueShardManager gets the domain replication QueueManager of a shard of the domain replication DLQ,
+// shard 0 is the domain replication QueueManager
+func (s *BeanImpl) GetDomainReplicationQueueShardManager(
+	shardID int,
+) (persistence.QueueManager, error) {
+
+	if shardID == 0 {
+		return s.GetDomainReplicationQueueManager(), nil
+	}
+
+	s.RLock()
+	queueManager, ok := s.shardIDToDomainReplicationQueueManager[shardID]
+	if ok {
+		s.RUnlock()
+		return queueManager, nil
+	}
+	s.RUnlock()
+
+	s.Lock()
+	defer s.Unlock()
+
+	queueManager, ok = s.shardIDToDomainReplicationQueueManager[shardID]
+	if ok {
+		return queueManager, nil
+	}
+
+	queueManager, err := s.queueManagerFactory.NewDomainReplicationQueueShardManager(shardID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.shardIDToDomainReplicationQueueManager[shardID] = queueManager
+	return queueManager, nil
+}
+
 // GetShardManager get ShardManager
 func (s *BeanImpl) GetShardManager() persistence.ShardManager {
 
@@ -373,4 +415,7 @@ func (s *BeanImpl) Close() {
 	for _, executionMgr := range s.shardIDToExecutionManager {
 		executionMgr.Close()
 	}
+	for _, queueMgr := range s.shardIDToDomainReplicationQueueManager {
+		queueMgr.Close()
+	}
 }
@@ -173,6 +173,21 @@ func (mr *MockBeanMockRecorder) SetDomainReplicationQueueManager(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDomainReplicationQueueManager", reflect.TypeOf((*MockBean)(nil).SetDomainReplicationQueueManager), arg0)
 }
 
+// GetDomainReplicationQueueShardManager mocks base method
+func (m *MockBean) GetDomainReplicationQueueShardManager(arg0 int) (persistence.QueueManager, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomainReplicationQueueShardManager", arg0)
+	ret0, _ := ret[0].(persistence.QueueManager)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomainReplicationQueueShardManager indicates an expected call of GetDomainReplicationQueueShardManager
+func (mr *MockBeanMockRecorder) GetDomainReplicationQueueShardManager(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainReplicationQueueShardManager", reflect.TypeOf((*MockBean)(nil).GetDomainReplicationQueueShardManager), arg0)
+}
+
 // GetShardManager mocks base method
 func (m *MockBean) GetShardManager() persistence.ShardManager {
 	m.ctrl.T.Helper()
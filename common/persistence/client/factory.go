@@ -60,6 +60,8 @@ type (
 		NewVisibilityManager(params *Params, serviceConfig *service.Config) (p.VisibilityManager, error)
 		// NewDomainReplicationQueueManager returns a new queue for domain replication
 		NewDomainReplicationQueueManager() (p.QueueManager, error)
+		// NewDomainReplicationQueueShardManager returns a new queue for a shard of the domain replication DLQ
+		NewDomainReplicationQueueShardManager(shardID int) (p.QueueManager, error)
 		// NewConfigStoreManager returns a new config store manager
 		NewConfigStoreManager() (p.ConfigStoreManager, error)
 	}
@@ -354,8 +356,12 @@ func (f *factoryImpl) newDBVisibilityManager(
 }
 
 func (f *factoryImpl) NewDomainReplicationQueueManager() (p.QueueManager, error) {
+	return f.NewDomainReplicationQueueShardManager(0)
+}
+
+func (f *factoryImpl) NewDomainReplicationQueueShardManager(shardID int) (p.QueueManager, error) {
 	ds := f.datastores[storeTypeQueue]
-	store, err := ds.factory.NewQueue(p.DomainReplicationQueueType)
+	store, err := ds.factory.NewQueue(p.GetDomainReplicationQueueShardType(shardID))
 	if err != nil {
 		return nil, err
 	}
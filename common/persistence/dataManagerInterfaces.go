@@ -66,6 +66,10 @@ const (
 	DomainReplicationQueueType QueueType = iota + 1
 )
 
+// domainReplicationQueueShardTypeBase offsets the queue types of the domain replication DLQ shards
+// from the other queue types
+const domainReplicationQueueShardTypeBase QueueType = 1000
+
 // Create Workflow Execution Mode
 const (
 	// Fail if current record exists
@@ -1745,6 +1749,11 @@ type (
 		NewExecutionManager(shardID int) (ExecutionManager, error)
 	}
 
+	// DomainReplicationQueueManagerFactory creates an instance of QueueManager for a given shard of the domain replication DLQ
+	DomainReplicationQueueManagerFactory interface {
+		NewDomainReplicationQueueShardManager(shardID int) (QueueManager, error)
+	}
+
 	// TaskManager is used to manage tasks
 	TaskManager interface {
 		Closeable
@@ -1810,20 +1819,52 @@ type (
 		DeleteMessagesBefore(ctx context.Context, messageID int64) error
 		UpdateAckLevel(ctx context.Context, messageID int64, clusterName string) error
 		GetAckLevels(ctx context.Context) (map[string]int64, error)
-		EnqueueMessageToDLQ(ctx context.Context, messagePayload []byte) error
+		EnqueueMessageToDLQ(ctx context.Context, domainID string, taskType *int32, messagePayload []byte) error
+		EnqueueMessagesToDLQ(ctx context.Context, messages []*QueueMessage) error
 		ReadMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*QueueMessage, []byte, error)
+		ReadMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*QueueMessage, []byte, error)
+		ReadMessagesFromDLQByTimeRange(ctx context.Context, startTime time.Time, endTime time.Time, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*QueueMessage, []byte, error)
 		DeleteMessageFromDLQ(ctx context.Context, messageID int64) error
 		RangeDeleteMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64) error
+		RangeDeleteMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID int64, lastMessageID int64) error
+		RangeSoftDeleteMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64) error
+		DeleteSoftDeletedMessagesFromDLQ(ctx context.Context, deletedBefore time.Time) error
 		UpdateDLQAckLevel(ctx context.Context, messageID int64, clusterName string) error
+		CompareAndSwapDLQAckLevel(ctx context.Context, expectedMessageID int64, messageID int64, clusterName string) error
+		UpdateDLQAckLevelWithVersion(ctx context.Context, messageID int64, clusterName string, expectedVersion int64) error
 		GetDLQAckLevels(ctx context.Context) (map[string]int64, error)
+		GetDLQAckLevelsWithVersion(ctx context.Context) (map[string]int64, int64, error)
 		GetDLQSize(ctx context.Context) (int64, error)
+		GetDLQMessageTypeHistogram(ctx context.Context) (map[int32]int64, error)
+		UpdateDLQMessageAttempts(ctx context.Context, messageID int64, attempts int) error
+		UpdateDLQMessagePayload(ctx context.Context, messageID int64, payload []byte) error
+		UpdateDLQMergeToken(ctx context.Context, token string, clusterName string) error
+		GetDLQMergeTokens(ctx context.Context) (map[string]string, error)
+		InsertDLQMergeRecord(ctx context.Context, record *DLQMergeRecord) error
+		GetDLQMergeHistory(ctx context.Context, limit int) ([]*DLQMergeRecord, error)
 	}
 
 	// QueueMessage is the message that stores in the queue
 	QueueMessage struct {
-		ID        int64     `json:"message_id"`
-		QueueType QueueType `json:"queue_type"`
-		Payload   []byte    `json:"message_payload"`
+		ID         int64     `json:"message_id"`
+		QueueType  QueueType `json:"queue_type"`
+		Payload    []byte    `json:"message_payload"`
+		Attempts   int       `json:"attempts"`
+		EnqueuedAt time.Time `json:"enqueued_at"`
+		DomainID   string    `json:"domain_id"`
+		TaskType   *int32    `json:"task_type"`
+	}
+
+	// DLQMergeRecord is the record of one batch of merged DLQ messages
+	DLQMergeRecord struct {
+		MergedAt       time.Time
+		StartMessageID int64
+		EndMessageID   int64
+		MergedCount    int64
+		FailedCount    int64
+		Duration       time.Duration
+		TriggeredBy    string
+		TraceID        string
 	}
 
 	ConfigStoreManager interface {
@@ -2996,3 +3037,12 @@ func HasMoreRowsToDelete(rowsDeleted, batchSize int) bool {
 	}
 	return true
 }
+
+// GetDomainReplicationQueueShardType returns the queue type of a shard of the domain replication DLQ,
+// shard 0 is the domain replication queue so the DLQ messages enqueued before the DLQ was sharded stay readable
+func GetDomainReplicationQueueShardType(shardID int) QueueType {
+	if shardID == 0 {
+		return DomainReplicationQueueType
+	}
+	return domainReplicationQueueShardTypeBase + QueueType(shardID)
+}
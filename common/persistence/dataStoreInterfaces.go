@@ -200,20 +200,40 @@ type (
 		DeleteMessagesBefore(ctx context.Context, messageID int64) error
 		UpdateAckLevel(ctx context.Context, messageID int64, clusterName string) error
 		GetAckLevels(ctx context.Context) (map[string]int64, error)
-		EnqueueMessageToDLQ(ctx context.Context, messagePayload []byte) error
+		EnqueueMessageToDLQ(ctx context.Context, domainID string, taskType *int32, messagePayload []byte) error
+		EnqueueMessagesToDLQ(ctx context.Context, messages []*InternalQueueMessage) error
 		ReadMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*InternalQueueMessage, []byte, error)
+		ReadMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*InternalQueueMessage, []byte, error)
+		ReadMessagesFromDLQByTimeRange(ctx context.Context, startTime time.Time, endTime time.Time, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*InternalQueueMessage, []byte, error)
 		DeleteMessageFromDLQ(ctx context.Context, messageID int64) error
 		RangeDeleteMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64) error
+		RangeDeleteMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID int64, lastMessageID int64) error
+		RangeSoftDeleteMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64) error
+		DeleteSoftDeletedMessagesFromDLQ(ctx context.Context, deletedBefore time.Time) error
 		UpdateDLQAckLevel(ctx context.Context, messageID int64, clusterName string) error
+		CompareAndSwapDLQAckLevel(ctx context.Context, expectedMessageID int64, messageID int64, clusterName string) error
+		UpdateDLQAckLevelWithVersion(ctx context.Context, messageID int64, clusterName string, expectedVersion int64) error
 		GetDLQAckLevels(ctx context.Context) (map[string]int64, error)
+		GetDLQAckLevelsWithVersion(ctx context.Context) (map[string]int64, int64, error)
 		GetDLQSize(ctx context.Context) (int64, error)
+		GetDLQMessageTypeHistogram(ctx context.Context) (map[int32]int64, error)
+		UpdateDLQMessageAttempts(ctx context.Context, messageID int64, attempts int) error
+		UpdateDLQMessagePayload(ctx context.Context, messageID int64, payload []byte) error
+		UpdateDLQMergeToken(ctx context.Context, token string, clusterName string) error
+		GetDLQMergeTokens(ctx context.Context) (map[string]string, error)
+		InsertDLQMergeRecord(ctx context.Context, record *DLQMergeRecord) error
+		GetDLQMergeHistory(ctx context.Context, limit int) ([]*DLQMergeRecord, error)
 	}
 
 	// InternalQueueMessage is the message that stores in the queue
 	InternalQueueMessage struct {
-		ID        int64     `json:"message_id"`
-		QueueType QueueType `json:"queue_type"`
-		Payload   []byte    `json:"message_payload"`
+		ID         int64     `json:"message_id"`
+		QueueType  QueueType `json:"queue_type"`
+		Payload    []byte    `json:"message_payload"`
+		Attempts   int       `json:"attempts"`
+		EnqueuedAt time.Time `json:"enqueued_at"`
+		DomainID   string    `json:"domain_id"`
+		TaskType   *int32    `json:"task_type"`
 	}
 
 	// DataBlob represents a blob for any binary data.
@@ -23,6 +23,7 @@ package nosql
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
@@ -33,6 +34,9 @@ import (
 
 const (
 	emptyMessageID = -1
+	// mergeTokenUpdateMaxAttempts is the number of times a merge token update is retried when the DLQ metadata row
+	// is updated concurrently, the updates of the other keys and the ack levels are kept by re-applying the update
+	mergeTokenUpdateMaxAttempts = 5
 )
 
 type (
@@ -99,12 +103,14 @@ func (q *nosqlQueueStore) EnqueueMessage(
 		return err
 	}
 
-	_, err = q.tryEnqueue(ctx, q.queueType, lastMessageID+1, messagePayload)
+	_, err = q.tryEnqueue(ctx, q.queueType, lastMessageID+1, "", nil, messagePayload)
 	return err
 }
 
 func (q *nosqlQueueStore) EnqueueMessageToDLQ(
 	ctx context.Context,
+	domainID string,
+	taskType *int32,
 	messagePayload []byte,
 ) error {
 	// Use negative queue type as the dlq type
@@ -113,20 +119,62 @@ func (q *nosqlQueueStore) EnqueueMessageToDLQ(
 		return err
 	}
 
-	_, err = q.tryEnqueue(ctx, q.getDLQTypeFromQueueType(), lastMessageID+1, messagePayload)
+	_, err = q.tryEnqueue(ctx, q.getDLQTypeFromQueueType(), lastMessageID+1, domainID, taskType, messagePayload)
 	return err
 }
 
+func (q *nosqlQueueStore) EnqueueMessagesToDLQ(
+	ctx context.Context,
+	messages []*persistence.InternalQueueMessage,
+) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	// Use negative queue type as the dlq type
+	lastMessageID, err := q.getLastMessageID(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		return err
+	}
+
+	enqueuedAt := time.Now()
+	rows := make([]*nosqlplugin.QueueMessageRow, 0, len(messages))
+	for i, message := range messages {
+		rows = append(rows, &nosqlplugin.QueueMessageRow{
+			QueueType:  q.getDLQTypeFromQueueType(),
+			ID:         lastMessageID + 1 + int64(i),
+			Payload:    message.Payload,
+			EnqueuedAt: enqueuedAt,
+			DomainID:   message.DomainID,
+			TaskType:   message.TaskType,
+		})
+	}
+	err = q.db.InsertIntoQueueBatch(ctx, rows)
+	if err != nil {
+		if _, ok := err.(*nosqlplugin.ConditionFailure); ok {
+			return &persistence.ConditionFailedError{Msg: fmt.Sprintf("message IDs after %v exist in queue", lastMessageID)}
+		}
+
+		return convertCommonErrors(q.db, fmt.Sprintf("EnqueueMessages, Type: %v", q.getDLQTypeFromQueueType()), err)
+	}
+	return nil
+}
+
 func (q *nosqlQueueStore) tryEnqueue(
 	ctx context.Context,
 	queueType persistence.QueueType,
 	messageID int64,
+	domainID string,
+	taskType *int32,
 	messagePayload []byte,
 ) (int64, error) {
 	err := q.db.InsertIntoQueue(ctx, &nosqlplugin.QueueMessageRow{
-		QueueType: queueType,
-		ID:        messageID,
-		Payload:   messagePayload,
+		QueueType:  queueType,
+		ID:         messageID,
+		Payload:    messagePayload,
+		EnqueuedAt: time.Now(),
+		DomainID:   domainID,
+		TaskType:   taskType,
 	})
 	if err != nil {
 		if _, ok := err.(*nosqlplugin.ConditionFailure); ok {
@@ -184,22 +232,70 @@ func (q *nosqlQueueStore) ReadMessagesFromDLQ(
 	pageSize int,
 	pageToken []byte,
 ) ([]*persistence.InternalQueueMessage, []byte, error) {
-	response, err := q.db.SelectMessagesBetween(ctx, nosqlplugin.SelectMessagesBetweenRequest{
-		QueueType:               q.getDLQTypeFromQueueType(),
+	return q.readMessagesFromDLQ(ctx, "ReadMessagesFromDLQ", nosqlplugin.SelectMessagesBetweenRequest{
 		ExclusiveBeginMessageID: firstMessageID,
 		InclusiveEndMessageID:   lastMessageID,
 		PageSize:                pageSize,
 		NextPageToken:           pageToken,
 	})
+}
+
+func (q *nosqlQueueStore) ReadMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*persistence.InternalQueueMessage, []byte, error) {
+	return q.readMessagesFromDLQ(ctx, "ReadMessagesFromDLQByDomain", nosqlplugin.SelectMessagesBetweenRequest{
+		DomainID:                domainID,
+		ExclusiveBeginMessageID: firstMessageID,
+		InclusiveEndMessageID:   lastMessageID,
+		PageSize:                pageSize,
+		NextPageToken:           pageToken,
+	})
+}
+
+func (q *nosqlQueueStore) ReadMessagesFromDLQByTimeRange(
+	ctx context.Context,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*persistence.InternalQueueMessage, []byte, error) {
+	return q.readMessagesFromDLQ(ctx, "ReadMessagesFromDLQByTimeRange", nosqlplugin.SelectMessagesBetweenRequest{
+		StartTime:               startTime,
+		EndTime:                 endTime,
+		ExclusiveBeginMessageID: firstMessageID,
+		InclusiveEndMessageID:   lastMessageID,
+		PageSize:                pageSize,
+		NextPageToken:           pageToken,
+	})
+}
+
+func (q *nosqlQueueStore) readMessagesFromDLQ(
+	ctx context.Context,
+	operation string,
+	request nosqlplugin.SelectMessagesBetweenRequest,
+) ([]*persistence.InternalQueueMessage, []byte, error) {
+	// Use negative queue type as the dlq type
+	request.QueueType = q.getDLQTypeFromQueueType()
+	response, err := q.db.SelectMessagesBetween(ctx, request)
 	if err != nil {
-		return nil, nil, convertCommonErrors(q.db, "ReadMessagesFromDLQ", err)
+		return nil, nil, convertCommonErrors(q.db, operation, err)
 	}
 	var result []*persistence.InternalQueueMessage
 	for _, msg := range response.Rows {
 		result = append(result, &persistence.InternalQueueMessage{
-			ID:        msg.ID,
-			QueueType: msg.QueueType,
-			Payload:   msg.Payload,
+			ID:         msg.ID,
+			QueueType:  msg.QueueType,
+			Payload:    msg.Payload,
+			Attempts:   msg.Attempts,
+			EnqueuedAt: msg.EnqueuedAt,
+			DomainID:   msg.DomainID,
 		})
 	}
 
@@ -242,6 +338,71 @@ func (q *nosqlQueueStore) RangeDeleteMessagesFromDLQ(
 	return nil
 }
 
+func (q *nosqlQueueStore) RangeDeleteMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	// Use negative queue type as the dlq type
+	if err := q.db.DeleteMessagesInRangeByDomain(ctx, q.getDLQTypeFromQueueType(), domainID, firstMessageID, lastMessageID); err != nil {
+		return convertCommonErrors(q.db, "RangeDeleteMessagesFromDLQByDomain", err)
+	}
+
+	return nil
+}
+
+func (q *nosqlQueueStore) RangeSoftDeleteMessagesFromDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	// Use negative queue type as the dlq type
+	if err := q.db.SoftDeleteMessagesInRange(ctx, q.getDLQTypeFromQueueType(), firstMessageID, lastMessageID, time.Now()); err != nil {
+		return convertCommonErrors(q.db, "RangeSoftDeleteMessagesFromDLQ", err)
+	}
+
+	return nil
+}
+
+func (q *nosqlQueueStore) DeleteSoftDeletedMessagesFromDLQ(
+	ctx context.Context,
+	deletedBefore time.Time,
+) error {
+	// Use negative queue type as the dlq type
+	if err := q.db.DeleteMessagesSoftDeletedBefore(ctx, q.getDLQTypeFromQueueType(), deletedBefore); err != nil {
+		return convertCommonErrors(q.db, "DeleteSoftDeletedMessagesFromDLQ", err)
+	}
+
+	return nil
+}
+
+func (q *nosqlQueueStore) UpdateDLQMessageAttempts(
+	ctx context.Context,
+	messageID int64,
+	attempts int,
+) error {
+	// Use negative queue type as the dlq type
+	if err := q.db.UpdateMessageAttempts(ctx, q.getDLQTypeFromQueueType(), messageID, attempts); err != nil {
+		return convertCommonErrors(q.db, "UpdateDLQMessageAttempts", err)
+	}
+
+	return nil
+}
+
+func (q *nosqlQueueStore) UpdateDLQMessagePayload(
+	ctx context.Context,
+	messageID int64,
+	payload []byte,
+) error {
+	// Use negative queue type as the dlq type
+	if err := q.db.UpdateMessagePayload(ctx, q.getDLQTypeFromQueueType(), messageID, payload); err != nil {
+		return convertCommonErrors(q.db, "UpdateDLQMessagePayload", err)
+	}
+
+	return nil
+}
+
 func (q *nosqlQueueStore) insertInitialQueueMetadataRecord(
 	ctx context.Context,
 	queueType persistence.QueueType,
@@ -283,6 +444,47 @@ func (q *nosqlQueueStore) UpdateDLQAckLevel(
 	return q.updateAckLevel(ctx, messageID, clusterName, q.getDLQTypeFromQueueType())
 }
 
+func (q *nosqlQueueStore) CompareAndSwapDLQAckLevel(
+	ctx context.Context,
+	expectedMessageID int64,
+	messageID int64,
+	clusterName string,
+) error {
+
+	// Use negative queue type as the dlq type
+	queueMetadata, err := q.getQueueMetadata(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		return err
+	}
+
+	ackLevel, ok := queueMetadata.ClusterAckLevels[clusterName]
+	if !ok {
+		ackLevel = emptyMessageID
+	}
+	if ackLevel != expectedMessageID {
+		return &persistence.ConditionFailedError{
+			Msg: fmt.Sprintf("dlq ack level of %v is %v, expected %v", clusterName, ackLevel, expectedMessageID),
+		}
+	}
+
+	if queueMetadata.ClusterAckLevels == nil {
+		queueMetadata.ClusterAckLevels = make(map[string]int64)
+	}
+	queueMetadata.ClusterAckLevels[clusterName] = messageID
+	queueMetadata.Version++
+
+	if err := q.db.UpdateQueueMetadataCas(ctx, *queueMetadata); err != nil {
+		if _, ok := err.(*nosqlplugin.ConditionFailure); ok {
+			return &persistence.ConditionFailedError{
+				Msg: "CompareAndSwapDLQAckLevel operation encounter concurrent write.",
+			}
+		}
+
+		return convertCommonErrors(q.db, "CompareAndSwapDLQAckLevel", err)
+	}
+	return nil
+}
+
 func (q *nosqlQueueStore) GetDLQAckLevels(
 	ctx context.Context,
 ) (map[string]int64, error) {
@@ -296,6 +498,153 @@ func (q *nosqlQueueStore) GetDLQAckLevels(
 	return queueMetadata.ClusterAckLevels, nil
 }
 
+// UpdateDLQAckLevelWithVersion sets the DLQ ack level if the DLQ metadata row is still at the expected version,
+// the row is updated with a lightweight transaction on its version
+func (q *nosqlQueueStore) UpdateDLQAckLevelWithVersion(
+	ctx context.Context,
+	messageID int64,
+	clusterName string,
+	expectedVersion int64,
+) error {
+
+	// Use negative queue type as the dlq type
+	queueMetadata, err := q.getQueueMetadata(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		return err
+	}
+	if queueMetadata.Version != expectedVersion {
+		return &persistence.ConditionFailedError{
+			Msg: fmt.Sprintf("dlq ack levels are at version %v, expected %v", queueMetadata.Version, expectedVersion),
+		}
+	}
+
+	if queueMetadata.ClusterAckLevels == nil {
+		queueMetadata.ClusterAckLevels = make(map[string]int64)
+	}
+	queueMetadata.ClusterAckLevels[clusterName] = messageID
+	queueMetadata.Version++
+
+	if err := q.db.UpdateQueueMetadataCas(ctx, *queueMetadata); err != nil {
+		if _, ok := err.(*nosqlplugin.ConditionFailure); ok {
+			return &persistence.ConditionFailedError{
+				Msg: "UpdateDLQAckLevelWithVersion operation encounter concurrent write.",
+			}
+		}
+
+		return convertCommonErrors(q.db, "UpdateDLQAckLevelWithVersion", err)
+	}
+	return nil
+}
+
+// GetDLQAckLevelsWithVersion returns the DLQ ack levels with the version of the DLQ metadata row
+func (q *nosqlQueueStore) GetDLQAckLevelsWithVersion(
+	ctx context.Context,
+) (map[string]int64, int64, error) {
+
+	// Use negative queue type as the dlq type
+	queueMetadata, err := q.getQueueMetadata(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return queueMetadata.ClusterAckLevels, queueMetadata.Version, nil
+}
+
+func (q *nosqlQueueStore) UpdateDLQMergeToken(
+	ctx context.Context,
+	token string,
+	clusterName string,
+) error {
+
+	// the whole metadata row is written conditionally on its version, so the token is set again on the
+	// latest row when another update of the row wins
+	for attempt := 0; attempt < mergeTokenUpdateMaxAttempts; attempt++ {
+		// Use negative queue type as the dlq type
+		queueMetadata, err := q.getQueueMetadata(ctx, q.getDLQTypeFromQueueType())
+		if err != nil {
+			return err
+		}
+
+		if queueMetadata.ClusterMergeTokens == nil {
+			queueMetadata.ClusterMergeTokens = make(map[string]string)
+		}
+		queueMetadata.ClusterMergeTokens[clusterName] = token
+		queueMetadata.Version++
+
+		err = q.db.UpdateQueueMetadataCas(ctx, *queueMetadata)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*nosqlplugin.ConditionFailure); !ok {
+			return convertCommonErrors(q.db, "UpdateDLQMergeToken", err)
+		}
+	}
+	return &types.InternalServiceError{
+		Message: fmt.Sprintf("UpdateDLQMergeToken operation encounter concurrent write %v times.", mergeTokenUpdateMaxAttempts),
+	}
+}
+
+func (q *nosqlQueueStore) GetDLQMergeTokens(
+	ctx context.Context,
+) (map[string]string, error) {
+
+	// Use negative queue type as the dlq type
+	queueMetadata, err := q.getQueueMetadata(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		return nil, err
+	}
+
+	return queueMetadata.ClusterMergeTokens, nil
+}
+
+func (q *nosqlQueueStore) InsertDLQMergeRecord(
+	ctx context.Context,
+	record *persistence.DLQMergeRecord,
+) error {
+
+	row := &nosqlplugin.DLQMergeHistoryRow{
+		QueueType:      q.getDLQTypeFromQueueType(),
+		MergedAt:       record.MergedAt,
+		StartMessageID: record.StartMessageID,
+		EndMessageID:   record.EndMessageID,
+		MergedCount:    record.MergedCount,
+		FailedCount:    record.FailedCount,
+		Duration:       record.Duration,
+		TriggeredBy:    record.TriggeredBy,
+		TraceID:        record.TraceID,
+	}
+	if err := q.db.InsertDLQMergeHistory(ctx, row); err != nil {
+		return convertCommonErrors(q.db, "InsertDLQMergeRecord", err)
+	}
+	return nil
+}
+
+func (q *nosqlQueueStore) GetDLQMergeHistory(
+	ctx context.Context,
+	limit int,
+) ([]*persistence.DLQMergeRecord, error) {
+
+	rows, err := q.db.SelectDLQMergeHistory(ctx, q.getDLQTypeFromQueueType(), limit)
+	if err != nil {
+		return nil, convertCommonErrors(q.db, "GetDLQMergeHistory", err)
+	}
+
+	records := make([]*persistence.DLQMergeRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, &persistence.DLQMergeRecord{
+			MergedAt:       row.MergedAt,
+			StartMessageID: row.StartMessageID,
+			EndMessageID:   row.EndMessageID,
+			MergedCount:    row.MergedCount,
+			FailedCount:    row.FailedCount,
+			Duration:       row.Duration,
+			TriggeredBy:    row.TriggeredBy,
+			TraceID:        row.TraceID,
+		})
+	}
+	return records, nil
+}
+
 func (q *nosqlQueueStore) GetDLQSize(
 	ctx context.Context,
 ) (int64, error) {
@@ -307,6 +656,17 @@ func (q *nosqlQueueStore) GetDLQSize(
 	return size, err
 }
 
+func (q *nosqlQueueStore) GetDLQMessageTypeHistogram(
+	ctx context.Context,
+) (map[int32]int64, error) {
+
+	histogram, err := q.db.GetQueueMessageTypeHistogram(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		return nil, convertCommonErrors(q.db, "GetDLQMessageTypeHistogram", err)
+	}
+	return histogram, nil
+}
+
 func (q *nosqlQueueStore) getQueueMetadata(
 	ctx context.Context,
 	queueType persistence.QueueType,
@@ -23,32 +23,86 @@ package cassandra
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
+	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin/cassandra/gocql"
 )
 
 const (
-	templateEnqueueMessageQuery             = `INSERT INTO queue (queue_type, message_id, message_payload) VALUES(?, ?, ?) IF NOT EXISTS`
-	templateGetLastMessageIDQuery           = `SELECT message_id FROM queue WHERE queue_type=? ORDER BY message_id DESC LIMIT 1`
-	templateGetMessagesQuery                = `SELECT message_id, message_payload FROM queue WHERE queue_type = ? and message_id > ? LIMIT ?`
-	templateGetMessagesFromDLQQuery         = `SELECT message_id, message_payload FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ?`
-	templateRangeDeleteMessagesBeforeQuery  = `DELETE FROM queue WHERE queue_type = ? and message_id < ?`
-	templateRangeDeleteMessagesBetweenQuery = `DELETE FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ?`
-	templateDeleteMessageQuery              = `DELETE FROM queue WHERE queue_type = ? and message_id = ?`
-	templateGetQueueMetadataQuery           = `SELECT cluster_ack_level, version FROM queue_metadata WHERE queue_type = ?`
-	templateInsertQueueMetadataQuery        = `INSERT INTO queue_metadata (queue_type, cluster_ack_level, version) VALUES(?, ?, ?) IF NOT EXISTS`
-	templateUpdateQueueMetadataQuery        = `UPDATE queue_metadata SET cluster_ack_level = ?, version = ? WHERE queue_type = ? IF version = ?`
-	templateGetQueueSizeQuery               = `SELECT COUNT(1) AS count FROM queue WHERE queue_type=?`
+	// messageBatchSize is the number of messages deleted or soft deleted by a single batch
+	messageBatchSize = 100
+	// indexedMessageBatchSize is the number of messages deleted along with their index rows by a single batch,
+	// the index rows are in other partitions so the batch is logged and kept smaller
+	indexedMessageBatchSize = 20
+
+	templateEnqueueMessageQuery              = `INSERT INTO queue (queue_type, message_id, message_payload, enqueued_at, domain_id, task_type) VALUES(?, ?, ?, ?, ?, ?) IF NOT EXISTS`
+	templateGetLastMessageIDQuery            = `SELECT message_id FROM queue WHERE queue_type=? ORDER BY message_id DESC LIMIT 1`
+	templateGetMessagesQuery                 = `SELECT message_id, message_payload FROM queue WHERE queue_type = ? and message_id > ? LIMIT ?`
+	templateGetMessagesFromDLQQuery          = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id, deleted_at FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ?`
+	templateGetMessagesByIDsQuery            = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id, deleted_at FROM queue WHERE queue_type = ? and message_id IN ?`
+	templateGetMessagesByEnqueueTimeQuery    = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id, deleted_at FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ? and enqueued_at >= ? and enqueued_at < ? ALLOW FILTERING`
+	templateGetMessageIDsByDomainQuery       = `SELECT message_id FROM queue_by_domain WHERE queue_type = ? and domain_id = ? and message_id > ? and message_id <= ?`
+	templateInsertMessageByDomainQuery       = `INSERT INTO queue_by_domain (queue_type, domain_id, message_id) VALUES(?, ?, ?)`
+	templateDeleteMessageByDomainQuery       = `DELETE FROM queue_by_domain WHERE queue_type = ? and domain_id = ? and message_id = ?`
+	templateInsertMessageByTaskTypeQuery     = `INSERT INTO queue_by_task_type (queue_type, task_type, message_id) VALUES(?, ?, ?)`
+	templateDeleteMessageByTaskTypeQuery     = `DELETE FROM queue_by_task_type WHERE queue_type = ? and task_type = ? and message_id = ?`
+	templateSoftDeleteMessageByTaskTypeQuery = `UPDATE queue_by_task_type SET deleted_at = ? WHERE queue_type = ? and task_type = ? and message_id = ?`
+	templateGetMessageIndexQuery             = `SELECT domain_id, task_type FROM queue WHERE queue_type = ? and message_id = ?`
+	templateGetMessageIndexesQuery           = `SELECT message_id, domain_id, task_type FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ?`
+	templateGetMessageIndexesByIDsQuery      = `SELECT message_id, domain_id, task_type FROM queue WHERE queue_type = ? and message_id IN ?`
+	templateGetMessageDeletionsQuery         = `SELECT message_id, deleted_at, task_type FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ?`
+	templateGetAllMessageDeletionsQuery      = `SELECT message_id, deleted_at, domain_id, task_type FROM queue WHERE queue_type = ?`
+	templateSoftDeleteMessageQuery           = `UPDATE queue SET deleted_at = ? WHERE queue_type = ? and message_id = ?`
+	templateRangeDeleteMessagesBeforeQuery   = `DELETE FROM queue WHERE queue_type = ? and message_id < ?`
+	templateRangeDeleteMessagesBetweenQuery  = `DELETE FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ?`
+	templateDeleteMessageQuery               = `DELETE FROM queue WHERE queue_type = ? and message_id = ?`
+	templateUpdateMessageAttemptsQuery       = `UPDATE queue SET attempts = ? WHERE queue_type = ? and message_id = ?`
+	templateUpdateMessagePayloadQuery        = `UPDATE queue SET message_payload = ? WHERE queue_type = ? and message_id = ?`
+	templateGetQueueMetadataQuery            = `SELECT cluster_ack_level, cluster_merge_token, version FROM queue_metadata WHERE queue_type = ?`
+	templateInsertQueueMetadataQuery         = `INSERT INTO queue_metadata (queue_type, cluster_ack_level, version) VALUES(?, ?, ?) IF NOT EXISTS`
+	templateUpdateQueueMetadataQuery         = `UPDATE queue_metadata SET cluster_ack_level = ?, cluster_merge_token = ?, version = ? WHERE queue_type = ? IF version = ?`
+	templateGetQueueSizeQuery                = `SELECT COUNT(1) AS count, COUNT(deleted_at) AS deleted_count FROM queue WHERE queue_type=?`
+	templateGetQueueTaskTypesQuery           = `SELECT DISTINCT queue_type, task_type FROM queue_by_task_type`
+	templateGetQueueTaskTypeSizeQuery        = `SELECT COUNT(1) AS count, COUNT(deleted_at) AS deleted_count FROM queue_by_task_type WHERE queue_type = ? and task_type = ?`
+	templateInsertDLQMergeHistoryQuery       = `INSERT INTO dlq_merge_history (queue_type, merged_at, start_message_id, end_message_id, merged_count, failed_count, duration, triggered_by, trace_id) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	templateGetDLQMergeHistoryQuery          = `SELECT merged_at, start_message_id, end_message_id, merged_count, failed_count, duration, triggered_by, trace_id FROM dlq_merge_history WHERE queue_type = ? LIMIT ?`
 )
 
-//Insert message into queue, return error if failed or already exists
+type (
+	// queueMessageIndex is a message with the columns it is indexed by in the index tables of queue
+	queueMessageIndex struct {
+		id       int64
+		domainID string
+		taskType *int32
+	}
+)
+
+func (i queueMessageIndex) isIndexed() bool {
+	return i.domainID != "" || i.taskType != nil
+}
+
+func (i queueMessageIndex) equals(other queueMessageIndex) bool {
+	if i.id != other.id || i.domainID != other.domainID || (i.taskType == nil) != (other.taskType == nil) {
+		return false
+	}
+	return i.taskType == nil || *i.taskType == *other.taskType
+}
+
+// Insert message into queue, return error if failed or already exists
 // Must return ConditionFailure error if row already exists
 func (db *cdb) InsertIntoQueue(
 	ctx context.Context,
 	row *nosqlplugin.QueueMessageRow,
 ) error {
-	query := db.session.Query(templateEnqueueMessageQuery, row.QueueType, row.ID, row.Payload).WithContext(ctx)
+	rows := []*nosqlplugin.QueueMessageRow{row}
+	if err := db.insertMessageIndexes(ctx, rows); err != nil {
+		return err
+	}
+
+	query := db.session.Query(templateEnqueueMessageQuery, row.QueueType, row.ID, row.Payload, row.EnqueuedAt, getDomainIDValue(row.DomainID), row.TaskType).WithContext(ctx)
 	previous := make(map[string]interface{})
 	applied, err := query.MapScanCAS(previous)
 	if err != nil {
@@ -56,6 +110,60 @@ func (db *cdb) InsertIntoQueue(
 	}
 
 	if !applied {
+		db.deleteOrphanMessageIndexes(ctx, rows, []map[string]interface{}{previous})
+		return nosqlplugin.NewConditionFailure("queue")
+	}
+	return nil
+}
+
+// Insert messages of the same queue into queue in a single batch, return error if failed or any already exists
+// Return ConditionFailure if the condition doesn't meet
+//
+// The queue is partitioned by queue_type alone: message IDs are allocated by conditional inserts, which need all
+// messages of a queue in the same partition. A queue is therefore a single partition, which gets hot under heavy
+// enqueuing, so callers keep their batches small and the batch is unlogged: a batch within a single partition is
+// applied atomically without going through the batch log.
+func (db *cdb) InsertIntoQueueBatch(
+	ctx context.Context,
+	rows []*nosqlplugin.QueueMessageRow,
+) error {
+	for _, row := range rows {
+		if row.QueueType != rows[0].QueueType {
+			return fmt.Errorf("batch inserts messages into queues %v and %v, it must insert into a single queue", rows[0].QueueType, row.QueueType)
+		}
+	}
+
+	if err := db.insertMessageIndexes(ctx, rows); err != nil {
+		return err
+	}
+
+	batch := db.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+	for _, row := range rows {
+		batch.Query(templateEnqueueMessageQuery, row.QueueType, row.ID, row.Payload, row.EnqueuedAt, getDomainIDValue(row.DomainID), row.TaskType)
+	}
+
+	previous := make(map[string]interface{})
+	applied, iter, err := db.session.MapExecuteBatchCAS(batch, previous)
+	defer func() {
+		if iter != nil {
+			_ = iter.Close()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	if !applied {
+		// first iter MapScan is done inside MapExecuteBatchCAS
+		existing := []map[string]interface{}{previous}
+		for iter != nil {
+			message := make(map[string]interface{})
+			if !iter.MapScan(message) {
+				break
+			}
+			existing = append(existing, message)
+		}
+		db.deleteOrphanMessageIndexes(ctx, rows, existing)
 		return nosqlplugin.NewConditionFailure("queue")
 	}
 	return nil
@@ -116,45 +224,150 @@ func (db *cdb) SelectMessagesBetween(
 	ctx context.Context,
 	request nosqlplugin.SelectMessagesBetweenRequest,
 ) (*nosqlplugin.SelectMessagesBetweenResponse, error) {
+	// soft deleted messages can't be filtered out by the query as deleted_at is not part of the primary key, they are
+	// skipped after they are read, so the pages are read until the page is full or there are no more messages to
+	// return a short page only when it is the last one
+	pageSize := request.PageSize
+	response := &nosqlplugin.SelectMessagesBetweenResponse{NextPageToken: request.NextPageToken}
+	for {
+		request.PageSize = pageSize - len(response.Rows)
+		request.NextPageToken = response.NextPageToken
+		page, err := db.selectMessagesPage(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		response.Rows = append(response.Rows, page.Rows...)
+		response.NextPageToken = page.NextPageToken
+		if len(response.Rows) >= pageSize || len(response.NextPageToken) == 0 {
+			return response, nil
+		}
+	}
+}
+
+// selectMessagesPage reads a page of the messages, without the soft deleted messages of the page
+func (db *cdb) selectMessagesPage(
+	ctx context.Context,
+	request nosqlplugin.SelectMessagesBetweenRequest,
+) (*nosqlplugin.SelectMessagesBetweenResponse, error) {
+	if request.DomainID != "" {
+		return db.selectMessagesByDomain(ctx, request)
+	}
+
 	// Reading replication tasks need to be quorum level consistent, otherwise we could loose task
 	// Use negative queue type as the dlq type
 	query := db.session.Query(templateGetMessagesFromDLQQuery,
 		request.QueueType,
 		request.ExclusiveBeginMessageID,
 		request.InclusiveEndMessageID,
-	).PageSize(request.PageSize).PageState(request.NextPageToken).WithContext(ctx)
+	)
+	if !request.EndTime.IsZero() {
+		// enqueued_at is not part of the primary key, so the messages of the queue are scanned for those in the time range
+		query = db.session.Query(templateGetMessagesByEnqueueTimeQuery,
+			request.QueueType,
+			request.ExclusiveBeginMessageID,
+			request.InclusiveEndMessageID,
+			request.StartTime,
+			request.EndTime,
+		)
+	}
+	query = query.PageSize(request.PageSize).PageState(request.NextPageToken).WithContext(ctx)
 
 	iter := query.Iter()
 	if iter == nil {
 		return nil, fmt.Errorf("SelectMessagesBetween operation failed. Not able to create query iterator")
 	}
 
-	var rows []nosqlplugin.QueueMessageRow
-	message := make(map[string]interface{})
-	for iter.MapScan(message) {
-		payload := getMessagePayload(message)
-		id := getMessageID(message)
-		rows = append(rows, nosqlplugin.QueueMessageRow{ID: id, Payload: payload})
-		message = make(map[string]interface{})
+	rows := scanQueueMessages(iter)
+	nextPageToken := iter.PageState()
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return &nosqlplugin.SelectMessagesBetweenResponse{
+		Rows:          rows,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// selectMessagesByDomain reads a page of the IDs of the domain's messages from queue_by_domain, and then the messages
+func (db *cdb) selectMessagesByDomain(
+	ctx context.Context,
+	request nosqlplugin.SelectMessagesBetweenRequest,
+) (*nosqlplugin.SelectMessagesBetweenResponse, error) {
+	iter := db.session.Query(templateGetMessageIDsByDomainQuery,
+		request.QueueType,
+		request.DomainID,
+		request.ExclusiveBeginMessageID,
+		request.InclusiveEndMessageID,
+	).PageSize(request.PageSize).PageState(request.NextPageToken).WithContext(ctx).Iter()
+	if iter == nil {
+		return nil, fmt.Errorf("SelectMessagesBetween operation failed. Not able to create query iterator")
 	}
 
+	var messageIDs []int64
+	var messageID int64
+	for iter.Scan(&messageID) {
+		messageIDs = append(messageIDs, messageID)
+	}
 	nextPageToken := iter.PageState()
 	if err := iter.Close(); err != nil {
 		return nil, err
 	}
 
+	var rows []nosqlplugin.QueueMessageRow
+	if len(messageIDs) > 0 {
+		iter = db.session.Query(templateGetMessagesByIDsQuery,
+			request.QueueType,
+			messageIDs,
+		).PageSize(len(messageIDs)).WithContext(ctx).Iter()
+		if iter == nil {
+			return nil, fmt.Errorf("SelectMessagesBetween operation failed. Not able to create query iterator")
+		}
+		for _, row := range scanQueueMessages(iter) {
+			// the index rows of messages which were not inserted are skipped
+			if row.DomainID == request.DomainID {
+				rows = append(rows, row)
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &nosqlplugin.SelectMessagesBetweenResponse{
 		Rows:          rows,
 		NextPageToken: nextPageToken,
 	}, nil
 }
 
+// scanQueueMessages reads the messages of the iterator, skipping the soft deleted messages
+func scanQueueMessages(iter gocql.Iter) []nosqlplugin.QueueMessageRow {
+	var rows []nosqlplugin.QueueMessageRow
+	message := make(map[string]interface{})
+	for iter.MapScan(message) {
+		// soft deleted messages are kept until they are garbage collected, but are never read
+		if !getMessageDeletedAt(message).IsZero() {
+			message = make(map[string]interface{})
+			continue
+		}
+		payload := getMessagePayload(message)
+		id := getMessageID(message)
+		attempts := getMessageAttempts(message)
+		enqueuedAt := getMessageEnqueuedAt(message)
+		domainID := getMessageDomainID(message)
+		rows = append(rows, nosqlplugin.QueueMessageRow{ID: id, Payload: payload, Attempts: attempts, EnqueuedAt: enqueuedAt, DomainID: domainID})
+		message = make(map[string]interface{})
+	}
+	return rows
+}
+
 // Delete all messages before exclusiveBeginMessageID
 func (db *cdb) DeleteMessagesBefore(
 	ctx context.Context,
 	queueType persistence.QueueType,
 	exclusiveBeginMessageID int64,
 ) error {
+	// it deletes the messages of the replication queue, which are enqueued without the columns they would be indexed by
 	query := db.session.Query(templateRangeDeleteMessagesBeforeQuery, queueType, exclusiveBeginMessageID).WithContext(ctx)
 	return query.Exec()
 }
@@ -166,20 +379,383 @@ func (db *cdb) DeleteMessagesInRange(
 	exclusiveBeginMessageID int64,
 	inclusiveEndMessageID int64,
 ) error {
+	// the messages with index rows are deleted along with them, and the rest of the range by a single range delete
+	iter := db.session.Query(templateGetMessageIndexesQuery,
+		queueType,
+		exclusiveBeginMessageID,
+		inclusiveEndMessageID,
+	).PageSize(messageBatchSize).WithContext(ctx).Iter()
+	if iter == nil {
+		return fmt.Errorf("DeleteMessagesInRange operation failed. Not able to create query iterator")
+	}
+
+	var indexes []queueMessageIndex
+	index := queueMessageIndex{}
+	for iter.Scan(&index.id, &index.domainID, &index.taskType) {
+		if index.isIndexed() {
+			indexes = append(indexes, index)
+		}
+		index = queueMessageIndex{}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	if err := db.deleteIndexedMessages(ctx, queueType, indexes); err != nil {
+		return err
+	}
 	query := db.session.Query(templateRangeDeleteMessagesBetweenQuery, queueType, exclusiveBeginMessageID, inclusiveEndMessageID).WithContext(ctx)
 	return query.Exec()
 }
 
+// Delete the messages of a domain in a range between exclusiveBeginMessageID and inclusiveEndMessageID
+func (db *cdb) DeleteMessagesInRangeByDomain(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	domainID string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+) error {
+	// the IDs of the domain's messages are read from queue_by_domain,
+	// and the messages are deleted along with their index rows a page at a time
+	iter := db.session.Query(templateGetMessageIDsByDomainQuery,
+		queueType,
+		domainID,
+		exclusiveBeginMessageID,
+		inclusiveEndMessageID,
+	).PageSize(messageBatchSize).WithContext(ctx).Iter()
+	if iter == nil {
+		return fmt.Errorf("DeleteMessagesInRangeByDomain operation failed. Not able to create query iterator")
+	}
+
+	var messageIDs []int64
+	var messageID int64
+	for iter.Scan(&messageID) {
+		messageIDs = append(messageIDs, messageID)
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	for len(messageIDs) > 0 {
+		batchSize := indexedMessageBatchSize
+		if len(messageIDs) < batchSize {
+			batchSize = len(messageIDs)
+		}
+		indexes, err := db.selectMessageIndexes(ctx, queueType, messageIDs[:batchSize])
+		if err != nil {
+			return err
+		}
+
+		batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		for _, messageID := range messageIDs[:batchSize] {
+			index, ok := indexes[messageID]
+			if !ok || index.domainID != domainID {
+				// the index row of a message which was not inserted, the message ID is not the domain's
+				batch.Query(templateDeleteMessageByDomainQuery, queueType, domainID, messageID)
+				continue
+			}
+			batch.Query(templateDeleteMessageQuery, queueType, messageID)
+			addMessageIndexDeletes(batch, queueType, index)
+		}
+		if err := db.session.ExecuteBatch(batch); err != nil {
+			return err
+		}
+		messageIDs = messageIDs[batchSize:]
+	}
+	return nil
+}
+
+// Soft delete all messages in a range between exclusiveBeginMessageID and inclusiveEndMessageID by setting their deletedAt time
+func (db *cdb) SoftDeleteMessagesInRange(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+	deletedAt time.Time,
+) error {
+	// updates can't address a range of rows, so the messages are soft deleted one by one a page at a time,
+	// skipping the ones which are soft deleted already to keep their original deletion time
+	iter := db.session.Query(templateGetMessageDeletionsQuery,
+		queueType,
+		exclusiveBeginMessageID,
+		inclusiveEndMessageID,
+	).PageSize(messageBatchSize).WithContext(ctx).Iter()
+	if iter == nil {
+		return fmt.Errorf("SoftDeleteMessagesInRange operation failed. Not able to create query iterator")
+	}
+
+	var messageIDs []int64
+	var indexes []queueMessageIndex
+	var messageDeletedAt time.Time
+	index := queueMessageIndex{}
+	for iter.Scan(&index.id, &messageDeletedAt, &index.taskType) {
+		if messageDeletedAt.IsZero() {
+			if index.taskType != nil {
+				indexes = append(indexes, index)
+			} else {
+				messageIDs = append(messageIDs, index.id)
+			}
+		}
+		index = queueMessageIndex{}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	// queue_by_task_type keeps the deletion time of the messages to leave the soft deleted messages out of the histogram
+	for len(indexes) > 0 {
+		batchSize := indexedMessageBatchSize
+		if len(indexes) < batchSize {
+			batchSize = len(indexes)
+		}
+		batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		for _, index := range indexes[:batchSize] {
+			batch.Query(templateSoftDeleteMessageQuery, deletedAt, queueType, index.id)
+			batch.Query(templateSoftDeleteMessageByTaskTypeQuery, deletedAt, queueType, *index.taskType, index.id)
+		}
+		if err := db.session.ExecuteBatch(batch); err != nil {
+			return err
+		}
+		indexes = indexes[batchSize:]
+	}
+	return db.executeMessageBatches(ctx, messageIDs, func(batch gocql.Batch, messageID int64) {
+		batch.Query(templateSoftDeleteMessageQuery, deletedAt, queueType, messageID)
+	})
+}
+
+// Delete all messages soft deleted before deletedBefore
+func (db *cdb) DeleteMessagesSoftDeletedBefore(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	deletedBefore time.Time,
+) error {
+	// deleted_at is not part of the primary key, so the whole queue is scanned for soft deleted messages
+	iter := db.session.Query(templateGetAllMessageDeletionsQuery,
+		queueType,
+	).PageSize(messageBatchSize).WithContext(ctx).Iter()
+	if iter == nil {
+		return fmt.Errorf("DeleteMessagesSoftDeletedBefore operation failed. Not able to create query iterator")
+	}
+
+	var messageIDs []int64
+	var indexes []queueMessageIndex
+	var messageDeletedAt time.Time
+	index := queueMessageIndex{}
+	for iter.Scan(&index.id, &messageDeletedAt, &index.domainID, &index.taskType) {
+		if !messageDeletedAt.IsZero() && messageDeletedAt.Before(deletedBefore) {
+			if index.isIndexed() {
+				indexes = append(indexes, index)
+			} else {
+				messageIDs = append(messageIDs, index.id)
+			}
+		}
+		index = queueMessageIndex{}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	if err := db.deleteIndexedMessages(ctx, queueType, indexes); err != nil {
+		return err
+	}
+	return db.executeMessageBatches(ctx, messageIDs, func(batch gocql.Batch, messageID int64) {
+		batch.Query(templateDeleteMessageQuery, queueType, messageID)
+	})
+}
+
+// executeMessageBatches executes the query added by addQuery for every message, messageBatchSize messages at a time
+func (db *cdb) executeMessageBatches(
+	ctx context.Context,
+	messageIDs []int64,
+	addQuery func(batch gocql.Batch, messageID int64),
+) error {
+	for len(messageIDs) > 0 {
+		batchSize := messageBatchSize
+		if len(messageIDs) < batchSize {
+			batchSize = len(messageIDs)
+		}
+		// all messages of a queue share the queue_type partition, so an unlogged batch is applied atomically
+		batch := db.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+		for _, id := range messageIDs[:batchSize] {
+			addQuery(batch, id)
+		}
+		if err := db.session.ExecuteBatch(batch); err != nil {
+			return err
+		}
+		messageIDs = messageIDs[batchSize:]
+	}
+	return nil
+}
+
+// insertMessageIndexes writes the index rows of the messages. Message IDs are allocated by conditional inserts into queue,
+// which can't be batched with writes to other partitions, so the index rows are written before the messages: a message
+// is never missing from the indexes, and the reads through the indexes skip the rows of messages which were not inserted.
+func (db *cdb) insertMessageIndexes(
+	ctx context.Context,
+	rows []*nosqlplugin.QueueMessageRow,
+) error {
+	batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	indexed := false
+	for _, row := range rows {
+		if row.DomainID != "" {
+			batch.Query(templateInsertMessageByDomainQuery, row.QueueType, row.DomainID, row.ID)
+			indexed = true
+		}
+		if row.TaskType != nil {
+			batch.Query(templateInsertMessageByTaskTypeQuery, row.QueueType, *row.TaskType, row.ID)
+			indexed = true
+		}
+	}
+	if !indexed {
+		return nil
+	}
+	return db.session.ExecuteBatch(batch)
+}
+
+// deleteOrphanMessageIndexes deletes the index rows written for messages which were not inserted, the index rows
+// which match the message inserted before with the same ID are kept. The orphan index rows are only deleted on a best
+// effort basis, as the reads through the indexes skip them.
+func (db *cdb) deleteOrphanMessageIndexes(
+	ctx context.Context,
+	rows []*nosqlplugin.QueueMessageRow,
+	existing []map[string]interface{},
+) {
+	existingIndexes := make(map[int64]queueMessageIndex, len(existing))
+	for _, message := range existing {
+		if id, ok := message["message_id"].(int64); ok {
+			existingIndexes[id] = queueMessageIndex{id: id, domainID: getMessageDomainID(message), taskType: getMessageTaskType(message)}
+		}
+	}
+
+	batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	orphans := false
+	for _, row := range rows {
+		index := queueMessageIndex{id: row.ID, domainID: row.DomainID, taskType: row.TaskType}
+		if existingIndex, ok := existingIndexes[row.ID]; ok && existingIndex.equals(index) {
+			continue
+		}
+		if index.isIndexed() {
+			addMessageIndexDeletes(batch, row.QueueType, index)
+			orphans = true
+		}
+	}
+	if !orphans {
+		return
+	}
+	if err := db.session.ExecuteBatch(batch); err != nil {
+		db.logger.Warn("Unable to delete orphan queue message index rows.", tag.Error(err))
+	}
+}
+
+// selectMessageIndexes reads the columns the messages are indexed by, the messages which don't exist are left out
+func (db *cdb) selectMessageIndexes(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageIDs []int64,
+) (map[int64]queueMessageIndex, error) {
+	iter := db.session.Query(templateGetMessageIndexesByIDsQuery,
+		queueType,
+		messageIDs,
+	).PageSize(len(messageIDs)).WithContext(ctx).Iter()
+	if iter == nil {
+		return nil, fmt.Errorf("selectMessageIndexes operation failed. Not able to create query iterator")
+	}
+
+	indexes := make(map[int64]queueMessageIndex, len(messageIDs))
+	index := queueMessageIndex{}
+	for iter.Scan(&index.id, &index.domainID, &index.taskType) {
+		indexes[index.id] = index
+		index = queueMessageIndex{}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// deleteIndexedMessages deletes the messages along with their index rows, indexedMessageBatchSize messages at a time
+func (db *cdb) deleteIndexedMessages(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	indexes []queueMessageIndex,
+) error {
+	for len(indexes) > 0 {
+		batchSize := indexedMessageBatchSize
+		if len(indexes) < batchSize {
+			batchSize = len(indexes)
+		}
+		batch := db.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		for _, index := range indexes[:batchSize] {
+			batch.Query(templateDeleteMessageQuery, queueType, index.id)
+			addMessageIndexDeletes(batch, queueType, index)
+		}
+		if err := db.session.ExecuteBatch(batch); err != nil {
+			return err
+		}
+		indexes = indexes[batchSize:]
+	}
+	return nil
+}
+
+// addMessageIndexDeletes adds the deletes of the index rows of the message to the batch
+func addMessageIndexDeletes(
+	batch gocql.Batch,
+	queueType persistence.QueueType,
+	index queueMessageIndex,
+) {
+	if index.domainID != "" {
+		batch.Query(templateDeleteMessageByDomainQuery, queueType, index.domainID, index.id)
+	}
+	if index.taskType != nil {
+		batch.Query(templateDeleteMessageByTaskTypeQuery, queueType, *index.taskType, index.id)
+	}
+}
+
 // Delete one message
 func (db *cdb) DeleteMessage(
 	ctx context.Context,
 	queueType persistence.QueueType,
 	messageID int64,
 ) error {
+	index := queueMessageIndex{id: messageID}
+	err := db.session.Query(templateGetMessageIndexQuery, queueType, messageID).WithContext(ctx).Scan(&index.domainID, &index.taskType)
+	if err != nil {
+		if db.IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if index.isIndexed() {
+		return db.deleteIndexedMessages(ctx, queueType, []queueMessageIndex{index})
+	}
 	query := db.session.Query(templateDeleteMessageQuery, queueType, messageID).WithContext(ctx)
 	return query.Exec()
 }
 
+// Update the number of attempts made to process one message
+func (db *cdb) UpdateMessageAttempts(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageID int64,
+	attempts int,
+) error {
+	query := db.session.Query(templateUpdateMessageAttemptsQuery, attempts, queueType, messageID).WithContext(ctx)
+	return query.Exec()
+}
+
+// Replace the payload of one message
+func (db *cdb) UpdateMessagePayload(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageID int64,
+	payload []byte,
+) error {
+	query := db.session.Query(templateUpdateMessagePayloadQuery, payload, queueType, messageID).WithContext(ctx)
+	return query.Exec()
+}
+
 // Insert an empty metadata row, starting from a version
 func (db *cdb) InsertQueueMetadata(
 	ctx context.Context,
@@ -209,6 +785,7 @@ func (db *cdb) UpdateQueueMetadataCas(
 ) error {
 	query := db.session.Query(templateUpdateQueueMetadataQuery,
 		row.ClusterAckLevels,
+		row.ClusterMergeTokens,
 		row.Version,
 		row.QueueType,
 		row.Version-1,
@@ -235,8 +812,9 @@ func (db *cdb) SelectQueueMetadata(
 ) (*nosqlplugin.QueueMetadataRow, error) {
 	query := db.session.Query(templateGetQueueMetadataQuery, queueType).WithContext(ctx)
 	var ackLevels map[string]int64
+	var mergeTokens map[string]string
 	var version int64
-	err := query.Scan(&ackLevels, &version)
+	err := query.Scan(&ackLevels, &mergeTokens, &version)
 	if err != nil {
 		return nil, err
 	}
@@ -245,10 +823,14 @@ func (db *cdb) SelectQueueMetadata(
 	if ackLevels == nil {
 		ackLevels = make(map[string]int64)
 	}
+	if mergeTokens == nil {
+		mergeTokens = make(map[string]string)
+	}
 	return &nosqlplugin.QueueMetadataRow{
-		QueueType:        queueType,
-		ClusterAckLevels: ackLevels,
-		Version:          version,
+		QueueType:          queueType,
+		ClusterAckLevels:   ackLevels,
+		ClusterMergeTokens: mergeTokens,
+		Version:            version,
 	}, nil
 }
 
@@ -263,7 +845,103 @@ func (db *cdb) GetQueueSize(
 	if err := query.MapScan(result); err != nil {
 		return 0, err
 	}
-	return result["count"].(int64), nil
+	// soft deleted messages are not counted, COUNT of a column only counts the rows where it is set
+	return result["count"].(int64) - result["deleted_count"].(int64), nil
+}
+
+func (db *cdb) GetQueueMessageTypeHistogram(
+	ctx context.Context,
+	queueType persistence.QueueType,
+) (map[int32]int64, error) {
+
+	// queue_by_task_type only contains messages enqueued with a task type, it has a partition per queue type and
+	// task type, the partitions of the queue are listed first and the messages of each partition are counted
+	iter := db.session.Query(templateGetQueueTaskTypesQuery).WithContext(ctx).Iter()
+	if iter == nil {
+		return nil, fmt.Errorf("GetQueueMessageTypeHistogram operation failed. Not able to create query iterator")
+	}
+
+	var taskTypes []int32
+	var partitionQueueType, taskType int32
+	for iter.Scan(&partitionQueueType, &taskType) {
+		if persistence.QueueType(partitionQueueType) == queueType {
+			taskTypes = append(taskTypes, taskType)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	histogram := make(map[int32]int64)
+	for _, taskType := range taskTypes {
+		query := db.session.Query(templateGetQueueTaskTypeSizeQuery, queueType, taskType).WithContext(ctx)
+		result := make(map[string]interface{})
+		if err := query.MapScan(result); err != nil {
+			return nil, err
+		}
+		// soft deleted messages are not counted, COUNT of a column only counts the rows where it is set
+		if count := result["count"].(int64) - result["deleted_count"].(int64); count > 0 {
+			histogram[taskType] = count
+		}
+	}
+	return histogram, nil
+}
+
+// Insert the record of a batch of merged DLQ messages
+func (db *cdb) InsertDLQMergeHistory(
+	ctx context.Context,
+	row *nosqlplugin.DLQMergeHistoryRow,
+) error {
+	query := db.session.Query(templateInsertDLQMergeHistoryQuery,
+		row.QueueType,
+		row.MergedAt,
+		row.StartMessageID,
+		row.EndMessageID,
+		row.MergedCount,
+		row.FailedCount,
+		int64(row.Duration),
+		row.TriggeredBy,
+		row.TraceID,
+	).WithContext(ctx)
+	return query.Exec()
+}
+
+// Read the most recent DLQ merge records, newest first
+func (db *cdb) SelectDLQMergeHistory(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	limit int,
+) ([]*nosqlplugin.DLQMergeHistoryRow, error) {
+	query := db.session.Query(templateGetDLQMergeHistoryQuery, queueType, limit).WithContext(ctx)
+
+	iter := query.Iter()
+	if iter == nil {
+		return nil, fmt.Errorf("SelectDLQMergeHistory operation failed. Not able to create query iterator")
+	}
+
+	var rows []*nosqlplugin.DLQMergeHistoryRow
+	row := &nosqlplugin.DLQMergeHistoryRow{QueueType: queueType}
+	var duration int64
+	for iter.Scan(
+		&row.MergedAt,
+		&row.StartMessageID,
+		&row.EndMessageID,
+		&row.MergedCount,
+		&row.FailedCount,
+		&duration,
+		&row.TriggeredBy,
+		&row.TraceID,
+	) {
+		row.Duration = time.Duration(duration)
+		rows = append(rows, row)
+		row = &nosqlplugin.DLQMergeHistoryRow{QueueType: queueType}
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
 }
 
 func getMessagePayload(
@@ -279,3 +957,62 @@ func getMessageID(
 
 	return message["message_id"].(int64)
 }
+
+func getMessageAttempts(
+	message map[string]interface{},
+) int {
+
+	// attempts is null for messages which have never been retried
+	attempts, _ := message["attempts"].(int)
+	return attempts
+}
+
+func getMessageDomainID(
+	message map[string]interface{},
+) string {
+
+	// domain_id is null for messages written before the column was added
+	domainID, _ := message["domain_id"].(string)
+	return domainID
+}
+
+// getDomainIDValue writes null for messages without a domain, which are not indexed in queue_by_domain
+func getDomainIDValue(
+	domainID string,
+) interface{} {
+
+	if domainID == "" {
+		return nil
+	}
+	return domainID
+}
+
+func getMessageTaskType(
+	message map[string]interface{},
+) *int32 {
+
+	// task_type is null for messages written before the column was added or enqueued without a task type
+	if taskType, ok := message["task_type"].(int); ok {
+		value := int32(taskType)
+		return &value
+	}
+	return nil
+}
+
+func getMessageDeletedAt(
+	message map[string]interface{},
+) time.Time {
+
+	// deleted_at is null for messages which are not soft deleted
+	deletedAt, _ := message["deleted_at"].(time.Time)
+	return deletedAt
+}
+
+func getMessageEnqueuedAt(
+	message map[string]interface{},
+) time.Time {
+
+	// enqueued_at is null for messages written before the column was added
+	enqueuedAt, _ := message["enqueued_at"].(time.Time)
+	return enqueuedAt
+}
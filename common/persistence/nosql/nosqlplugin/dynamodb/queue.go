@@ -22,12 +22,13 @@ package dynamodb
 
 import (
 	"context"
+	"time"
 
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
 )
 
-//Insert message into queue, return error if failed or already exists
+// Insert message into queue, return error if failed or already exists
 // Return ConditionFailure if the condition doesn't meet
 func (db *ddb) InsertIntoQueue(
 	ctx context.Context,
@@ -36,6 +37,15 @@ func (db *ddb) InsertIntoQueue(
 	panic("TODO")
 }
 
+// Insert messages of the same queue into queue in a single batch, return error if failed or any already exists
+// Return ConditionFailure if the condition doesn't meet
+func (db *ddb) InsertIntoQueueBatch(
+	ctx context.Context,
+	rows []*nosqlplugin.QueueMessageRow,
+) error {
+	panic("TODO")
+}
+
 // Get the ID of last message inserted into the queue
 func (db *ddb) SelectLastEnqueuedMessageID(
 	ctx context.Context,
@@ -81,6 +91,37 @@ func (db *ddb) DeleteMessagesInRange(
 	panic("TODO")
 }
 
+// Delete the messages of a domain in a range between exclusiveBeginMessageID and inclusiveEndMessageID
+func (db *ddb) DeleteMessagesInRangeByDomain(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	domainID string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+) error {
+	panic("TODO")
+}
+
+// Soft delete all messages in a range between exclusiveBeginMessageID and inclusiveEndMessageID
+func (db *ddb) SoftDeleteMessagesInRange(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+	deletedAt time.Time,
+) error {
+	panic("TODO")
+}
+
+// Delete all messages soft deleted before deletedBefore
+func (db *ddb) DeleteMessagesSoftDeletedBefore(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	deletedBefore time.Time,
+) error {
+	panic("TODO")
+}
+
 // Delete one message
 func (db *ddb) DeleteMessage(
 	ctx context.Context,
@@ -90,6 +131,26 @@ func (db *ddb) DeleteMessage(
 	panic("TODO")
 }
 
+// Update the number of attempts made to process one message
+func (db *ddb) UpdateMessageAttempts(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageID int64,
+	attempts int,
+) error {
+	panic("TODO")
+}
+
+// Replace the payload of one message
+func (db *ddb) UpdateMessagePayload(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageID int64,
+	payload []byte,
+) error {
+	panic("TODO")
+}
+
 // Insert an empty metadata row, starting from a version
 func (db *ddb) InsertQueueMetadata(
 	ctx context.Context,
@@ -123,3 +184,25 @@ func (db *ddb) GetQueueSize(
 ) (int64, error) {
 	panic("TODO")
 }
+
+func (db *ddb) GetQueueMessageTypeHistogram(
+	ctx context.Context,
+	queueType persistence.QueueType,
+) (map[int32]int64, error) {
+	panic("TODO")
+}
+
+func (db *ddb) InsertDLQMergeHistory(
+	ctx context.Context,
+	row *nosqlplugin.DLQMergeHistoryRow,
+) error {
+	panic("TODO")
+}
+
+func (db *ddb) SelectDLQMergeHistory(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	limit int,
+) ([]*nosqlplugin.DLQMergeHistoryRow, error) {
+	panic("TODO")
+}
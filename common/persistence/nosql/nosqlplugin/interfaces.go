@@ -113,24 +113,42 @@ type (
 	 *
 	 * Significant columns:
 	 * queue_message partition key: (queueType), range key: (messageID)
+	 * queue_message must also be readable by (queueType, domainID) ordered by messageID, e.g. through an index or a view
+	 * queue_message must also be countable by (queueType, taskType), e.g. through an index or a view
 	 * queue_metadata partition key: (queueType), range key: N/A, query condition column(version)
+	 * dlq_merge_history partition key: (queueType), range key: (mergedAt, startMessageID)
 	 */
 	MessageQueueCRUD interface {
 		//Insert message into queue, return error if failed or already exists
 		// Must return conditionFailed error if row already exists
 		InsertIntoQueue(ctx context.Context, row *QueueMessageRow) error
+		//Insert messages of the same queue into queue in a single batch, return error if failed or any already exists
+		// Must return conditionFailed error if any row already exists
+		InsertIntoQueueBatch(ctx context.Context, rows []*QueueMessageRow) error
 		// Get the ID of last message inserted into the queue
 		SelectLastEnqueuedMessageID(ctx context.Context, queueType persistence.QueueType) (int64, error)
 		// Read queue messages starting from the exclusiveBeginMessageID
 		SelectMessagesFrom(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, maxRows int) ([]*QueueMessageRow, error)
-		// Read queue message starting from exclusiveBeginMessageID int64, inclusiveEndMessageID int64
+		// Read queue message starting from exclusiveBeginMessageID int64, inclusiveEndMessageID int64,
+		// only the messages of request.DomainID are read if it is set, otherwise only the messages enqueued
+		// in the time range of the request if its EndTime is set. Soft deleted messages are never read.
 		SelectMessagesBetween(ctx context.Context, request SelectMessagesBetweenRequest) (*SelectMessagesBetweenResponse, error)
 		// Delete all messages before exclusiveBeginMessageID
 		DeleteMessagesBefore(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64) error
 		// Delete all messages in a range between exclusiveBeginMessageID and inclusiveEndMessageID
 		DeleteMessagesInRange(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, inclusiveEndMessageID int64) error
+		// Delete the messages of a domain in a range between exclusiveBeginMessageID and inclusiveEndMessageID
+		DeleteMessagesInRangeByDomain(ctx context.Context, queueType persistence.QueueType, domainID string, exclusiveBeginMessageID int64, inclusiveEndMessageID int64) error
 		// Delete one message
 		DeleteMessage(ctx context.Context, queueType persistence.QueueType, messageID int64) error
+		// Soft delete all messages in a range between exclusiveBeginMessageID and inclusiveEndMessageID by setting their deletedAt time
+		SoftDeleteMessagesInRange(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, inclusiveEndMessageID int64, deletedAt time.Time) error
+		// Delete all messages soft deleted before deletedBefore
+		DeleteMessagesSoftDeletedBefore(ctx context.Context, queueType persistence.QueueType, deletedBefore time.Time) error
+		// Update the number of attempts made to process one message
+		UpdateMessageAttempts(ctx context.Context, queueType persistence.QueueType, messageID int64, attempts int) error
+		// Replace the payload of one message, e.g. after re-encrypting it
+		UpdateMessagePayload(ctx context.Context, queueType persistence.QueueType, messageID int64, payload []byte) error
 
 		// Insert an empty metadata row, starting from a version
 		InsertQueueMetadata(ctx context.Context, queueType persistence.QueueType, version int64) error
@@ -140,8 +158,16 @@ type (
 		UpdateQueueMetadataCas(ctx context.Context, row QueueMetadataRow) error
 		// Read a QueueMetadata
 		SelectQueueMetadata(ctx context.Context, queueType persistence.QueueType) (*QueueMetadataRow, error)
-		// GetQueueSize return the queue size
+		// GetQueueSize return the queue size, soft deleted messages are not counted
 		GetQueueSize(ctx context.Context, queueType persistence.QueueType) (int64, error)
+		// GetQueueMessageTypeHistogram return the number of messages of each task type, soft deleted messages
+		// and messages enqueued without a task type are not counted
+		GetQueueMessageTypeHistogram(ctx context.Context, queueType persistence.QueueType) (map[int32]int64, error)
+
+		// Insert the record of a batch of merged DLQ messages
+		InsertDLQMergeHistory(ctx context.Context, row *DLQMergeHistoryRow) error
+		// Read the most recent DLQ merge records, newest first
+		SelectDLQMergeHistory(ctx context.Context, queueType persistence.QueueType, limit int) ([]*DLQMergeHistoryRow, error)
 	}
 
 	/***
@@ -245,6 +245,34 @@ func (mr *MockDBMockRecorder) DeleteMessage(ctx, queueType, messageID interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockDB)(nil).DeleteMessage), ctx, queueType, messageID)
 }
 
+// UpdateMessageAttempts mocks base method.
+func (m *MockDB) UpdateMessageAttempts(ctx context.Context, queueType persistence.QueueType, messageID int64, attempts int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessageAttempts", ctx, queueType, messageID, attempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessageAttempts indicates an expected call of UpdateMessageAttempts.
+func (mr *MockDBMockRecorder) UpdateMessageAttempts(ctx, queueType, messageID, attempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageAttempts", reflect.TypeOf((*MockDB)(nil).UpdateMessageAttempts), ctx, queueType, messageID, attempts)
+}
+
+// UpdateMessagePayload mocks base method.
+func (m *MockDB) UpdateMessagePayload(ctx context.Context, queueType persistence.QueueType, messageID int64, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessagePayload", ctx, queueType, messageID, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessagePayload indicates an expected call of UpdateMessagePayload.
+func (mr *MockDBMockRecorder) UpdateMessagePayload(ctx, queueType, messageID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessagePayload", reflect.TypeOf((*MockDB)(nil).UpdateMessagePayload), ctx, queueType, messageID, payload)
+}
+
 // DeleteMessagesBefore mocks base method.
 func (m *MockDB) DeleteMessagesBefore(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64) error {
 	m.ctrl.T.Helper()
@@ -273,6 +301,34 @@ func (mr *MockDBMockRecorder) DeleteMessagesInRange(ctx, queueType, exclusiveBeg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesInRange", reflect.TypeOf((*MockDB)(nil).DeleteMessagesInRange), ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID)
 }
 
+// DeleteMessagesInRangeByDomain mocks base method.
+func (m *MockDB) DeleteMessagesInRangeByDomain(ctx context.Context, queueType persistence.QueueType, domainID string, exclusiveBeginMessageID int64, inclusiveEndMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessagesInRangeByDomain", ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessagesInRangeByDomain indicates an expected call of DeleteMessagesInRangeByDomain.
+func (mr *MockDBMockRecorder) DeleteMessagesInRangeByDomain(ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesInRangeByDomain", reflect.TypeOf((*MockDB)(nil).DeleteMessagesInRangeByDomain), ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID)
+}
+
+// DeleteMessagesSoftDeletedBefore mocks base method.
+func (m *MockDB) DeleteMessagesSoftDeletedBefore(ctx context.Context, queueType persistence.QueueType, deletedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessagesSoftDeletedBefore", ctx, queueType, deletedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessagesSoftDeletedBefore indicates an expected call of DeleteMessagesSoftDeletedBefore.
+func (mr *MockDBMockRecorder) DeleteMessagesSoftDeletedBefore(ctx, queueType, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesSoftDeletedBefore", reflect.TypeOf((*MockDB)(nil).DeleteMessagesSoftDeletedBefore), ctx, queueType, deletedBefore)
+}
+
 // DeleteReplicationDLQTask mocks base method.
 func (m *MockDB) DeleteReplicationDLQTask(ctx context.Context, shardID int, sourceCluster string, taskID int64) error {
 	m.ctrl.T.Helper()
@@ -371,6 +427,21 @@ func (mr *MockDBMockRecorder) DeleteWorkflowExecution(ctx, shardID, domainID, wo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkflowExecution", reflect.TypeOf((*MockDB)(nil).DeleteWorkflowExecution), ctx, shardID, domainID, workflowID, runID)
 }
 
+// GetQueueMessageTypeHistogram mocks base method.
+func (m *MockDB) GetQueueMessageTypeHistogram(ctx context.Context, queueType persistence.QueueType) (map[int32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueueMessageTypeHistogram", ctx, queueType)
+	ret0, _ := ret[0].(map[int32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueueMessageTypeHistogram indicates an expected call of GetQueueMessageTypeHistogram.
+func (mr *MockDBMockRecorder) GetQueueMessageTypeHistogram(ctx, queueType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueMessageTypeHistogram", reflect.TypeOf((*MockDB)(nil).GetQueueMessageTypeHistogram), ctx, queueType)
+}
+
 // GetQueueSize mocks base method.
 func (m *MockDB) GetQueueSize(ctx context.Context, queueType persistence.QueueType) (int64, error) {
 	m.ctrl.T.Helper()
@@ -428,6 +499,20 @@ func (mr *MockDBMockRecorder) InsertIntoHistoryTreeAndNode(ctx, treeRow, nodeRow
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIntoHistoryTreeAndNode", reflect.TypeOf((*MockDB)(nil).InsertIntoHistoryTreeAndNode), ctx, treeRow, nodeRow)
 }
 
+// InsertDLQMergeHistory mocks base method.
+func (m *MockDB) InsertDLQMergeHistory(ctx context.Context, row *DLQMergeHistoryRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDLQMergeHistory", ctx, row)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDLQMergeHistory indicates an expected call of InsertDLQMergeHistory.
+func (mr *MockDBMockRecorder) InsertDLQMergeHistory(ctx, row interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDLQMergeHistory", reflect.TypeOf((*MockDB)(nil).InsertDLQMergeHistory), ctx, row)
+}
+
 // InsertIntoQueue mocks base method.
 func (m *MockDB) InsertIntoQueue(ctx context.Context, row *QueueMessageRow) error {
 	m.ctrl.T.Helper()
@@ -442,6 +527,20 @@ func (mr *MockDBMockRecorder) InsertIntoQueue(ctx, row interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIntoQueue", reflect.TypeOf((*MockDB)(nil).InsertIntoQueue), ctx, row)
 }
 
+// InsertIntoQueueBatch mocks base method.
+func (m *MockDB) InsertIntoQueueBatch(ctx context.Context, rows []*QueueMessageRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertIntoQueueBatch", ctx, rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertIntoQueueBatch indicates an expected call of InsertIntoQueueBatch.
+func (mr *MockDBMockRecorder) InsertIntoQueueBatch(ctx, rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIntoQueueBatch", reflect.TypeOf((*MockDB)(nil).InsertIntoQueueBatch), ctx, rows)
+}
+
 // InsertQueueMetadata mocks base method.
 func (m *MockDB) InsertQueueMetadata(ctx context.Context, queueType persistence.QueueType, version int64) error {
 	m.ctrl.T.Helper()
@@ -881,6 +980,21 @@ func (mr *MockDBMockRecorder) SelectFromHistoryTree(ctx, filter interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectFromHistoryTree", reflect.TypeOf((*MockDB)(nil).SelectFromHistoryTree), ctx, filter)
 }
 
+// SelectDLQMergeHistory mocks base method.
+func (m *MockDB) SelectDLQMergeHistory(ctx context.Context, queueType persistence.QueueType, limit int) ([]*DLQMergeHistoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDLQMergeHistory", ctx, queueType, limit)
+	ret0, _ := ret[0].([]*DLQMergeHistoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDLQMergeHistory indicates an expected call of SelectDLQMergeHistory.
+func (mr *MockDBMockRecorder) SelectDLQMergeHistory(ctx, queueType, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDLQMergeHistory", reflect.TypeOf((*MockDB)(nil).SelectDLQMergeHistory), ctx, queueType, limit)
+}
+
 // SelectLastEnqueuedMessageID mocks base method.
 func (m *MockDB) SelectLastEnqueuedMessageID(ctx context.Context, queueType persistence.QueueType) (int64, error) {
 	m.ctrl.T.Helper()
@@ -971,6 +1085,20 @@ func (mr *MockDBMockRecorder) SelectQueueMetadata(ctx, queueType interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectQueueMetadata", reflect.TypeOf((*MockDB)(nil).SelectQueueMetadata), ctx, queueType)
 }
 
+// SoftDeleteMessagesInRange mocks base method.
+func (m *MockDB) SoftDeleteMessagesInRange(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, inclusiveEndMessageID int64, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteMessagesInRange", ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDeleteMessagesInRange indicates an expected call of SoftDeleteMessagesInRange.
+func (mr *MockDBMockRecorder) SoftDeleteMessagesInRange(ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteMessagesInRange", reflect.TypeOf((*MockDB)(nil).SoftDeleteMessagesInRange), ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt)
+}
+
 // SelectReplicationDLQTasksCount mocks base method.
 func (m *MockDB) SelectReplicationDLQTasksCount(ctx context.Context, shardID int, sourceCluster string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1331,6 +1459,34 @@ func (mr *MocktableCRUDMockRecorder) DeleteMessage(ctx, queueType, messageID int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MocktableCRUD)(nil).DeleteMessage), ctx, queueType, messageID)
 }
 
+// UpdateMessageAttempts mocks base method.
+func (m *MocktableCRUD) UpdateMessageAttempts(ctx context.Context, queueType persistence.QueueType, messageID int64, attempts int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessageAttempts", ctx, queueType, messageID, attempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessageAttempts indicates an expected call of UpdateMessageAttempts.
+func (mr *MocktableCRUDMockRecorder) UpdateMessageAttempts(ctx, queueType, messageID, attempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageAttempts", reflect.TypeOf((*MocktableCRUD)(nil).UpdateMessageAttempts), ctx, queueType, messageID, attempts)
+}
+
+// UpdateMessagePayload mocks base method.
+func (m *MocktableCRUD) UpdateMessagePayload(ctx context.Context, queueType persistence.QueueType, messageID int64, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessagePayload", ctx, queueType, messageID, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessagePayload indicates an expected call of UpdateMessagePayload.
+func (mr *MocktableCRUDMockRecorder) UpdateMessagePayload(ctx, queueType, messageID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessagePayload", reflect.TypeOf((*MocktableCRUD)(nil).UpdateMessagePayload), ctx, queueType, messageID, payload)
+}
+
 // DeleteMessagesBefore mocks base method.
 func (m *MocktableCRUD) DeleteMessagesBefore(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64) error {
 	m.ctrl.T.Helper()
@@ -1359,6 +1515,34 @@ func (mr *MocktableCRUDMockRecorder) DeleteMessagesInRange(ctx, queueType, exclu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesInRange", reflect.TypeOf((*MocktableCRUD)(nil).DeleteMessagesInRange), ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID)
 }
 
+// DeleteMessagesInRangeByDomain mocks base method.
+func (m *MocktableCRUD) DeleteMessagesInRangeByDomain(ctx context.Context, queueType persistence.QueueType, domainID string, exclusiveBeginMessageID int64, inclusiveEndMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessagesInRangeByDomain", ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessagesInRangeByDomain indicates an expected call of DeleteMessagesInRangeByDomain.
+func (mr *MocktableCRUDMockRecorder) DeleteMessagesInRangeByDomain(ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesInRangeByDomain", reflect.TypeOf((*MocktableCRUD)(nil).DeleteMessagesInRangeByDomain), ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID)
+}
+
+// DeleteMessagesSoftDeletedBefore mocks base method.
+func (m *MocktableCRUD) DeleteMessagesSoftDeletedBefore(ctx context.Context, queueType persistence.QueueType, deletedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessagesSoftDeletedBefore", ctx, queueType, deletedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessagesSoftDeletedBefore indicates an expected call of DeleteMessagesSoftDeletedBefore.
+func (mr *MocktableCRUDMockRecorder) DeleteMessagesSoftDeletedBefore(ctx, queueType, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesSoftDeletedBefore", reflect.TypeOf((*MocktableCRUD)(nil).DeleteMessagesSoftDeletedBefore), ctx, queueType, deletedBefore)
+}
+
 // DeleteReplicationDLQTask mocks base method.
 func (m *MocktableCRUD) DeleteReplicationDLQTask(ctx context.Context, shardID int, sourceCluster string, taskID int64) error {
 	m.ctrl.T.Helper()
@@ -1457,6 +1641,21 @@ func (mr *MocktableCRUDMockRecorder) DeleteWorkflowExecution(ctx, shardID, domai
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkflowExecution", reflect.TypeOf((*MocktableCRUD)(nil).DeleteWorkflowExecution), ctx, shardID, domainID, workflowID, runID)
 }
 
+// GetQueueMessageTypeHistogram mocks base method.
+func (m *MocktableCRUD) GetQueueMessageTypeHistogram(ctx context.Context, queueType persistence.QueueType) (map[int32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueueMessageTypeHistogram", ctx, queueType)
+	ret0, _ := ret[0].(map[int32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueueMessageTypeHistogram indicates an expected call of GetQueueMessageTypeHistogram.
+func (mr *MocktableCRUDMockRecorder) GetQueueMessageTypeHistogram(ctx, queueType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueMessageTypeHistogram", reflect.TypeOf((*MocktableCRUD)(nil).GetQueueMessageTypeHistogram), ctx, queueType)
+}
+
 // GetQueueSize mocks base method.
 func (m *MocktableCRUD) GetQueueSize(ctx context.Context, queueType persistence.QueueType) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1514,6 +1713,20 @@ func (mr *MocktableCRUDMockRecorder) InsertIntoHistoryTreeAndNode(ctx, treeRow,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIntoHistoryTreeAndNode", reflect.TypeOf((*MocktableCRUD)(nil).InsertIntoHistoryTreeAndNode), ctx, treeRow, nodeRow)
 }
 
+// InsertDLQMergeHistory mocks base method.
+func (m *MocktableCRUD) InsertDLQMergeHistory(ctx context.Context, row *DLQMergeHistoryRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDLQMergeHistory", ctx, row)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDLQMergeHistory indicates an expected call of InsertDLQMergeHistory.
+func (mr *MocktableCRUDMockRecorder) InsertDLQMergeHistory(ctx, row interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDLQMergeHistory", reflect.TypeOf((*MocktableCRUD)(nil).InsertDLQMergeHistory), ctx, row)
+}
+
 // InsertIntoQueue mocks base method.
 func (m *MocktableCRUD) InsertIntoQueue(ctx context.Context, row *QueueMessageRow) error {
 	m.ctrl.T.Helper()
@@ -1528,6 +1741,20 @@ func (mr *MocktableCRUDMockRecorder) InsertIntoQueue(ctx, row interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIntoQueue", reflect.TypeOf((*MocktableCRUD)(nil).InsertIntoQueue), ctx, row)
 }
 
+// InsertIntoQueueBatch mocks base method.
+func (m *MocktableCRUD) InsertIntoQueueBatch(ctx context.Context, rows []*QueueMessageRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertIntoQueueBatch", ctx, rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertIntoQueueBatch indicates an expected call of InsertIntoQueueBatch.
+func (mr *MocktableCRUDMockRecorder) InsertIntoQueueBatch(ctx, rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIntoQueueBatch", reflect.TypeOf((*MocktableCRUD)(nil).InsertIntoQueueBatch), ctx, rows)
+}
+
 // InsertQueueMetadata mocks base method.
 func (m *MocktableCRUD) InsertQueueMetadata(ctx context.Context, queueType persistence.QueueType, version int64) error {
 	m.ctrl.T.Helper()
@@ -1911,6 +2138,21 @@ func (mr *MocktableCRUDMockRecorder) SelectFromHistoryTree(ctx, filter interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectFromHistoryTree", reflect.TypeOf((*MocktableCRUD)(nil).SelectFromHistoryTree), ctx, filter)
 }
 
+// SelectDLQMergeHistory mocks base method.
+func (m *MocktableCRUD) SelectDLQMergeHistory(ctx context.Context, queueType persistence.QueueType, limit int) ([]*DLQMergeHistoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDLQMergeHistory", ctx, queueType, limit)
+	ret0, _ := ret[0].([]*DLQMergeHistoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDLQMergeHistory indicates an expected call of SelectDLQMergeHistory.
+func (mr *MocktableCRUDMockRecorder) SelectDLQMergeHistory(ctx, queueType, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDLQMergeHistory", reflect.TypeOf((*MocktableCRUD)(nil).SelectDLQMergeHistory), ctx, queueType, limit)
+}
+
 // SelectLastEnqueuedMessageID mocks base method.
 func (m *MocktableCRUD) SelectLastEnqueuedMessageID(ctx context.Context, queueType persistence.QueueType) (int64, error) {
 	m.ctrl.T.Helper()
@@ -2001,6 +2243,20 @@ func (mr *MocktableCRUDMockRecorder) SelectQueueMetadata(ctx, queueType interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectQueueMetadata", reflect.TypeOf((*MocktableCRUD)(nil).SelectQueueMetadata), ctx, queueType)
 }
 
+// SoftDeleteMessagesInRange mocks base method.
+func (m *MocktableCRUD) SoftDeleteMessagesInRange(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, inclusiveEndMessageID int64, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteMessagesInRange", ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDeleteMessagesInRange indicates an expected call of SoftDeleteMessagesInRange.
+func (mr *MocktableCRUDMockRecorder) SoftDeleteMessagesInRange(ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteMessagesInRange", reflect.TypeOf((*MocktableCRUD)(nil).SoftDeleteMessagesInRange), ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt)
+}
+
 // SelectReplicationDLQTasksCount mocks base method.
 func (m *MocktableCRUD) SelectReplicationDLQTasksCount(ctx context.Context, shardID int, sourceCluster string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -2468,6 +2724,34 @@ func (mr *MockMessageQueueCRUDMockRecorder) DeleteMessage(ctx, queueType, messag
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockMessageQueueCRUD)(nil).DeleteMessage), ctx, queueType, messageID)
 }
 
+// UpdateMessageAttempts mocks base method.
+func (m *MockMessageQueueCRUD) UpdateMessageAttempts(ctx context.Context, queueType persistence.QueueType, messageID int64, attempts int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessageAttempts", ctx, queueType, messageID, attempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessageAttempts indicates an expected call of UpdateMessageAttempts.
+func (mr *MockMessageQueueCRUDMockRecorder) UpdateMessageAttempts(ctx, queueType, messageID, attempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageAttempts", reflect.TypeOf((*MockMessageQueueCRUD)(nil).UpdateMessageAttempts), ctx, queueType, messageID, attempts)
+}
+
+// UpdateMessagePayload mocks base method.
+func (m *MockMessageQueueCRUD) UpdateMessagePayload(ctx context.Context, queueType persistence.QueueType, messageID int64, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessagePayload", ctx, queueType, messageID, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessagePayload indicates an expected call of UpdateMessagePayload.
+func (mr *MockMessageQueueCRUDMockRecorder) UpdateMessagePayload(ctx, queueType, messageID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessagePayload", reflect.TypeOf((*MockMessageQueueCRUD)(nil).UpdateMessagePayload), ctx, queueType, messageID, payload)
+}
+
 // DeleteMessagesBefore mocks base method.
 func (m *MockMessageQueueCRUD) DeleteMessagesBefore(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64) error {
 	m.ctrl.T.Helper()
@@ -2496,6 +2780,49 @@ func (mr *MockMessageQueueCRUDMockRecorder) DeleteMessagesInRange(ctx, queueType
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesInRange", reflect.TypeOf((*MockMessageQueueCRUD)(nil).DeleteMessagesInRange), ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID)
 }
 
+// DeleteMessagesInRangeByDomain mocks base method.
+func (m *MockMessageQueueCRUD) DeleteMessagesInRangeByDomain(ctx context.Context, queueType persistence.QueueType, domainID string, exclusiveBeginMessageID int64, inclusiveEndMessageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessagesInRangeByDomain", ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessagesInRangeByDomain indicates an expected call of DeleteMessagesInRangeByDomain.
+func (mr *MockMessageQueueCRUDMockRecorder) DeleteMessagesInRangeByDomain(ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesInRangeByDomain", reflect.TypeOf((*MockMessageQueueCRUD)(nil).DeleteMessagesInRangeByDomain), ctx, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID)
+}
+
+// DeleteMessagesSoftDeletedBefore mocks base method.
+func (m *MockMessageQueueCRUD) DeleteMessagesSoftDeletedBefore(ctx context.Context, queueType persistence.QueueType, deletedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessagesSoftDeletedBefore", ctx, queueType, deletedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessagesSoftDeletedBefore indicates an expected call of DeleteMessagesSoftDeletedBefore.
+func (mr *MockMessageQueueCRUDMockRecorder) DeleteMessagesSoftDeletedBefore(ctx, queueType, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessagesSoftDeletedBefore", reflect.TypeOf((*MockMessageQueueCRUD)(nil).DeleteMessagesSoftDeletedBefore), ctx, queueType, deletedBefore)
+}
+
+// GetQueueMessageTypeHistogram mocks base method.
+func (m *MockMessageQueueCRUD) GetQueueMessageTypeHistogram(ctx context.Context, queueType persistence.QueueType) (map[int32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueueMessageTypeHistogram", ctx, queueType)
+	ret0, _ := ret[0].(map[int32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueueMessageTypeHistogram indicates an expected call of GetQueueMessageTypeHistogram.
+func (mr *MockMessageQueueCRUDMockRecorder) GetQueueMessageTypeHistogram(ctx, queueType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueMessageTypeHistogram", reflect.TypeOf((*MockMessageQueueCRUD)(nil).GetQueueMessageTypeHistogram), ctx, queueType)
+}
+
 // GetQueueSize mocks base method.
 func (m *MockMessageQueueCRUD) GetQueueSize(ctx context.Context, queueType persistence.QueueType) (int64, error) {
 	m.ctrl.T.Helper()
@@ -2511,6 +2838,20 @@ func (mr *MockMessageQueueCRUDMockRecorder) GetQueueSize(ctx, queueType interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueueSize", reflect.TypeOf((*MockMessageQueueCRUD)(nil).GetQueueSize), ctx, queueType)
 }
 
+// InsertDLQMergeHistory mocks base method.
+func (m *MockMessageQueueCRUD) InsertDLQMergeHistory(ctx context.Context, row *DLQMergeHistoryRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDLQMergeHistory", ctx, row)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDLQMergeHistory indicates an expected call of InsertDLQMergeHistory.
+func (mr *MockMessageQueueCRUDMockRecorder) InsertDLQMergeHistory(ctx, row interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDLQMergeHistory", reflect.TypeOf((*MockMessageQueueCRUD)(nil).InsertDLQMergeHistory), ctx, row)
+}
+
 // InsertIntoQueue mocks base method.
 func (m *MockMessageQueueCRUD) InsertIntoQueue(ctx context.Context, row *QueueMessageRow) error {
 	m.ctrl.T.Helper()
@@ -2525,6 +2866,20 @@ func (mr *MockMessageQueueCRUDMockRecorder) InsertIntoQueue(ctx, row interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIntoQueue", reflect.TypeOf((*MockMessageQueueCRUD)(nil).InsertIntoQueue), ctx, row)
 }
 
+// InsertIntoQueueBatch mocks base method.
+func (m *MockMessageQueueCRUD) InsertIntoQueueBatch(ctx context.Context, rows []*QueueMessageRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertIntoQueueBatch", ctx, rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertIntoQueueBatch indicates an expected call of InsertIntoQueueBatch.
+func (mr *MockMessageQueueCRUDMockRecorder) InsertIntoQueueBatch(ctx, rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIntoQueueBatch", reflect.TypeOf((*MockMessageQueueCRUD)(nil).InsertIntoQueueBatch), ctx, rows)
+}
+
 // InsertQueueMetadata mocks base method.
 func (m *MockMessageQueueCRUD) InsertQueueMetadata(ctx context.Context, queueType persistence.QueueType, version int64) error {
 	m.ctrl.T.Helper()
@@ -2539,6 +2894,21 @@ func (mr *MockMessageQueueCRUDMockRecorder) InsertQueueMetadata(ctx, queueType,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertQueueMetadata", reflect.TypeOf((*MockMessageQueueCRUD)(nil).InsertQueueMetadata), ctx, queueType, version)
 }
 
+// SelectDLQMergeHistory mocks base method.
+func (m *MockMessageQueueCRUD) SelectDLQMergeHistory(ctx context.Context, queueType persistence.QueueType, limit int) ([]*DLQMergeHistoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDLQMergeHistory", ctx, queueType, limit)
+	ret0, _ := ret[0].([]*DLQMergeHistoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDLQMergeHistory indicates an expected call of SelectDLQMergeHistory.
+func (mr *MockMessageQueueCRUDMockRecorder) SelectDLQMergeHistory(ctx, queueType, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDLQMergeHistory", reflect.TypeOf((*MockMessageQueueCRUD)(nil).SelectDLQMergeHistory), ctx, queueType, limit)
+}
+
 // SelectLastEnqueuedMessageID mocks base method.
 func (m *MockMessageQueueCRUD) SelectLastEnqueuedMessageID(ctx context.Context, queueType persistence.QueueType) (int64, error) {
 	m.ctrl.T.Helper()
@@ -2599,6 +2969,20 @@ func (mr *MockMessageQueueCRUDMockRecorder) SelectQueueMetadata(ctx, queueType i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectQueueMetadata", reflect.TypeOf((*MockMessageQueueCRUD)(nil).SelectQueueMetadata), ctx, queueType)
 }
 
+// SoftDeleteMessagesInRange mocks base method.
+func (m *MockMessageQueueCRUD) SoftDeleteMessagesInRange(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, inclusiveEndMessageID int64, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteMessagesInRange", ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDeleteMessagesInRange indicates an expected call of SoftDeleteMessagesInRange.
+func (mr *MockMessageQueueCRUDMockRecorder) SoftDeleteMessagesInRange(ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteMessagesInRange", reflect.TypeOf((*MockMessageQueueCRUD)(nil).SoftDeleteMessagesInRange), ctx, queueType, exclusiveBeginMessageID, inclusiveEndMessageID, deletedAt)
+}
+
 // UpdateQueueMetadataCas mocks base method.
 func (m *MockMessageQueueCRUD) UpdateQueueMetadataCas(ctx context.Context, row QueueMetadataRow) error {
 	m.ctrl.T.Helper()
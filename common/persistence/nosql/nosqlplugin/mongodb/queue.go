@@ -23,12 +23,13 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql/nosqlplugin"
 )
 
-//Insert message into queue, return error if failed or already exists
+// Insert message into queue, return error if failed or already exists
 // Return ConditionFailure if the condition doesn't meet
 func (db *mdb) InsertIntoQueue(
 	ctx context.Context,
@@ -37,6 +38,15 @@ func (db *mdb) InsertIntoQueue(
 	panic("TODO")
 }
 
+// Insert messages of the same queue into queue in a single batch, return error if failed or any already exists
+// Return ConditionFailure if the condition doesn't meet
+func (db *mdb) InsertIntoQueueBatch(
+	ctx context.Context,
+	rows []*nosqlplugin.QueueMessageRow,
+) error {
+	panic("TODO")
+}
+
 // Get the ID of last message inserted into the queue
 func (db *mdb) SelectLastEnqueuedMessageID(
 	ctx context.Context,
@@ -82,6 +92,37 @@ func (db *mdb) DeleteMessagesInRange(
 	panic("TODO")
 }
 
+// Delete the messages of a domain in a range between exclusiveBeginMessageID and inclusiveEndMessageID
+func (db *mdb) DeleteMessagesInRangeByDomain(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	domainID string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+) error {
+	panic("TODO")
+}
+
+// Soft delete all messages in a range between exclusiveBeginMessageID and inclusiveEndMessageID
+func (db *mdb) SoftDeleteMessagesInRange(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+	deletedAt time.Time,
+) error {
+	panic("TODO")
+}
+
+// Delete all messages soft deleted before deletedBefore
+func (db *mdb) DeleteMessagesSoftDeletedBefore(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	deletedBefore time.Time,
+) error {
+	panic("TODO")
+}
+
 // Delete one message
 func (db *mdb) DeleteMessage(
 	ctx context.Context,
@@ -91,6 +132,26 @@ func (db *mdb) DeleteMessage(
 	panic("TODO")
 }
 
+// Update the number of attempts made to process one message
+func (db *mdb) UpdateMessageAttempts(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageID int64,
+	attempts int,
+) error {
+	panic("TODO")
+}
+
+// Replace the payload of one message
+func (db *mdb) UpdateMessagePayload(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageID int64,
+	payload []byte,
+) error {
+	panic("TODO")
+}
+
 // Insert an empty metadata row, starting from a version
 func (db *mdb) InsertQueueMetadata(
 	ctx context.Context,
@@ -126,3 +187,25 @@ func (db *mdb) GetQueueSize(
 ) (int64, error) {
 	panic("TODO")
 }
+
+func (db *mdb) GetQueueMessageTypeHistogram(
+	ctx context.Context,
+	queueType persistence.QueueType,
+) (map[int32]int64, error) {
+	panic("TODO")
+}
+
+func (db *mdb) InsertDLQMergeHistory(
+	ctx context.Context,
+	row *nosqlplugin.DLQMergeHistoryRow,
+) error {
+	panic("TODO")
+}
+
+func (db *mdb) SelectDLQMergeHistory(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	limit int,
+) ([]*nosqlplugin.DLQMergeHistoryRow, error) {
+	panic("TODO")
+}
@@ -222,10 +222,15 @@ type (
 	// SelectMessagesBetweenRequest is a request struct for SelectMessagesBetween
 	SelectMessagesBetweenRequest struct {
 		QueueType               persistence.QueueType
+		DomainID                string
 		ExclusiveBeginMessageID int64
 		InclusiveEndMessageID   int64
 		PageSize                int
 		NextPageToken           []byte
+
+		// only the messages enqueued between StartTime, inclusive, and EndTime, exclusive, are read if EndTime is set
+		StartTime time.Time
+		EndTime   time.Time
 	}
 
 	// SelectMessagesBetweenResponse is a response struct for SelectMessagesBetween
@@ -236,16 +241,34 @@ type (
 
 	// QueueMessageRow defines the row struct for queue message
 	QueueMessageRow struct {
-		QueueType persistence.QueueType
-		ID        int64
-		Payload   []byte
+		QueueType  persistence.QueueType
+		ID         int64
+		Payload    []byte
+		Attempts   int
+		EnqueuedAt time.Time
+		DomainID   string
+		TaskType   *int32
 	}
 
 	// QueueMetadataRow defines the row struct for metadata
 	QueueMetadataRow struct {
-		QueueType        persistence.QueueType
-		ClusterAckLevels map[string]int64
-		Version          int64
+		QueueType          persistence.QueueType
+		ClusterAckLevels   map[string]int64
+		ClusterMergeTokens map[string]string
+		Version            int64
+	}
+
+	// DLQMergeHistoryRow defines the row struct for a DLQ merge record
+	DLQMergeHistoryRow struct {
+		QueueType      persistence.QueueType
+		MergedAt       time.Time
+		StartMessageID int64
+		EndMessageID   int64
+		MergedCount    int64
+		FailedCount    int64
+		Duration       time.Duration
+		TriggeredBy    string
+		TraceID        string
 	}
 
 	// HistoryNodeRow represents a row in history_node table
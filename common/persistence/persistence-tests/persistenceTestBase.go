@@ -1880,6 +1880,8 @@ func (s *TestBase) GetAckLevels(
 // PublishToDomainDLQ is a utility method to add messages to the domain DLQ
 func (s *TestBase) PublishToDomainDLQ(
 	ctx context.Context,
+	domainID string,
+	taskType *int32,
 	messagePayload []byte,
 ) error {
 
@@ -1894,7 +1896,28 @@ func (s *TestBase) PublishToDomainDLQ(
 		}),
 	)
 	return throttleRetry.Do(ctx, func() error {
-		return s.DomainReplicationQueueMgr.EnqueueMessageToDLQ(ctx, messagePayload)
+		return s.DomainReplicationQueueMgr.EnqueueMessageToDLQ(ctx, domainID, taskType, messagePayload)
+	})
+}
+
+// PublishBatchToDomainDLQ is a utility method to add a batch of messages to the domain DLQ
+func (s *TestBase) PublishBatchToDomainDLQ(
+	ctx context.Context,
+	messages []*persistence.QueueMessage,
+) error {
+
+	retryPolicy := backoff.NewExponentialRetryPolicy(100 * time.Millisecond)
+	retryPolicy.SetBackoffCoefficient(1.5)
+	retryPolicy.SetMaximumAttempts(5)
+
+	throttleRetry := backoff.NewThrottleRetry(
+		backoff.WithRetryPolicy(retryPolicy),
+		backoff.WithRetryableError(func(e error) bool {
+			return persistence.IsTransientError(e) || isMessageIDConflictError(e)
+		}),
+	)
+	return throttleRetry.Do(ctx, func() error {
+		return s.DomainReplicationQueueMgr.EnqueueMessagesToDLQ(ctx, messages)
 	})
 }
 
@@ -1916,6 +1939,48 @@ func (s *TestBase) GetMessagesFromDomainDLQ(
 	)
 }
 
+// GetMessagesFromDomainDLQByDomain is a utility method to get the messages of a domain from the domain DLQ
+func (s *TestBase) GetMessagesFromDomainDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*persistence.QueueMessage, []byte, error) {
+
+	return s.DomainReplicationQueueMgr.ReadMessagesFromDLQByDomain(
+		ctx,
+		domainID,
+		firstMessageID,
+		lastMessageID,
+		pageSize,
+		pageToken,
+	)
+}
+
+// GetMessagesFromDomainDLQByTimeRange is a utility method to get the messages enqueued in a time range from the domain DLQ
+func (s *TestBase) GetMessagesFromDomainDLQByTimeRange(
+	ctx context.Context,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*persistence.QueueMessage, []byte, error) {
+
+	return s.DomainReplicationQueueMgr.ReadMessagesFromDLQByTimeRange(
+		ctx,
+		startTime,
+		endTime,
+		firstMessageID,
+		lastMessageID,
+		pageSize,
+		pageToken,
+	)
+}
+
 // UpdateDomainDLQAckLevel updates domain dlq ack level
 func (s *TestBase) UpdateDomainDLQAckLevel(
 	ctx context.Context,
@@ -1926,6 +1991,35 @@ func (s *TestBase) UpdateDomainDLQAckLevel(
 	return s.DomainReplicationQueueMgr.UpdateDLQAckLevel(ctx, lastProcessedMessageID, clusterName)
 }
 
+// CompareAndSwapDomainDLQAckLevel updates domain dlq ack level if it matches the expected ack level
+func (s *TestBase) CompareAndSwapDomainDLQAckLevel(
+	ctx context.Context,
+	expectedMessageID int64,
+	lastProcessedMessageID int64,
+	clusterName string,
+) error {
+
+	return s.DomainReplicationQueueMgr.CompareAndSwapDLQAckLevel(ctx, expectedMessageID, lastProcessedMessageID, clusterName)
+}
+
+// UpdateDomainDLQAckLevelWithVersion updates domain dlq ack level if the dlq metadata is at the expected version
+func (s *TestBase) UpdateDomainDLQAckLevelWithVersion(
+	ctx context.Context,
+	lastProcessedMessageID int64,
+	clusterName string,
+	expectedVersion int64,
+) error {
+
+	return s.DomainReplicationQueueMgr.UpdateDLQAckLevelWithVersion(ctx, lastProcessedMessageID, clusterName, expectedVersion)
+}
+
+// GetDomainDLQAckLevelsWithVersion returns domain dlq ack levels with the version of the dlq metadata
+func (s *TestBase) GetDomainDLQAckLevelsWithVersion(
+	ctx context.Context,
+) (map[string]int64, int64, error) {
+	return s.DomainReplicationQueueMgr.GetDLQAckLevelsWithVersion(ctx)
+}
+
 // GetDomainDLQAckLevel returns domain dlq ack level
 func (s *TestBase) GetDomainDLQAckLevel(
 	ctx context.Context,
@@ -1933,6 +2027,40 @@ func (s *TestBase) GetDomainDLQAckLevel(
 	return s.DomainReplicationQueueMgr.GetDLQAckLevels(ctx)
 }
 
+// UpdateDomainDLQMergeToken updates domain dlq merge token
+func (s *TestBase) UpdateDomainDLQMergeToken(
+	ctx context.Context,
+	token string,
+	clusterName string,
+) error {
+
+	return s.DomainReplicationQueueMgr.UpdateDLQMergeToken(ctx, token, clusterName)
+}
+
+// GetDomainDLQMergeTokens returns domain dlq merge tokens
+func (s *TestBase) GetDomainDLQMergeTokens(
+	ctx context.Context,
+) (map[string]string, error) {
+	return s.DomainReplicationQueueMgr.GetDLQMergeTokens(ctx)
+}
+
+// InsertDomainDLQMergeRecord records a batch of merged domain dlq messages
+func (s *TestBase) InsertDomainDLQMergeRecord(
+	ctx context.Context,
+	record *persistence.DLQMergeRecord,
+) error {
+
+	return s.DomainReplicationQueueMgr.InsertDLQMergeRecord(ctx, record)
+}
+
+// GetDomainDLQMergeHistory returns the most recent domain dlq merge records
+func (s *TestBase) GetDomainDLQMergeHistory(
+	ctx context.Context,
+	limit int,
+) ([]*persistence.DLQMergeRecord, error) {
+	return s.DomainReplicationQueueMgr.GetDLQMergeHistory(ctx, limit)
+}
+
 // GetDomainDLQSize returns domain dlq size
 func (s *TestBase) GetDomainDLQSize(
 	ctx context.Context,
@@ -1940,6 +2068,13 @@ func (s *TestBase) GetDomainDLQSize(
 	return s.DomainReplicationQueueMgr.GetDLQSize(ctx)
 }
 
+// GetDomainDLQMessageTypeHistogram returns the number of domain dlq messages of each task type
+func (s *TestBase) GetDomainDLQMessageTypeHistogram(
+	ctx context.Context,
+) (map[int32]int64, error) {
+	return s.DomainReplicationQueueMgr.GetDLQMessageTypeHistogram(ctx)
+}
+
 // DeleteMessageFromDomainDLQ deletes one message from domain DLQ
 func (s *TestBase) DeleteMessageFromDomainDLQ(
 	ctx context.Context,
@@ -1959,6 +2094,36 @@ func (s *TestBase) RangeDeleteMessagesFromDomainDLQ(
 	return s.DomainReplicationQueueMgr.RangeDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
 }
 
+// RangeDeleteMessagesFromDomainDLQByDomain deletes the messages of a domain from domain DLQ
+func (s *TestBase) RangeDeleteMessagesFromDomainDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+
+	return s.DomainReplicationQueueMgr.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID)
+}
+
+// RangeSoftDeleteMessagesFromDomainDLQ soft deletes messages from domain DLQ
+func (s *TestBase) RangeSoftDeleteMessagesFromDomainDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+
+	return s.DomainReplicationQueueMgr.RangeSoftDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
+}
+
+// DeleteSoftDeletedMessagesFromDomainDLQ deletes the messages soft deleted before deletedBefore from domain DLQ
+func (s *TestBase) DeleteSoftDeletedMessagesFromDomainDLQ(
+	ctx context.Context,
+	deletedBefore time.Time,
+) error {
+
+	return s.DomainReplicationQueueMgr.DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore)
+}
+
 // GenerateTransferTaskIDs helper
 func (g *TestTransferTaskIDGenerator) GenerateTransferTaskIDs(number int) ([]int64, error) {
 	result := []int64{}
@@ -22,12 +22,17 @@ package persistencetests
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
+
+	p "github.com/uber/cadence/common/persistence"
 )
 
 type (
@@ -156,7 +161,7 @@ func (s *QueuePersistenceSuite) TestDomainReplicationDLQ() {
 		go func() {
 			defer wg.Done()
 			for message := range messageChan {
-				err := s.PublishToDomainDLQ(ctx, message)
+				err := s.PublishToDomainDLQ(ctx, "", nil, message)
 				s.Nil(err, "Enqueue message failed.")
 			}
 		}()
@@ -171,6 +176,9 @@ func (s *QueuePersistenceSuite) TestDomainReplicationDLQ() {
 	s.Nil(err, "GetReplicationMessages failed.")
 	s.Equal(len(token), 0)
 	s.Equal(len(result1)+len(result2), numMessages)
+	for _, message := range append(result1, result2...) {
+		s.False(message.EnqueuedAt.IsZero())
+	}
 	_, _, err = s.GetMessagesFromDomainDLQ(ctx, -1, 1<<63-1, numMessages, nil)
 	s.NoError(err, "GetReplicationMessages failed.")
 	s.Equal(len(token), 0)
@@ -195,6 +203,231 @@ func (s *QueuePersistenceSuite) TestDomainReplicationDLQ() {
 	s.Equal(len(result4), 0)
 }
 
+// TestDomainReplicationDLQBatch tests enqueuing a batch of messages to the domain DLQ
+func (s *QueuePersistenceSuite) TestDomainReplicationDLQBatch() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	sizeBefore, err := s.GetDomainDLQSize(ctx)
+	s.NoError(err, "GetDomainDLQSize failed")
+
+	numMessages := 20
+	var messages []*p.QueueMessage
+	for i := 0; i < numMessages; i++ {
+		messages = append(messages, &p.QueueMessage{Payload: []byte{byte(i)}})
+	}
+	err = s.PublishBatchToDomainDLQ(ctx, messages)
+	s.NoError(err, "Enqueue message batch failed.")
+	err = s.PublishBatchToDomainDLQ(ctx, nil)
+	s.NoError(err, "Enqueue empty message batch failed.")
+
+	size, err := s.GetDomainDLQSize(ctx)
+	s.NoError(err, "GetDomainDLQSize failed")
+	s.Equal(sizeBefore+int64(numMessages), size)
+
+	result, _, err := s.GetMessagesFromDomainDLQ(ctx, -1, 1<<63-1, int(size), nil)
+	s.NoError(err, "GetReplicationMessages failed.")
+	s.Len(result, int(size))
+	batch := result[len(result)-numMessages:]
+	for i, message := range batch {
+		s.Equal(messages[i].Payload, message.Payload)
+		s.Equal(batch[0].ID+int64(i), message.ID)
+		s.False(message.EnqueuedAt.IsZero())
+	}
+}
+
+// TestDomainReplicationDLQByDomain tests reading and deleting the domain DLQ messages of a single domain
+func (s *QueuePersistenceSuite) TestDomainReplicationDLQByDomain() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID1 := uuid.New()
+	domainID2 := uuid.New()
+	numMessages := 10
+	var messages []*p.QueueMessage
+	for i := 0; i < numMessages; i++ {
+		domainID := domainID1
+		if i%2 == 1 {
+			domainID = domainID2
+		}
+		messages = append(messages, &p.QueueMessage{Payload: []byte{byte(i)}, DomainID: domainID})
+	}
+	err := s.PublishBatchToDomainDLQ(ctx, messages)
+	s.NoError(err, "Enqueue message batch failed.")
+	err = s.PublishToDomainDLQ(ctx, domainID1, nil, []byte{byte(numMessages)})
+	s.NoError(err, "Enqueue message failed.")
+
+	result1, _, err := s.GetMessagesFromDomainDLQByDomain(ctx, domainID1, -1, 1<<63-1, numMessages, nil)
+	s.NoError(err, "GetMessagesFromDomainDLQByDomain failed.")
+	s.Len(result1, numMessages/2+1)
+	for i, message := range result1 {
+		s.Equal(domainID1, message.DomainID)
+		s.Equal([]byte{byte(2 * i)}, message.Payload)
+	}
+	result2, _, err := s.GetMessagesFromDomainDLQByDomain(ctx, domainID2, -1, 1<<63-1, numMessages, nil)
+	s.NoError(err, "GetMessagesFromDomainDLQByDomain failed.")
+	s.Len(result2, numMessages/2)
+	for i, message := range result2 {
+		s.Equal(domainID2, message.DomainID)
+		s.Equal([]byte{byte(2*i + 1)}, message.Payload)
+	}
+
+	err = s.RangeDeleteMessagesFromDomainDLQByDomain(ctx, domainID1, -1, 1<<63-1)
+	s.NoError(err, "RangeDeleteMessagesFromDomainDLQByDomain failed.")
+
+	result1, _, err = s.GetMessagesFromDomainDLQByDomain(ctx, domainID1, -1, 1<<63-1, numMessages, nil)
+	s.NoError(err, "GetMessagesFromDomainDLQByDomain failed.")
+	s.Empty(result1)
+	result2, _, err = s.GetMessagesFromDomainDLQByDomain(ctx, domainID2, -1, 1<<63-1, numMessages, nil)
+	s.NoError(err, "GetMessagesFromDomainDLQByDomain failed.")
+	s.Len(result2, numMessages/2)
+}
+
+// TestDomainReplicationDLQByTimeRange tests reading the domain DLQ messages enqueued in a time range
+func (s *QueuePersistenceSuite) TestDomainReplicationDLQByTimeRange() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	var oldMessages []*p.QueueMessage
+	for i := 0; i < 3; i++ {
+		oldMessages = append(oldMessages, &p.QueueMessage{Payload: []byte{byte(i)}})
+	}
+	err := s.PublishBatchToDomainDLQ(ctx, oldMessages)
+	s.NoError(err, "Enqueue message batch failed.")
+	time.Sleep(100 * time.Millisecond)
+	startTime := time.Now()
+	time.Sleep(100 * time.Millisecond)
+	var newMessages []*p.QueueMessage
+	for i := 0; i < 4; i++ {
+		newMessages = append(newMessages, &p.QueueMessage{Payload: []byte{byte(10 + i)}})
+	}
+	err = s.PublishBatchToDomainDLQ(ctx, newMessages)
+	s.NoError(err, "Enqueue message batch failed.")
+	endTime := time.Now().Add(time.Minute)
+
+	// a filtered page may hold fewer messages than the page size, so pages are read until there is no next page
+	var result []*p.QueueMessage
+	var token []byte
+	for {
+		var page []*p.QueueMessage
+		page, token, err = s.GetMessagesFromDomainDLQByTimeRange(ctx, startTime, endTime, -1, 1<<63-1, 2, token)
+		s.NoError(err, "GetMessagesFromDomainDLQByTimeRange failed.")
+		result = append(result, page...)
+		if len(token) == 0 {
+			break
+		}
+	}
+	s.Len(result, len(newMessages))
+	for i, message := range result {
+		s.Equal(newMessages[i].Payload, message.Payload)
+		s.False(message.EnqueuedAt.Before(startTime))
+	}
+
+	result, _, err = s.GetMessagesFromDomainDLQByTimeRange(ctx, startTime.Add(-time.Minute), startTime, -1, 1<<63-1, 100, nil)
+	s.NoError(err, "GetMessagesFromDomainDLQByTimeRange failed.")
+	s.True(len(result) >= len(oldMessages))
+	for i, message := range result[len(result)-len(oldMessages):] {
+		s.Equal(oldMessages[i].Payload, message.Payload)
+		s.True(message.EnqueuedAt.Before(startTime))
+	}
+}
+
+// TestDomainReplicationDLQMessageTypeHistogram tests counting domain DLQ messages by task type
+func (s *QueuePersistenceSuite) TestDomainReplicationDLQMessageTypeHistogram() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	histogramBefore, err := s.GetDomainDLQMessageTypeHistogram(ctx)
+	s.NoError(err, "GetDomainDLQMessageTypeHistogram failed.")
+
+	taskType1 := int32(0)
+	taskType2 := int32(1)
+	var messages []*p.QueueMessage
+	for i := 0; i < 5; i++ {
+		taskType := &taskType1
+		if i%2 == 1 {
+			taskType = &taskType2
+		}
+		messages = append(messages, &p.QueueMessage{Payload: []byte{byte(i)}, TaskType: taskType})
+	}
+	err = s.PublishBatchToDomainDLQ(ctx, messages)
+	s.NoError(err, "Enqueue message batch failed.")
+	err = s.PublishToDomainDLQ(ctx, "", &taskType2, []byte{5})
+	s.NoError(err, "Enqueue message failed.")
+	// messages without a task type are not counted
+	err = s.PublishToDomainDLQ(ctx, "", nil, []byte{6})
+	s.NoError(err, "Enqueue message failed.")
+
+	histogramAfter, err := s.GetDomainDLQMessageTypeHistogram(ctx)
+	s.NoError(err, "GetDomainDLQMessageTypeHistogram failed.")
+	s.Equal(histogramBefore[taskType1]+3, histogramAfter[taskType1])
+	s.Equal(histogramBefore[taskType2]+3, histogramAfter[taskType2])
+}
+
+// TestDomainReplicationDLQSoftDelete tests soft deleting domain DLQ messages and garbage collecting them
+func (s *QueuePersistenceSuite) TestDomainReplicationDLQSoftDelete() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	sizeBefore, err := s.GetDomainDLQSize(ctx)
+	s.NoError(err, "GetDomainDLQSize failed")
+
+	domainID := uuid.New()
+	numMessages := 10
+	var messages []*p.QueueMessage
+	for i := 0; i < numMessages; i++ {
+		messages = append(messages, &p.QueueMessage{Payload: []byte{byte(i)}, DomainID: domainID})
+	}
+	err = s.PublishBatchToDomainDLQ(ctx, messages)
+	s.NoError(err, "Enqueue message batch failed.")
+
+	result, _, err := s.GetMessagesFromDomainDLQByDomain(ctx, domainID, -1, 1<<63-1, numMessages, nil)
+	s.NoError(err, "GetMessagesFromDomainDLQByDomain failed.")
+	s.Require().Len(result, numMessages)
+	firstMessageID := result[0].ID - 1
+	lastMessageID := result[numMessages-1].ID
+
+	err = s.RangeSoftDeleteMessagesFromDomainDLQ(ctx, firstMessageID, result[numMessages/2-1].ID)
+	s.NoError(err, "RangeSoftDeleteMessagesFromDomainDLQ failed.")
+
+	// soft deleted messages are neither read nor counted
+	remaining, _, err := s.GetMessagesFromDomainDLQ(ctx, firstMessageID, lastMessageID, numMessages, nil)
+	s.NoError(err, "GetReplicationMessages failed.")
+	s.Equal(result[numMessages/2:], remaining)
+	remaining, _, err = s.GetMessagesFromDomainDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, numMessages, nil)
+	s.NoError(err, "GetMessagesFromDomainDLQByDomain failed.")
+	s.Equal(result[numMessages/2:], remaining)
+
+	// a page is filled with the messages after the soft deleted ones, even when they take up a whole page
+	page, _, err := s.GetMessagesFromDomainDLQ(ctx, firstMessageID, lastMessageID, numMessages/2, nil)
+	s.NoError(err, "GetReplicationMessages failed.")
+	s.Equal(result[numMessages/2:], page)
+	page, _, err = s.GetMessagesFromDomainDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, numMessages/2, nil)
+	s.NoError(err, "GetMessagesFromDomainDLQByDomain failed.")
+	s.Equal(result[numMessages/2:], page)
+	page, token, err := s.GetMessagesFromDomainDLQ(ctx, firstMessageID, result[numMessages/2-1].ID, numMessages/2, nil)
+	s.NoError(err, "GetReplicationMessages failed.")
+	s.Empty(page)
+	s.Empty(token)
+
+	size, err := s.GetDomainDLQSize(ctx)
+	s.NoError(err, "GetDomainDLQSize failed")
+	s.Equal(sizeBefore+int64(numMessages/2), size)
+
+	err = s.DeleteSoftDeletedMessagesFromDomainDLQ(ctx, time.Now().Add(-time.Hour))
+	s.NoError(err, "DeleteSoftDeletedMessagesFromDomainDLQ failed.")
+	err = s.DeleteSoftDeletedMessagesFromDomainDLQ(ctx, time.Now().Add(time.Minute))
+	s.NoError(err, "DeleteSoftDeletedMessagesFromDomainDLQ failed.")
+
+	// garbage collection only removes the soft deleted messages
+	remaining, _, err = s.GetMessagesFromDomainDLQ(ctx, firstMessageID, lastMessageID, numMessages, nil)
+	s.NoError(err, "GetReplicationMessages failed.")
+	s.Equal(result[numMessages/2:], remaining)
+	size, err = s.GetDomainDLQSize(ctx)
+	s.NoError(err, "GetDomainDLQSize failed")
+	s.Equal(sizeBefore+int64(numMessages/2), size)
+}
+
 // TestDomainDLQMetadataOperations tests queue metadata operations
 func (s *QueuePersistenceSuite) TestDomainDLQMetadataOperations() {
 	clusterName := "test"
@@ -219,3 +452,185 @@ func (s *QueuePersistenceSuite) TestDomainDLQMetadataOperations() {
 	s.Require().NoError(err)
 	s.Equal(int64(10), ackLevel[clusterName])
 }
+
+// TestDomainDLQAckLevelCompareAndSwap tests conditional update of the dlq ack level
+func (s *QueuePersistenceSuite) TestDomainDLQAckLevelCompareAndSwap() {
+	clusterName := "test-cas"
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	err := s.CompareAndSwapDomainDLQAckLevel(ctx, -1, 10, clusterName)
+	s.NoError(err)
+
+	ackLevel, err := s.GetDomainDLQAckLevel(ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(10), ackLevel[clusterName])
+
+	err = s.CompareAndSwapDomainDLQAckLevel(ctx, -1, 5, clusterName)
+	s.IsType(&p.ConditionFailedError{}, err)
+
+	err = s.CompareAndSwapDomainDLQAckLevel(ctx, 10, 20, clusterName)
+	s.NoError(err)
+
+	ackLevel, err = s.GetDomainDLQAckLevel(ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(20), ackLevel[clusterName])
+}
+
+// TestDomainDLQAckLevelWithVersion tests optimistic updates of the dlq ack level
+func (s *QueuePersistenceSuite) TestDomainDLQAckLevelWithVersion() {
+	clusterName := "test-version"
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	_, version, err := s.GetDomainDLQAckLevelsWithVersion(ctx)
+	s.Require().NoError(err)
+
+	err = s.UpdateDomainDLQAckLevelWithVersion(ctx, 10, clusterName, version)
+	s.NoError(err)
+
+	ackLevels, newVersion, err := s.GetDomainDLQAckLevelsWithVersion(ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(10), ackLevels[clusterName])
+	s.NotEqual(version, newVersion)
+
+	// the version read before the update is stale
+	err = s.UpdateDomainDLQAckLevelWithVersion(ctx, 20, clusterName, version)
+	s.IsType(&p.ConditionFailedError{}, err)
+
+	// an update of any ack level changes the version
+	err = s.UpdateDomainDLQAckLevel(ctx, 5, clusterName+"-other")
+	s.NoError(err)
+	err = s.UpdateDomainDLQAckLevelWithVersion(ctx, 20, clusterName, newVersion)
+	s.IsType(&p.ConditionFailedError{}, err)
+
+	ackLevels, _, err = s.GetDomainDLQAckLevelsWithVersion(ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(10), ackLevels[clusterName])
+}
+
+// TestDomainDLQAckLevelWithVersion_Concurrent tests no concurrent optimistic update of the dlq ack level is lost
+func (s *QueuePersistenceSuite) TestDomainDLQAckLevelWithVersion_Concurrent() {
+	clusterName := "test-version-concurrent"
+	concurrency := 10
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				ackLevels, version, err := s.GetDomainDLQAckLevelsWithVersion(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+				err = s.UpdateDomainDLQAckLevelWithVersion(ctx, ackLevels[clusterName]+1, clusterName, version)
+				if _, ok := err.(*p.ConditionFailedError); ok {
+					continue
+				}
+				errs <- err
+				return
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		s.NoError(err)
+	}
+
+	ackLevels, _, err := s.GetDomainDLQAckLevelsWithVersion(ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(concurrency), ackLevels[clusterName])
+}
+
+// TestDomainDLQMergeTokenOperations tests queue merge token operations
+func (s *QueuePersistenceSuite) TestDomainDLQMergeTokenOperations() {
+	clusterName := "test"
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	mergeTokens, err := s.GetDomainDLQMergeTokens(ctx)
+	s.Require().NoError(err)
+	s.Equal(0, len(mergeTokens))
+
+	err = s.UpdateDomainDLQMergeToken(ctx, "token1", clusterName)
+	s.NoError(err)
+
+	mergeTokens, err = s.GetDomainDLQMergeTokens(ctx)
+	s.Require().NoError(err)
+	s.Equal("token1", mergeTokens[clusterName])
+
+	err = s.UpdateDomainDLQMergeToken(ctx, "token2", clusterName)
+	s.NoError(err)
+
+	mergeTokens, err = s.GetDomainDLQMergeTokens(ctx)
+	s.Require().NoError(err)
+	s.Equal("token2", mergeTokens[clusterName])
+}
+
+// TestDomainDLQMergeTokenOperations_Concurrent tests no concurrent update of the merge tokens of different keys is lost
+func (s *QueuePersistenceSuite) TestDomainDLQMergeTokenOperations_Concurrent() {
+	concurrency := 5
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- s.UpdateDomainDLQMergeToken(ctx, fmt.Sprintf("token%v", i), fmt.Sprintf("test-concurrent-%v", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		s.NoError(err)
+	}
+
+	mergeTokens, err := s.GetDomainDLQMergeTokens(ctx)
+	s.Require().NoError(err)
+	for i := 0; i < concurrency; i++ {
+		s.Equal(fmt.Sprintf("token%v", i), mergeTokens[fmt.Sprintf("test-concurrent-%v", i)])
+	}
+}
+
+// TestDomainDLQMergeHistory tests recording and reading domain dlq merge history
+func (s *QueuePersistenceSuite) TestDomainDLQMergeHistory() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i := int64(0); i < 3; i++ {
+		err := s.InsertDomainDLQMergeRecord(ctx, &p.DLQMergeRecord{
+			MergedAt:       now.Add(time.Duration(i) * time.Second),
+			StartMessageID: i*10 + 1,
+			EndMessageID:   i*10 + 10,
+			MergedCount:    9,
+			FailedCount:    1,
+			Duration:       time.Duration(i+1) * time.Millisecond,
+			TriggeredBy:    "test",
+			TraceID:        fmt.Sprintf("trace-%v", i),
+		})
+		s.Require().NoError(err)
+	}
+
+	history, err := s.GetDomainDLQMergeHistory(ctx, 2)
+	s.Require().NoError(err)
+	s.Require().Len(history, 2)
+	s.Equal(int64(21), history[0].StartMessageID)
+	s.Equal(int64(30), history[0].EndMessageID)
+	s.Equal(int64(9), history[0].MergedCount)
+	s.Equal(int64(1), history[0].FailedCount)
+	s.Equal(3*time.Millisecond, history[0].Duration)
+	s.Equal("test", history[0].TriggeredBy)
+	s.Equal("trace-2", history[0].TraceID)
+	s.True(now.Add(2 * time.Second).Equal(history[0].MergedAt))
+	s.Equal(int64(11), history[1].StartMessageID)
+}
@@ -23,6 +23,7 @@ package persistence
 import (
 	"context"
 	"math/rand"
+	"time"
 
 	"github.com/uber/cadence/common/errors"
 	"github.com/uber/cadence/common/log"
@@ -2107,6 +2108,8 @@ func (p *queueErrorInjectionPersistenceClient) DeleteMessagesBefore(
 
 func (p *queueErrorInjectionPersistenceClient) EnqueueMessageToDLQ(
 	ctx context.Context,
+	domainID string,
+	taskType *int32,
 	message []byte,
 ) error {
 	fakeErr := generateFakeError(p.errorRate)
@@ -2114,7 +2117,7 @@ func (p *queueErrorInjectionPersistenceClient) EnqueueMessageToDLQ(
 	var persistenceErr error
 	var forwardCall bool
 	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
-		persistenceErr = p.persistence.EnqueueMessageToDLQ(ctx, message)
+		persistenceErr = p.persistence.EnqueueMessageToDLQ(ctx, domainID, taskType, message)
 	}
 
 	if fakeErr != nil {
@@ -2129,6 +2132,30 @@ func (p *queueErrorInjectionPersistenceClient) EnqueueMessageToDLQ(
 	return persistenceErr
 }
 
+func (p *queueErrorInjectionPersistenceClient) EnqueueMessagesToDLQ(
+	ctx context.Context,
+	messages []*QueueMessage,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.EnqueueMessagesToDLQ(ctx, messages)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationEnqueueMessagesToDLQ,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *queueErrorInjectionPersistenceClient) ReadMessagesFromDLQ(
 	ctx context.Context,
 	firstMessageID int64,
@@ -2158,6 +2185,36 @@ func (p *queueErrorInjectionPersistenceClient) ReadMessagesFromDLQ(
 	return response, token, persistenceErr
 }
 
+func (p *queueErrorInjectionPersistenceClient) ReadMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*QueueMessage, []byte, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response []*QueueMessage
+	var token []byte
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, token, persistenceErr = p.persistence.ReadMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationReadMessagesFromDLQByDomain,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, nil, fakeErr
+	}
+	return response, token, persistenceErr
+}
+
 func (p *queueErrorInjectionPersistenceClient) RangeDeleteMessagesFromDLQ(
 	ctx context.Context,
 	firstMessageID int64,
@@ -2183,6 +2240,112 @@ func (p *queueErrorInjectionPersistenceClient) RangeDeleteMessagesFromDLQ(
 	return persistenceErr
 }
 
+func (p *queueErrorInjectionPersistenceClient) ReadMessagesFromDLQByTimeRange(
+	ctx context.Context,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*QueueMessage, []byte, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response []*QueueMessage
+	var token []byte
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, token, persistenceErr = p.persistence.ReadMessagesFromDLQByTimeRange(ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationReadMessagesFromDLQByTimeRange,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, nil, fakeErr
+	}
+	return response, token, persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) RangeDeleteMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationRangeDeleteMessagesFromDLQByDomain,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) RangeSoftDeleteMessagesFromDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.RangeSoftDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationRangeSoftDeleteMessagesFromDLQ,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) DeleteSoftDeletedMessagesFromDLQ(
+	ctx context.Context,
+	deletedBefore time.Time,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationDeleteSoftDeletedMessagesFromDLQ,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *queueErrorInjectionPersistenceClient) UpdateDLQAckLevel(
 	ctx context.Context,
 	messageID int64,
@@ -2208,6 +2371,32 @@ func (p *queueErrorInjectionPersistenceClient) UpdateDLQAckLevel(
 	return persistenceErr
 }
 
+func (p *queueErrorInjectionPersistenceClient) CompareAndSwapDLQAckLevel(
+	ctx context.Context,
+	expectedMessageID int64,
+	messageID int64,
+	clusterName string,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.CompareAndSwapDLQAckLevel(ctx, expectedMessageID, messageID, clusterName)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationCompareAndSwapDLQAckLevel,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *queueErrorInjectionPersistenceClient) GetDLQAckLevels(
 	ctx context.Context,
 ) (map[string]int64, error) {
@@ -2232,6 +2421,57 @@ func (p *queueErrorInjectionPersistenceClient) GetDLQAckLevels(
 	return response, persistenceErr
 }
 
+func (p *queueErrorInjectionPersistenceClient) UpdateDLQAckLevelWithVersion(
+	ctx context.Context,
+	messageID int64,
+	clusterName string,
+	expectedVersion int64,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.UpdateDLQAckLevelWithVersion(ctx, messageID, clusterName, expectedVersion)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationUpdateDLQAckLevelWithVersion,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) GetDLQAckLevelsWithVersion(
+	ctx context.Context,
+) (map[string]int64, int64, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response map[string]int64
+	var version int64
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, version, persistenceErr = p.persistence.GetDLQAckLevelsWithVersion(ctx)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetDLQAckLevelsWithVersion,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, 0, fakeErr
+	}
+	return response, version, persistenceErr
+}
+
 func (p *queueErrorInjectionPersistenceClient) GetDLQSize(
 	ctx context.Context,
 ) (int64, error) {
@@ -2256,6 +2496,178 @@ func (p *queueErrorInjectionPersistenceClient) GetDLQSize(
 	return response, persistenceErr
 }
 
+func (p *queueErrorInjectionPersistenceClient) GetDLQMessageTypeHistogram(
+	ctx context.Context,
+) (map[int32]int64, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response map[int32]int64
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetDLQMessageTypeHistogram(ctx)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetDLQMessageTypeHistogram,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) UpdateDLQMessageAttempts(
+	ctx context.Context,
+	messageID int64,
+	attempts int,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.UpdateDLQMessageAttempts(ctx, messageID, attempts)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationUpdateDLQMessageAttempts,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) UpdateDLQMessagePayload(
+	ctx context.Context,
+	messageID int64,
+	payload []byte,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.UpdateDLQMessagePayload(ctx, messageID, payload)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationUpdateDLQMessagePayload,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) UpdateDLQMergeToken(
+	ctx context.Context,
+	token string,
+	clusterName string,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.UpdateDLQMergeToken(ctx, token, clusterName)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationUpdateDLQMergeToken,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) GetDLQMergeTokens(
+	ctx context.Context,
+) (map[string]string, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response map[string]string
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetDLQMergeTokens(ctx)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetDLQMergeTokens,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) InsertDLQMergeRecord(
+	ctx context.Context,
+	record *DLQMergeRecord,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.InsertDLQMergeRecord(ctx, record)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationInsertDLQMergeRecord,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *queueErrorInjectionPersistenceClient) GetDLQMergeHistory(
+	ctx context.Context,
+	limit int,
+) ([]*DLQMergeRecord, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response []*DLQMergeRecord
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetDLQMergeHistory(ctx, limit)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetDLQMergeHistory,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *queueErrorInjectionPersistenceClient) DeleteMessageFromDLQ(
 	ctx context.Context,
 	messageID int64,
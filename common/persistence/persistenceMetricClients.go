@@ -1435,14 +1435,26 @@ func (p *queuePersistenceClient) DeleteMessagesBefore(
 
 func (p *queuePersistenceClient) EnqueueMessageToDLQ(
 	ctx context.Context,
+	domainID string,
+	taskType *int32,
 	message []byte,
 ) error {
 	op := func() error {
-		return p.persistence.EnqueueMessageToDLQ(ctx, message)
+		return p.persistence.EnqueueMessageToDLQ(ctx, domainID, taskType, message)
 	}
 	return p.call(metrics.PersistenceEnqueueMessageToDLQScope, op)
 }
 
+func (p *queuePersistenceClient) EnqueueMessagesToDLQ(
+	ctx context.Context,
+	messages []*QueueMessage,
+) error {
+	op := func() error {
+		return p.persistence.EnqueueMessagesToDLQ(ctx, messages)
+	}
+	return p.call(metrics.PersistenceEnqueueMessagesToDLQScope, op)
+}
+
 func (p *queuePersistenceClient) ReadMessagesFromDLQ(
 	ctx context.Context,
 	firstMessageID int64,
@@ -1464,6 +1476,28 @@ func (p *queuePersistenceClient) ReadMessagesFromDLQ(
 	return result, token, nil
 }
 
+func (p *queuePersistenceClient) ReadMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*QueueMessage, []byte, error) {
+	var result []*QueueMessage
+	var token []byte
+	op := func() error {
+		var err error
+		result, token, err = p.persistence.ReadMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken)
+		return err
+	}
+	err := p.call(metrics.PersistenceReadQueueMessagesFromDLQByDomainScope, op)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, token, nil
+}
+
 func (p *queuePersistenceClient) DeleteMessageFromDLQ(
 	ctx context.Context,
 	messageID int64,
@@ -1485,6 +1519,62 @@ func (p *queuePersistenceClient) RangeDeleteMessagesFromDLQ(
 	return p.call(metrics.PersistenceRangeDeleteMessagesFromDLQScope, op)
 }
 
+func (p *queuePersistenceClient) ReadMessagesFromDLQByTimeRange(
+	ctx context.Context,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*QueueMessage, []byte, error) {
+	var result []*QueueMessage
+	var token []byte
+	op := func() error {
+		var err error
+		result, token, err = p.persistence.ReadMessagesFromDLQByTimeRange(ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken)
+		return err
+	}
+	err := p.call(metrics.PersistenceReadQueueMessagesFromDLQByTimeRangeScope, op)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, token, nil
+}
+
+func (p *queuePersistenceClient) RangeDeleteMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	op := func() error {
+		return p.persistence.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID)
+	}
+	return p.call(metrics.PersistenceRangeDeleteMessagesFromDLQByDomainScope, op)
+}
+
+func (p *queuePersistenceClient) RangeSoftDeleteMessagesFromDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	op := func() error {
+		return p.persistence.RangeSoftDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
+	}
+	return p.call(metrics.PersistenceRangeSoftDeleteMessagesFromDLQScope, op)
+}
+
+func (p *queuePersistenceClient) DeleteSoftDeletedMessagesFromDLQ(
+	ctx context.Context,
+	deletedBefore time.Time,
+) error {
+	op := func() error {
+		return p.persistence.DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore)
+	}
+	return p.call(metrics.PersistenceDeleteSoftDeletedMessagesFromDLQScope, op)
+}
+
 func (p *queuePersistenceClient) UpdateDLQAckLevel(
 	ctx context.Context,
 	messageID int64,
@@ -1496,6 +1586,30 @@ func (p *queuePersistenceClient) UpdateDLQAckLevel(
 	return p.call(metrics.PersistenceUpdateDLQAckLevelScope, op)
 }
 
+func (p *queuePersistenceClient) CompareAndSwapDLQAckLevel(
+	ctx context.Context,
+	expectedMessageID int64,
+	messageID int64,
+	clusterName string,
+) error {
+	op := func() error {
+		return p.persistence.CompareAndSwapDLQAckLevel(ctx, expectedMessageID, messageID, clusterName)
+	}
+	return p.call(metrics.PersistenceCompareAndSwapDLQAckLevelScope, op)
+}
+
+func (p *queuePersistenceClient) UpdateDLQAckLevelWithVersion(
+	ctx context.Context,
+	messageID int64,
+	clusterName string,
+	expectedVersion int64,
+) error {
+	op := func() error {
+		return p.persistence.UpdateDLQAckLevelWithVersion(ctx, messageID, clusterName, expectedVersion)
+	}
+	return p.call(metrics.PersistenceUpdateDLQAckLevelWithVersionScope, op)
+}
+
 func (p *queuePersistenceClient) GetDLQAckLevels(
 	ctx context.Context,
 ) (map[string]int64, error) {
@@ -1512,6 +1626,23 @@ func (p *queuePersistenceClient) GetDLQAckLevels(
 	return resp, nil
 }
 
+func (p *queuePersistenceClient) GetDLQAckLevelsWithVersion(
+	ctx context.Context,
+) (map[string]int64, int64, error) {
+	var resp map[string]int64
+	var version int64
+	op := func() error {
+		var err error
+		resp, version, err = p.persistence.GetDLQAckLevelsWithVersion(ctx)
+		return err
+	}
+	err := p.call(metrics.PersistenceGetDLQAckLevelsWithVersionScope, op)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp, version, nil
+}
+
 func (p *queuePersistenceClient) GetDLQSize(
 	ctx context.Context,
 ) (int64, error) {
@@ -1528,6 +1659,98 @@ func (p *queuePersistenceClient) GetDLQSize(
 	return resp, nil
 }
 
+func (p *queuePersistenceClient) GetDLQMessageTypeHistogram(
+	ctx context.Context,
+) (map[int32]int64, error) {
+	var resp map[int32]int64
+	op := func() error {
+		var err error
+		resp, err = p.persistence.GetDLQMessageTypeHistogram(ctx)
+		return err
+	}
+	err := p.call(metrics.PersistenceGetDLQMessageTypeHistogramScope, op)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *queuePersistenceClient) UpdateDLQMessageAttempts(
+	ctx context.Context,
+	messageID int64,
+	attempts int,
+) error {
+	op := func() error {
+		return p.persistence.UpdateDLQMessageAttempts(ctx, messageID, attempts)
+	}
+	return p.call(metrics.PersistenceUpdateDLQMessageAttemptsScope, op)
+}
+
+func (p *queuePersistenceClient) UpdateDLQMessagePayload(
+	ctx context.Context,
+	messageID int64,
+	payload []byte,
+) error {
+	op := func() error {
+		return p.persistence.UpdateDLQMessagePayload(ctx, messageID, payload)
+	}
+	return p.call(metrics.PersistenceUpdateDLQMessagePayloadScope, op)
+}
+
+func (p *queuePersistenceClient) UpdateDLQMergeToken(
+	ctx context.Context,
+	token string,
+	clusterName string,
+) error {
+	op := func() error {
+		return p.persistence.UpdateDLQMergeToken(ctx, token, clusterName)
+	}
+	return p.call(metrics.PersistenceUpdateDLQMergeTokenScope, op)
+}
+
+func (p *queuePersistenceClient) GetDLQMergeTokens(
+	ctx context.Context,
+) (map[string]string, error) {
+	var resp map[string]string
+	op := func() error {
+		var err error
+		resp, err = p.persistence.GetDLQMergeTokens(ctx)
+		return err
+	}
+	err := p.call(metrics.PersistenceGetDLQMergeTokensScope, op)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *queuePersistenceClient) InsertDLQMergeRecord(
+	ctx context.Context,
+	record *DLQMergeRecord,
+) error {
+	op := func() error {
+		return p.persistence.InsertDLQMergeRecord(ctx, record)
+	}
+	return p.call(metrics.PersistenceInsertDLQMergeRecordScope, op)
+}
+
+func (p *queuePersistenceClient) GetDLQMergeHistory(
+	ctx context.Context,
+	limit int,
+) ([]*DLQMergeRecord, error) {
+	var resp []*DLQMergeRecord
+	op := func() error {
+		var err error
+		resp, err = p.persistence.GetDLQMergeHistory(ctx, limit)
+		return err
+	}
+	err := p.call(metrics.PersistenceGetDLQMergeHistoryScope, op)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (p *queuePersistenceClient) Close() {
 	p.persistence.Close()
 }
@@ -22,6 +22,7 @@ package persistence
 
 import (
 	"context"
+	"time"
 
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/quotas"
@@ -1115,13 +1116,26 @@ func (p *queueRateLimitedPersistenceClient) DeleteMessagesBefore(
 
 func (p *queueRateLimitedPersistenceClient) EnqueueMessageToDLQ(
 	ctx context.Context,
+	domainID string,
+	taskType *int32,
 	message []byte,
 ) error {
 	if ok := p.rateLimiter.Allow(); !ok {
 		return ErrPersistenceLimitExceeded
 	}
 
-	return p.persistence.EnqueueMessageToDLQ(ctx, message)
+	return p.persistence.EnqueueMessageToDLQ(ctx, domainID, taskType, message)
+}
+
+func (p *queueRateLimitedPersistenceClient) EnqueueMessagesToDLQ(
+	ctx context.Context,
+	messages []*QueueMessage,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.EnqueueMessagesToDLQ(ctx, messages)
 }
 
 func (p *queueRateLimitedPersistenceClient) ReadMessagesFromDLQ(
@@ -1138,6 +1152,21 @@ func (p *queueRateLimitedPersistenceClient) ReadMessagesFromDLQ(
 	return p.persistence.ReadMessagesFromDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken)
 }
 
+func (p *queueRateLimitedPersistenceClient) ReadMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*QueueMessage, []byte, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, nil, ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.ReadMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken)
+}
+
 func (p *queueRateLimitedPersistenceClient) RangeDeleteMessagesFromDLQ(
 	ctx context.Context,
 	firstMessageID int64,
@@ -1150,6 +1179,58 @@ func (p *queueRateLimitedPersistenceClient) RangeDeleteMessagesFromDLQ(
 	return p.persistence.RangeDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
 }
 
+func (p *queueRateLimitedPersistenceClient) ReadMessagesFromDLQByTimeRange(
+	ctx context.Context,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*QueueMessage, []byte, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, nil, ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.ReadMessagesFromDLQByTimeRange(ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken)
+}
+
+func (p *queueRateLimitedPersistenceClient) RangeDeleteMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID)
+}
+
+func (p *queueRateLimitedPersistenceClient) RangeSoftDeleteMessagesFromDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.RangeSoftDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
+}
+
+func (p *queueRateLimitedPersistenceClient) DeleteSoftDeletedMessagesFromDLQ(
+	ctx context.Context,
+	deletedBefore time.Time,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore)
+}
+
 func (p *queueRateLimitedPersistenceClient) UpdateDLQAckLevel(
 	ctx context.Context,
 	messageID int64,
@@ -1162,6 +1243,19 @@ func (p *queueRateLimitedPersistenceClient) UpdateDLQAckLevel(
 	return p.persistence.UpdateDLQAckLevel(ctx, messageID, clusterName)
 }
 
+func (p *queueRateLimitedPersistenceClient) CompareAndSwapDLQAckLevel(
+	ctx context.Context,
+	expectedMessageID int64,
+	messageID int64,
+	clusterName string,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.CompareAndSwapDLQAckLevel(ctx, expectedMessageID, messageID, clusterName)
+}
+
 func (p *queueRateLimitedPersistenceClient) GetDLQAckLevels(
 	ctx context.Context,
 ) (map[string]int64, error) {
@@ -1172,6 +1266,29 @@ func (p *queueRateLimitedPersistenceClient) GetDLQAckLevels(
 	return p.persistence.GetDLQAckLevels(ctx)
 }
 
+func (p *queueRateLimitedPersistenceClient) UpdateDLQAckLevelWithVersion(
+	ctx context.Context,
+	messageID int64,
+	clusterName string,
+	expectedVersion int64,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.UpdateDLQAckLevelWithVersion(ctx, messageID, clusterName, expectedVersion)
+}
+
+func (p *queueRateLimitedPersistenceClient) GetDLQAckLevelsWithVersion(
+	ctx context.Context,
+) (map[string]int64, int64, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, 0, ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.GetDLQAckLevelsWithVersion(ctx)
+}
+
 func (p *queueRateLimitedPersistenceClient) GetDLQSize(
 	ctx context.Context,
 ) (int64, error) {
@@ -1182,6 +1299,84 @@ func (p *queueRateLimitedPersistenceClient) GetDLQSize(
 	return p.persistence.GetDLQSize(ctx)
 }
 
+func (p *queueRateLimitedPersistenceClient) GetDLQMessageTypeHistogram(
+	ctx context.Context,
+) (map[int32]int64, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.GetDLQMessageTypeHistogram(ctx)
+}
+
+func (p *queueRateLimitedPersistenceClient) UpdateDLQMessageAttempts(
+	ctx context.Context,
+	messageID int64,
+	attempts int,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.UpdateDLQMessageAttempts(ctx, messageID, attempts)
+}
+
+func (p *queueRateLimitedPersistenceClient) UpdateDLQMessagePayload(
+	ctx context.Context,
+	messageID int64,
+	payload []byte,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.UpdateDLQMessagePayload(ctx, messageID, payload)
+}
+
+func (p *queueRateLimitedPersistenceClient) UpdateDLQMergeToken(
+	ctx context.Context,
+	token string,
+	clusterName string,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.UpdateDLQMergeToken(ctx, token, clusterName)
+}
+
+func (p *queueRateLimitedPersistenceClient) GetDLQMergeTokens(
+	ctx context.Context,
+) (map[string]string, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.GetDLQMergeTokens(ctx)
+}
+
+func (p *queueRateLimitedPersistenceClient) InsertDLQMergeRecord(
+	ctx context.Context,
+	record *DLQMergeRecord,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.InsertDLQMergeRecord(ctx, record)
+}
+
+func (p *queueRateLimitedPersistenceClient) GetDLQMergeHistory(
+	ctx context.Context,
+	limit int,
+) ([]*DLQMergeRecord, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	return p.persistence.GetDLQMergeHistory(ctx, limit)
+}
+
 func (p *queueRateLimitedPersistenceClient) DeleteMessageFromDLQ(
 	ctx context.Context,
 	messageID int64,
@@ -24,6 +24,7 @@ package persistence
 
 import (
 	"context"
+	"time"
 )
 
 type (
@@ -75,8 +76,16 @@ func (q *queueManager) GetAckLevels(ctx context.Context) (map[string]int64, erro
 	return q.persistence.GetAckLevels(ctx)
 }
 
-func (q *queueManager) EnqueueMessageToDLQ(ctx context.Context, messagePayload []byte) error {
-	return q.persistence.EnqueueMessageToDLQ(ctx, messagePayload)
+func (q *queueManager) EnqueueMessageToDLQ(ctx context.Context, domainID string, taskType *int32, messagePayload []byte) error {
+	return q.persistence.EnqueueMessageToDLQ(ctx, domainID, taskType, messagePayload)
+}
+
+func (q *queueManager) EnqueueMessagesToDLQ(ctx context.Context, messages []*QueueMessage) error {
+	internalMessages := make([]*InternalQueueMessage, 0, len(messages))
+	for _, message := range messages {
+		internalMessages = append(internalMessages, q.toInternalQueueMessage(message))
+	}
+	return q.persistence.EnqueueMessagesToDLQ(ctx, internalMessages)
 }
 
 func (q *queueManager) ReadMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*QueueMessage, []byte, error) {
@@ -91,6 +100,18 @@ func (q *queueManager) ReadMessagesFromDLQ(ctx context.Context, firstMessageID i
 	return output, data, err
 }
 
+func (q *queueManager) ReadMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*QueueMessage, []byte, error) {
+	resp, data, err := q.persistence.ReadMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID, pageSize, pageToken)
+	if resp == nil {
+		return nil, data, err
+	}
+	var output []*QueueMessage
+	for _, message := range resp {
+		output = append(output, q.fromInternalQueueMessage(message))
+	}
+	return output, data, err
+}
+
 func (q *queueManager) DeleteMessageFromDLQ(ctx context.Context, messageID int64) error {
 	return q.persistence.DeleteMessageFromDLQ(ctx, messageID)
 }
@@ -99,22 +120,102 @@ func (q *queueManager) RangeDeleteMessagesFromDLQ(ctx context.Context, firstMess
 	return q.persistence.RangeDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
 }
 
+func (q *queueManager) ReadMessagesFromDLQByTimeRange(ctx context.Context, startTime time.Time, endTime time.Time, firstMessageID int64, lastMessageID int64, pageSize int, pageToken []byte) ([]*QueueMessage, []byte, error) {
+	resp, data, err := q.persistence.ReadMessagesFromDLQByTimeRange(ctx, startTime, endTime, firstMessageID, lastMessageID, pageSize, pageToken)
+	if resp == nil {
+		return nil, data, err
+	}
+	var output []*QueueMessage
+	for _, message := range resp {
+		output = append(output, q.fromInternalQueueMessage(message))
+	}
+	return output, data, err
+}
+
+func (q *queueManager) RangeDeleteMessagesFromDLQByDomain(ctx context.Context, domainID string, firstMessageID int64, lastMessageID int64) error {
+	return q.persistence.RangeDeleteMessagesFromDLQByDomain(ctx, domainID, firstMessageID, lastMessageID)
+}
+
+func (q *queueManager) RangeSoftDeleteMessagesFromDLQ(ctx context.Context, firstMessageID int64, lastMessageID int64) error {
+	return q.persistence.RangeSoftDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
+}
+
+func (q *queueManager) DeleteSoftDeletedMessagesFromDLQ(ctx context.Context, deletedBefore time.Time) error {
+	return q.persistence.DeleteSoftDeletedMessagesFromDLQ(ctx, deletedBefore)
+}
+
 func (q *queueManager) UpdateDLQAckLevel(ctx context.Context, messageID int64, clusterName string) error {
 	return q.persistence.UpdateDLQAckLevel(ctx, messageID, clusterName)
 }
 
+func (q *queueManager) CompareAndSwapDLQAckLevel(ctx context.Context, expectedMessageID int64, messageID int64, clusterName string) error {
+	return q.persistence.CompareAndSwapDLQAckLevel(ctx, expectedMessageID, messageID, clusterName)
+}
+
+func (q *queueManager) UpdateDLQAckLevelWithVersion(ctx context.Context, messageID int64, clusterName string, expectedVersion int64) error {
+	return q.persistence.UpdateDLQAckLevelWithVersion(ctx, messageID, clusterName, expectedVersion)
+}
+
 func (q *queueManager) GetDLQAckLevels(ctx context.Context) (map[string]int64, error) {
 	return q.persistence.GetDLQAckLevels(ctx)
 }
 
+func (q *queueManager) GetDLQAckLevelsWithVersion(ctx context.Context) (map[string]int64, int64, error) {
+	return q.persistence.GetDLQAckLevelsWithVersion(ctx)
+}
+
 func (q *queueManager) GetDLQSize(ctx context.Context) (int64, error) {
 	return q.persistence.GetDLQSize(ctx)
 }
 
+func (q *queueManager) GetDLQMessageTypeHistogram(ctx context.Context) (map[int32]int64, error) {
+	return q.persistence.GetDLQMessageTypeHistogram(ctx)
+}
+
+func (q *queueManager) UpdateDLQMessageAttempts(ctx context.Context, messageID int64, attempts int) error {
+	return q.persistence.UpdateDLQMessageAttempts(ctx, messageID, attempts)
+}
+
+func (q *queueManager) UpdateDLQMessagePayload(ctx context.Context, messageID int64, payload []byte) error {
+	return q.persistence.UpdateDLQMessagePayload(ctx, messageID, payload)
+}
+
+func (q *queueManager) UpdateDLQMergeToken(ctx context.Context, token string, clusterName string) error {
+	return q.persistence.UpdateDLQMergeToken(ctx, token, clusterName)
+}
+
+func (q *queueManager) GetDLQMergeTokens(ctx context.Context) (map[string]string, error) {
+	return q.persistence.GetDLQMergeTokens(ctx)
+}
+
+func (q *queueManager) InsertDLQMergeRecord(ctx context.Context, record *DLQMergeRecord) error {
+	return q.persistence.InsertDLQMergeRecord(ctx, record)
+}
+
+func (q *queueManager) GetDLQMergeHistory(ctx context.Context, limit int) ([]*DLQMergeRecord, error) {
+	return q.persistence.GetDLQMergeHistory(ctx, limit)
+}
+
 func (q *queueManager) fromInternalQueueMessage(message *InternalQueueMessage) *QueueMessage {
 	return &QueueMessage{
-		ID:        message.ID,
-		QueueType: message.QueueType,
-		Payload:   message.Payload,
+		ID:         message.ID,
+		QueueType:  message.QueueType,
+		Payload:    message.Payload,
+		Attempts:   message.Attempts,
+		EnqueuedAt: message.EnqueuedAt,
+		DomainID:   message.DomainID,
+		TaskType:   message.TaskType,
+	}
+}
+
+func (q *queueManager) toInternalQueueMessage(message *QueueMessage) *InternalQueueMessage {
+	return &InternalQueueMessage{
+		ID:         message.ID,
+		QueueType:  message.QueueType,
+		Payload:    message.Payload,
+		Attempts:   message.Attempts,
+		EnqueuedAt: message.EnqueuedAt,
+		DomainID:   message.DomainID,
+		TaskType:   message.TaskType,
 	}
 }
@@ -24,6 +24,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/persistence"
@@ -31,6 +32,9 @@ import (
 	"github.com/uber/cadence/common/types"
 )
 
+// emptyQueueMetadataVersion is the version of the ack levels of a queue whose metadata is not inserted yet
+const emptyQueueMetadataVersion = -1
+
 type (
 	sqlQueueStore struct {
 		queueType persistence.QueueType
@@ -68,7 +72,7 @@ func (q *sqlQueueStore) EnqueueMessage(
 			}
 		}
 
-		_, err = tx.InsertIntoQueue(ctx, newQueueRow(q.queueType, lastMessageID+1, messagePayload))
+		_, err = tx.InsertIntoQueue(ctx, newQueueRow(q.queueType, lastMessageID+1, "", nil, messagePayload))
 		return err
 	})
 }
@@ -94,10 +98,13 @@ func (q *sqlQueueStore) ReadMessages(
 func newQueueRow(
 	queueType persistence.QueueType,
 	messageID int64,
+	domainID string,
+	taskType *int32,
 	payload []byte,
 ) *sqlplugin.QueueRow {
 
-	return &sqlplugin.QueueRow{QueueType: queueType, MessageID: messageID, MessagePayload: payload}
+	enqueuedAt := time.Now()
+	return &sqlplugin.QueueRow{QueueType: queueType, MessageID: messageID, MessagePayload: payload, EnqueuedAt: &enqueuedAt, DomainID: domainID, TaskType: taskType}
 }
 
 func (q *sqlQueueStore) DeleteMessagesBefore(
@@ -149,6 +156,8 @@ func (q *sqlQueueStore) GetAckLevels(
 
 func (q *sqlQueueStore) EnqueueMessageToDLQ(
 	ctx context.Context,
+	domainID string,
+	taskType *int32,
 	messagePayload []byte,
 ) error {
 	return q.txExecute(ctx, sqlplugin.DbDefaultShard, "EnqueueMessageToDLQ", func(tx sqlplugin.Tx) error {
@@ -161,7 +170,34 @@ func (q *sqlQueueStore) EnqueueMessageToDLQ(
 				return err
 			}
 		}
-		_, err = tx.InsertIntoQueue(ctx, newQueueRow(q.getDLQTypeFromQueueType(), lastMessageID+1, messagePayload))
+		_, err = tx.InsertIntoQueue(ctx, newQueueRow(q.getDLQTypeFromQueueType(), lastMessageID+1, domainID, taskType, messagePayload))
+		return err
+	})
+}
+
+func (q *sqlQueueStore) EnqueueMessagesToDLQ(
+	ctx context.Context,
+	messages []*persistence.InternalQueueMessage,
+) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return q.txExecute(ctx, sqlplugin.DbDefaultShard, "EnqueueMessagesToDLQ", func(tx sqlplugin.Tx) error {
+		lastMessageID, err := tx.GetLastEnqueuedMessageIDForUpdate(ctx, q.getDLQTypeFromQueueType())
+		if err != nil {
+			if err == sql.ErrNoRows {
+				lastMessageID = -1
+			} else {
+				return err
+			}
+		}
+
+		rows := make([]sqlplugin.QueueRow, 0, len(messages))
+		for i, message := range messages {
+			rows = append(rows, *newQueueRow(q.getDLQTypeFromQueueType(), lastMessageID+1+int64(i), message.DomainID, message.TaskType, message.Payload))
+		}
+		_, err = tx.InsertIntoQueueBatch(ctx, rows)
 		return err
 	})
 }
@@ -174,6 +210,52 @@ func (q *sqlQueueStore) ReadMessagesFromDLQ(
 	pageToken []byte,
 ) ([]*persistence.InternalQueueMessage, []byte, error) {
 
+	return q.readMessagesFromDLQ("ReadMessagesFromDLQ", firstMessageID, lastMessageID, pageSize, pageToken,
+		func(firstMessageID int64) ([]sqlplugin.QueueRow, error) {
+			return q.db.GetMessagesBetween(ctx, q.getDLQTypeFromQueueType(), firstMessageID, lastMessageID, pageSize)
+		})
+}
+
+func (q *sqlQueueStore) ReadMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*persistence.InternalQueueMessage, []byte, error) {
+
+	return q.readMessagesFromDLQ("ReadMessagesFromDLQByDomain", firstMessageID, lastMessageID, pageSize, pageToken,
+		func(firstMessageID int64) ([]sqlplugin.QueueRow, error) {
+			return q.db.GetMessagesBetweenByDomain(ctx, q.getDLQTypeFromQueueType(), domainID, firstMessageID, lastMessageID, pageSize)
+		})
+}
+
+func (q *sqlQueueStore) ReadMessagesFromDLQByTimeRange(
+	ctx context.Context,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*persistence.InternalQueueMessage, []byte, error) {
+
+	return q.readMessagesFromDLQ("ReadMessagesFromDLQByTimeRange", firstMessageID, lastMessageID, pageSize, pageToken,
+		func(firstMessageID int64) ([]sqlplugin.QueueRow, error) {
+			return q.db.GetMessagesBetweenByEnqueueTime(ctx, q.getDLQTypeFromQueueType(), startTime, endTime, firstMessageID, lastMessageID, pageSize)
+		})
+}
+
+func (q *sqlQueueStore) readMessagesFromDLQ(
+	operation string,
+	firstMessageID int64,
+	lastMessageID int64,
+	pageSize int,
+	pageToken []byte,
+	getMessages func(firstMessageID int64) ([]sqlplugin.QueueRow, error),
+) ([]*persistence.InternalQueueMessage, []byte, error) {
+
 	if len(pageToken) != 0 {
 		lastReadMessageID, err := deserializePageToken(pageToken)
 		if err != nil {
@@ -183,14 +265,19 @@ func (q *sqlQueueStore) ReadMessagesFromDLQ(
 		firstMessageID = lastReadMessageID
 	}
 
-	rows, err := q.db.GetMessagesBetween(ctx, q.getDLQTypeFromQueueType(), firstMessageID, lastMessageID, pageSize)
+	rows, err := getMessages(firstMessageID)
 	if err != nil {
-		return nil, nil, convertCommonErrors(q.db, "ReadMessagesFromDLQ", "", err)
+		return nil, nil, convertCommonErrors(q.db, operation, "", err)
 	}
 
 	var messages []*persistence.InternalQueueMessage
 	for _, row := range rows {
-		messages = append(messages, &persistence.InternalQueueMessage{ID: row.MessageID, Payload: row.MessagePayload})
+		// a NULL enqueue time is left as the zero time, which readers treat as unknown
+		var enqueuedAt time.Time
+		if row.EnqueuedAt != nil {
+			enqueuedAt = *row.EnqueuedAt
+		}
+		messages = append(messages, &persistence.InternalQueueMessage{ID: row.MessageID, Payload: row.MessagePayload, Attempts: row.Attempts, EnqueuedAt: enqueuedAt, DomainID: row.DomainID})
 	}
 
 	var newPagingToken []byte
@@ -224,6 +311,66 @@ func (q *sqlQueueStore) RangeDeleteMessagesFromDLQ(
 	return nil
 }
 
+func (q *sqlQueueStore) RangeDeleteMessagesFromDLQByDomain(
+	ctx context.Context,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	_, err := q.db.RangeDeleteMessagesByDomain(ctx, q.getDLQTypeFromQueueType(), domainID, firstMessageID, lastMessageID)
+	if err != nil {
+		return convertCommonErrors(q.db, "RangeDeleteMessagesFromDLQByDomain", "", err)
+	}
+	return nil
+}
+
+func (q *sqlQueueStore) RangeSoftDeleteMessagesFromDLQ(
+	ctx context.Context,
+	firstMessageID int64,
+	lastMessageID int64,
+) error {
+	_, err := q.db.SoftDeleteMessages(ctx, q.getDLQTypeFromQueueType(), firstMessageID, lastMessageID, time.Now())
+	if err != nil {
+		return convertCommonErrors(q.db, "RangeSoftDeleteMessagesFromDLQ", "", err)
+	}
+	return nil
+}
+
+func (q *sqlQueueStore) DeleteSoftDeletedMessagesFromDLQ(
+	ctx context.Context,
+	deletedBefore time.Time,
+) error {
+	_, err := q.db.DeleteSoftDeletedMessages(ctx, q.getDLQTypeFromQueueType(), deletedBefore)
+	if err != nil {
+		return convertCommonErrors(q.db, "DeleteSoftDeletedMessagesFromDLQ", "", err)
+	}
+	return nil
+}
+
+func (q *sqlQueueStore) UpdateDLQMessageAttempts(
+	ctx context.Context,
+	messageID int64,
+	attempts int,
+) error {
+	_, err := q.db.UpdateMessageAttempts(ctx, q.getDLQTypeFromQueueType(), messageID, attempts)
+	if err != nil {
+		return convertCommonErrors(q.db, "UpdateDLQMessageAttempts", "", err)
+	}
+	return nil
+}
+
+func (q *sqlQueueStore) UpdateDLQMessagePayload(
+	ctx context.Context,
+	messageID int64,
+	payload []byte,
+) error {
+	_, err := q.db.UpdateMessagePayload(ctx, q.getDLQTypeFromQueueType(), messageID, payload)
+	if err != nil {
+		return convertCommonErrors(q.db, "UpdateDLQMessagePayload", "", err)
+	}
+	return nil
+}
+
 func (q *sqlQueueStore) UpdateDLQAckLevel(
 	ctx context.Context,
 	messageID int64,
@@ -249,6 +396,90 @@ func (q *sqlQueueStore) UpdateDLQAckLevel(
 	})
 }
 
+func (q *sqlQueueStore) CompareAndSwapDLQAckLevel(
+	ctx context.Context,
+	expectedMessageID int64,
+	messageID int64,
+	clusterName string,
+) error {
+	return q.txExecute(ctx, sqlplugin.DbDefaultShard, "CompareAndSwapDLQAckLevel", func(tx sqlplugin.Tx) error {
+		clusterAckLevels, err := tx.GetAckLevels(ctx, q.getDLQTypeFromQueueType(), true)
+		if err != nil {
+			return err
+		}
+
+		ackLevel, ok := clusterAckLevels[clusterName]
+		if !ok {
+			ackLevel = -1
+		}
+		if ackLevel != expectedMessageID {
+			return &persistence.ConditionFailedError{
+				Msg: fmt.Sprintf("dlq ack level of %v is %v, expected %v", clusterName, ackLevel, expectedMessageID),
+			}
+		}
+
+		if clusterAckLevels == nil {
+			return tx.InsertAckLevel(ctx, q.getDLQTypeFromQueueType(), messageID, clusterName)
+		}
+
+		clusterAckLevels[clusterName] = messageID
+		return tx.UpdateAckLevels(ctx, q.getDLQTypeFromQueueType(), clusterAckLevels)
+	})
+}
+
+// UpdateDLQAckLevelWithVersion sets the DLQ ack level if the DLQ metadata is still at the expected version,
+// the update is conditioned on the version column so it fails if the metadata was updated concurrently
+func (q *sqlQueueStore) UpdateDLQAckLevelWithVersion(
+	ctx context.Context,
+	messageID int64,
+	clusterName string,
+	expectedVersion int64,
+) error {
+	return q.txExecute(ctx, sqlplugin.DbDefaultShard, "UpdateDLQAckLevelWithVersion", func(tx sqlplugin.Tx) error {
+		clusterAckLevels, version, err := tx.GetAckLevelsWithVersion(ctx, q.getDLQTypeFromQueueType())
+		if err != nil {
+			if !q.db.IsNotFoundError(err) {
+				return err
+			}
+			version = emptyQueueMetadataVersion
+		}
+		if version != expectedVersion {
+			return &persistence.ConditionFailedError{
+				Msg: fmt.Sprintf("dlq ack levels are at version %v, expected %v", version, expectedVersion),
+			}
+		}
+
+		if version == emptyQueueMetadataVersion {
+			err := tx.InsertAckLevel(ctx, q.getDLQTypeFromQueueType(), messageID, clusterName)
+			if err != nil && q.db.IsDupEntryError(err) {
+				return &persistence.ConditionFailedError{
+					Msg: "UpdateDLQAckLevelWithVersion operation encounter concurrent write.",
+				}
+			}
+			return err
+		}
+
+		if clusterAckLevels == nil {
+			clusterAckLevels = make(map[string]int64)
+		}
+		clusterAckLevels[clusterName] = messageID
+		result, err := tx.UpdateAckLevelsWithVersion(ctx, q.getDLQTypeFromQueueType(), clusterAckLevels, expectedVersion)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return &persistence.ConditionFailedError{
+				Msg: "UpdateDLQAckLevelWithVersion operation encounter concurrent write.",
+			}
+		}
+		return nil
+	})
+}
+
 func (q *sqlQueueStore) GetDLQAckLevels(
 	ctx context.Context,
 ) (map[string]int64, error) {
@@ -259,6 +490,110 @@ func (q *sqlQueueStore) GetDLQAckLevels(
 	return result, nil
 }
 
+// GetDLQAckLevelsWithVersion returns the DLQ ack levels with the version of the DLQ metadata,
+// which is emptyQueueMetadataVersion until the metadata is inserted
+func (q *sqlQueueStore) GetDLQAckLevelsWithVersion(
+	ctx context.Context,
+) (map[string]int64, int64, error) {
+	result, version, err := q.db.GetAckLevelsWithVersion(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		if q.db.IsNotFoundError(err) {
+			return nil, emptyQueueMetadataVersion, nil
+		}
+		return nil, 0, convertCommonErrors(q.db, "GetDLQAckLevelsWithVersion", "", err)
+	}
+	return result, version, nil
+}
+
+func (q *sqlQueueStore) UpdateDLQMergeToken(
+	ctx context.Context,
+	token string,
+	clusterName string,
+) error {
+	return q.txExecute(ctx, sqlplugin.DbDefaultShard, "UpdateDLQMergeToken", func(tx sqlplugin.Tx) error {
+		clusterAckLevels, err := tx.GetAckLevels(ctx, q.getDLQTypeFromQueueType(), true)
+		if err != nil {
+			return err
+		}
+
+		// The merge tokens are stored on the metadata row, create it if it does not exist yet
+		if clusterAckLevels == nil {
+			if err := tx.InsertAckLevel(ctx, q.getDLQTypeFromQueueType(), -1, clusterName); err != nil {
+				return err
+			}
+		}
+
+		clusterMergeTokens, err := tx.GetMergeTokens(ctx, q.getDLQTypeFromQueueType())
+		if err != nil {
+			return err
+		}
+		if clusterMergeTokens == nil {
+			clusterMergeTokens = make(map[string]string)
+		}
+
+		clusterMergeTokens[clusterName] = token
+		return tx.UpdateMergeTokens(ctx, q.getDLQTypeFromQueueType(), clusterMergeTokens)
+	})
+}
+
+func (q *sqlQueueStore) GetDLQMergeTokens(
+	ctx context.Context,
+) (map[string]string, error) {
+	result, err := q.db.GetMergeTokens(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		return nil, convertCommonErrors(q.db, "GetDLQMergeTokens", "", err)
+	}
+	return result, nil
+}
+
+func (q *sqlQueueStore) InsertDLQMergeRecord(
+	ctx context.Context,
+	record *persistence.DLQMergeRecord,
+) error {
+
+	_, err := q.db.InsertIntoDLQMergeHistory(ctx, &sqlplugin.DLQMergeHistoryRow{
+		QueueType:      q.getDLQTypeFromQueueType(),
+		MergedAt:       record.MergedAt,
+		StartMessageID: record.StartMessageID,
+		EndMessageID:   record.EndMessageID,
+		MergedCount:    record.MergedCount,
+		FailedCount:    record.FailedCount,
+		Duration:       record.Duration,
+		TriggeredBy:    record.TriggeredBy,
+		TraceID:        record.TraceID,
+	})
+	if err != nil {
+		return convertCommonErrors(q.db, "InsertDLQMergeRecord", "", err)
+	}
+	return nil
+}
+
+func (q *sqlQueueStore) GetDLQMergeHistory(
+	ctx context.Context,
+	limit int,
+) ([]*persistence.DLQMergeRecord, error) {
+
+	rows, err := q.db.SelectFromDLQMergeHistory(ctx, q.getDLQTypeFromQueueType(), limit)
+	if err != nil {
+		return nil, convertCommonErrors(q.db, "GetDLQMergeHistory", "", err)
+	}
+
+	records := make([]*persistence.DLQMergeRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, &persistence.DLQMergeRecord{
+			MergedAt:       row.MergedAt,
+			StartMessageID: row.StartMessageID,
+			EndMessageID:   row.EndMessageID,
+			MergedCount:    row.MergedCount,
+			FailedCount:    row.FailedCount,
+			Duration:       row.Duration,
+			TriggeredBy:    row.TriggeredBy,
+			TraceID:        row.TraceID,
+		})
+	}
+	return records, nil
+}
+
 func (q *sqlQueueStore) GetDLQSize(
 	ctx context.Context,
 ) (int64, error) {
@@ -269,6 +604,16 @@ func (q *sqlQueueStore) GetDLQSize(
 	return result, nil
 }
 
+func (q *sqlQueueStore) GetDLQMessageTypeHistogram(
+	ctx context.Context,
+) (map[int32]int64, error) {
+	result, err := q.db.GetQueueMessageTypeHistogram(ctx, q.getDLQTypeFromQueueType())
+	if err != nil {
+		return nil, convertCommonErrors(q.db, "GetDLQMessageTypeHistogram", "", err)
+	}
+	return result, nil
+}
+
 func (q *sqlQueueStore) getDLQTypeFromQueueType() persistence.QueueType {
 	return -q.queueType
 }
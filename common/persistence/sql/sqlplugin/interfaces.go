@@ -559,6 +559,10 @@ type (
 		QueueType      persistence.QueueType
 		MessageID      int64
 		MessagePayload []byte
+		Attempts       int
+		EnqueuedAt     *time.Time // nil for messages enqueued before the column was added
+		DomainID       string
+		TaskType       *int32
 	}
 
 	// QueueMetadataRow represents a row in queue_metadata table
@@ -567,6 +571,19 @@ type (
 		Data      []byte
 	}
 
+	// DLQMergeHistoryRow represents a row in dlq_merge_history table
+	DLQMergeHistoryRow struct {
+		QueueType      persistence.QueueType
+		MergedAt       time.Time
+		StartMessageID int64
+		EndMessageID   int64
+		MergedCount    int64
+		FailedCount    int64
+		Duration       time.Duration
+		TriggeredBy    string
+		TraceID        string
+	}
+
 	// tableCRUD defines the API for interacting with the database tables
 	tableCRUD interface {
 		InsertIntoDomain(ctx context.Context, rows *DomainRow) (sql.Result, error)
@@ -787,16 +804,37 @@ type (
 		DeleteFromVisibility(ctx context.Context, filter *VisibilityFilter) (sql.Result, error)
 
 		InsertIntoQueue(ctx context.Context, row *QueueRow) (sql.Result, error)
+		InsertIntoQueueBatch(ctx context.Context, rows []QueueRow) (sql.Result, error)
 		GetLastEnqueuedMessageIDForUpdate(ctx context.Context, queueType persistence.QueueType) (int64, error)
 		GetMessagesFromQueue(ctx context.Context, queueType persistence.QueueType, lastMessageID int64, maxRows int) ([]QueueRow, error)
 		GetMessagesBetween(ctx context.Context, queueType persistence.QueueType, firstMessageID int64, lastMessageID int64, maxRows int) ([]QueueRow, error)
+		GetMessagesBetweenByDomain(ctx context.Context, queueType persistence.QueueType, domainID string, firstMessageID int64, lastMessageID int64, maxRows int) ([]QueueRow, error)
+		GetMessagesBetweenByEnqueueTime(ctx context.Context, queueType persistence.QueueType, startTime time.Time, endTime time.Time, firstMessageID int64, lastMessageID int64, maxRows int) ([]QueueRow, error)
 		DeleteMessagesBefore(ctx context.Context, queueType persistence.QueueType, messageID int64) (sql.Result, error)
 		RangeDeleteMessages(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, inclusiveEndMessageID int64) (sql.Result, error)
+		RangeDeleteMessagesByDomain(ctx context.Context, queueType persistence.QueueType, domainID string, exclusiveBeginMessageID int64, inclusiveEndMessageID int64) (sql.Result, error)
 		DeleteMessage(ctx context.Context, queueType persistence.QueueType, messageID int64) (sql.Result, error)
+		SoftDeleteMessages(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, inclusiveEndMessageID int64, deletedAt time.Time) (sql.Result, error)
+		DeleteSoftDeletedMessages(ctx context.Context, queueType persistence.QueueType, deletedBefore time.Time) (sql.Result, error)
+		UpdateMessageAttempts(ctx context.Context, queueType persistence.QueueType, messageID int64, attempts int) (sql.Result, error)
+		UpdateMessagePayload(ctx context.Context, queueType persistence.QueueType, messageID int64, payload []byte) (sql.Result, error)
 		InsertAckLevel(ctx context.Context, queueType persistence.QueueType, messageID int64, clusterName string) error
 		UpdateAckLevels(ctx context.Context, queueType persistence.QueueType, clusterAckLevels map[string]int64) error
 		GetAckLevels(ctx context.Context, queueType persistence.QueueType, forUpdate bool) (map[string]int64, error)
+		// UpdateAckLevelsWithVersion updates the ack levels only if the queue metadata is at the expected version,
+		// no row is affected otherwise
+		UpdateAckLevelsWithVersion(ctx context.Context, queueType persistence.QueueType, clusterAckLevels map[string]int64, expectedVersion int64) (sql.Result, error)
+		// GetAckLevelsWithVersion returns sql.ErrNoRows if the queue has no metadata
+		GetAckLevelsWithVersion(ctx context.Context, queueType persistence.QueueType) (map[string]int64, int64, error)
+		UpdateMergeTokens(ctx context.Context, queueType persistence.QueueType, clusterMergeTokens map[string]string) error
+		GetMergeTokens(ctx context.Context, queueType persistence.QueueType) (map[string]string, error)
 		GetQueueSize(ctx context.Context, queueType persistence.QueueType) (int64, error)
+		// GetQueueMessageTypeHistogram returns the number of messages of each task type,
+		// messages enqueued without a task type are not counted
+		GetQueueMessageTypeHistogram(ctx context.Context, queueType persistence.QueueType) (map[int32]int64, error)
+		InsertIntoDLQMergeHistory(ctx context.Context, row *DLQMergeHistoryRow) (sql.Result, error)
+		// SelectFromDLQMergeHistory returns the most recent rows, newest first
+		SelectFromDLQMergeHistory(ctx context.Context, queueType persistence.QueueType, limit int) ([]DLQMergeHistoryRow, error)
 
 		// The follow provide information about the underlying sql crud implementation
 		SupportsTTL() bool
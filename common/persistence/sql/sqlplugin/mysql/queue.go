@@ -24,24 +24,39 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/sql/sqlplugin"
 )
 
 const (
-	templateEnqueueMessageQuery            = `INSERT INTO queue (queue_type, message_id, message_payload) VALUES(:queue_type, :message_id, :message_payload)`
-	templateGetLastMessageIDQuery          = `SELECT message_id FROM queue WHERE queue_type=? ORDER BY message_id DESC LIMIT 1 FOR UPDATE`
-	templateGetMessagesQuery               = `SELECT message_id, message_payload FROM queue WHERE queue_type = ? and message_id > ? ORDER BY message_id ASC LIMIT ?`
-	templateGetMessagesBetweenQuery        = `SELECT message_id, message_payload FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ? ORDER BY message_id ASC LIMIT ?`
-	templateDeleteMessagesBeforeQuery      = `DELETE FROM queue WHERE queue_type = ? and message_id < ?`
-	templateRangeDeleteMessagesQuery       = `DELETE FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ?`
-	templateDeleteMessageQuery             = `DELETE FROM queue WHERE queue_type = ? and message_id = ?`
-	templateGetQueueMetadataQuery          = `SELECT data from queue_metadata WHERE queue_type = ?`
-	templateGetQueueMetadataForUpdateQuery = templateGetQueueMetadataQuery + ` FOR UPDATE`
-	templateInsertQueueMetadataQuery       = `INSERT INTO queue_metadata (queue_type, data) VALUES(:queue_type, :data)`
-	templateUpdateQueueMetadataQuery       = `UPDATE queue_metadata SET data = ? WHERE queue_type = ?`
-	templateGetQueueSizeQuery              = `SELECT COUNT(1) AS count FROM queue WHERE queue_type=?`
+	templateEnqueueMessageQuery               = `INSERT INTO queue (queue_type, message_id, message_payload, enqueued_at, domain_id, task_type) VALUES(:queue_type, :message_id, :message_payload, :enqueued_at, :domain_id, :task_type)`
+	templateGetLastMessageIDQuery             = `SELECT message_id FROM queue WHERE queue_type=? ORDER BY message_id DESC LIMIT 1 FOR UPDATE`
+	templateGetMessagesQuery                  = `SELECT message_id, message_payload FROM queue WHERE queue_type = ? and message_id > ? ORDER BY message_id ASC LIMIT ?`
+	templateGetMessagesBetweenQuery           = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ? and deleted_at IS NULL ORDER BY message_id ASC LIMIT ?`
+	templateGetMessagesByDomainQuery          = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id FROM queue WHERE queue_type = ? and domain_id = ? and message_id > ? and message_id <= ? and deleted_at IS NULL ORDER BY message_id ASC LIMIT ?`
+	templateGetMessagesByEnqueueTimeQuery     = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id FROM queue WHERE queue_type = ? and enqueued_at >= ? and enqueued_at < ? and message_id > ? and message_id <= ? and deleted_at IS NULL ORDER BY message_id ASC LIMIT ?`
+	templateDeleteMessagesBeforeQuery         = `DELETE FROM queue WHERE queue_type = ? and message_id < ?`
+	templateRangeDeleteMessagesQuery          = `DELETE FROM queue WHERE queue_type = ? and message_id > ? and message_id <= ?`
+	templateRangeDeleteMessagesByDomainQuery  = `DELETE FROM queue WHERE queue_type = ? and domain_id = ? and message_id > ? and message_id <= ?`
+	templateSoftDeleteMessagesQuery           = `UPDATE queue SET deleted_at = ? WHERE queue_type = ? and message_id > ? and message_id <= ? and deleted_at IS NULL`
+	templateDeleteSoftDeletedMessagesQuery    = `DELETE FROM queue WHERE queue_type = ? and deleted_at < ?`
+	templateDeleteMessageQuery                = `DELETE FROM queue WHERE queue_type = ? and message_id = ?`
+	templateUpdateMessageAttemptsQuery        = `UPDATE queue SET attempts = ? WHERE queue_type = ? and message_id = ?`
+	templateUpdateMessagePayloadQuery         = `UPDATE queue SET message_payload = ? WHERE queue_type = ? and message_id = ?`
+	templateGetQueueMetadataQuery             = `SELECT data from queue_metadata WHERE queue_type = ?`
+	templateGetQueueMetadataForUpdateQuery    = templateGetQueueMetadataQuery + ` FOR UPDATE`
+	templateInsertQueueMetadataQuery          = `INSERT INTO queue_metadata (queue_type, data) VALUES(:queue_type, :data)`
+	templateUpdateQueueMetadataQuery          = `UPDATE queue_metadata SET data = ?, version = version + 1 WHERE queue_type = ?`
+	templateGetQueueMetadataWithVersionQuery  = `SELECT data, version from queue_metadata WHERE queue_type = ?`
+	templateUpdateQueueMetadataWithVersion    = `UPDATE queue_metadata SET data = ?, version = ? WHERE queue_type = ? AND version = ?`
+	templateGetQueueMergeTokensQuery          = `SELECT merge_tokens from queue_metadata WHERE queue_type = ?`
+	templateUpdateQueueMergeTokensQuery       = `UPDATE queue_metadata SET merge_tokens = ? WHERE queue_type = ?`
+	templateGetQueueSizeQuery                 = `SELECT COUNT(1) AS count FROM queue WHERE queue_type=? and deleted_at IS NULL`
+	templateGetQueueMessageTypeHistogramQuery = `SELECT task_type, COUNT(1) AS count FROM queue WHERE queue_type=? and task_type IS NOT NULL and deleted_at IS NULL GROUP BY task_type`
+	templateInsertDLQMergeHistoryQuery        = `INSERT INTO dlq_merge_history (queue_type, merged_at, start_message_id, end_message_id, merged_count, failed_count, duration, triggered_by, trace_id) VALUES(:queue_type, :merged_at, :start_message_id, :end_message_id, :merged_count, :failed_count, :duration, :triggered_by, :trace_id)`
+	templateGetDLQMergeHistoryQuery           = `SELECT merged_at, start_message_id, end_message_id, merged_count, failed_count, duration, triggered_by, trace_id FROM dlq_merge_history WHERE queue_type = ? ORDER BY merged_at DESC, start_message_id DESC LIMIT ?`
 )
 
 // InsertIntoQueue inserts a new row into queue table
@@ -50,9 +65,31 @@ func (mdb *db) InsertIntoQueue(
 	row *sqlplugin.QueueRow,
 ) (sql.Result, error) {
 
+	if row.EnqueuedAt != nil {
+		enqueuedAt := mdb.converter.ToMySQLDateTime(*row.EnqueuedAt)
+		row.EnqueuedAt = &enqueuedAt
+	}
 	return mdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateEnqueueMessageQuery, row)
 }
 
+// InsertIntoQueueBatch inserts new rows into queue table with a single multi-row insert
+func (mdb *db) InsertIntoQueueBatch(
+	ctx context.Context,
+	rows []sqlplugin.QueueRow,
+) (sql.Result, error) {
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	for i := range rows {
+		if rows[i].EnqueuedAt != nil {
+			enqueuedAt := mdb.converter.ToMySQLDateTime(*rows[i].EnqueuedAt)
+			rows[i].EnqueuedAt = &enqueuedAt
+		}
+	}
+	return mdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateEnqueueMessageQuery, rows)
+}
+
 // GetLastEnqueuedMessageIDForUpdate returns the last enqueued message ID
 func (mdb *db) GetLastEnqueuedMessageIDForUpdate(
 	ctx context.Context,
@@ -88,6 +125,56 @@ func (mdb *db) GetMessagesBetween(
 
 	var rows []sqlplugin.QueueRow
 	err := mdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateGetMessagesBetweenQuery, queueType, firstMessageID, lastMessageID, maxRows)
+	for i := range rows {
+		if rows[i].EnqueuedAt != nil {
+			enqueuedAt := mdb.converter.FromMySQLDateTime(*rows[i].EnqueuedAt)
+			rows[i].EnqueuedAt = &enqueuedAt
+		}
+	}
+	return rows, err
+}
+
+// GetMessagesBetweenByDomain retrieves the messages of a domain from the queue
+func (mdb *db) GetMessagesBetweenByDomain(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	domainID string,
+	firstMessageID int64,
+	lastMessageID int64,
+	maxRows int,
+) ([]sqlplugin.QueueRow, error) {
+
+	var rows []sqlplugin.QueueRow
+	err := mdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateGetMessagesByDomainQuery, queueType, domainID, firstMessageID, lastMessageID, maxRows)
+	for i := range rows {
+		if rows[i].EnqueuedAt != nil {
+			enqueuedAt := mdb.converter.FromMySQLDateTime(*rows[i].EnqueuedAt)
+			rows[i].EnqueuedAt = &enqueuedAt
+		}
+	}
+	return rows, err
+}
+
+// GetMessagesBetweenByEnqueueTime retrieves the messages enqueued between startTime, inclusive, and endTime, exclusive, from the queue
+func (mdb *db) GetMessagesBetweenByEnqueueTime(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	startTime time.Time,
+	endTime time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	maxRows int,
+) ([]sqlplugin.QueueRow, error) {
+
+	var rows []sqlplugin.QueueRow
+	err := mdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateGetMessagesByEnqueueTimeQuery, queueType,
+		mdb.converter.ToMySQLDateTime(startTime), mdb.converter.ToMySQLDateTime(endTime), firstMessageID, lastMessageID, maxRows)
+	for i := range rows {
+		if rows[i].EnqueuedAt != nil {
+			enqueuedAt := mdb.converter.FromMySQLDateTime(*rows[i].EnqueuedAt)
+			rows[i].EnqueuedAt = &enqueuedAt
+		}
+	}
 	return rows, err
 }
 
@@ -112,6 +199,40 @@ func (mdb *db) RangeDeleteMessages(
 	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateRangeDeleteMessagesQuery, queueType, exclusiveBeginMessageID, inclusiveEndMessageID)
 }
 
+// RangeDeleteMessagesByDomain deletes the messages of a domain in a range from the queue
+func (mdb *db) RangeDeleteMessagesByDomain(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	domainID string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+) (sql.Result, error) {
+
+	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateRangeDeleteMessagesByDomainQuery, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID)
+}
+
+// SoftDeleteMessages soft deletes the messages in a range from the queue by setting their deleted_at time
+func (mdb *db) SoftDeleteMessages(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+	deletedAt time.Time,
+) (sql.Result, error) {
+
+	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateSoftDeleteMessagesQuery, mdb.converter.ToMySQLDateTime(deletedAt), queueType, exclusiveBeginMessageID, inclusiveEndMessageID)
+}
+
+// DeleteSoftDeletedMessages deletes the messages soft deleted before deletedBefore from the queue
+func (mdb *db) DeleteSoftDeletedMessages(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	deletedBefore time.Time,
+) (sql.Result, error) {
+
+	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateDeleteSoftDeletedMessagesQuery, queueType, mdb.converter.ToMySQLDateTime(deletedBefore))
+}
+
 // DeleteMessage deletes message with a messageID from the queue
 func (mdb *db) DeleteMessage(
 	ctx context.Context,
@@ -122,6 +243,28 @@ func (mdb *db) DeleteMessage(
 	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateDeleteMessageQuery, queueType, messageID)
 }
 
+// UpdateMessageAttempts updates the number of attempts made to process a message
+func (mdb *db) UpdateMessageAttempts(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageID int64,
+	attempts int,
+) (sql.Result, error) {
+
+	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateUpdateMessageAttemptsQuery, attempts, queueType, messageID)
+}
+
+// UpdateMessagePayload replaces the payload of a message
+func (mdb *db) UpdateMessagePayload(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	messageID int64,
+	payload []byte,
+) (sql.Result, error) {
+
+	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateUpdateMessagePayloadQuery, payload, queueType, messageID)
+}
+
 // InsertAckLevel inserts ack level
 func (mdb *db) InsertAckLevel(
 	ctx context.Context,
@@ -187,6 +330,88 @@ func (mdb *db) GetAckLevels(
 	return clusterAckLevels, nil
 }
 
+// UpdateAckLevelsWithVersion updates cluster ack levels if the queue metadata is at the expected version
+func (mdb *db) UpdateAckLevelsWithVersion(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	clusterAckLevels map[string]int64,
+	expectedVersion int64,
+) (sql.Result, error) {
+
+	data, err := json.Marshal(clusterAckLevels)
+	if err != nil {
+		return nil, err
+	}
+
+	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateUpdateQueueMetadataWithVersion, data, expectedVersion+1, queueType, expectedVersion)
+}
+
+// GetAckLevelsWithVersion returns ack levels for pulling clusters with the version of the queue metadata
+func (mdb *db) GetAckLevelsWithVersion(
+	ctx context.Context,
+	queueType persistence.QueueType,
+) (map[string]int64, int64, error) {
+
+	var row struct {
+		Data    []byte
+		Version int64
+	}
+	err := mdb.driver.GetContext(ctx, sqlplugin.DbDefaultShard, &row, templateGetQueueMetadataWithVersionQuery, queueType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var clusterAckLevels map[string]int64
+	if err := json.Unmarshal(row.Data, &clusterAckLevels); err != nil {
+		return nil, 0, err
+	}
+
+	return clusterAckLevels, row.Version, nil
+}
+
+// UpdateMergeTokens updates cluster merge tokens
+func (mdb *db) UpdateMergeTokens(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	clusterMergeTokens map[string]string,
+) error {
+
+	data, err := json.Marshal(clusterMergeTokens)
+	if err != nil {
+		return err
+	}
+
+	_, err = mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateUpdateQueueMergeTokensQuery, data, queueType)
+	return err
+}
+
+// GetMergeTokens returns merge tokens for pulling clusters
+func (mdb *db) GetMergeTokens(
+	ctx context.Context,
+	queueType persistence.QueueType,
+) (map[string]string, error) {
+
+	var data []byte
+	err := mdb.driver.GetContext(ctx, sqlplugin.DbDefaultShard, &data, templateGetQueueMergeTokensQuery, queueType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	clusterMergeTokens := make(map[string]string)
+	if len(data) == 0 {
+		return clusterMergeTokens, nil
+	}
+	if err := json.Unmarshal(data, &clusterMergeTokens); err != nil {
+		return nil, err
+	}
+
+	return clusterMergeTokens, nil
+}
+
 // GetQueueSize returns the queue size
 func (mdb *db) GetQueueSize(
 	ctx context.Context,
@@ -205,3 +430,56 @@ func (mdb *db) GetQueueSize(
 	}
 	return size[0], nil
 }
+
+// GetQueueMessageTypeHistogram returns the number of messages of each task type
+func (mdb *db) GetQueueMessageTypeHistogram(
+	ctx context.Context,
+	queueType persistence.QueueType,
+) (map[int32]int64, error) {
+
+	var rows []struct {
+		TaskType int32
+		Count    int64
+	}
+	if err := mdb.driver.SelectContext(
+		ctx,
+		sqlplugin.DbDefaultShard,
+		&rows,
+		templateGetQueueMessageTypeHistogramQuery,
+		queueType,
+	); err != nil {
+		return nil, err
+	}
+
+	histogram := make(map[int32]int64, len(rows))
+	for _, row := range rows {
+		histogram[row.TaskType] = row.Count
+	}
+	return histogram, nil
+}
+
+// InsertIntoDLQMergeHistory inserts a new row into dlq_merge_history table
+func (mdb *db) InsertIntoDLQMergeHistory(
+	ctx context.Context,
+	row *sqlplugin.DLQMergeHistoryRow,
+) (sql.Result, error) {
+
+	row.MergedAt = mdb.converter.ToMySQLDateTime(row.MergedAt)
+	return mdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateInsertDLQMergeHistoryQuery, row)
+}
+
+// SelectFromDLQMergeHistory returns the most recent DLQ merge records
+func (mdb *db) SelectFromDLQMergeHistory(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	limit int,
+) ([]sqlplugin.DLQMergeHistoryRow, error) {
+
+	var rows []sqlplugin.DLQMergeHistoryRow
+	err := mdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateGetDLQMergeHistoryQuery, queueType, limit)
+	for i := range rows {
+		rows[i].QueueType = queueType
+		rows[i].MergedAt = mdb.converter.FromMySQLDateTime(rows[i].MergedAt)
+	}
+	return rows, err
+}
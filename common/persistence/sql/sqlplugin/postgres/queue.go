@@ -24,31 +24,64 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/sql/sqlplugin"
 )
 
 const (
-	templateEnqueueMessageQuery            = `INSERT INTO queue (queue_type, message_id, message_payload) VALUES(:queue_type, :message_id, :message_payload)`
-	templateGetLastMessageIDQuery          = `SELECT message_id FROM queue WHERE queue_type=$1 ORDER BY message_id DESC LIMIT 1 FOR UPDATE`
-	templateGetMessagesQuery               = `SELECT message_id, message_payload FROM queue WHERE queue_type = $1 and message_id > $2 ORDER BY message_id ASC LIMIT $3`
-	templateGetMessagesBetweenQuery        = `SELECT message_id, message_payload FROM queue WHERE queue_type = $1 and messageid > $2 and message_id <= $3 ORDER BY message_id ASC LIMIT $4`
-	templateDeleteMessageQuery             = `DELETE FROM queue WHERE queue_type = $1 and message_id = $2`
-	templateDeleteMessagesBeforeQuery      = `DELETE FROM queue WHERE queue_type = $1 and message_id < $2`
-	templateRangeDeleteMessagesQuery       = `DELETE FROM queue WHERE queue_type = $1 and message_id > $2 and message_id <= $3`
-	templateGetQueueMetadataQuery          = `SELECT data from queue_metadata WHERE queue_type = $1`
-	templateGetQueueMetadataForUpdateQuery = templateGetQueueMetadataQuery + ` FOR UPDATE`
-	templateInsertQueueMetadataQuery       = `INSERT INTO queue_metadata (queue_type, data) VALUES(:queue_type, :data)`
-	templateUpdateQueueMetadataQuery       = `UPDATE queue_metadata SET data = $1 WHERE queue_type = $2`
-	templateGetQueueSizeQuery              = `SELECT COUNT(1) AS count FROM queue WHERE queue_type=$1`
+	templateEnqueueMessageQuery               = `INSERT INTO queue (queue_type, message_id, message_payload, enqueued_at, domain_id, task_type) VALUES(:queue_type, :message_id, :message_payload, :enqueued_at, :domain_id, :task_type)`
+	templateGetLastMessageIDQuery             = `SELECT message_id FROM queue WHERE queue_type=$1 ORDER BY message_id DESC LIMIT 1 FOR UPDATE`
+	templateGetMessagesQuery                  = `SELECT message_id, message_payload FROM queue WHERE queue_type = $1 and message_id > $2 ORDER BY message_id ASC LIMIT $3`
+	templateGetMessagesBetweenQuery           = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id FROM queue WHERE queue_type = $1 and message_id > $2 and message_id <= $3 and deleted_at IS NULL ORDER BY message_id ASC LIMIT $4`
+	templateGetMessagesByDomainQuery          = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id FROM queue WHERE queue_type = $1 and domain_id = $2 and message_id > $3 and message_id <= $4 and deleted_at IS NULL ORDER BY message_id ASC LIMIT $5`
+	templateGetMessagesByEnqueueTimeQuery     = `SELECT message_id, message_payload, attempts, enqueued_at, domain_id FROM queue WHERE queue_type = $1 and enqueued_at >= $2 and enqueued_at < $3 and message_id > $4 and message_id <= $5 and deleted_at IS NULL ORDER BY message_id ASC LIMIT $6`
+	templateDeleteMessageQuery                = `DELETE FROM queue WHERE queue_type = $1 and message_id = $2`
+	templateUpdateMessageAttemptsQuery        = `UPDATE queue SET attempts = $1 WHERE queue_type = $2 and message_id = $3`
+	templateUpdateMessagePayloadQuery         = `UPDATE queue SET message_payload = $1 WHERE queue_type = $2 and message_id = $3`
+	templateDeleteMessagesBeforeQuery         = `DELETE FROM queue WHERE queue_type = $1 and message_id < $2`
+	templateRangeDeleteMessagesQuery          = `DELETE FROM queue WHERE queue_type = $1 and message_id > $2 and message_id <= $3`
+	templateRangeDeleteMessagesByDomainQuery  = `DELETE FROM queue WHERE queue_type = $1 and domain_id = $2 and message_id > $3 and message_id <= $4`
+	templateSoftDeleteMessagesQuery           = `UPDATE queue SET deleted_at = $1 WHERE queue_type = $2 and message_id > $3 and message_id <= $4 and deleted_at IS NULL`
+	templateDeleteSoftDeletedMessagesQuery    = `DELETE FROM queue WHERE queue_type = $1 and deleted_at < $2`
+	templateGetQueueMetadataQuery             = `SELECT data from queue_metadata WHERE queue_type = $1`
+	templateGetQueueMetadataForUpdateQuery    = templateGetQueueMetadataQuery + ` FOR UPDATE`
+	templateInsertQueueMetadataQuery          = `INSERT INTO queue_metadata (queue_type, data) VALUES(:queue_type, :data)`
+	templateUpdateQueueMetadataQuery          = `UPDATE queue_metadata SET data = $1, version = version + 1 WHERE queue_type = $2`
+	templateGetQueueMetadataWithVersionQuery  = `SELECT data, version from queue_metadata WHERE queue_type = $1`
+	templateUpdateQueueMetadataWithVersion    = `UPDATE queue_metadata SET data = $1, version = $2 WHERE queue_type = $3 AND version = $4`
+	templateGetQueueMergeTokensQuery          = `SELECT merge_tokens from queue_metadata WHERE queue_type = $1`
+	templateUpdateQueueMergeTokensQuery       = `UPDATE queue_metadata SET merge_tokens = $1 WHERE queue_type = $2`
+	templateGetQueueSizeQuery                 = `SELECT COUNT(1) AS count FROM queue WHERE queue_type=$1 and deleted_at IS NULL`
+	templateGetQueueMessageTypeHistogramQuery = `SELECT task_type, COUNT(1) AS count FROM queue WHERE queue_type=$1 and task_type IS NOT NULL and deleted_at IS NULL GROUP BY task_type`
+	templateInsertDLQMergeHistoryQuery        = `INSERT INTO dlq_merge_history (queue_type, merged_at, start_message_id, end_message_id, merged_count, failed_count, duration, triggered_by, trace_id) VALUES(:queue_type, :merged_at, :start_message_id, :end_message_id, :merged_count, :failed_count, :duration, :triggered_by, :trace_id)`
+	templateGetDLQMergeHistoryQuery           = `SELECT merged_at, start_message_id, end_message_id, merged_count, failed_count, duration, triggered_by, trace_id FROM dlq_merge_history WHERE queue_type = $1 ORDER BY merged_at DESC, start_message_id DESC LIMIT $2`
 )
 
 // InsertIntoQueue inserts a new row into queue table
 func (pdb *db) InsertIntoQueue(ctx context.Context, row *sqlplugin.QueueRow) (sql.Result, error) {
+	if row.EnqueuedAt != nil {
+		enqueuedAt := pdb.converter.ToPostgresDateTime(*row.EnqueuedAt)
+		row.EnqueuedAt = &enqueuedAt
+	}
 	return pdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateEnqueueMessageQuery, row)
 }
 
+// InsertIntoQueueBatch inserts new rows into queue table with a single multi-row insert
+func (pdb *db) InsertIntoQueueBatch(ctx context.Context, rows []sqlplugin.QueueRow) (sql.Result, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	for i := range rows {
+		if rows[i].EnqueuedAt != nil {
+			enqueuedAt := pdb.converter.ToPostgresDateTime(*rows[i].EnqueuedAt)
+			rows[i].EnqueuedAt = &enqueuedAt
+		}
+	}
+	return pdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateEnqueueMessageQuery, rows)
+}
+
 // GetLastEnqueuedMessageIDForUpdate returns the last enqueued message ID
 func (pdb *db) GetLastEnqueuedMessageIDForUpdate(ctx context.Context, queueType persistence.QueueType) (int64, error) {
 	var lastMessageID int64
@@ -67,6 +100,39 @@ func (pdb *db) GetMessagesFromQueue(ctx context.Context, queueType persistence.Q
 func (pdb *db) GetMessagesBetween(ctx context.Context, queueType persistence.QueueType, firstMessageID int64, lastMessageID int64, maxRows int) ([]sqlplugin.QueueRow, error) {
 	var rows []sqlplugin.QueueRow
 	err := pdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateGetMessagesBetweenQuery, queueType, firstMessageID, lastMessageID, maxRows)
+	for i := range rows {
+		if rows[i].EnqueuedAt != nil {
+			enqueuedAt := pdb.converter.FromPostgresDateTime(*rows[i].EnqueuedAt)
+			rows[i].EnqueuedAt = &enqueuedAt
+		}
+	}
+	return rows, err
+}
+
+// GetMessagesBetweenByDomain retrieves the messages of a domain from the queue
+func (pdb *db) GetMessagesBetweenByDomain(ctx context.Context, queueType persistence.QueueType, domainID string, firstMessageID int64, lastMessageID int64, maxRows int) ([]sqlplugin.QueueRow, error) {
+	var rows []sqlplugin.QueueRow
+	err := pdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateGetMessagesByDomainQuery, queueType, domainID, firstMessageID, lastMessageID, maxRows)
+	for i := range rows {
+		if rows[i].EnqueuedAt != nil {
+			enqueuedAt := pdb.converter.FromPostgresDateTime(*rows[i].EnqueuedAt)
+			rows[i].EnqueuedAt = &enqueuedAt
+		}
+	}
+	return rows, err
+}
+
+// GetMessagesBetweenByEnqueueTime retrieves the messages enqueued between startTime, inclusive, and endTime, exclusive, from the queue
+func (pdb *db) GetMessagesBetweenByEnqueueTime(ctx context.Context, queueType persistence.QueueType, startTime time.Time, endTime time.Time, firstMessageID int64, lastMessageID int64, maxRows int) ([]sqlplugin.QueueRow, error) {
+	var rows []sqlplugin.QueueRow
+	err := pdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateGetMessagesByEnqueueTimeQuery, queueType,
+		pdb.converter.ToPostgresDateTime(startTime), pdb.converter.ToPostgresDateTime(endTime), firstMessageID, lastMessageID, maxRows)
+	for i := range rows {
+		if rows[i].EnqueuedAt != nil {
+			enqueuedAt := pdb.converter.FromPostgresDateTime(*rows[i].EnqueuedAt)
+			rows[i].EnqueuedAt = &enqueuedAt
+		}
+	}
 	return rows, err
 }
 
@@ -80,11 +146,36 @@ func (pdb *db) RangeDeleteMessages(ctx context.Context, queueType persistence.Qu
 	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateRangeDeleteMessagesQuery, queueType, exclusiveBeginMessageID, inclusiveEndMessageID)
 }
 
+// RangeDeleteMessagesByDomain deletes the messages of a domain in a range from the queue
+func (pdb *db) RangeDeleteMessagesByDomain(ctx context.Context, queueType persistence.QueueType, domainID string, exclusiveBeginMessageID int64, inclusiveEndMessageID int64) (sql.Result, error) {
+	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateRangeDeleteMessagesByDomainQuery, queueType, domainID, exclusiveBeginMessageID, inclusiveEndMessageID)
+}
+
+// SoftDeleteMessages soft deletes the messages in a range from the queue by setting their deleted_at time
+func (pdb *db) SoftDeleteMessages(ctx context.Context, queueType persistence.QueueType, exclusiveBeginMessageID int64, inclusiveEndMessageID int64, deletedAt time.Time) (sql.Result, error) {
+	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateSoftDeleteMessagesQuery, pdb.converter.ToPostgresDateTime(deletedAt), queueType, exclusiveBeginMessageID, inclusiveEndMessageID)
+}
+
+// DeleteSoftDeletedMessages deletes the messages soft deleted before deletedBefore from the queue
+func (pdb *db) DeleteSoftDeletedMessages(ctx context.Context, queueType persistence.QueueType, deletedBefore time.Time) (sql.Result, error) {
+	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateDeleteSoftDeletedMessagesQuery, queueType, pdb.converter.ToPostgresDateTime(deletedBefore))
+}
+
 // DeleteMessage deletes message with a messageID from the queue
 func (pdb *db) DeleteMessage(ctx context.Context, queueType persistence.QueueType, messageID int64) (sql.Result, error) {
 	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateDeleteMessageQuery, queueType, messageID)
 }
 
+// UpdateMessageAttempts updates the number of attempts made to process a message
+func (pdb *db) UpdateMessageAttempts(ctx context.Context, queueType persistence.QueueType, messageID int64, attempts int) (sql.Result, error) {
+	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateUpdateMessageAttemptsQuery, attempts, queueType, messageID)
+}
+
+// UpdateMessagePayload replaces the payload of a message
+func (pdb *db) UpdateMessagePayload(ctx context.Context, queueType persistence.QueueType, messageID int64, payload []byte) (sql.Result, error) {
+	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateUpdateMessagePayloadQuery, payload, queueType, messageID)
+}
+
 // InsertAckLevel inserts ack level
 func (pdb *db) InsertAckLevel(ctx context.Context, queueType persistence.QueueType, messageID int64, clusterName string) error {
 	clusterAckLevels := map[string]int64{clusterName: messageID}
@@ -134,6 +225,78 @@ func (pdb *db) GetAckLevels(ctx context.Context, queueType persistence.QueueType
 	return clusterAckLevels, nil
 }
 
+// UpdateAckLevelsWithVersion updates cluster ack levels if the queue metadata is at the expected version
+func (pdb *db) UpdateAckLevelsWithVersion(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	clusterAckLevels map[string]int64,
+	expectedVersion int64,
+) (sql.Result, error) {
+
+	data, err := json.Marshal(clusterAckLevels)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateUpdateQueueMetadataWithVersion, data, expectedVersion+1, queueType, expectedVersion)
+}
+
+// GetAckLevelsWithVersion returns ack levels for pulling clusters with the version of the queue metadata
+func (pdb *db) GetAckLevelsWithVersion(
+	ctx context.Context,
+	queueType persistence.QueueType,
+) (map[string]int64, int64, error) {
+
+	var row struct {
+		Data    []byte
+		Version int64
+	}
+	err := pdb.driver.GetContext(ctx, sqlplugin.DbDefaultShard, &row, templateGetQueueMetadataWithVersionQuery, queueType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var clusterAckLevels map[string]int64
+	if err := json.Unmarshal(row.Data, &clusterAckLevels); err != nil {
+		return nil, 0, err
+	}
+
+	return clusterAckLevels, row.Version, nil
+}
+
+// UpdateMergeTokens updates cluster merge tokens
+func (pdb *db) UpdateMergeTokens(ctx context.Context, queueType persistence.QueueType, clusterMergeTokens map[string]string) error {
+	data, err := json.Marshal(clusterMergeTokens)
+	if err != nil {
+		return err
+	}
+	_, err = pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateUpdateQueueMergeTokensQuery, data, queueType)
+	return err
+}
+
+// GetMergeTokens returns merge tokens for pulling clusters
+func (pdb *db) GetMergeTokens(ctx context.Context, queueType persistence.QueueType) (map[string]string, error) {
+	var data []byte
+	err := pdb.driver.GetContext(ctx, sqlplugin.DbDefaultShard, &data, templateGetQueueMergeTokensQuery, queueType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	clusterMergeTokens := make(map[string]string)
+	if len(data) == 0 {
+		return clusterMergeTokens, nil
+	}
+	if err := json.Unmarshal(data, &clusterMergeTokens); err != nil {
+		return nil, err
+	}
+
+	return clusterMergeTokens, nil
+}
+
 // GetQueueSize returns the queue size
 func (pdb *db) GetQueueSize(
 	ctx context.Context,
@@ -152,3 +315,56 @@ func (pdb *db) GetQueueSize(
 	}
 	return size[0], nil
 }
+
+// GetQueueMessageTypeHistogram returns the number of messages of each task type
+func (pdb *db) GetQueueMessageTypeHistogram(
+	ctx context.Context,
+	queueType persistence.QueueType,
+) (map[int32]int64, error) {
+
+	var rows []struct {
+		TaskType int32
+		Count    int64
+	}
+	if err := pdb.driver.SelectContext(
+		ctx,
+		sqlplugin.DbDefaultShard,
+		&rows,
+		templateGetQueueMessageTypeHistogramQuery,
+		queueType,
+	); err != nil {
+		return nil, err
+	}
+
+	histogram := make(map[int32]int64, len(rows))
+	for _, row := range rows {
+		histogram[row.TaskType] = row.Count
+	}
+	return histogram, nil
+}
+
+// InsertIntoDLQMergeHistory inserts a new row into dlq_merge_history table
+func (pdb *db) InsertIntoDLQMergeHistory(
+	ctx context.Context,
+	row *sqlplugin.DLQMergeHistoryRow,
+) (sql.Result, error) {
+
+	row.MergedAt = pdb.converter.ToPostgresDateTime(row.MergedAt)
+	return pdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateInsertDLQMergeHistoryQuery, row)
+}
+
+// SelectFromDLQMergeHistory returns the most recent DLQ merge records
+func (pdb *db) SelectFromDLQMergeHistory(
+	ctx context.Context,
+	queueType persistence.QueueType,
+	limit int,
+) ([]sqlplugin.DLQMergeHistoryRow, error) {
+
+	var rows []sqlplugin.DLQMergeHistoryRow
+	err := pdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateGetDLQMergeHistoryQuery, queueType, limit)
+	for i := range rows {
+		rows[i].QueueType = queueType
+		rows[i].MergedAt = pdb.converter.FromPostgresDateTime(rows[i].MergedAt)
+	}
+	return rows, err
+}
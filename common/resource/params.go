@@ -31,12 +31,14 @@ import (
 	"github.com/uber/cadence/common/blobstore"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/domain"
 	"github.com/uber/cadence/common/dynamicconfig"
 	es "github.com/uber/cadence/common/elasticsearch"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
 )
 
 type (
@@ -66,5 +68,13 @@ type (
 		ArchiverProvider         provider.ArchiverProvider
 		Authorizer               authorization.Authorizer // NOTE: this can be nil. If nil, AccessControlledHandlerImpl will initiate one with config.Authorization
 		AuthorizationConfig      config.Authorization     // NOTE: empty(default) struct will get a authorization.NoopAuthorizer
+		// DomainReplicationTaskExecutors are the executors of custom replication task types merged from the domain DLQ,
+		// they are registered on top of the executors of the built-in task types
+		DomainReplicationTaskExecutors map[types.ReplicationTaskType]domain.ReplicationTaskExecutor
+		// DomainReplicationFanOutDestinations are the executors of the remote clusters, keyed by cluster name, the domain DLQ
+		// messages of a task type are applied to in addition to the current cluster in active-active setups
+		DomainReplicationFanOutDestinations map[types.ReplicationTaskType]map[string]domain.ReplicationTaskExecutor
+		// DomainDLQDeduplicationFilter drops the replication tasks enqueued to the domain DLQ more than once, can be nil
+		DomainDLQDeduplicationFilter domain.DeduplicationFilter
 	}
 )
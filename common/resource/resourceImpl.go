@@ -188,11 +188,23 @@ func New(
 	)
 
 	domainMetricsScopeCache := cache.NewDomainMetricsScopeCache()
-	domainReplicationQueue := domain.NewReplicationQueue(
-		persistenceBean.GetDomainReplicationQueueManager(),
+	var domainReplicationQueueOptions []domain.ReplicationQueueOption
+	if params.DomainDLQDeduplicationFilter != nil {
+		domainReplicationQueueOptions = append(domainReplicationQueueOptions, domain.WithEnqueueDeduplication(params.DomainDLQDeduplicationFilter))
+	}
+	domainReplicationQueueManagers := make([]persistence.QueueManager, common.MaxInt(params.PersistenceConfig.DomainReplicationDLQNumShards, 1))
+	for shardID := range domainReplicationQueueManagers {
+		domainReplicationQueueManagers[shardID], err = persistenceBean.GetDomainReplicationQueueShardManager(shardID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	domainReplicationQueue := domain.NewShardedReplicationQueue(
+		domainReplicationQueueManagers,
 		params.ClusterMetadata.GetCurrentClusterName(),
 		params.MetricsClient,
 		logger,
+		domainReplicationQueueOptions...,
 	)
 
 	frontendRawClient := clientBean.GetFrontendClient()
@@ -374,6 +374,7 @@ func FromAdminGetReplicationMessagesResponse(t *types.GetReplicationMessagesResp
 	}
 	return &adminv1.GetReplicationMessagesResponse{
 		ShardMessages: FromReplicationMessagesMap(t.MessagesByShard),
+		DlqAckLevel:   t.DLQAckLevel,
 	}
 }
 
@@ -383,6 +384,7 @@ func ToAdminGetReplicationMessagesResponse(t *adminv1.GetReplicationMessagesResp
 	}
 	return &types.GetReplicationMessagesResponse{
 		MessagesByShard: ToReplicationMessagesMap(t.ShardMessages),
+		DLQAckLevel:     t.DlqAckLevel,
 	}
 }
 
@@ -546,6 +548,148 @@ func ToAdminPurgeDLQMessagesRequest(t *adminv1.PurgeDLQMessagesRequest) *types.P
 	}
 }
 
+func FromAdminForceUpdateDLQAckLevelRequest(t *types.ForceUpdateDLQAckLevelRequest) *adminv1.ForceUpdateDLQAckLevelRequest {
+	if t == nil {
+		return nil
+	}
+	return &adminv1.ForceUpdateDLQAckLevelRequest{
+		Type:     FromDLQType(t.Type),
+		AckLevel: t.AckLevel,
+		Reason:   t.Reason,
+	}
+}
+
+func ToAdminForceUpdateDLQAckLevelRequest(t *adminv1.ForceUpdateDLQAckLevelRequest) *types.ForceUpdateDLQAckLevelRequest {
+	if t == nil {
+		return nil
+	}
+	return &types.ForceUpdateDLQAckLevelRequest{
+		Type:     ToDLQType(t.Type),
+		AckLevel: t.AckLevel,
+		Reason:   t.Reason,
+	}
+}
+
+func FromAdminPauseDLQProcessingRequest(t *types.PauseDLQProcessingRequest) *adminv1.PauseDLQProcessingRequest {
+	if t == nil {
+		return nil
+	}
+	return &adminv1.PauseDLQProcessingRequest{
+		Type: FromDLQType(t.Type),
+	}
+}
+
+func ToAdminPauseDLQProcessingRequest(t *adminv1.PauseDLQProcessingRequest) *types.PauseDLQProcessingRequest {
+	if t == nil {
+		return nil
+	}
+	return &types.PauseDLQProcessingRequest{
+		Type: ToDLQType(t.Type),
+	}
+}
+
+func FromAdminResumeDLQProcessingRequest(t *types.ResumeDLQProcessingRequest) *adminv1.ResumeDLQProcessingRequest {
+	if t == nil {
+		return nil
+	}
+	return &adminv1.ResumeDLQProcessingRequest{
+		Type: FromDLQType(t.Type),
+	}
+}
+
+func ToAdminResumeDLQProcessingRequest(t *adminv1.ResumeDLQProcessingRequest) *types.ResumeDLQProcessingRequest {
+	if t == nil {
+		return nil
+	}
+	return &types.ResumeDLQProcessingRequest{
+		Type: ToDLQType(t.Type),
+	}
+}
+
+func FromAdminListDLQConsumersRequest(t *types.ListDLQConsumersRequest) *adminv1.ListDLQConsumersRequest {
+	if t == nil {
+		return nil
+	}
+	return &adminv1.ListDLQConsumersRequest{
+		Type:        FromDLQType(t.Type),
+		ClusterName: t.ClusterName,
+	}
+}
+
+func ToAdminListDLQConsumersRequest(t *adminv1.ListDLQConsumersRequest) *types.ListDLQConsumersRequest {
+	if t == nil {
+		return nil
+	}
+	return &types.ListDLQConsumersRequest{
+		Type:        ToDLQType(t.Type),
+		ClusterName: t.ClusterName,
+	}
+}
+
+func FromAdminListDLQConsumersResponse(t *types.ListDLQConsumersResponse) *adminv1.ListDLQConsumersResponse {
+	if t == nil {
+		return nil
+	}
+	return &adminv1.ListDLQConsumersResponse{
+		Consumers: FromDLQConsumerArray(t.Consumers),
+	}
+}
+
+func ToAdminListDLQConsumersResponse(t *adminv1.ListDLQConsumersResponse) *types.ListDLQConsumersResponse {
+	if t == nil {
+		return nil
+	}
+	return &types.ListDLQConsumersResponse{
+		Consumers: ToDLQConsumerArray(t.Consumers),
+	}
+}
+
+func FromDLQConsumerArray(t []*types.DLQConsumer) []*adminv1.DLQConsumer {
+	if t == nil {
+		return nil
+	}
+	v := make([]*adminv1.DLQConsumer, len(t))
+	for i := range t {
+		v[i] = FromDLQConsumer(t[i])
+	}
+	return v
+}
+
+func ToDLQConsumerArray(t []*adminv1.DLQConsumer) []*types.DLQConsumer {
+	if t == nil {
+		return nil
+	}
+	v := make([]*types.DLQConsumer, len(t))
+	for i := range t {
+		v[i] = ToDLQConsumer(t[i])
+	}
+	return v
+}
+
+func FromDLQConsumer(t *types.DLQConsumer) *adminv1.DLQConsumer {
+	if t == nil {
+		return nil
+	}
+	return &adminv1.DLQConsumer{
+		ConsumerGroup: t.ConsumerGroup,
+		TaskType:      t.TaskType,
+		AckLevel:      t.AckLevel,
+		UpdatedTime:   unixNanoToTime(t.UpdatedTime),
+	}
+}
+
+func ToDLQConsumer(t *adminv1.DLQConsumer) *types.DLQConsumer {
+	if t == nil {
+		return nil
+	}
+	return &types.DLQConsumer{
+		ConsumerGroup: t.ConsumerGroup,
+		TaskType:      t.TaskType,
+		AckLevel:      t.AckLevel,
+		UpdatedTime:   timeToUnixNano(t.UpdatedTime),
+	}
+}
+
 func FromAdminReadDLQMessagesRequest(t *types.ReadDLQMessagesRequest) *adminv1.ReadDLQMessagesRequest {
 	if t == nil {
 		return nil
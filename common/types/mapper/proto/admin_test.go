@@ -147,6 +147,31 @@ func TestAdminPurgeDLQMessagesRequest(t *testing.T) {
 		assert.Equal(t, item, ToAdminPurgeDLQMessagesRequest(FromAdminPurgeDLQMessagesRequest(item)))
 	}
 }
+func TestAdminForceUpdateDLQAckLevelRequest(t *testing.T) {
+	for _, item := range []*types.ForceUpdateDLQAckLevelRequest{nil, {}, &testdata.AdminForceUpdateDLQAckLevelRequest} {
+		assert.Equal(t, item, ToAdminForceUpdateDLQAckLevelRequest(FromAdminForceUpdateDLQAckLevelRequest(item)))
+	}
+}
+func TestAdminPauseDLQProcessingRequest(t *testing.T) {
+	for _, item := range []*types.PauseDLQProcessingRequest{nil, {}, &testdata.AdminPauseDLQProcessingRequest} {
+		assert.Equal(t, item, ToAdminPauseDLQProcessingRequest(FromAdminPauseDLQProcessingRequest(item)))
+	}
+}
+func TestAdminResumeDLQProcessingRequest(t *testing.T) {
+	for _, item := range []*types.ResumeDLQProcessingRequest{nil, {}, &testdata.AdminResumeDLQProcessingRequest} {
+		assert.Equal(t, item, ToAdminResumeDLQProcessingRequest(FromAdminResumeDLQProcessingRequest(item)))
+	}
+}
+func TestAdminListDLQConsumersRequest(t *testing.T) {
+	for _, item := range []*types.ListDLQConsumersRequest{nil, {}, &testdata.AdminListDLQConsumersRequest} {
+		assert.Equal(t, item, ToAdminListDLQConsumersRequest(FromAdminListDLQConsumersRequest(item)))
+	}
+}
+func TestAdminListDLQConsumersResponse(t *testing.T) {
+	for _, item := range []*types.ListDLQConsumersResponse{nil, {}, &testdata.AdminListDLQConsumersResponse} {
+		assert.Equal(t, item, ToAdminListDLQConsumersResponse(FromAdminListDLQConsumersResponse(item)))
+	}
+}
 func TestAdminReadDLQMessagesRequest(t *testing.T) {
 	for _, item := range []*types.ReadDLQMessagesRequest{nil, {}, &testdata.AdminReadDLQMessagesRequest} {
 		assert.Equal(t, item, ToAdminReadDLQMessagesRequest(FromAdminReadDLQMessagesRequest(item)))
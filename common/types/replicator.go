@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // DLQType is an internal type (TBD...)
@@ -235,6 +236,49 @@ func (v *FailoverMarkerAttributes) GetCreationTime() (o int64) {
 	return
 }
 
+// WorkflowResetTaskAttributes is an internal type (TBD...)
+type WorkflowResetTaskAttributes struct {
+	DomainID              string `json:"domainID,omitempty"`
+	WorkflowID            string `json:"workflowID,omitempty"`
+	RunID                 string `json:"runID,omitempty"`
+	Reason                string `json:"reason,omitempty"`
+	DecisionFinishEventID int64  `json:"decisionFinishEventId,omitempty"`
+	RequestID             string `json:"requestId,omitempty"`
+	SkipSignalReapply     bool   `json:"skipSignalReapply,omitempty"`
+}
+
+// GetDomainID is an internal getter (TBD...)
+func (v *WorkflowResetTaskAttributes) GetDomainID() (o string) {
+	if v != nil {
+		return v.DomainID
+	}
+	return
+}
+
+// GetWorkflowID is an internal getter (TBD...)
+func (v *WorkflowResetTaskAttributes) GetWorkflowID() (o string) {
+	if v != nil {
+		return v.WorkflowID
+	}
+	return
+}
+
+// GetRunID is an internal getter (TBD...)
+func (v *WorkflowResetTaskAttributes) GetRunID() (o string) {
+	if v != nil {
+		return v.RunID
+	}
+	return
+}
+
+// GetRequestID is an internal getter (TBD...)
+func (v *WorkflowResetTaskAttributes) GetRequestID() (o string) {
+	if v != nil {
+		return v.RequestID
+	}
+	return
+}
+
 // FailoverMarkers is an internal type (TBD...)
 type FailoverMarkers struct {
 	FailoverMarkers []*FailoverMarkerAttributes `json:"failoverMarkers,omitempty"`
@@ -248,6 +292,37 @@ func (v *FailoverMarkers) GetFailoverMarkers() (o []*FailoverMarkerAttributes) {
 	return
 }
 
+// ForceUpdateDLQAckLevelRequest is an internal type (TBD...)
+type ForceUpdateDLQAckLevelRequest struct {
+	Type     *DLQType `json:"type,omitempty"`
+	AckLevel int64    `json:"ackLevel,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// GetType is an internal getter (TBD...)
+func (v *ForceUpdateDLQAckLevelRequest) GetType() (o DLQType) {
+	if v != nil && v.Type != nil {
+		return *v.Type
+	}
+	return
+}
+
+// GetAckLevel is an internal getter (TBD...)
+func (v *ForceUpdateDLQAckLevelRequest) GetAckLevel() (o int64) {
+	if v != nil {
+		return v.AckLevel
+	}
+	return
+}
+
+// GetReason is an internal getter (TBD...)
+func (v *ForceUpdateDLQAckLevelRequest) GetReason() (o string) {
+	if v != nil {
+		return v.Reason
+	}
+	return
+}
+
 // GetDLQReplicationMessagesRequest is an internal type (TBD...)
 type GetDLQReplicationMessagesRequest struct {
 	TaskInfos []*ReplicationTaskInfo `json:"taskInfos,omitempty"`
@@ -343,6 +418,7 @@ func (v *GetReplicationMessagesRequest) GetClusterName() (o string) {
 // GetReplicationMessagesResponse is an internal type (TBD...)
 type GetReplicationMessagesResponse struct {
 	MessagesByShard map[int32]*ReplicationMessages `json:"messagesByShard,omitempty"`
+	DLQAckLevel     int64                          `json:"dlqAckLevel,omitempty"`
 }
 
 // GetMessagesByShard is an internal getter (TBD...)
@@ -353,6 +429,14 @@ func (v *GetReplicationMessagesResponse) GetMessagesByShard() (o map[int32]*Repl
 	return
 }
 
+// GetDLQAckLevel is an internal getter (TBD...)
+func (v *GetReplicationMessagesResponse) GetDLQAckLevel() (o int64) {
+	if v != nil {
+		return v.DLQAckLevel
+	}
+	return
+}
+
 // HistoryTaskV2Attributes is an internal type (TBD...)
 type HistoryTaskV2Attributes struct {
 	TaskID              int64                 `json:"taskId,omitempty"`
@@ -440,6 +524,58 @@ type HistoryCountDLQMessagesResponse struct {
 	Entries map[HistoryDLQCountKey]int64
 }
 
+// ListDLQConsumersRequest is an internal type (TBD...)
+type ListDLQConsumersRequest struct {
+	Type        *DLQType `json:"type,omitempty"`
+	ClusterName string   `json:"clusterName,omitempty"`
+}
+
+// GetType is an internal getter (TBD...)
+func (v *ListDLQConsumersRequest) GetType() (o DLQType) {
+	if v != nil && v.Type != nil {
+		return *v.Type
+	}
+	return
+}
+
+// GetClusterName is an internal getter (TBD...)
+func (v *ListDLQConsumersRequest) GetClusterName() (o string) {
+	if v != nil {
+		return v.ClusterName
+	}
+	return
+}
+
+// ListDLQConsumersResponse is an internal type (TBD...)
+type ListDLQConsumersResponse struct {
+	Consumers []*DLQConsumer `json:"consumers,omitempty"`
+}
+
+// GetConsumers is an internal getter (TBD...)
+func (v *ListDLQConsumersResponse) GetConsumers() (o []*DLQConsumer) {
+	if v != nil && v.Consumers != nil {
+		return v.Consumers
+	}
+	return
+}
+
+// DLQConsumer is an internal type (TBD...)
+type DLQConsumer struct {
+	ConsumerGroup string `json:"consumerGroup,omitempty"`
+	// TaskType is empty for the consumers of all task types
+	TaskType    string `json:"taskType,omitempty"`
+	AckLevel    int64  `json:"ackLevel,omitempty"`
+	UpdatedTime *int64 `json:"updatedTime,omitempty"`
+}
+
+// GetUpdatedTime is an internal getter (TBD...)
+func (v *DLQConsumer) GetUpdatedTime() (o int64) {
+	if v != nil && v.UpdatedTime != nil {
+		return *v.UpdatedTime
+	}
+	return
+}
+
 // MergeDLQMessagesRequest is an internal type (TBD...)
 type MergeDLQMessagesRequest struct {
 	Type                  *DLQType `json:"type,omitempty"`
@@ -511,6 +647,19 @@ func (v *MergeDLQMessagesResponse) GetNextPageToken() (o []byte) {
 	return
 }
 
+// PauseDLQProcessingRequest is an internal type (TBD...)
+type PauseDLQProcessingRequest struct {
+	Type *DLQType `json:"type,omitempty"`
+}
+
+// GetType is an internal getter (TBD...)
+func (v *PauseDLQProcessingRequest) GetType() (o DLQType) {
+	if v != nil && v.Type != nil {
+		return *v.Type
+	}
+	return
+}
+
 // PurgeDLQMessagesRequest is an internal type (TBD...)
 type PurgeDLQMessagesRequest struct {
 	Type                  *DLQType `json:"type,omitempty"`
@@ -649,6 +798,19 @@ func (v *ReadDLQMessagesResponse) GetNextPageToken() (o []byte) {
 	return
 }
 
+// ResumeDLQProcessingRequest is an internal type (TBD...)
+type ResumeDLQProcessingRequest struct {
+	Type *DLQType `json:"type,omitempty"`
+}
+
+// GetType is an internal getter (TBD...)
+func (v *ResumeDLQProcessingRequest) GetType() (o DLQType) {
+	if v != nil && v.Type != nil {
+		return *v.Type
+	}
+	return
+}
+
 // ReplicationMessages is an internal type (TBD...)
 type ReplicationMessages struct {
 	ReplicationTasks       []*ReplicationTask `json:"replicationTasks,omitempty"`
@@ -698,7 +860,31 @@ type ReplicationTask struct {
 	SyncActivityTaskAttributes    *SyncActivityTaskAttributes    `json:"syncActivityTaskAttributes,omitempty"`
 	HistoryTaskV2Attributes       *HistoryTaskV2Attributes       `json:"historyTaskV2Attributes,omitempty"`
 	FailoverMarkerAttributes      *FailoverMarkerAttributes      `json:"failoverMarkerAttributes,omitempty"`
-	CreationTime                  *int64                         `json:"creationTime,omitempty"`
+	// WorkflowResetTaskAttributes are the attributes of the workflow reset tasks.
+	// It is not part of the replication wire format and is only set on tasks written to or read from the local domain DLQ.
+	WorkflowResetTaskAttributes *WorkflowResetTaskAttributes `json:"workflowResetTaskAttributes,omitempty"`
+	CreationTime                *int64                       `json:"creationTime,omitempty"`
+	// EnqueuedAt is the time the task was written to the local domain DLQ.
+	// It is not part of the replication wire format and is only set on tasks read from the DLQ.
+	EnqueuedAt time.Time `json:"-"`
+	// SourceCluster is the cluster the task was replicated from.
+	// It is not part of the replication wire format and is only set on tasks written to or read from the local domain DLQ.
+	SourceCluster string `json:"sourceCluster,omitempty"`
+	// Priority is the priority the task is merged with from the local domain DLQ, higher priorities are merged first.
+	// It is not part of the replication wire format and is only set on tasks written to or read from the local domain DLQ.
+	Priority int `json:"priority,omitempty"`
+	// SchemaVersion is the DLQ schema version the task was persisted with.
+	// It is not part of the replication wire format and is only set on tasks read from the local domain DLQ.
+	SchemaVersion int `json:"-"`
+	// PayloadSize is the size in bytes of the task as persisted in the local domain DLQ.
+	// It is not part of the replication wire format and is only set on tasks read from the local domain DLQ.
+	PayloadSize int `json:"-"`
+	// RetryAfter is the time a task nacked back to the local domain DLQ becomes visible to merges again.
+	// It is not part of the replication wire format and is only set on tasks written to or read from the local domain DLQ.
+	RetryAfter time.Time `json:"-"`
+	// CorrelationID groups the tasks which are merged together from the local domain DLQ, e.g. the tasks of a workflow run.
+	// It is not part of the replication wire format and is only set on tasks written to or read from the local domain DLQ.
+	CorrelationID string `json:"correlationId,omitempty"`
 }
 
 // GetTaskType is an internal getter (TBD...)
@@ -757,6 +943,14 @@ func (v *ReplicationTask) GetFailoverMarkerAttributes() (o *FailoverMarkerAttrib
 	return
 }
 
+// GetWorkflowResetTaskAttributes is an internal getter (TBD...)
+func (v *ReplicationTask) GetWorkflowResetTaskAttributes() (o *WorkflowResetTaskAttributes) {
+	if v != nil && v.WorkflowResetTaskAttributes != nil {
+		return v.WorkflowResetTaskAttributes
+	}
+	return
+}
+
 // GetCreationTime is an internal getter (TBD...)
 func (v *ReplicationTask) GetCreationTime() (o int64) {
 	if v != nil && v.CreationTime != nil {
@@ -876,6 +1070,8 @@ func (e ReplicationTaskType) String() string {
 		return "HistoryV2"
 	case 6:
 		return "FailoverMarker"
+	case 7:
+		return "WorkflowReset"
 	}
 	return fmt.Sprintf("ReplicationTaskType(%d)", w)
 }
@@ -904,6 +1100,9 @@ func (e *ReplicationTaskType) UnmarshalText(value []byte) error {
 	case "FAILOVERMARKER":
 		*e = ReplicationTaskTypeFailoverMarker
 		return nil
+	case "WORKFLOWRESET":
+		*e = ReplicationTaskTypeWorkflowReset
+		return nil
 	default:
 		val, err := strconv.ParseInt(s, 10, 32)
 		if err != nil {
@@ -934,6 +1133,9 @@ const (
 	ReplicationTaskTypeHistoryV2
 	// ReplicationTaskTypeFailoverMarker is an option for ReplicationTaskType
 	ReplicationTaskTypeFailoverMarker
+	// ReplicationTaskTypeWorkflowReset is an option for ReplicationTaskType.
+	// It is not part of the replication wire format, the tasks of the type only exist in the local domain DLQ.
+	ReplicationTaskTypeWorkflowReset
 )
 
 // ReplicationToken is an internal type (TBD...)
@@ -95,6 +95,7 @@ var (
 	}
 	AdminGetReplicationMessagesResponse = types.GetReplicationMessagesResponse{
 		MessagesByShard: ReplicationMessagesMap,
+		DLQAckLevel:     MessageID1,
 	}
 	AdminGetWorkflowExecutionRawHistoryV2Request = types.GetWorkflowExecutionRawHistoryV2Request{
 		Domain:            DomainName,
@@ -133,6 +134,35 @@ var (
 		SourceCluster:         ClusterName1,
 		InclusiveEndMessageID: common.Int64Ptr(MessageID1),
 	}
+	AdminForceUpdateDLQAckLevelRequest = types.ForceUpdateDLQAckLevelRequest{
+		Type:     types.DLQTypeDomain.Ptr(),
+		AckLevel: MessageID1,
+		Reason:   Reason,
+	}
+	AdminPauseDLQProcessingRequest = types.PauseDLQProcessingRequest{
+		Type: types.DLQTypeDomain.Ptr(),
+	}
+	AdminResumeDLQProcessingRequest = types.ResumeDLQProcessingRequest{
+		Type: types.DLQTypeDomain.Ptr(),
+	}
+	AdminListDLQConsumersRequest = types.ListDLQConsumersRequest{
+		Type:        types.DLQTypeDomain.Ptr(),
+		ClusterName: ClusterName1,
+	}
+	AdminListDLQConsumersResponse = types.ListDLQConsumersResponse{
+		Consumers: []*types.DLQConsumer{
+			{
+				ConsumerGroup: "default",
+				AckLevel:      MessageID1,
+				UpdatedTime:   &Timestamp1,
+			},
+			{
+				ConsumerGroup: "replay",
+				TaskType:      types.ReplicationTaskTypeHistoryV2.String(),
+				AckLevel:      MessageID2,
+			},
+		},
+	}
 	AdminReadDLQMessagesRequest = types.ReadDLQMessagesRequest{
 		Type:                  types.DLQTypeDomain.Ptr(),
 		ShardID:               ShardID,
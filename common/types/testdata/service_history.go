@@ -74,12 +74,14 @@ var (
 		IsStickyTaskListEnabled:              true,
 	}
 	HistoryGetReplicationMessagesRequest  = AdminGetReplicationMessagesRequest
-	HistoryGetReplicationMessagesResponse = AdminGetReplicationMessagesResponse
-	HistoryCountDLQMessagesRequest        = types.CountDLQMessagesRequest{ForceFetch: true}
-	HistoryCountDLQMessagesResponse       = types.HistoryCountDLQMessagesResponse{Entries: map[types.HistoryDLQCountKey]int64{types.HistoryDLQCountKey{1, "A"}: 10}}
-	HistoryMergeDLQMessagesRequest        = AdminMergeDLQMessagesRequest
-	HistoryMergeDLQMessagesResponse       = AdminMergeDLQMessagesResponse
-	HistoryNotifyFailoverMarkersRequest   = types.NotifyFailoverMarkersRequest{
+	HistoryGetReplicationMessagesResponse = types.GetReplicationMessagesResponse{
+		MessagesByShard: ReplicationMessagesMap,
+	}
+	HistoryCountDLQMessagesRequest      = types.CountDLQMessagesRequest{ForceFetch: true}
+	HistoryCountDLQMessagesResponse     = types.HistoryCountDLQMessagesResponse{Entries: map[types.HistoryDLQCountKey]int64{types.HistoryDLQCountKey{1, "A"}: 10}}
+	HistoryMergeDLQMessagesRequest      = AdminMergeDLQMessagesRequest
+	HistoryMergeDLQMessagesResponse     = AdminMergeDLQMessagesResponse
+	HistoryNotifyFailoverMarkersRequest = types.NotifyFailoverMarkersRequest{
 		FailoverMarkerTokens: FailoverMarkerTokenArray,
 	}
 	HistoryPollMutableStateRequest = types.PollMutableStateRequest{
@@ -152,7 +152,7 @@ func NewCluster(options *TestClusterConfig, logger log.Logger, params persistenc
 		HistoryConfig:                 options.HistoryConfig,
 		WorkerConfig:                  options.WorkerConfig,
 		MockAdminClient:               options.MockAdminClient,
-		DomainReplicationTaskExecutor: domain.NewReplicationTaskExecutor(testBase.DomainManager, clock.NewRealTimeSource(), logger),
+		DomainReplicationTaskExecutor: domain.NewReplicationTaskExecutor(testBase.DomainManager, clock.NewRealTimeSource(), nil, logger),
 		AuthorizationConfig:           aConfig,
 	}
 	cluster := NewCadence(cadenceParams)
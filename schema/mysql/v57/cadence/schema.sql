@@ -257,11 +257,39 @@ CREATE TABLE queue (
   queue_type INT NOT NULL,
   message_id BIGINT NOT NULL,
   message_payload MEDIUMBLOB NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  enqueued_at DATETIME(6),
+  domain_id VARCHAR(255) NOT NULL DEFAULT '',
+  deleted_at DATETIME(6),
+  task_type INT,
   PRIMARY KEY(queue_type, message_id)
 );
 
+CREATE INDEX queue_by_domain ON queue(queue_type, domain_id, message_id);
+
+CREATE INDEX queue_by_deleted_at ON queue(queue_type, deleted_at);
+
+CREATE INDEX queue_by_task_type ON queue(queue_type, task_type);
+
+CREATE INDEX queue_by_enqueued_at ON queue(queue_type, enqueued_at);
+
 CREATE TABLE queue_metadata (
   queue_type INT NOT NULL,
   data MEDIUMBLOB NOT NULL,
+  merge_tokens MEDIUMBLOB,
+  version BIGINT NOT NULL DEFAULT 0,
   PRIMARY KEY(queue_type)
 );
+
+CREATE TABLE dlq_merge_history (
+  queue_type INT NOT NULL,
+  merged_at DATETIME(6) NOT NULL,
+  start_message_id BIGINT NOT NULL,
+  end_message_id BIGINT NOT NULL,
+  merged_count BIGINT NOT NULL,
+  failed_count BIGINT NOT NULL,
+  duration BIGINT NOT NULL, -- nanoseconds
+  triggered_by VARCHAR(255) NOT NULL,
+  trace_id VARCHAR(255) NOT NULL DEFAULT '',
+  PRIMARY KEY(queue_type, merged_at, start_message_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE queue ADD domain_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX queue_by_domain ON queue(queue_type, domain_id, message_id);
@@ -0,0 +1,3 @@
+ALTER TABLE queue ADD deleted_at DATETIME(6);
+
+CREATE INDEX queue_by_deleted_at ON queue(queue_type, deleted_at);
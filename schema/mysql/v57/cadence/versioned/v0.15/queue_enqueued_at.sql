@@ -0,0 +1 @@
+CREATE INDEX queue_by_enqueued_at ON queue(queue_type, enqueued_at);
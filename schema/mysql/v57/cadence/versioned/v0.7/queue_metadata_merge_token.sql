@@ -0,0 +1 @@
+ALTER TABLE queue_metadata ADD merge_tokens MEDIUMBLOB;
@@ -0,0 +1 @@
+ALTER TABLE dlq_merge_history ADD trace_id VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE queue ADD task_type INTEGER;
+CREATE INDEX queue_by_task_type ON queue(queue_type, task_type);
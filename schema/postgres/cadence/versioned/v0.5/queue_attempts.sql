@@ -0,0 +1 @@
+ALTER TABLE queue ADD attempts INTEGER NOT NULL DEFAULT 0;
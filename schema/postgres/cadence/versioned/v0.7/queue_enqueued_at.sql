@@ -0,0 +1 @@
+ALTER TABLE queue ADD enqueued_at TIMESTAMP;
@@ -0,0 +1,11 @@
+CREATE TABLE dlq_merge_history (
+  queue_type INTEGER NOT NULL,
+  merged_at TIMESTAMP NOT NULL,
+  start_message_id BIGINT NOT NULL,
+  end_message_id BIGINT NOT NULL,
+  merged_count BIGINT NOT NULL,
+  failed_count BIGINT NOT NULL,
+  duration BIGINT NOT NULL, -- nanoseconds
+  triggered_by VARCHAR(255) NOT NULL,
+  PRIMARY KEY(queue_type, merged_at, start_message_id)
+);
@@ -262,6 +262,70 @@ func (a *AccessControlledWorkflowAdminHandler) PurgeDLQMessages(ctx context.Cont
 	return a.AdminHandler.PurgeDLQMessages(ctx, request)
 }
 
+func (a *AccessControlledWorkflowAdminHandler) ForceUpdateDLQAckLevel(ctx context.Context, request *types.ForceUpdateDLQAckLevelRequest) error {
+	attr := &authorization.Attributes{
+		APIName:    "ForceUpdateDLQAckLevel",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return err
+	}
+	if !isAuthorized {
+		return errUnauthorized
+	}
+
+	return a.AdminHandler.ForceUpdateDLQAckLevel(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) PauseDLQProcessing(ctx context.Context, request *types.PauseDLQProcessingRequest) error {
+	attr := &authorization.Attributes{
+		APIName:    "PauseDLQProcessing",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return err
+	}
+	if !isAuthorized {
+		return errUnauthorized
+	}
+
+	return a.AdminHandler.PauseDLQProcessing(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) ResumeDLQProcessing(ctx context.Context, request *types.ResumeDLQProcessingRequest) error {
+	attr := &authorization.Attributes{
+		APIName:    "ResumeDLQProcessing",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return err
+	}
+	if !isAuthorized {
+		return errUnauthorized
+	}
+
+	return a.AdminHandler.ResumeDLQProcessing(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) ListDLQConsumers(ctx context.Context, request *types.ListDLQConsumersRequest) (*types.ListDLQConsumersResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "ListDLQConsumers",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.ListDLQConsumers(ctx, request)
+}
+
 func (a *AccessControlledWorkflowAdminHandler) ReadDLQMessages(ctx context.Context, request *types.ReadDLQMessagesRequest) (*types.ReadDLQMessagesResponse, error) {
 	attr := &authorization.Attributes{
 		APIName:    "ReadDLQMessages",
@@ -111,6 +111,26 @@ func (g adminGRPCHandler) PurgeDLQMessages(ctx context.Context, request *adminv1
 	return &adminv1.PurgeDLQMessagesResponse{}, proto.FromError(err)
 }
 
+func (g adminGRPCHandler) ForceUpdateDLQAckLevel(ctx context.Context, request *adminv1.ForceUpdateDLQAckLevelRequest) (*adminv1.ForceUpdateDLQAckLevelResponse, error) {
+	err := g.h.ForceUpdateDLQAckLevel(ctx, proto.ToAdminForceUpdateDLQAckLevelRequest(request))
+	return &adminv1.ForceUpdateDLQAckLevelResponse{}, proto.FromError(err)
+}
+
+func (g adminGRPCHandler) PauseDLQProcessing(ctx context.Context, request *adminv1.PauseDLQProcessingRequest) (*adminv1.PauseDLQProcessingResponse, error) {
+	err := g.h.PauseDLQProcessing(ctx, proto.ToAdminPauseDLQProcessingRequest(request))
+	return &adminv1.PauseDLQProcessingResponse{}, proto.FromError(err)
+}
+
+func (g adminGRPCHandler) ResumeDLQProcessing(ctx context.Context, request *adminv1.ResumeDLQProcessingRequest) (*adminv1.ResumeDLQProcessingResponse, error) {
+	err := g.h.ResumeDLQProcessing(ctx, proto.ToAdminResumeDLQProcessingRequest(request))
+	return &adminv1.ResumeDLQProcessingResponse{}, proto.FromError(err)
+}
+
+func (g adminGRPCHandler) ListDLQConsumers(ctx context.Context, request *adminv1.ListDLQConsumersRequest) (*adminv1.ListDLQConsumersResponse, error) {
+	response, err := g.h.ListDLQConsumers(ctx, proto.ToAdminListDLQConsumersRequest(request))
+	return proto.FromAdminListDLQConsumersResponse(response), proto.FromError(err)
+}
+
 func (g adminGRPCHandler) ReadDLQMessages(ctx context.Context, request *adminv1.ReadDLQMessagesRequest) (*adminv1.ReadDLQMessagesResponse, error) {
 	response, err := g.h.ReadDLQMessages(ctx, proto.ToAdminReadDLQMessagesRequest(request))
 	return proto.FromAdminReadDLQMessagesResponse(response), proto.FromError(err)
@@ -28,10 +28,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pborman/uuid"
+	"go.uber.org/yarpc"
 
 	"github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
@@ -61,6 +64,11 @@ const (
 
 var (
 	errInvalidFilters = &types.BadRequestError{Message: "Request Filters are invalid, unable to parse."}
+	errReasonNotSet   = &types.BadRequestError{Message: "Reason is not set on request."}
+
+	// the DLQ debug handlers are served by the pprof server on the default mux, which is shared by the process
+	// and only allows registering the handlers once
+	registerDLQDebugHandlers sync.Once
 )
 
 type (
@@ -82,6 +90,10 @@ type (
 		CountDLQMessages(context.Context, *types.CountDLQMessagesRequest) (*types.CountDLQMessagesResponse, error)
 		MergeDLQMessages(context.Context, *types.MergeDLQMessagesRequest) (*types.MergeDLQMessagesResponse, error)
 		PurgeDLQMessages(context.Context, *types.PurgeDLQMessagesRequest) error
+		ForceUpdateDLQAckLevel(context.Context, *types.ForceUpdateDLQAckLevelRequest) error
+		PauseDLQProcessing(context.Context, *types.PauseDLQProcessingRequest) error
+		ResumeDLQProcessing(context.Context, *types.ResumeDLQProcessingRequest) error
+		ListDLQConsumers(context.Context, *types.ListDLQConsumersRequest) (*types.ListDLQConsumersResponse, error)
 		ReadDLQMessages(context.Context, *types.ReadDLQMessagesRequest) (*types.ReadDLQMessagesResponse, error)
 		ReapplyEvents(context.Context, *types.ReapplyEventsRequest) error
 		RefreshWorkflowTasks(context.Context, *types.RefreshWorkflowTasksRequest) error
@@ -147,9 +159,110 @@ func NewAdminHandler(
 	config *Config,
 ) AdminHandler {
 
-	domainReplicationTaskExecutor := domain.NewReplicationTaskExecutor(
+	// the DLQ messages of the domains deleted by the domain replication are purged by the DLQ handler
+	var domainDLQHandler domain.DLQMessageHandler
+	domainManager := domain.NewDLQCleanupDomainManager(
 		resource.GetDomainManager(),
-		resource.GetTimeSource(),
+		func(ctx context.Context, domainID string) error {
+			return domainDLQHandler.PurgeDomain(ctx, domainID)
+		},
+		resource.GetLogger(),
+	)
+	domainReplicationTaskExecutors := domain.NewReplicationTaskExecutorRegistry(
+		domain.NewReplicationTaskExecutor(
+			domainManager,
+			resource.GetTimeSource(),
+			domain.NewWorkflowReplicationTaskExecutor(
+				resource.GetHistoryClient(),
+				resource.GetLogger(),
+			),
+			resource.GetLogger(),
+		),
+	)
+	domainReplicationTaskExecutors.RegisterExecutor(
+		types.ReplicationTaskTypeHistoryV2,
+		domain.NewHistoryReplicationTaskExecutor(
+			resource.GetHistoryClient(),
+			resource.GetLogger(),
+			domain.WithShardRouter(domain.NewShardRouter(
+				params.PersistenceConfig.NumHistoryShards,
+				resource.GetMembershipResolver(),
+			)),
+		),
+	)
+	domainReplicationTaskExecutors.RegisterExecutor(
+		types.ReplicationTaskTypeSyncActivity,
+		domain.NewSyncActivityReplicationTaskExecutor(resource.GetHistoryClient(), resource.GetLogger()),
+	)
+	domainReplicationTaskExecutors.RegisterExecutor(
+		types.ReplicationTaskTypeFailoverMarker,
+		domain.NewFailoverMarkerReplicationTaskExecutor(
+			resource.GetDomainManager(),
+			resource.GetHistoryClient(),
+			resource.GetLogger(),
+		),
+	)
+	domainReplicationTaskExecutors.RegisterExecutor(
+		types.ReplicationTaskTypeWorkflowReset,
+		domain.NewWorkflowResetReplicationTaskExecutor(resource.GetHistoryClient(), resource.GetLogger()),
+	)
+	for taskType, executor := range params.DomainReplicationTaskExecutors {
+		domainReplicationTaskExecutors.RegisterExecutor(taskType, executor)
+	}
+	for taskType, destinations := range params.DomainReplicationFanOutDestinations {
+		domain.RegisterFanOutExecutor(
+			domainReplicationTaskExecutors,
+			taskType,
+			resource.GetClusterMetadata().GetCurrentClusterName(),
+			destinations,
+		)
+	}
+	domainDLQHandler = domain.NewShardedDLQMessageHandler(
+		domain.NewDLQMessageHandler(
+			domainReplicationTaskExecutors,
+			resource.GetDomainReplicationQueue(),
+			resource.GetLogger(),
+			domain.WithMaxRetries(config.DomainDLQMaxRetryAttempts),
+			domain.WithSizeEmitInterval(config.DomainDLQSizeEmitInterval),
+			domain.WithRateLimit(config.DomainDLQMergeRPS, config.DomainDLQReplayRPS),
+			domain.WithDeduplication(config.DomainDLQDeduplicationWindowSize, config.DomainDLQDeduplicationFalsePositiveRate),
+			domain.WithMessageTTL(config.DomainDLQMessageTTL),
+			domain.WithPurgeBatchDelay(config.DomainDLQPurgeBatchDelay),
+			domain.WithPriorityMerge(config.DomainDLQPriorityMergeEnabled),
+			domain.WithGroupedMerge(config.DomainDLQGroupedMergeEnabled),
+			domain.WithExecutionJournal(config.DomainDLQExecutionJournalEnabled),
+			domain.WithVersionedAckLevel(config.DomainDLQVersionedAckLevelEnabled, config.DomainDLQAckLevelMaxConflictRetries),
+			domain.WithBatchSize(config.DomainDLQMaxReadPageSize),
+			domain.WithSoftDelete(config.DomainDLQSoftDeleteEnabled),
+			domain.WithLargeMessageThreshold(config.DomainDLQLargeMessageThresholdBytes),
+			domain.WithStalledAckThreshold(config.DomainDLQStalledAckThreshold),
+			domain.WithDepthAlertThreshold(config.DomainDLQDepthAlertThreshold),
+			domain.WithNotificationHooks(domain.NewWebhookNotificationHook(config.DomainDLQNotificationWebhookURL, nil)),
+			domain.WithMergeTimeout(config.DomainDLQMergeTimeout),
+			domain.WithReadMaxRetries(config.DomainDLQReadMaxRetries),
+			domain.WithCheckpointGranularity(config.DomainDLQMergeCheckpointGranularity),
+			domain.WithParallelism(config.DomainDLQMergeParallelism),
+			domain.WithInvalidTaskPolicy(config.DomainDLQInvalidMessagePolicy),
+			domain.WithBackpressure(config.DomainDLQMergeBackpressureMaxAttempts),
+			domain.WithArchival(
+				domain.NewDLQArchiver(
+					resource.GetArchiverProvider(),
+					resource.GetClusterMetadata(),
+					resource.GetLogger(),
+					resource.GetMetricsClient(),
+				),
+				config.DomainDLQArchivalEnabled,
+				config.DomainDLQArchivalURI,
+			),
+			domain.WithGrowthRateMonitor(config.DomainDLQGrowthRateSampleInterval, config.DomainDLQMaxGrowthRate),
+			domain.WithDistributedLocker(domain.NewDistributedLocker(resource.GetDomainReplicationQueue(), resource.GetTimeSource())),
+			domain.WithDestinationCluster(resource.GetClusterMetadata().GetCurrentClusterName()),
+			domain.WithTimeSource(resource.GetTimeSource()),
+			domain.WithMetricsClient(resource.GetMetricsClient()),
+		),
+		config.DomainDLQMergeShardCount,
+		config.DomainDLQMergeShardInterval,
+		resource.GetMembershipResolver(),
 		resource.GetLogger(),
 	)
 	return &adminHandlerImpl{
@@ -157,12 +270,7 @@ func NewAdminHandler(
 		numberOfHistoryShards: params.PersistenceConfig.NumHistoryShards,
 		params:                params,
 		config:                config,
-		domainDLQHandler: domain.NewDLQMessageHandler(
-			domainReplicationTaskExecutor,
-			resource.GetDomainReplicationQueue(),
-			resource.GetLogger(),
-			resource.GetMetricsClient(),
-		),
+		domainDLQHandler:      domainDLQHandler,
 		domainFailoverWatcher: domain.NewFailoverWatcher(
 			resource.GetDomainCache(),
 			resource.GetDomainManager(),
@@ -184,6 +292,12 @@ func NewAdminHandler(
 // Start starts the handler
 func (adh *adminHandlerImpl) Start() {
 	adh.domainDLQHandler.Start()
+	registerDLQDebugHandlers.Do(func() {
+		http.Handle(domain.DLQHealthPath, domain.NewDLQHealthHandler(adh.domainDLQHandler))
+		http.Handle(domain.DLQMergeHistoryPath, domain.NewDLQMergeHistoryHandler(adh.domainDLQHandler))
+		http.Handle(domain.DLQMessagesPath, domain.NewDLQMessagesHandler(adh.domainDLQHandler))
+		http.Handle(domain.DLQConflictPoliciesPath, domain.NewDLQConflictPoliciesHandler(adh.domainDLQHandler))
+	})
 
 	if adh.config.EnableGracefulFailover() {
 		adh.domainFailoverWatcher.Start()
@@ -953,6 +1067,19 @@ func (adh *adminHandlerImpl) GetReplicationMessages(
 	if err != nil {
 		return nil, adh.error(err, scope)
 	}
+
+	// the DLQ ack level only helps to correlate replication lag, failing to read it must not fail replication
+	resp.DLQAckLevel = common.EmptyMessageID
+	if adh.GetDomainReplicationQueue() != nil {
+		dlqAckLevel, err := adh.GetDomainReplicationQueue().GetDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup)
+		if err != nil {
+			adh.GetLogger().Warn("Failed to get domain replication queue DLQ ack level.",
+				tag.ClusterName(request.GetClusterName()),
+				tag.Error(err))
+		} else {
+			resp.DLQAckLevel = dlqAckLevel
+		}
+	}
 	return resp, nil
 }
 
@@ -990,6 +1117,7 @@ func (adh *adminHandlerImpl) GetDomainReplicationMessages(
 
 	replicationTasks, lastMessageID, err := adh.GetDomainReplicationQueue().GetReplicationMessages(
 		ctx,
+		request.GetClusterName(),
 		lastMessageID,
 		getDomainReplicationMessageBatchSize,
 	)
@@ -1120,9 +1248,13 @@ func (adh *adminHandlerImpl) ReadDLQMessages(
 				var err error
 				tasks, token, err = adh.domainDLQHandler.Read(
 					ctx,
+					domain.AllTaskTypes,
 					request.GetInclusiveEndMessageID(),
 					int(request.GetMaximumPageSize()),
 					request.GetNextPageToken())
+				if e, ok := err.(*domain.ErrPageSizeExceeded); ok {
+					return &types.BadRequestError{Message: e.Error()}
+				}
 				return err
 			}
 		}
@@ -1174,6 +1306,7 @@ func (adh *adminHandlerImpl) PurgeDLQMessages(
 			default:
 				return adh.domainDLQHandler.Purge(
 					ctx,
+					domain.AllTaskTypes,
 					request.GetInclusiveEndMessageID(),
 				)
 			}
@@ -1189,6 +1322,162 @@ func (adh *adminHandlerImpl) PurgeDLQMessages(
 	return nil
 }
 
+// ForceUpdateDLQAckLevel moves the ack level of DLQ forward without merging the messages before it,
+// so messages which can never be merged can be skipped
+func (adh *adminHandlerImpl) ForceUpdateDLQAckLevel(
+	ctx context.Context,
+	request *types.ForceUpdateDLQAckLevelRequest,
+) (err error) {
+
+	defer log.CapturePanic(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminForceUpdateDLQAckLevelScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return adh.error(errRequestNotSet, scope)
+	}
+
+	if request.Type == nil {
+		return adh.error(errEmptyQueueType, scope)
+	}
+
+	if request.GetReason() == "" {
+		return adh.error(errReasonNotSet, scope)
+	}
+
+	if request.GetType() != types.DLQTypeDomain {
+		return &types.BadRequestError{Message: "The DLQ type is not supported."}
+	}
+
+	queue := adh.GetDomainReplicationQueue()
+	ackLevel, err := queue.GetDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup)
+	if err != nil {
+		return adh.error(err, scope)
+	}
+	// the persistence silently ignores an ack level behind the current one
+	if request.GetAckLevel() < ackLevel {
+		return adh.error(&types.BadRequestError{
+			Message: fmt.Sprintf("The ack level can only be moved forward, the current ack level is %v.", ackLevel),
+		}, scope)
+	}
+	if err := queue.UpdateDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup, request.GetAckLevel()); err != nil {
+		return adh.error(err, scope)
+	}
+	adh.GetLogger().Warn("Force updated domain DLQ ack level.",
+		tag.DLQPreviousAckLevel(ackLevel),
+		tag.DLQAckLevel(request.GetAckLevel()),
+		tag.DLQAckLevelOverrideReason(request.GetReason()),
+	)
+	return nil
+}
+
+func (adh *adminHandlerImpl) PauseDLQProcessing(
+	ctx context.Context,
+	request *types.PauseDLQProcessingRequest,
+) (err error) {
+
+	defer log.CapturePanic(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminPauseDLQProcessingScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return adh.error(errRequestNotSet, scope)
+	}
+
+	if request.Type == nil {
+		return adh.error(errEmptyQueueType, scope)
+	}
+
+	if request.GetType() != types.DLQTypeDomain {
+		return &types.BadRequestError{Message: "The DLQ type is not supported."}
+	}
+
+	if err := adh.domainDLQHandler.Pause(ctx); err != nil {
+		return adh.error(err, scope)
+	}
+	return nil
+}
+
+func (adh *adminHandlerImpl) ResumeDLQProcessing(
+	ctx context.Context,
+	request *types.ResumeDLQProcessingRequest,
+) (err error) {
+
+	defer log.CapturePanic(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminResumeDLQProcessingScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return adh.error(errRequestNotSet, scope)
+	}
+
+	if request.Type == nil {
+		return adh.error(errEmptyQueueType, scope)
+	}
+
+	if request.GetType() != types.DLQTypeDomain {
+		return &types.BadRequestError{Message: "The DLQ type is not supported."}
+	}
+
+	if err := adh.domainDLQHandler.Resume(ctx); err != nil {
+		return adh.error(err, scope)
+	}
+	return nil
+}
+
+// ListDLQConsumers returns the consumer groups of the DLQ of the current cluster with their ack levels
+func (adh *adminHandlerImpl) ListDLQConsumers(
+	ctx context.Context,
+	request *types.ListDLQConsumersRequest,
+) (_ *types.ListDLQConsumersResponse, err error) {
+
+	defer log.CapturePanic(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminListDLQConsumersScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+
+	if request.Type == nil {
+		return nil, adh.error(errEmptyQueueType, scope)
+	}
+
+	if request.GetType() != types.DLQTypeDomain {
+		return nil, &types.BadRequestError{Message: "The DLQ type is not supported."}
+	}
+
+	currentClusterName := adh.GetClusterMetadata().GetCurrentClusterName()
+	if request.GetClusterName() != "" && request.GetClusterName() != currentClusterName {
+		return nil, &types.BadRequestError{Message: fmt.Sprintf(
+			"The DLQ consumers of cluster %v are only listed by that cluster, this is cluster %v.",
+			request.GetClusterName(),
+			currentClusterName,
+		)}
+	}
+
+	ackLevels, err := adh.GetDomainReplicationQueue().GetDLQConsumerAckLevels(ctx)
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	consumers := make([]*types.DLQConsumer, 0, len(ackLevels))
+	for _, ackLevel := range ackLevels {
+		consumer := &types.DLQConsumer{
+			ConsumerGroup: ackLevel.ConsumerGroup,
+			AckLevel:      ackLevel.AckLevel,
+		}
+		if ackLevel.TaskType != domain.AllTaskTypes {
+			consumer.TaskType = ackLevel.TaskType.String()
+		}
+		if !ackLevel.UpdatedAt.IsZero() {
+			consumer.UpdatedTime = common.Int64Ptr(ackLevel.UpdatedAt.UnixNano())
+		}
+		consumers = append(consumers, consumer)
+	}
+	return &types.ListDLQConsumersResponse{Consumers: consumers}, nil
+}
+
 func (adh *adminHandlerImpl) CountDLQMessages(
 	ctx context.Context,
 	request *types.CountDLQMessagesRequest,
@@ -1242,15 +1531,19 @@ func (adh *adminHandlerImpl) MergeDLQMessages(
 	case types.DLQTypeReplication:
 		return adh.GetHistoryClient().MergeDLQMessages(ctx, request)
 	case types.DLQTypeDomain:
-
+		// attribute the merge to the calling service in the DLQ merge history
+		ctx := domain.ContextWithDLQMergeTrigger(ctx, yarpc.CallFromContext(ctx).Caller())
 		op = func() error {
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
 			default:
 				var err error
+				// the merge request does not carry a request ID, so the merge is not fenced
 				token, err = adh.domainDLQHandler.Merge(
 					ctx,
+					domain.AllTaskTypes,
+					"",
 					request.GetInclusiveEndMessageID(),
 					int(request.GetMaximumPageSize()),
 					request.GetNextPageToken(),
@@ -1545,6 +1838,13 @@ func (adh *adminHandlerImpl) startRequestProfile(ctx context.Context, scope int)
 }
 
 func (adh *adminHandlerImpl) error(err error, scope metrics.Scope) error {
+	// the failure modes of the domain DLQ are reported with the error which caused them
+	if dlqErr, ok := err.(*domain.DLQError); ok {
+		switch dlqErr.Cause.(type) {
+		case *types.BadRequestError, *types.ServiceBusyError, *types.EntityNotExistsError:
+			return adh.error(dlqErr.Cause, scope)
+		}
+	}
 	switch err.(type) {
 	case *types.InternalServiceError:
 		adh.GetLogger().Error("Internal service error", tag.Error(err))
@@ -191,6 +191,20 @@ func (mr *MockAdminHandlerMockRecorder) DescribeWorkflowExecution(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkflowExecution", reflect.TypeOf((*MockAdminHandler)(nil).DescribeWorkflowExecution), arg0, arg1)
 }
 
+// ForceUpdateDLQAckLevel mocks base method.
+func (m *MockAdminHandler) ForceUpdateDLQAckLevel(arg0 context.Context, arg1 *types.ForceUpdateDLQAckLevelRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceUpdateDLQAckLevel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceUpdateDLQAckLevel indicates an expected call of ForceUpdateDLQAckLevel.
+func (mr *MockAdminHandlerMockRecorder) ForceUpdateDLQAckLevel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceUpdateDLQAckLevel", reflect.TypeOf((*MockAdminHandler)(nil).ForceUpdateDLQAckLevel), arg0, arg1)
+}
+
 // GetCrossClusterTasks mocks base method.
 func (m *MockAdminHandler) GetCrossClusterTasks(arg0 context.Context, arg1 *types.GetCrossClusterTasksRequest) (*types.GetCrossClusterTasksResponse, error) {
 	m.ctrl.T.Helper()
@@ -281,6 +295,21 @@ func (mr *MockAdminHandlerMockRecorder) GetWorkflowExecutionRawHistoryV2(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecutionRawHistoryV2", reflect.TypeOf((*MockAdminHandler)(nil).GetWorkflowExecutionRawHistoryV2), arg0, arg1)
 }
 
+// ListDLQConsumers mocks base method.
+func (m *MockAdminHandler) ListDLQConsumers(arg0 context.Context, arg1 *types.ListDLQConsumersRequest) (*types.ListDLQConsumersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDLQConsumers", arg0, arg1)
+	ret0, _ := ret[0].(*types.ListDLQConsumersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDLQConsumers indicates an expected call of ListDLQConsumers.
+func (mr *MockAdminHandlerMockRecorder) ListDLQConsumers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDLQConsumers", reflect.TypeOf((*MockAdminHandler)(nil).ListDLQConsumers), arg0, arg1)
+}
+
 // ListDynamicConfig mocks base method.
 func (m *MockAdminHandler) ListDynamicConfig(arg0 context.Context, arg1 *types.ListDynamicConfigRequest) (*types.ListDynamicConfigResponse, error) {
 	m.ctrl.T.Helper()
@@ -326,6 +355,20 @@ func (mr *MockAdminHandlerMockRecorder) MergeDLQMessages(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDLQMessages", reflect.TypeOf((*MockAdminHandler)(nil).MergeDLQMessages), arg0, arg1)
 }
 
+// PauseDLQProcessing mocks base method.
+func (m *MockAdminHandler) PauseDLQProcessing(arg0 context.Context, arg1 *types.PauseDLQProcessingRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseDLQProcessing", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseDLQProcessing indicates an expected call of PauseDLQProcessing.
+func (mr *MockAdminHandlerMockRecorder) PauseDLQProcessing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseDLQProcessing", reflect.TypeOf((*MockAdminHandler)(nil).PauseDLQProcessing), arg0, arg1)
+}
+
 // PurgeDLQMessages mocks base method.
 func (m *MockAdminHandler) PurgeDLQMessages(arg0 context.Context, arg1 *types.PurgeDLQMessagesRequest) error {
 	m.ctrl.T.Helper()
@@ -454,6 +497,20 @@ func (mr *MockAdminHandlerMockRecorder) RestoreDynamicConfig(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDynamicConfig", reflect.TypeOf((*MockAdminHandler)(nil).RestoreDynamicConfig), arg0, arg1)
 }
 
+// ResumeDLQProcessing mocks base method.
+func (m *MockAdminHandler) ResumeDLQProcessing(arg0 context.Context, arg1 *types.ResumeDLQProcessingRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeDLQProcessing", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeDLQProcessing indicates an expected call of ResumeDLQProcessing.
+func (mr *MockAdminHandlerMockRecorder) ResumeDLQProcessing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeDLQProcessing", reflect.TypeOf((*MockAdminHandler)(nil).ResumeDLQProcessing), arg0, arg1)
+}
+
 // Start mocks base method.
 func (m *MockAdminHandler) Start() {
 	m.ctrl.T.Helper()
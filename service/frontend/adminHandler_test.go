@@ -25,7 +25,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
@@ -37,8 +40,10 @@ import (
 	"github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/definition"
+	"github.com/uber/cadence/common/domain"
 	"github.com/uber/cadence/common/dynamicconfig"
 	esmock "github.com/uber/cadence/common/elasticsearch/mocks"
 	"github.com/uber/cadence/common/membership"
@@ -46,6 +51,7 @@ import (
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/resource"
+	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -88,6 +94,9 @@ func (s *adminHandlerSuite) SetupTest() {
 	s.mockHistoryV2Mgr = s.mockResource.HistoryMgr
 	s.frontendClient = s.mockResource.FrontendClient
 	s.mockResolver = s.mockResource.MembershipResolver
+	s.mockResolver.EXPECT().Subscribe(service.Frontend, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	s.mockResolver.EXPECT().Unsubscribe(service.Frontend, gomock.Any()).Return(nil).AnyTimes()
+	s.mockResource.ClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
 
 	params := &resource.Params{
 		PersistenceConfig: config.Persistence{
@@ -95,10 +104,42 @@ func (s *adminHandlerSuite) SetupTest() {
 		},
 	}
 	config := &Config{
-		EnableAdminProtection:  dynamicconfig.GetBoolPropertyFn(false),
-		EnableGracefulFailover: dynamicconfig.GetBoolPropertyFn(false),
+		EnableAdminProtection:                   dynamicconfig.GetBoolPropertyFn(false),
+		EnableGracefulFailover:                  dynamicconfig.GetBoolPropertyFn(false),
+		DomainDLQMaxRetryAttempts:               dynamicconfig.GetIntPropertyFn(5),
+		DomainDLQSizeEmitInterval:               dynamicconfig.GetDurationPropertyFn(5 * time.Minute),
+		DomainDLQMergeRPS:                       dynamicconfig.GetIntPropertyFn(10),
+		DomainDLQReplayRPS:                      dynamicconfig.GetIntPropertyFn(100),
+		DomainDLQDeduplicationWindowSize:        dynamicconfig.GetIntPropertyFn(0),
+		DomainDLQDeduplicationFalsePositiveRate: dynamicconfig.GetFloatPropertyFn(0.0001),
+		DomainDLQMergeShardCount:                dynamicconfig.GetIntPropertyFn(0),
+		DomainDLQMergeShardInterval:             dynamicconfig.GetDurationPropertyFn(time.Minute),
+		DomainDLQMessageTTL:                     dynamicconfig.GetDurationPropertyFn(30 * 24 * time.Hour),
+		DomainDLQPurgeBatchDelay:                dynamicconfig.GetDurationPropertyFn(100 * time.Millisecond),
+		DomainDLQPriorityMergeEnabled:           dynamicconfig.GetBoolPropertyFn(false),
+		DomainDLQGroupedMergeEnabled:            dynamicconfig.GetBoolPropertyFn(false),
+		DomainDLQExecutionJournalEnabled:        dynamicconfig.GetBoolPropertyFn(false),
+		DomainDLQVersionedAckLevelEnabled:       dynamicconfig.GetBoolPropertyFn(false),
+		DomainDLQAckLevelMaxConflictRetries:     dynamicconfig.GetIntPropertyFn(3),
+		DomainDLQArchivalEnabled:                dynamicconfig.GetBoolPropertyFn(false),
+		DomainDLQArchivalURI:                    dynamicconfig.GetStringPropertyFn(""),
+		DomainDLQGrowthRateSampleInterval:       dynamicconfig.GetDurationPropertyFn(time.Minute),
+		DomainDLQMaxGrowthRate:                  dynamicconfig.GetFloatPropertyFn(0),
+		DomainDLQMaxReadPageSize:                dynamicconfig.GetIntPropertyFn(1000),
+		DomainDLQSoftDeleteEnabled:              dynamicconfig.GetBoolPropertyFn(false),
+		DomainDLQLargeMessageThresholdBytes:     dynamicconfig.GetIntPropertyFn(1024 * 1024),
+		DomainDLQStalledAckThreshold:            dynamicconfig.GetDurationPropertyFn(time.Hour),
+		DomainDLQDepthAlertThreshold:            dynamicconfig.GetIntPropertyFn(0),
+		DomainDLQNotificationWebhookURL:         dynamicconfig.GetStringPropertyFn(""),
+		DomainDLQMergeTimeout:                   dynamicconfig.GetDurationPropertyFn(5 * time.Minute),
+		DomainDLQReadMaxRetries:                 dynamicconfig.GetIntPropertyFn(3),
+		DomainDLQMergeCheckpointGranularity:     dynamicconfig.GetStringPropertyFn(string(domain.CheckpointGranularityPerPage)),
+		DomainDLQMergeParallelism:               dynamicconfig.GetIntPropertyFn(1),
+		DomainDLQInvalidMessagePolicy:           dynamicconfig.GetStringPropertyFn(string(domain.InvalidTaskPolicyFail)),
+		DomainDLQMergeBackpressureMaxAttempts:   dynamicconfig.GetIntPropertyFn(10),
 	}
 	s.handler = NewAdminHandler(s.mockResource, params, config).(*adminHandlerImpl)
+	s.mockResource.DomainReplicationQueue.EXPECT().GetDLQProcessingPaused(gomock.Any(), domain.DefaultConsumerGroup).Return(false, nil).Times(1)
 	s.handler.Start()
 }
 
@@ -108,6 +149,19 @@ func (s *adminHandlerSuite) TearDownTest() {
 	s.handler.Stop()
 }
 
+func (s *adminHandlerSuite) TestStart_RegisterDLQDebugHandlers() {
+	for _, path := range []string{
+		domain.DLQHealthPath,
+		domain.DLQMergeHistoryPath,
+		domain.DLQMessagesPath,
+		domain.DLQConflictPoliciesPath,
+	} {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		_, pattern := http.DefaultServeMux.Handler(request)
+		s.Equal(path, pattern)
+	}
+}
+
 func (s *adminHandlerSuite) TestMaintainCorruptWorkflow_NormalWorkflow() {
 	s.testMaintainCorruptWorkflow(nil, nil, false)
 }
@@ -378,6 +432,199 @@ func (s *adminHandlerSuite) Test_GetWorkflowExecutionRawHistoryV2_SameStartIDAnd
 	s.NoError(err)
 }
 
+func (s *adminHandlerSuite) Test_GetReplicationMessages_DLQAckLevel() {
+	ctx := context.Background()
+	request := &types.GetReplicationMessagesRequest{ClusterName: "active"}
+	s.mockHistoryClient.EXPECT().GetReplicationMessages(ctx, request).Return(&types.GetReplicationMessagesResponse{
+		MessagesByShard: map[int32]*types.ReplicationMessages{0: {LastRetrievedMessageID: 10}},
+	}, nil)
+	s.mockResource.DomainReplicationQueue.EXPECT().GetDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup).Return(int64(42), nil)
+
+	resp, err := s.handler.GetReplicationMessages(ctx, request)
+	s.NoError(err)
+	s.Equal(int64(42), resp.DLQAckLevel)
+	s.Equal(int64(10), resp.MessagesByShard[0].LastRetrievedMessageID)
+}
+
+func (s *adminHandlerSuite) Test_GetReplicationMessages_DLQAckLevelUnavailable() {
+	ctx := context.Background()
+	request := &types.GetReplicationMessagesRequest{ClusterName: "active"}
+	s.mockHistoryClient.EXPECT().GetReplicationMessages(ctx, request).Return(&types.GetReplicationMessagesResponse{}, nil)
+	s.mockResource.DomainReplicationQueue.EXPECT().GetDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup).Return(int64(0), errors.New("some random error"))
+
+	resp, err := s.handler.GetReplicationMessages(ctx, request)
+	s.NoError(err)
+	s.Equal(int64(common.EmptyMessageID), resp.DLQAckLevel)
+}
+
+func (s *adminHandlerSuite) Test_ForceUpdateDLQAckLevel() {
+	ctx := context.Background()
+	s.mockResource.DomainReplicationQueue.EXPECT().GetDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup).Return(int64(10), nil)
+	s.mockResource.DomainReplicationQueue.EXPECT().UpdateDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup, int64(42)).Return(nil)
+
+	err := s.handler.ForceUpdateDLQAckLevel(ctx, &types.ForceUpdateDLQAckLevelRequest{
+		Type:     types.DLQTypeDomain.Ptr(),
+		AckLevel: 42,
+		Reason:   "corrupted messages",
+	})
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) Test_ForceUpdateDLQAckLevel_InvalidRequest() {
+	ctx := context.Background()
+	testCases := map[string]*types.ForceUpdateDLQAckLevelRequest{
+		"nil request":       nil,
+		"missing type":      {AckLevel: 42, Reason: "corrupted messages"},
+		"missing reason":    {Type: types.DLQTypeDomain.Ptr(), AckLevel: 42},
+		"replication queue": {Type: types.DLQTypeReplication.Ptr(), AckLevel: 42, Reason: "corrupted messages"},
+	}
+	for name, request := range testCases {
+		s.Run(name, func() {
+			err := s.handler.ForceUpdateDLQAckLevel(ctx, request)
+			s.IsType(&types.BadRequestError{}, err)
+		})
+	}
+}
+
+func (s *adminHandlerSuite) Test_ForceUpdateDLQAckLevel_Backwards() {
+	ctx := context.Background()
+	s.mockResource.DomainReplicationQueue.EXPECT().GetDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup).Return(int64(50), nil)
+
+	err := s.handler.ForceUpdateDLQAckLevel(ctx, &types.ForceUpdateDLQAckLevelRequest{
+		Type:     types.DLQTypeDomain.Ptr(),
+		AckLevel: 42,
+		Reason:   "corrupted messages",
+	})
+	s.IsType(&types.BadRequestError{}, err)
+}
+
+func (s *adminHandlerSuite) Test_ForceUpdateDLQAckLevel_UpdateFailed() {
+	ctx := context.Background()
+	s.mockResource.DomainReplicationQueue.EXPECT().GetDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup).Return(int64(10), nil)
+	s.mockResource.DomainReplicationQueue.EXPECT().UpdateDLQAckLevel(ctx, domain.AllTaskTypes, domain.DefaultConsumerGroup, int64(42)).
+		Return(&types.InternalServiceError{Message: "update failed"})
+
+	err := s.handler.ForceUpdateDLQAckLevel(ctx, &types.ForceUpdateDLQAckLevelRequest{
+		Type:     types.DLQTypeDomain.Ptr(),
+		AckLevel: 42,
+		Reason:   "corrupted messages",
+	})
+	s.IsType(&types.InternalServiceError{}, err)
+}
+
+func (s *adminHandlerSuite) Test_PauseDLQProcessing() {
+	ctx := context.Background()
+	s.mockResource.DomainReplicationQueue.EXPECT().UpdateDLQProcessingPaused(ctx, domain.DefaultConsumerGroup, true).Return(nil)
+
+	err := s.handler.PauseDLQProcessing(ctx, &types.PauseDLQProcessingRequest{Type: types.DLQTypeDomain.Ptr()})
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) Test_PauseDLQProcessing_InvalidRequest() {
+	ctx := context.Background()
+	testCases := map[string]*types.PauseDLQProcessingRequest{
+		"nil request":       nil,
+		"missing type":      {},
+		"replication queue": {Type: types.DLQTypeReplication.Ptr()},
+	}
+	for name, request := range testCases {
+		s.Run(name, func() {
+			err := s.handler.PauseDLQProcessing(ctx, request)
+			s.IsType(&types.BadRequestError{}, err)
+		})
+	}
+}
+
+func (s *adminHandlerSuite) Test_PauseDLQProcessing_UpdateFailed() {
+	ctx := context.Background()
+	s.mockResource.DomainReplicationQueue.EXPECT().UpdateDLQProcessingPaused(ctx, domain.DefaultConsumerGroup, true).
+		Return(&types.InternalServiceError{Message: "update failed"})
+
+	err := s.handler.PauseDLQProcessing(ctx, &types.PauseDLQProcessingRequest{Type: types.DLQTypeDomain.Ptr()})
+	s.IsType(&types.InternalServiceError{}, err)
+}
+
+func (s *adminHandlerSuite) Test_ResumeDLQProcessing() {
+	ctx := context.Background()
+	s.mockResource.DomainReplicationQueue.EXPECT().UpdateDLQProcessingPaused(ctx, domain.DefaultConsumerGroup, false).Return(nil)
+
+	err := s.handler.ResumeDLQProcessing(ctx, &types.ResumeDLQProcessingRequest{Type: types.DLQTypeDomain.Ptr()})
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) Test_ResumeDLQProcessing_InvalidRequest() {
+	ctx := context.Background()
+	testCases := map[string]*types.ResumeDLQProcessingRequest{
+		"nil request":       nil,
+		"missing type":      {},
+		"replication queue": {Type: types.DLQTypeReplication.Ptr()},
+	}
+	for name, request := range testCases {
+		s.Run(name, func() {
+			err := s.handler.ResumeDLQProcessing(ctx, request)
+			s.IsType(&types.BadRequestError{}, err)
+		})
+	}
+}
+
+func (s *adminHandlerSuite) Test_ListDLQConsumers() {
+	ctx := context.Background()
+	updatedAt := time.Unix(1700000000, 0)
+	s.mockResource.DomainReplicationQueue.EXPECT().GetDLQConsumerAckLevels(ctx).Return([]*domain.DLQConsumerAckLevel{
+		{ConsumerGroup: "replay", TaskType: types.ReplicationTaskTypeHistoryV2, AckLevel: 20},
+		{ConsumerGroup: domain.DefaultConsumerGroup, TaskType: domain.AllTaskTypes, AckLevel: 10, UpdatedAt: updatedAt},
+	}, nil)
+
+	resp, err := s.handler.ListDLQConsumers(ctx, &types.ListDLQConsumersRequest{
+		Type:        types.DLQTypeDomain.Ptr(),
+		ClusterName: cluster.TestCurrentClusterName,
+	})
+	s.NoError(err)
+	s.Equal(&types.ListDLQConsumersResponse{
+		Consumers: []*types.DLQConsumer{
+			{ConsumerGroup: "replay", TaskType: "HistoryV2", AckLevel: 20},
+			{ConsumerGroup: domain.DefaultConsumerGroup, AckLevel: 10, UpdatedTime: common.Int64Ptr(updatedAt.UnixNano())},
+		},
+	}, resp)
+}
+
+func (s *adminHandlerSuite) Test_ListDLQConsumers_InvalidRequest() {
+	ctx := context.Background()
+	testCases := map[string]*types.ListDLQConsumersRequest{
+		"nil request":       nil,
+		"missing type":      {},
+		"replication queue": {Type: types.DLQTypeReplication.Ptr()},
+		"other cluster":     {Type: types.DLQTypeDomain.Ptr(), ClusterName: cluster.TestAlternativeClusterName},
+	}
+	for name, request := range testCases {
+		s.Run(name, func() {
+			_, err := s.handler.ListDLQConsumers(ctx, request)
+			s.IsType(&types.BadRequestError{}, err)
+		})
+	}
+}
+
+func (s *adminHandlerSuite) Test_ListDLQConsumers_ReadFailed() {
+	ctx := context.Background()
+	s.mockResource.DomainReplicationQueue.EXPECT().GetDLQConsumerAckLevels(ctx).
+		Return(nil, &types.InternalServiceError{Message: "read failed"})
+
+	_, err := s.handler.ListDLQConsumers(ctx, &types.ListDLQConsumersRequest{Type: types.DLQTypeDomain.Ptr()})
+	s.IsType(&types.InternalServiceError{}, err)
+}
+
+func (s *adminHandlerSuite) Test_Error_DLQError() {
+	scope := metrics.NoopScope(metrics.Frontend)
+
+	// the error which caused the DLQ failure is returned if it is a service error
+	serviceBusyErr := &types.ServiceBusyError{Message: "busy"}
+	err := s.handler.error(&domain.DLQError{Kind: domain.ErrDLQQueueFull, Cause: serviceBusyErr}, scope)
+	s.Equal(serviceBusyErr, err)
+
+	err = s.handler.error(&domain.DLQError{Kind: domain.ErrDLQDeleteFailed, Cause: errors.New("some random error")}, scope)
+	s.Equal(&types.InternalServiceError{Message: "failed to delete domain DLQ messages: some random error"}, err)
+}
+
 func (s *adminHandlerSuite) Test_SetRequestDefaultValueAndGetTargetVersionHistory_DefinedStartAndEnd() {
 	inputStartEventID := int64(1)
 	inputStartVersion := int64(10)
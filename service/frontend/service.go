@@ -90,6 +90,37 @@ type Config struct {
 	EnableGracefulFailover                      dynamicconfig.BoolPropertyFn
 	DomainFailoverRefreshInterval               dynamicconfig.DurationPropertyFn
 	DomainFailoverRefreshTimerJitterCoefficient dynamicconfig.FloatPropertyFn
+	DomainDLQMaxRetryAttempts                   dynamicconfig.IntPropertyFn
+	DomainDLQSizeEmitInterval                   dynamicconfig.DurationPropertyFn
+	DomainDLQMergeRPS                           dynamicconfig.IntPropertyFn
+	DomainDLQReplayRPS                          dynamicconfig.IntPropertyFn
+	DomainDLQDeduplicationWindowSize            dynamicconfig.IntPropertyFn
+	DomainDLQDeduplicationFalsePositiveRate     dynamicconfig.FloatPropertyFn
+	DomainDLQMergeShardCount                    dynamicconfig.IntPropertyFn
+	DomainDLQMergeShardInterval                 dynamicconfig.DurationPropertyFn
+	DomainDLQMessageTTL                         dynamicconfig.DurationPropertyFn
+	DomainDLQPurgeBatchDelay                    dynamicconfig.DurationPropertyFn
+	DomainDLQPriorityMergeEnabled               dynamicconfig.BoolPropertyFn
+	DomainDLQMaxReadPageSize                    dynamicconfig.IntPropertyFn
+	DomainDLQSoftDeleteEnabled                  dynamicconfig.BoolPropertyFn
+	DomainDLQLargeMessageThresholdBytes         dynamicconfig.IntPropertyFn
+	DomainDLQStalledAckThreshold                dynamicconfig.DurationPropertyFn
+	DomainDLQDepthAlertThreshold                dynamicconfig.IntPropertyFn
+	DomainDLQNotificationWebhookURL             dynamicconfig.StringPropertyFn
+	DomainDLQMergeTimeout                       dynamicconfig.DurationPropertyFn
+	DomainDLQReadMaxRetries                     dynamicconfig.IntPropertyFn
+	DomainDLQMergeCheckpointGranularity         dynamicconfig.StringPropertyFn
+	DomainDLQMergeParallelism                   dynamicconfig.IntPropertyFn
+	DomainDLQInvalidMessagePolicy               dynamicconfig.StringPropertyFn
+	DomainDLQMergeBackpressureMaxAttempts       dynamicconfig.IntPropertyFn
+	DomainDLQGroupedMergeEnabled                dynamicconfig.BoolPropertyFn
+	DomainDLQExecutionJournalEnabled            dynamicconfig.BoolPropertyFn
+	DomainDLQVersionedAckLevelEnabled           dynamicconfig.BoolPropertyFn
+	DomainDLQAckLevelMaxConflictRetries         dynamicconfig.IntPropertyFn
+	DomainDLQArchivalEnabled                    dynamicconfig.BoolPropertyFn
+	DomainDLQArchivalURI                        dynamicconfig.StringPropertyFn
+	DomainDLQGrowthRateSampleInterval           dynamicconfig.DurationPropertyFn
+	DomainDLQMaxGrowthRate                      dynamicconfig.FloatPropertyFn
 
 	// ValidSearchAttributes is legal indexed keys that can be used in list APIs
 	ValidSearchAttributes             dynamicconfig.MapPropertyFn
@@ -151,6 +182,37 @@ func NewConfig(dc *dynamicconfig.Collection, numHistoryShards int, enableReadFro
 		EnableGracefulFailover:                      dc.GetBoolProperty(dynamicconfig.EnableGracefulFailover, true),
 		DomainFailoverRefreshInterval:               dc.GetDurationProperty(dynamicconfig.DomainFailoverRefreshInterval, 10*time.Second),
 		DomainFailoverRefreshTimerJitterCoefficient: dc.GetFloat64Property(dynamicconfig.DomainFailoverRefreshTimerJitterCoefficient, 0.1),
+		DomainDLQMaxRetryAttempts:                   dc.GetIntProperty(dynamicconfig.DomainDLQMaxRetryAttempts, 5),
+		DomainDLQSizeEmitInterval:                   dc.GetDurationProperty(dynamicconfig.DomainDLQSizeEmitInterval, 5*time.Minute),
+		DomainDLQMergeRPS:                           dc.GetIntProperty(dynamicconfig.DomainDLQMergeRPS, 10),
+		DomainDLQReplayRPS:                          dc.GetIntProperty(dynamicconfig.DomainDLQReplayRPS, 100),
+		DomainDLQDeduplicationWindowSize:            dc.GetIntProperty(dynamicconfig.DomainDLQDeduplicationWindowSize, 0),
+		DomainDLQDeduplicationFalsePositiveRate:     dc.GetFloat64Property(dynamicconfig.DomainDLQDeduplicationFalsePositiveRate, 0.0001),
+		DomainDLQMergeShardCount:                    dc.GetIntProperty(dynamicconfig.DomainDLQMergeShardCount, 0),
+		DomainDLQMergeShardInterval:                 dc.GetDurationProperty(dynamicconfig.DomainDLQMergeShardInterval, time.Minute),
+		DomainDLQMessageTTL:                         dc.GetDurationProperty(dynamicconfig.DomainDLQMessageTTL, 30*24*time.Hour),
+		DomainDLQPurgeBatchDelay:                    dc.GetDurationProperty(dynamicconfig.DomainDLQPurgeBatchDelay, 100*time.Millisecond),
+		DomainDLQPriorityMergeEnabled:               dc.GetBoolProperty(dynamicconfig.DomainDLQPriorityMergeEnabled, false),
+		DomainDLQMaxReadPageSize:                    dc.GetIntProperty(dynamicconfig.DomainDLQMaxReadPageSize, 1000),
+		DomainDLQSoftDeleteEnabled:                  dc.GetBoolProperty(dynamicconfig.DomainDLQSoftDeleteEnabled, false),
+		DomainDLQLargeMessageThresholdBytes:         dc.GetIntProperty(dynamicconfig.DomainDLQLargeMessageThresholdBytes, 1024*1024),
+		DomainDLQStalledAckThreshold:                dc.GetDurationProperty(dynamicconfig.DomainDLQStalledAckThreshold, time.Hour),
+		DomainDLQDepthAlertThreshold:                dc.GetIntProperty(dynamicconfig.DomainDLQDepthAlertThreshold, 0),
+		DomainDLQNotificationWebhookURL:             dc.GetStringProperty(dynamicconfig.DomainDLQNotificationWebhookURL, ""),
+		DomainDLQMergeTimeout:                       dc.GetDurationProperty(dynamicconfig.DomainDLQMergeTimeout, 5*time.Minute),
+		DomainDLQReadMaxRetries:                     dc.GetIntProperty(dynamicconfig.DomainDLQReadMaxRetries, 3),
+		DomainDLQMergeCheckpointGranularity:         dc.GetStringProperty(dynamicconfig.DomainDLQMergeCheckpointGranularity, string(domain.CheckpointGranularityPerPage)),
+		DomainDLQMergeParallelism:                   dc.GetIntProperty(dynamicconfig.DomainDLQMergeParallelism, 1),
+		DomainDLQInvalidMessagePolicy:               dc.GetStringProperty(dynamicconfig.DomainDLQInvalidMessagePolicy, string(domain.InvalidTaskPolicyFail)),
+		DomainDLQMergeBackpressureMaxAttempts:       dc.GetIntProperty(dynamicconfig.DomainDLQMergeBackpressureMaxAttempts, 10),
+		DomainDLQGroupedMergeEnabled:                dc.GetBoolProperty(dynamicconfig.DomainDLQGroupedMergeEnabled, false),
+		DomainDLQExecutionJournalEnabled:            dc.GetBoolProperty(dynamicconfig.DomainDLQExecutionJournalEnabled, false),
+		DomainDLQVersionedAckLevelEnabled:           dc.GetBoolProperty(dynamicconfig.DomainDLQVersionedAckLevelEnabled, false),
+		DomainDLQAckLevelMaxConflictRetries:         dc.GetIntProperty(dynamicconfig.DomainDLQAckLevelMaxConflictRetries, 3),
+		DomainDLQArchivalEnabled:                    dc.GetBoolProperty(dynamicconfig.DomainDLQArchivalEnabled, false),
+		DomainDLQArchivalURI:                        dc.GetStringProperty(dynamicconfig.DomainDLQArchivalURI, ""),
+		DomainDLQGrowthRateSampleInterval:           dc.GetDurationProperty(dynamicconfig.DomainDLQGrowthRateSampleInterval, time.Minute),
+		DomainDLQMaxGrowthRate:                      dc.GetFloat64Property(dynamicconfig.DomainDLQMaxGrowthRate, 0),
 		EnableClientVersionCheck:                    dc.GetBoolProperty(dynamicconfig.EnableClientVersionCheck, false),
 		ValidSearchAttributes:                       dc.GetMapProperty(dynamicconfig.ValidSearchAttributes, definition.GetDefaultIndexedKeys()),
 		SearchAttributesNumberOfKeysLimit:           dc.GetIntPropertyFilteredByDomain(dynamicconfig.SearchAttributesNumberOfKeysLimit, 100),
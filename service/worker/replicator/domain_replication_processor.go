@@ -199,7 +199,10 @@ func (p *domainReplicationProcessor) putDomainReplicationTaskToDLQ(
 		metrics.DomainReplicationTaskScope,
 		metrics.DomainTag(domainAttribute.GetInfo().GetName()),
 	).IncCounter(metrics.DomainReplicationEnqueueDLQCount)
-	return p.domainReplicationQueue.PublishToDLQ(context.Background(), task)
+	// record where the task came from, so merging it from the DLQ can route it back to the source cluster
+	dlqTask := *task
+	dlqTask.SourceCluster = p.sourceCluster
+	return p.domainReplicationQueue.PublishToDLQ(context.Background(), &dlqTask)
 }
 
 func (p *domainReplicationProcessor) handleDomainReplicationTask(
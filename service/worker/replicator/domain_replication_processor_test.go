@@ -124,12 +124,16 @@ func (s *domainReplicationSuite) TestPutDomainReplicationTaskToDLQ() {
 	task.DomainTaskAttributes = &types.DomainTaskAttributes{
 		ID: domainID,
 	}
+	// the task is enqueued with the cluster it was replicated from
+	dlqTask := *task
+	dlqTask.SourceCluster = s.sourceCluster
 
-	s.domainReplicationQueue.EXPECT().PublishToDLQ(gomock.Any(), task).Return(nil).Times(1)
+	s.domainReplicationQueue.EXPECT().PublishToDLQ(gomock.Any(), &dlqTask).Return(nil).Times(1)
 	err = s.replicationProcessor.putDomainReplicationTaskToDLQ(task)
 	s.NoError(err)
+	s.Empty(task.SourceCluster)
 
-	s.domainReplicationQueue.EXPECT().PublishToDLQ(gomock.Any(), task).Return(errors.New("test")).Times(1)
+	s.domainReplicationQueue.EXPECT().PublishToDLQ(gomock.Any(), &dlqTask).Return(errors.New("test")).Times(1)
 	err = s.replicationProcessor.putDomainReplicationTaskToDLQ(task)
 	s.Error(err)
 }
@@ -362,6 +362,7 @@ func (s *Service) startReplicator() {
 	domainReplicationTaskExecutor := domain.NewReplicationTaskExecutor(
 		s.Resource.GetDomainManager(),
 		s.Resource.GetTimeSource(),
+		nil,
 		s.Resource.GetLogger(),
 	)
 	msgReplicator := replicator.NewReplicator(
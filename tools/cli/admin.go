@@ -771,6 +771,17 @@ func getDLQFlags() []cli.Flag {
 	}
 }
 
+// getDomainDLQDBFlags returns the flags of the commands which manage the domain DLQ directly through the database
+func getDomainDLQDBFlags() []cli.Flag {
+	return append(getDBFlags(),
+		cli.IntFlag{
+			Name:  FlagDLQNumShards,
+			Value: 1,
+			Usage: "Number of shards of the domain DLQ, it must match the domainReplicationDLQNumShards persistence config of the cluster",
+		},
+	)
+}
+
 func newAdminDLQCommands() []cli.Command {
 	return []cli.Command{
 		{
@@ -812,7 +823,12 @@ func newAdminDLQCommands() []cli.Command {
 			Name:    "purge",
 			Aliases: []string{"p"},
 			Usage:   "Delete DLQ messages with equal or smaller ids than the provided task id",
-			Flags:   getDLQFlags(),
+			Flags: append(append(getDLQFlags(),
+				cli.BoolFlag{
+					Name:  FlagDryRun,
+					Usage: "Only count the domain DLQ messages which would be purged, reading them directly from the database",
+				},
+			), getDomainDLQDBFlags()...),
 			Action: func(c *cli.Context) {
 				AdminPurgeDLQMessages(c)
 			},
@@ -821,11 +837,161 @@ func newAdminDLQCommands() []cli.Command {
 			Name:    "merge",
 			Aliases: []string{"m"},
 			Usage:   "Merge DLQ messages with equal or smaller ids than the provided task id",
-			Flags:   getDLQFlags(),
+			Flags: append(append(getDLQFlags(),
+				cli.BoolFlag{
+					Name:  FlagDryRun,
+					Usage: "Only report the domain DLQ messages which would be merged, reading them directly from the database",
+				},
+				cli.BoolFlag{
+					Name:  FlagSimulate,
+					Usage: "Only report the operations the merge of the domain DLQ messages would perform, reading them and the domains directly from the database",
+				},
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: defaultPageSize,
+					Usage: "Number of DLQ messages merged by each request",
+				},
+				cli.BoolFlag{
+					Name:  FlagVerbose,
+					Usage: "Print the id of every merged DLQ message",
+				},
+				getFormatFlag(),
+			), getDomainDLQDBFlags()...),
 			Action: func(c *cli.Context) {
 				AdminMergeDLQMessages(c)
 			},
 		},
+		{
+			Name:    "export",
+			Aliases: []string{"e"},
+			Usage:   "Export domain DLQ messages with equal or smaller ids than the provided task id directly from the database",
+			Flags: append(getDomainDLQDBFlags(),
+				cli.IntFlag{
+					Name:  FlagLastMessageIDWithAlias,
+					Usage: "The upper boundary of the exported message",
+				},
+				cli.StringFlag{
+					Name:  FlagOutputFilenameWithAlias,
+					Usage: "Output file to write to, if not provided output is written to stdout",
+				},
+				cli.StringFlag{
+					Name:  FlagExportFormatWithAlias,
+					Usage: "Export format. (Options: json, prototext)",
+					Value: "json",
+				},
+			),
+			Action: func(c *cli.Context) {
+				AdminExportDomainDLQMessages(c)
+			},
+		},
+		{
+			Name:    "import",
+			Aliases: []string{"i"},
+			Usage:   "Import exported messages back into the domain DLQ directly through the database",
+			Flags: append(getDomainDLQDBFlags(),
+				cli.StringFlag{
+					Name:  FlagInputFileWithAlias,
+					Usage: "Input file of exported messages",
+				},
+				cli.StringFlag{
+					Name:  FlagExportFormatWithAlias,
+					Usage: "Format of the input file. (Options: json, prototext)",
+					Value: "json",
+				},
+			),
+			Action: func(c *cli.Context) {
+				AdminImportDomainDLQMessages(c)
+			},
+		},
+		{
+			Name:    "history",
+			Aliases: []string{"hist"},
+			Usage:   "Show the most recent domain DLQ merges directly from the database",
+			Flags: append(getDomainDLQDBFlags(),
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Usage: "Number of most recent merges to show",
+					Value: 20,
+				},
+				getFormatFlag(),
+			),
+			Action: func(c *cli.Context) {
+				AdminShowDomainDLQMergeHistory(c)
+			},
+		},
+		{
+			Name:  "snapshot",
+			Usage: "Snapshot the domain DLQ ack levels directly from the database, to restore them if they are lost",
+			Flags: append(getDomainDLQDBFlags(),
+				cli.StringFlag{
+					Name:  FlagOutputFilenameWithAlias,
+					Usage: "Output file to write to, if not provided output is written to stdout",
+				},
+			),
+			Action: func(c *cli.Context) {
+				AdminSnapshotDomainDLQAckLevel(c)
+			},
+		},
+		{
+			Name:  "restore",
+			Usage: "Restore the domain DLQ ack levels from a snapshot directly through the database, ack levels never move backwards",
+			Flags: append(getDomainDLQDBFlags(),
+				cli.StringFlag{
+					Name:  FlagInputFileWithAlias,
+					Usage: "Input file of the ack level snapshot",
+				},
+			),
+			Action: func(c *cli.Context) {
+				AdminRestoreDomainDLQAckLevel(c)
+			},
+		},
+		{
+			Name:  "pause",
+			Usage: "Pause the background merges of DLQ messages, the merges in progress stop once their current page is merged",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagDLQTypeWithAlias,
+					Usage: "Type of DLQ to manage. (Options: domain)",
+					Value: "domain",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminPauseDLQProcessing(c)
+			},
+		},
+		{
+			Name:  "resume",
+			Usage: "Resume the background merges of DLQ messages and start merging right away",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagDLQTypeWithAlias,
+					Usage: "Type of DLQ to manage. (Options: domain)",
+					Value: "domain",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminResumeDLQProcessing(c)
+			},
+		},
+		{
+			Name:  "list-consumers",
+			Usage: "List the consumer groups of DLQ with their ack levels",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagDLQTypeWithAlias,
+					Usage: "Type of DLQ to manage. (Options: domain)",
+					Value: "domain",
+				},
+				cli.StringFlag{
+					Name:  FlagCluster,
+					Usage: "Cluster of the DLQ, it must be the cluster of the frontend the command is sent to",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminListDLQConsumers(c)
+			},
+		},
 	}
 }
 
@@ -22,6 +22,8 @@ package cli
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -32,7 +34,12 @@ import (
 
 	"github.com/urfave/cli"
 
+	"github.com/uber/cadence/client/admin"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/domain"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
 )
@@ -41,6 +48,45 @@ const (
 	defaultPageSize = 1000
 )
 
+type DomainDLQMergePreviewRow struct {
+	MessageID       int64     `header:"Message ID" json:"messageID"`
+	TaskType        string    `header:"Task Type" json:"taskType"`
+	DomainID        string    `header:"Domain ID" json:"domainID"`
+	DomainName      string    `header:"Domain Name" json:"domainName"`
+	DomainOperation string    `header:"Operation" json:"domainOperation"`
+	FailoverVersion int64     `header:"Failover Version" json:"failoverVersion"`
+	EnqueuedAt      time.Time `header:"Enqueued At" json:"enqueuedAt"`
+}
+
+type DomainDLQSimulatedOperationRow struct {
+	MessageID       int64  `header:"Message ID" json:"messageID"`
+	TaskType        string `header:"Task Type" json:"taskType"`
+	Operation       string `header:"Operation" json:"operation"`
+	DomainID        string `header:"Domain ID" json:"domainID"`
+	DomainName      string `header:"Domain Name" json:"domainName"`
+	FailoverVersion int64  `header:"Failover Version" json:"failoverVersion"`
+	Error           string `header:"Error" json:"error,omitempty"`
+}
+
+type DomainDLQMergeHistoryRow struct {
+	MergedAt       time.Time     `header:"Merged At" json:"mergedAt"`
+	StartMessageID int64         `header:"Start Message ID" json:"startMessageID"`
+	EndMessageID   int64         `header:"End Message ID" json:"endMessageID"`
+	MergedCount    int64         `header:"Merged" json:"mergedCount"`
+	FailedCount    int64         `header:"Failed" json:"failedCount"`
+	Duration       time.Duration `header:"Duration" json:"duration"`
+	TriggeredBy    string        `header:"Triggered By" json:"triggeredBy"`
+	TraceID        string        `header:"Trace ID" json:"traceID,omitempty"`
+}
+
+type DLQConsumerRow struct {
+	ConsumerGroup string `header:"Consumer Group" json:"consumerGroup"`
+	TaskType      string `header:"Task Type" json:"taskType"`
+	AckLevel      int64  `header:"Ack Level" json:"ackLevel"`
+	// UpdatedAt is empty if the update time of the ack level was not recorded
+	UpdatedAt string `header:"Updated At" json:"updatedAt,omitempty"`
+}
+
 type DLQRow struct {
 	ShardID         int                        `header:"Shard ID" json:"shardID"`
 	DomainName      string                     `header:"Domain Name" json:"domainName"`
@@ -235,6 +281,13 @@ func AdminGetDLQMessages(c *cli.Context) {
 // AdminPurgeDLQMessages deletes messages from DLQ
 func AdminPurgeDLQMessages(c *cli.Context) {
 	dlqType := getRequiredOption(c, FlagDLQType)
+	if c.Bool(FlagDryRun) {
+		if dlqType != "domain" {
+			ErrorAndExit("Dry run is only supported for the domain DLQ.", fmt.Errorf("the queue type is not supported. Type: %v", dlqType))
+		}
+		AdminDryRunPurgeDomainDLQMessages(c)
+		return
+	}
 	sourceCluster := getRequiredOption(c, FlagSourceCluster)
 	var lastMessageID *int64
 	if c.IsSet(FlagLastMessageID) {
@@ -260,15 +313,53 @@ func AdminPurgeDLQMessages(c *cli.Context) {
 	}
 }
 
+// AdminDryRunPurgeDomainDLQMessages reports how many domain DLQ messages would be purged without purging them
+func AdminDryRunPurgeDomainDLQMessages(c *cli.Context) {
+	lastMessageID := common.EndMessageID
+	if c.IsSet(FlagLastMessageID) {
+		lastMessageID = c.Int64(FlagLastMessageID)
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	dlqHandler := initializeDomainDLQHandler(c)
+	willDeleteCount, err := dlqHandler.DryRunPurge(ctx, lastMessageID)
+	if err != nil {
+		ErrorAndExit("Failed to dry run purge of domain DLQ messages", err)
+	}
+	fmt.Printf("%v domain DLQ messages would be purged.\n", willDeleteCount)
+}
+
 // AdminMergeDLQMessages merges message from DLQ
 func AdminMergeDLQMessages(c *cli.Context) {
 	dlqType := getRequiredOption(c, FlagDLQType)
+	if c.Bool(FlagDryRun) {
+		if dlqType != "domain" {
+			ErrorAndExit("Dry run is only supported for the domain DLQ.", fmt.Errorf("the queue type is not supported. Type: %v", dlqType))
+		}
+		AdminDryRunMergeDomainDLQMessages(c)
+		return
+	}
+	if c.Bool(FlagSimulate) {
+		if dlqType != "domain" {
+			ErrorAndExit("Simulation is only supported for the domain DLQ.", fmt.Errorf("the queue type is not supported. Type: %v", dlqType))
+		}
+		AdminSimulateMergeDomainDLQMessages(c)
+		return
+	}
 	sourceCluster := getRequiredOption(c, FlagSourceCluster)
 	var lastMessageID *int64
 	if c.IsSet(FlagLastMessageID) {
 		lastMessageID = common.Int64Ptr(c.Int64(FlagLastMessageID))
 	}
 
+	pageSize := c.Int(FlagPageSize)
+	if pageSize <= 0 {
+		ErrorAndExit("Invalid page size.", fmt.Errorf("the page size must be positive. Page size: %v", pageSize))
+	}
+	verbose := c.Bool(FlagVerbose)
+
 	adminClient := cFactory.ServerAdminClient(c)
 ShardIDLoop:
 	for shardID := range getShards(c) {
@@ -277,10 +368,23 @@ ShardIDLoop:
 			SourceCluster:         sourceCluster,
 			ShardID:               int32(shardID),
 			InclusiveEndMessageID: lastMessageID,
-			MaximumPageSize:       defaultPageSize,
+			MaximumPageSize:       int32(pageSize),
 		}
 
 		for {
+			// the merge response does not list the merged messages, the page is read first to report them
+			var messageIDs []int64
+			if verbose {
+				ctx, cancel := newContext(c)
+				ids, err := readDLQMessageIDs(ctx, adminClient, request)
+				cancel()
+				if err != nil {
+					fmt.Printf("Failed to read DLQ message in shard %v with error: %v.\n", shardID, err)
+					continue ShardIDLoop
+				}
+				messageIDs = ids
+			}
+
 			ctx, cancel := newContext(c)
 			response, err := adminClient.MergeDLQMessages(ctx, request)
 			cancel()
@@ -288,6 +392,9 @@ ShardIDLoop:
 				fmt.Printf("Failed to merge DLQ message in shard %v with error: %v.\n", shardID, err)
 				continue ShardIDLoop
 			}
+			for _, messageID := range messageIDs {
+				fmt.Printf("Merged DLQ message %v in shard %v.\n", messageID, shardID)
+			}
 
 			if len(response.NextPageToken) == 0 {
 				break
@@ -299,6 +406,313 @@ ShardIDLoop:
 	}
 }
 
+func readDLQMessageIDs(ctx context.Context, adminClient admin.Client, request *types.MergeDLQMessagesRequest) ([]int64, error) {
+	response, err := adminClient.ReadDLQMessages(ctx, &types.ReadDLQMessagesRequest{
+		Type:                  request.Type,
+		SourceCluster:         request.SourceCluster,
+		ShardID:               request.ShardID,
+		InclusiveEndMessageID: request.InclusiveEndMessageID,
+		MaximumPageSize:       request.MaximumPageSize,
+		NextPageToken:         request.NextPageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the domain DLQ only returns the tasks, the history DLQ describes them in the tasks info
+	var messageIDs []int64
+	for _, info := range response.ReplicationTasksInfo {
+		messageIDs = append(messageIDs, info.TaskID)
+	}
+	if len(messageIDs) == 0 {
+		for _, task := range response.ReplicationTasks {
+			messageIDs = append(messageIDs, task.SourceTaskID)
+		}
+	}
+	return messageIDs, nil
+}
+
+// AdminDryRunMergeDomainDLQMessages reports the domain DLQ messages which would be merged without merging them
+func AdminDryRunMergeDomainDLQMessages(c *cli.Context) {
+	lastMessageID := common.EndMessageID
+	if c.IsSet(FlagLastMessageID) {
+		lastMessageID = c.Int64(FlagLastMessageID)
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	dlqHandler := initializeDomainDLQHandler(c)
+	previews, err := dlqHandler.DryRunMerge(ctx, domain.AllTaskTypes, lastMessageID)
+	if err != nil {
+		ErrorAndExit("Failed to dry run merge of domain DLQ messages", err)
+	}
+
+	table := make([]DomainDLQMergePreviewRow, 0, len(previews))
+	for _, preview := range previews {
+		table = append(table, DomainDLQMergePreviewRow{
+			MessageID:       preview.MessageID,
+			TaskType:        preview.TaskType.String(),
+			DomainID:        preview.DomainID,
+			DomainName:      preview.DomainName,
+			DomainOperation: preview.DomainOperation.String(),
+			FailoverVersion: preview.FailoverVersion,
+			EnqueuedAt:      preview.EnqueuedAt,
+		})
+	}
+	Render(c, table, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+// AdminSimulateMergeDomainDLQMessages reports the operations the merge of the domain DLQ messages would perform
+// on the domains of the cluster without performing them
+func AdminSimulateMergeDomainDLQMessages(c *cli.Context) {
+	lastMessageID := common.EndMessageID
+	if c.IsSet(FlagLastMessageID) {
+		lastMessageID = c.Int64(FlagLastMessageID)
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	dlqHandler := initializeDomainDLQHandler(c)
+	operations, err := dlqHandler.SimulateMerge(ctx, domain.AllTaskTypes, lastMessageID)
+	if err != nil {
+		ErrorAndExit("Failed to simulate merge of domain DLQ messages", err)
+	}
+
+	table := make([]DomainDLQSimulatedOperationRow, 0, len(operations))
+	for _, operation := range operations {
+		table = append(table, DomainDLQSimulatedOperationRow{
+			MessageID:       operation.MessageID,
+			TaskType:        operation.TaskType.String(),
+			Operation:       string(operation.Operation),
+			DomainID:        operation.DomainID,
+			DomainName:      operation.DomainName,
+			FailoverVersion: operation.FailoverVersion,
+			Error:           operation.Error,
+		})
+	}
+	Render(c, table, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+// AdminExportDomainDLQMessages exports domain DLQ messages without merging them
+func AdminExportDomainDLQMessages(c *cli.Context) {
+	format, err := domain.ParseExportFormat(c.String(FlagExportFormat))
+	if err != nil {
+		ErrorAndExit("Invalid export format", err)
+	}
+	lastMessageID := common.EndMessageID
+	if c.IsSet(FlagLastMessageID) {
+		lastMessageID = c.Int64(FlagLastMessageID)
+	}
+
+	var output io.Writer = os.Stdout
+	if c.IsSet(FlagOutputFilename) {
+		file, err := os.Create(c.String(FlagOutputFilename))
+		if err != nil {
+			ErrorAndExit("Failed to create output file", err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	dlqHandler := initializeDomainDLQHandler(c)
+	if err := dlqHandler.Export(ctx, domain.AllTaskTypes, lastMessageID, output, format); err != nil {
+		ErrorAndExit("Failed to export domain DLQ messages", err)
+	}
+}
+
+// AdminImportDomainDLQMessages re-enqueues exported messages into the domain DLQ
+func AdminImportDomainDLQMessages(c *cli.Context) {
+	format, err := domain.ParseExportFormat(c.String(FlagExportFormat))
+	if err != nil {
+		ErrorAndExit("Invalid export format", err)
+	}
+
+	file, err := os.Open(getRequiredOption(c, FlagInputFile))
+	if err != nil {
+		ErrorAndExit("Failed to open input file", err)
+	}
+	defer file.Close()
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	dlqHandler := initializeDomainDLQHandler(c)
+	if err := dlqHandler.Import(ctx, file, format); err != nil {
+		ErrorAndExit("Failed to import domain DLQ messages", err)
+	}
+	fmt.Println("Successfully imported domain DLQ messages.")
+}
+
+// AdminShowDomainDLQMergeHistory shows the most recent domain DLQ merges
+func AdminShowDomainDLQMergeHistory(c *cli.Context) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	dlqHandler := initializeDomainDLQHandler(c)
+	history, err := dlqHandler.MergeHistory(ctx, c.Int(FlagPageSize))
+	if err != nil {
+		ErrorAndExit("Failed to read domain DLQ merge history", err)
+	}
+
+	table := make([]DomainDLQMergeHistoryRow, 0, len(history))
+	for _, record := range history {
+		table = append(table, DomainDLQMergeHistoryRow(record))
+	}
+	Render(c, table, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+// AdminSnapshotDomainDLQAckLevel writes a snapshot of the domain DLQ ack levels, to restore them if they are lost
+func AdminSnapshotDomainDLQAckLevel(c *cli.Context) {
+	var output io.Writer = os.Stdout
+	if c.IsSet(FlagOutputFilename) {
+		file, err := os.Create(c.String(FlagOutputFilename))
+		if err != nil {
+			ErrorAndExit("Failed to create output file", err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	replicationQueue, _, _ := initializeDomainReplicationQueue(c)
+	snapshot, err := replicationQueue.SnapshotDLQAckLevel(ctx)
+	if err != nil {
+		ErrorAndExit("Failed to snapshot domain DLQ ack levels", err)
+	}
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot); err != nil {
+		ErrorAndExit("Failed to write domain DLQ ack level snapshot", err)
+	}
+}
+
+// AdminRestoreDomainDLQAckLevel restores the domain DLQ ack levels from a snapshot
+func AdminRestoreDomainDLQAckLevel(c *cli.Context) {
+	file, err := os.Open(getRequiredOption(c, FlagInputFile))
+	if err != nil {
+		ErrorAndExit("Failed to open input file", err)
+	}
+	defer file.Close()
+
+	var snapshot domain.DLQAckLevelSnapshot
+	if err := json.NewDecoder(file).Decode(&snapshot); err != nil {
+		ErrorAndExit("Failed to read domain DLQ ack level snapshot", err)
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	replicationQueue, _, _ := initializeDomainReplicationQueue(c)
+	if err := replicationQueue.RestoreDLQAckLevel(ctx, &snapshot); err != nil {
+		ErrorAndExit("Failed to restore domain DLQ ack levels", err)
+	}
+	fmt.Println("Successfully restored domain DLQ ack levels.")
+}
+
+// AdminPauseDLQProcessing pauses the background merges of DLQ messages until they are resumed
+func AdminPauseDLQProcessing(c *cli.Context) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	adminClient := cFactory.ServerAdminClient(c)
+	err := adminClient.PauseDLQProcessing(ctx, &types.PauseDLQProcessingRequest{
+		Type: toQueueType(c.String(FlagDLQType)),
+	})
+	if err != nil {
+		ErrorAndExit("Failed to pause DLQ processing", err)
+	}
+	fmt.Println("Successfully paused DLQ processing.")
+}
+
+// AdminResumeDLQProcessing resumes the background merges of DLQ messages
+func AdminResumeDLQProcessing(c *cli.Context) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	adminClient := cFactory.ServerAdminClient(c)
+	err := adminClient.ResumeDLQProcessing(ctx, &types.ResumeDLQProcessingRequest{
+		Type: toQueueType(c.String(FlagDLQType)),
+	})
+	if err != nil {
+		ErrorAndExit("Failed to resume DLQ processing", err)
+	}
+	fmt.Println("Successfully resumed DLQ processing.")
+}
+
+// AdminListDLQConsumers lists the consumer groups of DLQ with their ack levels
+func AdminListDLQConsumers(c *cli.Context) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	adminClient := cFactory.ServerAdminClient(c)
+	response, err := adminClient.ListDLQConsumers(ctx, &types.ListDLQConsumersRequest{
+		Type:        toQueueType(c.String(FlagDLQType)),
+		ClusterName: c.String(FlagCluster),
+	})
+	if err != nil {
+		ErrorAndExit("Failed to list DLQ consumers", err)
+	}
+
+	table := make([]DLQConsumerRow, 0, len(response.GetConsumers()))
+	for _, consumer := range response.GetConsumers() {
+		row := DLQConsumerRow{
+			ConsumerGroup: consumer.ConsumerGroup,
+			TaskType:      consumer.TaskType,
+			AckLevel:      consumer.AckLevel,
+		}
+		if row.TaskType == "" {
+			row.TaskType = "All"
+		}
+		if consumer.UpdatedTime != nil {
+			row.UpdatedAt = time.Unix(0, consumer.GetUpdatedTime()).Format(defaultDateTimeFormat)
+		}
+		table = append(table, row)
+	}
+	Render(c, table, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+func initializeDomainReplicationQueue(c *cli.Context) (domain.ReplicationQueue, log.Logger, metrics.Client) {
+	configuration, err := cFactory.ServerConfig(c)
+	if err != nil {
+		ErrorAndExit("Unable to load config.", err)
+	}
+
+	logger := initializeLogger(configuration)
+	metricsClient := initializeMetricsClient()
+	persistenceFactory := getPersistenceFactory(c)
+	queueManagers := make([]persistence.QueueManager, common.MaxInt(c.Int(FlagDLQNumShards), 1))
+	for shardID := range queueManagers {
+		queueManagers[shardID], err = persistenceFactory.NewDomainReplicationQueueShardManager(shardID)
+		if err != nil {
+			ErrorAndExit("Failed to initialize domain replication queue manager", err)
+		}
+	}
+
+	replicationQueue := domain.NewShardedReplicationQueue(queueManagers, configuration.ClusterGroupMetadata.CurrentClusterName, metricsClient, logger)
+	return replicationQueue, logger, metricsClient
+}
+
+func initializeDomainDLQHandler(c *cli.Context) domain.DLQMessageHandler {
+	replicationQueue, logger, metricsClient := initializeDomainReplicationQueue(c)
+	domainManager := initializeDomainManager(c)
+	return domain.NewDLQMessageHandler(
+		domain.NewReplicationTaskExecutorRegistry(
+			domain.NewReplicationTaskExecutor(domainManager, clock.NewRealTimeSource(), nil, logger),
+		),
+		replicationQueue,
+		logger,
+		domain.WithSimulationMode(domainManager),
+		domain.WithMetricsClient(metricsClient),
+	)
+}
+
 func getShards(c *cli.Context) chan int {
 	// Check if we have stdin available
 	stat, err := os.Stdin.Stat()
@@ -21,6 +21,8 @@
 package cli
 
 import (
+	"context"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -609,6 +611,138 @@ func (s *cliAppSuite) TestAdminFailover() {
 	s.Nil(err)
 }
 
+// setShardsOnStdin feeds the shard ids to the DLQ commands and returns the function restoring stdin
+func (s *cliAppSuite) setShardsOnStdin(shards string) func() {
+	reader, writer, err := os.Pipe()
+	s.NoError(err)
+	_, err = writer.WriteString(shards)
+	s.NoError(err)
+	s.NoError(writer.Close())
+
+	stdin := os.Stdin
+	os.Stdin = reader
+	return func() {
+		os.Stdin = stdin
+		reader.Close()
+	}
+}
+
+func (s *cliAppSuite) TestAdminMergeDLQMessages() {
+	defer s.setShardsOnStdin("1\n")()
+
+	firstPage := &types.MergeDLQMessagesRequest{
+		Type:                  types.DLQTypeReplication.Ptr(),
+		SourceCluster:         "standby",
+		ShardID:               1,
+		InclusiveEndMessageID: common.Int64Ptr(100),
+		MaximumPageSize:       2,
+	}
+	secondPage := *firstPage
+	secondPage.NextPageToken = []byte("token")
+	gomock.InOrder(
+		s.serverAdminClient.EXPECT().MergeDLQMessages(gomock.Any(), firstPage).
+			Return(&types.MergeDLQMessagesResponse{NextPageToken: []byte("token")}, nil),
+		s.serverAdminClient.EXPECT().MergeDLQMessages(gomock.Any(), &secondPage).
+			Return(&types.MergeDLQMessagesResponse{}, nil),
+	)
+
+	err := s.app.Run([]string{"", "admin", "dlq", "merge", "--source_cluster", "standby", "--lm", "100", "--ps", "2"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminMergeDLQMessages_Verbose() {
+	defer s.setShardsOnStdin("1\n2\n")()
+
+	s.serverAdminClient.EXPECT().ReadDLQMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *types.ReadDLQMessagesRequest, _ ...interface{}) (*types.ReadDLQMessagesResponse, error) {
+			s.Equal(int32(defaultPageSize), request.MaximumPageSize)
+			if request.ShardID == 2 {
+				return nil, &types.InternalServiceError{Message: "read failed"}
+			}
+			return &types.ReadDLQMessagesResponse{
+				ReplicationTasks: []*types.ReplicationTask{{SourceTaskID: 10}, {SourceTaskID: 11}},
+			}, nil
+		}).Times(2)
+	s.serverAdminClient.EXPECT().MergeDLQMessages(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *types.MergeDLQMessagesRequest, _ ...interface{}) (*types.MergeDLQMessagesResponse, error) {
+			s.Equal(int32(1), request.ShardID)
+			return &types.MergeDLQMessagesResponse{}, nil
+		}).Times(1)
+
+	err := s.app.Run([]string{"", "admin", "dlq", "merge", "--dt", "domain", "--source_cluster", "standby", "--verbose"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminMergeDLQMessages_InvalidPageSize() {
+	defer s.setShardsOnStdin("")()
+
+	errorCode := s.RunErrorExitCode([]string{"", "admin", "dlq", "merge", "--source_cluster", "standby", "--ps", "0"})
+	s.Equal(1, errorCode)
+}
+
+func (s *cliAppSuite) TestAdminPurgeDLQMessages() {
+	defer s.setShardsOnStdin("1\n")()
+
+	s.serverAdminClient.EXPECT().PurgeDLQMessages(gomock.Any(), &types.PurgeDLQMessagesRequest{
+		Type:                  types.DLQTypeReplication.Ptr(),
+		SourceCluster:         "standby",
+		ShardID:               1,
+		InclusiveEndMessageID: common.Int64Ptr(100),
+	}).Return(nil)
+
+	err := s.app.Run([]string{"", "admin", "dlq", "purge", "--source_cluster", "standby", "--lm", "100"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminPauseDLQProcessing() {
+	s.serverAdminClient.EXPECT().PauseDLQProcessing(gomock.Any(), &types.PauseDLQProcessingRequest{
+		Type: types.DLQTypeDomain.Ptr(),
+	}).Return(nil)
+
+	err := s.app.Run([]string{"", "admin", "dlq", "pause"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminPauseDLQProcessing_Failed() {
+	s.serverAdminClient.EXPECT().PauseDLQProcessing(gomock.Any(), gomock.Any()).
+		Return(&types.BadRequestError{Message: "The DLQ type is not supported."})
+
+	errorCode := s.RunErrorExitCode([]string{"", "admin", "dlq", "pause", "--dt", "history"})
+	s.Equal(1, errorCode)
+}
+
+func (s *cliAppSuite) TestAdminResumeDLQProcessing() {
+	s.serverAdminClient.EXPECT().ResumeDLQProcessing(gomock.Any(), &types.ResumeDLQProcessingRequest{
+		Type: types.DLQTypeDomain.Ptr(),
+	}).Return(nil)
+
+	err := s.app.Run([]string{"", "admin", "dlq", "resume", "--dt", "domain"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminListDLQConsumers() {
+	s.serverAdminClient.EXPECT().ListDLQConsumers(gomock.Any(), &types.ListDLQConsumersRequest{
+		Type:        types.DLQTypeDomain.Ptr(),
+		ClusterName: "active",
+	}).Return(&types.ListDLQConsumersResponse{
+		Consumers: []*types.DLQConsumer{
+			{ConsumerGroup: "default", AckLevel: 10, UpdatedTime: common.Int64Ptr(time.Now().UnixNano())},
+			{ConsumerGroup: "replay", TaskType: "HistoryV2", AckLevel: 20},
+		},
+	}, nil)
+
+	err := s.app.Run([]string{"", "admin", "dlq", "list-consumers", "--cluster", "active"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminListDLQConsumers_Failed() {
+	s.serverAdminClient.EXPECT().ListDLQConsumers(gomock.Any(), gomock.Any()).
+		Return(nil, &types.BadRequestError{Message: "The DLQ type is not supported."})
+
+	errorCode := s.RunErrorExitCode([]string{"", "admin", "dlq", "list-consumers", "--dt", "history"})
+	s.Equal(1, errorCode)
+}
+
 func (s *cliAppSuite) TestDescribeTaskList() {
 	resp := describeTaskListResponse
 	s.serverFrontendClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(resp, nil)
@@ -97,6 +97,7 @@ const (
 	FlagSkipCurrentCompleted              = "skip_current_completed"
 	FlagSkipBaseIsNotCurrent              = "skip_base_is_not_current"
 	FlagDryRun                            = "dry_run"
+	FlagSimulate                          = "simulate"
 	FlagNonDeterministicOnly              = "only_non_deterministic"
 	FlagInputTopic                        = "input_topic"
 	FlagInputTopicWithAlias               = FlagInputTopic + ", it"
@@ -242,10 +243,14 @@ const (
 	FlagTLSEnableHostVerification         = "tls_enable_host_verification"
 	FlagDLQType                           = "dlq_type"
 	FlagDLQTypeWithAlias                  = FlagDLQType + ", dt"
+	FlagDLQNumShards                      = "dlq_num_shards"
 	FlagMaxMessageCount                   = "max_message_count"
 	FlagMaxMessageCountWithAlias          = FlagMaxMessageCount + ", mmc"
 	FlagLastMessageID                     = "last_message_id"
 	FlagLastMessageIDWithAlias            = FlagLastMessageID + ", lm"
+	FlagVerbose                           = "verbose"
+	FlagExportFormat                      = "export_format"
+	FlagExportFormatWithAlias             = FlagExportFormat + ", ef"
 	FlagConcurrency                       = "concurrency"
 	FlagReportRate                        = "report_rate"
 	FlagLowerShardBound                   = "lower_shard_bound"